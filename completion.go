@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/tools"
+)
+
+// completionMethod is the MCP request for argument completion. mcp-go does
+// not route it, so the stdio transport answers it before the server sees it.
+const completionMethod = "completion/complete"
+
+// lockedWriter serializes whole-message writes from the server and from the
+// completion interceptor, and advertises the completions capability in the
+// initialize response.
+type lockedWriter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	announced bool
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := p
+	if !l.announced && bytes.Contains(p, []byte(`"protocolVersion"`)) {
+		if patched, ok := addCompletionsCapability(p); ok {
+			out = patched
+			l.announced = true
+		}
+	}
+	if _, err := l.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// addCompletionsCapability adds "completions": {} to the capabilities of an
+// initialize response line.
+func addCompletionsCapability(line []byte) ([]byte, bool) {
+	var msg map[string]json.RawMessage
+	if json.Unmarshal(line, &msg) != nil {
+		return nil, false
+	}
+	var result map[string]json.RawMessage
+	if json.Unmarshal(msg["result"], &result) != nil || result["capabilities"] == nil {
+		return nil, false
+	}
+	var capabilities map[string]json.RawMessage
+	if json.Unmarshal(result["capabilities"], &capabilities) != nil {
+		return nil, false
+	}
+	capabilities["completions"] = json.RawMessage(`{}`)
+
+	var err error
+	if result["capabilities"], err = json.Marshal(capabilities); err != nil {
+		return nil, false
+	}
+	if msg["result"], err = json.Marshal(result); err != nil {
+		return nil, false
+	}
+	patched, err := json.Marshal(msg)
+	if err != nil {
+		return nil, false
+	}
+	return append(patched, '\n'), true
+}
+
+// completionInterceptor passes stdin through line by line, except
+// completion/complete requests, which it answers directly on out.
+type completionInterceptor struct {
+	ctx       context.Context
+	lines     *bufio.Reader
+	out       io.Writer
+	completer *tools.Completer
+	pending   []byte
+}
+
+func newCompletionInterceptor(ctx context.Context, r io.Reader, out io.Writer, completer *tools.Completer) *completionInterceptor {
+	return &completionInterceptor{ctx: ctx, lines: bufio.NewReader(r), out: out, completer: completer}
+}
+
+func (c *completionInterceptor) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 {
+		line, err := c.lines.ReadBytes('\n')
+		if len(line) > 0 && !c.intercept(line) {
+			c.pending = line
+		}
+		if err != nil && len(c.pending) == 0 {
+			return 0, err
+		}
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+// intercept reports whether line is a completion request, answering it in
+// the background so that slow lookups do not hold up other requests.
+func (c *completionInterceptor) intercept(line []byte) bool {
+	if !bytes.Contains(line, []byte(completionMethod)) {
+		return false
+	}
+	var req struct {
+		ID     json.RawMessage    `json:"id"`
+		Method string             `json:"method"`
+		Params mcp.CompleteParams `json:"params"`
+	}
+	if json.Unmarshal(line, &req) != nil || req.Method != completionMethod || req.ID == nil {
+		return false
+	}
+
+	go func() {
+		response := map[string]interface{}{"jsonrpc": mcp.JSONRPC_VERSION, "id": req.ID}
+		result, err := c.completer.Complete(c.ctx, req.Params.Argument.Name, req.Params.Argument.Value)
+		if err != nil {
+			slog.Warn("completion failed", "argument", req.Params.Argument.Name, "error", err)
+			response["error"] = map[string]interface{}{"code": mcp.INTERNAL_ERROR, "message": err.Error()}
+		} else {
+			response["result"] = result
+		}
+		data, err := json.Marshal(response)
+		if err != nil {
+			return
+		}
+		_, _ = c.out.Write(append(data, '\n'))
+	}()
+	return true
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-todoist/tools"
+)
+
+// namesAPI serves project and label lists for completion tests.
+type namesAPI struct{}
+
+func (namesAPI) Get(_ context.Context, path string) ([]byte, error) {
+	if path == "/projects" {
+		return []byte(`[{"name": "Work"}, {"name": "Home"}]`), nil
+	}
+	return []byte(`[]`), nil
+}
+func (namesAPI) Post(context.Context, string, interface{}) ([]byte, error) {
+	return nil, fmt.Errorf("not supported")
+}
+func (namesAPI) Delete(context.Context, string) error { return fmt.Errorf("not supported") }
+func (namesAPI) TestConnection(context.Context) error { return nil }
+func (namesAPI) GetRemainingRequests() int            { return 450 }
+
+func TestCompletionInterceptor(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	completer := tools.NewCompleter(tools.NewReferenceCache(namesAPI{}, time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	stdout := &lockedWriter{w: outW}
+	go func() {
+		_ = server.NewStdioServer(s).Listen(ctx, newCompletionInterceptor(ctx, inR, stdout, completer), stdout)
+	}()
+
+	responses := bufio.NewScanner(outR)
+	send := func(msg string) map[string]interface{} {
+		t.Helper()
+		if _, err := io.WriteString(inW, msg+"\n"); err != nil {
+			t.Fatal(err)
+		}
+		if !responses.Scan() {
+			t.Fatalf("no response to %s", msg)
+		}
+		var resp map[string]interface{}
+		if err := json.Unmarshal(responses.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", responses.Text(), err)
+		}
+		return resp
+	}
+
+	init := send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"t","version":"1"}}}`)
+	capabilities := init["result"].(map[string]interface{})["capabilities"].(map[string]interface{})
+	if _, ok := capabilities["completions"]; !ok || capabilities["tools"] == nil {
+		t.Errorf("capabilities = %v, want completions alongside tools", capabilities)
+	}
+
+	resp := send(`{"jsonrpc":"2.0","id":"c1","method":"completion/complete","params":{"ref":{"type":"ref/prompt","name":"plan_my_day"},"argument":{"name":"project","value":"wo"}}}`)
+	values, _ := resp["result"].(map[string]interface{})["completion"].(map[string]interface{})["values"].([]interface{})
+	if resp["id"] != "c1" || len(values) != 1 || values[0] != "Work" {
+		t.Errorf("completion response = %v", resp)
+	}
+
+	// Other requests still reach the server.
+	if resp := send(`{"jsonrpc":"2.0","id":3,"method":"ping"}`); resp["id"] != float64(3) || resp["error"] != nil {
+		t.Errorf("ping response = %v", resp)
+	}
+}
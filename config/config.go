@@ -1,20 +1,136 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/joho/godotenv"
+	"github.com/rgabriel/mcp-todoist/tools"
 )
 
 // Config holds the application configuration.
 type Config struct {
 	TodoistAPIToken string
+	// TokenSource refreshes a token read from aws-sm:// or gcp-sm://; nil for static tokens.
+	TokenSource *TokenSource
+	// TemplatesDir is where project templates are stored as JSON files.
+	TemplatesDir string
+	// BackupDir is where delete_project archives projects before deleting them.
+	BackupDir string
+	// BackupBeforeDelete makes delete_project archive projects unless told not to.
+	BackupBeforeDelete bool
+	// TriageProjectID is the default project for captured emails; empty means Inbox.
+	TriageProjectID string
+	// DefaultProjectID is the project create_task and quick_add_task use when none is given.
+	DefaultProjectID string
+	// DefaultLabels are added to created tasks that have no labels.
+	DefaultLabels []string
+	// DefaultPriority is the API priority (1 normal to 4 urgent) of created tasks without one; zero means none.
+	DefaultPriority int
+	// DefaultDue is the due string of created tasks without a due date.
+	DefaultDue string
+	// CreationPolicies is the JSON of CREATION_POLICIES_FILE: per-project rules for new tasks.
+	CreationPolicies string
+	// WeeklySnapshotProjectID is the project that gets a weekly stats comment; empty disables it.
+	WeeklySnapshotProjectID string
+	// SprintWeeks is the sprint length for automatic rollover; zero disables it.
+	SprintWeeks int
+	// SprintTemplate names the project template new sprints are created from.
+	SprintTemplate string
+	// SprintLabel marks the tasks moved into a new sprint; empty means next-sprint.
+	SprintLabel string
+	// AtRiskDays is the deadline horizon of the at-risk monitor; zero disables it.
+	AtRiskDays int
+	// AtRiskLabel marks at-risk tasks; empty means at-risk.
+	AtRiskLabel string
+	// AtRiskComment also posts a warning comment on each newly flagged task.
+	AtRiskComment bool
+	// SomedayProjectID is the project holding Someday/Maybe tasks.
+	SomedayProjectID string
+	// SomedayLabel marks Someday/Maybe tasks; with no project either, "someday" is used.
+	SomedayLabel string
+	// WIPLimits maps section names (lowercase) to their default work-in-progress limit.
+	WIPLimits map[string]int
+	// RateLimitStateFile persists recent request times across restarts; empty disables it.
+	RateLimitStateFile string
+	// DebugAddr is the loopback address serving pprof and expvar; empty disables it.
+	DebugAddr string
+	// StatusPageURL is the status endpoint consulted after repeated server errors; empty disables it.
+	StatusPageURL string
+	// DebugDumpDir is where failing API calls are written as sanitized fixtures; empty disables it.
+	DebugDumpDir string
+	// InstructionsTemplate overrides the MCP server instructions template; empty uses the built-in one.
+	InstructionsTemplate string
+	// DisabledToolGroups lists tool groups hidden from clients at startup.
+	DisabledToolGroups []string
+	// ToolProfile names the curated tool subset to register; empty registers
+	// every tool except the raw Sync tools.
+	ToolProfile string
+	// OutputLang is the language of human-readable messages in tool results.
+	OutputLang string
+	// ResponseVerbosity is how much of each entity tool results include: minimal, normal, or full.
+	ResponseVerbosity string
+	// TokenScopes lists the OAuth scopes of a limited token; empty means a full personal token.
+	TokenScopes []string
+	// PIDFile is where the process ID is written at startup; empty disables it.
+	PIDFile string
+	// Transport is how MCP clients connect: stdio (the default), sse, http (Streamable HTTP), or pipe.
+	Transport string
+	// ListenAddr is the host:port the sse and http transports listen on.
+	ListenAddr string
+	// PipeName is the Windows named pipe the pipe transport listens on.
+	PipeName string
+	// PipeUser is the Windows account, a name or SID, allowed to open the
+	// pipe; empty allows the user running the server.
+	PipeUser string
+	// BulkSyncThreshold is the task count above which bulk changes use the
+	// Sync API; zero keeps the built-in threshold.
+	BulkSyncThreshold int
+	// PerRequestTokens is whether HTTP requests may bring their own API
+	// token: off (the default), optional, or required.
+	PerRequestTokens string
+	// AuthToken is the shared secret sse and http clients send as a bearer
+	// token; empty requires none.
+	AuthToken string
+	// AllowedOrigins lists the browser origins, besides loopback ones, that
+	// may call the sse and http transports.
+	AllowedOrigins []string
 }
 
+// Transports accepted in MCP_TRANSPORT and --transport.
+const (
+	TransportStdio = "stdio"
+	TransportSSE   = "sse"
+	TransportHTTP  = "http"
+	TransportPipe  = "pipe"
+)
+
+// Modes accepted in PER_REQUEST_TOKENS.
+const (
+	TokensOff      = "off"
+	TokensOptional = "optional"
+	TokensRequired = "required"
+)
+
+// defaultListenAddr only accepts local connections; deployments behind a
+// reverse proxy set MCP_LISTEN_ADDR.
+const defaultListenAddr = "127.0.0.1:8080"
+
+// defaultPipeName is the named pipe the pipe transport listens on when
+// MCP_PIPE_NAME is unset.
+const defaultPipeName = `\\.\pipe\mcp-todoist`
+
+// pipePrefix starts the name of every local Windows named pipe.
+const pipePrefix = `\\.\pipe\`
+
 // Load reads configuration from environment variables and .env file.
 func Load() (*Config, error) {
 	// Try to load .env file (ignore error if file doesn't exist)
@@ -39,7 +155,162 @@ func Load() (*Config, error) {
 		}
 	}
 
-	cfg := &Config{TodoistAPIToken: apiToken}
+	// Support reading the token from AWS Secrets Manager or GCP Secret Manager
+	refresh := defaultSecretRefresh
+	if v := strings.TrimSpace(os.Getenv("SECRET_REFRESH_INTERVAL")); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < time.Minute {
+			return nil, fmt.Errorf("invalid SECRET_REFRESH_INTERVAL %q (want a duration of at least 1m, e.g. 30m)", v)
+		}
+		refresh = d
+	}
+	tokenSource, err := newTokenSource(apiToken, refresh)
+	if err != nil {
+		return nil, err
+	}
+	if tokenSource != nil {
+		if apiToken, err = tokenSource.Token(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+
+	wipLimits, err := parseWIPLimits(os.Getenv("WIP_LIMITS"))
+	if err != nil {
+		return nil, err
+	}
+
+	sprintWeeks := 0
+	if v := strings.TrimSpace(os.Getenv("SPRINT_WEEKS")); v != "" {
+		sprintWeeks, err = strconv.Atoi(v)
+		if err != nil || sprintWeeks < 1 || sprintWeeks > 8 {
+			return nil, fmt.Errorf("invalid SPRINT_WEEKS %q (want a number of weeks from 1 to 8)", v)
+		}
+	}
+
+	defaultPriority := 0
+	if v := strings.TrimSpace(os.Getenv("DEFAULT_PRIORITY")); v != "" {
+		defaultPriority, err = strconv.Atoi(v)
+		if err != nil || defaultPriority < 1 || defaultPriority > 4 {
+			return nil, fmt.Errorf("invalid DEFAULT_PRIORITY %q (want 1 for normal to 4 for urgent)", v)
+		}
+	}
+
+	atRiskDays := 0
+	if v := strings.TrimSpace(os.Getenv("AT_RISK_DAYS")); v != "" {
+		atRiskDays, err = strconv.Atoi(v)
+		if err != nil || atRiskDays < 1 || atRiskDays > 30 {
+			return nil, fmt.Errorf("invalid AT_RISK_DAYS %q (want a number of days from 1 to 30)", v)
+		}
+	}
+	atRiskComment := false
+	if v := strings.TrimSpace(os.Getenv("AT_RISK_COMMENT")); v != "" {
+		atRiskComment, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AT_RISK_COMMENT %q (want true or false)", v)
+		}
+	}
+
+	bulkSyncThreshold := 0
+	if v := strings.TrimSpace(os.Getenv("BULK_SYNC_THRESHOLD")); v != "" {
+		bulkSyncThreshold, err = strconv.Atoi(v)
+		if err != nil || bulkSyncThreshold < 1 || bulkSyncThreshold > 100 {
+			return nil, fmt.Errorf("invalid BULK_SYNC_THRESHOLD %q (want a number of tasks from 1 to 100)", v)
+		}
+	}
+
+	debugAddr, err := parseDebugAddr(os.Getenv("DEBUG_ADDR"))
+	if err != nil {
+		return nil, err
+	}
+
+	statusPageURL := strings.TrimSpace(os.Getenv("STATUS_PAGE_URL"))
+	if statusPageURL != "" {
+		if u, err := url.Parse(statusPageURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid STATUS_PAGE_URL %q (want an http or https URL)", statusPageURL)
+		}
+	}
+
+	instructionsTemplate := ""
+	if path := strings.TrimSpace(os.Getenv("INSTRUCTIONS_FILE")); path != "" {
+		path = filepath.Clean(path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read INSTRUCTIONS_FILE %s: %w", path, err)
+		}
+		instructionsTemplate = string(data)
+	}
+
+	creationPolicies := ""
+	if path := strings.TrimSpace(os.Getenv("CREATION_POLICIES_FILE")); path != "" {
+		path = filepath.Clean(path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CREATION_POLICIES_FILE %s: %w", path, err)
+		}
+		creationPolicies = string(data)
+	}
+
+	triageProjectID, err := projectID("TRIAGE_PROJECT_ID")
+	if err != nil {
+		return nil, err
+	}
+	weeklySnapshotProjectID, err := projectID("WEEKLY_SNAPSHOT_PROJECT_ID")
+	if err != nil {
+		return nil, err
+	}
+	somedayProjectID, err := projectID("SOMEDAY_PROJECT_ID")
+	if err != nil {
+		return nil, err
+	}
+	defaultProjectID, err := projectID("DEFAULT_PROJECT")
+	if err != nil {
+		return nil, err
+	}
+
+	backupDir, backupBeforeDelete := deleteBackupDir()
+
+	cfg := &Config{
+		TodoistAPIToken:         apiToken,
+		TokenSource:             tokenSource,
+		TemplatesDir:            templatesDir(),
+		BackupDir:               backupDir,
+		BackupBeforeDelete:      backupBeforeDelete,
+		TriageProjectID:         triageProjectID,
+		DefaultProjectID:        defaultProjectID,
+		DefaultLabels:           parseLabels(os.Getenv("DEFAULT_LABELS")),
+		DefaultPriority:         defaultPriority,
+		DefaultDue:              strings.TrimSpace(os.Getenv("DEFAULT_DUE")),
+		CreationPolicies:        creationPolicies,
+		WeeklySnapshotProjectID: weeklySnapshotProjectID,
+		SprintWeeks:             sprintWeeks,
+		SprintTemplate:          strings.TrimSpace(os.Getenv("SPRINT_TEMPLATE")),
+		SprintLabel:             strings.TrimPrefix(strings.TrimSpace(os.Getenv("SPRINT_LABEL")), "@"),
+		AtRiskDays:              atRiskDays,
+		AtRiskLabel:             strings.TrimPrefix(strings.TrimSpace(os.Getenv("AT_RISK_LABEL")), "@"),
+		AtRiskComment:           atRiskComment,
+		SomedayProjectID:        somedayProjectID,
+		SomedayLabel:            strings.TrimPrefix(strings.TrimSpace(os.Getenv("SOMEDAY_LABEL")), "@"),
+		WIPLimits:               wipLimits,
+		RateLimitStateFile:      rateLimitStateFile(),
+		DebugAddr:               debugAddr,
+		StatusPageURL:           statusPageURL,
+		DebugDumpDir:            cleanPath(os.Getenv("DEBUG_DUMP_DIR")),
+		InstructionsTemplate:    instructionsTemplate,
+		DisabledToolGroups:      parseList(os.Getenv("DISABLED_TOOL_GROUPS")),
+		ToolProfile:             strings.ToLower(strings.TrimSpace(os.Getenv("TOOL_PROFILE"))),
+		OutputLang:              strings.TrimSpace(os.Getenv("OUTPUT_LANG")),
+		ResponseVerbosity:       strings.TrimSpace(os.Getenv("RESPONSE_VERBOSITY")),
+		PIDFile:                 cleanPath(os.Getenv("PID_FILE")),
+		TokenScopes:             parseList(os.Getenv("TODOIST_TOKEN_SCOPES")),
+		Transport:               NormalizeTransport(os.Getenv("MCP_TRANSPORT")),
+		ListenAddr:              listenAddr(os.Getenv("MCP_LISTEN_ADDR")),
+		PipeName:                pipeName(os.Getenv("MCP_PIPE_NAME")),
+		PipeUser:                strings.TrimSpace(os.Getenv("MCP_PIPE_USER")),
+		BulkSyncThreshold:       bulkSyncThreshold,
+		PerRequestTokens:        strings.ToLower(strings.TrimSpace(os.Getenv("PER_REQUEST_TOKENS"))),
+		AuthToken:               strings.TrimSpace(os.Getenv("MCP_AUTH_TOKEN")),
+		AllowedOrigins:          parseList(os.Getenv("MCP_ALLOWED_ORIGINS")),
+	}
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
@@ -48,16 +319,67 @@ func Load() (*Config, error) {
 
 // Validate checks that the configuration values are well-formed.
 func (c *Config) Validate() error {
-	if c.TodoistAPIToken == "" {
+	switch c.Transport {
+	case "", TransportStdio:
+	case TransportSSE, TransportHTTP:
+		if _, _, err := net.SplitHostPort(c.ListenAddr); err != nil {
+			return fmt.Errorf("invalid listen address %q (MCP_LISTEN_ADDR or --listen; want host:port, e.g. 127.0.0.1:8080): %w", c.ListenAddr, err)
+		}
+	case TransportPipe:
+		if len(c.PipeName) <= len(pipePrefix) || !strings.EqualFold(c.PipeName[:len(pipePrefix)], pipePrefix) {
+			return fmt.Errorf(`invalid pipe name %q (MCP_PIPE_NAME; want \\.\pipe\name)`, c.PipeName)
+		}
+	default:
+		return fmt.Errorf("invalid transport %q (MCP_TRANSPORT or --transport; want stdio, sse, http, or pipe)", c.Transport)
+	}
+	switch c.PerRequestTokens {
+	case "", TokensOff:
+	case TokensOptional, TokensRequired:
+		if c.Transport != TransportSSE && c.Transport != TransportHTTP {
+			return fmt.Errorf("PER_REQUEST_TOKENS needs the sse or http transport")
+		}
+	default:
+		return fmt.Errorf("invalid PER_REQUEST_TOKENS %q (want off, optional, or required)", c.PerRequestTokens)
+	}
+	if c.AuthToken != "" && len(c.AuthToken) < minAuthTokenLength {
+		return fmt.Errorf("MCP_AUTH_TOKEN is too short (got %d characters, want at least %d)", len(c.AuthToken), minAuthTokenLength)
+	}
+	if (c.Transport == TransportSSE || c.Transport == TransportHTTP) && !IsLoopback(c.ListenAddr) &&
+		c.AuthToken == "" && c.PerRequestTokens != TokensRequired {
+		return fmt.Errorf("listen address %q accepts remote connections; set MCP_AUTH_TOKEN or PER_REQUEST_TOKENS=required so that clients must authenticate", c.ListenAddr)
+	}
+	return ValidateToken(c.TodoistAPIToken)
+}
+
+// minAuthTokenLength is the shortest MCP_AUTH_TOKEN accepted.
+const minAuthTokenLength = 16
+
+// IsLoopback reports whether the host:port addr only accepts local
+// connections. An empty host listens on every interface.
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ValidateToken checks that a Todoist API token is well-formed.
+func ValidateToken(token string) error {
+	if token == "" {
 		return fmt.Errorf("API token is required")
 	}
-	if len(c.TodoistAPIToken) < 20 {
-		return fmt.Errorf("API token appears too short (got %d characters)", len(c.TodoistAPIToken))
+	if len(token) < 20 {
+		return fmt.Errorf("API token appears too short (got %d characters)", len(token))
 	}
-	if len(c.TodoistAPIToken) > 200 {
-		return fmt.Errorf("API token appears too long (got %d characters)", len(c.TodoistAPIToken))
+	if len(token) > 200 {
+		return fmt.Errorf("API token appears too long (got %d characters)", len(token))
 	}
-	for _, r := range c.TodoistAPIToken {
+	for _, r := range token {
 		if unicode.IsSpace(r) {
 			return fmt.Errorf("API token contains whitespace characters")
 		}
@@ -67,3 +389,163 @@ func (c *Config) Validate() error {
 	}
 	return nil
 }
+
+// NormalizeTransport lowercases a transport name, defaulting to stdio.
+func NormalizeTransport(value string) string {
+	if value = strings.ToLower(strings.TrimSpace(value)); value == "" {
+		return TransportStdio
+	}
+	return value
+}
+
+// projectID returns the project ID in the environment variable name, or ""
+// when it is unset. Project IDs end up in API paths, so they are held to the
+// same rules as tool ID parameters.
+func projectID(name string) (string, error) {
+	id := strings.TrimSpace(os.Getenv(name))
+	if id == "" {
+		return "", nil
+	}
+	if err := tools.ValidateID(id, name); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// listenAddr returns MCP_LISTEN_ADDR, or the loopback default.
+func listenAddr(value string) string {
+	if value = strings.TrimSpace(value); value == "" {
+		return defaultListenAddr
+	}
+	return value
+}
+
+// pipeName returns MCP_PIPE_NAME, or the default pipe.
+func pipeName(value string) string {
+	if value = strings.TrimSpace(value); value == "" {
+		return defaultPipeName
+	}
+	return value
+}
+
+// templatesDir returns TEMPLATES_DIR if set, otherwise a directory under the
+// user's config directory (falling back to the working directory).
+func templatesDir() string {
+	if dir := os.Getenv("TEMPLATES_DIR"); dir != "" {
+		return filepath.Clean(dir)
+	}
+	if base, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(base, "mcp-todoist", "templates")
+	}
+	return "templates"
+}
+
+// deleteBackupDir returns DELETE_BACKUP_DIR, which also turns on backups
+// before project deletion, or a default directory under the user's config
+// directory used only when a backup is requested explicitly.
+func deleteBackupDir() (string, bool) {
+	if dir := strings.TrimSpace(os.Getenv("DELETE_BACKUP_DIR")); dir != "" {
+		return filepath.Clean(dir), true
+	}
+	if base, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(base, "mcp-todoist", "backups"), false
+	}
+	return "backups", false
+}
+
+// cleanPath cleans a path setting, keeping empty values empty.
+func cleanPath(value string) string {
+	if value = strings.TrimSpace(value); value == "" {
+		return ""
+	}
+	return filepath.Clean(value)
+}
+
+// rateLimitStateFile returns RATE_LIMIT_STATE_FILE if set, otherwise a file
+// under the user's cache directory. "off" disables persistence.
+func rateLimitStateFile() string {
+	switch path := strings.TrimSpace(os.Getenv("RATE_LIMIT_STATE_FILE")); {
+	case strings.EqualFold(path, "off"):
+		return ""
+	case path != "":
+		return filepath.Clean(path)
+	}
+	if base, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(base, "mcp-todoist", "ratelimit.json")
+	}
+	return ""
+}
+
+// parseDebugAddr validates DEBUG_ADDR, a host:port for the diagnostics
+// endpoint. Profiles expose process internals, so only loopback hosts are
+// accepted.
+func parseDebugAddr(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	host, _, err := net.SplitHostPort(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid DEBUG_ADDR %q (want host:port, e.g. 127.0.0.1:6060): %w", value, err)
+	}
+	if host == "localhost" {
+		return value, nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return "", fmt.Errorf("DEBUG_ADDR %q must use a loopback host such as 127.0.0.1 or localhost", value)
+	}
+	return value, nil
+}
+
+// ReloadDisabledToolGroups re-reads the .env file, letting it override the
+// process environment, and returns DISABLED_TOOL_GROUPS. It backs SIGHUP
+// reloads, since a running process cannot see changes to its parent's
+// environment.
+func ReloadDisabledToolGroups() []string {
+	_ = godotenv.Overload()
+	return parseList(os.Getenv("DISABLED_TOOL_GROUPS"))
+}
+
+// parseList parses a comma-separated list such as tool group names or
+// token scopes, lowercased.
+func parseList(value string) []string {
+	var groups []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			groups = append(groups, name)
+		}
+	}
+	return groups
+}
+
+// parseLabels splits a comma-separated list of label names, dropping any
+// leading @. Unlike parseList it keeps the names' case.
+func parseLabels(value string) []string {
+	var labels []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimPrefix(strings.TrimSpace(name), "@"); name != "" {
+			labels = append(labels, name)
+		}
+	}
+	return labels
+}
+
+// parseWIPLimits parses WIP_LIMITS, a comma-separated list of section=limit
+// pairs such as "Doing=3,Review=2". Section names are matched case-insensitively.
+func parseWIPLimits(value string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, rawLimit, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		limit, err := strconv.Atoi(strings.TrimSpace(rawLimit))
+		if !ok || name == "" || err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid WIP_LIMITS entry %q (want section=limit, e.g. Doing=3)", pair)
+		}
+		limits[strings.ToLower(name)] = limit
+	}
+	return limits, nil
+}
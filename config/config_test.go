@@ -145,3 +145,546 @@ func TestLoad_MissingEnvVar(t *testing.T) {
 		t.Errorf("error = %q, want substring 'is required'", err.Error())
 	}
 }
+
+func TestLoad_TemplatesDir(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+
+	dir := t.TempDir()
+	t.Setenv("TEMPLATES_DIR", dir)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.TemplatesDir != dir {
+		t.Errorf("TemplatesDir = %q, want %q", cfg.TemplatesDir, dir)
+	}
+
+	t.Setenv("TEMPLATES_DIR", "")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !strings.HasSuffix(cfg.TemplatesDir, "templates") {
+		t.Errorf("default TemplatesDir = %q, want a templates directory", cfg.TemplatesDir)
+	}
+}
+
+func TestLoad_DeleteBackupDir(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+
+	dir := t.TempDir()
+	t.Setenv("DELETE_BACKUP_DIR", dir)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.BackupDir != dir || !cfg.BackupBeforeDelete {
+		t.Errorf("BackupDir = %q, BackupBeforeDelete = %v, want %q and true", cfg.BackupDir, cfg.BackupBeforeDelete, dir)
+	}
+
+	t.Setenv("DELETE_BACKUP_DIR", "")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !strings.HasSuffix(cfg.BackupDir, "backups") || cfg.BackupBeforeDelete {
+		t.Errorf("default BackupDir = %q, BackupBeforeDelete = %v, want a backups directory and false", cfg.BackupDir, cfg.BackupBeforeDelete)
+	}
+}
+
+func TestLoad_TriageProjectID(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("TRIAGE_PROJECT_ID", " 2203306141 ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.TriageProjectID != "2203306141" {
+		t.Errorf("TriageProjectID = %q, want 2203306141", cfg.TriageProjectID)
+	}
+
+	t.Setenv("TRIAGE_PROJECT_ID", "../projects")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TRIAGE_PROJECT_ID") {
+		t.Errorf("TRIAGE_PROJECT_ID=../projects: error = %v, want TRIAGE_PROJECT_ID error", err)
+	}
+}
+
+func TestLoad_WeeklySnapshotProjectID(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("WEEKLY_SNAPSHOT_PROJECT_ID", " 2203306141 ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.WeeklySnapshotProjectID != "2203306141" {
+		t.Errorf("WeeklySnapshotProjectID = %q, want 2203306141", cfg.WeeklySnapshotProjectID)
+	}
+
+	t.Setenv("WEEKLY_SNAPSHOT_PROJECT_ID", "../projects")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "WEEKLY_SNAPSHOT_PROJECT_ID") {
+		t.Errorf("WEEKLY_SNAPSHOT_PROJECT_ID=../projects: error = %v, want WEEKLY_SNAPSHOT_PROJECT_ID error", err)
+	}
+}
+
+func TestLoad_SomedayProjectID(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("SOMEDAY_PROJECT_ID", " 2203306141 ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.SomedayProjectID != "2203306141" {
+		t.Errorf("SomedayProjectID = %q, want 2203306141", cfg.SomedayProjectID)
+	}
+
+	t.Setenv("SOMEDAY_PROJECT_ID", "../projects")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "SOMEDAY_PROJECT_ID") {
+		t.Errorf("SOMEDAY_PROJECT_ID=../projects: error = %v, want SOMEDAY_PROJECT_ID error", err)
+	}
+}
+
+func TestLoad_WIPLimits(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("WIP_LIMITS", " Doing=3, review = 2 ,")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(cfg.WIPLimits) != 2 || cfg.WIPLimits["doing"] != 3 || cfg.WIPLimits["review"] != 2 {
+		t.Errorf("WIPLimits = %v, want doing=3 review=2", cfg.WIPLimits)
+	}
+
+	for _, bad := range []string{"Doing", "Doing=three", "=3", "Doing=-1"} {
+		t.Setenv("WIP_LIMITS", bad)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "WIP_LIMITS") {
+			t.Errorf("WIP_LIMITS=%q: error = %v, want WIP_LIMITS error", bad, err)
+		}
+	}
+}
+
+func TestLoad_RateLimitStateFile(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+
+	tests := []struct {
+		name  string
+		value string
+		want  func(string) bool
+	}{
+		{"explicit path", "/tmp/todoist/../rl.json", func(p string) bool { return p == "/tmp/rl.json" }},
+		{"disabled", "off", func(p string) bool { return p == "" }},
+		{"default under cache dir", "", func(p string) bool {
+			return p == "" || strings.HasSuffix(p, filepath.Join("mcp-todoist", "ratelimit.json"))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RATE_LIMIT_STATE_FILE", tt.value)
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() error: %v", err)
+			}
+			if !tt.want(cfg.RateLimitStateFile) {
+				t.Errorf("RateLimitStateFile = %q", cfg.RateLimitStateFile)
+			}
+		})
+	}
+}
+
+func TestLoad_DebugAddr(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+
+	for _, good := range []string{"127.0.0.1:6060", "localhost:6060", "[::1]:6060", ""} {
+		t.Setenv("DEBUG_ADDR", good)
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("DEBUG_ADDR=%q: Load() error: %v", good, err)
+		}
+		if cfg.DebugAddr != good {
+			t.Errorf("DebugAddr = %q, want %q", cfg.DebugAddr, good)
+		}
+	}
+
+	for _, bad := range []string{"0.0.0.0:6060", ":6060", "example.com:6060", "6060"} {
+		t.Setenv("DEBUG_ADDR", bad)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "DEBUG_ADDR") {
+			t.Errorf("DEBUG_ADDR=%q: error = %v, want DEBUG_ADDR error", bad, err)
+		}
+	}
+}
+
+func TestLoad_StatusPageURL(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+
+	for _, good := range []string{"https://status.example.com/api/v2/status.json", ""} {
+		t.Setenv("STATUS_PAGE_URL", good)
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("STATUS_PAGE_URL=%q: Load() error: %v", good, err)
+		}
+		if cfg.StatusPageURL != good {
+			t.Errorf("StatusPageURL = %q, want %q", cfg.StatusPageURL, good)
+		}
+	}
+
+	for _, bad := range []string{"status.example.com", "ftp://status.example.com", "https://"} {
+		t.Setenv("STATUS_PAGE_URL", bad)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "STATUS_PAGE_URL") {
+			t.Errorf("STATUS_PAGE_URL=%q: error = %v, want STATUS_PAGE_URL error", bad, err)
+		}
+	}
+}
+
+func TestLoad_Transport(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+
+	t.Setenv("MCP_TRANSPORT", "")
+	t.Setenv("MCP_LISTEN_ADDR", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Transport != TransportStdio || cfg.ListenAddr != "127.0.0.1:8080" {
+		t.Errorf("defaults = %q, %q", cfg.Transport, cfg.ListenAddr)
+	}
+
+	t.Setenv("MCP_TRANSPORT", " HTTP ")
+	t.Setenv("MCP_LISTEN_ADDR", "0.0.0.0:9000")
+	t.Setenv("MCP_AUTH_TOKEN", "")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "MCP_AUTH_TOKEN") {
+		t.Errorf("remote listen address without authentication: error = %v", err)
+	}
+	t.Setenv("MCP_AUTH_TOKEN", "s3cret-s3cret-s3cret")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Transport != TransportHTTP || cfg.ListenAddr != "0.0.0.0:9000" {
+		t.Errorf("got %q, %q", cfg.Transport, cfg.ListenAddr)
+	}
+
+	t.Setenv("MCP_LISTEN_ADDR", "9000")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "listen address") {
+		t.Errorf("bad listen address: error = %v", err)
+	}
+	// The address is only used by network transports.
+	t.Setenv("MCP_TRANSPORT", "stdio")
+	if _, err := Load(); err != nil {
+		t.Errorf("stdio with unused bad address: %v", err)
+	}
+
+	t.Setenv("MCP_TRANSPORT", "pipe")
+	t.Setenv("MCP_PIPE_NAME", "")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Transport != TransportPipe || cfg.PipeName != `\\.\pipe\mcp-todoist` {
+		t.Errorf("pipe defaults = %q, %q", cfg.Transport, cfg.PipeName)
+	}
+	t.Setenv("MCP_PIPE_NAME", "mcp-todoist")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "pipe name") {
+		t.Errorf("bad pipe name: error = %v", err)
+	}
+
+	t.Setenv("MCP_TRANSPORT", "websocket")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "transport") {
+		t.Errorf("bad transport: error = %v", err)
+	}
+}
+
+func TestLoad_PerRequestTokens(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("MCP_LISTEN_ADDR", "")
+
+	t.Setenv("MCP_TRANSPORT", "http")
+	t.Setenv("PER_REQUEST_TOKENS", " Required ")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.PerRequestTokens != TokensRequired {
+		t.Errorf("PerRequestTokens = %q", cfg.PerRequestTokens)
+	}
+
+	t.Setenv("MCP_TRANSPORT", "stdio")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "sse or http") {
+		t.Errorf("stdio with per-request tokens: error = %v", err)
+	}
+
+	t.Setenv("MCP_TRANSPORT", "sse")
+	t.Setenv("PER_REQUEST_TOKENS", "always")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "PER_REQUEST_TOKENS") {
+		t.Errorf("bad mode: error = %v", err)
+	}
+}
+
+func TestLoad_AuthToken(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("MCP_TRANSPORT", "http")
+	t.Setenv("MCP_LISTEN_ADDR", ":8080")
+	t.Setenv("MCP_AUTH_TOKEN", "")
+	t.Setenv("MCP_ALLOWED_ORIGINS", "https://mcp.example.com, https://app.example.com")
+
+	// Required per-request tokens authenticate every request.
+	t.Setenv("PER_REQUEST_TOKENS", "required")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(cfg.AllowedOrigins) != 2 || cfg.AllowedOrigins[1] != "https://app.example.com" {
+		t.Errorf("AllowedOrigins = %q", cfg.AllowedOrigins)
+	}
+
+	t.Setenv("PER_REQUEST_TOKENS", "optional")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "accepts remote connections") {
+		t.Errorf("optional tokens on all interfaces: error = %v", err)
+	}
+
+	t.Setenv("MCP_AUTH_TOKEN", "short")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "too short") {
+		t.Errorf("short MCP_AUTH_TOKEN: error = %v", err)
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:8080": true,
+		"[::1]:8080":     true,
+		"0.0.0.0:8080":   false,
+		":8080":          false,
+		"10.0.0.5:8080":  false,
+		"8080":           false,
+	} {
+		if got := IsLoopback(addr); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestLoad_DebugDumpDir(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+
+	t.Setenv("DEBUG_DUMP_DIR", " /tmp/dumps/../fixtures ")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.DebugDumpDir != "/tmp/fixtures" {
+		t.Errorf("DebugDumpDir = %q, want /tmp/fixtures", cfg.DebugDumpDir)
+	}
+
+	t.Setenv("DEBUG_DUMP_DIR", "")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.DebugDumpDir != "" {
+		t.Errorf("DebugDumpDir = %q, want empty", cfg.DebugDumpDir)
+	}
+}
+
+func TestLoad_InstructionsFile(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+
+	path := filepath.Join(t.TempDir(), "instructions.tmpl")
+	if err := os.WriteFile(path, []byte("Use {{.ToolCount}} tools wisely."), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("INSTRUCTIONS_FILE", path)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.InstructionsTemplate != "Use {{.ToolCount}} tools wisely." {
+		t.Errorf("InstructionsTemplate = %q", cfg.InstructionsTemplate)
+	}
+
+	t.Setenv("INSTRUCTIONS_FILE", filepath.Join(t.TempDir(), "missing.tmpl"))
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "INSTRUCTIONS_FILE") {
+		t.Errorf("error = %v, want INSTRUCTIONS_FILE error", err)
+	}
+}
+
+func TestLoad_DisabledToolGroups(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("DISABLED_TOOL_GROUPS", " Maintenance, templates ,,")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(cfg.DisabledToolGroups) != 2 || cfg.DisabledToolGroups[0] != "maintenance" || cfg.DisabledToolGroups[1] != "templates" {
+		t.Errorf("DisabledToolGroups = %v, want [maintenance templates]", cfg.DisabledToolGroups)
+	}
+}
+
+func TestLoad_ToolProfile(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("TOOL_PROFILE", " Basic ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.ToolProfile != "basic" {
+		t.Errorf("ToolProfile = %q, want basic", cfg.ToolProfile)
+	}
+}
+
+func TestLoad_TokenScopes(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("TODOIST_TOKEN_SCOPES", "Data:Read, task:add")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(cfg.TokenScopes) != 2 || cfg.TokenScopes[0] != "data:read" || cfg.TokenScopes[1] != "task:add" {
+		t.Errorf("TokenScopes = %v, want [data:read task:add]", cfg.TokenScopes)
+	}
+}
+
+func TestLoad_PIDFile(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("PID_FILE", " /run/mcp-todoist/../mcp-todoist.pid ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.PIDFile != "/run/mcp-todoist.pid" {
+		t.Errorf("PIDFile = %q, want /run/mcp-todoist.pid", cfg.PIDFile)
+	}
+}
+
+func TestLoad_Sprints(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("SPRINT_WEEKS", " 2 ")
+	t.Setenv("SPRINT_LABEL", "@Next")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.SprintWeeks != 2 || cfg.SprintLabel != "Next" {
+		t.Errorf("SprintWeeks = %d, SprintLabel = %q, want 2 and Next", cfg.SprintWeeks, cfg.SprintLabel)
+	}
+
+	for _, bad := range []string{"0", "9", "two"} {
+		t.Setenv("SPRINT_WEEKS", bad)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "SPRINT_WEEKS") {
+			t.Errorf("SPRINT_WEEKS=%q: error = %v, want SPRINT_WEEKS error", bad, err)
+		}
+	}
+}
+
+func TestLoad_BulkSyncThreshold(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.BulkSyncThreshold != 0 {
+		t.Errorf("BulkSyncThreshold = %d, want 0 when unset", cfg.BulkSyncThreshold)
+	}
+
+	t.Setenv("BULK_SYNC_THRESHOLD", " 20 ")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.BulkSyncThreshold != 20 {
+		t.Errorf("BulkSyncThreshold = %d, want 20", cfg.BulkSyncThreshold)
+	}
+
+	for _, bad := range []string{"0", "101", "many"} {
+		t.Setenv("BULK_SYNC_THRESHOLD", bad)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "BULK_SYNC_THRESHOLD") {
+			t.Errorf("BULK_SYNC_THRESHOLD=%q: error = %v, want BULK_SYNC_THRESHOLD error", bad, err)
+		}
+	}
+}
+
+func TestLoad_AtRisk(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("AT_RISK_DAYS", "5")
+	t.Setenv("AT_RISK_LABEL", "@Risk")
+	t.Setenv("AT_RISK_COMMENT", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.AtRiskDays != 5 || cfg.AtRiskLabel != "Risk" || !cfg.AtRiskComment {
+		t.Errorf("AtRiskDays = %d, AtRiskLabel = %q, AtRiskComment = %v, want 5, Risk, true", cfg.AtRiskDays, cfg.AtRiskLabel, cfg.AtRiskComment)
+	}
+
+	for _, bad := range []string{"0", "31", "soon"} {
+		t.Setenv("AT_RISK_DAYS", bad)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "AT_RISK_DAYS") {
+			t.Errorf("AT_RISK_DAYS=%q: error = %v, want AT_RISK_DAYS error", bad, err)
+		}
+	}
+	t.Setenv("AT_RISK_DAYS", "")
+	t.Setenv("AT_RISK_COMMENT", "maybe")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "AT_RISK_COMMENT") {
+		t.Errorf("AT_RISK_COMMENT=maybe: error = %v, want AT_RISK_COMMENT error", err)
+	}
+}
+
+func TestLoad_CreationDefaults(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	t.Setenv("DEFAULT_PROJECT", " 2203306141 ")
+	t.Setenv("DEFAULT_LABELS", "@Agent, triage,,")
+	t.Setenv("DEFAULT_PRIORITY", "2")
+	t.Setenv("DEFAULT_DUE", " today ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.DefaultProjectID != "2203306141" || cfg.DefaultPriority != 2 || cfg.DefaultDue != "today" {
+		t.Errorf("DefaultProjectID = %q, DefaultPriority = %d, DefaultDue = %q", cfg.DefaultProjectID, cfg.DefaultPriority, cfg.DefaultDue)
+	}
+	if len(cfg.DefaultLabels) != 2 || cfg.DefaultLabels[0] != "Agent" || cfg.DefaultLabels[1] != "triage" {
+		t.Errorf("DefaultLabels = %v, want [Agent triage]", cfg.DefaultLabels)
+	}
+
+	t.Setenv("DEFAULT_PROJECT", "../projects")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "DEFAULT_PROJECT") {
+		t.Errorf("DEFAULT_PROJECT=../projects: error = %v, want DEFAULT_PROJECT error", err)
+	}
+	t.Setenv("DEFAULT_PROJECT", "2203306141")
+
+	for _, bad := range []string{"0", "5", "high"} {
+		t.Setenv("DEFAULT_PRIORITY", bad)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "DEFAULT_PRIORITY") {
+			t.Errorf("DEFAULT_PRIORITY=%q: error = %v, want DEFAULT_PRIORITY error", bad, err)
+		}
+	}
+}
+
+func TestLoad_CreationPoliciesFile(t *testing.T) {
+	t.Setenv("TODOIST_API_TOKEN", "abcdef1234567890abcdef1234567890abcdef12")
+	path := filepath.Join(t.TempDir(), "policies.json")
+	if err := os.WriteFile(path, []byte(`{"p1": {"require_due": true}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CREATION_POLICIES_FILE", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.CreationPolicies != `{"p1": {"require_due": true}}` {
+		t.Errorf("CreationPolicies = %q", cfg.CreationPolicies)
+	}
+
+	t.Setenv("CREATION_POLICIES_FILE", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "CREATION_POLICIES_FILE") {
+		t.Errorf("missing file: error = %v, want CREATION_POLICIES_FILE error", err)
+	}
+}
@@ -0,0 +1,364 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSecretRefresh is how often a token read from a secrets manager is
+// fetched again, so rotated secrets are picked up without a restart.
+const defaultSecretRefresh = time.Hour
+
+// secretTimeout bounds a single secrets manager lookup.
+const secretTimeout = 10 * time.Second
+
+// Endpoints of the secrets managers and credential sources. Variables so
+// tests can point them at a local server.
+var (
+	awsSecretsEndpoint = func(region string) string {
+		return "https://secretsmanager." + region + ".amazonaws.com/"
+	}
+	awsContainerCredentialsBase = "http://169.254.170.2"
+	gcpSecretManagerBase        = "https://secretmanager.googleapis.com/v1/"
+	gcpMetadataTokenURL         = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	secretHTTPClient            = &http.Client{Timeout: secretTimeout}
+)
+
+// TokenSource fetches the API token from a secrets manager and caches it
+// for the refresh interval.
+type TokenSource struct {
+	ref     string
+	fetch   func(ctx context.Context) (string, error)
+	refresh time.Duration
+
+	mu      sync.Mutex
+	token   string
+	fetched time.Time
+}
+
+// newTokenSource returns a source for an aws-sm:// or gcp-sm:// reference,
+// or nil if ref names neither.
+func newTokenSource(ref string, refresh time.Duration) (*TokenSource, error) {
+	src := &TokenSource{ref: ref, refresh: refresh}
+	switch {
+	case strings.HasPrefix(ref, "aws-sm://"):
+		secret, err := parseAWSSecretRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		src.fetch = secret.fetch
+	case strings.HasPrefix(ref, "gcp-sm://"):
+		name, err := parseGCPSecretRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		src.fetch = func(ctx context.Context) (string, error) { return fetchGCPSecret(ctx, name) }
+	default:
+		return nil, nil
+	}
+	return src, nil
+}
+
+// Token returns the cached token, fetching it again once the refresh
+// interval has passed. If a refresh fails, the previous token is kept and the
+// error returned alongside it.
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Since(s.fetched) < s.refresh {
+		return s.token, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, secretTimeout)
+	defer cancel()
+	token, err := s.fetch(ctx)
+	if err == nil {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			err = fmt.Errorf("secret is empty")
+		}
+	}
+	if err != nil {
+		return s.token, fmt.Errorf("failed to read API token from %s: %w", s.ref, err)
+	}
+	s.token, s.fetched = token, time.Now()
+	return token, nil
+}
+
+// RefreshInterval is how long a fetched token is reused.
+func (s *TokenSource) RefreshInterval() time.Duration {
+	return s.refresh
+}
+
+// awsSecretRef is a parsed aws-sm://<secret-id>[?region=...&key=...]
+// reference. The secret ID may be a name or a full ARN.
+type awsSecretRef struct {
+	secretID string
+	region   string
+	// key selects a field when the secret string is a JSON object.
+	key string
+}
+
+func parseAWSSecretRef(ref string) (awsSecretRef, error) {
+	rest := strings.TrimPrefix(ref, "aws-sm://")
+	id, rawQuery, _ := strings.Cut(rest, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil || id == "" {
+		return awsSecretRef{}, fmt.Errorf("invalid secret reference %q (want aws-sm://<secret-id>[?region=<region>&key=<json-field>])", ref)
+	}
+	secret := awsSecretRef{secretID: id, region: query.Get("region"), key: query.Get("key")}
+	if secret.region == "" && strings.HasPrefix(id, "arn:") {
+		// arn:aws:secretsmanager:<region>:<account>:secret:<name>
+		if parts := strings.Split(id, ":"); len(parts) > 3 {
+			secret.region = parts[3]
+		}
+	}
+	if secret.region == "" {
+		secret.region = os.Getenv("AWS_REGION")
+	}
+	if secret.region == "" {
+		secret.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if secret.region == "" {
+		return awsSecretRef{}, fmt.Errorf("no AWS region for %q: add ?region= or set AWS_REGION", ref)
+	}
+	return secret, nil
+}
+
+// awsCredentials are the keys used to sign Secrets Manager requests.
+type awsCredentials struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"Token"`
+}
+
+// loadAWSCredentials reads credentials from the standard environment
+// variables, or from the ECS/EKS container credentials endpoint.
+func loadAWSCredentials(ctx context.Context) (awsCredentials, error) {
+	if id := os.Getenv("AWS_ACCESS_KEY_ID"); id != "" {
+		return awsCredentials{
+			AccessKeyID:     id,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+	uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if rel := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); uri == "" && rel != "" {
+		uri = awsContainerCredentialsBase + rel
+	}
+	if uri == "" {
+		return awsCredentials{}, fmt.Errorf("no AWS credentials: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, or run with a container credentials endpoint")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	if token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN"); token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	var creds awsCredentials
+	if err := doSecretRequest(req, &creds); err != nil {
+		return awsCredentials{}, fmt.Errorf("fetching container credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// fetch calls Secrets Manager GetSecretValue.
+func (a awsSecretRef) fetch(ctx context.Context) (string, error) {
+	creds, err := loadAWSCredentials(ctx)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(map[string]string{"SecretId": a.secretID})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, awsSecretsEndpoint(a.region), bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signAWSRequest(req, payload, creds, a.region, "secretsmanager", time.Now())
+
+	var resp struct {
+		SecretString string `json:"SecretString"`
+		SecretBinary []byte `json:"SecretBinary"`
+	}
+	if err := doSecretRequest(req, &resp); err != nil {
+		return "", err
+	}
+	value := resp.SecretString
+	if value == "" {
+		value = string(resp.SecretBinary)
+	}
+	if a.key == "" {
+		return value, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, cannot select key %q", a.key)
+	}
+	field, ok := fields[a.key].(string)
+	if !ok {
+		return "", fmt.Errorf("secret has no string field %q", a.key)
+	}
+	return field, nil
+}
+
+// signAWSRequest adds AWS Signature Version 4 headers to req.
+func signAWSRequest(req *http.Request, payload []byte, creds awsCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(payload)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// parseGCPSecretRef turns gcp-sm://<project>/<secret>[/<version>] or
+// gcp-sm://projects/<project>/secrets/<secret>[/versions/<version>] into a
+// secret version resource name.
+func parseGCPSecretRef(ref string) (string, error) {
+	rest := strings.Trim(strings.TrimPrefix(ref, "gcp-sm://"), "/")
+	parts := strings.Split(rest, "/")
+	switch {
+	case len(parts) == 4 && parts[0] == "projects" && parts[2] == "secrets":
+		return rest + "/versions/latest", nil
+	case len(parts) == 6 && parts[0] == "projects" && parts[2] == "secrets" && parts[4] == "versions":
+		return rest, nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return fmt.Sprintf("projects/%s/secrets/%s/versions/latest", parts[0], parts[1]), nil
+	case len(parts) == 3 && parts[0] != "" && parts[1] != "" && parts[2] != "":
+		return fmt.Sprintf("projects/%s/secrets/%s/versions/%s", parts[0], parts[1], parts[2]), nil
+	}
+	return "", fmt.Errorf("invalid secret reference %q (want gcp-sm://<project>/<secret>[/<version>])", ref)
+}
+
+// gcpAccessToken returns GOOGLE_OAUTH_ACCESS_TOKEN if set, otherwise a token
+// for the default service account from the metadata server.
+func gcpAccessToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpMetadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	var resp struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := doSecretRequest(req, &resp); err != nil {
+		return "", fmt.Errorf("no GCP credentials: set GOOGLE_OAUTH_ACCESS_TOKEN or run on GCP (%w)", err)
+	}
+	return resp.AccessToken, nil
+}
+
+// fetchGCPSecret accesses a Secret Manager secret version.
+func fetchGCPSecret(ctx context.Context, name string) (string, error) {
+	token, err := gcpAccessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpSecretManagerBase+name+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	var resp struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := doSecretRequest(req, &resp); err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("decoding secret payload: %w", err)
+	}
+	return string(data), nil
+}
+
+// doSecretRequest sends req and decodes a JSON response into out.
+func doSecretRequest(req *http.Request, out interface{}) error {
+	resp, err := secretHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
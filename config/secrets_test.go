@@ -0,0 +1,168 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAWSRequest(t *testing.T) {
+	// The "get-vanilla" case from the AWS Signature Version 4 test suite.
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signAWSRequest(req, nil, creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseAWSSecretRef(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	got, err := parseAWSSecretRef("aws-sm://arn:aws:secretsmanager:eu-west-1:123456789012:secret:todoist-AbCdEf?key=token")
+	if err != nil {
+		t.Fatalf("parseAWSSecretRef() error: %v", err)
+	}
+	if got.region != "eu-west-1" || got.key != "token" || !strings.HasSuffix(got.secretID, "secret:todoist-AbCdEf") {
+		t.Errorf("parsed = %+v", got)
+	}
+
+	if _, err := parseAWSSecretRef("aws-sm://todoist"); err == nil || !strings.Contains(err.Error(), "AWS_REGION") {
+		t.Errorf("error = %v, want missing region error", err)
+	}
+	t.Setenv("AWS_REGION", "us-east-2")
+	if got, err := parseAWSSecretRef("aws-sm://todoist"); err != nil || got.region != "us-east-2" {
+		t.Errorf("parsed = %+v, %v, want region from AWS_REGION", got, err)
+	}
+}
+
+func TestParseGCPSecretRef(t *testing.T) {
+	tests := map[string]string{
+		"gcp-sm://my-proj/todoist":                             "projects/my-proj/secrets/todoist/versions/latest",
+		"gcp-sm://my-proj/todoist/3":                           "projects/my-proj/secrets/todoist/versions/3",
+		"gcp-sm://projects/my-proj/secrets/todoist":            "projects/my-proj/secrets/todoist/versions/latest",
+		"gcp-sm://projects/my-proj/secrets/todoist/versions/2": "projects/my-proj/secrets/todoist/versions/2",
+	}
+	for ref, want := range tests {
+		if got, err := parseGCPSecretRef(ref); err != nil || got != want {
+			t.Errorf("parseGCPSecretRef(%q) = %q, %v, want %q", ref, got, err, want)
+		}
+	}
+	if _, err := parseGCPSecretRef("gcp-sm://todoist"); err == nil {
+		t.Error("expected error for a reference without project")
+	}
+}
+
+func TestLoad_AWSSecretsManager(t *testing.T) {
+	const token = "abcdef1234567890abcdef1234567890abcdef12"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			r.Header.Get("X-Amz-Security-Token") != "session" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		var body struct{ SecretId string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		secret, _ := json.Marshal(map[string]string{"token": token})
+		_ = json.NewEncoder(w).Encode(map[string]string{"Name": body.SecretId, "SecretString": string(secret)})
+	}))
+	defer srv.Close()
+	previous := awsSecretsEndpoint
+	awsSecretsEndpoint = func(string) string { return srv.URL + "/" }
+	t.Cleanup(func() { awsSecretsEndpoint = previous })
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+	t.Setenv("TODOIST_API_TOKEN", "aws-sm://prod/todoist?region=us-east-1&key=token")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.TodoistAPIToken != token || cfg.TokenSource == nil {
+		t.Errorf("token = %q, source = %v", cfg.TodoistAPIToken, cfg.TokenSource)
+	}
+	if cfg.TokenSource.RefreshInterval() != defaultSecretRefresh {
+		t.Errorf("RefreshInterval() = %v, want %v", cfg.TokenSource.RefreshInterval(), defaultSecretRefresh)
+	}
+}
+
+func TestLoad_GCPSecretManager(t *testing.T) {
+	const token = "abcdef1234567890abcdef1234567890abcdef12"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token" && r.Header.Get("Metadata-Flavor") == "Google":
+			fmt.Fprint(w, `{"access_token":"ya29.test","expires_in":3599}`)
+		case r.URL.Path == "/v1/projects/my-proj/secrets/todoist/versions/latest:access" && r.Header.Get("Authorization") == "Bearer ya29.test":
+			fmt.Fprintf(w, `{"payload":{"data":%q}}`, base64.StdEncoding.EncodeToString([]byte(token+"\n")))
+		default:
+			http.Error(w, "not found: "+r.URL.Path, http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	prevBase, prevToken := gcpSecretManagerBase, gcpMetadataTokenURL
+	gcpSecretManagerBase, gcpMetadataTokenURL = srv.URL+"/v1/", srv.URL+"/token"
+	t.Cleanup(func() { gcpSecretManagerBase, gcpMetadataTokenURL = prevBase, prevToken })
+
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+	t.Setenv("SECRET_REFRESH_INTERVAL", "15m")
+	t.Setenv("TODOIST_API_TOKEN", "gcp-sm://my-proj/todoist")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.TodoistAPIToken != token {
+		t.Errorf("token = %q, want %q", cfg.TodoistAPIToken, token)
+	}
+	if cfg.TokenSource.RefreshInterval() != 15*time.Minute {
+		t.Errorf("RefreshInterval() = %v, want 15m", cfg.TokenSource.RefreshInterval())
+	}
+
+	t.Setenv("SECRET_REFRESH_INTERVAL", "10s")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "SECRET_REFRESH_INTERVAL") {
+		t.Errorf("error = %v, want SECRET_REFRESH_INTERVAL error", err)
+	}
+}
+
+func TestTokenSource_Refresh(t *testing.T) {
+	calls := 0
+	values := []string{"first", "", "third"}
+	src := &TokenSource{ref: "test://", refresh: time.Hour, fetch: func(context.Context) (string, error) {
+		v := values[calls]
+		calls++
+		if v == "" {
+			return "", fmt.Errorf("unavailable")
+		}
+		return v, nil
+	}}
+
+	if got, err := src.Token(context.Background()); err != nil || got != "first" {
+		t.Fatalf("Token() = %q, %v", got, err)
+	}
+	if got, _ := src.Token(context.Background()); got != "first" || calls != 1 {
+		t.Errorf("cached Token() = %q after %d fetches, want first after 1", got, calls)
+	}
+
+	src.fetched = time.Now().Add(-2 * time.Hour)
+	if got, err := src.Token(context.Background()); err == nil || got != "first" {
+		t.Errorf("failed refresh = %q, %v, want previous token and an error", got, err)
+	}
+	if got, err := src.Token(context.Background()); err != nil || got != "third" {
+		t.Errorf("Token() = %q, %v, want third", got, err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"expvar"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+// startDebugServer serves net/http/pprof and expvar on addr, which config
+// restricts to loopback hosts. It runs on its own mux so nothing else is
+// exposed, and never stops the MCP server: failures are only logged.
+func startDebugServer(addr string, rl *todoist.RateLimiter, scheduler *todoist.Scheduler) {
+	started := time.Now()
+	expvar.Publish("version", expvar.Func(func() any { return version }))
+	expvar.Publish("uptime_seconds", expvar.Func(func() any { return int(time.Since(started).Seconds()) }))
+	expvar.Publish("goroutines", expvar.Func(func() any { return runtime.NumGoroutine() }))
+	expvar.Publish("rate_limit_remaining", expvar.Func(func() any { return rl.Remaining() }))
+	expvar.Publish("requests", expvar.Func(func() any {
+		inFlight, waiting := scheduler.Stats()
+		return map[string]int{"in_flight": inFlight, "waiting": waiting}
+	}))
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		slog.Info("debug endpoint listening", "addr", addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Warn("debug endpoint stopped", "addr", addr, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-todoist/config"
+)
+
+// shutdownTimeout is how long in-flight HTTP requests get to finish once
+// the server is interrupted.
+const shutdownTimeout = 10 * time.Second
+
+// httpTransport is an MCP transport served over HTTP. Shutdown closes its
+// sessions and then the HTTP server it was given.
+type httpTransport interface {
+	http.Handler
+	Shutdown(ctx context.Context) error
+}
+
+// newTransport creates the MCP transport for s, which is sse or http. With
+// srv, Shutdown also shuts down srv.
+func newTransport(s *server.MCPServer, transport string, srv *http.Server) httpTransport {
+	if transport == config.TransportSSE {
+		// A relative message endpoint keeps working behind a reverse proxy
+		// that serves the server under another host or scheme.
+		opts := []server.SSEOption{server.WithUseFullURLForMessageEndpoint(false), server.WithKeepAlive(true)}
+		if srv != nil {
+			opts = append(opts, server.WithHTTPServer(srv))
+		}
+		return server.NewSSEServer(s, opts...)
+	}
+	opts := []server.StreamableHTTPOption{server.WithHeartbeatInterval(30 * time.Second)}
+	if srv != nil {
+		opts = append(opts, server.WithStreamableHTTPServer(srv))
+	}
+	return server.NewStreamableHTTPServer(s, opts...)
+}
+
+// httpAccess is who may call the sse and http transports.
+type httpAccess struct {
+	// authToken, if set, is the shared secret clients send as a bearer token.
+	authToken string
+	// origins are the browser origins allowed besides loopback ones.
+	origins []string
+	// perRequestTokens is whether a bearer token other than authToken is a
+	// Todoist token for the tenant router to check.
+	perRequestTokens bool
+}
+
+// guard rejects requests to next from browser origins not in a.origins, so
+// that a web page cannot reach the server through DNS rebinding, and, with
+// a.authToken, requests without it. Requests without an Origin header come
+// from other programs and are only held to the token.
+func (a httpAccess) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if origin := req.Header.Get("Origin"); origin != "" && !a.allowsOrigin(origin) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		if a.authToken == "" {
+			next.ServeHTTP(w, req)
+			return
+		}
+		token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		token = strings.TrimSpace(token)
+		switch {
+		case ok && subtle.ConstantTimeCompare([]byte(token), []byte(a.authToken)) == 1:
+			// The shared secret is not a Todoist token; the request is
+			// served with the configured one.
+			req = req.Clone(req.Context())
+			req.Header.Del("Authorization")
+		case ok && token != "" && a.perRequestTokens:
+			// Todoist checks the client's own token.
+		default:
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "authentication required: send Authorization: Bearer <MCP_AUTH_TOKEN>", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// allowsOrigin reports whether a browser page at origin may call the server:
+// loopback origins and those in a.origins are allowed.
+func (a httpAccess) allowsOrigin(origin string) bool {
+	origin = strings.TrimSuffix(origin, "/")
+	for _, allowed := range a.origins {
+		if strings.EqualFold(origin, strings.TrimSuffix(allowed, "/")) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	if strings.EqualFold(u.Hostname(), "localhost") {
+		return true
+	}
+	ip := net.ParseIP(u.Hostname())
+	return ip != nil && ip.IsLoopback()
+}
+
+// newHTTPHandler serves mcp at the endpoints of transport (/sse and
+// /message, or /mcp) and /healthz for load balancers and reverse proxies.
+func newHTTPHandler(mcp http.Handler, transport string) http.Handler {
+	mux := http.NewServeMux()
+	if transport == config.TransportSSE {
+		mux.Handle("/", mcp)
+	} else {
+		mux.Handle("/mcp", mcp)
+	}
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+// describeTransport names the configured transport for get_server_info,
+// with the URL or pipe clients connect to when it is not stdio.
+func describeTransport(cfg *config.Config) string {
+	switch cfg.Transport {
+	case config.TransportSSE:
+		return "sse http://" + cfg.ListenAddr + "/sse"
+	case config.TransportHTTP:
+		return "http http://" + cfg.ListenAddr + "/mcp"
+	case config.TransportPipe:
+		return "pipe " + cfg.PipeName
+	}
+	return cfg.Transport
+}
+
+// serveHTTP runs the MCP server over transport on addr until the process is
+// interrupted or ctx ends, then shuts down gracefully. Requests must pass
+// access. With tenants, requests bringing their own API token are served by
+// that token's server instead.
+func serveHTTP(ctx context.Context, s *server.MCPServer, transport, addr string, access httpAccess, tenants *tenantRouter, ready func() error) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return serveHTTPListener(ctx, s, transport, ln, access, tenants, ready)
+}
+
+// serveHTTPListener serves on ln until ctx ends. ready, if set, runs once
+// ln is bound and before the first connection is accepted; its error closes
+// ln and is returned. Requests still running at the end then get
+// shutdownTimeout to finish before their connections are closed. It returns
+// nil after a shutdown, so that cleanup runs as for stdio.
+func serveHTTPListener(ctx context.Context, s *server.MCPServer, transport string, ln net.Listener, access httpAccess, tenants *tenantRouter, ready func() error) error {
+	srv := &http.Server{ReadHeaderTimeout: 10 * time.Second}
+	t := newTransport(s, transport, srv)
+	var mcp http.Handler = t
+	if tenants != nil {
+		mcp = tenants.Handler(t)
+	}
+	srv.Handler = newHTTPHandler(access.guard(mcp), transport)
+
+	if ready != nil {
+		if err := ready(); err != nil {
+			_ = ln.Close()
+			return err
+		}
+	}
+
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ln) }()
+	slog.Info("listening", "transport", transport, "addr", ln.Addr().String())
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := t.Shutdown(shutdownCtx); err != nil {
+		// Long-lived streams outlasting the timeout are cut off.
+		slog.Warn("closing connections still open after shutdown timeout", "error", err)
+		_ = srv.Close()
+	}
+	if err := <-served; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
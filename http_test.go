@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-todoist/config"
+)
+
+// startHTTP serves a bare MCP server over transport on a free port and
+// returns its base URL and a function that shuts it down and returns the
+// serve error.
+func startHTTP(t *testing.T, transport string) (string, func() error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := server.NewMCPServer("Todoist Server", "test", server.WithToolCapabilities(true))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveHTTPListener(ctx, s, transport, ln, httpAccess{}, nil, nil) }()
+	stop := func() error {
+		cancel()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(shutdownTimeout + time.Second):
+			t.Fatal("server did not shut down")
+			return nil
+		}
+	}
+	return "http://" + ln.Addr().String(), stop
+}
+
+func TestServeHTTP_ReadyAfterListen(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer busy.Close()
+
+	s := server.NewMCPServer("Todoist Server", "test", server.WithToolCapabilities(true))
+	called := false
+	err = serveHTTP(context.Background(), s, config.TransportHTTP, busy.Addr().String(), httpAccess{}, nil, func() error {
+		called = true
+		return nil
+	})
+	if err == nil || called {
+		t.Errorf("serveHTTP on a port in use = %v, ready called = %v; want an error before ready", err, called)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ready := func() error {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Errorf("listener not bound when ready ran: %v", err)
+			return nil
+		}
+		_ = conn.Close()
+		return errors.New("PID file not writable")
+	}
+	if err := serveHTTPListener(context.Background(), s, config.TransportHTTP, ln, httpAccess{}, nil, ready); err == nil || err.Error() != "PID file not writable" {
+		t.Errorf("serveHTTPListener with failing ready = %v, want its error", err)
+	}
+}
+
+func TestServeHTTP_StreamableHTTP(t *testing.T) {
+	base, stop := startHTTP(t, config.TransportHTTP)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+	req, _ := http.NewRequest(http.MethodPost, base+"/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(data), `"protocolVersion"`) {
+		t.Errorf("initialize = %d %s", resp.StatusCode, data)
+	}
+
+	resp, err = http.Get(base + "/healthz")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("healthz = %v, %v", resp, err)
+	} else {
+		_ = resp.Body.Close()
+	}
+
+	if err := stop(); err != nil {
+		t.Errorf("shutdown error: %v", err)
+	}
+	if _, err := http.Get(base + "/healthz"); err == nil {
+		t.Error("server still accepting connections after shutdown")
+	}
+}
+
+func TestServeHTTP_SSE(t *testing.T) {
+	base, stop := startHTTP(t, config.TransportSSE)
+
+	resp, err := http.Get(base + "/sse")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	reader := bufio.NewReader(resp.Body)
+	var endpoint string
+	for endpoint == "" {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+			endpoint = data
+		}
+	}
+	if !strings.HasPrefix(endpoint, "/message?sessionId=") {
+		t.Errorf("expected a relative message endpoint, got %q", endpoint)
+	}
+
+	// An open stream must not hold up shutdown.
+	if err := stop(); err != nil {
+		t.Errorf("shutdown error: %v", err)
+	}
+}
+
+func TestHTTPAccessGuard(t *testing.T) {
+	const secret = "s3cret-s3cret-s3cret"
+	// next echoes the Authorization header it was given.
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "served:"+r.Header.Get("Authorization"))
+	})
+
+	tests := []struct {
+		name     string
+		access   httpAccess
+		origin   string
+		bearer   string
+		wantCode int
+		wantBody string
+	}{
+		{"no origin", httpAccess{}, "", "", http.StatusOK, "served:"},
+		{"loopback origin", httpAccess{}, "http://localhost:6274", "", http.StatusOK, "served:"},
+		{"loopback IP origin", httpAccess{}, "http://127.0.0.1:3000", "", http.StatusOK, "served:"},
+		{"rebound origin", httpAccess{}, "http://attacker.example", "", http.StatusForbidden, "origin not allowed"},
+		{"allowed origin", httpAccess{origins: []string{"https://mcp.example.com"}}, "https://MCP.example.com", "", http.StatusOK, "served:"},
+		{"null origin", httpAccess{}, "null", "", http.StatusForbidden, "origin not allowed"},
+		{"secret missing", httpAccess{authToken: secret}, "", "", http.StatusUnauthorized, "authentication required"},
+		{"secret wrong", httpAccess{authToken: secret}, "", "guess", http.StatusUnauthorized, "authentication required"},
+		{"secret given", httpAccess{authToken: secret}, "", secret, http.StatusOK, "served:"},
+		{"tenant token with secret", httpAccess{authToken: secret, perRequestTokens: true}, "", tokenA, http.StatusOK, "served:Bearer " + tokenA},
+		{"origin checked before secret", httpAccess{authToken: secret}, "http://attacker.example", secret, http.StatusForbidden, "origin not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("{}"))
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			rec := httptest.NewRecorder()
+			tt.access.guard(next).ServeHTTP(rec, req)
+			body := rec.Body.String()
+			if rec.Code != tt.wantCode || !strings.Contains(body, tt.wantBody) || (rec.Code == http.StatusOK && body != tt.wantBody) {
+				t.Errorf("got %d %q, want %d %q", rec.Code, body, tt.wantCode, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestDescribeTransport(t *testing.T) {
+	tests := map[string]string{
+		config.TransportStdio: "stdio",
+		config.TransportSSE:   "sse http://127.0.0.1:9000/sse",
+		config.TransportHTTP:  "http http://127.0.0.1:9000/mcp",
+		config.TransportPipe:  `pipe \\.\pipe\mcp-todoist`,
+	}
+	for transport, want := range tests {
+		cfg := &config.Config{Transport: transport, ListenAddr: "127.0.0.1:9000", PipeName: `\\.\pipe\mcp-todoist`}
+		if got := describeTransport(cfg); got != want {
+			t.Errorf("describeTransport(%q) = %q, want %q", transport, got, want)
+		}
+	}
+}
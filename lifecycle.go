@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// sdNotify sends a state such as "READY=1" to systemd's notification socket
+// when the service runs with Type=notify. Without NOTIFY_SOCKET it does
+// nothing.
+func sdNotify(state string) error {
+	addr := os.Getenv("NOTIFY_SOCKET")
+	if addr == "" {
+		return nil
+	}
+	if strings.HasPrefix(addr, "@") {
+		// Abstract socket namespace.
+		addr = "\x00" + addr[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("connecting to NOTIFY_SOCKET: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("writing to NOTIFY_SOCKET: %w", err)
+	}
+	return nil
+}
+
+// writePIDFile records the process ID in path. The returned function removes
+// the file, unless another process has since replaced it.
+func writePIDFile(path string) (func(), error) {
+	pid := strconv.Itoa(os.Getpid())
+	if err := os.WriteFile(path, []byte(pid+"\n"), 0o644); err != nil {
+		return nil, fmt.Errorf("writing PID file: %w", err)
+	}
+	return func() {
+		if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) == pid {
+			_ = os.Remove(path)
+		}
+	}, nil
+}
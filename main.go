@@ -4,10 +4,16 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"flag"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -15,11 +21,23 @@ import (
 	"github.com/rgabriel/mcp-todoist/config"
 	"github.com/rgabriel/mcp-todoist/todoist"
 	"github.com/rgabriel/mcp-todoist/tools"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
 )
 
 var version = "dev"
 
-func setupLogger() {
+const (
+	// Todoist allows 450 requests per 15 minutes per token.
+	rateLimitWindow = 15 * time.Minute
+	rateLimitMax    = 450
+	// maxInFlight bounds concurrent API requests across both clients.
+	maxInFlight = 4
+	// referenceCacheTTL is how long projects, labels, and sections are
+	// served from cache for completions and get_context_bundle.
+	referenceCacheTTL = time.Minute
+)
+
+func setupLogger(w io.Writer) {
 	level := slog.LevelInfo
 	switch strings.ToUpper(os.Getenv("LOG_LEVEL")) {
 	case "DEBUG":
@@ -29,7 +47,7 @@ func setupLogger() {
 	case "ERROR":
 		level = slog.LevelError
 	}
-	handler := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level})
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
 	slog.SetDefault(slog.New(handler))
 }
 
@@ -72,6 +90,63 @@ func toolMiddleware(d time.Duration) server.ToolHandlerMiddleware {
 	}
 }
 
+// journalMiddleware records every call to a tool that is not annotated as
+// read-only in the session journal.
+func journalMiddleware(journal *tools.Journal, readOnly func(name string) bool) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if !readOnly(req.Params.Name) {
+				journal.Record(req.Params.Name, req.GetArguments(), result)
+			}
+			return result, err
+		}
+	}
+}
+
+// verbosityMiddleware reduces the entities in every tool result to the
+// configured RESPONSE_VERBOSITY. It runs outside linkMiddleware so that links
+// are still named after the full entities.
+func verbosityMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if err == nil {
+				respond.ApplyVerbosity(result)
+			}
+			return result, err
+		}
+	}
+}
+
+// rateLimitMiddleware adds the REST requests left in the current rate limit
+// window to every successful tool result, so that agents can pace themselves.
+func rateLimitMiddleware(client todoist.API) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if err == nil {
+				respond.AddRateLimit(result, client.GetRemainingRequests())
+			}
+			return result, err
+		}
+	}
+}
+
+// linkMiddleware attaches resource links for the entities touched by every
+// successful call to a tool that is not annotated as read-only.
+func linkMiddleware(readOnly func(name string) bool) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if err == nil && !readOnly(req.Params.Name) {
+				tools.AddEntityLinks(req.Params.Name, req.GetArguments(), result)
+			}
+			return result, err
+		}
+	}
+}
+
 func generateRequestID() string {
 	b := make([]byte, 4)
 	_, _ = rand.Read(b)
@@ -79,37 +154,356 @@ func generateRequestID() string {
 }
 
 func main() {
-	setupLogger()
+	transport := flag.String("transport", "", "how clients connect: stdio, sse, http, or pipe (overrides MCP_TRANSPORT)")
+	listen := flag.String("listen", "", "host:port for the sse and http transports (overrides MCP_LISTEN_ADDR)")
+	pipeUser := flag.String("pipe-user", "", "Windows account, a name or SID, allowed to open the pipe transport (overrides MCP_PIPE_USER)")
+	install := flag.Bool("install-service", false, "install a Windows service that serves the given --transport and --listen, then exit")
+	uninstall := flag.Bool("uninstall-service", false, "remove the Windows service installed by --install-service, then exit")
+	asService := flag.Bool("service", false, "run under the Windows service manager (set by --install-service)")
+	flag.Parse()
+
+	switch {
+	case *install || *uninstall:
+		setupLogger(os.Stderr)
+		var err error
+		if *install {
+			err = installService(*transport, *listen, *pipeUser)
+		} else {
+			err = uninstallService()
+		}
+		if err != nil {
+			slog.Error("service error", "error", err)
+			os.Exit(1)
+		}
+	case *asService:
+		os.Exit(runService(func(ctx context.Context) int { return run(ctx, *transport, *listen, *pipeUser) }))
+	default:
+		setupLogger(os.Stderr)
+		os.Exit(run(context.Background(), *transport, *listen, *pipeUser))
+	}
+}
 
+// run serves MCP clients until ctx ends, the process is interrupted, or the
+// client disconnects, and returns the exit code. transport, listen, and
+// pipeUser override the configured ones when set.
+func run(ctx context.Context, transport, listen, pipeUser string) int {
 	cfg, err := config.Load()
+	if err == nil && (transport != "" || listen != "") {
+		if transport != "" {
+			cfg.Transport = config.NormalizeTransport(transport)
+		}
+		if listen != "" {
+			cfg.ListenAddr = listen
+		}
+		err = cfg.Validate()
+	}
+	if err != nil {
+		slog.Error("configuration error", "error", err)
+		return 1
+	}
+	if pipeUser != "" {
+		cfg.PipeUser = pipeUser
+	}
+	creationPolicies, err := tools.ParseCreationPolicies(cfg.CreationPolicies)
 	if err != nil {
 		slog.Error("configuration error", "error", err)
-		os.Exit(1)
+		return 1
+	}
+
+	// Shared rate limiter and request scheduler for both REST and Sync clients
+	rl := todoist.NewRateLimiter(rateLimitWindow, rateLimitMax)
+	scheduler := todoist.NewScheduler(maxInFlight)
+	todoistClient := todoist.NewClient(cfg.TodoistAPIToken, rl, scheduler)
+	todoistSyncClient := todoist.NewSyncClient(cfg.TodoistAPIToken, rl, scheduler)
+	var dumper *todoist.FixtureDumper
+	if cfg.DebugDumpDir != "" {
+		dumper = todoist.NewFixtureDumper(cfg.DebugDumpDir)
+		slog.Info("writing fixtures of failing API calls", "dir", cfg.DebugDumpDir)
 	}
+	var monitor *todoist.StatusMonitor
+	if cfg.StatusPageURL != "" {
+		monitor = todoist.NewStatusMonitor(cfg.StatusPageURL)
+	}
+	todoistClient.SetFixtureDumper(dumper)
+	todoistSyncClient.SetFixtureDumper(dumper)
+	todoistClient.SetStatusMonitor(monitor)
+	todoistSyncClient.SetStatusMonitor(monitor)
 
-	// Shared rate limiter for both REST and Sync clients
-	rl := todoist.NewRateLimiter(15*time.Minute, 450)
-	todoistClient := todoist.NewClient(cfg.TodoistAPIToken, rl)
-	todoistSyncClient := todoist.NewSyncClient(cfg.TodoistAPIToken, rl)
+	if cfg.DebugAddr != "" {
+		startDebugServer(cfg.DebugAddr, rl, scheduler)
+	}
 
-	ctx := context.Background()
+	if cfg.RateLimitStateFile != "" {
+		if err := rl.LoadState(cfg.RateLimitStateFile); err != nil {
+			slog.Warn("ignoring saved rate limit state", "error", err)
+		}
+		persistCtx, stopPersist := context.WithCancel(ctx)
+		persisted := make(chan struct{})
+		go func() {
+			rl.PersistState(persistCtx, cfg.RateLimitStateFile, 5*time.Second)
+			close(persisted)
+		}()
+		defer func() {
+			stopPersist()
+			<-persisted
+		}()
+	}
+	if src := cfg.TokenSource; src != nil {
+		// Pick up rotated secrets without a restart, until run returns.
+		refreshCtx, stopRefresh := context.WithCancel(ctx)
+		defer stopRefresh()
+		go func() {
+			ticker := time.NewTicker(src.RefreshInterval())
+			defer ticker.Stop()
+			for {
+				select {
+				case <-refreshCtx.Done():
+					return
+				case <-ticker.C:
+				}
+				token, err := src.Token(refreshCtx)
+				if err != nil {
+					slog.Warn("keeping current API token", "error", err)
+					continue
+				}
+				todoistClient.SetToken(token)
+				todoistSyncClient.SetToken(token)
+			}
+		}()
+	}
 	if err := todoistClient.TestConnection(ctx); err != nil {
 		slog.Error("failed to connect to Todoist API", "error", err)
-		os.Exit(1)
+		return 1
+	}
+
+	if err := tools.SetOutputLanguage(cfg.OutputLang); err != nil {
+		slog.Error("configuration error", "error", fmt.Errorf("OUTPUT_LANG: %w", err))
+		return 1
+	}
+	if err := respond.SetVerbosity(cfg.ResponseVerbosity); err != nil {
+		slog.Error("configuration error", "error", fmt.Errorf("RESPONSE_VERBOSITY: %w", err))
+		return 1
+	}
+	if cfg.BulkSyncThreshold > 0 {
+		todoist.SetSyncBatchThreshold(cfg.BulkSyncThreshold)
+	}
+	opts := serverOptions{
+		creationPolicies: creationPolicies,
+		taskDefaults:     tools.TaskDefaults{ProjectID: cfg.DefaultProjectID, Labels: cfg.DefaultLabels, Priority: cfg.DefaultPriority, DueString: cfg.DefaultDue},
+		sprint:           tools.SprintOptions{Template: cfg.SprintTemplate, Label: cfg.SprintLabel, Weeks: cfg.SprintWeeks},
+		atRisk:           tools.AtRiskOptions{Days: cfg.AtRiskDays, Label: cfg.AtRiskLabel, Comment: cfg.AtRiskComment},
+		templatesDir:     cfg.TemplatesDir,
+		backupDir:        cfg.BackupDir,
+		disabledGroups:   cfg.DisabledToolGroups,
+	}
+	ts, err := newServer(cfg, opts, todoistClient, todoistSyncClient)
+	if err != nil {
+		slog.Error("configuration error", "error", err)
+		return 1
+	}
+	s, groups := ts.mcp, ts.groups
+
+	var tenants *tenantRouter
+	if cfg.PerRequestTokens == config.TokensOptional || cfg.PerRequestTokens == config.TokensRequired {
+		// Each token gets its own rate limit budget, stores, and template
+		// and backup directories; only the configured token runs automations.
+		tenants = newTenantRouter(cfg.PerRequestTokens, cfg.DisabledToolGroups, func(ctx context.Context, token string, disabled []string) (http.Handler, *tools.ToolGroups, error) {
+			limiter := todoist.NewRateLimiter(rateLimitWindow, rateLimitMax)
+			sched := todoist.NewScheduler(maxInFlight)
+			client := todoist.NewClient(token, limiter, sched)
+			syncClient := todoist.NewSyncClient(token, limiter, sched)
+			client.SetFixtureDumper(dumper)
+			syncClient.SetFixtureDumper(dumper)
+			client.SetStatusMonitor(monitor)
+			syncClient.SetStatusMonitor(monitor)
+			if err := client.TestConnection(ctx); err != nil {
+				return nil, nil, err
+			}
+			key := tenantKey(token)
+			tenantOpts := opts
+			tenantOpts.templatesDir = filepath.Join(cfg.TemplatesDir, "tenants", key)
+			tenantOpts.backupDir = filepath.Join(cfg.BackupDir, "tenants", key)
+			tenantOpts.disabledGroups = disabled
+			tenant, err := newServer(cfg, tenantOpts, client, syncClient)
+			if err != nil {
+				return nil, nil, err
+			}
+			return newTransport(tenant.mcp, cfg.Transport, nil), tenant.groups, nil
+		})
+	}
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			disabled := config.ReloadDisabledToolGroups()
+			if err := groups.Apply(disabled); err != nil {
+				slog.Warn("ignoring tool group reload", "error", err)
+				continue
+			}
+			if tenants != nil {
+				if err := tenants.ApplyDisabledGroups(disabled); err != nil {
+					slog.Warn("ignoring tool group reload for per-request tokens", "error", err)
+					continue
+				}
+			}
+			slog.Info("tool groups reloaded", "disabled", disabled)
+		}
+	}()
+
+	slog.Info("server starting",
+		"version", version,
+		"tools", len(s.ListTools()),
+		"profile", ts.profile.Name,
+		"transport", cfg.Transport,
+		"per_request_tokens", cfg.PerRequestTokens,
+		"auth_token", cfg.AuthToken != "",
+		"rate_limit", "450/15min",
+	)
+
+	var automations []tools.Automation
+	if cfg.WeeklySnapshotProjectID != "" {
+		automations = append(automations, tools.NewWeeklySnapshot(todoistClient, todoistSyncClient, cfg.WeeklySnapshotProjectID).Automation())
+	}
+	if opts.sprint.Weeks > 0 {
+		automations = append(automations, tools.SprintAutomation(todoistClient, todoistSyncClient, ts.templates, opts.sprint))
+	}
+	if opts.atRisk.Days > 0 {
+		automations = append(automations, tools.AtRiskAutomation(todoistClient, todoistSyncClient, opts.atRisk))
+	}
+	automationCtx, stopAutomations := context.WithCancel(ctx)
+	automationsDone := make(chan struct{})
+	go func() {
+		tools.RunAutomations(automationCtx, automations...)
+		close(automationsDone)
+	}()
+	defer func() {
+		stopAutomations()
+		<-automationsDone
+	}()
+
+	// The PID file and systemd's READY=1 say the server can take
+	// connections, so for sse, http, and pipe they wait until the listener
+	// is bound.
+	removePIDFile := func() {}
+	defer func() { removePIDFile() }()
+	ready := func() error {
+		if cfg.PIDFile != "" {
+			remove, err := writePIDFile(cfg.PIDFile)
+			if err != nil {
+				return err
+			}
+			removePIDFile = remove
+		}
+		if err := sdNotify("READY=1"); err != nil {
+			slog.Warn("failed to notify systemd", "error", err)
+		}
+		return nil
+	}
+
+	switch cfg.Transport {
+	case config.TransportStdio:
+		if err = ready(); err == nil {
+			err = serveStdio(ctx, s, tools.NewCompleter(ts.refs))
+		}
+	case config.TransportPipe:
+		err = servePipe(ctx, s, tools.NewCompleter(ts.refs), cfg.PipeName, cfg.PipeUser, ready)
+	default:
+		access := httpAccess{authToken: cfg.AuthToken, origins: cfg.AllowedOrigins, perRequestTokens: tenants != nil}
+		err = serveHTTP(ctx, s, cfg.Transport, cfg.ListenAddr, access, tenants, ready)
+	}
+	_ = sdNotify("STOPPING=1")
+	if err != nil {
+		slog.Error("server error", "error", err)
+		return 1
+	}
+	slog.Info("server stopped")
+	return 0
+}
+
+// toolServer is the MCP server for one API token, with its tool groups and
+// the stores its tools keep between calls.
+type toolServer struct {
+	mcp       *server.MCPServer
+	groups    *tools.ToolGroups
+	templates *tools.TemplateStore
+	refs      *tools.ReferenceCache
+	profile   tools.Profile
+}
+
+// serverOptions are the settings a toolServer is built with, derived from
+// the configuration.
+type serverOptions struct {
+	creationPolicies tools.CreationPolicies
+	taskDefaults     tools.TaskDefaults
+	sprint           tools.SprintOptions
+	atRisk           tools.AtRiskOptions
+	// templatesDir and backupDir hold the token's templates and project
+	// backups.
+	templatesDir string
+	backupDir    string
+	// disabledGroups are the tool groups hidden when the server is built.
+	disabledGroups []string
+}
+
+// newServer builds the MCP server and registers every tool, calling the
+// Todoist API through the given clients.
+func newServer(cfg *config.Config, opts serverOptions, todoistClient *todoist.Client, todoistSyncClient *todoist.SyncClient) (*toolServer, error) {
+	journal := tools.NewJournal(200)
+	planStore := tools.NewPlanStore(10 * time.Minute)
+	confirmations := tools.NewConfirmationStore(5 * time.Minute)
+	operations := tools.NewOperationStore(time.Hour)
+	focusSessions := tools.NewFocusStore(24 * time.Hour)
+	templateStore := tools.NewTemplateStore(opts.templatesDir)
+	refs := tools.NewReferenceCache(todoistClient, referenceCacheTTL)
+	backupStore := tools.NewBackupStore(opts.backupDir, cfg.BackupBeforeDelete)
+	sprintOptions, taskDefaults, atRiskOptions := opts.sprint, opts.taskDefaults, opts.atRisk
+	creationPolicies := opts.creationPolicies
+	projectClassifier := tools.NewProjectClassifier(todoistSyncClient, time.Hour)
+
+	var s *server.MCPServer
+	toolNames := func() []string {
+		registered := s.ListTools()
+		names := make([]string, 0, len(registered))
+		for name := range registered {
+			names = append(names, name)
+		}
+		return names
+	}
+	isReadOnly := func(name string) bool {
+		tool := s.GetTool(name)
+		return tool != nil && tool.Tool.Annotations.ReadOnlyHint != nil && *tool.Tool.Annotations.ReadOnlyHint
 	}
 
-	s := server.NewMCPServer(
+	s = server.NewMCPServer(
 		"Todoist Server",
 		version,
-		server.WithToolCapabilities(false),
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
 		server.WithRecovery(),
 		server.WithToolHandlerMiddleware(toolMiddleware(30*time.Second)),
+		server.WithToolHandlerMiddleware(journalMiddleware(journal, isReadOnly)),
+		server.WithToolHandlerMiddleware(verbosityMiddleware()),
+		server.WithToolHandlerMiddleware(rateLimitMiddleware(todoistClient)),
+		server.WithToolHandlerMiddleware(linkMiddleware(isReadOnly)),
 	)
+	groups := tools.NewToolGroups(s)
+	profile, err := tools.LookupProfile(cfg.ToolProfile)
+	if err != nil {
+		return nil, fmt.Errorf("TOOL_PROFILE: %w", err)
+	}
+	groups.SetProfile(profile)
+	if err := tools.ValidateScopes(cfg.TokenScopes); err != nil {
+		return nil, fmt.Errorf("TODOIST_TOKEN_SCOPES: %w", err)
+	}
+	groups.SetScopes(cfg.TokenScopes)
+	scopeGuard := tools.NewScopeGuard(s, time.Hour)
+	server.WithToolHandlerMiddleware(scopeGuard.Middleware())(s)
 
 	// ── Task tools ──────────────────────────────────────────────────────
 
-	s.AddTool(mcp.NewTool("search_tasks",
-		mcp.WithDescription("Search and list active tasks. Supports Todoist filter syntax, project filtering, label filtering, and fetching by IDs. Returns an array of task objects with id, content, description, project_id, priority, due, labels, and url. Use list_projects first to get valid project_id values."),
+	groups.Add("tasks", mcp.NewTool("search_tasks",
+		mcp.WithDescription("Search and list active tasks. Supports Todoist filter syntax, project filtering, label filtering, creation date and deadline ranges, and fetching by IDs. Returns an array of task objects with id, content, description, project_id, priority, due, labels, url, and is_timed (true when the task is due at a time of day rather than all day). Use list_projects first to get valid project_id values."),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(true),
@@ -119,16 +513,67 @@ func main() {
 		mcp.WithString("project_id",
 			mcp.Description("Filter tasks by project ID. Use list_projects to discover valid IDs."),
 		),
+		mcp.WithBoolean("include_subprojects",
+			mcp.Description("With project_id, also include tasks from all projects nested under it."),
+			mcp.DefaultBool(false),
+		),
 		mcp.WithString("label",
 			mcp.Description("Filter tasks by label name. Use list_labels to discover valid names."),
 		),
 		mcp.WithArray("ids",
 			mcp.Description("Fetch specific tasks by their IDs."),
 		),
-	), tools.SearchTasksHandler(todoistClient))
+		mcp.WithString("created_after",
+			mcp.Description("Only return tasks created at or after this date (YYYY-MM-DD) or timestamp (RFC 3339)."),
+		),
+		mcp.WithString("created_before",
+			mcp.Description("Only return tasks created before this date (YYYY-MM-DD) or timestamp (RFC 3339)."),
+		),
+		mcp.WithString("deadline_from",
+			mcp.Description("Only return tasks with a deadline on or after this date (YYYY-MM-DD). Tasks without a deadline are excluded."),
+		),
+		mcp.WithString("deadline_to",
+			mcp.Description("Only return tasks with a deadline on or before this date (YYYY-MM-DD). Tasks without a deadline are excluded."),
+		),
+		mcp.WithBoolean("added_by_me",
+			mcp.Description("Only return tasks created by the token owner (useful in shared projects)."),
+		),
+		mcp.WithBoolean("group_by_day",
+			mcp.Description("Also return days: tasks grouped by due date in calendar order, each with all_day tasks and timed tasks sorted chronologically. Undated tasks form a final group with a null date."),
+		),
+		mcp.WithBoolean("include_completed",
+			mcp.Description("Also search tasks completed in the last 90 days. Completed matches are marked with completed: true."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("hide_deferred",
+			mcp.Description("Leave out tasks whose defer date (set with set_defer_date) is after today in the user's time zone, and report how many were hidden as hidden_deferred."),
+			mcp.DefaultBool(false),
+		),
+	), tools.SearchTasksHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("tasks", mcp.NewTool("search_all",
+		mcp.WithDescription("Search tasks, projects, sections, labels, and comments for a text query in one call (case-insensitive substring match on names, content, and descriptions). Returns typed matches with IDs and project names, useful for resolving vague references like 'the thing about taxes'. Only active tasks are searched."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Text to look for."),
+		),
+		mcp.WithArray("types",
+			mcp.Description("Entity types to search: 'task', 'project', 'section', 'label', 'comment'. Defaults to all."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum matches per entity type (1-50)."),
+			mcp.Min(1),
+			mcp.Max(50),
+			mcp.DefaultNumber(10),
+		),
+	), tools.SearchAllHandler(todoistClient, todoistSyncClient))
 
-	s.AddTool(mcp.NewTool("get_task",
-		mcp.WithDescription("Get a single task by ID with full details including content, description, project_id, section_id, priority (1-4), labels, due date, assignee, duration, and URL."),
+	groups.Add("tasks", mcp.NewTool("get_task",
+		mcp.WithDescription("Get a single task by ID with full details including content, description, project_id, section_id, priority (1-4), labels, due date, assignee, duration, and URL. Set include_context to also get the parent task chain and project/section breadcrumb."),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(true),
@@ -137,10 +582,26 @@ func main() {
 			mcp.MinLength(1),
 			mcp.Description("Task ID to retrieve. Use search_tasks to find task IDs."),
 		),
-	), tools.GetTaskHandler(todoistClient))
+		mcp.WithBoolean("include_context",
+			mcp.Description("Also resolve parent_chain (ancestor tasks, root first) and breadcrumb (e.g., 'Work > Clients > Backlog', prefixed with the workspace name for team workspace projects) describing where the task lives. Costs extra API requests."),
+			mcp.DefaultBool(false),
+		),
+	), tools.GetTaskHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("tasks", mcp.NewTool("get_tasks",
+		mcp.WithDescription("Get many tasks by ID in as few API requests as possible, instead of calling get_task in a loop. Returns tasks keyed by ID; IDs without an active task (completed, deleted, or inaccessible) map to {\"not_found\": true} and are listed in not_found."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithArray("task_ids",
+			mcp.Required(),
+			mcp.Description("Task IDs to retrieve, at most 500."),
+			mcp.WithStringItems(),
+		),
+	), tools.GetTasksHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("create_task",
-		mcp.WithDescription("Create a new task. Returns the created task object with its assigned ID. Use list_projects and list_sections to get valid project_id/section_id values. Priority uses Todoist's internal scale: 1=normal, 4=urgent."),
+	groups.Add("tasks", mcp.NewTool("create_task",
+		mcp.WithDescription("Create a new task. Returns the created task object with its assigned ID. Use list_projects and list_sections to get valid project_id/section_id values. Priority uses Todoist's internal scale: 1=normal, 4=urgent. Server-configured defaults fill in an omitted project, labels, priority, or due date and are listed under applied_defaults. A project's creation policy may reject the task or add a missing due date or label, listed under policy_fixes."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(false),
 		mcp.WithOpenWorldHintAnnotation(true),
@@ -198,9 +659,9 @@ func main() {
 			mcp.Description("Deadline date in YYYY-MM-DD format."),
 			mcp.Pattern(`^\d{4}-\d{2}-\d{2}$`),
 		),
-	), tools.CreateTaskHandler(todoistClient))
+	), tools.CreateTaskHandler(todoistClient, taskDefaults, creationPolicies))
 
-	s.AddTool(mcp.NewTool("update_task",
+	groups.Add("tasks", mcp.NewTool("update_task",
 		mcp.WithDescription("Update an existing task. Only provided fields are changed; omitted fields keep their current values. Returns the updated task object."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
@@ -217,7 +678,11 @@ func main() {
 			mcp.Description("New task description (supports markdown)."),
 		),
 		mcp.WithArray("labels",
-			mcp.Description("New array of label names (replaces existing labels)."),
+			mcp.Description("Label names. How they apply depends on merge_strategy."),
+		),
+		mcp.WithString("merge_strategy",
+			mcp.Description("How labels combine with the task's current labels, read just before the update: replace (default) sets exactly these labels, union adds them, subtract removes them. Use union or subtract to avoid dropping labels you did not know about."),
+			mcp.Enum("replace", "union", "subtract"),
 		),
 		mcp.WithNumber("priority",
 			mcp.Description("New priority: 1 (normal) to 4 (urgent)."),
@@ -249,9 +714,12 @@ func main() {
 			mcp.Description("New deadline date in YYYY-MM-DD format."),
 			mcp.Pattern(`^\d{4}-\d{2}-\d{2}$`),
 		),
+		mcp.WithBoolean("skip_if_unchanged",
+			mcp.Description("Read the task first and skip the write if every requested value already matches, returning the task with changed: false. A due_string always counts as a change, since phrases like \"tomorrow\" resolve to a new date each day. With this set, the response always includes changed."),
+		),
 	), tools.UpdateTaskHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("complete_task",
+	groups.Add("tasks", mcp.NewTool("complete_task",
 		mcp.WithDescription("Mark a task as completed. For recurring tasks, this advances to the next occurrence. Returns success confirmation with the task_id."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
@@ -261,22 +729,30 @@ func main() {
 			mcp.MinLength(1),
 			mcp.Description("Task ID to complete. Use search_tasks to find task IDs."),
 		),
-	), tools.CompleteTaskHandler(todoistClient))
+		mcp.WithString("completed_at",
+			mcp.Description("When the task was actually done (RFC 3339 timestamp or YYYY-MM-DD), to backdate the completion in stats and karma. Not supported for recurring tasks."),
+		),
+		mcp.WithString("subtask_policy",
+			mcp.Description("What to do with open subtasks: ignore leaves them as they are (default), complete_all completes them with the task through the Sync API, require_complete refuses while any remain open."),
+			mcp.Enum("ignore", "complete_all", "require_complete"),
+		),
+	), tools.CompleteTaskHandler(todoistClient, todoistSyncClient))
 
-	s.AddTool(mcp.NewTool("uncomplete_task",
-		mcp.WithDescription("Reopen a previously completed task. Returns success confirmation with the task_id."),
+	groups.Add("tasks", mcp.NewTool("uncomplete_task",
+		mcp.WithDescription("Reopen a previously completed task, by task_id or by a completed_item_id returned from search_completed. Returns success confirmation with the task_id."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(true),
 		mcp.WithString("task_id",
-			mcp.Required(),
-			mcp.MinLength(1),
 			mcp.Description("Task ID to reopen."),
 		),
-	), tools.UncompleteTaskHandler(todoistClient))
+		mcp.WithString("completed_item_id",
+			mcp.Description("Completed-archive item ID from search_completed, used when task_id is not given. Must have been completed in the last 90 days."),
+		),
+	), tools.UncompleteTaskHandler(todoistClient, todoistSyncClient))
 
-	s.AddTool(mcp.NewTool("delete_task",
-		mcp.WithDescription("Permanently delete a task. This cannot be undone. Use complete_task instead if you want to mark it done. Returns success confirmation."),
+	groups.Add("tasks", mcp.NewTool("delete_task",
+		mcp.WithDescription("Permanently delete a task. This cannot be undone. Use complete_task instead if you want to mark it done. Subtasks are deleted with their parent, so a task with subtasks needs force. Returns success confirmation."),
 		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(true),
@@ -285,10 +761,13 @@ func main() {
 			mcp.MinLength(1),
 			mcp.Description("Task ID to delete. Use search_tasks to find task IDs."),
 		),
+		mcp.WithBoolean("force",
+			mcp.Description("Delete the task even though it has subtasks, which Todoist deletes along with it. Without it, a task with subtasks is not deleted and the error lists them."),
+		),
 	), tools.DeleteTaskHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("quick_add_task",
-		mcp.WithDescription("Quick-add a task using Todoist inline syntax. Parses #project, @label, p1-p4 priority, and date keywords from the content string. Example: 'Buy milk #Shopping @groceries p1 tomorrow'. Returns the created task."),
+	groups.Add("tasks", mcp.NewTool("quick_add_task",
+		mcp.WithDescription("Quick-add a task using Todoist inline syntax. Parses #project, @label, p1-p4 priority, and date keywords from the content string. Example: 'Buy milk #Shopping @groceries p1 tomorrow'. Server-configured defaults fill in what the content leaves out and are listed under applied_defaults. Returns the created task."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(false),
 		mcp.WithOpenWorldHintAnnotation(true),
@@ -297,17 +776,65 @@ func main() {
 			mcp.MinLength(1),
 			mcp.Description("Task content with inline syntax: #ProjectName @label p1-p4 and date keywords."),
 		),
-	), tools.QuickAddTaskHandler(todoistClient))
+	), tools.QuickAddTaskHandler(todoistClient, taskDefaults, creationPolicies))
+
+	groups.Add("tasks", mcp.NewTool("process_inbox",
+		mcp.WithDescription("Work through the Inbox in two calls per page. Without decisions, returns a page of Inbox tasks, oldest first, each with suggested actions (move to a project or add labels its content names, schedule, delete if stale), plus next_cursor. With decisions, applies them in one Sync batch: per task a project_id, due_string or due_date, and labels, or delete or complete. Returns counts per change, failed tasks, and how many tasks are left in the Inbox."),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithNumber("page_size",
+			mcp.Description("Tasks per page, 1-50 (default: 10)."),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("next_cursor from the previous page."),
+		),
+		mcp.WithArray("decisions",
+			mcp.Description("Decisions to apply, at most 100. Each is {task_id, project_id?, due_string?, due_date?, labels?} or {task_id, delete: true} or {task_id, complete: true}. labels replaces the task's labels. Tasks must be in the Inbox."),
+		),
+	), tools.ProcessInboxHandler(todoistClient, todoistSyncClient, projectClassifier))
+
+	groups.Add("tasks", mcp.NewTool("suggest_project",
+		mcp.WithDescription("Suggest the project a task belongs in, from the words of the tasks already in each project. The model is trained on your active tasks outside the Inbox and refreshed hourly. Pass the content of a task you are about to create, or the task_id of an existing (e.g. Inbox) task. Returns projects with a confidence between 0 and 1 and the matching keywords, best first; empty when no word is known."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("content",
+			mcp.Description("Task content to classify."),
+		),
+		mcp.WithString("task_id",
+			mcp.Description("Existing task to classify. Overrides content."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum suggestions, 1-10 (default: 3)."),
+		),
+	), tools.SuggestProjectHandler(todoistClient, projectClassifier))
 
-	s.AddTool(mcp.NewTool("get_task_stats",
-		mcp.WithDescription("Get aggregate statistics about all active tasks. Returns total_active count, today count, overdue count, breakdown by_priority (p1-p4), and breakdown by_project (project name to count)."),
+	groups.Add("tasks", mcp.NewTool("get_task_stats",
+		mcp.WithDescription("Get aggregate statistics about all active tasks. Returns total_active count, today count, overdue count, breakdown by_priority (p1-p4), breakdown by_project (project name to count), and deadline stats: deadlines_overdue, deadlines_approaching (deadline within deadline_days), and due_after_deadline (tasks scheduled after their deadline). Deadlines are tracked separately from due dates."),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithNumber("deadline_days",
+			mcp.Description("Days ahead a deadline counts as approaching (default 7)."),
+			mcp.Min(0),
+			mcp.Max(365),
+		),
 	), tools.GetTaskStatsHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("bulk_complete_tasks",
-		mcp.WithDescription("Complete multiple tasks at once by IDs or filter. Uses Sync API batching for >5 tasks (single request) or REST API for <=5 tasks. Returns completed/failed counts and used_batching flag."),
+	groups.Add("tasks", mcp.NewTool("get_task_history",
+		mcp.WithDescription("Get the activity history of a task in chronological order: creation, content and due date changes, completions, reopenings, and comments. Each event has subject (task or comment), event_type, event_date, initiator_id, and details. Useful for auditing what happened to a task."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("task_id",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Task ID to get history for. Works for active and completed tasks."),
+		),
+	), tools.TaskHistoryHandler(todoistSyncClient))
+
+	groups.Add("tasks", mcp.NewTool("bulk_complete_tasks",
+		mcp.WithDescription("Complete multiple tasks at once by IDs or filter. When selecting by filter, the first call only returns a preview of the matched tasks and a confirmation_token (valid 5 minutes); call again with the same filter and the token to complete exactly the previewed tasks. Uses Sync API batching for >5 tasks (single request) or when REST rate limit headroom is low, otherwise REST. Returns completed/failed counts and used_batching flag."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(true),
@@ -317,10 +844,29 @@ func main() {
 		mcp.WithString("filter",
 			mcp.Description("Todoist filter to select tasks to complete (e.g., 'today & p1')."),
 		),
-	), tools.BulkCompleteTasksHandler(todoistClient, todoistSyncClient))
+		mcp.WithString("confirmation_token",
+			mcp.Description("Token from a previous filter preview. Required to act on a filter."),
+		),
+		mcp.WithString("completed_at",
+			mcp.Description("When the tasks were actually done (RFC 3339 timestamp or YYYY-MM-DD), to backdate the completions in stats and karma. Always uses the Sync API. Recurring tasks are skipped and reported as failed."),
+		),
+		mcp.WithBoolean("atomic",
+			mcp.Description("All or nothing: if any task fails to complete, reopen the ones that were completed (moving recurring tasks back to their previous date) and return an error (default: false, report partial results). Not combinable with completed_at for recurring tasks."),
+		),
+		mcp.WithBoolean("estimate_cost",
+			mcp.Description("Only report how many REST and Sync requests the change would use versus the remaining rate limit budget; nothing is changed and no confirmation is needed."),
+		),
+		mcp.WithString("strategy",
+			mcp.Description("API to send the change through: auto (default) uses REST for small batches and the Sync API above BULK_SYNC_THRESHOLD tasks; rest or sync forces one, for tokens or workspaces that misbehave under the other. Fails when some change has no form in the forced API (moves and Sync-only updates cannot use rest)."),
+			mcp.Enum("auto", "rest", "sync"),
+		),
+		mcp.WithBoolean("background",
+			mcp.Description("Return an operation_id at once and run the change in the background, for jobs too large to finish within the client's tool timeout. Poll get_operation_status for progress and the result; cancel_operation stops it."),
+		),
+	), operations.Background("bulk_complete_tasks", tools.BulkCompleteTasksHandler(todoistClient, todoistSyncClient, confirmations)))
 
-	s.AddTool(mcp.NewTool("batch_create_tasks",
-		mcp.WithDescription("Create multiple tasks in a single Sync API request. Supports parent-child relationships via parent_temp_id (use array index of parent task). Returns created_tasks with real IDs and temp_id_mapping."),
+	groups.Add("tasks", mcp.NewTool("batch_create_tasks",
+		mcp.WithDescription("Create multiple tasks through the Sync API, 100 per request. Supports parent-child relationships via parent_temp_id (use array index of parent task). Returns created_tasks with real IDs and temp_id_mapping."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(false),
 		mcp.WithOpenWorldHintAnnotation(true),
@@ -328,10 +874,16 @@ func main() {
 			mcp.Required(),
 			mcp.Description("Array of task objects. Each must have 'content' (string). Optional: description, project_id, section_id, labels, priority (1-4), due_string, due_date, parent_id, parent_temp_id (index of parent in this array)."),
 		),
-	), tools.BatchCreateTasksHandler(todoistSyncClient))
+		mcp.WithBoolean("atomic",
+			mcp.Description("All or nothing: if any task fails to be created, delete the tasks this batch created and return an error, so no half-created hierarchy is left behind (default: false, report partial results)."),
+		),
+		mcp.WithBoolean("background",
+			mcp.Description("Return an operation_id at once and run the change in the background, for jobs too large to finish within the client's tool timeout. Poll get_operation_status for progress and the result; cancel_operation stops it."),
+		),
+	), operations.Background("batch_create_tasks", tools.BatchCreateTasksHandler(todoistClient, todoistSyncClient, creationPolicies)))
 
-	s.AddTool(mcp.NewTool("move_tasks",
-		mcp.WithDescription("Move multiple tasks to a different project. Uses Sync API batching for >5 tasks. Provide either task_ids or a filter to select tasks. Returns moved/failed counts and destination project name."),
+	groups.Add("tasks", mcp.NewTool("move_tasks",
+		mcp.WithDescription("Move multiple tasks to a different project in a single Sync API batch. Provide either task_ids or a filter to select tasks. A filter first returns a preview and confirmation_token (valid 5 minutes); call again with the same filter, to_project_id, and token to move exactly the previewed tasks. Returns moved/failed counts and destination project name."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(true),
@@ -341,113 +893,490 @@ func main() {
 		mcp.WithString("filter",
 			mcp.Description("Todoist filter to select tasks to move."),
 		),
+		mcp.WithString("confirmation_token",
+			mcp.Description("Token from a previous filter preview. Required to act on a filter."),
+		),
 		mcp.WithString("to_project_id",
 			mcp.Required(),
 			mcp.MinLength(1),
 			mcp.Description("Destination project ID. Use list_projects to find valid IDs."),
 		),
-	), tools.MoveTasksHandler(todoistClient, todoistSyncClient))
-
-	// ── Project tools ───────────────────────────────────────────────────
+		mcp.WithBoolean("preserve_sections",
+			mcp.Description("Place each task in the destination section with the same name as its current section, creating missing sections. Tasks without a section go to the project root (default: false, all tasks go to the root)."),
+		),
+		mcp.WithBoolean("estimate_cost",
+			mcp.Description("Only report how many REST and Sync requests the change would use versus the remaining rate limit budget; nothing is changed and no confirmation is needed."),
+		),
+		mcp.WithString("strategy",
+			mcp.Description("API to send the change through: auto (default) uses REST for small batches and the Sync API above BULK_SYNC_THRESHOLD tasks; rest or sync forces one, for tokens or workspaces that misbehave under the other. Fails when some change has no form in the forced API (moves and Sync-only updates cannot use rest)."),
+			mcp.Enum("auto", "rest", "sync"),
+		),
+		mcp.WithBoolean("atomic",
+			mcp.Description("All or nothing: if any task fails to move, move the moved ones back to their previous parent, section, or project and return an error (default: false, report partial results). Sections created by preserve_sections are kept."),
+		),
+		mcp.WithBoolean("background",
+			mcp.Description("Return an operation_id at once and run the change in the background, for jobs too large to finish within the client's tool timeout. Poll get_operation_status for progress and the result; cancel_operation stops it."),
+		),
+	), operations.Background("move_tasks", tools.MoveTasksHandler(todoistClient, todoistSyncClient, confirmations)))
 
-	s.AddTool(mcp.NewTool("list_projects",
-		mcp.WithDescription("List all projects. Returns each project's id, name, color, parent_id, order, is_favorite, is_inbox_project, is_team_inbox, and view_style. Use the id field as project_id in other tools."),
-		mcp.WithReadOnlyHintAnnotation(true),
+	groups.Add("tasks", mcp.NewTool("bulk_delete_tasks",
+		mcp.WithDescription("Permanently delete multiple tasks (max 100), using one Sync API batch for more than 5 tasks. Provide task_ids, or a filter: a filter first returns a preview of the matched tasks and a confirmation_token (valid 5 minutes), and only the follow-up call with the same filter and token deletes exactly the previewed tasks. This cannot be undone."),
+		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(true),
-	), tools.ListProjectsHandler(todoistClient))
-
-	s.AddTool(mcp.NewTool("create_project",
-		mcp.WithDescription("Create a new project. Returns the created project object with its assigned ID."),
-		mcp.WithDestructiveHintAnnotation(false),
-		mcp.WithIdempotentHintAnnotation(false),
-		mcp.WithOpenWorldHintAnnotation(true),
-		mcp.WithString("name",
-			mcp.Required(),
-			mcp.MinLength(1),
-			mcp.Description("Project name."),
+		mcp.WithArray("task_ids",
+			mcp.Description("Array of task IDs to delete. Overrides filter if both provided."),
 		),
-		mcp.WithString("parent_id",
-			mcp.Description("Parent project ID for creating sub-projects."),
+		mcp.WithString("filter",
+			mcp.Description("Todoist filter to select tasks to delete."),
 		),
-		mcp.WithString("color",
-			mcp.Description("Project color."),
-			mcp.Enum("berry_red", "red", "orange", "yellow", "olive_green", "lime_green", "green", "mint_green", "teal", "sky_blue", "light_blue", "blue", "grape", "violet", "lavender", "magenta", "salmon", "charcoal", "grey", "taupe"),
+		mcp.WithString("confirmation_token",
+			mcp.Description("Token from a previous filter preview. Required to act on a filter."),
 		),
-		mcp.WithBoolean("is_favorite",
-			mcp.Description("Whether project is a favorite."),
-			mcp.DefaultBool(false),
+		mcp.WithBoolean("estimate_cost",
+			mcp.Description("Only report how many REST and Sync requests the change would use versus the remaining rate limit budget; nothing is changed and no confirmation is needed."),
 		),
-		mcp.WithString("view_style",
-			mcp.Description("Project view style."),
-			mcp.Enum("list", "board"),
-			mcp.DefaultString("list"),
+		mcp.WithString("strategy",
+			mcp.Description("API to send the change through: auto (default) uses REST for small batches and the Sync API above BULK_SYNC_THRESHOLD tasks; rest or sync forces one, for tokens or workspaces that misbehave under the other. Fails when some change has no form in the forced API (moves and Sync-only updates cannot use rest)."),
+			mcp.Enum("auto", "rest", "sync"),
 		),
-	), tools.CreateProjectHandler(todoistClient))
+		mcp.WithBoolean("background",
+			mcp.Description("Return an operation_id at once and run the change in the background, for jobs too large to finish within the client's tool timeout. Poll get_operation_status for progress and the result; cancel_operation stops it."),
+		),
+	), operations.Background("bulk_delete_tasks", tools.BulkDeleteTasksHandler(todoistClient, todoistSyncClient, confirmations)))
 
-	s.AddTool(mcp.NewTool("get_project",
-		mcp.WithDescription("Get a single project by ID with full details including name, color, parent_id, order, is_favorite, and view_style."),
-		mcp.WithReadOnlyHintAnnotation(true),
+	groups.Add("tasks", mcp.NewTool("reassign_tasks",
+		mcp.WithDescription("Reassign active tasks in a shared project from one collaborator to another, e.g. while someone is on leave. Without task_ids or filter, every task in the project assigned to from_assignee_id is reassigned. A filter first returns a preview and confirmation_token (valid 5 minutes). Selected tasks that are in another project or assigned to someone else are skipped. Returns a per-task result with status reassigned, skipped, or failed."),
+		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(true),
 		mcp.WithString("project_id",
 			mcp.Required(),
 			mcp.MinLength(1),
-			mcp.Description("Project ID to retrieve. Use list_projects to find IDs."),
+			mcp.Description("Shared project ID."),
 		),
-	), tools.GetProjectHandler(todoistClient))
-
-	s.AddTool(mcp.NewTool("update_project",
-		mcp.WithDescription("Update an existing project. Only provided fields are changed. Returns the updated project object."),
-		mcp.WithDestructiveHintAnnotation(false),
-		mcp.WithIdempotentHintAnnotation(true),
-		mcp.WithOpenWorldHintAnnotation(true),
-		mcp.WithString("project_id",
+		mcp.WithString("from_assignee_id",
 			mcp.Required(),
 			mcp.MinLength(1),
-			mcp.Description("Project ID to update."),
+			mcp.Description("User ID of the current assignee."),
 		),
-		mcp.WithString("name",
-			mcp.Description("New project name."),
+		mcp.WithString("to_assignee_id",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("User ID of the new assignee. Must be a collaborator of the project."),
 		),
-		mcp.WithString("color",
-			mcp.Description("New project color."),
-			mcp.Enum("berry_red", "red", "orange", "yellow", "olive_green", "lime_green", "green", "mint_green", "teal", "sky_blue", "light_blue", "blue", "grape", "violet", "lavender", "magenta", "salmon", "charcoal", "grey", "taupe"),
+		mcp.WithArray("task_ids",
+			mcp.Description("Limit the change to these task IDs. Overrides filter if both provided."),
 		),
-		mcp.WithBoolean("is_favorite",
-			mcp.Description("Whether project is a favorite."),
+		mcp.WithString("filter",
+			mcp.Description("Todoist filter limiting which tasks are reassigned."),
 		),
-		mcp.WithString("view_style",
-			mcp.Description("New view style."),
-			mcp.Enum("list", "board"),
+		mcp.WithString("confirmation_token",
+			mcp.Description("Token from a previous filter preview. Required to act on a filter."),
 		),
-	), tools.UpdateProjectHandler(todoistClient))
+		mcp.WithBoolean("atomic",
+			mcp.Description("All or nothing: if any task fails to be reassigned, assign the reassigned ones back to from_assignee_id and return an error (default: false, report partial results)."),
+		),
+		mcp.WithBoolean("estimate_cost",
+			mcp.Description("Only report how many REST and Sync requests the change would use versus the remaining rate limit budget; nothing is changed and no confirmation is needed."),
+		),
+		mcp.WithString("strategy",
+			mcp.Description("API to send the change through: auto (default) uses REST for small batches and the Sync API above BULK_SYNC_THRESHOLD tasks; rest or sync forces one, for tokens or workspaces that misbehave under the other. Fails when some change has no form in the forced API (moves and Sync-only updates cannot use rest)."),
+			mcp.Enum("auto", "rest", "sync"),
+		),
+		mcp.WithBoolean("background",
+			mcp.Description("Return an operation_id at once and run the change in the background, for jobs too large to finish within the client's tool timeout. Poll get_operation_status for progress and the result; cancel_operation stops it."),
+		),
+	), operations.Background("reassign_tasks", tools.ReassignTasksHandler(todoistClient, todoistSyncClient, confirmations)))
 
-	s.AddTool(mcp.NewTool("delete_project",
-		mcp.WithDescription("Permanently delete a project and all its tasks. This cannot be undone. Returns success confirmation."),
-		mcp.WithDestructiveHintAnnotation(true),
+	groups.Add("tasks", mcp.NewTool("find_orphan_assignments",
+		mcp.WithDescription("Find tasks in shared projects assigned to users who are no longer collaborators of the project. With action 'unassign' or 'reassign' and confirm: true, clears those assignments or moves them to to_assignee_id in one batched update. Without project_id, every shared project is checked. Returns the orphaned tasks and, when changing, the per-task outcome."),
+		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(true),
 		mcp.WithString("project_id",
-			mcp.Required(),
-			mcp.MinLength(1),
-			mcp.Description("Project ID to delete. Use list_projects to find IDs."),
+			mcp.Description("Shared project ID to check. Defaults to all shared projects."),
 		),
-	), tools.DeleteProjectHandler(todoistClient))
-
-	// ── Section tools ───────────────────────────────────────────────────
+		mcp.WithString("action",
+			mcp.Description("What to do with orphaned tasks (default: report)."),
+			mcp.Enum("report", "unassign", "reassign"),
+		),
+		mcp.WithString("to_assignee_id",
+			mcp.Description("User ID to reassign orphaned tasks to. Required for action 'reassign'; tasks in projects this user is not a collaborator of are skipped."),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("Must be true for action 'unassign' or 'reassign'."),
+		),
+	), tools.FindOrphanAssignmentsHandler(todoistClient, todoistSyncClient))
 
-	s.AddTool(mcp.NewTool("list_sections",
-		mcp.WithDescription("List sections, optionally filtered by project. Returns each section's id, name, project_id, and order. Use the id field as section_id in create_task."),
+	groups.Add("tasks", mcp.NewTool("plan_bulk_operation",
+		mcp.WithDescription("Plan a bulk change without applying it. Resolves the tasks selected by filter or task_ids, builds the exact Sync API commands for the requested actions, and returns them with a plan_id for review. Nothing is changed until execute_plan is called with the plan_id. Plans expire after 10 minutes. Example: filter '#Inbox & @someday' with move_to_project_id and clear_due."),
 		mcp.WithReadOnlyHintAnnotation(true),
-		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
 		mcp.WithOpenWorldHintAnnotation(true),
-		mcp.WithString("project_id",
-			mcp.Description("Filter sections by project ID. Use list_projects to find IDs."),
+		mcp.WithString("filter",
+			mcp.Description("Todoist filter selecting the tasks (e.g., '#Inbox & @someday')."),
+		),
+		mcp.WithArray("task_ids",
+			mcp.Description("Explicit task IDs to act on. Used when filter is not provided."),
+		),
+		mcp.WithString("move_to_project_id",
+			mcp.Description("Move the tasks to this project."),
+		),
+		mcp.WithString("move_to_section_id",
+			mcp.Description("Move the tasks to this section."),
+		),
+		mcp.WithString("due_string",
+			mcp.Description("Set a new due date in natural language (e.g., 'next monday')."),
+		),
+		mcp.WithBoolean("clear_due",
+			mcp.Description("Remove the due date from the tasks."),
+		),
+		mcp.WithNumber("priority",
+			mcp.Description("Set priority (1=normal, 4=urgent)."),
+			mcp.Min(1),
+			mcp.Max(4),
+		),
+		mcp.WithArray("add_labels",
+			mcp.Description("Labels to add, keeping existing labels."),
+		),
+		mcp.WithArray("remove_labels",
+			mcp.Description("Labels to remove."),
+		),
+		mcp.WithBoolean("complete",
+			mcp.Description("Complete the tasks after the other changes."),
+		),
+		mcp.WithBoolean("delete",
+			mcp.Description("Delete the tasks. Cannot be combined with other actions."),
+		),
+	), tools.PlanBulkOperationHandler(todoistClient, planStore))
+
+	groups.Add("tasks", mcp.NewTool("execute_plan",
+		mcp.WithDescription("Apply a plan created by plan_bulk_operation in a single Sync API batch. Each plan can be executed once. Returns the number of commands that succeeded and details of any failures."),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("plan_id",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("plan_id returned by plan_bulk_operation."),
+		),
+	), tools.ExecutePlanHandler(todoistSyncClient, planStore))
+
+	groups.Add("tasks", mcp.NewTool("snooze_task",
+		mcp.WithDescription("Push a task's due date forward while keeping a record of the commitment. Moves the due date (keeping any time of day), adds the snoozed label, and posts a comment \"💤 Snoozed from <original> until <new>\" so the original date is not lost. Recurring tasks cannot be snoozed. Use list_snoozed to review or restore."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("task_id",
+			mcp.Required(),
+			mcp.Description("Task to snooze."),
+		),
+		mcp.WithString("until",
+			mcp.Description("New due date (YYYY-MM-DD). Provide exactly one of until or days."),
+		),
+		mcp.WithNumber("days",
+			mcp.Description("Snooze for this many days from today (1-365)."),
+			mcp.Min(1),
+			mcp.Max(365),
+		),
+		mcp.WithString("reason",
+			mcp.Description("Optional reason recorded in the snooze comment."),
+		),
+	), tools.SnoozeTaskHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("tasks", mcp.NewTool("list_snoozed",
+		mcp.WithDescription("List tasks snoozed with snooze_task, with current_due, original_due, snoozed_until, and reason read from the latest snooze comment. With restore=true, moves the tasks back to their original due dates and removes the snoozed label."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithBoolean("restore",
+			mcp.Description("Restore original due dates of the listed tasks."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithArray("task_ids",
+			mcp.Description("Limit listing and restoring to these task IDs."),
+		),
+	), tools.ListSnoozedHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("tasks", mcp.NewTool("get_due_soon",
+		mcp.WithDescription("List timed tasks starting within the next N hours, sorted by start time, so you can warn e.g. \"your 3pm task starts in 20 minutes\". Due times are resolved in the user's Todoist time zone. Tasks that already started but are still within their duration are included with status in_progress. All-day tasks are excluded. Each task has starts_at, starts_in_minutes, status, message, and ends_at when it has a duration."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithNumber("hours",
+			mcp.Description("Look-ahead window in hours (up to 168)."),
+			mcp.Min(0),
+			mcp.Max(168),
+			mcp.DefaultNumber(2),
+		),
+	), tools.GetDueSoonHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("tasks", mcp.NewTool("search_completed",
+		mcp.WithDescription("Search the archive of completed tasks by text, project, and completion date range (up to 90 days; defaults to the last 30). Returns matches newest first with task_id, content, project_id, completed_at, and completed_item_id when the archive record has its own ID. Pass either ID to uncomplete_task to reopen a task."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Description("Case-insensitive text to find in task content or description."),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Only tasks completed in this project."),
+		),
+		mcp.WithString("since",
+			mcp.Description("Earliest completion date (YYYY-MM-DD). Defaults to 30 days before until."),
+		),
+		mcp.WithString("until",
+			mcp.Description("Latest completion date, inclusive (YYYY-MM-DD). Defaults to today."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of matches to return (1-200)."),
+			mcp.Min(1),
+			mcp.Max(200),
+			mcp.DefaultNumber(50),
+		),
+	), tools.SearchCompletedHandler(todoistSyncClient))
+
+	groups.Add("tasks", mcp.NewTool("get_completed_tasks",
+		mcp.WithDescription("List completed tasks as full records, newest first, e.g. for a weekly review. Defaults to the last 7 days; the range can span up to 90 days. Returns tasks with completed_at, counts by_project and by_day for the page, and next_cursor when more tasks remain. Use search_completed to find a completed task by text."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("since",
+			mcp.Description("Earliest completion time, as YYYY-MM-DD (UTC) or RFC 3339. Defaults to 7 days before until."),
+		),
+		mcp.WithString("until",
+			mcp.Description("Latest completion time, as YYYY-MM-DD (the whole day, UTC) or RFC 3339. Defaults to now."),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Only tasks completed in this project."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of tasks per page (1-200)."),
+			mcp.Min(1),
+			mcp.Max(200),
+			mcp.DefaultNumber(50),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("next_cursor from the previous page. Pass the same since, until, and project_id as before."),
+		),
+	), tools.GetCompletedTasksHandler(todoistSyncClient))
+
+	groups.Add("tasks", mcp.NewTool("list_reminders",
+		mcp.WithDescription("List task reminders (relative, absolute, and location) through the Sync API. Returns each reminder's id, item_id (the task), type, and minute_offset, due, or name/loc_lat/loc_long/loc_trigger/radius depending on the type. Use the id as reminder_id in delete_reminder."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("task_id",
+			mcp.Description("Only reminders of this task."),
+		),
+		mcp.WithString("type",
+			mcp.Description("Only reminders of this type."),
+			mcp.Enum("relative", "absolute", "location"),
+		),
+	), tools.ListRemindersHandler(todoistSyncClient))
+
+	groups.Add("tasks", mcp.NewTool("add_reminder",
+		mcp.WithDescription("Add a reminder to a task. relative reminds minute_offset minutes before the task's due time (the task needs a due time); absolute reminds at due_datetime or due_string; location reminds when arriving at or leaving a place. Reminders need Todoist Pro. Returns the reminder with its new id."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("task_id",
+			mcp.Required(),
+			mcp.Description("Task to remind about."),
+		),
+		mcp.WithString("type",
+			mcp.Required(),
+			mcp.Description("Kind of reminder."),
+			mcp.Enum("relative", "absolute", "location"),
+		),
+		mcp.WithNumber("minute_offset",
+			mcp.Description("relative: minutes before the due time (0 for at the due time)."),
+			mcp.Min(0),
+		),
+		mcp.WithString("due_datetime",
+			mcp.Description("absolute: when to remind, as RFC 3339 or YYYY-MM-DDTHH:MM:SS in the user's time zone."),
+		),
+		mcp.WithString("due_string",
+			mcp.Description("absolute: when to remind in natural language, e.g. \"tomorrow at 9am\". Use instead of due_datetime."),
+		),
+		mcp.WithString("name",
+			mcp.Description("location: name of the place."),
+		),
+		mcp.WithNumber("loc_lat",
+			mcp.Description("location: latitude of the place."),
+			mcp.Min(-90),
+			mcp.Max(90),
+		),
+		mcp.WithNumber("loc_long",
+			mcp.Description("location: longitude of the place."),
+			mcp.Min(-180),
+			mcp.Max(180),
+		),
+		mcp.WithString("loc_trigger",
+			mcp.Description("location: remind on arriving or leaving."),
+			mcp.Enum("on_enter", "on_leave"),
+			mcp.DefaultString("on_enter"),
+		),
+		mcp.WithNumber("radius",
+			mcp.Description("location: radius around the place in meters."),
+			mcp.Min(1),
+		),
+	), tools.AddReminderHandler(todoistSyncClient))
+
+	groups.Add("tasks", mcp.NewTool("delete_reminder",
+		mcp.WithDescription("Delete a reminder by its id from list_reminders. The task itself is not changed."),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("reminder_id",
+			mcp.Required(),
+			mcp.Description("Reminder to delete."),
+		),
+	), tools.DeleteReminderHandler(todoistSyncClient))
+
+	// ── Project tools ───────────────────────────────────────────────────
+
+	groups.Add("projects", mcp.NewTool("list_projects",
+		mcp.WithDescription("List all projects. Returns each project's id, name, description, color, parent_id, order, is_favorite, is_inbox_project, is_team_inbox, and view_style, plus workspace_id and folder_id for workspace projects. Use the id field as project_id in other tools."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+	), tools.ListProjectsHandler(todoistClient))
+
+	groups.Add("projects", mcp.NewTool("create_project",
+		mcp.WithDescription("Create a new project. Returns the created project object with its assigned ID."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Project name."),
+		),
+		mcp.WithString("parent_id",
+			mcp.Description("Parent project ID for creating sub-projects."),
+		),
+		mcp.WithString("description",
+			mcp.Description("Project description shown under the project name (supports Markdown)."),
+		),
+		mcp.WithString("color",
+			mcp.Description("Project color."),
+			mcp.Enum("berry_red", "red", "orange", "yellow", "olive_green", "lime_green", "green", "mint_green", "teal", "sky_blue", "light_blue", "blue", "grape", "violet", "lavender", "magenta", "salmon", "charcoal", "grey", "taupe"),
+		),
+		mcp.WithBoolean("is_favorite",
+			mcp.Description("Whether project is a favorite."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("view_style",
+			mcp.Description("Project view style."),
+			mcp.Enum("list", "board", "calendar"),
+			mcp.DefaultString("list"),
+		),
+	), tools.CreateProjectHandler(todoistClient))
+
+	groups.Add("projects", mcp.NewTool("get_project",
+		mcp.WithDescription("Get a single project by ID with full details including name, description, color, parent_id, order, is_favorite, and view_style. Workspace projects also include workspace_id, folder_id, and is_shared."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Project ID to retrieve. Use list_projects to find IDs."),
+		),
+	), tools.GetProjectHandler(todoistClient))
+
+	groups.Add("projects", mcp.NewTool("update_project",
+		mcp.WithDescription("Update an existing project. Only provided fields are changed. Returns the updated project object."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Project ID to update."),
+		),
+		mcp.WithString("name",
+			mcp.Description("New project name."),
+		),
+		mcp.WithString("description",
+			mcp.Description("New project description. Pass an empty string to clear it."),
+		),
+		mcp.WithString("color",
+			mcp.Description("New project color."),
+			mcp.Enum("berry_red", "red", "orange", "yellow", "olive_green", "lime_green", "green", "mint_green", "teal", "sky_blue", "light_blue", "blue", "grape", "violet", "lavender", "magenta", "salmon", "charcoal", "grey", "taupe"),
+		),
+		mcp.WithBoolean("is_favorite",
+			mcp.Description("Whether project is a favorite."),
+		),
+		mcp.WithString("view_style",
+			mcp.Description("New view style."),
+			mcp.Enum("list", "board", "calendar"),
+		),
+		mcp.WithBoolean("skip_if_unchanged",
+			mcp.Description("Read the project first and skip the write if every requested value already matches, returning the project with changed: false. With this set, the response always includes changed."),
+		),
+	), tools.UpdateProjectHandler(todoistClient))
+
+	groups.Add("projects", mcp.NewTool("delete_project",
+		mcp.WithDescription("Permanently delete a project and all its tasks. This cannot be undone in Todoist; set backup to keep a JSON archive on the server first. Returns success confirmation, and the archive file and counts under backup."),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Project ID to delete. Use list_projects to find IDs."),
+		),
+		mcp.WithBoolean("backup",
+			mcp.Description("Archive the project, its sub-projects, sections, and active tasks to a JSON file on the server before deleting. If the archive cannot be written, nothing is deleted. Defaults to on when the server sets DELETE_BACKUP_DIR."),
+		),
+	), tools.DeleteProjectHandler(todoistClient, backupStore))
+
+	groups.Add("projects", mcp.NewTool("get_project_stats",
+		mcp.WithDescription("Get aggregate statistics for a single project in one call. Returns active_tasks, overdue count, by_priority (p1-p4), by_section (section name to count, including empty sections), by_assignee (collaborator name to count), and last_activity_at."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Project ID to summarize. Use list_projects to find IDs."),
+		),
+		mcp.WithBoolean("include_subprojects",
+			mcp.Description("Aggregate over the project and all projects nested under it. The response then lists the covered project_ids."),
+			mcp.DefaultBool(false),
+		),
+	), tools.GetProjectStatsHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("projects", mcp.NewTool("bulk_set_view_style",
+		mcp.WithDescription("Switch the view style (list, board, or calendar) of many projects at once using a single Sync API batch. Returns the number of projects updated and any failed IDs."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithArray("project_ids",
+			mcp.Required(),
+			mcp.Description("Project IDs to update (max 100). Use list_projects to find IDs."),
+		),
+		mcp.WithString("view_style",
+			mcp.Required(),
+			mcp.Description("View style to apply to every project."),
+			mcp.Enum("list", "board", "calendar"),
+		),
+	), tools.BulkSetViewStyleHandler(todoistSyncClient))
+
+	// ── Section tools ───────────────────────────────────────────────────
+
+	groups.Add("sections", mcp.NewTool("list_sections",
+		mcp.WithDescription("List sections, optionally filtered by project. Returns each section's id, name, project_id, and order. Use the id field as section_id in create_task."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Description("Filter sections by project ID. Use list_projects to find IDs."),
 		),
 	), tools.ListSectionsHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("create_section",
+	groups.Add("sections", mcp.NewTool("create_section",
 		mcp.WithDescription("Create a new section in a project. Returns the created section object."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(false),
@@ -467,7 +1396,7 @@ func main() {
 		),
 	), tools.CreateSectionHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("update_section",
+	groups.Add("sections", mcp.NewTool("update_section",
 		mcp.WithDescription("Rename a section. Returns the updated section object."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
@@ -484,7 +1413,7 @@ func main() {
 		),
 	), tools.UpdateSectionHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("delete_section",
+	groups.Add("sections", mcp.NewTool("delete_section",
 		mcp.WithDescription("Permanently delete a section and move its tasks to the parent project. This cannot be undone. Returns success confirmation."),
 		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(true),
@@ -496,16 +1425,52 @@ func main() {
 		),
 	), tools.DeleteSectionHandler(todoistClient))
 
+	groups.Add("sections", mcp.NewTool("batch_create_sections",
+		mcp.WithDescription("Create several sections in a project, in the given order, with a single Sync API request. Handy for setting up board-style projects (e.g. Backlog, Doing, Review, Done). New sections are placed after existing ones; names that already exist in the project are skipped unless skip_existing is false. Returns created sections with their new IDs, plus skipped and failed names."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.Description("Project to add the sections to."),
+		),
+		mcp.WithArray("names",
+			mcp.Required(),
+			mcp.Description("Section names in the order they should appear (max 100)."),
+		),
+		mcp.WithBoolean("skip_existing",
+			mcp.Description("Skip names that match an existing section in the project (case-insensitive)."),
+			mcp.DefaultBool(true),
+		),
+	), tools.BatchCreateSectionsHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("sections", mcp.NewTool("check_wip_limits",
+		mcp.WithDescription("Check a board project's sections against work-in-progress limits. Limits come from the limits parameter (keyed by section name or ID), falling back to the server's WIP_LIMITS setting and then default_limit. Only top-level tasks count. Returns each checked section's count and limit, and for sections over their limit the excess tasks (the last ones in section order)."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.Description("Board project to check."),
+		),
+		mcp.WithObject("limits",
+			mcp.Description("Map of section name or ID to its WIP limit, e.g. {\"Doing\": 3, \"Review\": 2}. Overrides WIP_LIMITS."),
+		),
+		mcp.WithNumber("default_limit",
+			mcp.Description("Limit for sections without an explicit one. Sections without a limit are skipped when omitted."),
+			mcp.Min(0),
+		),
+	), tools.CheckWIPLimitsHandler(todoistClient, cfg.WIPLimits))
+
 	// ── Label tools ─────────────────────────────────────────────────────
 
-	s.AddTool(mcp.NewTool("list_labels",
+	groups.Add("labels", mcp.NewTool("list_labels",
 		mcp.WithDescription("List all personal labels. Returns each label's id, name, color, order, and is_favorite. Use the name field in create_task's labels array."),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(true),
 	), tools.ListLabelsHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("create_label",
+	groups.Add("labels", mcp.NewTool("create_label",
 		mcp.WithDescription("Create a new personal label. Returns the created label object."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(false),
@@ -528,7 +1493,7 @@ func main() {
 		),
 	), tools.CreateLabelHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("update_label",
+	groups.Add("labels", mcp.NewTool("update_label",
 		mcp.WithDescription("Update a personal label. Only provided fields are changed. Returns the updated label object."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
@@ -553,7 +1518,7 @@ func main() {
 		),
 	), tools.UpdateLabelHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("delete_label",
+	groups.Add("labels", mcp.NewTool("delete_label",
 		mcp.WithDescription("Permanently delete a personal label. Tasks with this label will have it removed. This cannot be undone. Returns success confirmation."),
 		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(true),
@@ -567,7 +1532,7 @@ func main() {
 
 	// ── Comment tools ───────────────────────────────────────────────────
 
-	s.AddTool(mcp.NewTool("get_comments",
+	groups.Add("comments", mcp.NewTool("get_comments",
 		mcp.WithDescription("Get comments for a task or project. Provide either task_id or project_id. Returns an array of comment objects with id, content, posted_at, and attachment fields."),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(true),
@@ -580,7 +1545,7 @@ func main() {
 		),
 	), tools.GetCommentsHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("add_comment",
+	groups.Add("comments", mcp.NewTool("add_comment",
 		mcp.WithDescription("Add a comment to a task or project. Provide content and either task_id or project_id. Returns the created comment object."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(false),
@@ -598,7 +1563,7 @@ func main() {
 		),
 	), tools.AddCommentHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("update_comment",
+	groups.Add("comments", mcp.NewTool("update_comment",
 		mcp.WithDescription("Update the content of an existing comment. Returns the updated comment object."),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
@@ -615,7 +1580,7 @@ func main() {
 		),
 	), tools.UpdateCommentHandler(todoistClient))
 
-	s.AddTool(mcp.NewTool("delete_comment",
+	groups.Add("comments", mcp.NewTool("delete_comment",
 		mcp.WithDescription("Permanently delete a comment. This cannot be undone. Returns success confirmation."),
 		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(true),
@@ -627,14 +1592,841 @@ func main() {
 		),
 	), tools.DeleteCommentHandler(todoistClient))
 
-	slog.Info("server starting",
-		"version", version,
-		"tools", 29,
-		"rate_limit", "450/15min",
-	)
+	groups.Add("comments", mcp.NewTool("log_time",
+		mcp.WithDescription("Record time spent on a task as a structured comment such as \"⏱ 45m on 2025-06-01 — note\". Lightweight time tracking without another service; read entries back with get_time_log."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("task_id",
+			mcp.Required(),
+			mcp.Description("Task the time was spent on."),
+		),
+		mcp.WithNumber("minutes",
+			mcp.Required(),
+			mcp.Description("Whole minutes spent (1-1440)."),
+			mcp.Min(1),
+			mcp.Max(1440),
+		),
+		mcp.WithString("date",
+			mcp.Description("Day the time was spent (YYYY-MM-DD). Defaults to today."),
+		),
+		mcp.WithString("note",
+			mcp.Description("Optional short note appended to the entry."),
+		),
+	), tools.LogTimeHandler(todoistClient))
 
-	if err := server.ServeStdio(s); err != nil {
-		fmt.Fprintf(os.Stderr, "server error: %v\n", err)
-		os.Exit(1)
-	}
+	groups.Add("comments", mcp.NewTool("get_time_log",
+		mcp.WithDescription("Parse and sum time-log comments written by log_time. Returns entries (task, date, minutes, note) sorted by date, total_minutes, and totals by_task, by_date, and by_project. Without task_id, reads all comments on active tasks in a single Sync API request."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("task_id",
+			mcp.Description("Only entries for this task (also works for completed tasks)."),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Only entries for tasks in this project."),
+		),
+		mcp.WithString("from",
+			mcp.Description("Only entries on or after this date (YYYY-MM-DD)."),
+		),
+		mcp.WithString("to",
+			mcp.Description("Only entries on or before this date (YYYY-MM-DD)."),
+		),
+	), tools.GetTimeLogHandler(todoistClient, todoistSyncClient))
+
+	// ── Maintenance tools ───────────────────────────────────────────────
+
+	groups.Add("maintenance", mcp.NewTool("find_stale_tasks",
+		mcp.WithDescription("Find active tasks with no due date that have not been created or updated in N days, grouped by project name with idle_days per task. Optionally labels them in a single Sync API batch so they can be reviewed later."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithNumber("days",
+			mcp.Description("Minimum number of days without changes for a task to count as stale."),
+			mcp.Min(1),
+			mcp.DefaultNumber(30),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Only check tasks in this project. Use list_projects to find IDs."),
+		),
+		mcp.WithBoolean("apply_label",
+			mcp.Description("Add label_name to every stale task that does not already have it."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("label_name",
+			mcp.Description("Label to apply when apply_label is true."),
+			mcp.DefaultString("stale"),
+		),
+	), tools.FindStaleTasksHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("maintenance", mcp.NewTool("review_someday",
+		mcp.WithDescription("Review the Someday/Maybe list (SOMEDAY_PROJECT_ID and/or SOMEDAY_LABEL, default @someday) in batches, oldest first, with each task's age. Without decisions, returns the next batch and a next_cursor. With decisions, applies them in one Sync batch: keep leaves a task as is, activate moves it to project_id with a due date and drops the Someday label, delete deletes it. Decisions may only name tasks on the list."),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Description("Someday project to review instead of the configured list."),
+		),
+		mcp.WithString("label",
+			mcp.Description("Someday label to review instead of the configured one."),
+		),
+		mcp.WithNumber("batch_size",
+			mcp.Description("Tasks per batch, 1-50 (default: 10)."),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("next_cursor from the previous batch."),
+		),
+		mcp.WithArray("decisions",
+			mcp.Description("Array of objects with task_id and action (keep, activate, delete). activate also needs project_id and due_string or due_date (YYYY-MM-DD). Up to 100."),
+		),
+	), tools.ReviewSomedayHandler(todoistClient, todoistSyncClient, tools.SomedayOptions{ProjectID: cfg.SomedayProjectID, Label: cfg.SomedayLabel}))
+
+	groups.Add("maintenance", mcp.NewTool("cleanup_workspace",
+		mcp.WithDescription("Find sections with no active tasks and projects with no active tasks (excluding Inbox, favorites, and projects with sub-projects). With action 'report' (default) only lists candidates and returns a confirmation_token (valid 5 minutes); 'archive' or 'delete' requires that token and applies the change through the Sync API to exactly the reported candidates that are still empty."),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("action",
+			mcp.Description("What to do with empty sections and projects."),
+			mcp.Enum("report", "archive", "delete"),
+			mcp.DefaultString("report"),
+		),
+		mcp.WithString("confirmation_token",
+			mcp.Description("Token from a 'report' call. Required for archive or delete."),
+		),
+	), tools.CleanupWorkspaceHandler(todoistClient, todoistSyncClient, confirmations))
+
+	groups.Add("maintenance", mcp.NewTool("normalize_colors",
+		mcp.WithDescription("List current project and label colors, and optionally bulk-apply a color scheme. Each rule matches projects and/or labels whose name contains 'match' (case-insensitive) and sets 'color'; the first matching rule wins. Changes are applied through the Sync API, 100 per request. Returns current_colors (color to names) and the list of changes."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithArray("rules",
+			mcp.Description("Color rules. Each object: match (string, required), color (required, a Todoist color name), entity ('project', 'label', or 'all'; default 'all'), include_sub_projects (bool; also recolor projects nested under a matching project). Omit to only list colors."),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Report the changes the rules would make without applying them."),
+			mcp.DefaultBool(false),
+		),
+	), tools.NormalizeColorsHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("maintenance", mcp.NewTool("run_self_test",
+		mcp.WithDescription("End-to-end smoke test for a new setup: creates a task in the Inbox with a marker label, updates it, adds a comment, completes it, and deletes it. Returns passed, per-step status (ok, failed, skipped) with errors and timings, and the test task ID. The task is deleted even if a middle step fails; if deletion fails, the message says so. Uses 5 API requests."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("label",
+			mcp.Description("Marker label put on the test task."),
+			mcp.DefaultString("mcp-self-test"),
+		),
+	), tools.RunSelfTestHandler(todoistClient))
+
+	// ── Report tools ────────────────────────────────────────────────────
+
+	groups.Add("reports", mcp.NewTool("get_monthly_report",
+		mcp.WithDescription("Summarize a month's completed tasks for retrospectives. Returns total_completed, by_project (project name to count), by_label (label to count), and notable_p1 (completed urgent tasks). Pages through the completed tasks endpoint automatically."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("month",
+			mcp.Description("Month to report on in YYYY-MM format (e.g., '2026-01'). Defaults to the current month. Months run midnight to midnight in the user's Todoist time zone."),
+			mcp.Pattern(`^\d{4}-\d{2}$`),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Restrict the report to a single project. Use list_projects to find IDs."),
+		),
+	), tools.MonthlyReportHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("reports", mcp.NewTool("get_goal_progress",
+		mcp.WithDescription("Compare today's completed task count against the karma daily goal and report streak status. Returns completed_today, remaining_today, daily_goal_met, weekly progress, current/max daily streak, and streak_at_risk (goal not yet met and not in vacation mode)."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+	), tools.GoalProgressHandler(todoistSyncClient))
+
+	groups.Add("reports", mcp.NewTool("get_productivity_stats",
+		mcp.WithDescription("Get Todoist karma and productivity statistics for completed work, which get_task_stats (active tasks only) does not cover. Returns karma {score, trend, last_change, disabled}, completed_count (all time), goals {daily, weekly, ignore_days, vacation_mode}, streaks (daily and weekly current/last/max, each {count, start, end}), and completed counts per day (last 7 days) and per week (last 4 weeks), each with by_project."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithBoolean("include_karma_history",
+			mcp.Description("Also return karma.history, the average karma per day as charted in the Todoist app (default: false)."),
+		),
+	), tools.GetProductivityStatsHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("reports", mcp.NewTool("get_project_burndown",
+		mcp.WithDescription("Get daily burndown data for a project over a date range, suitable for charting sprint progress. Returns one entry per day with remaining (open at end of day), completed (completed that day), and cumulative_completed. Built from current tasks plus completed task history; deleted tasks are not counted."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Project ID to chart. Use list_projects to find IDs."),
+		),
+		mcp.WithString("since",
+			mcp.Description("First day of the range in YYYY-MM-DD format. Defaults to 13 days before until."),
+			mcp.Pattern(`^\d{4}-\d{2}-\d{2}$`),
+		),
+		mcp.WithString("until",
+			mcp.Description("Last day of the range in YYYY-MM-DD format. Defaults to today (UTC). The range may span at most 90 days."),
+			mcp.Pattern(`^\d{4}-\d{2}-\d{2}$`),
+		),
+	), tools.ProjectBurndownHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("reports", mcp.NewTool("get_recurring_stats",
+		mcp.WithDescription("Report how often each recurring task was completed versus skipped over a date window, to surface broken habits. Expected occurrences are estimated from the recurrence pattern (e.g. 'every day', 'every 2 weeks', 'every mon, thu') and counted from when the task was created; completions come from the completed task history. Each task gets completed, expected, skipped, completion_rate, last_completed, overdue, and a status of on_track, slipping, broken (rate below min_rate), or unknown (recurrence pattern not recognised). Worst performers are listed first."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("since",
+			mcp.Description("First day of the window, YYYY-MM-DD. Defaults to 30 days before until."),
+		),
+		mcp.WithString("until",
+			mcp.Description("Last day of the window, YYYY-MM-DD. Defaults to today. The window may span at most 90 days."),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Only report recurring tasks in this project."),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Todoist filter selecting the tasks to report on (default: 'recurring'). Non-recurring matches are ignored."),
+		),
+		mcp.WithNumber("min_rate",
+			mcp.Description("Completion rate below which a habit is reported as broken (default: 0.5)."),
+			mcp.Min(0),
+			mcp.Max(1),
+		),
+	), tools.RecurringStatsHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("reports", mcp.NewTool("get_habit_summary",
+		mcp.WithDescription("Habit dashboard for recurring tasks labeled @habit (or matching a filter). For each habit returns current_streak and longest_streak (consecutive on-schedule completions over the last 90 days), at_risk (one more missed occurrence ends the streak), done_today, completed_this_week, completed_this_month, and misses (expected occurrences minus completions over the last `days` days). Weeks start on Monday; dates use the user's Todoist time zone. Non-recurring matches are counted but not summarized."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("label",
+			mcp.Description("Label marking habit tasks (default: 'habit')."),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Todoist filter selecting habit tasks; overrides label (e.g. '#Health & recurring')."),
+		),
+		mcp.WithNumber("days",
+			mcp.Description("Window in days for counting misses (default: 30, max: 90)."),
+			mcp.Min(1),
+			mcp.Max(90),
+		),
+	), tools.GetHabitSummaryHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("reports", mcp.NewTool("get_collaborator_digest",
+		mcp.WithDescription("Per-person digest of a shared project: each collaborator's overdue tasks, tasks due through the end of this week (Monday to Sunday, in the user's time zone), and tasks completed recently. Each person includes a plain-text summary ready to post to chat or email."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Shared project ID."),
+		),
+		mcp.WithNumber("completed_days",
+			mcp.Description("How many days of completions to include (1-30, default 7)."),
+			mcp.Min(1),
+			mcp.Max(30),
+		),
+		mcp.WithBoolean("include_unassigned",
+			mcp.Description("Also list unassigned tasks under \"Unassigned\" (default: false)."),
+		),
+	), tools.CollaboratorDigestHandler(todoistClient, todoistSyncClient))
+	// ── Planning tools ──────────────────────────────────────────────────
+
+	groups.Add("planning", mcp.NewTool("get_workload_estimate",
+		mcp.WithDescription("Estimate scheduled workload from task durations for today or the next 7 days. Returns per-day estimated_minutes, remaining_minutes against capacity, over_capacity flags, and the list of tasks lacking a duration estimate. Day-based durations count as a full day of capacity."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("period",
+			mcp.Description("Time window to estimate."),
+			mcp.Enum("today", "week"),
+			mcp.DefaultString("today"),
+		),
+		mcp.WithNumber("capacity_hours",
+			mcp.Description("Available working hours per day used to flag over-capacity days."),
+			mcp.Min(0),
+			mcp.Max(24),
+			mcp.DefaultNumber(6),
+		),
+	), tools.WorkloadEstimateHandler(todoistClient))
+
+	groups.Add("planning", mcp.NewTool("suggest_reschedule",
+		mcp.WithDescription("Propose how to relieve an over-capacity day. Sums task duration estimates for the day and, if they exceed capacity, picks lower-priority tasks to push (lowest priority first; p1, recurring, and fixed-time tasks stay) and assigns each to the earliest following day with room, never past its deadline. Changes nothing; returns moves, unplaced tasks, and an apply block with arguments for bulk_reschedule."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("date",
+			mcp.Description("Day to relieve (YYYY-MM-DD). Defaults to today."),
+		),
+		mcp.WithNumber("capacity_hours",
+			mcp.Description("Available working hours per day."),
+			mcp.Min(0),
+			mcp.Max(24),
+			mcp.DefaultNumber(6),
+		),
+		mcp.WithNumber("horizon_days",
+			mcp.Description("Number of following days that may receive pushed tasks."),
+			mcp.Min(1),
+			mcp.Max(30),
+			mcp.DefaultNumber(7),
+		),
+	), tools.SuggestRescheduleHandler(todoistClient))
+
+	groups.Add("planning", mcp.NewTool("bulk_reschedule",
+		mcp.WithDescription("Set new due dates on up to 100 tasks in one call, e.g. the apply block from suggest_reschedule. Uses REST for a few tasks and a single Sync API batch for more. Returns rescheduled and failed counts with failed_task_ids."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithArray("moves",
+			mcp.Required(),
+			mcp.Description("Array of objects with task_id and due_date (YYYY-MM-DD)."),
+		),
+		mcp.WithBoolean("estimate_cost",
+			mcp.Description("Only report how many REST and Sync requests the change would use versus the remaining rate limit budget; nothing is changed and no confirmation is needed."),
+		),
+		mcp.WithString("strategy",
+			mcp.Description("API to send the change through: auto (default) uses REST for small batches and the Sync API above BULK_SYNC_THRESHOLD tasks; rest or sync forces one, for tokens or workspaces that misbehave under the other. Fails when some change has no form in the forced API (moves and Sync-only updates cannot use rest)."),
+			mcp.Enum("auto", "rest", "sync"),
+		),
+		mcp.WithBoolean("atomic",
+			mcp.Description("All or nothing: if any task fails to be rescheduled, set the rescheduled ones back to their previous due date (or no date) and return an error (default: false, report partial results)."),
+		),
+		mcp.WithBoolean("background",
+			mcp.Description("Return an operation_id at once and run the change in the background, for jobs too large to finish within the client's tool timeout. Poll get_operation_status for progress and the result; cancel_operation stops it."),
+		),
+	), operations.Background("bulk_reschedule", tools.BulkRescheduleHandler(todoistClient, todoistSyncClient)))
+
+	groups.Add("planning", mcp.NewTool("estimate_tasks",
+		mcp.WithDescription("Read or apply time estimates across tasks. Without estimate, reports how many selected tasks carry an estimate (from the duration field or an estimate label like @15min or @1h), the distribution by_estimate, total_minutes, and the unestimated tasks. With estimate, sets it on every selected task (max 100) as a duration, an estimate label replacing any previous one, or both."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithArray("task_ids",
+			mcp.Description("Task IDs to select."),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Todoist filter to select tasks (used when task_ids is empty)."),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Project to select tasks from (used when task_ids and filter are empty). With no selection, reports on all active tasks."),
+		),
+		mcp.WithString("estimate",
+			mcp.Description("Estimate to apply, e.g. 15min, 45m, or 1h. Omit to only report."),
+		),
+		mcp.WithString("target",
+			mcp.Description("Where to store an applied estimate."),
+			mcp.Enum("duration", "label", "both"),
+			mcp.DefaultString("duration"),
+		),
+	), tools.EstimateTasksHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("planning", mcp.NewTool("suggest_next_actions",
+		mcp.WithDescription("Rank what to work on right now for the time, energy, and context available. Candidates must carry one of the context labels, fit in the available minutes (from durations or estimate labels; unestimated tasks are kept), not need more energy than given (tasks labeled @high-energy need high, @low-energy low, others medium), not be deferred, and not wait on an open blocker. They are scored by priority, the nearer of due date and deadline, how much of the time they use, and matching energy, with reasons. Also returns a plan: the best-scoring estimated tasks that fit together in the time."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithNumber("minutes",
+			mcp.Required(),
+			mcp.Description("Minutes available."),
+		),
+		mcp.WithString("energy",
+			mcp.Description("Current energy level (default: medium)."),
+			mcp.Enum("low", "medium", "high"),
+		),
+		mcp.WithArray("contexts",
+			mcp.Description("Context labels, e.g. ['home', 'errands']. Only tasks with at least one are considered."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum suggestions, 1-20 (default: 5)."),
+		),
+	), tools.SuggestNextActionsHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("planning", mcp.NewTool("flag_at_risk_tasks",
+		mcp.WithDescription("Find tasks whose deadline is within the next days, or already past, but which have no due date or sit unassigned in a shared project. With flag: true, adds the @at-risk label to those not yet labeled, and with comment: true also posts a warning comment on each, in one batch. Returns the tasks with days_left and reasons. The server can also run this hourly on its own when AT_RISK_DAYS is set."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithNumber("days",
+			mcp.Description("How many days ahead to check deadlines, 0-30. Defaults to AT_RISK_DAYS, or 3."),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Only check tasks in this project."),
+		),
+		mcp.WithBoolean("flag",
+			mcp.Description("Label the at-risk tasks that are not labeled yet (default: false, report only)."),
+		),
+		mcp.WithString("label",
+			mcp.Description("Label marking at-risk tasks. Defaults to AT_RISK_LABEL, or at-risk."),
+		),
+		mcp.WithBoolean("comment",
+			mcp.Description("With flag, also post a warning comment on each newly labeled task. Defaults to AT_RISK_COMMENT."),
+		),
+	), tools.FlagAtRiskTasksHandler(todoistClient, todoistSyncClient, atRiskOptions))
+
+	groups.Add("planning", mcp.NewTool("set_defer_date",
+		mcp.WithDescription("Defer a task until a date: it stays active but is not available to work on before then, and search_tasks with hide_deferred leaves it out. Unlike the due date, the defer date says when a task can start, not when it must be done. Stored as a 'ref:defer=YYYY-MM-DD' line in the reference footer of the description (see set_task_reference)."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("task_id",
+			mcp.Required(),
+			mcp.Description("The ID of the task."),
+		),
+		mcp.WithString("date",
+			mcp.Required(),
+			mcp.Description("Defer date (YYYY-MM-DD). Pass an empty string to clear it."),
+		),
+	), tools.SetDeferDateHandler(todoistClient))
+
+	groups.Add("planning", mcp.NewTool("link_tasks",
+		mcp.WithDescription("Record that one task blocks another, across projects. Todoist has no dependencies, so the link is stored as a 'ref:blocked-by=<ids>' line in the reference footer of the blocked task's description (see set_task_reference). Pass exactly one of blocked_by or blocks. Links that would create a cycle are refused."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("task_id",
+			mcp.Required(),
+			mcp.Description("The ID of the task."),
+		),
+		mcp.WithString("blocked_by",
+			mcp.Description("ID of a task that must be completed before task_id."),
+		),
+		mcp.WithString("blocks",
+			mcp.Description("ID of a task that cannot start until task_id is completed."),
+		),
+		mcp.WithBoolean("remove",
+			mcp.Description("Remove the link instead of adding it (default: false)."),
+		),
+	), tools.LinkTasksHandler(todoistClient))
+
+	groups.Add("planning", mcp.NewTool("get_blockers",
+		mcp.WithDescription("Resolve task dependencies recorded with link_tasks. With task_id, returns the task's direct blockers, every open task it transitively waits on, and the tasks it blocks. Without it, lists all linked tasks and flags those with incomplete blockers, blocked ones first. Blockers that are no longer active count as done."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("task_id",
+			mcp.Description("Resolve the dependencies of this task."),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Only list linked tasks in this project. Their blockers may be in any project."),
+		),
+	), tools.GetBlockersHandler(todoistClient))
+
+	groups.Add("planning", mcp.NewTool("suggest_task_order",
+		mcp.WithDescription("Suggest a working order for a project's tasks from their link_tasks dependencies and due dates. Tasks come after their blockers; among tasks that are ready, the earliest date needed first (a blocker is needed by the earliest due date of anything waiting on it), then higher priority. Also returns the critical path (the dependency chain with the most estimated minutes), conflicts where a blocker is due after a task that needs it, and dependency cycles, whose tasks are left out of the order. Changes nothing."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.Description("The project to order. Blockers in other projects are taken into account."),
+		),
+	), tools.SuggestTaskOrderHandler(todoistClient))
+
+	groups.Add("planning", mcp.NewTool("start_focus_session",
+		mcp.WithDescription("Start a focus session: pick the top N tasks for a filter and remember them in the server until end_focus_session. Tasks are ranked by priority (10 points per level) plus deadline urgency (up to 30 points for a deadline within a week or passed) and 5 points for being due today or overdue. Optionally adds a @focus label so the picks stand out in Todoist. Returns session_id, started_at, and the picked tasks with their scores. Sessions expire after 24 hours."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("filter",
+			mcp.Description("Todoist filter to pick tasks from (default: 'today | overdue')."),
+		),
+		mcp.WithNumber("count",
+			mcp.Description("Number of tasks to focus on (default: 3, max: 10)."),
+			mcp.Min(1),
+			mcp.Max(10),
+		),
+		mcp.WithBoolean("add_label",
+			mcp.Description("Add the focus label to the picked tasks; end_focus_session removes it again (default: false)."),
+		),
+		mcp.WithString("label",
+			mcp.Description("Label to add when add_label is true (default: 'focus')."),
+		),
+	), tools.StartFocusSessionHandler(todoistClient, todoistSyncClient, focusSessions))
+
+	groups.Add("planning", mcp.NewTool("end_focus_session",
+		mcp.WithDescription("End a focus session and report what got done while it ran: completed and not_completed session tasks, also_completed (other tasks finished during the session), completion_rate, and duration_minutes. Removes the focus label from unfinished tasks if the session added it."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("session_id",
+			mcp.Description("Session to end. Defaults to the most recently started session."),
+		),
+	), tools.EndFocusSessionHandler(todoistClient, todoistSyncClient, focusSessions))
+
+	// ── Favorite tools ──────────────────────────────────────────────────
+
+	groups.Add("favorites", mcp.NewTool("list_favorites",
+		mcp.WithDescription("List everything pinned to the Todoist sidebar: favorite projects, labels, and saved filters. Returns id, name, color, and (for filters) query for each favorite."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+	), tools.ListFavoritesHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("favorites", mcp.NewTool("set_favorite",
+		mcp.WithDescription("Mark or unmark projects, labels, and saved filters as favorites in a single Sync API batch. Favorites appear in the Todoist sidebar. Use list_favorites to see the current set."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithArray("items",
+			mcp.Required(),
+			mcp.Description("Entities to update (max 100). Each object: type ('project', 'label', or 'filter') and id."),
+		),
+		mcp.WithBoolean("is_favorite",
+			mcp.Description("true to add to favorites, false to remove."),
+			mcp.DefaultBool(true),
+		),
+	), tools.SetFavoriteHandler(todoistSyncClient))
+
+	// ── Session tools ───────────────────────────────────────────────────
+
+	groups.Add("session", mcp.NewTool("list_recent_operations",
+		mcp.WithDescription("List the mutating tool calls made through this server in the current session, newest first. Each entry has the tool name, timestamp, status (ok or error), and affected entity IDs. The same journal is readable as the todoist://operations/recent resource."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of operations to return (1-200)."),
+			mcp.Min(1),
+			mcp.Max(200),
+			mcp.DefaultNumber(20),
+		),
+	), tools.ListRecentOperationsHandler(journal))
+
+	s.AddResource(mcp.NewResource(tools.RecentOperationsURI, "Recent operations",
+		mcp.WithResourceDescription("Journal of mutating tool calls made in this session, newest first."),
+		mcp.WithMIMEType("application/json"),
+	), tools.RecentOperationsResourceHandler(journal))
+
+	s.AddResource(mcp.NewResource(tools.DailyDigestURI, "Today's digest",
+		mcp.WithResourceDescription("Markdown summary of today, rendered on read: tasks due today, overdue tasks, today's completions, and unread notifications. Clients can pin it as ambient context."),
+		mcp.WithMIMEType("text/markdown"),
+	), tools.DailyDigestResourceHandler(todoistClient, todoistSyncClient))
+
+	for _, kind := range []string{"task", "project", "section", "comment", "label"} {
+		s.AddResourceTemplate(mcp.NewResourceTemplate(tools.EntityURI(kind, "{id}"), "Todoist "+kind,
+			mcp.WithTemplateDescription(fmt.Sprintf("The %s with the given ID, as the REST API reports it. Mutating tools link the entities they touch with these URIs.", kind)),
+			mcp.WithTemplateMIMEType("application/json"),
+		), tools.EntityResourceHandler(todoistClient, kind))
+	}
+
+	groups.Add("session", mcp.NewTool("estimate_operation_cost",
+		mcp.WithDescription("Predict how many REST and Sync requests a bulk change would consume, compared with the rate limit budget remaining in the current 15-minute window, without changing anything. Uses the same REST-or-Sync choice as the bulk tools: by default REST for up to 5 tasks (BULK_SYNC_THRESHOLD), one Sync request per 100 commands above that. Returns cost {operations, strategy, rest_requests, sync_requests, rest_remaining, sync_remaining, within_budget}. Use it to split large jobs across windows; the bulk tools also accept estimate_cost for the same report on their exact selection."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("operation",
+			mcp.Required(),
+			mcp.Description("Kind of change: complete, delete, update, move (Sync-only), or create (Sync-only)."),
+			mcp.Enum("complete", "delete", "update", "move", "create"),
+		),
+		mcp.WithNumber("count",
+			mcp.Description("Number of tasks the change affects."),
+			mcp.Min(1),
+		),
+		mcp.WithArray("task_ids",
+			mcp.Description("Task IDs the change affects (used when count is omitted)."),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Todoist filter selecting the affected tasks (used when count and task_ids are omitted; costs one lookup request)."),
+		),
+		mcp.WithString("strategy",
+			mcp.Description("API the change would be sent through: auto (default), rest, or sync, as accepted by the bulk tools."),
+			mcp.Enum("auto", "rest", "sync"),
+		),
+	), tools.EstimateOperationCostHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("session", mcp.NewTool("get_operation_status",
+		mcp.WithDescription("Report a background operation started with background: true on a bulk tool: state (running, succeeded, failed, cancelled), done/total progress, and once finished the tool's usual result or error. Without operation_id, lists the operations of the last hour, newest first."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString("operation_id",
+			mcp.Description("Operation ID returned by the bulk tool."),
+		),
+	), tools.GetOperationStatusHandler(operations))
+
+	groups.Add("session", mcp.NewTool("cancel_operation",
+		mcp.WithDescription("Stop a running background operation. Changes already sent stay applied; the remaining ones are reported as failed in the operation's result."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString("operation_id",
+			mcp.Required(),
+			mcp.Description("Operation ID returned by the bulk tool."),
+		),
+	), tools.CancelOperationHandler(operations))
+
+	transports := []string{describeTransport(cfg)}
+	if cfg.DebugAddr != "" {
+		transports = append(transports, "debug http://"+cfg.DebugAddr+"/debug/")
+	}
+	groups.Add("session", mcp.NewTool("get_server_info",
+		mcp.WithDescription("Describe this server deployment: version, transports, registered tool names, cache status, rate limit configuration (window, max_requests, remaining, max_in_flight, persisted state file), and the Todoist API variants in use with their base URLs. Makes no Todoist API calls. Useful when debugging a setup or checking which tools a host can call."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+	), tools.GetServerInfoHandler(tools.ServerInfo{
+		Version:            version,
+		Transports:         transports,
+		RateLimitWindow:    rateLimitWindow,
+		RateLimitMax:       rateLimitMax,
+		MaxInFlight:        maxInFlight,
+		RateLimitStateFile: cfg.RateLimitStateFile,
+		Profile:            profile.Name,
+		TokenScopes:        cfg.TokenScopes,
+		RefusedTools:       scopeGuard.Refused,
+		ReferenceCacheTTL:  refs.TTL(),
+	}, todoistClient, toolNames))
+
+	groups.Add("session", mcp.NewTool("get_context_bundle",
+		mcp.WithDescription("Fetch the reference data needed at the start of a session in one call: all projects, labels, and sections, plus the tasks due today or overdue. Projects, labels, and sections are served from a short-lived cache when fresh (see cache_ttl_seconds and cached); today's tasks are always fetched live. Call this once instead of list_projects, list_labels, list_sections, and search_tasks."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Description("Only return the sections of this project."),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Refetch projects, labels, and sections even if the cache is fresh, e.g. after creating or renaming some (default: false)."),
+		),
+	), tools.GetContextBundleHandler(refs))
+
+	// ── Template tools ──────────────────────────────────────────────────
+
+	groups.Add("templates", mcp.NewTool("save_project_as_template",
+		mcp.WithDescription("Save a project's sections and tasks as a named template in the local template library. Due dates are stored as relative days (day 1 is the earliest due date in the project); completed tasks and absolute dates are not kept."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.Description("The ID of the project to save as a template."),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Template name, e.g. 'Client Onboarding'. Names are case-insensitive."),
+		),
+		mcp.WithString("description",
+			mcp.Description("Optional description of what the template is for."),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Replace an existing template with the same name."),
+			mcp.DefaultBool(false),
+		),
+	), tools.SaveTemplateHandler(todoistClient, templateStore))
+
+	groups.Add("templates", mcp.NewTool("list_templates",
+		mcp.WithDescription("List the project templates saved in the local template library with their section and task counts."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+	), tools.ListTemplatesHandler(templateStore))
+
+	groups.Add("templates", mcp.NewTool("instantiate_template",
+		mcp.WithDescription("Create a template's sections and tasks in a new project (project_name) or an existing one (project_id). Relative days become due dates counted from start_date (default today): day 1 is the start date, day 7 is six days later. Everything is created in one Sync batch of at most 100 commands."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the template to instantiate."),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Existing project to add the template's sections and tasks to. Mutually exclusive with project_name."),
+		),
+		mcp.WithString("project_name",
+			mcp.Description("Name of a new project to create from the template. Mutually exclusive with project_id."),
+		),
+		mcp.WithString("start_date",
+			mcp.Description("Anchor date in YYYY-MM-DD format. Day 1 tasks are due on this date, day 4 tasks three days later, and so on. Defaults to today."),
+		),
+	), tools.InstantiateTemplateHandler(todoistSyncClient, templateStore))
+
+	groups.Add("templates", mcp.NewTool("start_sprint",
+		mcp.WithDescription("Start this week's sprint: create the project \"Sprint YYYY-WW\" (ISO week), from a template if one is given, move tasks labeled @next-sprint into it and remove that label, carry over the open tasks of earlier sprint projects, and archive those projects once emptied. Does nothing if this week's sprint already exists. Returns project_id, created, tasks_moved, archived sprint names, and failed operations. The server can also do this every SPRINT_WEEKS weeks on its own."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("template",
+			mcp.Description("Saved project template to create the sprint from. Defaults to SPRINT_TEMPLATE, or an empty project."),
+		),
+		mcp.WithString("label",
+			mcp.Description("Label of the tasks to pull into the sprint, without @. Defaults to SPRINT_LABEL, or next-sprint."),
+		),
+	), tools.StartSprintHandler(todoistClient, todoistSyncClient, templateStore, sprintOptions))
+
+	groups.Add("templates", mcp.NewTool("duplicate_project",
+		mcp.WithDescription("Copy a project's sections and active tasks (with subtasks, labels, and priorities) into a new project. Due dates keep their spacing: the earliest due date maps to start_date (or today) and the rest are shifted by the same amount. Completed tasks and comments are not copied."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.Description("The ID of the project to duplicate."),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name for the new project. Defaults to the source name with ' (copy)' appended."),
+		),
+		mcp.WithString("start_date",
+			mcp.Description("Anchor date in YYYY-MM-DD format for the earliest due date. Defaults to today."),
+		),
+	), tools.DuplicateProjectHandler(todoistClient, todoistSyncClient))
+
+	groups.Add("templates", mcp.NewTool("export_project",
+		mcp.WithDescription("Export a project's sections and active tasks in Todoist's CSV template format (TYPE, CONTENT, DESCRIPTION, PRIORITY, INDENT, AUTHOR, RESPONSIBLE, DATE, DATE_LANG, TIMEZONE, DURATION, DURATION_UNIT). The result can be imported with the Todoist web app's 'Import from template' or with import_tasks."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.Description("The ID of the project to export."),
+		),
+	), tools.ExportProjectHandler(todoistClient))
+
+	groups.Add("templates", mcp.NewTool("import_tasks",
+		mcp.WithDescription("Import sections, tasks, and notes into a project from a CSV in Todoist's template format, such as a file from the web app's 'Export as template'. INDENT sets subtask nesting, PRIORITY uses UI numbering (1 = urgent), @labels in CONTENT become labels, and DATE is a natural language due date. Everything is created in one Sync batch of at most 100 commands."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("project_id",
+			mcp.Required(),
+			mcp.Description("The ID of the project to import into."),
+		),
+		mcp.WithString("csv",
+			mcp.Required(),
+			mcp.Description("CSV content with a header row. TYPE and CONTENT columns are required; other columns are optional."),
+		),
+	), tools.ImportTasksHandler(todoistSyncClient))
+
+	// ── Integration tools ───────────────────────────────────────────────
+
+	groups.Add("integrations", mcp.NewTool("link_github_issue",
+		mcp.WithDescription("Create or update a task that mirrors a GitHub issue. The task gets the @github label, the issue URL in its description, and content like '[owner/repo#123](url) title'; a comment records the link when the task is created. Call again with a new title to sync it, or with state 'closed' to complete the task. Does not call the GitHub API."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("issue",
+			mcp.Required(),
+			mcp.Description("Issue reference in owner/repo#number format, e.g. 'golang/go#12345'."),
+		),
+		mcp.WithString("title",
+			mcp.Description("Issue title. Required when creating the linked task; when given for an existing link, the task content is updated to match."),
+		),
+		mcp.WithString("state",
+			mcp.Description("Issue state. 'closed' completes the linked task."),
+			mcp.Enum("open", "closed"),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Project for a newly created task (defaults to Inbox). Ignored when the issue is already linked."),
+		),
+	), tools.LinkGitHubIssueHandler(todoistClient))
+
+	groups.Add("integrations", mcp.NewTool("set_task_reference",
+		mcp.WithDescription("Attach an external system ID (Jira key, email Message-ID, CRM record, ...) to a task. References are stored as 'ref:<system>=<id>' lines in a footer at the end of the task description, one per system, so they survive edits to the content and body. An empty reference removes the system's entry."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("task_id",
+			mcp.Required(),
+			mcp.Description("The ID of the task."),
+		),
+		mcp.WithString("system",
+			mcp.Required(),
+			mcp.Description("External system name, lowercase (e.g. 'jira', 'email', 'salesforce')."),
+		),
+		mcp.WithString("reference",
+			mcp.Required(),
+			mcp.Description("The external ID. Pass an empty string to remove the reference."),
+		),
+	), tools.SetTaskReferenceHandler(todoistClient))
+
+	groups.Add("integrations", mcp.NewTool("find_task_by_reference",
+		mcp.WithDescription("Find active tasks whose description footer holds the given external reference (set with set_task_reference). Matches the exact system and ID."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("system",
+			mcp.Required(),
+			mcp.Description("External system name (e.g. 'jira')."),
+		),
+		mcp.WithString("reference",
+			mcp.Required(),
+			mcp.Description("The external ID to look up."),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Limit the search to one project."),
+		),
+	), tools.FindTaskByReferenceHandler(todoistClient))
+
+	groups.Add("integrations", mcp.NewTool("capture_email_as_task",
+		mcp.WithDescription("Create a task from an email: the subject becomes the content, the sender, received date, a message:// link, and the body go in the description, and the task gets the @email label. When message_id is given it is stored as an 'email' reference (see find_task_by_reference) so the same email is not captured twice. Tasks go to project_id, else the configured triage project (TRIAGE_PROJECT_ID), else Inbox."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("subject",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Email subject, used as the task content."),
+		),
+		mcp.WithString("sender",
+			mcp.Required(),
+			mcp.Description("Sender, e.g. 'Jane Doe <jane@example.com>'."),
+		),
+		mcp.WithString("body",
+			mcp.Description("Plain text email body. Long bodies are truncated to fit Todoist's description limit."),
+		),
+		mcp.WithString("received_date",
+			mcp.Description("When the email was received: RFC 3339, an RFC 5322 Date header value, or YYYY-MM-DD."),
+		),
+		mcp.WithString("message_id",
+			mcp.Description("The email's Message-ID header, e.g. '<abc123@mail.example.com>'. Enables the message:// link and reference lookup."),
+		),
+		mcp.WithString("project_id",
+			mcp.Description("Project for the task. Overrides the configured triage project."),
+		),
+		mcp.WithString("due_string",
+			mcp.Description("Optional natural language due date, e.g. 'tomorrow'."),
+		),
+	), tools.CaptureEmailAsTaskHandler(todoistClient, cfg.TriageProjectID))
+
+	// ── Admin tools ─────────────────────────────────────────────────────
+
+	// Registered outside any group so that it can never disable itself.
+	groups.AddPinned(mcp.NewTool("configure_tool_groups",
+		mcp.WithDescription("List the tool groups (tasks, projects, sections, labels, comments, maintenance, reports, planning, favorites, session, templates, integrations) with their tools and whether each is enabled, and optionally enable or disable groups. Connected clients are notified that the tool list changed. Call with no arguments to list."),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithArray("enable",
+			mcp.Description("Group names to enable."),
+		),
+		mcp.WithArray("disable",
+			mcp.Description("Group names to disable."),
+		),
+	), tools.ConfigureToolGroupsHandler(groups))
+
+	// Raw Sync access is pinned, and only an explicitly selected admin
+	// profile exposes it.
+	groups.AddPinned(mcp.NewTool("execute_commands",
+		mcp.WithDescription("Advanced: send up to 100 Sync API commands in one request, e.g. a new project with its sections, tasks, and reminders. Each command is {type, args, temp_id?}. Give *_add commands a temp_id name and reference it from later commands as \"$name\" in ID arguments (id, ids, and *_id keys such as project_id, section_id, parent_id, item_id); other text is sent unchanged. Supported types: project_*, section_*, item_add/update/move/close/uncomplete/delete, label_*, note_*, reminder_* (add, update, delete and where applicable move, archive, unarchive). Returns the status of each command and the real IDs for every temp_id. Prefer the dedicated tools when one fits."),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithArray("commands",
+			mcp.Required(),
+			mcp.Description("Sync commands in execution order, e.g. [{\"type\": \"project_add\", \"temp_id\": \"p\", \"args\": {\"name\": \"Launch\"}}, {\"type\": \"item_add\", \"args\": {\"content\": \"Plan\", \"project_id\": \"$p\"}}]."),
+		),
+	), tools.ExecuteCommandsHandler(todoistSyncClient))
+
+	// A full sync reads the whole account, so it is pinned like raw Sync
+	// commands and only an explicitly selected admin profile exposes it.
+	groups.AddPinned(mcp.NewTool("full_sync",
+		mcp.WithDescription("Advanced: read the whole account in one Sync API request, e.g. for client-side analytics. Returns the sync payload (projects, items, sections, labels, notes, filters, reminders, user, and more) plus counts per resource and a sync_token. Pass that sync_token on the next call to receive only what changed since. Payloads can be very large; select resource_types to keep them small. Costs one request instead of one per REST listing."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithArray("resource_types",
+			mcp.Description("Resource types to include, e.g. [\"projects\", \"items\"], or [\"all\", \"-notes\"] to exclude some (default: [\"all\"])."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("sync_token",
+			mcp.Description("sync_token from an earlier full_sync to return only changes since then (default: a full sync)."),
+		),
+	), tools.FullSyncHandler(todoistSyncClient))
+
+	if err := groups.Apply(opts.disabledGroups); err != nil {
+		return nil, fmt.Errorf("DISABLED_TOOL_GROUPS: %w", err)
+	}
+
+	// Instructions describe the registered tools, so they are set last.
+	instructions, err := tools.BuildInstructions(cfg.InstructionsTemplate, toolNames())
+	if err != nil {
+		return nil, err
+	}
+	server.WithInstructions(instructions)(s)
+
+	return &toolServer{mcp: s, groups: groups, templates: templateStore, refs: refs, profile: profile}, nil
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-todoist/tools"
+)
+
+// servePipe runs the MCP server on the Windows named pipe name until the
+// process is interrupted or ctx ends. Clients connect one at a time and
+// speak the stdio protocol over the pipe, so a local bridge can hand the
+// pipe to an MCP host as if it were the server's stdin and stdout. Only
+// user (see listenPipe) can connect.
+func servePipe(ctx context.Context, s *server.MCPServer, completer *tools.Completer, name, user string, ready func() error) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ln, err := listenPipe(name, user)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", name, err)
+	}
+	return servePipeListener(ctx, s, completer, ln, ready)
+}
+
+// servePipeListener serves pipe clients accepted from ln, one at a time,
+// until ctx ends. ready, if set, runs before the first client is accepted;
+// its error closes ln and is returned. It returns nil after a shutdown.
+func servePipeListener(ctx context.Context, s *server.MCPServer, completer *tools.Completer, ln net.Listener, ready func() error) error {
+	if ready != nil {
+		if err := ready(); err != nil {
+			_ = ln.Close()
+			return err
+		}
+	}
+	slog.Info("listening", "transport", "pipe", "addr", ln.Addr().String())
+
+	// Closing the listener and the open connection ends a blocked Accept or
+	// Read once ctx is done.
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		slog.Debug("pipe client connected")
+		connCtx, cancel := context.WithCancel(ctx)
+		go func() {
+			<-connCtx.Done()
+			_ = conn.Close()
+		}()
+		err = serveStream(connCtx, s, completer, conn, conn)
+		cancel()
+		_ = conn.Close()
+		if err != nil {
+			slog.Warn("pipe client failed", "error", err)
+		}
+		slog.Debug("pipe client disconnected")
+	}
+}
+
+// pipeAddr is the address of both ends of a named pipe connection.
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"net"
+)
+
+// listenPipe fails outside Windows, which has no named pipes.
+func listenPipe(string, string) (net.Listener, error) {
+	return nil, errors.New("the pipe transport is only supported on Windows")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-todoist/tools"
+)
+
+// memListener hands out in-memory connections, standing in for a named pipe.
+type memListener struct {
+	conns  chan net.Conn
+	closed chan struct{}
+}
+
+func newMemListener() *memListener {
+	return &memListener{conns: make(chan net.Conn), closed: make(chan struct{})}
+}
+
+func (l *memListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *memListener) Close() error {
+	select {
+	case <-l.closed:
+	default:
+		close(l.closed)
+	}
+	return nil
+}
+
+func (l *memListener) Addr() net.Addr { return pipeAddr(`\\.\pipe\test`) }
+
+// dial connects a client, returning its end of the connection.
+func (l *memListener) dial() net.Conn {
+	client, srv := net.Pipe()
+	l.conns <- srv
+	return client
+}
+
+func TestServePipeListener(t *testing.T) {
+	s := server.NewMCPServer("Todoist Server", "test", server.WithToolCapabilities(true))
+	ln := newMemListener()
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- servePipeListener(ctx, s, tools.NewCompleter(nil), ln, func() error {
+			close(ready)
+			return nil
+		})
+	}()
+	<-ready
+
+	// Clients are served one after another, each with CRLF line endings as
+	// Windows tools send them.
+	for i := 0; i < 2; i++ {
+		conn := ln.dial()
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		if _, err := conn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}` + "\r\n")); err != nil {
+			t.Fatalf("client %d write: %v", i, err)
+		}
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil || !strings.Contains(line, `"protocolVersion"`) {
+			t.Fatalf("client %d initialize = %q, %v", i, line, err)
+		}
+		_ = conn.Close()
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("shutdown error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
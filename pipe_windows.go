@@ -0,0 +1,244 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                = syscall.NewLazyDLL("kernel32.dll")
+	procCreateNamedPipeW    = kernel32.NewProc("CreateNamedPipeW")
+	procConnectNamedPipe    = kernel32.NewProc("ConnectNamedPipe")
+	procDisconnectNamedPipe = kernel32.NewProc("DisconnectNamedPipe")
+
+	procConvertStringSecurityDescriptorToSecurityDescriptorW = advapi32.NewProc("ConvertStringSecurityDescriptorToSecurityDescriptorW")
+)
+
+// Named pipe flags and errors, from winbase.h and winerror.h.
+const (
+	pipeAccessDuplex          = 0x00000003
+	fileFlagFirstPipeInstance = 0x00080000
+	pipeTypeByte              = 0x00000000
+	pipeReadmodeByte          = 0x00000000
+	pipeWait                  = 0x00000000
+	pipeRejectRemoteClients   = 0x00000008
+	pipeUnlimitedInstances    = 255
+	pipeBufferSize            = 64 * 1024
+
+	errorPipeConnected = syscall.Errno(535)
+
+	sddlRevision1 = 1
+)
+
+// pipeListener accepts clients on a local named pipe. Each Accept waits on
+// a new instance of the pipe; remote clients are rejected, and so are local
+// users other than the one the pipe was created for.
+type pipeListener struct {
+	name string
+	path *uint16
+	// sa restricts the pipe to its user and the SYSTEM account. Its
+	// security descriptor is freed by Close.
+	sa *syscall.SecurityAttributes
+
+	mu sync.Mutex
+	// next is the instance waiting for a client, or 0 before Accept has
+	// created one.
+	next      syscall.Handle
+	accepting bool
+	closed    bool
+}
+
+// listenPipe creates the named pipe name, which only user (an account name
+// or SID) and the SYSTEM account can open; an empty user is the one running
+// the process. It fails if another process already serves a pipe by that
+// name.
+func listenPipe(name, user string) (net.Listener, error) {
+	path, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	sid, err := pipeUserSID(user)
+	if err != nil {
+		return nil, err
+	}
+	sa, err := pipeSecurity(sid)
+	if err != nil {
+		return nil, err
+	}
+	l := &pipeListener{name: name, path: path, sa: sa}
+	if l.next, err = l.createInstance(true); err != nil {
+		_, _ = syscall.LocalFree(syscall.Handle(sa.SecurityDescriptor))
+		return nil, err
+	}
+	return l, nil
+}
+
+// pipeUserSID returns the SID of the Windows account user, given as a name
+// or a SID, or of the user running the process when user is empty.
+func pipeUserSID(user string) (string, error) {
+	var sid *syscall.SID
+	var err error
+	switch {
+	case user == "":
+		token, err := syscall.OpenCurrentProcessToken()
+		if err != nil {
+			return "", err
+		}
+		defer token.Close()
+		tokenUser, err := token.GetTokenUser()
+		if err != nil {
+			return "", err
+		}
+		sid = tokenUser.User.Sid
+	case strings.HasPrefix(strings.ToUpper(user), "S-1-"):
+		if sid, err = syscall.StringToSid(user); err != nil {
+			return "", fmt.Errorf("invalid pipe user SID %q: %w", user, err)
+		}
+	default:
+		if sid, _, _, err = syscall.LookupSID("", user); err != nil {
+			return "", fmt.Errorf("unknown pipe user %q: %w", user, err)
+		}
+	}
+	return sid.String()
+}
+
+// pipeSecurity returns security attributes granting the account sid and the
+// SYSTEM account full access and everyone else none. Without them, a pipe
+// created by a service running as SYSTEM would only be readable by other
+// users.
+func pipeSecurity(sid string) (*syscall.SecurityAttributes, error) {
+	sddl, err := syscall.UTF16PtrFromString("D:P(A;;GA;;;SY)(A;;GA;;;" + sid + ")")
+	if err != nil {
+		return nil, err
+	}
+	var sd uintptr
+	r, _, err := procConvertStringSecurityDescriptorToSecurityDescriptorW.Call(
+		uintptr(unsafe.Pointer(sddl)),
+		sddlRevision1,
+		uintptr(unsafe.Pointer(&sd)),
+		0,
+	)
+	if r == 0 {
+		return nil, fmt.Errorf("building the pipe security descriptor: %w", err)
+	}
+	sa := &syscall.SecurityAttributes{SecurityDescriptor: sd}
+	sa.Length = uint32(unsafe.Sizeof(*sa))
+	return sa, nil
+}
+
+// createInstance creates an instance of the pipe for the next client.
+func (l *pipeListener) createInstance(first bool) (syscall.Handle, error) {
+	mode := uint32(pipeAccessDuplex)
+	if first {
+		mode |= fileFlagFirstPipeInstance
+	}
+	r, _, err := procCreateNamedPipeW.Call(
+		uintptr(unsafe.Pointer(l.path)),
+		uintptr(mode),
+		pipeTypeByte|pipeReadmodeByte|pipeWait|pipeRejectRemoteClients,
+		pipeUnlimitedInstances,
+		pipeBufferSize,
+		pipeBufferSize,
+		0,
+		uintptr(unsafe.Pointer(l.sa)),
+	)
+	if h := syscall.Handle(r); h != syscall.InvalidHandle {
+		return h, nil
+	}
+	return 0, err
+}
+
+// Accept waits for the next client to open the pipe.
+func (l *pipeListener) Accept() (net.Conn, error) {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, net.ErrClosed
+	}
+	if l.next == 0 {
+		h, err := l.createInstance(false)
+		if err != nil {
+			l.mu.Unlock()
+			return nil, err
+		}
+		l.next = h
+	}
+	h := l.next
+	l.accepting = true
+	l.mu.Unlock()
+
+	r, _, err := procConnectNamedPipe.Call(uintptr(h), 0)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.accepting = false
+	l.next = 0
+	if l.closed {
+		_ = syscall.CloseHandle(h)
+		return nil, net.ErrClosed
+	}
+	if r == 0 && err != errorPipeConnected {
+		_ = syscall.CloseHandle(h)
+		return nil, &net.OpError{Op: "accept", Net: "pipe", Addr: pipeAddr(l.name), Err: err}
+	}
+	return &pipeConn{File: os.NewFile(uintptr(h), l.name), handle: h, addr: pipeAddr(l.name)}, nil
+}
+
+// Close stops accepting clients. A client already accepted stays connected.
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	switch {
+	case l.accepting:
+		// ConnectNamedPipe cannot be cancelled, so connect to the pipe to
+		// end it; Accept then closes the instance.
+		if h, err := syscall.CreateFile(l.path, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_EXISTING, 0, 0); err == nil {
+			_ = syscall.CloseHandle(h)
+		}
+	case l.next != 0:
+		_ = syscall.CloseHandle(l.next)
+		l.next = 0
+	}
+	// Instances already created keep their own copy of the descriptor.
+	_, _ = syscall.LocalFree(syscall.Handle(l.sa.SecurityDescriptor))
+	return nil
+}
+
+// Addr returns the pipe name.
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr(l.name)
+}
+
+// pipeConn is the server end of a connected pipe instance.
+type pipeConn struct {
+	*os.File
+	handle syscall.Handle
+	addr   pipeAddr
+	once   sync.Once
+}
+
+// Close disconnects the client and closes the instance. Disconnecting
+// first ends a Read blocked on the client, which would otherwise hold up
+// closing the file.
+func (c *pipeConn) Close() error {
+	var err error
+	c.once.Do(func() {
+		_, _, _ = procDisconnectNamedPipe.Call(uintptr(c.handle))
+		err = c.File.Close()
+	})
+	return err
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return c.addr }
+func (c *pipeConn) RemoteAddr() net.Addr { return c.addr }
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// errNoServices is returned by the service flags outside Windows, where
+// systemd or launchd run the server instead.
+var errNoServices = errors.New("Windows services are only supported on Windows")
+
+func installService(string, string, string) error {
+	return errNoServices
+}
+
+func uninstallService() error {
+	return errNoServices
+}
+
+func runService(func(ctx context.Context) int) int {
+	fmt.Fprintf(os.Stderr, "service error: %v\n", errNoServices)
+	return 1
+}
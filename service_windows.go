@@ -0,0 +1,291 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/rgabriel/mcp-todoist/config"
+)
+
+var (
+	advapi32                         = syscall.NewLazyDLL("advapi32.dll")
+	procOpenSCManagerW               = advapi32.NewProc("OpenSCManagerW")
+	procCreateServiceW               = advapi32.NewProc("CreateServiceW")
+	procOpenServiceW                 = advapi32.NewProc("OpenServiceW")
+	procDeleteService                = advapi32.NewProc("DeleteService")
+	procCloseServiceHandle           = advapi32.NewProc("CloseServiceHandle")
+	procStartServiceCtrlDispatcherW  = advapi32.NewProc("StartServiceCtrlDispatcherW")
+	procRegisterServiceCtrlHandlerEx = advapi32.NewProc("RegisterServiceCtrlHandlerExW")
+	procSetServiceStatus             = advapi32.NewProc("SetServiceStatus")
+)
+
+// The service is installed as serviceName, which also names its log file,
+// and listed as serviceDisplayName.
+const (
+	serviceName        = "mcp-todoist"
+	serviceDisplayName = "Todoist MCP Server"
+)
+
+// Service manager access rights, states, and controls, from winsvc.h.
+const (
+	scManagerConnect       = 0x0001
+	scManagerCreateService = 0x0002
+	serviceAllAccess       = 0xF01FF
+	serviceDelete          = 0x10000
+
+	serviceWin32OwnProcess = 0x10
+	serviceAutoStart       = 2
+	serviceErrorNormal     = 1
+
+	serviceStopped      = 1
+	serviceStartPending = 2
+	serviceStopPending  = 3
+	serviceRunning      = 4
+
+	serviceAcceptStop     = 0x1
+	serviceAcceptShutdown = 0x4
+
+	serviceControlStop        = 1
+	serviceControlInterrogate = 4
+	serviceControlShutdown    = 5
+
+	errorCallNotImplemented = 120
+	// errorServiceSpecific reports the exit code in ServiceSpecificExitCode.
+	errorServiceSpecific = 1066
+)
+
+// serviceStatus is SERVICE_STATUS.
+type serviceStatus struct {
+	ServiceType             uint32
+	CurrentState            uint32
+	ControlsAccepted        uint32
+	Win32ExitCode           uint32
+	ServiceSpecificExitCode uint32
+	CheckPoint              uint32
+	WaitHint                uint32
+}
+
+// serviceTableEntry is SERVICE_TABLE_ENTRYW.
+type serviceTableEntry struct {
+	ServiceName *uint16
+	ServiceProc uintptr
+}
+
+// openSCManager connects to the local service control manager.
+func openSCManager(access uint32) (syscall.Handle, error) {
+	r, _, err := procOpenSCManagerW.Call(0, 0, uintptr(access))
+	if r == 0 {
+		return 0, fmt.Errorf("connecting to the service manager: %w", err)
+	}
+	return syscall.Handle(r), nil
+}
+
+func closeServiceHandle(h syscall.Handle) {
+	_, _, _ = procCloseServiceHandle.Call(uintptr(h))
+}
+
+// installService registers this executable as an automatically started
+// service that runs with --service and the given transport and listen
+// address. A service has no client on stdio, so transport must be sse,
+// http, or pipe. The service runs as SYSTEM, so with pipe the pipe is opened
+// to pipeUser, or to the installing user when it is empty, by its SID.
+func installService(transport, listen, pipeUser string) error {
+	switch config.NormalizeTransport(transport) {
+	case config.TransportSSE, config.TransportHTTP, config.TransportPipe:
+	default:
+		return fmt.Errorf("a service needs --transport sse, http, or pipe")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{syscall.EscapeArg(exe), "--service", "--transport", syscall.EscapeArg(transport)}
+	if listen != "" {
+		args = append(args, "--listen", syscall.EscapeArg(listen))
+	}
+	if config.NormalizeTransport(transport) == config.TransportPipe {
+		sid, err := pipeUserSID(pipeUser)
+		if err != nil {
+			return err
+		}
+		args = append(args, "--pipe-user", sid)
+	}
+
+	scm, err := openSCManager(scManagerConnect | scManagerCreateService)
+	if err != nil {
+		return err
+	}
+	defer closeServiceHandle(scm)
+	name, _ := syscall.UTF16PtrFromString(serviceName)
+	display, _ := syscall.UTF16PtrFromString(serviceDisplayName)
+	cmdline, err := syscall.UTF16PtrFromString(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	r, _, err := procCreateServiceW.Call(
+		uintptr(scm),
+		uintptr(unsafe.Pointer(name)),
+		uintptr(unsafe.Pointer(display)),
+		serviceAllAccess,
+		serviceWin32OwnProcess,
+		serviceAutoStart,
+		serviceErrorNormal,
+		uintptr(unsafe.Pointer(cmdline)),
+		0, 0, 0, 0, 0,
+	)
+	if r == 0 {
+		return fmt.Errorf("creating service %s: %w", serviceName, err)
+	}
+	closeServiceHandle(syscall.Handle(r))
+	slog.Info("service installed", "name", serviceName, "command", strings.Join(args, " "))
+	return nil
+}
+
+// uninstallService removes the service installed by installService. A
+// running service is removed once it stops.
+func uninstallService() error {
+	scm, err := openSCManager(scManagerConnect)
+	if err != nil {
+		return err
+	}
+	defer closeServiceHandle(scm)
+	name, _ := syscall.UTF16PtrFromString(serviceName)
+	r, _, err := procOpenServiceW.Call(uintptr(scm), uintptr(unsafe.Pointer(name)), serviceDelete)
+	if r == 0 {
+		return fmt.Errorf("opening service %s: %w", serviceName, err)
+	}
+	h := syscall.Handle(r)
+	defer closeServiceHandle(h)
+	if r, _, err := procDeleteService.Call(uintptr(h)); r == 0 {
+		return fmt.Errorf("deleting service %s: %w", serviceName, err)
+	}
+	slog.Info("service uninstalled", "name", serviceName)
+	return nil
+}
+
+// windowsService is the state shared between the service's main function
+// and its control handler, which the service manager calls on its own
+// threads.
+type windowsService struct {
+	run    func(ctx context.Context) int
+	handle uintptr
+	ctx    context.Context
+	stop   context.CancelFunc
+	code   int
+
+	mu     sync.Mutex
+	status serviceStatus
+}
+
+var svc *windowsService
+
+// runService runs the server under the Windows service manager until it
+// asks the service to stop. The working directory becomes the directory of
+// the executable, so a .env file next to it is read, and logs are appended
+// to mcp-todoist.log there, since a service has no console.
+func runService(run func(ctx context.Context) int) int {
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "service error: %v\n", err)
+		return 1
+	}
+	dir := filepath.Dir(exe)
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "service error: %v\n", err)
+		return 1
+	}
+	logFile, err := os.OpenFile(filepath.Join(dir, serviceName+".log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "service error: %v\n", err)
+		return 1
+	}
+	defer logFile.Close()
+	setupLogger(logFile)
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	svc = &windowsService{run: run, ctx: ctx, stop: stop}
+
+	name, _ := syscall.UTF16PtrFromString(serviceName)
+	table := []serviceTableEntry{
+		{ServiceName: name, ServiceProc: syscall.NewCallback(serviceMain)},
+		{},
+	}
+	// Blocks until the service has stopped.
+	if r, _, err := procStartServiceCtrlDispatcherW.Call(uintptr(unsafe.Pointer(&table[0]))); r == 0 {
+		slog.Error("service error", "error", fmt.Errorf("connecting to the service manager (--service is set by --install-service): %w", err))
+		return 1
+	}
+	return svc.code
+}
+
+// serviceMain is the service's ServiceMain, called by the service manager
+// once the dispatcher has started.
+func serviceMain(_, _ uintptr) uintptr {
+	name, _ := syscall.UTF16PtrFromString(serviceName)
+	h, _, err := procRegisterServiceCtrlHandlerEx.Call(uintptr(unsafe.Pointer(name)), syscall.NewCallback(serviceControl), 0)
+	if h == 0 {
+		slog.Error("service error", "error", fmt.Errorf("registering control handler: %w", err))
+		return 0
+	}
+	svc.handle = h
+	svc.setState(serviceStartPending, 0, 0)
+
+	done := make(chan struct{})
+	go func() {
+		svc.code = svc.run(svc.ctx)
+		close(done)
+	}()
+	svc.setState(serviceRunning, serviceAcceptStop|serviceAcceptShutdown, 0)
+	<-done
+
+	svc.setState(serviceStopped, 0, svc.code)
+	return 0
+}
+
+// serviceControl is the service's HandlerEx.
+func serviceControl(control, _, _, _ uintptr) uintptr {
+	switch control {
+	case serviceControlStop, serviceControlShutdown:
+		svc.setState(serviceStopPending, 0, 0)
+		svc.stop()
+	case serviceControlInterrogate:
+		// The service manager already knows the current state.
+	default:
+		return errorCallNotImplemented
+	}
+	return 0
+}
+
+// setState reports state and the controls it accepts to the service
+// manager, with code as the service's exit code once it has stopped.
+func (s *windowsService) setState(state, accepts uint32, code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.ServiceType = serviceWin32OwnProcess
+	s.status.CurrentState = state
+	s.status.ControlsAccepted = accepts
+	s.status.Win32ExitCode = 0
+	s.status.ServiceSpecificExitCode = 0
+	if code != 0 {
+		s.status.Win32ExitCode = errorServiceSpecific
+		s.status.ServiceSpecificExitCode = uint32(code)
+	}
+	if state == serviceStartPending || state == serviceStopPending {
+		s.status.CheckPoint++
+		s.status.WaitHint = uint32(shutdownTimeout.Milliseconds()) + 5000
+	} else {
+		s.status.CheckPoint = 0
+		s.status.WaitHint = 0
+	}
+	_, _, _ = procSetServiceStatus.Call(s.handle, uintptr(unsafe.Pointer(&s.status)))
+}
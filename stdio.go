@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-todoist/tools"
+)
+
+// serveStdio runs the MCP server over stdin and stdout, with stdin
+// normalized by newStdinReader and completion requests answered by completer.
+// It returns nil when the client disconnects, by closing either stdin or
+// stdout, or the process is interrupted or ctx ends, so that shutdown cleanup
+// runs in every case.
+func serveStdio(ctx context.Context, s *server.MCPServer, completer *tools.Completer) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// Writing to a closed stdout would otherwise kill the process with SIGPIPE.
+	signal.Ignore(syscall.SIGPIPE)
+
+	return serveStream(ctx, s, completer, os.Stdin, os.Stdout)
+}
+
+// serveStream speaks the stdio protocol with one client over r and w until
+// the client disconnects or ctx ends, both of which return nil.
+func serveStream(ctx context.Context, s *server.MCPServer, completer *tools.Completer, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	out := &lockedWriter{w: &disconnectWriter{w: w, disconnect: cancel}}
+	in := newCompletionInterceptor(ctx, newStdinReader(r), out, completer)
+
+	err := server.NewStdioServer(s).Listen(ctx, in, out)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// disconnectWriter calls disconnect once a write fails, which for stdout
+// means the client has gone away.
+type disconnectWriter struct {
+	w          io.Writer
+	disconnect func()
+}
+
+func (d *disconnectWriter) Write(p []byte) (int, error) {
+	n, err := d.w.Write(p)
+	if err != nil {
+		d.disconnect()
+	}
+	return n, err
+}
+
+// newStdinReader undoes what Windows shells and consoles do to piped input:
+// a UTF-8 byte order mark is skipped, UTF-16 input (PowerShell's default
+// encoding, detected by its byte order mark) is converted to UTF-8, and
+// carriage returns are dropped so CRLF line endings read as LF. A raw CR is
+// only ever whitespace in JSON, so dropping it cannot change a message.
+func newStdinReader(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	switch bom, _ := br.Peek(3); {
+	case bytes.HasPrefix(bom, []byte{0xEF, 0xBB, 0xBF}):
+		_, _ = br.Discard(3)
+	case bytes.HasPrefix(bom, []byte{0xFF, 0xFE}):
+		_, _ = br.Discard(2)
+		src = &utf16Reader{r: br, littleEndian: true}
+	case bytes.HasPrefix(bom, []byte{0xFE, 0xFF}):
+		_, _ = br.Discard(2)
+		src = &utf16Reader{r: br}
+	}
+	return &crlfReader{r: src}
+}
+
+// crlfReader drops carriage returns.
+type crlfReader struct {
+	r io.Reader
+}
+
+func (c *crlfReader) Read(p []byte) (int, error) {
+	for {
+		n, err := c.r.Read(p)
+		kept := 0
+		for _, b := range p[:n] {
+			if b != '\r' {
+				p[kept] = b
+				kept++
+			}
+		}
+		// Never report (0, nil) for a read that only held CRs.
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
+
+// utf16Reader converts a UTF-16 byte stream to UTF-8.
+type utf16Reader struct {
+	r            *bufio.Reader
+	littleEndian bool
+	pending      []byte
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	for len(u.pending) == 0 {
+		unit, err := u.readUnit()
+		if err != nil {
+			return 0, err
+		}
+		r := rune(unit)
+		if utf16.IsSurrogate(r) {
+			low, err := u.readUnit()
+			if err != nil {
+				return 0, err
+			}
+			r = utf16.DecodeRune(r, rune(low))
+		}
+		u.pending = utf8.AppendRune(u.pending, r)
+	}
+	n := copy(p, u.pending)
+	u.pending = u.pending[n:]
+	return n, nil
+}
+
+func (u *utf16Reader) readUnit() (uint16, error) {
+	var b [2]byte
+	if _, err := io.ReadFull(u.r, b[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		return 0, err
+	}
+	if u.littleEndian {
+		return uint16(b[0]) | uint16(b[1])<<8, nil
+	}
+	return uint16(b[0])<<8 | uint16(b[1]), nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func encodeUTF16(s string, littleEndian bool) []byte {
+	var buf bytes.Buffer
+	if littleEndian {
+		buf.Write([]byte{0xFF, 0xFE})
+	} else {
+		buf.Write([]byte{0xFE, 0xFF})
+	}
+	for _, unit := range utf16.Encode([]rune(s)) {
+		if littleEndian {
+			buf.Write([]byte{byte(unit), byte(unit >> 8)})
+		} else {
+			buf.Write([]byte{byte(unit >> 8), byte(unit)})
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestNewStdinReader(t *testing.T) {
+	const want = "{\"jsonrpc\":\"2.0\",\"method\":\"ping\",\"params\":{\"q\":\"Café 🚀\"}}\n{\"id\":2}\n"
+	crlf := strings.ReplaceAll(want, "\n", "\r\n")
+
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"plain", []byte(want)},
+		{"CRLF line endings", []byte(crlf)},
+		{"UTF-8 BOM", append([]byte{0xEF, 0xBB, 0xBF}, crlf...)},
+		{"UTF-16LE", encodeUTF16(crlf, true)},
+		{"UTF-16BE", encodeUTF16(crlf, false)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := io.ReadAll(newStdinReader(bytes.NewReader(tt.input)))
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			if string(got) != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
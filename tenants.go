@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/config"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools"
+)
+
+const (
+	// tenantIdleTimeout is how long the server of a per-request token is
+	// kept after its last request ended.
+	tenantIdleTimeout = time.Hour
+	// maxTenants bounds the per-request token servers kept at once.
+	maxTenants = 100
+)
+
+// tenantKey identifies a token in logs and directory names without
+// revealing it.
+func tenantKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// tenant is the server of one per-request token.
+type tenant struct {
+	// ready is closed once handler and groups are set, or err is.
+	ready   chan struct{}
+	handler http.Handler
+	groups  *tools.ToolGroups
+	err     error
+
+	active   int
+	lastUsed time.Time
+}
+
+// tenantRouter serves each HTTP request with the server of the Todoist token
+// in its "Authorization: Bearer" header. Every token gets its own server,
+// built on first use: its own API clients and rate limiter, and its own
+// journal, plans, and other stores, so users sharing the process never see
+// each other's data. Requests without a token use the configured token,
+// unless tokens are required.
+type tenantRouter struct {
+	required bool
+	// build verifies token and builds its server and MCP transport.
+	build func(ctx context.Context, token string, disabled []string) (http.Handler, *tools.ToolGroups, error)
+
+	mu       sync.Mutex
+	tenants  map[string]*tenant
+	disabled []string
+}
+
+// newTenantRouter creates a router for the PER_REQUEST_TOKENS mode, building
+// servers with build and the initially disabled tool groups.
+func newTenantRouter(mode string, disabled []string, build func(ctx context.Context, token string, disabled []string) (http.Handler, *tools.ToolGroups, error)) *tenantRouter {
+	return &tenantRouter{
+		required: mode == config.TokensRequired,
+		build:    build,
+		tenants:  make(map[string]*tenant),
+		disabled: disabled,
+	}
+}
+
+// Handler routes requests with a token to that token's server and the rest
+// to fallback.
+func (r *tenantRouter) Handler(fallback http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			if r.required {
+				http.Error(w, "a Todoist API token is required: send Authorization: Bearer <token>", http.StatusUnauthorized)
+				return
+			}
+			fallback.ServeHTTP(w, req)
+			return
+		}
+		token = strings.TrimSpace(token)
+		if err := config.ValidateToken(token); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		t, err := r.acquire(req.Context(), token)
+		switch {
+		case errors.Is(err, todoist.ErrUnauthorized):
+			http.Error(w, "Todoist rejected the API token", http.StatusUnauthorized)
+			return
+		case errors.Is(err, errTooManyTenants):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		case err != nil:
+			http.Error(w, fmt.Sprintf("failed to connect to Todoist: %v", err), http.StatusBadGateway)
+			return
+		}
+		defer r.release(t)
+		t.handler.ServeHTTP(w, req)
+	})
+}
+
+var errTooManyTenants = errors.New("too many API tokens in use; try again later")
+
+// acquire returns the server of token, building it on first use. Concurrent
+// first requests wait for a single build; a failed build is not kept.
+func (r *tenantRouter) acquire(ctx context.Context, token string) (*tenant, error) {
+	key := tenantKey(token)
+
+	r.mu.Lock()
+	t, ok := r.tenants[key]
+	if !ok {
+		r.evictLocked()
+		if len(r.tenants) >= maxTenants {
+			r.mu.Unlock()
+			return nil, errTooManyTenants
+		}
+		t = &tenant{ready: make(chan struct{})}
+		r.tenants[key] = t
+		disabled := r.disabled
+		go func() {
+			// The build outlives the request that started it, so that
+			// others waiting for it are not failed by its cancellation.
+			handler, groups, err := r.build(context.WithoutCancel(ctx), token, disabled)
+			r.mu.Lock()
+			t.handler, t.groups, t.err = handler, groups, err
+			if err != nil {
+				delete(r.tenants, key)
+			} else {
+				slog.Info("serving per-request token", "tenant", key)
+			}
+			r.mu.Unlock()
+			close(t.ready)
+		}()
+	}
+	t.active++
+	r.mu.Unlock()
+
+	select {
+	case <-t.ready:
+	case <-ctx.Done():
+		r.release(t)
+		return nil, ctx.Err()
+	}
+	if t.err != nil {
+		r.release(t)
+		return nil, t.err
+	}
+	return t, nil
+}
+
+// release marks a request to t as finished.
+func (r *tenantRouter) release(t *tenant) {
+	r.mu.Lock()
+	t.active--
+	t.lastUsed = time.Now()
+	r.mu.Unlock()
+}
+
+// evictLocked drops servers without requests in flight that have been idle
+// for tenantIdleTimeout, and when still full, the longest idle one.
+func (r *tenantRouter) evictLocked() {
+	var oldestKey string
+	var oldest time.Time
+	for key, t := range r.tenants {
+		if t.active > 0 || t.groups == nil {
+			continue
+		}
+		if time.Since(t.lastUsed) > tenantIdleTimeout {
+			delete(r.tenants, key)
+			continue
+		}
+		if oldestKey == "" || t.lastUsed.Before(oldest) {
+			oldestKey, oldest = key, t.lastUsed
+		}
+	}
+	if len(r.tenants) >= maxTenants && oldestKey != "" {
+		delete(r.tenants, oldestKey)
+	}
+}
+
+// ApplyDisabledGroups hides tool groups on every server, including those
+// built later.
+func (r *tenantRouter) ApplyDisabledGroups(disabled []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tenants {
+		if t.groups == nil {
+			continue
+		}
+		if err := t.groups.Apply(disabled); err != nil {
+			return err
+		}
+	}
+	r.disabled = disabled
+	return nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-todoist/config"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools"
+)
+
+const (
+	tokenA = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	tokenB = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+// fakeTenants builds servers that answer with the tenant key of their token
+// and counts the builds per token.
+type fakeTenants struct {
+	mu      sync.Mutex
+	builds  map[string]int
+	err     error
+	servers []*server.MCPServer
+}
+
+func (f *fakeTenants) build(ctx context.Context, token string, disabled []string) (http.Handler, *tools.ToolGroups, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.builds == nil {
+		f.builds = make(map[string]int)
+	}
+	f.builds[token]++
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	srv := server.NewMCPServer("test", "test", server.WithToolCapabilities(true))
+	groups := tools.NewToolGroups(srv)
+	groups.Add("tasks", mcp.NewTool("create_task"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, nil
+	})
+	if err := groups.Apply(disabled); err != nil {
+		return nil, nil, err
+	}
+	f.servers = append(f.servers, srv)
+	key := tenantKey(token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, key)
+	}), groups, nil
+}
+
+var fallbackHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	_, _ = fmt.Fprint(w, "fallback")
+})
+
+func serveTenant(h http.Handler, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("{}"))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTenantRouter_Routing(t *testing.T) {
+	f := &fakeTenants{}
+	h := newTenantRouter(config.TokensOptional, nil, f.build).Handler(fallbackHandler)
+
+	if rec := serveTenant(h, ""); rec.Body.String() != "fallback" {
+		t.Errorf("request without token = %d %q, want fallback", rec.Code, rec.Body.String())
+	}
+	for i := 0; i < 3; i++ {
+		if rec := serveTenant(h, tokenA); rec.Body.String() != tenantKey(tokenA) {
+			t.Errorf("token A = %d %q", rec.Code, rec.Body.String())
+		}
+	}
+	if rec := serveTenant(h, tokenB); rec.Body.String() != tenantKey(tokenB) {
+		t.Errorf("token B = %d %q", rec.Code, rec.Body.String())
+	}
+	if f.builds[tokenA] != 1 || f.builds[tokenB] != 1 {
+		t.Errorf("expected one build per token, got %v", f.builds)
+	}
+	if rec := serveTenant(h, "short"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("malformed token = %d, want 401", rec.Code)
+	}
+}
+
+func TestTenantRouter_Required(t *testing.T) {
+	f := &fakeTenants{}
+	h := newTenantRouter(config.TokensRequired, nil, f.build).Handler(fallbackHandler)
+
+	if rec := serveTenant(h, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("request without token = %d, want 401", rec.Code)
+	}
+	if rec := serveTenant(h, tokenA); rec.Code != http.StatusOK {
+		t.Errorf("token A = %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestTenantRouter_BuildErrors(t *testing.T) {
+	f := &fakeTenants{err: fmt.Errorf("connection test: %w", todoist.ErrUnauthorized)}
+	h := newTenantRouter(config.TokensOptional, nil, f.build).Handler(fallbackHandler)
+
+	if rec := serveTenant(h, tokenA); rec.Code != http.StatusUnauthorized {
+		t.Errorf("rejected token = %d, want 401", rec.Code)
+	}
+	// Failed builds are not kept, so the next request tries again.
+	f.err = fmt.Errorf("connection refused")
+	if rec := serveTenant(h, tokenA); rec.Code != http.StatusBadGateway {
+		t.Errorf("unreachable API = %d, want 502", rec.Code)
+	}
+	if f.builds[tokenA] != 2 {
+		t.Errorf("expected failed builds to be retried, got %d builds", f.builds[tokenA])
+	}
+}
+
+func TestTenantRouter_TooManyTenants(t *testing.T) {
+	f := &fakeTenants{}
+	r := newTenantRouter(config.TokensOptional, nil, f.build)
+	var held []*tenant
+	for i := 0; i < maxTenants; i++ {
+		tn, err := r.acquire(context.Background(), fmt.Sprintf("%040d", i))
+		if err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+		held = append(held, tn)
+	}
+
+	if rec := serveTenant(r.Handler(fallbackHandler), tokenA); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("token beyond limit = %d, want 503", rec.Code)
+	}
+
+	// Once a server is idle, it makes room for a new token.
+	r.release(held[0])
+	if rec := serveTenant(r.Handler(fallbackHandler), tokenA); rec.Code != http.StatusOK {
+		t.Errorf("token after release = %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestTenantRouter_ApplyDisabledGroups(t *testing.T) {
+	f := &fakeTenants{}
+	r := newTenantRouter(config.TokensOptional, nil, f.build)
+	h := r.Handler(fallbackHandler)
+	serveTenant(h, tokenA)
+
+	if err := r.ApplyDisabledGroups([]string{"tasks"}); err != nil {
+		t.Fatalf("ApplyDisabledGroups: %v", err)
+	}
+	// Servers built afterwards start with the groups disabled too.
+	serveTenant(h, tokenB)
+	if len(f.servers) != 2 {
+		t.Fatalf("expected 2 servers, got %d", len(f.servers))
+	}
+	for i, srv := range f.servers {
+		if n := len(srv.ListTools()); n != 0 {
+			t.Errorf("server %d has %d tools after disabling tasks", i, n)
+		}
+	}
+}
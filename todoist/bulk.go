@@ -0,0 +1,442 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// DefaultSyncBatchThreshold is the operation count above which the Sync
+	// API is used unless BULK_SYNC_THRESHOLD says otherwise: one Sync request
+	// replaces several REST requests.
+	DefaultSyncBatchThreshold = 5
+	// MaxSyncCommands is the Sync API's limit on commands per request.
+	MaxSyncCommands = 100
+)
+
+// Strategy names reported in BulkResult. StrategyAuto, the default, lets
+// the executor choose; it is never reported.
+const (
+	StrategyREST = "rest"
+	StrategySync = "sync"
+	StrategyAuto = "auto"
+)
+
+// Strategies lists the values accepted by WithStrategy.
+var Strategies = []string{StrategyAuto, StrategyREST, StrategySync}
+
+// syncBatchThreshold is the operation count above which StrategyAuto uses
+// the Sync API.
+var syncBatchThreshold = DefaultSyncBatchThreshold
+
+// SetSyncBatchThreshold sets the operation count above which bulk changes
+// use the Sync API when the caller leaves the strategy to the executor.
+func SetSyncBatchThreshold(n int) {
+	syncBatchThreshold = n
+}
+
+type strategyKey struct{}
+
+// WithStrategy returns a context whose bulk executions use strategy, one of
+// Strategies, instead of choosing between REST and Sync. Some tokens and
+// workspaces behave differently under one of the APIs.
+func WithStrategy(ctx context.Context, strategy string) context.Context {
+	return context.WithValue(ctx, strategyKey{}, strategy)
+}
+
+// strategyFrom returns the bulk strategy carried by ctx.
+func strategyFrom(ctx context.Context) string {
+	if s, ok := ctx.Value(strategyKey{}).(string); ok && s != "" {
+		return s
+	}
+	return StrategyAuto
+}
+
+type progressKey struct{}
+
+// WithProgress returns a context whose bulk executions call report after each
+// request with the number of operations attempted so far and the total, so
+// long-running jobs can show how far they got.
+func WithProgress(ctx context.Context, report func(done, total int)) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// reportProgress calls the progress function carried by ctx, if any.
+func reportProgress(ctx context.Context, done, total int) {
+	if report, ok := ctx.Value(progressKey{}).(func(done, total int)); ok {
+		report(done, total)
+	}
+}
+
+// BulkOperation is one change to a single entity. It carries a Sync command,
+// a REST request, or both; the executor picks which form to send.
+type BulkOperation struct {
+	// ID identifies the entity in results.
+	ID string
+	// Command is the Sync API form. A zero Type means the operation has no Sync form.
+	Command Command
+	// Method and Path are the REST form (e.g. POST /tasks/123/close). An empty
+	// Path means the operation has no REST form.
+	Method string
+	Path   string
+	Body   interface{}
+	// Revert undoes the change, for rolling back a failed run; nil when the
+	// change cannot be undone. Its ID is ignored.
+	Revert *BulkOperation
+}
+
+func (op BulkOperation) hasSync() bool { return op.Command.Type != "" }
+func (op BulkOperation) hasREST() bool { return op.Path != "" }
+
+// BulkFailure records an operation that did not apply.
+type BulkFailure struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// BulkResult reports the outcome of a bulk execution. Every operation appears
+// in exactly one of Succeeded or Failed.
+type BulkResult struct {
+	Strategy  string
+	Succeeded []string
+	Failed    []BulkFailure
+}
+
+// FailedIDs returns the IDs of failed operations.
+func (r *BulkResult) FailedIDs() []string {
+	ids := make([]string, len(r.Failed))
+	for i, f := range r.Failed {
+		ids[i] = f.ID
+	}
+	return ids
+}
+
+// BulkExecutor applies bulk operations through the REST or Sync API.
+type BulkExecutor struct {
+	client     API
+	syncClient SyncAPI
+}
+
+// NewBulkExecutor creates an executor using the given clients.
+func NewBulkExecutor(client API, syncClient SyncAPI) *BulkExecutor {
+	return &BulkExecutor{client: client, syncClient: syncClient}
+}
+
+// chooseStrategy picks REST or Sync for ops. With StrategyAuto, Sync is used
+// when any operation lacks a REST form, when the batch is larger than the
+// sync batch threshold, or when the REST budget is too small for one request
+// per operation. A requested strategy is used as is, or fails when some
+// operation has no form for it. An error is also returned when the chosen
+// API has too little rate limit headroom.
+func (e *BulkExecutor) chooseStrategy(ops []BulkOperation, requested string) (string, error) {
+	canREST, canSync := true, true
+	for _, op := range ops {
+		canREST = canREST && op.hasREST()
+		canSync = canSync && op.hasSync()
+	}
+	if !canREST && !canSync {
+		return "", fmt.Errorf("operations mix REST-only and Sync-only changes")
+	}
+
+	syncRequests := syncRequestCount(len(ops))
+	syncFits := canSync && e.syncClient.GetRemainingRequests() >= syncRequests
+	restRemaining := e.client.GetRemainingRequests()
+	restFits := canREST && restRemaining >= len(ops)
+
+	switch requested {
+	case StrategyREST:
+		if !canREST {
+			return "", fmt.Errorf("strategy rest is not available: some of these changes can only be made through the Sync API")
+		}
+		canSync, syncFits = false, false
+	case StrategySync:
+		if !canSync {
+			return "", fmt.Errorf("strategy sync is not available: some of these changes can only be made through the REST API")
+		}
+		canREST, restFits = false, false
+	case StrategyAuto:
+	default:
+		return "", fmt.Errorf("unknown bulk strategy %q", requested)
+	}
+
+	switch {
+	case syncFits && (!canREST || len(ops) > syncBatchThreshold || !restFits):
+		return StrategySync, nil
+	case restFits:
+		return StrategyREST, nil
+	case canSync:
+		return "", fmt.Errorf("insufficient rate limit capacity: need %d requests, have %d remaining in 15min window", syncRequests, e.syncClient.GetRemainingRequests())
+	default:
+		return "", fmt.Errorf("insufficient rate limit capacity: need %d requests, have %d remaining in 15min window", len(ops), restRemaining)
+	}
+}
+
+// CostEstimate predicts the requests Execute would send for a set of
+// operations, against the rate limit budget currently remaining.
+type CostEstimate struct {
+	Operations    int    `json:"operations"`
+	Strategy      string `json:"strategy,omitempty"`
+	RESTRequests  int    `json:"rest_requests"`
+	SyncRequests  int    `json:"sync_requests"`
+	RESTRemaining int    `json:"rest_remaining"`
+	SyncRemaining int    `json:"sync_remaining"`
+	WithinBudget  bool   `json:"within_budget"`
+	Message       string `json:"message,omitempty"`
+}
+
+// Estimate reports how Execute would apply ops without sending anything. When
+// neither API has enough headroom, Strategy is empty, WithinBudget is false,
+// and the request counts are those of the cheapest form, i.e. the budget to
+// wait for.
+func (e *BulkExecutor) Estimate(ctx context.Context, ops []BulkOperation) CostEstimate {
+	est := CostEstimate{
+		Operations:    len(ops),
+		RESTRemaining: e.client.GetRemainingRequests(),
+		SyncRemaining: e.syncClient.GetRemainingRequests(),
+	}
+	if len(ops) == 0 {
+		est.Strategy = StrategyREST
+		est.WithinBudget = true
+		return est
+	}
+
+	requested := strategyFrom(ctx)
+	strategy, err := e.chooseStrategy(ops, requested)
+	if err == nil {
+		est.Strategy = strategy
+		est.WithinBudget = true
+	} else {
+		// Mirror chooseStrategy: the requested API when every operation has
+		// a form for it, otherwise Sync, the cheaper form whenever every
+		// operation has one.
+		est.Message = err.Error()
+		canREST, canSync := true, true
+		for _, op := range ops {
+			canREST = canREST && op.hasREST()
+			canSync = canSync && op.hasSync()
+		}
+		switch {
+		case requested == StrategyREST && canREST:
+			strategy = StrategyREST
+		case requested == StrategySync && canSync:
+			strategy = StrategySync
+		case canSync:
+			strategy = StrategySync
+		default:
+			strategy = StrategyREST
+		}
+	}
+
+	if strategy == StrategySync {
+		est.SyncRequests = syncRequestCount(len(ops))
+	} else {
+		est.RESTRequests = len(ops)
+	}
+	return est
+}
+
+// syncRequestCount returns the number of Sync requests needed for n commands.
+func syncRequestCount(n int) int {
+	return (n + MaxSyncCommands - 1) / MaxSyncCommands
+}
+
+// Execute applies ops and reports per-operation results. Individual failures
+// never abort the run; an error is returned only when nothing was attempted
+// (no rate limit headroom, or ctx already done) or the first request failed
+// outright, in which case no operation was applied. When ctx is cancelled
+// mid-run, the operations not yet sent are reported as failed. Requests are
+// sent at PriorityBulk so interactive calls overtake them.
+func (e *BulkExecutor) Execute(ctx context.Context, ops []BulkOperation) (*BulkResult, error) {
+	result := &BulkResult{Succeeded: make([]string, 0, len(ops)), Failed: make([]BulkFailure, 0)}
+	if len(ops) == 0 {
+		result.Strategy = StrategyREST
+		return result, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	strategy, err := e.chooseStrategy(ops, strategyFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+	result.Strategy = strategy
+	ctx = WithPriority(ctx, PriorityBulk)
+
+	if strategy == StrategySync {
+		for start := 0; start < len(ops); start += MaxSyncCommands {
+			if err := ctx.Err(); err != nil {
+				result.failRemaining(ops[start:], err)
+				break
+			}
+			chunk := ops[start:min(start+MaxSyncCommands, len(ops))]
+			commands := make([]Command, len(chunk))
+			for i, op := range chunk {
+				commands[i] = op.Command
+			}
+
+			syncResp, err := e.syncClient.BatchCommands(ctx, commands)
+			if err != nil {
+				if start == 0 {
+					return nil, err
+				}
+				for _, op := range chunk {
+					result.Failed = append(result.Failed, BulkFailure{ID: op.ID, Error: err.Error()})
+				}
+				continue
+			}
+			for _, op := range chunk {
+				status := syncResp.SyncStatus[op.Command.UUID]
+				if s, ok := status.(string); ok && s == "ok" {
+					result.Succeeded = append(result.Succeeded, op.ID)
+				} else {
+					result.Failed = append(result.Failed, BulkFailure{ID: op.ID, Error: fmt.Sprintf("%v", status)})
+				}
+			}
+			reportProgress(ctx, start+len(chunk), len(ops))
+		}
+		return result, nil
+	}
+
+	for i, op := range ops {
+		if err := ctx.Err(); err != nil {
+			result.failRemaining(ops[i:], err)
+			break
+		}
+		var err error
+		switch op.Method {
+		case http.MethodDelete:
+			err = e.client.Delete(ctx, op.Path)
+		default:
+			_, err = e.client.Post(ctx, op.Path, op.Body)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, BulkFailure{ID: op.ID, Error: err.Error()})
+		} else {
+			result.Succeeded = append(result.Succeeded, op.ID)
+		}
+		reportProgress(ctx, i+1, len(ops))
+	}
+	return result, nil
+}
+
+// failRemaining reports ops, which were never sent, as failed with err.
+func (r *BulkResult) failRemaining(ops []BulkOperation, err error) {
+	for _, op := range ops {
+		r.Failed = append(r.Failed, BulkFailure{ID: op.ID, Error: err.Error()})
+	}
+}
+
+// Rollback undoes the operations of ops that result reports as succeeded,
+// newest first so that dependent changes are reverted before the ones they
+// build on. Operations without a Revert are reported as failed. The executor
+// chooses the strategy regardless of the one requested for the run, since
+// reverts may only have one form.
+func (e *BulkExecutor) Rollback(ctx context.Context, ops []BulkOperation, result *BulkResult) (*BulkResult, error) {
+	succeeded := make(map[string]bool, len(result.Succeeded))
+	for _, id := range result.Succeeded {
+		succeeded[id] = true
+	}
+
+	var reverts []BulkOperation
+	var irreversible []BulkFailure
+	for i := len(ops) - 1; i >= 0; i-- {
+		op := ops[i]
+		if !succeeded[op.ID] {
+			continue
+		}
+		if op.Revert == nil {
+			irreversible = append(irreversible, BulkFailure{ID: op.ID, Error: "change cannot be undone"})
+			continue
+		}
+		revert := *op.Revert
+		revert.ID = op.ID
+		reverts = append(reverts, revert)
+	}
+
+	reverted, err := e.Execute(WithStrategy(ctx, StrategyAuto), reverts)
+	if err != nil {
+		return nil, err
+	}
+	reverted.Failed = append(reverted.Failed, irreversible...)
+	return reverted, nil
+}
+
+// SendCommands sends Sync commands in requests of at most MaxSyncCommands,
+// for batches that may exceed one request. Temp IDs created by an earlier
+// request are replaced with their real IDs in the top-level arguments of
+// later commands, since the Sync API resolves temp IDs only within one
+// request. The statuses and temp ID mappings of all requests are merged. An
+// error is returned only when the first request failed; a later failed
+// request, or ctx being cancelled between requests, is recorded as the error
+// status of each command not applied. Progress is reported after each request.
+func SendCommands(ctx context.Context, syncClient SyncAPI, commands []Command) (*SyncResponse, error) {
+	merged := &SyncResponse{SyncStatus: make(map[string]interface{}, len(commands)), TempIDMapping: make(map[string]string)}
+	ctx = WithPriority(ctx, PriorityBulk)
+
+	for start := 0; start < len(commands); start += MaxSyncCommands {
+		chunk := commands[start:min(start+MaxSyncCommands, len(commands))]
+		if err := ctx.Err(); err != nil {
+			if start == 0 {
+				return nil, err
+			}
+			failCommands(merged, commands[start:], err)
+			break
+		}
+
+		sent := make([]Command, len(chunk))
+		for i, cmd := range chunk {
+			sent[i] = resolveEarlierTempIDs(cmd, merged.TempIDMapping)
+		}
+		syncResp, err := syncClient.BatchCommands(ctx, sent)
+		if err != nil {
+			if start == 0 {
+				return nil, err
+			}
+			failCommands(merged, chunk, err)
+			continue
+		}
+		for _, cmd := range chunk {
+			merged.SyncStatus[cmd.UUID] = syncResp.SyncStatus[cmd.UUID]
+		}
+		for tempID, realID := range syncResp.TempIDMapping {
+			merged.TempIDMapping[tempID] = realID
+		}
+		merged.SyncToken = syncResp.SyncToken
+		reportProgress(ctx, start+len(chunk), len(commands))
+	}
+	return merged, nil
+}
+
+// resolveEarlierTempIDs returns cmd with the string arguments naming a temp ID
+// in mapping replaced by the real ID. cmd itself is not changed.
+func resolveEarlierTempIDs(cmd Command, mapping map[string]string) Command {
+	var args map[string]interface{}
+	for key, value := range cmd.Args {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if realID, ok := mapping[s]; ok {
+			if args == nil {
+				args = make(map[string]interface{}, len(cmd.Args))
+				for k, v := range cmd.Args {
+					args[k] = v
+				}
+			}
+			args[key] = realID
+		}
+	}
+	if args != nil {
+		cmd.Args = args
+	}
+	return cmd
+}
+
+// failCommands records err as the status of commands that were not applied.
+func failCommands(resp *SyncResponse, commands []Command, err error) {
+	for _, cmd := range commands {
+		resp.SyncStatus[cmd.UUID] = map[string]interface{}{"error": err.Error()}
+	}
+}
@@ -0,0 +1,426 @@
+package todoist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeAPI records REST calls for BulkExecutor tests.
+type fakeAPI struct {
+	remaining int
+	failPath  string
+	calls     []string
+}
+
+func (f *fakeAPI) Get(_ context.Context, _ string) ([]byte, error) { return nil, nil }
+func (f *fakeAPI) Post(_ context.Context, path string, _ interface{}) ([]byte, error) {
+	f.calls = append(f.calls, "POST "+path)
+	if path == f.failPath {
+		return nil, errors.New("not found")
+	}
+	return nil, nil
+}
+func (f *fakeAPI) Delete(_ context.Context, path string) error {
+	f.calls = append(f.calls, "DELETE "+path)
+	return nil
+}
+func (f *fakeAPI) TestConnection(_ context.Context) error { return nil }
+func (f *fakeAPI) GetRemainingRequests() int              { return f.remaining }
+
+// fakeSyncAPI records Sync batches for BulkExecutor tests.
+type fakeSyncAPI struct {
+	remaining int
+	failUUID  string
+	errOnCall map[int]error
+	batches   [][]Command
+}
+
+func (f *fakeSyncAPI) BatchCommands(_ context.Context, commands []Command) (*SyncResponse, error) {
+	f.batches = append(f.batches, commands)
+	if err := f.errOnCall[len(f.batches)]; err != nil {
+		return nil, err
+	}
+	status := make(map[string]interface{})
+	mapping := make(map[string]string)
+	for _, cmd := range commands {
+		if cmd.UUID == f.failUUID {
+			status[cmd.UUID] = map[string]interface{}{"error": "Item not found"}
+		} else {
+			status[cmd.UUID] = "ok"
+			if cmd.TempID != "" {
+				mapping[cmd.TempID] = "id-" + cmd.TempID
+			}
+		}
+	}
+	return &SyncResponse{SyncStatus: status, TempIDMapping: mapping}, nil
+}
+func (f *fakeSyncAPI) Get(_ context.Context, _ string) ([]byte, error) { return nil, nil }
+func (f *fakeSyncAPI) GetRemainingRequests() int                       { return f.remaining }
+
+func closeOps(n int) []BulkOperation {
+	ops := make([]BulkOperation, n)
+	for i := range ops {
+		id := fmt.Sprintf("t%d", i)
+		ops[i] = BulkOperation{
+			ID:      id,
+			Command: Command{Type: "item_close", UUID: "u-" + id, Args: map[string]interface{}{"id": id}},
+			Method:  http.MethodPost,
+			Path:    "/tasks/" + id + "/close",
+		}
+	}
+	return ops
+}
+
+func TestBulkExecutor_Strategy(t *testing.T) {
+	syncOnly := closeOps(2)
+	for i := range syncOnly {
+		syncOnly[i].Path = ""
+	}
+
+	tests := []struct {
+		name          string
+		ops           []BulkOperation
+		restRemaining int
+		syncRemaining int
+		wantStrategy  string
+		wantErr       string
+	}{
+		{name: "small batch uses REST", ops: closeOps(3), restRemaining: 450, syncRemaining: 450, wantStrategy: StrategyREST},
+		{name: "threshold is inclusive", ops: closeOps(DefaultSyncBatchThreshold), restRemaining: 450, syncRemaining: 450, wantStrategy: StrategyREST},
+		{name: "large batch uses Sync", ops: closeOps(DefaultSyncBatchThreshold + 1), restRemaining: 450, syncRemaining: 450, wantStrategy: StrategySync},
+		{name: "low REST headroom falls back to Sync", ops: closeOps(3), restRemaining: 2, syncRemaining: 2, wantStrategy: StrategySync},
+		{name: "Sync-only operations", ops: syncOnly, restRemaining: 450, syncRemaining: 450, wantStrategy: StrategySync},
+		{name: "no headroom", ops: closeOps(3), restRemaining: 0, syncRemaining: 0, wantErr: "insufficient rate limit capacity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewBulkExecutor(&fakeAPI{remaining: tt.restRemaining}, &fakeSyncAPI{remaining: tt.syncRemaining})
+			result, err := e.Execute(context.Background(), tt.ops)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Strategy != tt.wantStrategy {
+				t.Errorf("strategy = %s, want %s", result.Strategy, tt.wantStrategy)
+			}
+			if len(result.Succeeded) != len(tt.ops) {
+				t.Errorf("succeeded = %v, want %d", result.Succeeded, len(tt.ops))
+			}
+		})
+	}
+}
+
+func TestBulkExecutor_RequestedStrategy(t *testing.T) {
+	syncOnly := closeOps(2)
+	for i := range syncOnly {
+		syncOnly[i].Path = ""
+	}
+
+	tests := []struct {
+		name         string
+		strategy     string
+		ops          []BulkOperation
+		wantStrategy string
+		wantErr      string
+	}{
+		{name: "auto keeps the default choice", strategy: StrategyAuto, ops: closeOps(3), wantStrategy: StrategyREST},
+		{name: "forced Sync for a small batch", strategy: StrategySync, ops: closeOps(2), wantStrategy: StrategySync},
+		{name: "forced REST for a large batch", strategy: StrategyREST, ops: closeOps(20), wantStrategy: StrategyREST},
+		{name: "REST unavailable", strategy: StrategyREST, ops: syncOnly, wantErr: "strategy rest is not available"},
+		{name: "unknown strategy", strategy: "graphql", ops: closeOps(2), wantErr: "unknown bulk strategy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, syncClient := &fakeAPI{remaining: 450}, &fakeSyncAPI{remaining: 450}
+			ctx := WithStrategy(context.Background(), tt.strategy)
+			result, err := NewBulkExecutor(client, syncClient).Execute(ctx, tt.ops)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if len(client.calls) != 0 || len(syncClient.batches) != 0 {
+					t.Errorf("nothing should be sent: calls %v, batches %d", client.calls, len(syncClient.batches))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Strategy != tt.wantStrategy {
+				t.Errorf("strategy = %s, want %s", result.Strategy, tt.wantStrategy)
+			}
+		})
+	}
+}
+
+func TestBulkExecutor_SyncBatchThreshold(t *testing.T) {
+	SetSyncBatchThreshold(1)
+	defer SetSyncBatchThreshold(DefaultSyncBatchThreshold)
+
+	e := NewBulkExecutor(&fakeAPI{remaining: 450}, &fakeSyncAPI{remaining: 450})
+	if got := e.Estimate(context.Background(), closeOps(1)); got.Strategy != StrategyREST {
+		t.Errorf("1 operation: strategy = %s, want rest", got.Strategy)
+	}
+	if got := e.Estimate(context.Background(), closeOps(2)); got.Strategy != StrategySync || got.SyncRequests != 1 {
+		t.Errorf("2 operations: estimate = %+v, want one Sync request", got)
+	}
+}
+
+func TestBulkExecutor_Rollback(t *testing.T) {
+	ops := closeOps(4)
+	for i := range ops[:3] {
+		id := ops[i].ID
+		ops[i].Revert = &BulkOperation{Command: Command{Type: "item_uncomplete", UUID: "r-" + id}, Method: http.MethodPost, Path: "/tasks/" + id + "/reopen"}
+	}
+	ops[2].Revert.Path = ""
+	result := &BulkResult{Succeeded: []string{"t0", "t2", "t3"}, Failed: []BulkFailure{{ID: "t1", Error: "not found"}}}
+
+	client, syncClient := &fakeAPI{remaining: 450}, &fakeSyncAPI{remaining: 450}
+	ctx := WithStrategy(context.Background(), StrategyREST)
+	reverted, err := NewBulkExecutor(client, syncClient).Rollback(ctx, ops, result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// t2's revert is Sync-only, so the requested REST strategy gives way.
+	if reverted.Strategy != StrategySync || len(syncClient.batches) != 1 {
+		t.Fatalf("strategy = %s, batches = %d, want one Sync batch", reverted.Strategy, len(syncClient.batches))
+	}
+	var uuids []string
+	for _, cmd := range syncClient.batches[0] {
+		uuids = append(uuids, cmd.UUID)
+	}
+	if strings.Join(uuids, ",") != "r-t2,r-t0" {
+		t.Errorf("reverts = %v, want newest first", uuids)
+	}
+	if strings.Join(reverted.Succeeded, ",") != "t2,t0" || strings.Join(reverted.FailedIDs(), ",") != "t3" {
+		t.Errorf("succeeded = %v, failed = %v", reverted.Succeeded, reverted.Failed)
+	}
+}
+
+func TestBulkExecutor_Progress(t *testing.T) {
+	var reports []string
+	ctx := WithProgress(context.Background(), func(done, total int) {
+		reports = append(reports, fmt.Sprintf("%d/%d", done, total))
+	})
+
+	if _, err := NewBulkExecutor(&fakeAPI{remaining: 450}, &fakeSyncAPI{remaining: 450}).Execute(ctx, closeOps(3)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(reports, ","); got != "1/3,2/3,3/3" {
+		t.Errorf("REST progress = %s", got)
+	}
+
+	reports = nil
+	if _, err := NewBulkExecutor(&fakeAPI{remaining: 450}, &fakeSyncAPI{remaining: 450}).Execute(ctx, closeOps(150)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(reports, ","); got != "100/150,150/150" {
+		t.Errorf("Sync progress = %s", got)
+	}
+}
+
+func TestBulkExecutor_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := NewBulkExecutor(&fakeAPI{remaining: 450}, &fakeSyncAPI{remaining: 450}).Execute(ctx, closeOps(0)); err != nil {
+		t.Fatalf("an empty run should not fail: %v", err)
+	}
+
+	client := &fakeAPI{remaining: 450}
+	ctx = WithProgress(ctx, func(done, _ int) {
+		if done == 2 {
+			cancel()
+		}
+	})
+	result, err := NewBulkExecutor(client, &fakeSyncAPI{remaining: 450}).Execute(ctx, closeOps(4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.calls) != 2 || strings.Join(result.Succeeded, ",") != "t0,t1" || strings.Join(result.FailedIDs(), ",") != "t2,t3" {
+		t.Errorf("calls = %v, succeeded = %v, failed = %v", client.calls, result.Succeeded, result.Failed)
+	}
+
+	if _, err := NewBulkExecutor(client, &fakeSyncAPI{remaining: 450}).Execute(ctx, closeOps(2)); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled before anything is sent", err)
+	}
+}
+
+func TestBulkExecutor_PartialFailures(t *testing.T) {
+	t.Run("REST", func(t *testing.T) {
+		client := &fakeAPI{remaining: 450, failPath: "/tasks/t1/close"}
+		result, err := NewBulkExecutor(client, &fakeSyncAPI{remaining: 450}).Execute(context.Background(), closeOps(3))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Join(result.Succeeded, ",") != "t0,t2" || strings.Join(result.FailedIDs(), ",") != "t1" {
+			t.Errorf("succeeded = %v, failed = %v", result.Succeeded, result.Failed)
+		}
+	})
+
+	t.Run("Sync", func(t *testing.T) {
+		syncClient := &fakeSyncAPI{remaining: 450, failUUID: "u-t4"}
+		result, err := NewBulkExecutor(&fakeAPI{remaining: 450}, syncClient).Execute(context.Background(), closeOps(8))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Succeeded) != 7 || strings.Join(result.FailedIDs(), ",") != "t4" {
+			t.Errorf("succeeded = %v, failed = %v", result.Succeeded, result.Failed)
+		}
+	})
+
+	t.Run("Sync chunks", func(t *testing.T) {
+		syncClient := &fakeSyncAPI{remaining: 450, errOnCall: map[int]error{2: errors.New("timeout")}}
+		result, err := NewBulkExecutor(&fakeAPI{remaining: 450}, syncClient).Execute(context.Background(), closeOps(MaxSyncCommands+10))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(syncClient.batches) != 2 || len(syncClient.batches[0]) != MaxSyncCommands {
+			t.Fatalf("batches = %d", len(syncClient.batches))
+		}
+		if len(result.Succeeded) != MaxSyncCommands || len(result.Failed) != 10 || result.Failed[0].Error != "timeout" {
+			t.Errorf("succeeded = %d, failed = %v", len(result.Succeeded), result.Failed)
+		}
+	})
+
+	t.Run("first Sync request fails", func(t *testing.T) {
+		syncClient := &fakeSyncAPI{remaining: 450, errOnCall: map[int]error{1: errors.New("sync error")}}
+		if _, err := NewBulkExecutor(&fakeAPI{remaining: 450}, syncClient).Execute(context.Background(), closeOps(8)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestBulkExecutor_Estimate(t *testing.T) {
+	tests := []struct {
+		name          string
+		ops           []BulkOperation
+		restRemaining int
+		syncRemaining int
+		want          CostEstimate
+	}{
+		{
+			name:          "small batch over REST",
+			ops:           closeOps(3),
+			restRemaining: 450,
+			syncRemaining: 450,
+			want:          CostEstimate{Operations: 3, Strategy: StrategyREST, RESTRequests: 3, RESTRemaining: 450, SyncRemaining: 450, WithinBudget: true},
+		},
+		{
+			name:          "large batch in Sync chunks",
+			ops:           closeOps(250),
+			restRemaining: 450,
+			syncRemaining: 450,
+			want:          CostEstimate{Operations: 250, Strategy: StrategySync, SyncRequests: 3, RESTRemaining: 450, SyncRemaining: 450, WithinBudget: true},
+		},
+		{
+			name:          "over budget reports the cheapest form",
+			ops:           closeOps(250),
+			restRemaining: 2,
+			syncRemaining: 2,
+			want: CostEstimate{Operations: 250, SyncRequests: 3, RESTRemaining: 2, SyncRemaining: 2,
+				Message: "insufficient rate limit capacity: need 3 requests, have 2 remaining in 15min window"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{remaining: tt.restRemaining}
+			syncAPI := &fakeSyncAPI{remaining: tt.syncRemaining}
+			got := NewBulkExecutor(api, syncAPI).Estimate(context.Background(), tt.ops)
+			if got != tt.want {
+				t.Errorf("Estimate() = %+v, want %+v", got, tt.want)
+			}
+			if len(api.calls) != 0 || len(syncAPI.batches) != 0 {
+				t.Error("Estimate must not send requests")
+			}
+		})
+	}
+}
+
+func TestSendCommands(t *testing.T) {
+	addCommands := func(n int) []Command {
+		commands := make([]Command, n)
+		for i := range commands {
+			args := map[string]interface{}{"content": fmt.Sprintf("task %d", i)}
+			if i > 0 {
+				args["parent_id"] = fmt.Sprintf("tmp-%d", i-1)
+			}
+			commands[i] = Command{Type: "item_add", UUID: fmt.Sprintf("u-%d", i), TempID: fmt.Sprintf("tmp-%d", i), Args: args}
+		}
+		return commands
+	}
+
+	t.Run("chunks and carries temp IDs", func(t *testing.T) {
+		var reports []string
+		ctx := WithProgress(context.Background(), func(done, total int) {
+			reports = append(reports, fmt.Sprintf("%d/%d", done, total))
+		})
+		syncClient := &fakeSyncAPI{remaining: 450}
+		commands := addCommands(MaxSyncCommands + 5)
+		resp, err := SendCommands(ctx, syncClient, commands)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(syncClient.batches) != 2 || len(syncClient.batches[0]) != MaxSyncCommands {
+			t.Fatalf("batches = %d", len(syncClient.batches))
+		}
+		// The first command of the second request names a parent created by
+		// the first request, which only its real ID can reach.
+		if got := syncClient.batches[1][0].Args["parent_id"]; got != fmt.Sprintf("id-tmp-%d", MaxSyncCommands-1) {
+			t.Errorf("parent_id = %v, want the real ID", got)
+		}
+		if got := syncClient.batches[1][1].Args["parent_id"]; got != fmt.Sprintf("tmp-%d", MaxSyncCommands) {
+			t.Errorf("parent_id = %v, want the temp ID of the same request", got)
+		}
+		if commands[MaxSyncCommands].Args["parent_id"] != fmt.Sprintf("tmp-%d", MaxSyncCommands-1) {
+			t.Error("the caller's commands were changed")
+		}
+		if len(resp.SyncStatus) != MaxSyncCommands+5 || len(resp.TempIDMapping) != MaxSyncCommands+5 {
+			t.Errorf("merged %d statuses and %d mappings", len(resp.SyncStatus), len(resp.TempIDMapping))
+		}
+		if got := strings.Join(reports, ","); got != "100/105,105/105" {
+			t.Errorf("progress = %s", got)
+		}
+	})
+
+	t.Run("later request fails", func(t *testing.T) {
+		syncClient := &fakeSyncAPI{remaining: 450, errOnCall: map[int]error{2: errors.New("timeout")}}
+		resp, err := SendCommands(context.Background(), syncClient, addCommands(MaxSyncCommands+1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.SyncStatus["u-0"] != "ok" || fmt.Sprint(resp.SyncStatus[fmt.Sprintf("u-%d", MaxSyncCommands)]) != "map[error:timeout]" {
+			t.Errorf("statuses = %v", resp.SyncStatus)
+		}
+	})
+
+	t.Run("first request fails", func(t *testing.T) {
+		syncClient := &fakeSyncAPI{remaining: 450, errOnCall: map[int]error{1: errors.New("sync error")}}
+		if _, err := SendCommands(context.Background(), syncClient, addCommands(3)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("cancelled between requests", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ctx = WithProgress(ctx, func(_, _ int) { cancel() })
+		syncClient := &fakeSyncAPI{remaining: 450}
+		resp, err := SendCommands(ctx, syncClient, addCommands(MaxSyncCommands+1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(syncClient.batches) != 1 || fmt.Sprint(resp.SyncStatus[fmt.Sprintf("u-%d", MaxSyncCommands)]) != "map[error:context canceled]" {
+			t.Errorf("batches = %d, statuses = %v", len(syncClient.batches), resp.SyncStatus[fmt.Sprintf("u-%d", MaxSyncCommands)])
+		}
+	})
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,16 +19,38 @@ const (
 	maxRequests     = 450
 )
 
+// APIEndpoint describes a Todoist API variant used by this package.
+type APIEndpoint struct {
+	Name    string `json:"name"`
+	BaseURL string `json:"base_url"`
+	UsedFor string `json:"used_for"`
+}
+
+// APIEndpoints lists the Todoist API variants the clients call.
+func APIEndpoints() []APIEndpoint {
+	return []APIEndpoint{
+		{Name: "REST v2", BaseURL: baseURL, UsedFor: "single-item reads and writes (Client)"},
+		{Name: "Sync v1", BaseURL: syncBaseURL, UsedFor: "batched commands and sync reads (SyncClient)"},
+		{Name: "API v1", BaseURL: apiV1BaseURL, UsedFor: "completed tasks, stats, and activity (SyncClient)"},
+	}
+}
+
+// ErrUnauthorized is returned when Todoist rejects the API token.
+var ErrUnauthorized = errors.New("authentication failed")
+
 // Client wraps the HTTP client with Todoist-specific functionality.
 type Client struct {
 	httpClient  *http.Client
-	apiToken    string
+	apiToken    atomic.Pointer[string]
 	rateLimiter *RateLimiter
+	scheduler   *Scheduler
+	dumper      *FixtureDumper
+	status      *StatusMonitor
 }
 
-// NewClient creates a new Todoist API client with a shared rate limiter.
-func NewClient(apiToken string, rl *RateLimiter) *Client {
-	return &Client{
+// NewClient creates a new Todoist API client with a shared rate limiter and request scheduler.
+func NewClient(apiToken string, rl *RateLimiter, sched *Scheduler) *Client {
+	c := &Client{
 		httpClient: &http.Client{
 			Timeout: timeout,
 			Transport: &http.Transport{
@@ -35,9 +59,29 @@ func NewClient(apiToken string, rl *RateLimiter) *Client {
 				DisableCompression: false,
 			},
 		},
-		apiToken:    apiToken,
 		rateLimiter: rl,
+		scheduler:   sched,
 	}
+	c.SetToken(apiToken)
+	return c
+}
+
+// SetToken replaces the API token used by subsequent requests, e.g. after
+// a rotated secret was fetched again.
+func (c *Client) SetToken(apiToken string) {
+	c.apiToken.Store(&apiToken)
+}
+
+// SetFixtureDumper makes failing requests write fixtures through d. Call it
+// before the client is used.
+func (c *Client) SetFixtureDumper(d *FixtureDumper) {
+	c.dumper = d
+}
+
+// SetStatusMonitor makes repeated server errors consult the status page
+// through m. Call it before the client is used.
+func (c *Client) SetStatusMonitor(m *StatusMonitor) {
+	c.status = m
 }
 
 // doRequest performs an HTTP request with proper headers and error handling.
@@ -45,10 +89,16 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	if err := c.rateLimiter.Check(); err != nil {
 		return nil, err
 	}
+	release, err := c.scheduler.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	var reqBody io.Reader
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
@@ -61,25 +111,31 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	token := *c.apiToken.Load()
+	req.Header.Set("Authorization", "Bearer "+token)
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		c.dumper.dump(token, req, jsonData, nil, nil, err)
 		return nil, &RetryableError{err: fmt.Errorf("request failed: %w", err)}
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
+		c.dumper.dump(token, req, jsonData, resp, nil, err)
 		return nil, &RetryableError{err: fmt.Errorf("failed to read response: %w", err)}
 	}
+	c.rateLimiter.Observe(resp.StatusCode, resp.Header, respBody)
 
 	if resp.StatusCode >= 400 {
-		return nil, handleHTTPError(resp.StatusCode, respBody)
+		c.dumper.dump(token, req, jsonData, resp, respBody, nil)
+		return nil, c.status.observe(ctx, resp.StatusCode, handleHTTPError(resp.StatusCode, resp.Header, respBody))
 	}
+	c.status.observe(ctx, resp.StatusCode, nil)
 
 	return respBody, nil
 }
@@ -123,16 +179,23 @@ func (c *Client) GetRemainingRequests() int {
 }
 
 // handleHTTPError converts HTTP error responses to meaningful error messages.
-func handleHTTPError(statusCode int, body []byte) error {
+func handleHTTPError(statusCode int, header http.Header, body []byte) error {
 	switch statusCode {
 	case 401:
-		return fmt.Errorf("authentication failed: invalid API token (get a valid token from https://todoist.com/prefs/integrations)")
+		return fmt.Errorf("%w: invalid API token (get a valid token from https://todoist.com/prefs/integrations)", ErrUnauthorized)
 	case 403:
 		return fmt.Errorf("access forbidden: you don't have permission to access this resource")
 	case 404:
 		return fmt.Errorf("resource not found: the requested item doesn't exist")
 	case 429:
-		return fmt.Errorf("rate limit exceeded: too many requests (max 450 per 15 minutes). Please wait and try again")
+		wait, ok := retryAfter(header, body, time.Now())
+		if !ok {
+			wait = defaultRetryAfter
+		}
+		return &RateLimitError{
+			err:        fmt.Errorf("rate limit exceeded: too many requests (max 450 per 15 minutes). Please wait and try again"),
+			retryAfter: wait,
+		}
 	case 500, 502, 503, 504:
 		return &RetryableError{err: fmt.Errorf("server error (status %d): please try again later", statusCode)}
 	default:
@@ -0,0 +1,152 @@
+package todoist
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// FixtureDumper writes the request and response of every failing API call
+// to a directory, so users can attach reproducible traces to bug reports.
+// The API token is redacted wherever it appears. A nil FixtureDumper writes
+// nothing.
+type FixtureDumper struct {
+	dir string
+	seq atomic.Uint64
+}
+
+// NewFixtureDumper creates a dumper writing to dir, which is created on the
+// first failure.
+func NewFixtureDumper(dir string) *FixtureDumper {
+	return &FixtureDumper{dir: dir}
+}
+
+// fixture is the file written for one failing call.
+type fixture struct {
+	Time     time.Time        `json:"time"`
+	Request  fixtureRequest   `json:"request"`
+	Response *fixtureResponse `json:"response,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+type fixtureRequest struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Headers map[string][]string `json:"headers"`
+	Body    interface{}         `json:"body,omitempty"`
+}
+
+type fixtureResponse struct {
+	Status  int                 `json:"status"`
+	Headers map[string][]string `json:"headers"`
+	Body    interface{}         `json:"body,omitempty"`
+}
+
+// dump records a failed call: resp is nil when no response arrived, in which
+// case callErr says why. Failures to write are logged, never returned, so
+// they cannot mask the API error being reported.
+func (d *FixtureDumper) dump(token string, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, callErr error) {
+	if d == nil {
+		return
+	}
+	f := fixture{
+		Time: time.Now().UTC(),
+		Request: fixtureRequest{
+			Method:  req.Method,
+			URL:     redact(req.URL.String(), token),
+			Headers: redactHeaders(req.Header, token),
+			Body:    fixtureBody(reqBody, token),
+		},
+	}
+	if resp != nil {
+		f.Response = &fixtureResponse{
+			Status:  resp.StatusCode,
+			Headers: redactHeaders(resp.Header, token),
+			Body:    fixtureBody(respBody, token),
+		}
+	}
+	if callErr != nil {
+		f.Error = redact(callErr.Error(), token)
+	}
+	if err := d.write(f); err != nil {
+		slog.Warn("failed to write API fixture", "dir", d.dir, "error", err)
+	}
+}
+
+func (d *FixtureDumper) write(f fixture) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.dir, 0o700); err != nil {
+		return err
+	}
+	status := "error"
+	if f.Response != nil {
+		status = fmt.Sprint(f.Response.Status)
+	}
+	name := fmt.Sprintf("%s-%04d-%s-%s.json", f.Time.Format("20060102T150405"), d.seq.Add(1), strings.ToLower(f.Request.Method), status)
+	return os.WriteFile(filepath.Join(d.dir, name), data, 0o600)
+}
+
+// redact replaces every occurrence of the token in s.
+func redact(s, token string) string {
+	if token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, token, redacted)
+}
+
+// redactHeaders copies headers, dropping credentials and redacting the token
+// from the rest.
+func redactHeaders(h http.Header, token string) map[string][]string {
+	out := make(map[string][]string, len(h))
+	for key, values := range h {
+		switch http.CanonicalHeaderKey(key) {
+		case "Authorization", "Cookie", "Set-Cookie":
+			out[key] = []string{redacted}
+			continue
+		}
+		copied := make([]string, len(values))
+		for i, v := range values {
+			copied[i] = redact(v, token)
+		}
+		out[key] = copied
+	}
+	return out
+}
+
+// fixtureBody returns a redacted body in its most readable form: JSON is
+// embedded as is, Sync API form bodies are decoded into their fields, and
+// anything else is kept as text.
+func fixtureBody(body []byte, token string) interface{} {
+	if len(body) == 0 {
+		return nil
+	}
+	text := redact(string(body), token)
+	if json.Valid([]byte(text)) {
+		return json.RawMessage(text)
+	}
+	if form, err := url.ParseQuery(text); err == nil && len(form) > 0 && !strings.ContainsAny(text, " \n") {
+		fields := make(map[string]interface{}, len(form))
+		for key := range form {
+			value := redact(form.Get(key), token)
+			if json.Valid([]byte(value)) {
+				fields[key] = json.RawMessage(value)
+			} else {
+				fields[key] = value
+			}
+		}
+		return fields
+	}
+	return text
+}
@@ -0,0 +1,102 @@
+package todoist
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readFixtures(t *testing.T, dir string) []map[string]interface{} {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	var fixtures []map[string]interface{}
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatalf("read fixture: %v", err)
+		}
+		var f map[string]interface{}
+		if err := json.Unmarshal(data, &f); err != nil {
+			t.Fatalf("parse fixture %s: %v", e.Name(), err)
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures
+}
+
+func TestFixtureDumper_RedactsToken(t *testing.T) {
+	const token = "0123456789abcdef0123456789abcdef01234567"
+	dir := filepath.Join(t.TempDir(), "dumps")
+	d := NewFixtureDumper(dir)
+
+	req, _ := http.NewRequest(http.MethodPost, baseURL+"/tasks", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp := &http.Response{StatusCode: 400, Header: http.Header{"X-Request-Id": {"abc"}}}
+	d.dump(token, req, []byte(`{"content":"Buy milk"}`), resp, []byte(`{"error":"bad token `+token+`"}`), nil)
+
+	data, err := os.ReadDir(dir)
+	if err != nil || len(data) != 1 {
+		t.Fatalf("expected one fixture, got %v (%v)", data, err)
+	}
+	if !strings.HasSuffix(data[0].Name(), "-post-400.json") {
+		t.Errorf("unexpected fixture name %q", data[0].Name())
+	}
+	raw, _ := os.ReadFile(filepath.Join(dir, data[0].Name()))
+	if strings.Contains(string(raw), token) {
+		t.Fatalf("fixture leaks the token:\n%s", raw)
+	}
+
+	f := readFixtures(t, dir)[0]
+	request := f["request"].(map[string]interface{})
+	if auth := request["headers"].(map[string]interface{})["Authorization"].([]interface{})[0]; auth != redacted {
+		t.Errorf("expected redacted Authorization, got %v", auth)
+	}
+	if content := request["body"].(map[string]interface{})["content"]; content != "Buy milk" {
+		t.Errorf("expected JSON request body embedded, got %v", request["body"])
+	}
+	response := f["response"].(map[string]interface{})
+	if response["status"] != float64(400) {
+		t.Errorf("expected status 400, got %v", response["status"])
+	}
+	if msg := response["body"].(map[string]interface{})["error"]; msg != "bad token "+redacted {
+		t.Errorf("expected token redacted from response body, got %v", msg)
+	}
+}
+
+func TestFixtureDumper_SyncFormAndTransportError(t *testing.T) {
+	const token = "secret-token"
+	dir := t.TempDir()
+	d := NewFixtureDumper(dir)
+
+	form := url.Values{}
+	form.Set("commands", `[{"type":"item_add","uuid":"u1","args":{"content":"x"}}]`)
+	req, _ := http.NewRequest(http.MethodPost, syncBaseURL, nil)
+	d.dump(token, req, []byte(form.Encode()), nil, nil, errors.New("dial tcp: connection refused"))
+
+	f := readFixtures(t, dir)[0]
+	if _, ok := f["response"]; ok {
+		t.Error("expected no response for a transport error")
+	}
+	if f["error"] != "dial tcp: connection refused" {
+		t.Errorf("unexpected error %v", f["error"])
+	}
+	commands, ok := f["request"].(map[string]interface{})["body"].(map[string]interface{})["commands"].([]interface{})
+	if !ok || len(commands) != 1 {
+		t.Errorf("expected decoded commands, got %v", f["request"])
+	}
+}
+
+func TestFixtureDumper_Nil(t *testing.T) {
+	var d *FixtureDumper
+	req, _ := http.NewRequest(http.MethodGet, baseURL+"/tasks", nil)
+	d.dump("token", req, nil, nil, nil, errors.New("boom"))
+}
@@ -14,5 +14,6 @@ type API interface {
 // SyncAPI defines the interface for the Todoist Sync API client.
 type SyncAPI interface {
 	BatchCommands(ctx context.Context, commands []Command) (*SyncResponse, error)
+	Get(ctx context.Context, path string) ([]byte, error)
 	GetRemainingRequests() int
 }
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fields records which modeled fields a decoded entity carried and which of
+// them were null, so that encoding it again reproduces the input: a field
+// the API omitted stays omitted and a null stays null unless it was set.
+type fields struct {
+	present map[string]bool
+	null    map[string]bool
+}
+
+// modelKey is a modeled JSON field of an entity struct.
+type modelKey struct {
+	name string
+	// always fields are encoded even when the input lacked them.
+	always bool
+}
+
+var keyCache sync.Map // reflect.Type -> []modelKey
+
+// modelKeys lists the JSON fields of the struct type t, read from its json
+// tags. A model:"always" tag marks fields that are always encoded.
+func modelKeys(t reflect.Type) []modelKey {
+	if keys, ok := keyCache.Load(t); ok {
+		return keys.([]modelKey)
+	}
+	var keys []modelKey
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" || !f.IsExported() {
+			continue
+		}
+		keys = append(keys, modelKey{name: name, always: f.Tag.Get("model") == "always"})
+	}
+	keyCache.Store(t, keys)
+	return keys
+}
+
+// decode unmarshals data into known, a pointer to a struct without JSON
+// methods, and returns the fields known does not model, kept verbatim as
+// json.RawMessage values.
+func decode(data []byte, known interface{}, meta *fields) (map[string]interface{}, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, known); err != nil {
+		return nil, err
+	}
+
+	meta.present = make(map[string]bool)
+	meta.null = make(map[string]bool)
+	for _, key := range modelKeys(reflect.TypeOf(known).Elem()) {
+		value, ok := raw[key.name]
+		if !ok {
+			continue
+		}
+		meta.present[key.name] = true
+		if isNull(value) {
+			meta.null[key.name] = true
+		}
+		delete(raw, key.name)
+	}
+
+	var extra map[string]interface{}
+	if len(raw) > 0 {
+		extra = make(map[string]interface{}, len(raw))
+		for key, value := range raw {
+			extra[key] = value
+		}
+	}
+	return extra, nil
+}
+
+// encode marshals known, a struct without JSON methods, together with extra.
+// Extra fields never replace modeled ones. A modeled field left at its zero
+// value is omitted when the decoded input lacked it and stays null when the
+// input had null.
+func encode(known interface{}, extra map[string]interface{}, meta fields) ([]byte, error) {
+	data, err := json.Marshal(known)
+	if err != nil {
+		return nil, err
+	}
+	var encoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(encoded)+len(extra))
+	for key, value := range extra {
+		out[key] = value
+	}
+	for _, key := range modelKeys(reflect.TypeOf(known)) {
+		value := encoded[key.name]
+		switch {
+		case !isZero(value) || key.always:
+			out[key.name] = value
+		case meta.null[key.name]:
+			out[key.name] = json.RawMessage("null")
+		case meta.present[key.name]:
+			out[key.name] = value
+		default:
+			delete(out, key.name)
+		}
+	}
+	return json.Marshal(out)
+}
+
+// isNull reports whether value is the JSON null literal.
+func isNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
+
+// isZero reports whether value encodes a Go zero value.
+func isZero(value json.RawMessage) bool {
+	switch string(bytes.TrimSpace(value)) {
+	case "", "null", `""`, "0", "false", "[]", "{}":
+		return true
+	}
+	return false
+}
+
+// set stores a field that is not modeled in *extra, creating the map.
+func set(extra *map[string]interface{}, key string, value interface{}) {
+	if *extra == nil {
+		*extra = make(map[string]interface{})
+	}
+	(*extra)[key] = value
+}
@@ -0,0 +1,326 @@
+// Package models defines typed Todoist entities as the REST and unified v1
+// APIs report them. Each model names the fields tools read and write; fields
+// it does not name are kept in Extra, so that decoding an entity and
+// encoding it again returns what the API sent.
+package models
+
+import "encoding/json"
+
+// Due is the due date of a task. Date is YYYY-MM-DD, or for tasks due at a
+// time of day in the Sync API, a date-time.
+type Due struct {
+	Date        string `json:"date"`
+	String      string `json:"string"`
+	Lang        string `json:"lang"`
+	IsRecurring bool   `json:"is_recurring"`
+	// Datetime is set by the REST API for tasks due at a time of day.
+	Datetime string `json:"datetime"`
+	Timezone string `json:"timezone"`
+
+	// Extra holds the fields Due does not model.
+	Extra  map[string]interface{} `json:"-"`
+	fields fields
+}
+
+type dueFields Due
+
+// UnmarshalJSON decodes a due date, keeping unmodeled fields in Extra.
+func (d *Due) UnmarshalJSON(data []byte) error {
+	var known dueFields
+	extra, err := decode(data, &known, &known.fields)
+	if err != nil {
+		return err
+	}
+	*d = Due(known)
+	d.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the due date with its Extra fields.
+func (d Due) MarshalJSON() ([]byte, error) {
+	return encode(dueFields(d), d.Extra, d.fields)
+}
+
+// IsTimed reports whether the task is due at a time of day rather than all
+// day. REST tasks carry the time in datetime; Sync API tasks append it to
+// date.
+func (d *Due) IsTimed() bool {
+	return d != nil && (d.Datetime != "" || len(d.Date) > 10)
+}
+
+// Day returns the YYYY-MM-DD day the task is due, or "" without a due date.
+func (d *Due) Day() string {
+	if d == nil || len(d.Date) < 10 {
+		return ""
+	}
+	return d.Date[:10]
+}
+
+// Deadline is the date a task must be done by, independent of its due date.
+type Deadline struct {
+	Date string `json:"date"`
+	Lang string `json:"lang"`
+
+	// Extra holds the fields Deadline does not model.
+	Extra  map[string]interface{} `json:"-"`
+	fields fields
+}
+
+type deadlineFields Deadline
+
+// UnmarshalJSON decodes a deadline, keeping unmodeled fields in Extra.
+func (d *Deadline) UnmarshalJSON(data []byte) error {
+	var known deadlineFields
+	extra, err := decode(data, &known, &known.fields)
+	if err != nil {
+		return err
+	}
+	*d = Deadline(known)
+	d.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the deadline with its Extra fields.
+func (d Deadline) MarshalJSON() ([]byte, error) {
+	return encode(deadlineFields(d), d.Extra, d.fields)
+}
+
+// Day returns the YYYY-MM-DD deadline, or "" without a deadline.
+func (d *Deadline) Day() string {
+	if d == nil || len(d.Date) < 10 {
+		return ""
+	}
+	return d.Date[:10]
+}
+
+// Duration is how long a task takes, in minutes or days.
+type Duration struct {
+	Amount int `json:"amount"`
+	// Unit is "minute" or "day".
+	Unit string `json:"unit"`
+}
+
+// Task is an active or completed task.
+type Task struct {
+	ID          string   `json:"id"`
+	ProjectID   string   `json:"project_id"`
+	SectionID   string   `json:"section_id"`
+	ParentID    string   `json:"parent_id"`
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	Priority    int      `json:"priority"`
+	Order       int      `json:"order"`
+	Labels      []string `json:"labels"`
+	// Due, Deadline, and Duration are nil when the task has none.
+	Due      *Due      `json:"due"`
+	Deadline *Deadline `json:"deadline"`
+	Duration *Duration `json:"duration"`
+	// AssigneeID is the REST field and ResponsibleUID the v1 one.
+	AssigneeID     string `json:"assignee_id"`
+	ResponsibleUID string `json:"responsible_uid"`
+	// IsCompleted is the REST field and Checked the v1 one; see Completed.
+	IsCompleted bool `json:"is_completed"`
+	Checked     bool `json:"checked"`
+	// CreatedAt is the REST field and AddedAt the v1 one.
+	CreatedAt string `json:"created_at"`
+	AddedAt   string `json:"added_at"`
+	// UpdatedAt is when the task was last changed, in API v1 responses.
+	UpdatedAt string `json:"updated_at"`
+	// CreatorID is the REST field and AddedByUID the v1 one.
+	CreatorID  string `json:"creator_id"`
+	AddedByUID string `json:"added_by_uid"`
+	// CompletedAt, CompletedByUID, and UserID are set on tasks from the
+	// completed task archive. Older archive records carry their own ID and
+	// point at the task with TaskID.
+	CompletedAt    string `json:"completed_at"`
+	CompletedByUID string `json:"completed_by_uid"`
+	UserID         string `json:"user_id"`
+	TaskID         string `json:"task_id"`
+	// IsDeleted marks deleted tasks in Sync API resources.
+	IsDeleted bool `json:"is_deleted"`
+
+	// Extra holds the fields Task does not model, and any fields a tool adds
+	// to its result with Set.
+	Extra  map[string]interface{} `json:"-"`
+	fields fields
+}
+
+type taskFields Task
+
+// UnmarshalJSON decodes a task, keeping unmodeled fields in Extra.
+func (t *Task) UnmarshalJSON(data []byte) error {
+	var known taskFields
+	extra, err := decode(data, &known, &known.fields)
+	if err != nil {
+		return err
+	}
+	*t = Task(known)
+	t.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the task with its Extra fields.
+func (t Task) MarshalJSON() ([]byte, error) {
+	return encode(taskFields(t), t.Extra, t.fields)
+}
+
+// Set adds a field that Task does not model to its JSON encoding.
+func (t *Task) Set(key string, value interface{}) {
+	set(&t.Extra, key, value)
+}
+
+// Completed reports whether the task is completed in either API's terms.
+func (t *Task) Completed() bool {
+	return t.IsCompleted || t.Checked
+}
+
+// IsTimed reports whether the task is due at a time of day.
+func (t *Task) IsTimed() bool {
+	return t.Due.IsTimed()
+}
+
+// Project is a project. Description is always encoded, since older API
+// responses omit it for projects that have never had one.
+type Project struct {
+	ID             string `json:"id"`
+	ParentID       string `json:"parent_id"`
+	Name           string `json:"name"`
+	Description    string `json:"description" model:"always"`
+	Color          string `json:"color"`
+	IsFavorite     bool   `json:"is_favorite"`
+	IsShared       bool   `json:"is_shared"`
+	IsInboxProject bool   `json:"is_inbox_project"`
+	IsTeamInbox    bool   `json:"is_team_inbox"`
+	IsArchived     bool   `json:"is_archived"`
+	// IsDeleted marks deleted projects in Sync API resources.
+	IsDeleted bool   `json:"is_deleted"`
+	ViewStyle string `json:"view_style"`
+	// WorkspaceID is the team workspace the project belongs to, or empty
+	// for a personal project.
+	WorkspaceID string `json:"workspace_id"`
+
+	// Extra holds the fields Project does not model.
+	Extra  map[string]interface{} `json:"-"`
+	fields fields
+}
+
+type projectFields Project
+
+// UnmarshalJSON decodes a project, keeping unmodeled fields in Extra.
+func (p *Project) UnmarshalJSON(data []byte) error {
+	var known projectFields
+	extra, err := decode(data, &known, &known.fields)
+	if err != nil {
+		return err
+	}
+	*p = Project(known)
+	p.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the project with its Extra fields.
+func (p Project) MarshalJSON() ([]byte, error) {
+	return encode(projectFields(p), p.Extra, p.fields)
+}
+
+// Set adds a field that Project does not model to its JSON encoding.
+func (p *Project) Set(key string, value interface{}) {
+	set(&p.Extra, key, value)
+}
+
+// Section is a section of a project.
+type Section struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	Name      string `json:"name"`
+	Order     int    `json:"order"`
+
+	// Extra holds the fields Section does not model.
+	Extra  map[string]interface{} `json:"-"`
+	fields fields
+}
+
+type sectionFields Section
+
+// UnmarshalJSON decodes a section, keeping unmodeled fields in Extra.
+func (s *Section) UnmarshalJSON(data []byte) error {
+	var known sectionFields
+	extra, err := decode(data, &known, &known.fields)
+	if err != nil {
+		return err
+	}
+	*s = Section(known)
+	s.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the section with its Extra fields.
+func (s Section) MarshalJSON() ([]byte, error) {
+	return encode(sectionFields(s), s.Extra, s.fields)
+}
+
+// Label is a personal label.
+type Label struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Color      string `json:"color"`
+	Order      int    `json:"order"`
+	IsFavorite bool   `json:"is_favorite"`
+
+	// Extra holds the fields Label does not model.
+	Extra  map[string]interface{} `json:"-"`
+	fields fields
+}
+
+type labelFields Label
+
+// UnmarshalJSON decodes a label, keeping unmodeled fields in Extra.
+func (l *Label) UnmarshalJSON(data []byte) error {
+	var known labelFields
+	extra, err := decode(data, &known, &known.fields)
+	if err != nil {
+		return err
+	}
+	*l = Label(known)
+	l.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the label with its Extra fields.
+func (l Label) MarshalJSON() ([]byte, error) {
+	return encode(labelFields(l), l.Extra, l.fields)
+}
+
+// Comment is a comment on a task or a project.
+type Comment struct {
+	ID        string `json:"id"`
+	TaskID    string `json:"task_id"`
+	ProjectID string `json:"project_id"`
+	Content   string `json:"content"`
+	PostedAt  string `json:"posted_at"`
+	// Attachment is the attached file, kept as the API reports it.
+	Attachment json.RawMessage `json:"attachment"`
+
+	// Extra holds the fields Comment does not model.
+	Extra  map[string]interface{} `json:"-"`
+	fields fields
+}
+
+type commentFields Comment
+
+// UnmarshalJSON decodes a comment, keeping unmodeled fields in Extra.
+func (c *Comment) UnmarshalJSON(data []byte) error {
+	var known commentFields
+	extra, err := decode(data, &known, &known.fields)
+	if err != nil {
+		return err
+	}
+	*c = Comment(known)
+	c.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the comment with its Extra fields.
+func (c Comment) MarshalJSON() ([]byte, error) {
+	return encode(commentFields(c), c.Extra, c.fields)
+}
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// assertRoundTrip decodes input into v, encodes it again, and checks that
+// the result is the same JSON.
+func assertRoundTrip(t *testing.T, input string, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(input), v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	output, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	assertSameJSON(t, string(output), input)
+}
+
+func assertSameJSON(t *testing.T, got, want string) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal([]byte(got), &g); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("invalid JSON %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("JSON differs:\n got  %s\n want %s", got, want)
+	}
+}
+
+func TestTask_RoundTrip(t *testing.T) {
+	tests := map[string]string{
+		"rest": `{
+			"id": "2995104339", "project_id": "2203306141", "section_id": null, "parent_id": null,
+			"content": "Buy milk", "description": "", "is_completed": false, "labels": ["errand"],
+			"priority": 4, "comment_count": 0, "creator_id": "2671355", "created_at": "2026-01-02T12:00:00.000000Z",
+			"assignee_id": null, "assigner_id": null, "order": 1, "url": "https://todoist.com/showTask?id=2995104339",
+			"due": {"date": "2026-01-05", "string": "every monday", "lang": "en", "is_recurring": true,
+				"datetime": "2026-01-05T09:00:00.000000Z", "timezone": "Europe/Berlin"},
+			"deadline": {"date": "2026-01-09"},
+			"duration": {"amount": 15, "unit": "minute"}
+		}`,
+		"v1": `{
+			"id": "6X7rM8997g3RQmvh", "v2_id": "2995104339", "project_id": "6Jf8VQXxpwv56VQ7",
+			"section_id": null, "parent_id": null, "content": "Buy milk", "description": "",
+			"checked": false, "is_deleted": false, "labels": [], "priority": 1, "child_order": 3,
+			"added_at": "2026-01-02T12:00:00.000000Z", "added_by_uid": "2671355", "responsible_uid": null,
+			"due": {"date": "2026-01-05T09:00:00", "string": "jan 5 9am", "lang": "en", "is_recurring": false, "timezone": null},
+			"deadline": null, "duration": null, "day_order": -1, "note_count": 2
+		}`,
+		"minimal": `{"id": "1", "content": "Bare"}`,
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			var task Task
+			assertRoundTrip(t, input, &task)
+		})
+	}
+}
+
+func TestTask_Fields(t *testing.T) {
+	var task Task
+	input := `{"id": "1", "content": "Call", "checked": true, "labels": ["phone"], "section_id": null,
+		"due": {"date": "2026-01-05T09:00:00", "is_recurring": false}, "child_order": 3}`
+	if err := json.Unmarshal([]byte(input), &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != "1" || task.Content != "Call" || task.SectionID != "" || len(task.Labels) != 1 {
+		t.Errorf("task = %+v", task)
+	}
+	if !task.Completed() || !task.IsTimed() || task.Due.Day() != "2026-01-05" {
+		t.Errorf("completed %v, timed %v, day %q", task.Completed(), task.IsTimed(), task.Due.Day())
+	}
+	if string(task.Extra["child_order"].(json.RawMessage)) != "3" {
+		t.Errorf("extra = %v", task.Extra)
+	}
+	if task.Deadline != nil || task.Duration != nil || task.Deadline.Day() != "" {
+		t.Error("missing deadline and duration should be nil")
+	}
+	deadline := &Deadline{Date: "2026-02-01"}
+	if deadline.Day() != "2026-02-01" {
+		t.Errorf("deadline day = %q", deadline.Day())
+	}
+
+	var allDay Task
+	if err := json.Unmarshal([]byte(`{"id": "2", "due": {"date": "2026-01-05"}}`), &allDay); err != nil {
+		t.Fatal(err)
+	}
+	var undated Task
+	if allDay.IsTimed() || undated.IsTimed() || undated.Due.Day() != "" {
+		t.Error("all-day and undated tasks are not timed")
+	}
+}
+
+func TestTask_Changes(t *testing.T) {
+	var task Task
+	input := `{"id": "1", "content": "Old", "priority": 2, "section_id": null, "due": {"date": "2026-01-05"}, "order": 7}`
+	if err := json.Unmarshal([]byte(input), &task); err != nil {
+		t.Fatal(err)
+	}
+	task.Content = "New"
+	task.SectionID = "42"
+	task.Priority = 0
+	task.Due = nil
+	task.Labels = []string{"work"}
+	task.Set("is_timed", false)
+
+	output, err := json.Marshal(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameJSON(t, string(output),
+		`{"id": "1", "content": "New", "priority": 0, "section_id": "42", "due": null, "order": 7, "labels": ["work"], "is_timed": false}`)
+}
+
+func TestTask_Constructed(t *testing.T) {
+	output, err := json.Marshal(Task{Content: "Draft", Priority: 3, Due: &Due{String: "tomorrow"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameJSON(t, string(output), `{"content": "Draft", "priority": 3, "due": {"string": "tomorrow"}}`)
+}
+
+func TestProject_RoundTrip(t *testing.T) {
+	var project Project
+	assertRoundTrip(t, `{
+		"id": "2203306141", "parent_id": null, "order": 1, "color": "charcoal", "name": "Inbox",
+		"comment_count": 0, "is_shared": false, "is_favorite": false, "is_inbox_project": true,
+		"is_team_inbox": false, "view_style": "list", "url": "https://todoist.com/showProject?id=2203306141",
+		"description": "", "workspace_id": null, "folder_id": null
+	}`, &project)
+	if !project.IsInboxProject || project.Name != "Inbox" {
+		t.Errorf("project = %+v", project)
+	}
+}
+
+func TestProject_DescriptionAlwaysEncoded(t *testing.T) {
+	var project Project
+	if err := json.Unmarshal([]byte(`{"id": "1", "name": "Old"}`), &project); err != nil {
+		t.Fatal(err)
+	}
+	output, err := json.Marshal(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameJSON(t, string(output), `{"id": "1", "name": "Old", "description": ""}`)
+}
+
+func TestSection_RoundTrip(t *testing.T) {
+	var section Section
+	assertRoundTrip(t, `{"id": "7025", "project_id": "2203306141", "order": 1, "name": "Groceries", "is_collapsed": false}`, &section)
+	if section.Name != "Groceries" || section.Order != 1 {
+		t.Errorf("section = %+v", section)
+	}
+}
+
+func TestLabel_RoundTrip(t *testing.T) {
+	var label Label
+	assertRoundTrip(t, `{"id": "2156154810", "name": "Food", "color": "charcoal", "order": 1, "is_favorite": false}`, &label)
+	if label.Name != "Food" {
+		t.Errorf("label = %+v", label)
+	}
+}
+
+func TestComment_RoundTrip(t *testing.T) {
+	tests := []string{
+		`{"id": "2992679862", "task_id": "2995104339", "project_id": null, "content": "Need one bottle",
+			"posted_at": "2026-01-02T12:00:00.000000Z", "attachment": {"file_name": "File.pdf", "file_type": "application/pdf",
+			"file_url": "https://cdn-domain.tld/path/to/file.pdf", "resource_type": "file"}}`,
+		`{"id": "2992679863", "task_id": null, "project_id": "2203306141", "content": "Plan", "posted_at": "2026-01-02T12:00:00Z", "attachment": null}`,
+	}
+	for _, input := range tests {
+		var comment Comment
+		assertRoundTrip(t, input, &comment)
+	}
+}
+
+func TestModels_Lists(t *testing.T) {
+	input := `[{"id": "1", "name": "Work", "child_order": 2}, {"id": "2", "name": "Home", "description": "Chores"}]`
+	var projects []Project
+	if err := json.Unmarshal([]byte(input), &projects); err != nil {
+		t.Fatal(err)
+	}
+	output, err := json.Marshal(projects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameJSON(t, string(output),
+		`[{"id": "1", "name": "Work", "child_order": 2, "description": ""}, {"id": "2", "name": "Home", "description": "Chores"}]`)
+}
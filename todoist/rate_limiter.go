@@ -1,17 +1,35 @@
 package todoist
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
+// defaultRetryAfter is how long requests are held back after a 429 response
+// that does not say when to retry.
+const defaultRetryAfter = time.Minute
+
 // RateLimiter implements a sliding-window rate limiter safe for concurrent use.
 type RateLimiter struct {
 	mu           sync.Mutex
 	requestTimes []time.Time
 	window       time.Duration
 	maxRequests  int
+	blockedUntil time.Time
+	// version counts changes, so PersistState only writes when needed.
+	version uint64
 }
 
 // NewRateLimiter creates a rate limiter with the given window and max requests.
@@ -41,12 +59,25 @@ func (rl *RateLimiter) Check() error {
 	}
 	rl.requestTimes = valid
 
+	if now.Before(rl.blockedUntil) {
+		wait := rl.blockedUntil.Sub(now)
+		return &RateLimitError{
+			err:        fmt.Errorf("rate limit exceeded: Todoist asked to retry after %s", wait.Round(time.Second)),
+			retryAfter: wait,
+		}
+	}
 	if len(rl.requestTimes) >= rl.maxRequests {
-		return fmt.Errorf("rate limit reached: %d requests in the last %s (max: %d)",
-			len(rl.requestTimes), rl.window, rl.maxRequests)
+		// Capacity returns when the oldest request leaves the window.
+		wait := max(rl.requestTimes[len(rl.requestTimes)-rl.maxRequests].Add(rl.window).Sub(now), 0)
+		return &RateLimitError{
+			err: fmt.Errorf("rate limit reached: %d requests in the last %s (max: %d)",
+				len(rl.requestTimes), rl.window, rl.maxRequests),
+			retryAfter: wait,
+		}
 	}
 
 	rl.requestTimes = append(rl.requestTimes, now)
+	rl.version++
 	return nil
 }
 
@@ -56,6 +87,9 @@ func (rl *RateLimiter) Remaining() int {
 	defer rl.mu.Unlock()
 
 	now := time.Now()
+	if now.Before(rl.blockedUntil) {
+		return 0
+	}
 	cutoff := now.Add(-rl.window)
 
 	count := 0
@@ -67,3 +101,209 @@ func (rl *RateLimiter) Remaining() int {
 
 	return rl.maxRequests - count
 }
+
+// Observe updates the limiter from an API response. Locally counted requests
+// drift when other clients share the token, so a server-reported remaining
+// count (X-RateLimit-Remaining) replaces the local one. A 429 response blocks
+// all requests until its Retry-After has passed.
+func (rl *RateLimiter) Observe(statusCode int, header http.Header, body []byte) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.version++
+
+	now := time.Now()
+	if statusCode == http.StatusTooManyRequests {
+		wait, ok := retryAfter(header, body, now)
+		if !ok {
+			wait = defaultRetryAfter
+		}
+		if until := now.Add(wait); until.After(rl.blockedUntil) {
+			rl.blockedUntil = until
+		}
+	}
+
+	remaining, err := strconv.Atoi(strings.TrimSpace(header.Get("X-RateLimit-Remaining")))
+	if err != nil || remaining < 0 {
+		return
+	}
+	used := max(rl.maxRequests-remaining, 0)
+
+	cutoff := now.Add(-rl.window)
+	valid := rl.requestTimes[:0]
+	for _, t := range rl.requestTimes {
+		if t.After(cutoff) {
+			valid = append(valid, t)
+		}
+	}
+	rl.requestTimes = valid
+
+	if len(rl.requestTimes) > used {
+		// The server counted fewer requests; forget the oldest local ones.
+		slices.SortFunc(rl.requestTimes, func(a, b time.Time) int { return a.Compare(b) })
+		rl.requestTimes = rl.requestTimes[len(rl.requestTimes)-used:]
+		return
+	}
+
+	// Requests made elsewhere are recorded so that they expire when the
+	// server's window resets, or a full window from now if it did not say.
+	at := now
+	if reset, ok := rateLimitReset(header.Get("X-RateLimit-Reset"), now); ok {
+		if start := reset.Add(-rl.window); start.Before(now) {
+			at = start
+		}
+	}
+	for len(rl.requestTimes) < used {
+		rl.requestTimes = append(rl.requestTimes, at)
+	}
+}
+
+// rateLimiterState is the on-disk form of a RateLimiter.
+type rateLimiterState struct {
+	RequestTimes []time.Time `json:"request_times"`
+	BlockedUntil time.Time   `json:"blocked_until,omitzero"`
+}
+
+// SaveState writes the requests still inside the window, and any 429 block,
+// to path so a restarted server keeps its accounting. The file is replaced
+// atomically.
+func (rl *RateLimiter) SaveState(path string) error {
+	rl.mu.Lock()
+	now := time.Now()
+	cutoff := now.Add(-rl.window)
+	state := rateLimiterState{RequestTimes: make([]time.Time, 0, len(rl.requestTimes))}
+	for _, t := range rl.requestTimes {
+		if t.After(cutoff) {
+			state.RequestTimes = append(state.RequestTimes, t)
+		}
+	}
+	if now.Before(rl.blockedUntil) {
+		state.BlockedUntil = rl.blockedUntil
+	}
+	rl.mu.Unlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encoding rate limit state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating rate limit state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".ratelimit-*.json")
+	if err != nil {
+		return fmt.Errorf("writing rate limit state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing rate limit state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing rate limit state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing rate limit state: %w", err)
+	}
+	return nil
+}
+
+// LoadState restores requests saved by SaveState, dropping those that have
+// left the window. A missing file is not an error.
+func (rl *RateLimiter) LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading rate limit state: %w", err)
+	}
+	var state rateLimiterState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("parsing rate limit state %s: %w", path, err)
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-rl.window)
+	for _, t := range state.RequestTimes {
+		// Timestamps from the future (clock changes) are clamped to now.
+		if t.After(cutoff) {
+			if t.After(now) {
+				t = now
+			}
+			rl.requestTimes = append(rl.requestTimes, t)
+		}
+	}
+	if state.BlockedUntil.After(rl.blockedUntil) {
+		rl.blockedUntil = state.BlockedUntil
+	}
+	return nil
+}
+
+// PersistState saves the limiter to path every interval while it is changing,
+// and once more when ctx ends. Save errors are logged, not fatal.
+func (rl *RateLimiter) PersistState(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var saved uint64
+	save := func() {
+		rl.mu.Lock()
+		version := rl.version
+		rl.mu.Unlock()
+		if version == saved {
+			return
+		}
+		if err := rl.SaveState(path); err != nil {
+			slog.Warn("failed to save rate limit state", "path", path, "error", err)
+			return
+		}
+		saved = version
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			save()
+		case <-ctx.Done():
+			save()
+			return
+		}
+	}
+}
+
+// retryAfter reads how long to wait after a 429 from the Retry-After header
+// (seconds or an HTTP date) or the retry_after field of Todoist's error body.
+func retryAfter(header http.Header, body []byte, now time.Time) (time.Duration, bool) {
+	if v := strings.TrimSpace(header.Get("Retry-After")); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(t.Sub(now), 0), true
+		}
+	}
+	var errBody struct {
+		ErrorExtra struct {
+			RetryAfter float64 `json:"retry_after"`
+		} `json:"error_extra"`
+	}
+	if json.Unmarshal(body, &errBody) == nil && errBody.ErrorExtra.RetryAfter > 0 {
+		return time.Duration(errBody.ErrorExtra.RetryAfter * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+// rateLimitReset parses X-RateLimit-Reset, given either as a Unix timestamp or
+// as seconds until the window resets.
+func rateLimitReset(v string, now time.Time) (time.Time, bool) {
+	n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+	if n > 1_000_000_000 {
+		return time.Unix(n, 0), true
+	}
+	return now.Add(time.Duration(n) * time.Second), true
+}
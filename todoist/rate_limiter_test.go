@@ -1,6 +1,12 @@
 package todoist
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -45,8 +51,13 @@ func TestRateLimiter_Check_RespectsLimit(t *testing.T) {
 		}
 	}
 
-	if err := rl.Check(); err == nil {
-		t.Fatal("Check() should fail when limit is reached")
+	err := rl.Check()
+	var limitErr *RateLimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("Check() = %v, want RateLimitError when limit is reached", err)
+	}
+	if wait := limitErr.RetryAfter(); wait > 15*time.Minute || wait < 14*time.Minute {
+		t.Errorf("RetryAfter() = %v, want about 15m", wait)
 	}
 }
 
@@ -93,3 +104,163 @@ func TestRateLimiter_ConcurrentAccess(t *testing.T) {
 		t.Errorf("Remaining() = %d, want 50", got)
 	}
 }
+
+func TestRateLimiter_Observe_RemainingHeader(t *testing.T) {
+	rl := NewRateLimiter(15*time.Minute, 10)
+	_ = rl.Check()
+
+	// Another client sharing the token has used 5 more requests.
+	rl.Observe(http.StatusOK, http.Header{"X-Ratelimit-Remaining": []string{"4"}}, nil)
+	if got := rl.Remaining(); got != 4 {
+		t.Errorf("Remaining() after server reported 4 = %d, want 4", got)
+	}
+
+	// The server may also report more capacity than counted locally.
+	rl.Observe(http.StatusOK, http.Header{"X-Ratelimit-Remaining": []string{"8"}}, nil)
+	if got := rl.Remaining(); got != 8 {
+		t.Errorf("Remaining() after server reported 8 = %d, want 8", got)
+	}
+
+	// Without the header the local count is kept.
+	rl.Observe(http.StatusOK, http.Header{}, nil)
+	if got := rl.Remaining(); got != 8 {
+		t.Errorf("Remaining() without header = %d, want 8", got)
+	}
+}
+
+func TestRateLimiter_Observe_ResetHeader(t *testing.T) {
+	rl := NewRateLimiter(time.Hour, 10)
+	rl.Observe(http.StatusOK, http.Header{
+		"X-Ratelimit-Remaining": []string{"0"},
+		"X-Ratelimit-Reset":     []string{"0"},
+	}, nil)
+
+	// Requests counted by the server expire at its reset, not a window from now.
+	if got := rl.Remaining(); got != 10 {
+		t.Errorf("Remaining() after immediate reset = %d, want 10", got)
+	}
+}
+
+func TestRateLimiter_Observe_TooManyRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   http.Header
+		body     string
+		wantWait time.Duration
+	}{
+		{name: "Retry-After seconds", header: http.Header{"Retry-After": []string{"30"}}, wantWait: 30 * time.Second},
+		{name: "error body retry_after", header: http.Header{}, body: `{"error": "Too many requests", "error_extra": {"retry_after": 12}}`, wantWait: 12 * time.Second},
+		{name: "no hint", header: http.Header{}, wantWait: defaultRetryAfter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl := NewRateLimiter(15*time.Minute, 450)
+			rl.Observe(http.StatusTooManyRequests, tt.header, []byte(tt.body))
+
+			if got := rl.Remaining(); got != 0 {
+				t.Errorf("Remaining() while blocked = %d, want 0", got)
+			}
+			err := rl.Check()
+			if err == nil || !strings.Contains(err.Error(), "retry after") {
+				t.Fatalf("Check() while blocked = %v, want retry error", err)
+			}
+			if wait := time.Until(rl.blockedUntil); wait > tt.wantWait || wait < tt.wantWait-time.Second {
+				t.Errorf("blocked for %v, want %v", wait, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_Observe_BlockExpires(t *testing.T) {
+	rl := NewRateLimiter(15*time.Minute, 450)
+	rl.Observe(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"0"}}, nil)
+	if err := rl.Check(); err != nil {
+		t.Errorf("Check() after Retry-After elapsed: %v", err)
+	}
+}
+
+func TestRateLimiter_SaveLoadState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "ratelimit.json")
+
+	rl := NewRateLimiter(15*time.Minute, 10)
+	for i := 0; i < 3; i++ {
+		if err := rl.Check(); err != nil {
+			t.Fatalf("Check() error: %v", err)
+		}
+	}
+	rl.requestTimes = append(rl.requestTimes, time.Now().Add(-20*time.Minute))
+	if err := rl.SaveState(path); err != nil {
+		t.Fatalf("SaveState() error: %v", err)
+	}
+
+	restored := NewRateLimiter(15*time.Minute, 10)
+	if err := restored.LoadState(path); err != nil {
+		t.Fatalf("LoadState() error: %v", err)
+	}
+	if got := restored.Remaining(); got != 7 {
+		t.Errorf("Remaining() after restore = %d, want 7", got)
+	}
+}
+
+func TestRateLimiter_LoadState_KeepsBlock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit.json")
+
+	rl := NewRateLimiter(15*time.Minute, 10)
+	rl.Observe(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"120"}}, nil)
+	if err := rl.SaveState(path); err != nil {
+		t.Fatalf("SaveState() error: %v", err)
+	}
+
+	restored := NewRateLimiter(15*time.Minute, 10)
+	if err := restored.LoadState(path); err != nil {
+		t.Fatalf("LoadState() error: %v", err)
+	}
+	if err := restored.Check(); err == nil || !strings.Contains(err.Error(), "retry after") {
+		t.Errorf("Check() after restore = %v, want retry-after error", err)
+	}
+}
+
+func TestRateLimiter_LoadState_MissingOrInvalid(t *testing.T) {
+	dir := t.TempDir()
+	rl := NewRateLimiter(15*time.Minute, 10)
+	if err := rl.LoadState(filepath.Join(dir, "missing.json")); err != nil {
+		t.Errorf("LoadState() on missing file error: %v", err)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := rl.LoadState(bad); err == nil {
+		t.Error("LoadState() on invalid file should fail")
+	}
+	if got := rl.Remaining(); got != 10 {
+		t.Errorf("Remaining() = %d, want 10", got)
+	}
+}
+
+func TestRateLimiter_PersistState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit.json")
+	rl := NewRateLimiter(15*time.Minute, 10)
+	if err := rl.Check(); err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		rl.PersistState(ctx, path, time.Hour)
+		close(done)
+	}()
+	cancel()
+	<-done
+
+	restored := NewRateLimiter(15*time.Minute, 10)
+	if err := restored.LoadState(path); err != nil {
+		t.Fatalf("LoadState() error: %v", err)
+	}
+	if got := restored.Remaining(); got != 9 {
+		t.Errorf("Remaining() after restore = %d, want 9", got)
+	}
+}
@@ -20,6 +20,24 @@ type RetryableError struct {
 func (e *RetryableError) Error() string { return e.err.Error() }
 func (e *RetryableError) Unwrap() error { return e.err }
 
+// RetryAfter suggests how long callers should wait before trying again once
+// the automatic retries are used up.
+func (e *RetryableError) RetryAfter() time.Duration { return maxDelay }
+
+// RateLimitError reports a request refused by the local rate limiter or by
+// Todoist with a 429. It is not retried automatically: waiting out the limit
+// takes longer than a tool call should block.
+type RateLimitError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string { return e.err.Error() }
+func (e *RateLimitError) Unwrap() error { return e.err }
+
+// RetryAfter is how long until a request is expected to be accepted again.
+func (e *RateLimitError) RetryAfter() time.Duration { return e.retryAfter }
+
 // retryWithBackoff executes fn up to maxAttempts times with exponential backoff.
 // Only retries when fn returns a RetryableError.
 func retryWithBackoff(ctx context.Context, attempts int, fn func() error) error {
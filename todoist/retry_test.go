@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 )
@@ -103,3 +104,19 @@ func TestRetryableError_Unwrap(t *testing.T) {
 		t.Error("expected errors.As to match RetryableError")
 	}
 }
+
+func TestHandleHTTPError_RetryHints(t *testing.T) {
+	var limitErr *RateLimitError
+	err := handleHTTPError(429, http.Header{"Retry-After": []string{"42"}}, nil)
+	if !errors.As(err, &limitErr) || limitErr.RetryAfter() != 42*time.Second {
+		t.Errorf("429 error = %v, want RateLimitError retrying after 42s", err)
+	}
+
+	var retryable *RetryableError
+	if err := handleHTTPError(503, http.Header{}, nil); !errors.As(err, &retryable) || retryable.RetryAfter() != maxDelay {
+		t.Errorf("503 error = %v, want RetryableError", err)
+	}
+	if err := handleHTTPError(404, http.Header{}, nil); errors.As(err, &retryable) || errors.As(err, &limitErr) {
+		t.Errorf("404 error = %v, want no retry hint", err)
+	}
+}
@@ -0,0 +1,146 @@
+package todoist
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Priority orders requests waiting for a Scheduler slot.
+type Priority int
+
+const (
+	// PriorityInteractive is the default for requests serving a single tool
+	// call, such as fetching today's tasks.
+	PriorityInteractive Priority = iota
+	// PriorityBulk marks the many sub-requests of a bulk operation.
+	PriorityBulk
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a context whose requests are scheduled at p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// priorityFrom returns the request priority carried by ctx.
+func priorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityInteractive
+}
+
+// Scheduler bounds the number of API requests in flight. Waiting interactive
+// requests are always admitted before waiting bulk requests, and one slot is
+// kept free of bulk work, so a quick lookup is never stuck behind a large
+// bulk update. A nil Scheduler admits every request immediately.
+type Scheduler struct {
+	mu          sync.Mutex
+	maxInFlight int
+	inFlight    int
+	bulk        int
+	waiting     [2][]*waiter
+}
+
+// waiter is a request queued for a slot; ready is closed once it is admitted.
+type waiter struct {
+	ready chan struct{}
+}
+
+// NewScheduler creates a scheduler allowing maxInFlight concurrent requests.
+func NewScheduler(maxInFlight int) *Scheduler {
+	return &Scheduler{maxInFlight: max(maxInFlight, 1)}
+}
+
+// Acquire blocks until the request may be sent, then returns a function that
+// must be called when it completes. It fails only if ctx ends first.
+func (s *Scheduler) Acquire(ctx context.Context) (func(), error) {
+	if s == nil {
+		return func() {}, nil
+	}
+	p := priorityFrom(ctx)
+
+	s.mu.Lock()
+	if s.canAdmit(p) && len(s.waiting[PriorityInteractive]) == 0 && (p == PriorityInteractive || len(s.waiting[PriorityBulk]) == 0) {
+		s.admit(p)
+		s.mu.Unlock()
+		return s.releaseFunc(p), nil
+	}
+	w := &waiter{ready: make(chan struct{})}
+	s.waiting[p] = append(s.waiting[p], w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return s.releaseFunc(p), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if i := slices.Index(s.waiting[p], w); i >= 0 {
+			s.waiting[p] = slices.Delete(s.waiting[p], i, i+1)
+			return nil, ctx.Err()
+		}
+		// Admitted while giving up: hand the slot on.
+		s.release(p)
+		return nil, ctx.Err()
+	}
+}
+
+// Stats reports the requests currently in flight and waiting for a slot.
+func (s *Scheduler) Stats() (inFlight, waiting int) {
+	if s == nil {
+		return 0, 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inFlight, len(s.waiting[PriorityInteractive]) + len(s.waiting[PriorityBulk])
+}
+
+// canAdmit reports whether a request at p fits now. Bulk requests may not
+// take the last free slot when more than one is configured.
+func (s *Scheduler) canAdmit(p Priority) bool {
+	if p == PriorityBulk && s.maxInFlight > 1 {
+		return s.inFlight < s.maxInFlight && s.bulk < s.maxInFlight-1
+	}
+	return s.inFlight < s.maxInFlight
+}
+
+func (s *Scheduler) admit(p Priority) {
+	s.inFlight++
+	if p == PriorityBulk {
+		s.bulk++
+	}
+}
+
+func (s *Scheduler) releaseFunc(p Priority) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.release(p)
+		})
+	}
+}
+
+// release frees a slot and admits waiting requests, interactive ones first.
+func (s *Scheduler) release(p Priority) {
+	s.inFlight--
+	if p == PriorityBulk {
+		s.bulk--
+	}
+	for _, q := range []Priority{PriorityInteractive, PriorityBulk} {
+		for len(s.waiting[q]) > 0 && s.canAdmit(q) {
+			w := s.waiting[q][0]
+			s.waiting[q] = s.waiting[q][1:]
+			s.admit(q)
+			close(w.ready)
+		}
+		if len(s.waiting[q]) > 0 {
+			// Keep lower priorities queued behind a waiting higher one.
+			return
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package todoist
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// acquireAsync starts Acquire in a goroutine and returns a channel that
+// receives its release function once admitted.
+func acquireAsync(t *testing.T, s *Scheduler, ctx context.Context) <-chan func() {
+	t.Helper()
+	admitted := make(chan func(), 1)
+	go func() {
+		release, err := s.Acquire(ctx)
+		if err == nil {
+			admitted <- release
+		}
+	}()
+	return admitted
+}
+
+func waitAdmitted(t *testing.T, admitted <-chan func(), want bool) func() {
+	t.Helper()
+	select {
+	case release := <-admitted:
+		if !want {
+			t.Fatal("request was admitted, want it queued")
+		}
+		return release
+	case <-time.After(50 * time.Millisecond):
+		if want {
+			t.Fatal("request was not admitted")
+		}
+		return nil
+	}
+}
+
+func TestScheduler_ReservesSlotForInteractive(t *testing.T) {
+	s := NewScheduler(2)
+	bulkCtx := WithPriority(context.Background(), PriorityBulk)
+
+	releaseBulk := waitAdmitted(t, acquireAsync(t, s, bulkCtx), true)
+	queuedBulk := acquireAsync(t, s, bulkCtx)
+	waitAdmitted(t, queuedBulk, false)
+
+	// The reserved slot serves an interactive request right away.
+	releaseInteractive := waitAdmitted(t, acquireAsync(t, s, context.Background()), true)
+	releaseInteractive()
+	waitAdmitted(t, queuedBulk, false)
+
+	releaseBulk()
+	waitAdmitted(t, queuedBulk, true)()
+}
+
+func TestScheduler_InteractiveOvertakesQueuedBulk(t *testing.T) {
+	s := NewScheduler(1)
+	bulkCtx := WithPriority(context.Background(), PriorityBulk)
+
+	release := waitAdmitted(t, acquireAsync(t, s, context.Background()), true)
+	queuedBulk := acquireAsync(t, s, bulkCtx)
+	waitAdmitted(t, queuedBulk, false)
+	queuedInteractive := acquireAsync(t, s, context.Background())
+	waitAdmitted(t, queuedInteractive, false)
+
+	release()
+	releaseInteractive := waitAdmitted(t, queuedInteractive, true)
+	waitAdmitted(t, queuedBulk, false)
+	releaseInteractive()
+	waitAdmitted(t, queuedBulk, true)()
+}
+
+func TestScheduler_CancelWhileQueued(t *testing.T) {
+	s := NewScheduler(1)
+	release, err := s.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire() error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire() on a full scheduler = %v, want deadline exceeded", err)
+	}
+
+	release()
+	release() // releasing twice must not free a second slot
+	if s.inFlight != 0 || len(s.waiting[PriorityInteractive]) != 0 {
+		t.Errorf("inFlight = %d, waiting = %d; want 0, 0", s.inFlight, len(s.waiting[PriorityInteractive]))
+	}
+}
+
+func TestScheduler_Nil(t *testing.T) {
+	var s *Scheduler
+	release, err := s.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("nil scheduler Acquire() error: %v", err)
+	}
+	release()
+}
+
+func TestScheduler_Stats(t *testing.T) {
+	s := NewScheduler(1)
+	release, err := s.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire() error: %v", err)
+	}
+	queued := acquireAsync(t, s, context.Background())
+	waitAdmitted(t, queued, false)
+
+	if inFlight, waiting := s.Stats(); inFlight != 1 || waiting != 1 {
+		t.Errorf("Stats() = %d, %d, want 1, 1", inFlight, waiting)
+	}
+	release()
+	waitAdmitted(t, queued, true)()
+	if inFlight, waiting := s.Stats(); inFlight != 0 || waiting != 0 {
+		t.Errorf("Stats() after release = %d, %d, want 0, 0", inFlight, waiting)
+	}
+
+	var nilScheduler *Scheduler
+	if inFlight, waiting := nilScheduler.Stats(); inFlight != 0 || waiting != 0 {
+		t.Errorf("nil Stats() = %d, %d, want 0, 0", inFlight, waiting)
+	}
+}
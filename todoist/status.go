@@ -0,0 +1,147 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// statusFailureThreshold is how many 5xx responses in a row make the
+	// monitor consult the status page.
+	statusFailureThreshold = 3
+	// statusCacheTTL is how long a status page answer is reused.
+	statusCacheTTL = 5 * time.Minute
+	// degradedRetryAfter is the wait suggested while Todoist reports an incident.
+	degradedRetryAfter = time.Minute
+)
+
+// StatusMonitor consults a Statuspage-style status endpoint
+// (/api/v2/status.json) after repeated server errors, so errors during a
+// Todoist incident say so and agents pause instead of retrying at once. A nil
+// StatusMonitor does nothing.
+type StatusMonitor struct {
+	url        string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	failures  int
+	checkedAt time.Time
+	// incident describes the reported degradation; empty when operational.
+	incident string
+}
+
+// NewStatusMonitor creates a monitor querying the status endpoint at url.
+func NewStatusMonitor(url string) *StatusMonitor {
+	return &StatusMonitor{url: url, httpClient: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// DegradedError is a server error returned while the status page reports an
+// incident. It is not retried automatically, since retries are unlikely to
+// help until the incident is resolved.
+type DegradedError struct {
+	err      error
+	incident string
+}
+
+func (e *DegradedError) Error() string {
+	return fmt.Sprintf("%v; Todoist reports degraded performance (%s), so pause before retrying", e.err, e.incident)
+}
+func (e *DegradedError) Unwrap() error { return e.err }
+
+// RetryAfter suggests waiting out part of the incident before trying again.
+func (e *DegradedError) RetryAfter() time.Duration { return degradedRetryAfter }
+
+// observe counts consecutive 5xx responses and returns err, annotated with
+// the reported incident once the threshold is reached and the status page
+// reports one.
+func (m *StatusMonitor) observe(ctx context.Context, statusCode int, err error) error {
+	if m == nil {
+		return err
+	}
+	m.mu.Lock()
+	if statusCode < 500 {
+		m.failures = 0
+		m.mu.Unlock()
+		return err
+	}
+	m.failures++
+	failures := m.failures
+	m.mu.Unlock()
+
+	if failures < statusFailureThreshold || err == nil {
+		return err
+	}
+	incident := m.check(ctx)
+	if incident == "" {
+		return err
+	}
+	// Unwrap the RetryableError so the automatic retries stop.
+	var retryable *RetryableError
+	if errors.As(err, &retryable) {
+		err = retryable.err
+	}
+	return &DegradedError{err: err, incident: incident}
+}
+
+// check returns the incident the status page reports, from cache when it was
+// queried recently. A status page that cannot be read counts as operational.
+func (m *StatusMonitor) check(ctx context.Context) string {
+	m.mu.Lock()
+	if time.Since(m.checkedAt) < statusCacheTTL {
+		incident := m.incident
+		m.mu.Unlock()
+		return incident
+	}
+	m.mu.Unlock()
+
+	incident, err := m.fetch(ctx)
+	if err != nil {
+		incident = ""
+	}
+	m.mu.Lock()
+	m.checkedAt = time.Now()
+	m.incident = incident
+	m.mu.Unlock()
+	return incident
+}
+
+func (m *StatusMonitor) fetch(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status page returned %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	var page struct {
+		Status struct {
+			Indicator   string `json:"indicator"`
+			Description string `json:"description"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return "", err
+	}
+	if page.Status.Indicator == "" || page.Status.Indicator == "none" {
+		return "", nil
+	}
+	if page.Status.Description == "" {
+		return page.Status.Indicator, nil
+	}
+	return page.Status.Description, nil
+}
@@ -0,0 +1,89 @@
+package todoist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func statusPage(t *testing.T, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestStatusMonitor_AnnotatesAfterRepeatedServerErrors(t *testing.T) {
+	srv, hits := statusPage(t, `{"status":{"indicator":"major","description":"Partial System Outage"}}`)
+	m := NewStatusMonitor(srv.URL)
+	ctx := context.Background()
+
+	for i := 1; i < statusFailureThreshold; i++ {
+		err := m.observe(ctx, 503, handleHTTPError(503, http.Header{}, nil))
+		var retryable *RetryableError
+		if !errors.As(err, &retryable) {
+			t.Fatalf("failure %d: expected plain retryable error, got %v", i, err)
+		}
+	}
+	if hits.Load() != 0 {
+		t.Fatal("status page queried before the threshold")
+	}
+
+	err := m.observe(ctx, 503, handleHTTPError(503, http.Header{}, nil))
+	var degraded *DegradedError
+	if !errors.As(err, &degraded) {
+		t.Fatalf("expected DegradedError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Todoist reports degraded performance (Partial System Outage)") {
+		t.Errorf("unexpected message %q", err)
+	}
+	var retryable *RetryableError
+	if errors.As(err, &retryable) {
+		t.Error("degraded errors should not be retried automatically")
+	}
+	if degraded.RetryAfter() != degradedRetryAfter {
+		t.Errorf("RetryAfter() = %v", degraded.RetryAfter())
+	}
+
+	// The answer is cached.
+	_ = m.observe(ctx, 500, handleHTTPError(500, http.Header{}, nil))
+	if hits.Load() != 1 {
+		t.Errorf("expected 1 status page query, got %d", hits.Load())
+	}
+
+	// A success resets the count.
+	_ = m.observe(ctx, 200, nil)
+	if err := m.observe(ctx, 502, handleHTTPError(502, http.Header{}, nil)); errors.As(err, &degraded) {
+		t.Errorf("expected count reset after success, got %v", err)
+	}
+}
+
+func TestStatusMonitor_Operational(t *testing.T) {
+	srv, _ := statusPage(t, `{"status":{"indicator":"none","description":"All Systems Operational"}}`)
+	m := NewStatusMonitor(srv.URL)
+	var err error
+	for range statusFailureThreshold {
+		err = m.observe(context.Background(), 500, handleHTTPError(500, http.Header{}, nil))
+	}
+	var retryable *RetryableError
+	if !errors.As(err, &retryable) || strings.Contains(err.Error(), "degraded") {
+		t.Errorf("expected unannotated retryable error, got %v", err)
+	}
+}
+
+func TestStatusMonitor_Nil(t *testing.T) {
+	var m *StatusMonitor
+	want := errors.New("boom")
+	if err := m.observe(context.Background(), 500, want); err != want {
+		t.Errorf("nil monitor changed the error: %v", err)
+	}
+}
@@ -8,20 +8,26 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 const (
-	syncBaseURL = "https://api.todoist.com/api/v1/sync"
+	apiV1BaseURL = "https://api.todoist.com/api/v1"
+	syncBaseURL  = apiV1BaseURL + "/sync"
 )
 
-// SyncClient wraps the HTTP client for Todoist Sync API v1.
+// SyncClient wraps the HTTP client for Todoist Sync API v1 and the read-only
+// endpoints of the unified API v1 (completed tasks, stats, activity).
 type SyncClient struct {
 	httpClient  *http.Client
-	apiToken    string
+	apiToken    atomic.Pointer[string]
 	rateLimiter *RateLimiter
+	scheduler   *Scheduler
+	dumper      *FixtureDumper
+	status      *StatusMonitor
 }
 
 // Command represents a Sync API command.
@@ -40,9 +46,9 @@ type SyncResponse struct {
 	FullSync      bool                   `json:"full_sync"`
 }
 
-// NewSyncClient creates a new Todoist Sync API client with a shared rate limiter.
-func NewSyncClient(apiToken string, rl *RateLimiter) *SyncClient {
-	return &SyncClient{
+// NewSyncClient creates a new Todoist Sync API client with a shared rate limiter and request scheduler.
+func NewSyncClient(apiToken string, rl *RateLimiter, sched *Scheduler) *SyncClient {
+	sc := &SyncClient{
 		httpClient: &http.Client{
 			Timeout: timeout,
 			Transport: &http.Transport{
@@ -51,9 +57,29 @@ func NewSyncClient(apiToken string, rl *RateLimiter) *SyncClient {
 				DisableCompression: false,
 			},
 		},
-		apiToken:    apiToken,
 		rateLimiter: rl,
+		scheduler:   sched,
 	}
+	sc.SetToken(apiToken)
+	return sc
+}
+
+// SetToken replaces the API token used by subsequent requests, e.g. after
+// a rotated secret was fetched again.
+func (sc *SyncClient) SetToken(apiToken string) {
+	sc.apiToken.Store(&apiToken)
+}
+
+// SetFixtureDumper makes failing requests write fixtures through d. Call it
+// before the client is used.
+func (sc *SyncClient) SetFixtureDumper(d *FixtureDumper) {
+	sc.dumper = d
+}
+
+// SetStatusMonitor makes repeated server errors consult the status page
+// through m. Call it before the client is used.
+func (sc *SyncClient) SetStatusMonitor(m *StatusMonitor) {
+	sc.status = m
 }
 
 // BatchCommands sends multiple commands in a single Sync API request.
@@ -72,6 +98,11 @@ func (sc *SyncClient) doBatchRequest(ctx context.Context, commands []Command) (*
 	if err := sc.rateLimiter.Check(); err != nil {
 		return nil, err
 	}
+	release, err := sc.scheduler.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	commandsJSON, err := json.Marshal(commands)
 	if err != nil {
@@ -80,38 +111,99 @@ func (sc *SyncClient) doBatchRequest(ctx context.Context, commands []Command) (*
 
 	formData := url.Values{}
 	formData.Set("commands", string(commandsJSON))
+	encoded := []byte(formData.Encode())
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, syncBaseURL, bytes.NewBufferString(formData.Encode()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, syncBaseURL, bytes.NewReader(encoded))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+sc.apiToken)
+	token := *sc.apiToken.Load()
+	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := sc.httpClient.Do(req)
 	if err != nil {
+		sc.dumper.dump(token, req, encoded, nil, nil, err)
 		return nil, &RetryableError{err: fmt.Errorf("request failed: %w", err)}
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
+		sc.dumper.dump(token, req, encoded, resp, nil, err)
 		return nil, &RetryableError{err: fmt.Errorf("failed to read response: %w", err)}
 	}
+	sc.rateLimiter.Observe(resp.StatusCode, resp.Header, respBody)
 
 	if resp.StatusCode >= 400 {
-		return nil, handleHTTPError(resp.StatusCode, respBody)
+		sc.dumper.dump(token, req, encoded, resp, respBody, nil)
+		return nil, sc.status.observe(ctx, resp.StatusCode, handleHTTPError(resp.StatusCode, resp.Header, respBody))
 	}
+	sc.status.observe(ctx, resp.StatusCode, nil)
 
 	var syncResp SyncResponse
 	if err := json.Unmarshal(respBody, &syncResp); err != nil {
+		sc.dumper.dump(token, req, encoded, resp, respBody, err)
 		return nil, fmt.Errorf("failed to parse sync response: %w", err)
 	}
 
 	return &syncResp, nil
 }
 
+// Get performs a GET request against the unified API v1 with automatic retry on
+// transient failures. The path is relative to https://api.todoist.com/api/v1.
+func (sc *SyncClient) Get(ctx context.Context, path string) ([]byte, error) {
+	var result []byte
+	err := retryWithBackoff(ctx, maxAttempts, func() error {
+		var reqErr error
+		result, reqErr = sc.doGetRequest(ctx, path)
+		return reqErr
+	})
+	return result, err
+}
+
+func (sc *SyncClient) doGetRequest(ctx context.Context, path string) ([]byte, error) {
+	if err := sc.rateLimiter.Check(); err != nil {
+		return nil, err
+	}
+	release, err := sc.scheduler.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiV1BaseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token := *sc.apiToken.Load()
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := sc.httpClient.Do(req)
+	if err != nil {
+		sc.dumper.dump(token, req, nil, nil, nil, err)
+		return nil, &RetryableError{err: fmt.Errorf("request failed: %w", err)}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		sc.dumper.dump(token, req, nil, resp, nil, err)
+		return nil, &RetryableError{err: fmt.Errorf("failed to read response: %w", err)}
+	}
+	sc.rateLimiter.Observe(resp.StatusCode, resp.Header, respBody)
+
+	if resp.StatusCode >= 400 {
+		sc.dumper.dump(token, req, nil, resp, respBody, nil)
+		return nil, sc.status.observe(ctx, resp.StatusCode, handleHTTPError(resp.StatusCode, resp.Header, respBody))
+	}
+	sc.status.observe(ctx, resp.StatusCode, nil)
+
+	return respBody, nil
+}
+
 // GetRemainingRequests returns how many requests are available in the current window.
 func (sc *SyncClient) GetRemainingRequests() int {
 	return sc.rateLimiter.Remaining()
@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// maxActivityPages caps pagination through the activity log.
+const maxActivityPages = 10
+
+// fetchActivities pages through the API v1 activity log with the given query parameters.
+func fetchActivities(ctx context.Context, syncClient todoist.SyncAPI, query url.Values) ([]map[string]interface{}, error) {
+	var events []map[string]interface{}
+	cursor := ""
+
+	for page := 0; page < maxActivityPages; page++ {
+		params := url.Values{}
+		for key, values := range query {
+			params[key] = values
+		}
+		params.Set("limit", "100")
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		respBody, err := syncClient.Get(ctx, "/activities?"+params.Encode())
+		if err != nil {
+			return nil, err
+		}
+
+		var resp struct {
+			Results    []map[string]interface{} `json:"results"`
+			NextCursor *string                  `json:"next_cursor"`
+		}
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse activity log: %w", err)
+		}
+
+		events = append(events, resp.Results...)
+
+		if resp.NextCursor == nil || *resp.NextCursor == "" {
+			break
+		}
+		cursor = *resp.NextCursor
+	}
+
+	return events, nil
+}
+
+// TaskHistoryHandler creates a handler for retrieving the activity history of a task.
+func TaskHistoryHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		taskID, ok := args["task_id"].(string)
+		if !ok || taskID == "" {
+			return respond.Error("task_id is required"), nil
+		}
+		if err := ValidateID(taskID, "task_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		itemQuery := url.Values{}
+		itemQuery.Set("object_type", "item")
+		itemQuery.Set("object_id", taskID)
+		itemEvents, err := fetchActivities(ctx, syncClient, itemQuery)
+		if err != nil {
+			return respond.Errorf("failed to fetch task history: %v", err), nil
+		}
+
+		noteQuery := url.Values{}
+		noteQuery.Set("object_type", "note")
+		noteQuery.Set("parent_item_id", taskID)
+		noteEvents, err := fetchActivities(ctx, syncClient, noteQuery)
+		if err != nil {
+			return respond.Errorf("failed to fetch comment history: %v", err), nil
+		}
+
+		events := make([]map[string]interface{}, 0, len(itemEvents)+len(noteEvents))
+		for _, e := range itemEvents {
+			events = append(events, historyEntry(e, "task"))
+		}
+		for _, e := range noteEvents {
+			events = append(events, historyEntry(e, "comment"))
+		}
+
+		sort.SliceStable(events, func(i, j int) bool {
+			a, _ := events[i]["event_date"].(string)
+			b, _ := events[j]["event_date"].(string)
+			return a < b
+		})
+
+		response := map[string]interface{}{
+			"task_id": taskID,
+			"count":   len(events),
+			"events":  events,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// historyEntry trims an activity log event down to the fields useful for auditing.
+func historyEntry(event map[string]interface{}, subject string) map[string]interface{} {
+	entry := map[string]interface{}{
+		"subject":    subject,
+		"event_type": event["event_type"],
+		"event_date": event["event_date"],
+	}
+	if initiator, ok := event["initiator_id"]; ok && initiator != nil {
+		entry["initiator_id"] = initiator
+	}
+	if extra, ok := event["extra_data"].(map[string]interface{}); ok && len(extra) > 0 {
+		entry["details"] = extra
+	}
+	return entry
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTaskHistoryHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		syncGet   func(ctx context.Context, path string) ([]byte, error)
+		wantErr   bool
+		errSubstr string
+		wantTypes []string
+	}{
+		{
+			name: "merges task and comment events chronologically",
+			args: map[string]interface{}{"task_id": "123"},
+			syncGet: func(_ context.Context, path string) ([]byte, error) {
+				switch {
+				case strings.Contains(path, "object_type=item") && strings.Contains(path, "object_id=123"):
+					if !strings.Contains(path, "cursor=c2") {
+						return json.Marshal(map[string]interface{}{
+							"results": []map[string]interface{}{
+								{"event_type": "added", "event_date": "2026-01-01T09:00:00Z"},
+							},
+							"next_cursor": "c2",
+						})
+					}
+					return json.Marshal(map[string]interface{}{
+						"results": []map[string]interface{}{
+							{"event_type": "completed", "event_date": "2026-01-05T09:00:00Z"},
+						},
+					})
+				case strings.Contains(path, "object_type=note") && strings.Contains(path, "parent_item_id=123"):
+					return json.Marshal(map[string]interface{}{
+						"results": []map[string]interface{}{
+							{"event_type": "added", "event_date": "2026-01-03T09:00:00Z", "extra_data": map[string]interface{}{"content": "LGTM"}},
+						},
+					})
+				}
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			},
+			wantTypes: []string{"task:added", "comment:added", "task:completed"},
+		},
+		{
+			name:      "missing task_id",
+			args:      map[string]interface{}{},
+			wantErr:   true,
+			errSubstr: "task_id is required",
+		},
+		{
+			name:      "invalid task_id",
+			args:      map[string]interface{}{"task_id": "1/2"},
+			wantErr:   true,
+			errSubstr: "contains invalid characters",
+		},
+		{
+			name: "API error",
+			args: map[string]interface{}{"task_id": "123"},
+			syncGet: func(_ context.Context, _ string) ([]byte, error) {
+				return nil, fmt.Errorf("forbidden")
+			},
+			wantErr:   true,
+			errSubstr: "failed to fetch task history",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncClient := &MockSyncAPI{GetFn: tt.syncGet}
+			handler := TaskHistoryHandler(syncClient)
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				Events []map[string]interface{} `json:"events"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if len(resp.Events) != len(tt.wantTypes) {
+				t.Fatalf("got %d events, want %d", len(resp.Events), len(tt.wantTypes))
+			}
+			for i, want := range tt.wantTypes {
+				got := fmt.Sprintf("%v:%v", resp.Events[i]["subject"], resp.Events[i]["event_type"])
+				if got != want {
+					t.Errorf("events[%d] = %s, want %s", i, got, want)
+				}
+			}
+		})
+	}
+}
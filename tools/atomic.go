@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// maxListedFailures caps the failures spelled out in an atomic failure message.
+const maxListedFailures = 5
+
+// atomicRequested reports whether a tool was called with atomic, asking it to
+// undo the changes that succeeded when any change of the run fails.
+func atomicRequested(args map[string]interface{}) bool {
+	atomic, _ := args["atomic"].(bool)
+	return atomic
+}
+
+// atomicFailure rolls back the succeeded operations of a run that had
+// failures, including a cancelled run, and returns the error result reporting
+// both. verb names the change in the message, e.g. "complete".
+func atomicFailure(ctx context.Context, client todoist.API, syncClient todoist.SyncAPI, ops []todoist.BulkOperation, result *todoist.BulkResult, verb string) *mcp.CallToolResult {
+	total := len(result.Succeeded) + len(result.Failed)
+	message := fmt.Sprintf("atomic %s failed for %d of %d tasks (%s)", verb, len(result.Failed), total, describeFailures(result.Failed))
+	if len(result.Succeeded) == 0 {
+		return respond.Errorf("%s; nothing was changed", message)
+	}
+
+	// A cancelled run still has to be undone, so the rollback ignores the
+	// cancellation that may have caused the failures.
+	reverted, err := todoist.NewBulkExecutor(client, syncClient).Rollback(context.WithoutCancel(ctx), ops, result)
+	switch {
+	case err != nil:
+		return respond.Errorf("%s; rolling back the %d that succeeded failed: %v. These tasks are still changed: %s",
+			message, len(result.Succeeded), err, strings.Join(result.Succeeded, ", "))
+	case len(reverted.Failed) > 0:
+		return respond.Errorf("%s; %d of the %d that succeeded could not be reverted and are still changed (%s)",
+			message, len(reverted.Failed), len(result.Succeeded), describeFailures(reverted.Failed))
+	}
+	return respond.Errorf("%s; the %d that succeeded were reverted, so nothing was changed", message, len(result.Succeeded))
+}
+
+// rollBackBatchCreate deletes the tasks a batch of item_add commands created
+// when any command of the batch failed, and returns the error result reporting
+// it; nil when every command succeeded. Failed commands are named by their
+// index in the batch.
+func rollBackBatchCreate(ctx context.Context, client todoist.API, syncClient todoist.SyncAPI, commands []todoist.Command, syncResp *todoist.SyncResponse) *mcp.CallToolResult {
+	result := &todoist.BulkResult{}
+	var ops []todoist.BulkOperation
+	for i, cmd := range commands {
+		status := syncResp.SyncStatus[cmd.UUID]
+		realID, created := syncResp.TempIDMapping[cmd.TempID]
+		if s, ok := status.(string); !ok || s != "ok" || !created {
+			result.Failed = append(result.Failed, todoist.BulkFailure{ID: fmt.Sprintf("tasks[%d]", i), Error: fmt.Sprintf("%v", status)})
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, realID)
+		ops = append(ops, todoist.BulkOperation{ID: realID, Revert: deleteOperation(realID)})
+	}
+	if len(result.Failed) == 0 {
+		return nil
+	}
+	return atomicFailure(ctx, client, syncClient, ops, result, "create")
+}
+
+// reopenOperation returns the operation reopening a completed task.
+func reopenOperation(taskID string) *todoist.BulkOperation {
+	return &todoist.BulkOperation{
+		Command: todoist.Command{Type: "item_uncomplete", UUID: todoist.GenerateUUID(), Args: map[string]interface{}{"id": taskID}},
+		Method:  http.MethodPost,
+		Path:    fmt.Sprintf("/tasks/%s/reopen", taskID),
+	}
+}
+
+// restoreDueOperation returns the operation setting a task's due back to due,
+// the REST due object it had before the change, or removing it when due is
+// nil. A full due object, which keeps recurrence, time, and time zone, is only
+// accepted by the Sync API.
+func restoreDueOperation(taskID string, due *models.Due) *todoist.BulkOperation {
+	if due == nil {
+		return &todoist.BulkOperation{
+			Command: todoist.Command{Type: "item_update", UUID: todoist.GenerateUUID(), Args: map[string]interface{}{"id": taskID, "due": nil}},
+			Method:  http.MethodPost,
+			Path:    fmt.Sprintf("/tasks/%s", taskID),
+			Body:    map[string]interface{}{"due_string": "no date"},
+		}
+	}
+	date := due.Datetime
+	if date == "" {
+		date = due.Date
+	}
+	restored := map[string]interface{}{"date": date, "string": due.String, "is_recurring": due.IsRecurring}
+	if due.Lang != "" {
+		restored["lang"] = due.Lang
+	}
+	if due.Timezone != "" {
+		restored["timezone"] = due.Timezone
+	}
+	return &todoist.BulkOperation{
+		Command: todoist.Command{Type: "item_update", UUID: todoist.GenerateUUID(), Args: map[string]interface{}{"id": taskID, "due": restored}},
+	}
+}
+
+// moveBackOperation returns the operation moving task back under its
+// original parent, or else into its original section or project. item_move
+// takes exactly one destination.
+func moveBackOperation(task models.Task) *todoist.BulkOperation {
+	args := map[string]interface{}{"id": task.ID}
+	switch {
+	case task.ParentID != "":
+		args["parent_id"] = task.ParentID
+	case task.SectionID != "":
+		args["section_id"] = task.SectionID
+	default:
+		args["project_id"] = task.ProjectID
+	}
+	return &todoist.BulkOperation{
+		Command: todoist.Command{Type: "item_move", UUID: todoist.GenerateUUID(), Args: args},
+	}
+}
+
+// deleteOperation returns the operation deleting a task, which undoes
+// creating it.
+func deleteOperation(taskID string) *todoist.BulkOperation {
+	return &todoist.BulkOperation{
+		Command: todoist.Command{Type: "item_delete", UUID: todoist.GenerateUUID(), Args: map[string]interface{}{"id": taskID}},
+		Method:  http.MethodDelete,
+		Path:    fmt.Sprintf("/tasks/%s", taskID),
+	}
+}
+
+// describeFailures lists failures as "id: error", up to maxListedFailures.
+func describeFailures(failures []todoist.BulkFailure) string {
+	parts := make([]string, 0, min(len(failures), maxListedFailures)+1)
+	for i, f := range failures {
+		if i == maxListedFailures {
+			parts = append(parts, fmt.Sprintf("and %d more", len(failures)-i))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", f.ID, f.Error))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,321 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestBatchCreateTasksHandler_Atomic(t *testing.T) {
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		status := make(map[string]interface{})
+		mapping := make(map[string]string)
+		for i, cmd := range commands {
+			if i == 2 {
+				status[cmd.UUID] = map[string]interface{}{"error": "Invalid section"}
+				continue
+			}
+			status[cmd.UUID] = "ok"
+			mapping[cmd.TempID] = fmt.Sprintf("real-%d", i)
+		}
+		return &todoist.SyncResponse{SyncStatus: status, TempIDMapping: mapping}, nil
+	}}
+	var deleted []string
+	client := &MockAPI{DeleteFn: func(_ context.Context, path string) error {
+		deleted = append(deleted, path)
+		return nil
+	}}
+
+	result, err := BatchCreateTasksHandler(client, syncClient, nil)(context.Background(), makeReq(map[string]interface{}{
+		"tasks": []interface{}{
+			map[string]interface{}{"content": "Parent"},
+			map[string]interface{}{"content": "Child", "parent_temp_id": "0"},
+			map[string]interface{}{"content": "Broken", "section_id": "999"},
+		},
+		"atomic": true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	text := resultText(result)
+	if !result.IsError || !strings.Contains(text, "atomic create failed for 1 of 3 tasks (tasks[2]:") || !strings.Contains(text, "the 2 that succeeded were reverted") {
+		t.Fatalf("expected a rollback error, got %s", text)
+	}
+	if strings.Join(deleted, ",") != "/tasks/real-1,/tasks/real-0" {
+		t.Errorf("deleted = %v, want the subtask before its parent", deleted)
+	}
+}
+
+func TestBulkCompleteTasksHandler_Atomic(t *testing.T) {
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1"},
+				{"id": "2", "due": map[string]interface{}{"date": "2026-01-05", "string": "every monday", "lang": "en", "is_recurring": true}},
+				{"id": "3"},
+			})
+		},
+	}
+	var rollback []todoist.Command
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+			if cmd.Type == "item_close" && cmd.Args["id"] == "3" {
+				status[cmd.UUID] = map[string]interface{}{"error": "Item not found"}
+			}
+		}
+		if commands[0].Type != "item_close" {
+			rollback = commands
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+
+	result, err := BulkCompleteTasksHandler(client, syncClient, NewConfirmationStore(time.Minute))(context.Background(), makeReq(map[string]interface{}{
+		"task_ids": []interface{}{"1", "2", "3"},
+		"strategy": "sync",
+		"atomic":   true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "3: map[error:Item not found]") || !strings.Contains(text, "nothing was changed") {
+		t.Fatalf("expected a rollback error, got %s", text)
+	}
+
+	if len(rollback) != 2 {
+		t.Fatalf("rollback = %+v, want two commands", rollback)
+	}
+	if rollback[0].Type != "item_update" || rollback[0].Args["id"] != "2" {
+		t.Errorf("recurring task revert = %+v, want item_update of 2", rollback[0])
+	}
+	due, _ := rollback[0].Args["due"].(map[string]interface{})
+	if due["date"] != "2026-01-05" || due["string"] != "every monday" || due["lang"] != "en" {
+		t.Errorf("restored due = %v", due)
+	}
+	if rollback[1].Type != "item_uncomplete" || rollback[1].Args["id"] != "1" {
+		t.Errorf("revert = %+v, want item_uncomplete of 1", rollback[1])
+	}
+}
+
+func TestBulkCompleteTasksHandler_AtomicCancelled(t *testing.T) {
+	started := make(chan struct{})
+	reopened := make(chan string, 2)
+	client := &MockAPI{
+		GetFn: func(_ context.Context, _ string) ([]byte, error) {
+			return json.Marshal([]map[string]interface{}{{"id": "1"}, {"id": "2"}})
+		},
+		PostFn: func(ctx context.Context, path string, _ interface{}) ([]byte, error) {
+			switch path {
+			case "/tasks/1/close":
+				// Hold the first close until the operation is cancelled.
+				close(started)
+				<-ctx.Done()
+			case "/tasks/1/reopen", "/tasks/2/reopen":
+				reopened <- path
+			}
+			return nil, nil
+		},
+	}
+
+	store := NewOperationStore(time.Minute)
+	handler := store.Background("bulk_complete_tasks", BulkCompleteTasksHandler(client, &MockSyncAPI{}, NewConfirmationStore(time.Minute)))
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{
+		"task_ids":   []interface{}{"1", "2"},
+		"strategy":   "rest",
+		"atomic":     true,
+		"background": true,
+	}))
+	var started0 struct {
+		OperationID string `json:"operation_id"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &started0); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	<-started
+	if result, _ := CancelOperationHandler(store)(context.Background(), makeReq(map[string]interface{}{"operation_id": started0.OperationID})); result.IsError {
+		t.Fatalf("cancel failed: %s", resultText(result))
+	}
+
+	select {
+	case path := <-reopened:
+		if path != "/tasks/1/reopen" {
+			t.Errorf("reverted %s, want /tasks/1/reopen", path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the completed task was not reopened after cancellation")
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		op, _ := store.snapshot(started0.OperationID)
+		if op.State != OperationRunning {
+			if op.State != OperationCancelled || !strings.Contains(op.Error, "nothing was changed") {
+				t.Errorf("operation = %+v, want cancelled with the rollback reported", op)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("operation did not finish")
+		}
+	}
+}
+
+func TestBulkCompleteTasksHandler_AtomicBackdatedRecurring(t *testing.T) {
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		return json.Marshal([]map[string]interface{}{{"id": "1", "due": map[string]interface{}{"date": "2026-01-05", "is_recurring": true}}})
+	}}
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		t.Fatal("nothing should be sent")
+		return nil, nil
+	}}
+
+	result, _ := BulkCompleteTasksHandler(client, syncClient, NewConfirmationStore(time.Minute))(context.Background(), makeReq(map[string]interface{}{
+		"task_ids":     []interface{}{"1"},
+		"completed_at": "2026-01-04",
+		"atomic":       true,
+	}))
+	if !result.IsError || !strings.Contains(resultText(result), "stopped before changing anything") {
+		t.Errorf("expected an up-front error, got %s", resultText(result))
+	}
+}
+
+func TestMoveTasksHandler_Atomic(t *testing.T) {
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if strings.HasPrefix(path, "/projects/") {
+			return json.Marshal(map[string]interface{}{"id": "p9", "name": "Archive"})
+		}
+		return json.Marshal([]map[string]interface{}{
+			{"id": "1", "project_id": "p1", "parent_id": "t0"},
+			{"id": "2", "project_id": "p1", "section_id": "s1"},
+			{"id": "3", "project_id": "p2"},
+			{"id": "4", "project_id": "p1"},
+		})
+	}}
+	var rollback []todoist.Command
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+			if cmd.Args["project_id"] == "p9" && cmd.Args["id"] == "4" {
+				status[cmd.UUID] = map[string]interface{}{"error": "Item not found"}
+			}
+		}
+		if commands[0].Args["project_id"] != "p9" {
+			rollback = commands
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+
+	result, err := MoveTasksHandler(client, syncClient, NewConfirmationStore(time.Minute))(context.Background(), makeReq(map[string]interface{}{
+		"task_ids":      []interface{}{"1", "2", "3", "4"},
+		"to_project_id": "p9",
+		"atomic":        true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "atomic move failed for 1 of 4 tasks") || !strings.Contains(text, "nothing was changed") {
+		t.Fatalf("expected a rollback error, got %s", text)
+	}
+
+	want := []string{"3 project_id p2", "2 section_id s1", "1 parent_id t0"}
+	if len(rollback) != len(want) {
+		t.Fatalf("rollback = %+v, want %d moves", rollback, len(want))
+	}
+	for i, cmd := range rollback {
+		var got string
+		for _, key := range []string{"parent_id", "section_id", "project_id"} {
+			if v, ok := cmd.Args[key]; ok {
+				got = fmt.Sprintf("%s %s %v", cmd.Args["id"], key, v)
+			}
+		}
+		if cmd.Type != "item_move" || len(cmd.Args) != 2 || got != want[i] {
+			t.Errorf("rollback[%d] = %s %v, want item_move %s", i, cmd.Type, cmd.Args, want[i])
+		}
+	}
+}
+
+func TestBulkRescheduleHandler_Atomic(t *testing.T) {
+	var reverted []string
+	client := &MockAPI{
+		GetFn: func(_ context.Context, _ string) ([]byte, error) {
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1", "due": map[string]interface{}{"date": "2026-03-02", "datetime": "2026-03-02T09:00:00", "string": "Mar 2 9am", "is_recurring": false}},
+				{"id": "2"},
+			})
+		},
+		PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+			if path == "/tasks/3" {
+				return nil, fmt.Errorf("not found")
+			}
+			if fields, _ := body.(map[string]interface{}); fields["due_string"] == "no date" {
+				reverted = append(reverted, path)
+			}
+			return nil, nil
+		},
+	}
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+			due, _ := cmd.Args["due"].(map[string]interface{})
+			reverted = append(reverted, fmt.Sprintf("%v %v", cmd.Args["id"], due["date"]))
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+
+	result, _ := BulkRescheduleHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{
+		"moves": []interface{}{
+			map[string]interface{}{"task_id": "1", "due_date": "2026-03-09"},
+			map[string]interface{}{"task_id": "2", "due_date": "2026-03-09"},
+			map[string]interface{}{"task_id": "3", "due_date": "2026-03-09"},
+		},
+		"strategy": "rest",
+		"atomic":   true,
+	}))
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "atomic reschedule failed for 1 of 3 tasks") || !strings.Contains(text, "the 2 that succeeded were reverted") {
+		t.Fatalf("expected a rollback error, got %s", text)
+	}
+	// The timed due date needs the Sync API; the task without one is
+	// cleared through either API.
+	if got := strings.Join(reverted, ","); got != "2 <nil>,1 2026-03-02T09:00:00" && got != "/tasks/2,1 2026-03-02T09:00:00" {
+		t.Errorf("reverts = %s, want task 2 cleared and task 1 back at its time", got)
+	}
+}
+
+func TestAtomicFailure_RevertFailed(t *testing.T) {
+	client := &MockAPI{PostFn: func(_ context.Context, path string, _ interface{}) ([]byte, error) {
+		if path == "/tasks/b/reopen" {
+			return nil, fmt.Errorf("forbidden")
+		}
+		return nil, nil
+	}}
+	ops := []todoist.BulkOperation{
+		{ID: "a", Revert: reopenOperation("a")},
+		{ID: "b", Revert: reopenOperation("b")},
+		{ID: "c"},
+		{ID: "d", Revert: reopenOperation("d")},
+	}
+	result := &todoist.BulkResult{Succeeded: []string{"a", "b", "c"}, Failed: []todoist.BulkFailure{{ID: "d", Error: "not found"}}}
+
+	text := resultText(atomicFailure(context.Background(), client, &MockSyncAPI{}, ops, result, "complete"))
+	if !strings.Contains(text, "2 of the 3 that succeeded could not be reverted") ||
+		!strings.Contains(text, "b: forbidden") || !strings.Contains(text, "c: change cannot be undone") {
+		t.Errorf("message = %s", text)
+	}
+}
+
+func TestDescribeFailures(t *testing.T) {
+	var failures []todoist.BulkFailure
+	for i := range 7 {
+		failures = append(failures, todoist.BulkFailure{ID: fmt.Sprint(i), Error: "x"})
+	}
+	if got := describeFailures(failures); got != "0: x; 1: x; 2: x; 3: x; 4: x; and 2 more" {
+		t.Errorf("describeFailures = %q", got)
+	}
+}
@@ -0,0 +1,250 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// defaultAtRiskLabel marks tasks flagged as at risk of missing their deadline.
+const defaultAtRiskLabel = "at-risk"
+
+// defaultAtRiskDays is how far ahead deadlines are checked when neither the
+// call nor the configuration says.
+const defaultAtRiskDays = 3
+
+// AtRiskOptions configures the deadline breach monitor.
+type AtRiskOptions struct {
+	// Days is how many days ahead deadlines are checked; zero disables the
+	// automation.
+	Days int
+	// Label marks flagged tasks; empty means at-risk.
+	Label string
+	// Comment also posts a warning comment on each newly flagged task.
+	Comment bool
+}
+
+// atRiskReasons returns why a task with a near deadline is at risk: it has no
+// due date, or it sits unassigned in a shared project. Tasks in personal
+// projects are never assigned, so only scheduling counts there.
+func atRiskReasons(task models.Task, shared map[string]bool) []string {
+	reasons := make([]string, 0, 2)
+	if task.Due.Day() == "" {
+		reasons = append(reasons, "unscheduled")
+	}
+	if task.AssigneeID == "" && shared[task.ProjectID] {
+		reasons = append(reasons, "unassigned")
+	}
+	return reasons
+}
+
+// atRiskComment is the warning posted on a newly flagged task.
+func atRiskComment(deadline string, daysLeft int, reasons []string) string {
+	when := fmt.Sprintf("is in %d day(s)", daysLeft)
+	switch {
+	case daysLeft == 0:
+		when = "is today"
+	case daysLeft < 0:
+		when = fmt.Sprintf("passed %d day(s) ago", -daysLeft)
+	}
+	return fmt.Sprintf("At risk: the deadline %s %s, but this task is still %s.", deadline, when, strings.Join(reasons, " and "))
+}
+
+// checkAtRiskTasks finds the tasks whose deadline is at most days away, or
+// already past, and which are unscheduled or unassigned. With flag set, it
+// labels those not yet flagged, and comments on them if opts.Comment is set,
+// in one Sync batch.
+func checkAtRiskTasks(ctx context.Context, client todoist.API, syncClient todoist.SyncAPI, today time.Time, days int, projectID string, flag bool, opts AtRiskOptions) (respond.Envelope, error) {
+	label := opts.Label
+	if label == "" {
+		label = defaultAtRiskLabel
+	}
+
+	projectsBody, err := client.Get(ctx, "/projects")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch projects: %w", err)
+	}
+	var projects []models.Project
+	if err := json.Unmarshal(projectsBody, &projects); err != nil {
+		return nil, fmt.Errorf("failed to parse projects: %w", err)
+	}
+	shared := make(map[string]bool)
+	for _, project := range projects {
+		if project.IsShared {
+			shared[project.ID] = true
+		}
+	}
+
+	params := url.Values{}
+	if projectID != "" {
+		params.Set("project_id", projectID)
+	}
+	tasks, err := fetchTasks(ctx, client, params)
+	if err != nil {
+		return nil, err
+	}
+
+	horizon := today.AddDate(0, 0, days).Format("2006-01-02")
+	entries := make([]map[string]interface{}, 0)
+	var commands []todoist.Command
+	commandTask := make(map[string]string)
+	alreadyFlagged := 0
+	for _, task := range tasks {
+		deadline := task.Deadline.Day()
+		if deadline == "" || deadline > horizon {
+			continue
+		}
+		reasons := atRiskReasons(task, shared)
+		if len(reasons) == 0 {
+			continue
+		}
+		date, err := time.ParseInLocation("2006-01-02", deadline, today.Location())
+		if err != nil {
+			continue
+		}
+		daysLeft := int(date.Sub(today).Hours() / 24)
+		taskID := task.ID
+		flagged := taskHasLabel(task, label)
+		entries = append(entries, map[string]interface{}{
+			"id":         taskID,
+			"content":    task.Content,
+			"project_id": task.ProjectID,
+			"deadline":   deadline,
+			"days_left":  daysLeft,
+			"reasons":    reasons,
+			"flagged":    flagged,
+		})
+		if flagged {
+			alreadyFlagged++
+			continue
+		}
+		if !flag {
+			continue
+		}
+
+		updated := append(slices.Clone(task.Labels), label)
+		cmd := todoist.Command{Type: "item_update", UUID: todoist.GenerateUUID(), Args: map[string]interface{}{"id": taskID, "labels": updated}}
+		commands = append(commands, cmd)
+		commandTask[cmd.UUID] = taskID
+		if opts.Comment {
+			cmd := todoist.Command{Type: "note_add", UUID: todoist.GenerateUUID(), TempID: todoist.GenerateTempID(), Args: map[string]interface{}{"item_id": taskID, "content": atRiskComment(deadline, daysLeft, reasons)}}
+			commands = append(commands, cmd)
+			commandTask[cmd.UUID] = taskID
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i]["deadline"].(string) < entries[j]["deadline"].(string) })
+
+	response := respond.List("tasks", entries).
+		Set("days", days).
+		Set("label", label).
+		Set("already_flagged", alreadyFlagged)
+	if !flag {
+		return response, nil
+	}
+	if len(commands) > maxPlanCommands {
+		return nil, fmt.Errorf("%d commands exceed the batch limit of %d; narrow the check with project_id or fewer days", len(commands), maxPlanCommands)
+	}
+
+	failed := make([]todoist.BulkFailure, 0)
+	flaggedCount := 0
+	if len(commands) > 0 {
+		syncResp, err := syncClient.BatchCommands(ctx, commands)
+		if err != nil {
+			return nil, fmt.Errorf("failed to flag tasks: %w", err)
+		}
+		failedIDs := make(map[string]bool)
+		for _, cmd := range commands {
+			if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+				continue
+			}
+			taskID := commandTask[cmd.UUID]
+			if !failedIDs[taskID] {
+				failedIDs[taskID] = true
+				failed = append(failed, todoist.BulkFailure{ID: taskID, Error: fmt.Sprintf("%s: %v", cmd.Type, syncResp.SyncStatus[cmd.UUID])})
+			}
+		}
+		for _, cmd := range commands {
+			if cmd.Type == "item_update" && !failedIDs[commandTask[cmd.UUID]] {
+				flaggedCount++
+			}
+		}
+	}
+	response.Set("flagged", flaggedCount).Set("failed", failed)
+	return response, nil
+}
+
+// AtRiskAutomation flags at-risk tasks every hour, looking opts.Days ahead.
+// Tasks already carrying the label are left alone, so each is flagged, and
+// commented on, once.
+func AtRiskAutomation(client todoist.API, syncClient todoist.SyncAPI, opts AtRiskOptions) Automation {
+	return Automation{
+		Name:  "deadline_monitor",
+		Every: time.Hour,
+		Run: func(ctx context.Context) error {
+			loc, err := userLocation(ctx, syncClient)
+			if err != nil {
+				return fmt.Errorf("failed to resolve user time zone: %w", err)
+			}
+			now := time.Now().In(loc)
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+			_, err = checkAtRiskTasks(ctx, client, syncClient, today, opts.Days, "", true, opts)
+			return err
+		},
+	}
+}
+
+// FlagAtRiskTasksHandler creates a handler that lists, and optionally flags,
+// tasks whose deadline is near but which nobody has scheduled or taken on.
+func FlagAtRiskTasksHandler(client todoist.API, syncClient todoist.SyncAPI, defaults AtRiskOptions) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		opts := defaults
+		days := opts.Days
+		if days == 0 {
+			days = defaultAtRiskDays
+		}
+		if d, ok := args["days"].(float64); ok {
+			if d < 0 || d > 30 {
+				return respond.Error("days must be between 0 and 30"), nil
+			}
+			days = int(d)
+		}
+		projectID, _ := args["project_id"].(string)
+		if projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+		if label, ok := args["label"].(string); ok && label != "" {
+			opts.Label = strings.TrimPrefix(label, "@")
+		}
+		if comment, ok := args["comment"].(bool); ok {
+			opts.Comment = comment
+		}
+		flag, _ := args["flag"].(bool)
+
+		loc, err := userLocation(ctx, syncClient)
+		if err != nil {
+			return respond.Errorf("failed to resolve user time zone: %v", err), nil
+		}
+		now := time.Now().In(loc)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+		response, err := checkAtRiskTasks(ctx, client, syncClient, today, days, projectID, flag, opts)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+// atRiskMock serves shared project p-team and personal project p-me. Tasks
+// 1, 2, and 5 are at risk within three days; 2 is already flagged.
+func atRiskMock() *MockAPI {
+	today := time.Now().UTC()
+	day := func(n int) map[string]interface{} {
+		return map[string]interface{}{"date": today.AddDate(0, 0, n).Format("2006-01-02")}
+	}
+	return &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if path == "/projects" {
+			return json.Marshal([]map[string]interface{}{{"id": "p-team", "is_shared": true}, {"id": "p-me"}})
+		}
+		return json.Marshal([]map[string]interface{}{
+			{"id": "1", "content": "Ship report", "project_id": "p-team", "deadline": day(2), "due": day(1)},
+			{"id": "2", "content": "File taxes", "project_id": "p-me", "deadline": day(1), "labels": []interface{}{"at-risk"}},
+			{"id": "3", "content": "Owned and scheduled", "project_id": "p-team", "deadline": day(1), "due": day(0), "assignee_id": "u1"},
+			{"id": "4", "content": "Far deadline", "project_id": "p-me", "deadline": day(10)},
+			{"id": "5", "content": "Renew lease", "project_id": "p-me", "deadline": day(-1), "labels": []interface{}{"home"}},
+			{"id": "6", "content": "Personal, scheduled", "project_id": "p-me", "deadline": day(1), "due": day(0)},
+		})
+	}}
+}
+
+func TestAtRiskComment(t *testing.T) {
+	if got := atRiskComment("2026-10-18", 2, []string{"unscheduled", "unassigned"}); got != "At risk: the deadline 2026-10-18 is in 2 day(s), but this task is still unscheduled and unassigned." {
+		t.Errorf("comment = %q", got)
+	}
+	if got := atRiskComment("2026-10-15", -1, []string{"unscheduled"}); !strings.Contains(got, "passed 1 day(s) ago") {
+		t.Errorf("comment = %q", got)
+	}
+}
+
+func TestFlagAtRiskTasksHandler_Report(t *testing.T) {
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, _ string) ([]byte, error) {
+		return []byte(`{"tz_info":{"timezone":"UTC"}}`), nil
+	}}
+	handler := FlagAtRiskTasksHandler(atRiskMock(), syncClient, AtRiskOptions{})
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{}))
+	var resp struct {
+		Count          int                      `json:"count"`
+		Days           int                      `json:"days"`
+		AlreadyFlagged int                      `json:"already_flagged"`
+		Tasks          []map[string]interface{} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("parse: %v (%s)", err, resultText(result))
+	}
+	if resp.Count != 3 || resp.Days != defaultAtRiskDays || resp.AlreadyFlagged != 1 {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if resp.Tasks[0]["id"] != "5" || resp.Tasks[1]["id"] != "2" || resp.Tasks[2]["id"] != "1" {
+		t.Errorf("tasks = %v", resp.Tasks)
+	}
+	if reasons, _ := resp.Tasks[2]["reasons"].([]interface{}); len(reasons) != 1 || reasons[0] != "unassigned" {
+		t.Errorf("task 1 reasons = %v", resp.Tasks[2]["reasons"])
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"days": float64(31)}))
+	if !result.IsError {
+		t.Errorf("days 31: %s", resultText(result))
+	}
+}
+
+func TestFlagAtRiskTasksHandler_Flag(t *testing.T) {
+	var sent []todoist.Command
+	syncClient := &MockSyncAPI{
+		GetFn: func(_ context.Context, _ string) ([]byte, error) {
+			return []byte(`{"tz_info":{"timezone":"UTC"}}`), nil
+		},
+		BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+			sent = commands
+			status := make(map[string]interface{})
+			for _, cmd := range commands {
+				status[cmd.UUID] = "ok"
+			}
+			return &todoist.SyncResponse{SyncStatus: status}, nil
+		},
+	}
+	handler := FlagAtRiskTasksHandler(atRiskMock(), syncClient, AtRiskOptions{Comment: true})
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"flag": true}))
+	if text := resultText(result); result.IsError || !strings.Contains(text, `"flagged": 2`) {
+		t.Fatalf("result = %s", text)
+	}
+	if len(sent) != 4 || sent[0].Type != "item_update" || sent[1].Type != "note_add" {
+		t.Fatalf("sent = %+v", sent)
+	}
+	labels, _ := sent[0].Args["labels"].([]string)
+	if sent[0].Args["id"] != "1" || len(labels) != 1 || labels[0] != "at-risk" {
+		t.Errorf("update = %v", sent[0].Args)
+	}
+	if labels, _ := sent[2].Args["labels"].([]string); sent[2].Args["id"] != "5" || len(labels) != 2 || labels[0] != "home" {
+		t.Errorf("update = %v", sent[2].Args)
+	}
+
+	sent = nil
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"flag": true, "comment": false}))
+	if len(sent) != 2 || sent[1].Type != "item_update" {
+		t.Errorf("without comment: sent = %+v (%s)", sent, resultText(result))
+	}
+}
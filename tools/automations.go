@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+// Automation is a job the server runs on its own, outside any tool call.
+type Automation struct {
+	Name string
+	// Every is how often Run is called. Run decides for itself whether there
+	// is work to do, so that missed ticks, e.g. while the machine slept, are
+	// caught up on the next one.
+	Every time.Duration
+	Run   func(ctx context.Context) error
+}
+
+// RunAutomations runs each automation once at startup and then on its
+// interval until ctx ends, and returns once all of them have stopped. Their
+// API requests are scheduled at bulk priority, so they never hold up tool
+// calls. Failures are logged and retried on the next tick.
+func RunAutomations(ctx context.Context, automations ...Automation) {
+	ctx = todoist.WithPriority(ctx, todoist.PriorityBulk)
+
+	var wg sync.WaitGroup
+	for _, a := range automations {
+		wg.Add(1)
+		go func(a Automation) {
+			defer wg.Done()
+			ticker := time.NewTicker(a.Every)
+			defer ticker.Stop()
+			for {
+				if err := a.Run(ctx); err != nil && ctx.Err() == nil {
+					slog.Warn("automation failed", "automation", a.Name, "error", err)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(a)
+	}
+	wg.Wait()
+}
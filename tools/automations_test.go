@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunAutomations(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+	done := make(chan struct{})
+	go func() {
+		RunAutomations(ctx, Automation{
+			Name:  "test",
+			Every: time.Millisecond,
+			Run: func(context.Context) error {
+				if runs.Add(1) == 3 {
+					cancel()
+				}
+				return errors.New("retried on the next tick")
+			},
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunAutomations did not stop after cancel")
+	}
+	if n := runs.Load(); n < 3 {
+		t.Errorf("ran %d times, want at least 3", n)
+	}
+}
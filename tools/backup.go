@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+)
+
+// ProjectBackup is the JSON archive of a project written before it is
+// deleted: the project and its sub-projects, which Todoist deletes with it,
+// and their sections and active tasks as the REST API reports them.
+type ProjectBackup struct {
+	ProjectID  string           `json:"project_id"`
+	BackedUpAt time.Time        `json:"backed_up_at"`
+	Projects   []models.Project `json:"projects"`
+	Sections   []models.Section `json:"sections"`
+	Tasks      []models.Task    `json:"tasks"`
+}
+
+// BackupStore writes project backups as JSON files in a directory.
+type BackupStore struct {
+	mu        sync.Mutex
+	dir       string
+	byDefault bool
+}
+
+// NewBackupStore creates a backup store backed by dir. With byDefault set,
+// delete_project backs up projects unless the call opts out. The directory
+// is created on first write.
+func NewBackupStore(dir string, byDefault bool) *BackupStore {
+	return &BackupStore{dir: dir, byDefault: byDefault}
+}
+
+// Write stores a backup and returns the path of the file written. File names
+// carry the project ID and time, so repeated backups never overwrite.
+func (bs *BackupStore) Write(backup *ProjectBackup) (string, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if err := os.MkdirAll(bs.dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	name := fmt.Sprintf("project-%s-%s.json", backup.ProjectID, backup.BackedUpAt.Format("20060102T150405.000Z"))
+	path := filepath.Join(bs.dir, name)
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode backup: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+	return path, nil
+}
+
+// buildProjectBackup collects a project, its sub-projects, and their sections
+// and tasks.
+func buildProjectBackup(ctx context.Context, client todoist.API, projectID string) (*ProjectBackup, error) {
+	respBody, err := client.Get(ctx, "/projects")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch projects: %w", err)
+	}
+	var projects []models.Project
+	if err := json.Unmarshal(respBody, &projects); err != nil {
+		return nil, fmt.Errorf("failed to parse projects: %w", err)
+	}
+	ids := descendantsIn(projects, projectID)
+
+	backup := &ProjectBackup{
+		ProjectID:  projectID,
+		BackedUpAt: time.Now().UTC(),
+		Projects:   make([]models.Project, 0, len(ids)),
+		Sections:   make([]models.Section, 0),
+		Tasks:      make([]models.Task, 0),
+	}
+	for _, project := range projects {
+		if slices.Contains(ids, project.ID) {
+			backup.Projects = append(backup.Projects, project)
+		}
+	}
+	if len(backup.Projects) == 0 {
+		return nil, fmt.Errorf("project %s not found", projectID)
+	}
+
+	for _, id := range ids {
+		params := url.Values{}
+		params.Set("project_id", id)
+
+		sectionsBody, err := client.Get(ctx, "/sections?"+params.Encode())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch sections: %w", err)
+		}
+		var sections []models.Section
+		if err := json.Unmarshal(sectionsBody, &sections); err != nil {
+			return nil, fmt.Errorf("failed to parse sections: %w", err)
+		}
+		backup.Sections = append(backup.Sections, sections...)
+
+		tasks, err := fetchTasks(ctx, client, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+		}
+		backup.Tasks = append(backup.Tasks, tasks...)
+	}
+	return backup, nil
+}
@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDeleteProjectHandler_Backup(t *testing.T) {
+	newClient := func(deleted *bool) *MockAPI {
+		return &MockAPI{
+			GetFn: func(_ context.Context, path string) ([]byte, error) {
+				switch {
+				case path == "/projects":
+					return []byte(`[{"id": "1", "name": "Work"}, {"id": "2", "name": "Q4", "parent_id": "1"}, {"id": "3", "name": "Home"}]`), nil
+				case strings.HasPrefix(path, "/sections?"):
+					return []byte(`[{"id": "s1", "name": "Doing"}]`), nil
+				case strings.HasPrefix(path, "/tasks?"):
+					return []byte(`[{"id": "t1", "content": "Plan"}]`), nil
+				}
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			},
+			DeleteFn: func(_ context.Context, _ string) error {
+				*deleted = true
+				return nil
+			},
+		}
+	}
+
+	t.Run("on by default", func(t *testing.T) {
+		var deleted bool
+		dir := t.TempDir()
+		result, _ := DeleteProjectHandler(newClient(&deleted), NewBackupStore(dir, true))(context.Background(), makeReq(map[string]interface{}{"project_id": "1"}))
+		if result.IsError || !deleted {
+			t.Fatalf("deleted = %v, result = %s", deleted, resultText(result))
+		}
+		var response struct {
+			Backup struct {
+				File     string `json:"file"`
+				Projects int    `json:"projects"`
+				Sections int    `json:"sections"`
+				Tasks    int    `json:"tasks"`
+			} `json:"backup"`
+		}
+		if err := json.Unmarshal([]byte(resultText(result)), &response); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if response.Backup.Projects != 2 || response.Backup.Sections != 2 || response.Backup.Tasks != 2 {
+			t.Errorf("unexpected backup counts: %+v", response.Backup)
+		}
+
+		data, err := os.ReadFile(response.Backup.File)
+		if err != nil {
+			t.Fatalf("reading backup: %v", err)
+		}
+		var backup ProjectBackup
+		if err := json.Unmarshal(data, &backup); err != nil {
+			t.Fatalf("invalid backup: %v", err)
+		}
+		if backup.ProjectID != "1" || len(backup.Projects) != 2 || backup.Projects[1].Name != "Q4" {
+			t.Errorf("unexpected backup: %+v", backup)
+		}
+	})
+
+	t.Run("opted out", func(t *testing.T) {
+		var deleted bool
+		dir := t.TempDir()
+		result, _ := DeleteProjectHandler(newClient(&deleted), NewBackupStore(dir, true))(context.Background(), makeReq(map[string]interface{}{"project_id": "1", "backup": false}))
+		if result.IsError || !deleted || strings.Contains(resultText(result), "backup") {
+			t.Errorf("deleted = %v, result = %s", deleted, resultText(result))
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Errorf("wrote %d backups, want none", len(entries))
+		}
+	})
+
+	t.Run("backup failure keeps project", func(t *testing.T) {
+		var deleted bool
+		file := t.TempDir() + "/not-a-dir"
+		if err := os.WriteFile(file, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		result, _ := DeleteProjectHandler(newClient(&deleted), NewBackupStore(file, false))(context.Background(), makeReq(map[string]interface{}{"project_id": "1", "backup": true}))
+		if !result.IsError || deleted || !strings.Contains(resultText(result), "project not deleted") {
+			t.Errorf("deleted = %v, result = %s", deleted, resultText(result))
+		}
+	})
+}
@@ -0,0 +1,270 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// classifierStopwords are left out of the model; they say nothing about where
+// a task belongs.
+var classifierStopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "by": true, "for": true,
+	"from": true, "in": true, "is": true, "it": true, "my": true, "of": true,
+	"on": true, "or": true, "the": true, "this": true, "to": true, "up": true,
+	"with": true,
+}
+
+// classifierTokens splits text into lowercase words, dropping stopwords and
+// single characters.
+func classifierTokens(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	tokens := make([]string, 0, len(words))
+	for _, w := range words {
+		if len([]rune(w)) > 1 && !classifierStopwords[w] {
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
+
+// ProjectSuggestion is a project the classifier proposes for a task.
+type ProjectSuggestion struct {
+	ProjectID   string  `json:"project_id"`
+	ProjectName string  `json:"project_name"`
+	Confidence  float64 `json:"confidence"`
+	// Keywords are the task's words most often seen in the project.
+	Keywords []string `json:"keywords"`
+}
+
+// projectModel is a multinomial naive Bayes model of which words appear in
+// the tasks of each project.
+type projectModel struct {
+	names      map[string]string
+	tasks      map[string]int
+	wordCounts map[string]map[string]int
+	wordTotals map[string]int
+	vocabulary map[string]bool
+	trainedOn  int
+	trainedAt  time.Time
+	projectIDs []string
+}
+
+// trainProjectModel builds a model from active tasks and their projects. The
+// Inbox and archived projects are not suggestion targets, so their tasks are
+// left out.
+func trainProjectModel(items []models.Task, projects []models.Project) *projectModel {
+	m := &projectModel{
+		names:      make(map[string]string),
+		tasks:      make(map[string]int),
+		wordCounts: make(map[string]map[string]int),
+		wordTotals: make(map[string]int),
+		vocabulary: make(map[string]bool),
+		trainedAt:  time.Now(),
+	}
+	for _, project := range projects {
+		if project.IsInboxProject || project.IsArchived {
+			continue
+		}
+		m.names[project.ID] = project.Name
+	}
+	for _, item := range items {
+		if item.Checked {
+			continue
+		}
+		projectID := item.ProjectID
+		if _, ok := m.names[projectID]; !ok {
+			continue
+		}
+		tokens := classifierTokens(item.Content)
+		if len(tokens) == 0 {
+			continue
+		}
+		if m.wordCounts[projectID] == nil {
+			m.wordCounts[projectID] = make(map[string]int)
+			m.projectIDs = append(m.projectIDs, projectID)
+		}
+		m.tasks[projectID]++
+		m.trainedOn++
+		for _, t := range tokens {
+			m.wordCounts[projectID][t]++
+			m.wordTotals[projectID]++
+			m.vocabulary[t] = true
+		}
+	}
+	sort.Strings(m.projectIDs)
+	return m
+}
+
+// suggest ranks the projects for text, best first, with confidences summing
+// to 1 across all projects. Text sharing no word with any trained task gets
+// no suggestions.
+func (m *projectModel) suggest(text string, limit int) []ProjectSuggestion {
+	var known []string
+	for _, t := range classifierTokens(text) {
+		if m.vocabulary[t] {
+			known = append(known, t)
+		}
+	}
+	if len(known) == 0 || len(m.projectIDs) == 0 {
+		return []ProjectSuggestion{}
+	}
+
+	// Log-probabilities with add-one smoothing, then softmax.
+	scores := make([]float64, len(m.projectIDs))
+	best := math.Inf(-1)
+	for i, id := range m.projectIDs {
+		score := math.Log(float64(m.tasks[id]) / float64(m.trainedOn))
+		denominator := float64(m.wordTotals[id] + len(m.vocabulary))
+		for _, t := range known {
+			score += math.Log(float64(m.wordCounts[id][t]+1) / denominator)
+		}
+		scores[i] = score
+		best = math.Max(best, score)
+	}
+	sum := 0.0
+	for i := range scores {
+		scores[i] = math.Exp(scores[i] - best)
+		sum += scores[i]
+	}
+
+	suggestions := make([]ProjectSuggestion, 0, len(m.projectIDs))
+	for i, id := range m.projectIDs {
+		keywords := make([]string, 0)
+		for _, t := range known {
+			if m.wordCounts[id][t] > 0 && !slices.Contains(keywords, t) {
+				keywords = append(keywords, t)
+			}
+		}
+		sort.SliceStable(keywords, func(a, b int) bool { return m.wordCounts[id][keywords[a]] > m.wordCounts[id][keywords[b]] })
+		if len(keywords) > 3 {
+			keywords = keywords[:3]
+		}
+		suggestions = append(suggestions, ProjectSuggestion{
+			ProjectID:   id,
+			ProjectName: m.names[id],
+			Confidence:  math.Round(scores[i]/sum*100) / 100,
+			Keywords:    keywords,
+		})
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].Confidence > suggestions[j].Confidence })
+	return suggestions[:min(limit, len(suggestions))]
+}
+
+// ProjectClassifier suggests projects for tasks from the words of the tasks
+// already in each project. The model is trained from a full sync and kept
+// for ttl, so tools can consult it per task without spending the rate limit
+// budget.
+type ProjectClassifier struct {
+	syncClient todoist.SyncAPI
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	model *projectModel
+}
+
+// NewProjectClassifier creates a classifier whose model is retrained after
+// ttl.
+func NewProjectClassifier(syncClient todoist.SyncAPI, ttl time.Duration) *ProjectClassifier {
+	return &ProjectClassifier{syncClient: syncClient, ttl: ttl}
+}
+
+// load returns the current model, training a new one when there is none or
+// it is older than ttl. A failed retraining keeps serving the previous model.
+func (c *ProjectClassifier) load(ctx context.Context) (*projectModel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.model != nil && time.Since(c.model.trainedAt) < c.ttl {
+		return c.model, nil
+	}
+	resources, err := fetchSyncResources(ctx, c.syncClient, "items", "projects")
+	if err == nil {
+		var items []models.Task
+		var projects []models.Project
+		if items, err = decodeSyncTasks(resources["items"]); err == nil {
+			if projects, err = decodeSyncProjects(resources["projects"]); err == nil {
+				c.model = trainProjectModel(items, projects)
+				return c.model, nil
+			}
+		}
+	}
+	if c.model != nil {
+		return c.model, nil
+	}
+	return nil, fmt.Errorf("failed to train project classifier: %w", err)
+}
+
+// Suggest returns up to limit projects for a task with the given text, best
+// first.
+func (c *ProjectClassifier) Suggest(ctx context.Context, text string, limit int) ([]ProjectSuggestion, error) {
+	model, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.suggest(text, limit), nil
+}
+
+// SuggestProjectHandler creates a handler that suggests projects for a new
+// task's content or an existing task.
+func SuggestProjectHandler(client todoist.API, classifier *ProjectClassifier) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		content, _ := args["content"].(string)
+		taskID, _ := args["task_id"].(string)
+		if taskID != "" {
+			if err := ValidateID(taskID, "task_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			respBody, err := client.Get(ctx, fmt.Sprintf("/tasks/%s", taskID))
+			if err != nil {
+				return respond.Errorf("failed to get task: %v", err), nil
+			}
+			var task models.Task
+			if err := json.Unmarshal(respBody, &task); err != nil {
+				return respond.Errorf("failed to parse task: %v", err), nil
+			}
+			content = task.Content
+		}
+		if strings.TrimSpace(content) == "" {
+			return respond.Error("content or task_id is required"), nil
+		}
+		limit := 3
+		if l, ok := args["limit"].(float64); ok {
+			if l < 1 || l > 10 {
+				return respond.Error("limit must be between 1 and 10"), nil
+			}
+			limit = int(l)
+		}
+
+		model, err := classifier.load(ctx)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		response := respond.List("suggestions", model.suggest(content, limit)).
+			Set("content", content).
+			Set("trained_on_tasks", model.trainedOn)
+		if taskID != "" {
+			response.Set("task_id", taskID)
+		}
+		if response["count"] == 0 {
+			response.Warn("no word of the content appears in tasks outside the Inbox; add more detail or pick a project by hand")
+		}
+		return respond.JSON(response), nil
+	}
+}
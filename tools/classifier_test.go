@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// classifierSync serves a full sync with Work, Home, an archived project, and
+// the Inbox, counting the requests in calls.
+func classifierSync(calls *int) *MockSyncAPI {
+	return &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		*calls++
+		if !strings.HasPrefix(path, "/sync?") {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return json.Marshal(map[string]interface{}{
+			"projects": []map[string]interface{}{
+				{"id": "p-in", "name": "Inbox", "is_inbox_project": true},
+				{"id": "p-work", "name": "Work"},
+				{"id": "p-home", "name": "Home"},
+				{"id": "p-old", "name": "Old", "is_archived": true},
+			},
+			"items": []map[string]interface{}{
+				{"id": "1", "project_id": "p-work", "content": "Send invoice to vendor"},
+				{"id": "2", "project_id": "p-work", "content": "Review vendor contract"},
+				{"id": "3", "project_id": "p-work", "content": "Prepare quarterly report"},
+				{"id": "4", "project_id": "p-home", "content": "Fix the kitchen sink"},
+				{"id": "5", "project_id": "p-home", "content": "Buy paint for kitchen"},
+				{"id": "6", "project_id": "p-in", "content": "Vendor vendor vendor"},
+				{"id": "7", "project_id": "p-old", "content": "Garden kitchen"},
+				{"id": "8", "project_id": "p-home", "content": "Old vendor task", "is_deleted": true},
+			},
+		})
+	}}
+}
+
+func TestClassifierTokens(t *testing.T) {
+	got := classifierTokens("Call the Vendor about Q4 invoice #42, é-mail & a")
+	want := []string{"call", "vendor", "about", "q4", "invoice", "42", "mail"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokens = %v, want %v", got, want)
+	}
+}
+
+func TestProjectClassifier_Suggest(t *testing.T) {
+	calls := 0
+	classifier := NewProjectClassifier(classifierSync(&calls), time.Hour)
+
+	suggestions, err := classifier.Suggest(context.Background(), "Pay vendor invoice", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 2 || suggestions[0].ProjectID != "p-work" || suggestions[0].ProjectName != "Work" || suggestions[0].Confidence < 0.8 {
+		t.Fatalf("suggestions = %+v", suggestions)
+	}
+	if !reflect.DeepEqual(suggestions[0].Keywords, []string{"vendor", "invoice"}) {
+		t.Errorf("keywords = %v", suggestions[0].Keywords)
+	}
+	if total := suggestions[0].Confidence + suggestions[1].Confidence; total < 0.99 || total > 1.01 {
+		t.Errorf("confidences sum to %v", total)
+	}
+
+	// Archived projects and the Inbox are not targets.
+	if suggestions, _ := classifier.Suggest(context.Background(), "Garden", 5); len(suggestions) != 0 {
+		t.Errorf("garden suggestions = %+v", suggestions)
+	}
+	if suggestions, _ := classifier.Suggest(context.Background(), "Repaint kitchen", 1); len(suggestions) != 1 || suggestions[0].ProjectID != "p-home" {
+		t.Errorf("kitchen suggestions = %+v", suggestions)
+	}
+	if calls != 1 {
+		t.Errorf("sync requests = %d, want 1 (model cached)", calls)
+	}
+}
+
+func TestSuggestProjectHandler(t *testing.T) {
+	calls := 0
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if path == "/tasks/9" {
+			return []byte(`{"id":"9","content":"Fix kitchen light"}`), nil
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}}
+	handler := SuggestProjectHandler(client, NewProjectClassifier(classifierSync(&calls), time.Hour))
+
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"task_id": "9"}))
+	text := resultText(result)
+	if result.IsError || !strings.Contains(text, `"project_id": "p-home"`) || !strings.Contains(text, `"trained_on_tasks": 5`) || !strings.Contains(text, `"task_id": "9"`) {
+		t.Errorf("task_id result = %s", text)
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"content": "Unrelated words"}))
+	if text := resultText(result); result.IsError || !strings.Contains(text, `"count": 0`) || !strings.Contains(text, "warnings") {
+		t.Errorf("unknown content result = %s", text)
+	}
+
+	for _, args := range []map[string]interface{}{{}, {"content": "vendor", "limit": float64(11)}} {
+		if result, _ := handler(context.Background(), makeReq(args)); !result.IsError {
+			t.Errorf("%v: expected tool error, got %s", args, resultText(result))
+		}
+	}
+}
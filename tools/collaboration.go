@@ -0,0 +1,489 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// fetchCollaborators returns the collaborators of a shared project, mapping
+// user IDs to names.
+func fetchCollaborators(ctx context.Context, client todoist.API, projectID string) (map[string]string, error) {
+	respBody, err := client.Get(ctx, fmt.Sprintf("/projects/%s/collaborators", projectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch collaborators: %w", err)
+	}
+	var collaborators []map[string]interface{}
+	if err := json.Unmarshal(respBody, &collaborators); err != nil {
+		return nil, fmt.Errorf("failed to parse collaborators: %w", err)
+	}
+	names := make(map[string]string, len(collaborators))
+	for _, c := range collaborators {
+		if id, ok := c["id"].(string); ok {
+			name, _ := c["name"].(string)
+			names[id] = name
+		}
+	}
+	return names, nil
+}
+
+// ReassignTasksHandler creates a handler that moves active tasks in a shared
+// project from one collaborator to another.
+func ReassignTasksHandler(client todoist.API, syncClient todoist.SyncAPI, confirmations *ConfirmationStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		ctx, err := bulkStrategyArg(ctx, args)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		projectID, _ := args["project_id"].(string)
+		fromID, _ := args["from_assignee_id"].(string)
+		toID, _ := args["to_assignee_id"].(string)
+		for _, p := range [][2]string{{"project_id", projectID}, {"from_assignee_id", fromID}, {"to_assignee_id", toID}} {
+			if err := ValidateID(p[1], p[0]); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+		if fromID == toID {
+			return respond.Error("from_assignee_id and to_assignee_id must differ"), nil
+		}
+
+		collaborators, err := fetchCollaborators(ctx, client, projectID)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		if _, ok := collaborators[toID]; !ok {
+			return respond.Errorf("user %s is not a collaborator of project %s; use get_project_stats or the Todoist app to check who the project is shared with", toID, projectID), nil
+		}
+
+		// Without task_ids or a filter, every task of the project is a candidate.
+		params := url.Values{}
+		filter, _ := args["filter"].(string)
+		selected := filter != "" || len(taskIDsArg(args)) > 0
+		if selected {
+			taskIDs, preview := selectBulkTaskIDs(ctx, client, confirmations, args, "reassign_tasks", projectID, fromID, toID)
+			if preview != nil {
+				return preview, nil
+			}
+			params.Set("ids", strings.Join(taskIDs, ","))
+		} else {
+			params.Set("project_id", projectID)
+		}
+		tasks, err := fetchTasks(ctx, client, params)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		skipped := 0
+		results := make([]map[string]interface{}, 0, len(tasks))
+		contents := make(map[string]interface{}, len(tasks))
+		var ops []todoist.BulkOperation
+		for _, task := range tasks {
+			taskID := task.ID
+			contents[taskID] = task.Content
+			reason := ""
+			switch {
+			case task.ProjectID != projectID:
+				reason = "not in project"
+			case task.AssigneeID != fromID:
+				reason = "not assigned to from_assignee_id"
+			}
+			if reason != "" {
+				// Project-wide runs only report the tasks they change.
+				if !selected {
+					continue
+				}
+				skipped++
+				results = append(results, map[string]interface{}{"task_id": taskID, "content": task.Content, "status": "skipped", "reason": reason})
+				continue
+			}
+			ops = append(ops, todoist.BulkOperation{
+				ID: taskID,
+				Command: todoist.Command{
+					Type: "item_update",
+					UUID: todoist.GenerateUUID(),
+					Args: map[string]interface{}{"id": taskID, "responsible_uid": toID},
+				},
+				Method: http.MethodPost,
+				Path:   fmt.Sprintf("/tasks/%s", taskID),
+				Body:   map[string]interface{}{"assignee_id": toID},
+				Revert: &todoist.BulkOperation{
+					Command: todoist.Command{
+						Type: "item_update",
+						UUID: todoist.GenerateUUID(),
+						Args: map[string]interface{}{"id": taskID, "responsible_uid": fromID},
+					},
+					Method: http.MethodPost,
+					Path:   fmt.Sprintf("/tasks/%s", taskID),
+					Body:   map[string]interface{}{"assignee_id": fromID},
+				},
+			})
+		}
+
+		if estimateCostRequested(args) {
+			return bulkCostResult(ctx, client, syncClient, ops)
+		}
+
+		reassigned := 0
+		if len(ops) > 0 {
+			result, err := todoist.NewBulkExecutor(client, syncClient).Execute(ctx, ops)
+			if err != nil {
+				return respond.Errorf("failed to reassign tasks: %v", err), nil
+			}
+			if atomicRequested(args) && len(result.Failed) > 0 {
+				return atomicFailure(ctx, client, syncClient, ops, result, "reassign"), nil
+			}
+			reassigned = len(result.Succeeded)
+			for _, id := range result.Succeeded {
+				results = append(results, map[string]interface{}{"task_id": id, "content": contents[id], "status": "reassigned"})
+			}
+			for _, f := range result.Failed {
+				results = append(results, map[string]interface{}{"task_id": f.ID, "content": contents[f.ID], "status": "failed", "error": f.Error})
+			}
+		}
+
+		response := map[string]interface{}{
+			"project_id": projectID,
+			"from":       map[string]interface{}{"id": fromID, "name": collaborators[fromID]},
+			"to":         map[string]interface{}{"id": toID, "name": collaborators[toID]},
+			"reassigned": reassigned,
+			"failed":     len(ops) - reassigned,
+			"skipped":    skipped,
+			"tasks":      results,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// FindOrphanAssignmentsHandler creates a handler that finds tasks in shared
+// projects assigned to users who are no longer collaborators, and optionally
+// unassigns them or reassigns them to a current collaborator.
+func FindOrphanAssignmentsHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		action, _ := args["action"].(string)
+		if action == "" {
+			action = "report"
+		}
+		if action != "report" && action != "unassign" && action != "reassign" {
+			return respond.Error("action must be one of: report, unassign, reassign"), nil
+		}
+		toID, _ := args["to_assignee_id"].(string)
+		if action == "reassign" {
+			if err := ValidateID(toID, "to_assignee_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+		confirm, _ := args["confirm"].(bool)
+		if action != "report" && !confirm {
+			return respond.Errorf("action '%s' requires confirm: true; run with action 'report' first to review the orphaned tasks", action), nil
+		}
+
+		// Without project_id, every shared project is checked.
+		var projectIDs []string
+		if projectID, _ := args["project_id"].(string); projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			projectIDs = []string{projectID}
+		} else {
+			respBody, err := client.Get(ctx, "/projects")
+			if err != nil {
+				return respond.Errorf("failed to fetch projects: %v", err), nil
+			}
+			var projects []models.Project
+			if err := json.Unmarshal(respBody, &projects); err != nil {
+				return respond.Errorf("failed to parse projects: %v", err), nil
+			}
+			for _, project := range projects {
+				if project.IsShared {
+					projectIDs = append(projectIDs, project.ID)
+				}
+			}
+		}
+
+		orphans := make([]map[string]interface{}, 0)
+		skipped := make([]map[string]interface{}, 0)
+		var ops []todoist.BulkOperation
+		for _, projectID := range projectIDs {
+			collaborators, err := fetchCollaborators(ctx, client, projectID)
+			if err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			params := url.Values{}
+			params.Set("project_id", projectID)
+			tasks, err := fetchTasks(ctx, client, params)
+			if err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			for _, task := range tasks {
+				assignee := task.AssigneeID
+				if assignee == "" {
+					continue
+				}
+				if _, ok := collaborators[assignee]; ok {
+					continue
+				}
+				taskID := task.ID
+				orphans = append(orphans, map[string]interface{}{
+					"task_id":     taskID,
+					"content":     task.Content,
+					"project_id":  projectID,
+					"assignee_id": assignee,
+				})
+
+				var assignTo interface{}
+				switch action {
+				case "report":
+					continue
+				case "reassign":
+					if _, ok := collaborators[toID]; !ok {
+						skipped = append(skipped, map[string]interface{}{"task_id": taskID, "reason": fmt.Sprintf("user %s is not a collaborator of project %s", toID, projectID)})
+						continue
+					}
+					assignTo = toID
+				}
+				ops = append(ops, todoist.BulkOperation{
+					ID: taskID,
+					Command: todoist.Command{
+						Type: "item_update",
+						UUID: todoist.GenerateUUID(),
+						Args: map[string]interface{}{"id": taskID, "responsible_uid": assignTo},
+					},
+					Method: http.MethodPost,
+					Path:   fmt.Sprintf("/tasks/%s", taskID),
+					Body:   map[string]interface{}{"assignee_id": assignTo},
+				})
+			}
+		}
+
+		response := respond.List("orphans", orphans).
+			Set("action", action).
+			Set("projects_checked", len(projectIDs))
+		if action == "report" {
+			return respond.JSON(response), nil
+		}
+
+		failed := make([]todoist.BulkFailure, 0)
+		changed := 0
+		if len(ops) > 0 {
+			result, err := todoist.NewBulkExecutor(client, syncClient).Execute(ctx, ops)
+			if err != nil {
+				return respond.Errorf("failed to update assignments: %v", err), nil
+			}
+			changed = len(result.Succeeded)
+			failed = append(failed, result.Failed...)
+		}
+		if action == "unassign" {
+			response.Set("unassigned", changed)
+		} else {
+			response.Set("reassigned", changed).
+				Set("to_assignee_id", toID).
+				Set("skipped", skipped)
+		}
+		response.Set("failed", failed)
+
+		return respond.JSON(response), nil
+	}
+}
+
+// unassignedKey groups tasks without an assignee in digests.
+const unassignedKey = "unassigned"
+
+// completedBy returns the user a completed task is credited to: its assignee
+// if it had one, otherwise whoever completed it.
+func completedBy(item models.Task) string {
+	for _, id := range []string{item.ResponsibleUID, item.AssigneeID, item.CompletedByUID, item.UserID} {
+		if id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// digestTask trims a task to the fields shown in digests.
+func digestTask(task models.Task) map[string]interface{} {
+	return map[string]interface{}{
+		"id":       task.ID,
+		"content":  task.Content,
+		"due":      task.Due.Day(),
+		"priority": task.Priority,
+	}
+}
+
+// collaboratorDigest collects one person's tasks.
+type collaboratorDigest struct {
+	ID          string                   `json:"id"`
+	Name        string                   `json:"name"`
+	Overdue     []map[string]interface{} `json:"overdue"`
+	DueThisWeek []map[string]interface{} `json:"due_this_week"`
+	Completed   []map[string]interface{} `json:"recently_completed"`
+	Summary     string                   `json:"summary"`
+}
+
+// summarize renders the digest as plain text suitable for chat or email.
+func (d *collaboratorDigest) summarize(completedDays int) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d overdue, %d due this week, %d completed in the last %d days", d.Name, len(d.Overdue), len(d.DueThisWeek), len(d.Completed), completedDays)
+	section := func(title string, items []map[string]interface{}, dateKey string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s:", title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "\n- %v", item["content"])
+			if date, _ := item[dateKey].(string); date != "" {
+				fmt.Fprintf(&b, " (%s)", date)
+			}
+		}
+	}
+	section("Overdue", d.Overdue, "due")
+	section("Due this week", d.DueThisWeek, "due")
+	section("Completed", d.Completed, "completed_at")
+	d.Summary = b.String()
+}
+
+// CollaboratorDigestHandler creates a handler that summarizes a shared
+// project per collaborator: overdue tasks, tasks due this week, and recent
+// completions.
+func CollaboratorDigestHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectID, _ := args["project_id"].(string)
+		if err := ValidateID(projectID, "project_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		completedDays := 7
+		if d, ok := args["completed_days"].(float64); ok {
+			if d < 1 || d > 30 {
+				return respond.Error("completed_days must be between 1 and 30"), nil
+			}
+			completedDays = int(d)
+		}
+		includeUnassigned, _ := args["include_unassigned"].(bool)
+
+		collaborators, err := fetchCollaborators(ctx, client, projectID)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		loc, err := userLocation(ctx, syncClient)
+		if err != nil {
+			return respond.Errorf("failed to resolve user time zone: %v", err), nil
+		}
+		now := time.Now().In(loc)
+		today := now.Format("2006-01-02")
+		// Weeks run Monday to Sunday.
+		weekEnd := now.AddDate(0, 0, 6-(int(now.Weekday())+6)%7).Format("2006-01-02")
+
+		params := url.Values{}
+		params.Set("project_id", projectID)
+		tasks, err := fetchTasks(ctx, client, params)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		extra := url.Values{}
+		extra.Set("project_id", projectID)
+		completed, truncated, err := fetchCompletedTasks(ctx, syncClient, now.AddDate(0, 0, -completedDays), now, extra)
+		if err != nil {
+			return respond.Errorf("failed to fetch completed tasks: %v", err), nil
+		}
+
+		digests := make(map[string]*collaboratorDigest, len(collaborators)+1)
+		digestFor := func(id string) *collaboratorDigest {
+			if id == "" {
+				if !includeUnassigned {
+					return nil
+				}
+				id = unassignedKey
+			}
+			d, ok := digests[id]
+			if !ok {
+				name := collaborators[id]
+				if id == unassignedKey {
+					name = "Unassigned"
+				} else if name == "" {
+					name = id
+				}
+				d = &collaboratorDigest{
+					ID: id, Name: name,
+					Overdue:     []map[string]interface{}{},
+					DueThisWeek: []map[string]interface{}{},
+					Completed:   []map[string]interface{}{},
+				}
+				digests[id] = d
+			}
+			return d
+		}
+		for id := range collaborators {
+			digestFor(id)
+		}
+
+		for _, task := range tasks {
+			due := task.Due.Day()
+			if due == "" || due > weekEnd {
+				continue
+			}
+			d := digestFor(task.AssigneeID)
+			if d == nil {
+				continue
+			}
+			if due < today {
+				d.Overdue = append(d.Overdue, digestTask(task))
+			} else {
+				d.DueThisWeek = append(d.DueThisWeek, digestTask(task))
+			}
+		}
+		for _, item := range completed {
+			if d := digestFor(completedBy(item)); d != nil {
+				d.Completed = append(d.Completed, map[string]interface{}{
+					"id":           completedTaskID(item),
+					"content":      item.Content,
+					"completed_at": item.CompletedAt,
+				})
+			}
+		}
+
+		people := make([]*collaboratorDigest, 0, len(digests))
+		for _, d := range digests {
+			byDue := func(items []map[string]interface{}) {
+				sort.SliceStable(items, func(i, j int) bool { return fmt.Sprint(items[i]["due"]) < fmt.Sprint(items[j]["due"]) })
+			}
+			byDue(d.Overdue)
+			byDue(d.DueThisWeek)
+			d.summarize(completedDays)
+			people = append(people, d)
+		}
+		sort.Slice(people, func(i, j int) bool {
+			if (people[i].ID == unassignedKey) != (people[j].ID == unassignedKey) {
+				return people[j].ID == unassignedKey
+			}
+			return strings.ToLower(people[i].Name) < strings.ToLower(people[j].Name)
+		})
+
+		response := respond.Envelope{
+			"project_id":     projectID,
+			"today":          today,
+			"week_ends":      weekEnd,
+			"completed_days": completedDays,
+			"people":         people,
+		}
+		warnCompletedTruncated(response, truncated)
+
+		return respond.JSON(response), nil
+	}
+}
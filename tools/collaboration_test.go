@@ -0,0 +1,265 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func collaborationMock(tasks []map[string]interface{}, posts map[string]interface{}) *MockAPI {
+	return &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			switch {
+			case path == "/projects/p1/collaborators":
+				return json.Marshal([]map[string]interface{}{
+					{"id": "u1", "name": "Ada"},
+					{"id": "u2", "name": "Grace"},
+				})
+			case strings.HasPrefix(path, "/tasks?"):
+				return json.Marshal(tasks)
+			}
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		},
+		PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+			posts[path] = body
+			return json.Marshal(map[string]interface{}{"id": "x"})
+		},
+	}
+}
+
+func TestReassignTasksHandler(t *testing.T) {
+	tasks := []map[string]interface{}{
+		{"id": "1", "content": "Review PR", "project_id": "p1", "assignee_id": "u1"},
+		{"id": "2", "content": "Write docs", "project_id": "p1", "assignee_id": "u2"},
+		{"id": "3", "content": "Other project", "project_id": "p9", "assignee_id": "u1"},
+	}
+
+	t.Run("project wide", func(t *testing.T) {
+		posts := make(map[string]interface{})
+		handler := ReassignTasksHandler(collaborationMock(tasks[:2], posts), &MockSyncAPI{}, NewConfirmationStore(time.Minute))
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{
+			"project_id": "p1", "from_assignee_id": "u1", "to_assignee_id": "u2",
+		}))
+		if result.IsError {
+			t.Fatalf("unexpected tool error: %s", resultText(result))
+		}
+		if len(posts) != 1 || posts["/tasks/1"].(map[string]interface{})["assignee_id"] != "u2" {
+			t.Errorf("posts = %v", posts)
+		}
+		var resp struct {
+			Reassigned int                      `json:"reassigned"`
+			Skipped    int                      `json:"skipped"`
+			Tasks      []map[string]interface{} `json:"tasks"`
+		}
+		if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Reassigned != 1 || resp.Skipped != 0 || len(resp.Tasks) != 1 || resp.Tasks[0]["status"] != "reassigned" {
+			t.Errorf("response = %+v", resp)
+		}
+	})
+
+	t.Run("task ids report skipped tasks", func(t *testing.T) {
+		posts := make(map[string]interface{})
+		handler := ReassignTasksHandler(collaborationMock(tasks, posts), &MockSyncAPI{}, NewConfirmationStore(time.Minute))
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{
+			"project_id": "p1", "from_assignee_id": "u1", "to_assignee_id": "u2",
+			"task_ids": []interface{}{"1", "2", "3"},
+		}))
+		text := resultText(result)
+		if result.IsError || len(posts) != 1 {
+			t.Fatalf("result = %s, posts = %v", text, posts)
+		}
+		if !strings.Contains(text, `"skipped": 2`) || !strings.Contains(text, "not in project") || !strings.Contains(text, "not assigned to from_assignee_id") {
+			t.Errorf("response = %s", text)
+		}
+	})
+
+	t.Run("sync batch", func(t *testing.T) {
+		many := make([]map[string]interface{}, 8)
+		for i := range many {
+			many[i] = map[string]interface{}{"id": fmt.Sprint(i), "project_id": "p1", "assignee_id": "u1"}
+		}
+		var sent []todoist.Command
+		syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+			sent = commands
+			status := make(map[string]interface{})
+			for _, cmd := range commands {
+				status[cmd.UUID] = "ok"
+			}
+			return &todoist.SyncResponse{SyncStatus: status}, nil
+		}}
+		handler := ReassignTasksHandler(collaborationMock(many, map[string]interface{}{}), syncClient, NewConfirmationStore(time.Minute))
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{
+			"project_id": "p1", "from_assignee_id": "u1", "to_assignee_id": "u2",
+		}))
+		if result.IsError || len(sent) != 8 || sent[0].Type != "item_update" || sent[0].Args["responsible_uid"] != "u2" {
+			t.Errorf("result = %s, sent = %+v", resultText(result), sent)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		handler := ReassignTasksHandler(collaborationMock(nil, map[string]interface{}{}), &MockSyncAPI{}, NewConfirmationStore(time.Minute))
+		tests := []struct {
+			args map[string]interface{}
+			want string
+		}{
+			{map[string]interface{}{"project_id": "p1", "from_assignee_id": "u1"}, "to_assignee_id is required"},
+			{map[string]interface{}{"project_id": "p1", "from_assignee_id": "u1", "to_assignee_id": "u1"}, "must differ"},
+			{map[string]interface{}{"project_id": "p1", "from_assignee_id": "u1", "to_assignee_id": "u7"}, "not a collaborator"},
+		}
+		for _, tt := range tests {
+			result, _ := handler(context.Background(), makeReq(tt.args))
+			if !result.IsError || !strings.Contains(resultText(result), tt.want) {
+				t.Errorf("result = %q, want %q", resultText(result), tt.want)
+			}
+		}
+	})
+}
+
+func TestCollaboratorDigestHandler(t *testing.T) {
+	today := time.Now().UTC()
+	day := func(offset int) string { return today.AddDate(0, 0, offset).Format("2006-01-02") }
+	weekEnd := today.AddDate(0, 0, 6-(int(today.Weekday())+6)%7)
+	tasks := []map[string]interface{}{
+		{"id": "1", "content": "Fix login", "assignee_id": "u1", "due": map[string]interface{}{"date": day(-2)}},
+		{"id": "2", "content": "Ship release", "assignee_id": "u1", "due": map[string]interface{}{"date": day(0)}},
+		{"id": "3", "content": "Plan Q3", "assignee_id": "u2", "due": map[string]interface{}{"date": weekEnd.AddDate(0, 0, 1).Format("2006-01-02")}},
+		{"id": "4", "content": "Triage inbox", "due": map[string]interface{}{"date": day(0)}},
+	}
+	client := collaborationMock(tasks, map[string]interface{}{})
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if path == "/user" {
+			return []byte(`{"tz_info": {"timezone": "UTC"}}`), nil
+		}
+		if !strings.Contains(path, "project_id=p1") {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return json.Marshal(map[string]interface{}{"items": []map[string]interface{}{
+			{"task_id": "9", "content": "Write docs", "responsible_uid": "u2", "completed_at": today.Format(time.RFC3339)},
+		}})
+	}}
+
+	handler := CollaboratorDigestHandler(client, syncClient)
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"project_id": "p1", "include_unassigned": true}))
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+	var resp struct {
+		People []collaboratorDigest `json:"people"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.People) != 3 || resp.People[0].Name != "Ada" || resp.People[1].Name != "Grace" || resp.People[2].ID != unassignedKey {
+		t.Fatalf("people = %+v", resp.People)
+	}
+	ada, grace, unassigned := resp.People[0], resp.People[1], resp.People[2]
+	if len(ada.Overdue) != 1 || len(ada.DueThisWeek) != 1 || !strings.Contains(ada.Summary, "1 overdue, 1 due this week") {
+		t.Errorf("Ada = %+v", ada)
+	}
+	if len(grace.DueThisWeek) != 0 || len(grace.Completed) != 1 || !strings.Contains(grace.Summary, "- Write docs") {
+		t.Errorf("Grace = %+v", grace)
+	}
+	if len(unassigned.DueThisWeek) != 1 {
+		t.Errorf("unassigned = %+v", unassigned)
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"project_id": "p1", "completed_days": float64(60)}))
+	if !result.IsError || !strings.Contains(resultText(result), "completed_days") {
+		t.Errorf("result = %q, want completed_days error", resultText(result))
+	}
+}
+
+func TestFindOrphanAssignmentsHandler(t *testing.T) {
+	tasks := []map[string]interface{}{
+		{"id": "1", "content": "Review PR", "project_id": "p1", "assignee_id": "u1"},
+		{"id": "2", "content": "Write docs", "project_id": "p1", "assignee_id": "gone"},
+		{"id": "3", "content": "Plan offsite", "project_id": "p1"},
+	}
+	mock := func(posts map[string]interface{}) *MockAPI {
+		client := collaborationMock(tasks, posts)
+		get := client.GetFn
+		client.GetFn = func(ctx context.Context, path string) ([]byte, error) {
+			if path == "/projects" {
+				return json.Marshal([]map[string]interface{}{
+					{"id": "p1", "is_shared": true},
+					{"id": "p2", "is_shared": false},
+				})
+			}
+			return get(ctx, path)
+		}
+		return client
+	}
+
+	t.Run("report checks shared projects", func(t *testing.T) {
+		posts := make(map[string]interface{})
+		handler := FindOrphanAssignmentsHandler(mock(posts), &MockSyncAPI{})
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{}))
+		var resp struct {
+			Count           int                      `json:"count"`
+			ProjectsChecked int                      `json:"projects_checked"`
+			Orphans         []map[string]interface{} `json:"orphans"`
+		}
+		if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+			t.Fatalf("parse: %v (%s)", err, resultText(result))
+		}
+		if resp.Count != 1 || resp.ProjectsChecked != 1 || resp.Orphans[0]["task_id"] != "2" || resp.Orphans[0]["assignee_id"] != "gone" {
+			t.Errorf("response = %+v", resp)
+		}
+		if len(posts) != 0 {
+			t.Errorf("report changed tasks: %v", posts)
+		}
+	})
+
+	t.Run("unassign", func(t *testing.T) {
+		posts := make(map[string]interface{})
+		handler := FindOrphanAssignmentsHandler(mock(posts), &MockSyncAPI{})
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{"project_id": "p1", "action": "unassign", "confirm": true}))
+		if text := resultText(result); result.IsError || !strings.Contains(text, `"unassigned": 1`) {
+			t.Errorf("result = %s", text)
+		}
+		body, _ := posts["/tasks/2"].(map[string]interface{})
+		if assignee, ok := body["assignee_id"]; len(posts) != 1 || !ok || assignee != nil {
+			t.Errorf("posts = %v", posts)
+		}
+	})
+
+	t.Run("reassign", func(t *testing.T) {
+		posts := make(map[string]interface{})
+		handler := FindOrphanAssignmentsHandler(mock(posts), &MockSyncAPI{})
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{"project_id": "p1", "action": "reassign", "to_assignee_id": "u2", "confirm": true}))
+		if text := resultText(result); result.IsError || !strings.Contains(text, `"reassigned": 1`) {
+			t.Errorf("result = %s", text)
+		}
+		if body, _ := posts["/tasks/2"].(map[string]interface{}); body["assignee_id"] != "u2" {
+			t.Errorf("posts = %v", posts)
+		}
+
+		result, _ = handler(context.Background(), makeReq(map[string]interface{}{"project_id": "p1", "action": "reassign", "to_assignee_id": "stranger", "confirm": true}))
+		if text := resultText(result); !strings.Contains(text, `"reassigned": 0`) || !strings.Contains(text, "not a collaborator") {
+			t.Errorf("result = %s", text)
+		}
+	})
+
+	tests := []struct {
+		args map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{"action": "archive"}, "action must be one of"},
+		{map[string]interface{}{"action": "unassign"}, "requires confirm: true"},
+		{map[string]interface{}{"action": "reassign", "confirm": true}, "to_assignee_id is required"},
+	}
+	for _, tt := range tests {
+		posts := make(map[string]interface{})
+		result, _ := FindOrphanAssignmentsHandler(mock(posts), &MockSyncAPI{})(context.Background(), makeReq(tt.args))
+		if !result.IsError || !strings.Contains(resultText(result), tt.want) || len(posts) != 0 {
+			t.Errorf("%v: result = %s, want %q", tt.args, resultText(result), tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// syncCommandTypes are the Sync API command types execute_commands accepts.
+var syncCommandTypes = []string{
+	"project_add", "project_update", "project_move", "project_archive", "project_unarchive", "project_delete",
+	"section_add", "section_update", "section_move", "section_archive", "section_unarchive", "section_delete",
+	"item_add", "item_update", "item_move", "item_close", "item_uncomplete", "item_delete",
+	"label_add", "label_update", "label_delete",
+	"note_add", "note_update", "note_delete",
+	"reminder_add", "reminder_update", "reminder_delete",
+}
+
+// tempRefPrefix marks an ID argument as a reference to the temp_id of an
+// earlier command, e.g. "$proj". Only ID arguments are resolved, so text such
+// as content "$5 coffee" is sent as written.
+const tempRefPrefix = "$"
+
+// parseSyncCommands validates raw execute_commands input and converts it to
+// Sync commands. Client-chosen temp_id names are replaced with generated temp
+// IDs; refs maps each name to the generated ID.
+func parseSyncCommands(raw []interface{}) ([]todoist.Command, map[string]string, error) {
+	if len(raw) == 0 {
+		return nil, nil, fmt.Errorf("commands must be a non-empty array")
+	}
+	if len(raw) > todoist.MaxSyncCommands {
+		return nil, nil, fmt.Errorf("too many commands: %d (maximum %d per request)", len(raw), todoist.MaxSyncCommands)
+	}
+
+	refs := make(map[string]string)
+	commands := make([]todoist.Command, 0, len(raw))
+	for i, item := range raw {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("commands[%d] must be an object", i)
+		}
+		cmdType, _ := obj["type"].(string)
+		if !slices.Contains(syncCommandTypes, cmdType) {
+			return nil, nil, fmt.Errorf("commands[%d]: unsupported type %q (supported: %s)", i, cmdType, strings.Join(syncCommandTypes, ", "))
+		}
+		args, ok := obj["args"].(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("commands[%d]: args must be an object", i)
+		}
+
+		// References resolve only to earlier commands, so the name of this
+		// command's own temp_id is registered after its args are checked.
+		resolved, err := resolveTempRefs(args, refs, fmt.Sprintf("commands[%d].args", i), false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cmd := todoist.Command{Type: cmdType, UUID: todoist.GenerateUUID(), Args: resolved.(map[string]interface{})}
+		if name, _ := obj["temp_id"].(string); name != "" {
+			if !strings.HasSuffix(cmdType, "_add") {
+				return nil, nil, fmt.Errorf("commands[%d]: temp_id is only allowed on *_add commands", i)
+			}
+			if _, dup := refs[name]; dup {
+				return nil, nil, fmt.Errorf("commands[%d]: duplicate temp_id %q", i, name)
+			}
+			cmd.TempID = todoist.GenerateTempID()
+			refs[name] = cmd.TempID
+		}
+		commands = append(commands, cmd)
+	}
+	return commands, refs, nil
+}
+
+// isIDKey reports whether an argument holds an ID or a list of IDs:
+// "id", "ids", and keys ending in "_id" or "_ids".
+func isIDKey(key string) bool {
+	return key == "id" || key == "ids" || strings.HasSuffix(key, "_id") || strings.HasSuffix(key, "_ids")
+}
+
+// resolveTempRefs replaces "$name" ID arguments anywhere in v with the
+// generated temp ID for name and validates plain ones. isID is set for the
+// value of an ID argument; other strings are left alone.
+func resolveTempRefs(v interface{}, refs map[string]string, path string, isID bool) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			resolved, err := resolveTempRefs(item, refs, path+"."+key, isIDKey(key))
+			if err != nil {
+				return nil, err
+			}
+			out[key] = resolved
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			resolved, err := resolveTempRefs(item, refs, fmt.Sprintf("%s[%d]", path, i), isID)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	case string:
+		if !isID {
+			return val, nil
+		}
+		name, ok := strings.CutPrefix(val, tempRefPrefix)
+		if !ok || name == "" {
+			if err := ValidateID(val, path); err != nil {
+				return nil, err
+			}
+			return val, nil
+		}
+		tempID, ok := refs[name]
+		if !ok {
+			return nil, fmt.Errorf("%s references unknown temp_id %q; define it with temp_id on an earlier command", path, name)
+		}
+		return tempID, nil
+	default:
+		return v, nil
+	}
+}
+
+// ExecuteCommandsHandler creates a handler that sends a validated list of Sync
+// commands in one request, so related changes such as a project with its
+// sections and tasks are applied together.
+func ExecuteCommandsHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		raw, _ := args["commands"].([]interface{})
+		commands, refs, err := parseSyncCommands(raw)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		syncResp, err := syncClient.BatchCommands(ctx, commands)
+		if err != nil {
+			return respond.Errorf("failed to execute commands: %v", err), nil
+		}
+
+		succeeded := 0
+		results := make([]map[string]interface{}, 0, len(commands))
+		for i, cmd := range commands {
+			entry := map[string]interface{}{"index": i, "type": cmd.Type}
+			if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+				succeeded++
+				entry["status"] = "ok"
+			} else {
+				entry["status"] = "failed"
+				entry["error"] = fmt.Sprintf("%v", syncResp.SyncStatus[cmd.UUID])
+			}
+			results = append(results, entry)
+		}
+
+		mapping := make(map[string]string, len(refs))
+		for name, tempID := range refs {
+			if realID, ok := syncResp.TempIDMapping[tempID]; ok {
+				mapping[name] = realID
+			}
+		}
+
+		response := map[string]interface{}{
+			"succeeded":       succeeded,
+			"failed":          len(commands) - succeeded,
+			"commands":        results,
+			"temp_id_mapping": mapping,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestExecuteCommandsHandler(t *testing.T) {
+	var sent []todoist.Command
+	syncClient := &MockSyncAPI{
+		BatchCommandsFn: func(ctx context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+			sent = commands
+			status := map[string]interface{}{}
+			for _, cmd := range commands {
+				status[cmd.UUID] = "ok"
+			}
+			return &todoist.SyncResponse{
+				SyncStatus:    status,
+				TempIDMapping: map[string]string{commands[0].TempID: "proj-1", commands[1].TempID: "sec-1"},
+			}, nil
+		},
+	}
+
+	handler := ExecuteCommandsHandler(syncClient)
+	result, err := handler(context.Background(), makeReq(map[string]interface{}{
+		"commands": []interface{}{
+			map[string]interface{}{"type": "project_add", "temp_id": "proj", "args": map[string]interface{}{"name": "Launch"}},
+			map[string]interface{}{"type": "section_add", "temp_id": "design", "args": map[string]interface{}{"name": "Design", "project_id": "$proj"}},
+			map[string]interface{}{"type": "item_add", "args": map[string]interface{}{"content": "$5 coffee", "project_id": "$proj", "section_id": "$design"}},
+		},
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+
+	if len(sent) != 3 || sent[1].Args["project_id"] != sent[0].TempID || sent[2].Args["section_id"] != sent[1].TempID {
+		t.Errorf("temp references not resolved: %+v", sent)
+	}
+	if sent[2].Args["content"] != "$5 coffee" {
+		t.Errorf("content = %v, want it sent as written", sent[2].Args["content"])
+	}
+
+	var resp struct {
+		Succeeded     int               `json:"succeeded"`
+		TempIDMapping map[string]string `json:"temp_id_mapping"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Succeeded != 3 || resp.TempIDMapping["proj"] != "proj-1" || resp.TempIDMapping["design"] != "sec-1" {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestExecuteCommandsHandler_Validation(t *testing.T) {
+	syncClient := &MockSyncAPI{}
+	handler := ExecuteCommandsHandler(syncClient)
+
+	tests := map[string]struct {
+		commands []interface{}
+		want     string
+	}{
+		"empty": {nil, "non-empty"},
+		"unsupported type": {[]interface{}{
+			map[string]interface{}{"type": "user_update", "args": map[string]interface{}{}},
+		}, "unsupported type"},
+		"forward reference": {[]interface{}{
+			map[string]interface{}{"type": "item_add", "args": map[string]interface{}{"content": "x", "project_id": "$proj"}},
+			map[string]interface{}{"type": "project_add", "temp_id": "proj", "args": map[string]interface{}{"name": "p"}},
+		}, "unknown temp_id"},
+		"duplicate temp_id": {[]interface{}{
+			map[string]interface{}{"type": "project_add", "temp_id": "p", "args": map[string]interface{}{"name": "a"}},
+			map[string]interface{}{"type": "project_add", "temp_id": "p", "args": map[string]interface{}{"name": "b"}},
+		}, "duplicate temp_id"},
+		"invalid id": {[]interface{}{
+			map[string]interface{}{"type": "item_delete", "args": map[string]interface{}{"id": "../x"}},
+		}, "invalid characters"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, _ := handler(context.Background(), makeReq(map[string]interface{}{"commands": tt.commands}))
+			if !result.IsError || !strings.Contains(resultText(result), tt.want) {
+				t.Errorf("result = %q, want error containing %q", resultText(result), tt.want)
+			}
+		})
+	}
+}
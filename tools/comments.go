@@ -8,6 +8,8 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
 )
 
 // GetCommentsHandler creates a handler for getting comments.
@@ -20,7 +22,7 @@ func GetCommentsHandler(client todoist.API) func(context.Context, mcp.CallToolRe
 
 		if taskID, ok := args["task_id"].(string); ok && taskID != "" {
 			if err := ValidateID(taskID, "task_id"); err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return respond.ErrorFrom(err), nil
 			}
 			params.Set("task_id", taskID)
 			hasFilter = true
@@ -28,39 +30,31 @@ func GetCommentsHandler(client todoist.API) func(context.Context, mcp.CallToolRe
 
 		if projectID, ok := args["project_id"].(string); ok && projectID != "" {
 			if err := ValidateID(projectID, "project_id"); err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return respond.ErrorFrom(err), nil
 			}
 			params.Set("project_id", projectID)
 			hasFilter = true
 		}
 
 		if !hasFilter {
-			return mcp.NewToolResultError("either task_id or project_id is required"), nil
+			return respond.Error("either task_id or project_id is required"), nil
 		}
 
 		path := "/comments?" + params.Encode()
 
 		respBody, err := client.Get(ctx, path)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get comments: %v", err)), nil
+			return respond.Errorf("failed to get comments: %v", err), nil
 		}
 
-		var comments []map[string]interface{}
+		var comments []models.Comment
 		if err := json.Unmarshal(respBody, &comments); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse comments: %v", err)), nil
+			return respond.Errorf("failed to parse comments: %v", err), nil
 		}
 
-		response := map[string]interface{}{
-			"count":    len(comments),
-			"comments": comments,
-		}
+		response := respond.List("comments", comments)
 
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
 
@@ -71,7 +65,7 @@ func AddCommentHandler(client todoist.API) func(context.Context, mcp.CallToolReq
 
 		content, ok := args["content"].(string)
 		if !ok || content == "" {
-			return mcp.NewToolResultError("content is required"), nil
+			return respond.Error("content is required"), nil
 		}
 
 		body := map[string]interface{}{
@@ -89,25 +83,20 @@ func AddCommentHandler(client todoist.API) func(context.Context, mcp.CallToolReq
 		}
 
 		if !hasTarget {
-			return mcp.NewToolResultError("either task_id or project_id is required"), nil
+			return respond.Error("either task_id or project_id is required"), nil
 		}
 
 		respBody, err := client.Post(ctx, "/comments", body)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to add comment: %v", err)), nil
+			return respond.Errorf("failed to add comment: %v", err), nil
 		}
 
-		var comment map[string]interface{}
+		var comment models.Comment
 		if err := json.Unmarshal(respBody, &comment); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse response: %v", err)), nil
-		}
-
-		jsonData, err := json.MarshalIndent(comment, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			return respond.Errorf("failed to parse response: %v", err), nil
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(comment), nil
 	}
 }
 
@@ -118,15 +107,15 @@ func UpdateCommentHandler(client todoist.API) func(context.Context, mcp.CallTool
 
 		commentID, ok := args["comment_id"].(string)
 		if !ok || commentID == "" {
-			return mcp.NewToolResultError("comment_id is required"), nil
+			return respond.Error("comment_id is required"), nil
 		}
 		if err := ValidateID(commentID, "comment_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
 		content, ok := args["content"].(string)
 		if !ok || content == "" {
-			return mcp.NewToolResultError("content is required"), nil
+			return respond.Error("content is required"), nil
 		}
 
 		body := map[string]interface{}{
@@ -136,20 +125,15 @@ func UpdateCommentHandler(client todoist.API) func(context.Context, mcp.CallTool
 		path := fmt.Sprintf("/comments/%s", commentID)
 		respBody, err := client.Post(ctx, path, body)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to update comment: %v", err)), nil
+			return respond.Errorf("failed to update comment: %v", err), nil
 		}
 
-		var comment map[string]interface{}
+		var comment models.Comment
 		if err := json.Unmarshal(respBody, &comment); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse response: %v", err)), nil
-		}
-
-		jsonData, err := json.MarshalIndent(comment, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			return respond.Errorf("failed to parse response: %v", err), nil
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(comment), nil
 	}
 }
 
@@ -160,29 +144,24 @@ func DeleteCommentHandler(client todoist.API) func(context.Context, mcp.CallTool
 
 		commentID, ok := args["comment_id"].(string)
 		if !ok || commentID == "" {
-			return mcp.NewToolResultError("comment_id is required"), nil
+			return respond.Error("comment_id is required"), nil
 		}
 		if err := ValidateID(commentID, "comment_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
 		path := fmt.Sprintf("/comments/%s", commentID)
 		err := client.Delete(ctx, path)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to delete comment: %v", err)), nil
+			return respond.Errorf("failed to delete comment: %v", err), nil
 		}
 
 		response := map[string]interface{}{
 			"success":    true,
 			"comment_id": commentID,
-			"message":    "Comment deleted successfully",
-		}
-
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			"message":    localize("comment_deleted"),
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
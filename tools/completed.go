@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// completedTaskID returns the ID of the task behind a completed-archive item.
+// Older archive records carry their own id and point at the task via task_id.
+func completedTaskID(item models.Task) string {
+	if item.TaskID != "" {
+		return item.TaskID
+	}
+	return item.ID
+}
+
+// resolveCompletedItem finds the task ID for a completed-archive item ID by
+// scanning completions from the last 90 days.
+func resolveCompletedItem(ctx context.Context, syncClient todoist.SyncAPI, completedItemID string) (string, error) {
+	until := time.Now()
+	items, _, err := fetchCompletedTasks(ctx, syncClient, until.Add(-maxCompletedRange), until, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to search completed tasks: %w", err)
+	}
+	for _, item := range items {
+		if item.ID == completedItemID {
+			return completedTaskID(item), nil
+		}
+	}
+	return "", fmt.Errorf("completed item %s not found among tasks completed in the last 90 days", completedItemID)
+}
+
+// SearchCompletedHandler creates a handler for searching the completed task archive.
+func SearchCompletedHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		query, _ := args["query"].(string)
+		query = strings.ToLower(strings.TrimSpace(query))
+
+		until := time.Now().UTC()
+		if v, ok := args["until"].(string); ok && v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return respond.Error("until must be in YYYY-MM-DD format"), nil
+			}
+			until = t.AddDate(0, 0, 1)
+		}
+		since := until.AddDate(0, 0, -30)
+		if v, ok := args["since"].(string); ok && v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return respond.Error("since must be in YYYY-MM-DD format"), nil
+			}
+			since = t
+		}
+		if !since.Before(until) {
+			return respond.Error("since must not be after until"), nil
+		}
+		if until.Sub(since) > maxCompletedRange {
+			return respond.Error("date range must not exceed 90 days"), nil
+		}
+
+		limit := 50
+		if l, ok := args["limit"].(float64); ok {
+			if l < 1 || l > 200 {
+				return respond.Error("limit must be between 1 and 200"), nil
+			}
+			limit = int(l)
+		}
+
+		extra := url.Values{}
+		if p, ok := args["project_id"].(string); ok && p != "" {
+			if err := ValidateID(p, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			extra.Set("project_id", p)
+		}
+
+		items, truncated, err := fetchCompletedTasks(ctx, syncClient, since, until, extra)
+		if err != nil {
+			return respond.Errorf("failed to search completed tasks: %v", err), nil
+		}
+
+		matches := make([]map[string]interface{}, 0)
+		for _, item := range items {
+			if query != "" && textMatch(query, textField{"content", item.Content}, textField{"description", item.Description}) == "" {
+				continue
+			}
+			match := map[string]interface{}{
+				"task_id":      completedTaskID(item),
+				"content":      item.Content,
+				"project_id":   item.ProjectID,
+				"completed_at": item.CompletedAt,
+			}
+			if item.ID != match["task_id"] {
+				match["completed_item_id"] = item.ID
+			}
+			if item.SectionID != "" {
+				match["section_id"] = item.SectionID
+			}
+			matches = append(matches, match)
+		}
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i]["completed_at"].(string) > matches[j]["completed_at"].(string)
+		})
+
+		total := len(matches)
+		if total > limit {
+			matches = matches[:limit]
+		}
+
+		response := respond.Envelope{
+			"since":     since.Format("2006-01-02"),
+			"until":     until.AddDate(0, 0, -1).Format("2006-01-02"),
+			"count":     len(matches),
+			"total":     total,
+			"truncated": total > limit,
+			"tasks":     matches,
+		}
+		warnCompletedTruncated(response, truncated)
+
+		return respond.JSON(response), nil
+	}
+}
+
+// GetCompletedTasksHandler creates a handler that lists completed tasks as
+// full records, one page per call, for reviews such as "what did I finish
+// last week". Pagination uses the endpoint's own cursor, so the filters of a
+// follow-up call must match the first one.
+func GetCompletedTasksHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		until := time.Now().UTC()
+		if v, ok := args["until"].(string); ok && v != "" {
+			t, err := parseDateBound(v, "until")
+			if err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			if len(v) == len("2006-01-02") {
+				// A date includes the whole day.
+				t = t.AddDate(0, 0, 1)
+			}
+			until = t
+		}
+		since := until.AddDate(0, 0, -7)
+		if v, ok := args["since"].(string); ok && v != "" {
+			t, err := parseDateBound(v, "since")
+			if err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			since = t
+		}
+		if !since.Before(until) {
+			return respond.Error("since must be before until"), nil
+		}
+		if until.Sub(since) > maxCompletedRange {
+			return respond.Error("date range must not exceed 90 days"), nil
+		}
+
+		limit := 50
+		if l, ok := args["limit"].(float64); ok {
+			if l < 1 || l > 200 {
+				return respond.Error("limit must be between 1 and 200"), nil
+			}
+			limit = int(l)
+		}
+
+		params := url.Values{}
+		params.Set("since", since.UTC().Format(time.RFC3339))
+		params.Set("until", until.UTC().Format(time.RFC3339))
+		params.Set("limit", fmt.Sprint(limit))
+		if p, ok := args["project_id"].(string); ok && p != "" {
+			if err := ValidateID(p, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			params.Set("project_id", p)
+		}
+		if cursor, ok := args["cursor"].(string); ok && cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		respBody, err := syncClient.Get(ctx, "/tasks/completed/by_completion_date?"+params.Encode())
+		if err != nil {
+			return respond.Errorf("failed to get completed tasks: %v", err), nil
+		}
+		var page struct {
+			Items      []models.Task `json:"items"`
+			NextCursor *string       `json:"next_cursor"`
+		}
+		if err := json.Unmarshal(respBody, &page); err != nil {
+			return respond.Errorf("failed to parse completed tasks: %v", err), nil
+		}
+
+		tasks := page.Items
+		if tasks == nil {
+			tasks = []models.Task{}
+		}
+		sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].CompletedAt > tasks[j].CompletedAt })
+		byProject := make(map[string]int)
+		byDay := make(map[string]int)
+		for _, task := range tasks {
+			byProject[task.ProjectID]++
+			if len(task.CompletedAt) >= len("2006-01-02") {
+				byDay[task.CompletedAt[:10]]++
+			}
+		}
+
+		response := respond.List("tasks", tasks).
+			Set("since", since.UTC().Format(time.RFC3339)).
+			Set("until", until.UTC().Format(time.RFC3339)).
+			Set("by_project", byProject).
+			Set("by_day", byDay)
+		if page.NextCursor != nil {
+			response.Paginate(*page.NextCursor)
+		}
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchCompletedHandler(t *testing.T) {
+	var lastQuery url.Values
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		u, err := url.Parse(path)
+		if err != nil || u.Path != "/tasks/completed/by_completion_date" {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		lastQuery = u.Query()
+		return json.Marshal(map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"id": "t1", "content": "Renew passport", "project_id": "p1", "completed_at": "2026-02-10T09:00:00Z"},
+				{"id": "c2", "task_id": "t2", "content": "Book flights", "description": "passport needed", "project_id": "p1", "completed_at": "2026-02-12T09:00:00Z"},
+				{"id": "t3", "content": "Buy milk", "project_id": "p2", "completed_at": "2026-02-11T09:00:00Z"},
+			},
+			"next_cursor": nil,
+		})
+	}}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantIDs   []string
+		wantSince string
+		wantErr   string
+	}{
+		{
+			name:      "query matches content and description, newest first",
+			args:      map[string]interface{}{"query": "Passport", "since": "2026-02-01", "until": "2026-02-28"},
+			wantIDs:   []string{"t2", "t1"},
+			wantSince: "2026-02-01T00:00:00Z",
+		},
+		{
+			name:    "limit",
+			args:    map[string]interface{}{"since": "2026-02-01", "until": "2026-02-28", "limit": float64(1)},
+			wantIDs: []string{"t2"},
+		},
+		{
+			name:    "range too wide",
+			args:    map[string]interface{}{"since": "2025-01-01", "until": "2026-02-28"},
+			wantErr: "must not exceed 90 days",
+		},
+		{
+			name:    "bad since",
+			args:    map[string]interface{}{"since": "last month"},
+			wantErr: "since must be",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SearchCompletedHandler(syncClient)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				Tasks []map[string]interface{} `json:"tasks"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			var ids []string
+			for _, task := range resp.Tasks {
+				ids = append(ids, task["task_id"].(string))
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("task ids = %v, want %v", ids, tt.wantIDs)
+			}
+			if resp.Tasks[0]["task_id"] == "t2" && resp.Tasks[0]["completed_item_id"] != "c2" {
+				t.Errorf("completed_item_id = %v, want c2", resp.Tasks[0]["completed_item_id"])
+			}
+			if tt.wantSince != "" && lastQuery.Get("since") != tt.wantSince {
+				t.Errorf("since = %s, want %s", lastQuery.Get("since"), tt.wantSince)
+			}
+		})
+	}
+}
+
+func TestGetCompletedTasksHandler(t *testing.T) {
+	var lastQuery url.Values
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		u, err := url.Parse(path)
+		if err != nil || u.Path != "/tasks/completed/by_completion_date" {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		lastQuery = u.Query()
+		return []byte(`{
+			"items": [
+				{"id": "t1", "content": "Renew passport", "project_id": "p1", "completed_at": "2026-02-10T09:00:00Z", "note_count": 2},
+				{"id": "t2", "content": "Book flights", "project_id": "p1", "completed_at": "2026-02-12T09:00:00Z"},
+				{"id": "t3", "content": "Buy milk", "project_id": "p2", "completed_at": "2026-02-10T18:00:00Z"}
+			],
+			"next_cursor": "page2"
+		}`), nil
+	}}
+
+	result, err := GetCompletedTasksHandler(syncClient)(context.Background(), makeReq(map[string]interface{}{
+		"since": "2026-02-09", "until": "2026-02-15", "project_id": "p1", "limit": float64(3), "cursor": "page1",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	if lastQuery.Get("since") != "2026-02-09T00:00:00Z" || lastQuery.Get("until") != "2026-02-16T00:00:00Z" ||
+		lastQuery.Get("project_id") != "p1" || lastQuery.Get("limit") != "3" || lastQuery.Get("cursor") != "page1" {
+		t.Errorf("query = %v", lastQuery)
+	}
+
+	var resp struct {
+		Count      int                      `json:"count"`
+		Tasks      []map[string]interface{} `json:"tasks"`
+		ByProject  map[string]int           `json:"by_project"`
+		ByDay      map[string]int           `json:"by_day"`
+		NextCursor string                   `json:"next_cursor"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Count != 3 || resp.Tasks[0]["id"] != "t2" || resp.Tasks[2]["id"] != "t1" {
+		t.Errorf("tasks = %v, want newest first", resp.Tasks)
+	}
+	if resp.Tasks[2]["note_count"] != float64(2) {
+		t.Errorf("full records should be kept, got %v", resp.Tasks[2])
+	}
+	if resp.ByProject["p1"] != 2 || resp.ByProject["p2"] != 1 || resp.ByDay["2026-02-10"] != 2 {
+		t.Errorf("by_project = %v, by_day = %v", resp.ByProject, resp.ByDay)
+	}
+	if resp.NextCursor != "page2" {
+		t.Errorf("next_cursor = %q", resp.NextCursor)
+	}
+}
+
+func TestGetCompletedTasksHandler_Defaults(t *testing.T) {
+	var lastQuery url.Values
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		u, _ := url.Parse(path)
+		lastQuery = u.Query()
+		return []byte(`{"items": [], "next_cursor": null}`), nil
+	}}
+
+	result, _ := GetCompletedTasksHandler(syncClient)(context.Background(), makeReq(nil))
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(result))
+	}
+	since, _ := time.Parse(time.RFC3339, lastQuery.Get("since"))
+	until, _ := time.Parse(time.RFC3339, lastQuery.Get("until"))
+	if got := until.Sub(since); got != 7*24*time.Hour {
+		t.Errorf("default range = %v, want 7 days", got)
+	}
+	if lastQuery.Get("limit") != "50" {
+		t.Errorf("default limit = %q", lastQuery.Get("limit"))
+	}
+	if text := resultText(result); !strings.Contains(text, `"tasks": []`) || strings.Contains(text, "next_cursor") {
+		t.Errorf("empty page = %s", text)
+	}
+}
+
+func TestGetCompletedTasksHandler_Validation(t *testing.T) {
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		t.Fatalf("unexpected request: %s", path)
+		return nil, nil
+	}}
+	tests := []struct {
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{map[string]interface{}{"since": "2025-01-01", "until": "2026-02-28"}, "must not exceed 90 days"},
+		{map[string]interface{}{"since": "2026-03-01", "until": "2026-02-28"}, "since must be before until"},
+		{map[string]interface{}{"since": "last week"}, "since must be"},
+		{map[string]interface{}{"limit": float64(500)}, "limit must be between"},
+		{map[string]interface{}{"project_id": "../x"}, "project_id"},
+	}
+	for _, tt := range tests {
+		result, _ := GetCompletedTasksHandler(syncClient)(context.Background(), makeReq(tt.args))
+		if !result.IsError || !strings.Contains(resultText(result), tt.wantErr) {
+			t.Errorf("args %v: got %s, want error containing %q", tt.args, resultText(result), tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+// maxCompletionValues is the protocol's limit on values per completion result.
+const maxCompletionValues = 100
+
+// filterSnippets are common Todoist filter expressions offered for filter
+// arguments.
+var filterSnippets = []string{
+	"today", "overdue", "today | overdue", "tomorrow", "next 7 days", "no date",
+	"p1", "p2", "p1 | p2", "recurring", "assigned to: me", "assigned to: others",
+	"created before: -30 days", "no labels", "subtask", "!subtask",
+}
+
+// ReferenceCache keeps projects, labels, and sections for a short time, so
+// that keystroke-driven lookups such as argument completion, and reference
+// data requested at the start of every session, do not spend the rate limit
+// budget.
+type ReferenceCache struct {
+	client todoist.API
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	lists map[string]cachedList
+}
+
+// cachedList is the response of one list endpoint and when it was fetched.
+type cachedList struct {
+	items   []map[string]interface{}
+	fetched time.Time
+}
+
+// NewReferenceCache creates a cache whose entries are refetched after ttl.
+func NewReferenceCache(client todoist.API, ttl time.Duration) *ReferenceCache {
+	return &ReferenceCache{client: client, ttl: ttl, lists: make(map[string]cachedList)}
+}
+
+// TTL returns how long entries are served before they are refetched.
+func (c *ReferenceCache) TTL() time.Duration {
+	return c.ttl
+}
+
+// list returns the entities at path, one of /projects, /labels, or
+// /sections, and whether they came from the cache. Stale entries, and all
+// entries when refresh is set, are refetched; a failed refetch keeps
+// serving the previous entries along with the error. Callers must not
+// modify the returned maps.
+func (c *ReferenceCache) list(ctx context.Context, path string, refresh bool) (items []map[string]interface{}, cached bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.lists[path]
+	if ok && !refresh && time.Since(entry.fetched) < c.ttl {
+		return entry.items, true, nil
+	}
+	respBody, err := c.client.Get(ctx, path)
+	if err == nil {
+		var fetched []map[string]interface{}
+		if err = json.Unmarshal(respBody, &fetched); err == nil {
+			c.lists[path] = cachedList{items: fetched, fetched: time.Now()}
+			return fetched, false, nil
+		}
+		err = fmt.Errorf("failed to parse %s: %w", strings.TrimPrefix(path, "/"), err)
+	}
+	return entry.items, ok, err
+}
+
+// names returns the cached project and label names, refreshing them when
+// stale. A failed refresh keeps serving the previous names.
+func (c *ReferenceCache) names(ctx context.Context) (projects, labels []string, err error) {
+	projectList, _, err := c.list(ctx, "/projects", false)
+	labelList, _, labelErr := c.list(ctx, "/labels", false)
+	if err == nil {
+		err = labelErr
+	}
+	return sortedNames(projectList), sortedNames(labelList), err
+}
+
+// sortedNames lists the "name" field of items, sorted. It returns nil for no
+// items.
+func sortedNames(items []map[string]interface{}) []string {
+	if items == nil {
+		return nil
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		if name, ok := item["name"].(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Completer suggests values for prompt and resource template arguments by
+// argument name: project names, label names, and filter expressions.
+type Completer struct {
+	cache *ReferenceCache
+}
+
+// NewCompleter creates a completer backed by cache.
+func NewCompleter(cache *ReferenceCache) *Completer {
+	return &Completer{cache: cache}
+}
+
+// Complete returns the values for argument name that match the partially
+// typed value. Arguments the completer does not know get no suggestions.
+func (c *Completer) Complete(ctx context.Context, name, value string) (*mcp.CompleteResult, error) {
+	var candidates []string
+	switch strings.ToLower(name) {
+	case "project", "project_name", "projects":
+		projects, _, err := c.cache.names(ctx)
+		if err != nil && projects == nil {
+			return nil, err
+		}
+		candidates = projects
+	case "label", "label_name", "labels":
+		_, labels, err := c.cache.names(ctx)
+		if err != nil && labels == nil {
+			return nil, err
+		}
+		candidates = labels
+	case "filter", "query":
+		var err error
+		if candidates, err = c.filterCandidates(ctx, value); err != nil {
+			return nil, err
+		}
+	}
+	return completionResult(matchCompletions(candidates, value)), nil
+}
+
+// filterCandidates offers "#Project" and "@label" terms when the value ends
+// in one, and the filter snippets otherwise.
+func (c *Completer) filterCandidates(ctx context.Context, value string) ([]string, error) {
+	// Complete the last term so that "today & #Wo" suggests "today & #Work".
+	i := strings.LastIndexAny(value, " &|(!,")
+	prefix, term := value[:i+1], value[i+1:]
+	if !strings.HasPrefix(term, "#") && !strings.HasPrefix(term, "@") {
+		return filterSnippets, nil
+	}
+
+	projects, labels, err := c.cache.names(ctx)
+	if err != nil && projects == nil {
+		return nil, err
+	}
+	names, sigil := projects, "#"
+	if strings.HasPrefix(term, "@") {
+		names, sigil = labels, "@"
+	}
+	candidates := make([]string, len(names))
+	for i, name := range names {
+		// Filter terms with spaces must be escaped with backslashes.
+		candidates[i] = prefix + sigil + strings.ReplaceAll(name, " ", `\ `)
+	}
+	return candidates, nil
+}
+
+// matchCompletions keeps the candidates that start with value, followed by
+// those that merely contain it, ignoring case.
+func matchCompletions(candidates []string, value string) []string {
+	needle := strings.ToLower(value)
+	var prefixed, contained []string
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+		switch {
+		case strings.HasPrefix(lower, needle):
+			prefixed = append(prefixed, candidate)
+		case strings.Contains(lower, needle):
+			contained = append(contained, candidate)
+		}
+	}
+	return append(prefixed, contained...)
+}
+
+// completionResult caps values at the protocol limit and reports the total.
+func completionResult(values []string) *mcp.CompleteResult {
+	result := &mcp.CompleteResult{}
+	result.Completion.Total = len(values)
+	if len(values) > maxCompletionValues {
+		values = values[:maxCompletionValues]
+		result.Completion.HasMore = true
+	}
+	if values == nil {
+		values = []string{}
+	}
+	result.Completion.Values = values
+	return result
+}
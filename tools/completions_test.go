@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func completionClient(calls *int) *MockAPI {
+	return &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		*calls++
+		switch path {
+		case "/projects":
+			return json.Marshal([]map[string]interface{}{{"name": "Work"}, {"name": "Home Renovation"}, {"name": "Side Work"}})
+		case "/labels":
+			return json.Marshal([]map[string]interface{}{{"name": "waiting"}, {"name": "urgent"}})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}}
+}
+
+func TestCompleter_Complete(t *testing.T) {
+	calls := 0
+	completer := NewCompleter(NewReferenceCache(completionClient(&calls), time.Minute))
+
+	tests := []struct {
+		name, value string
+		want        []string
+	}{
+		{"project", "wo", []string{"Work", "Side Work"}},
+		{"label", "", []string{"urgent", "waiting"}},
+		{"filter", "today & #Ho", []string{`today & #Home\ Renovation`}},
+		{"filter", "@wa", []string{"@waiting"}},
+		{"filter", "overd", []string{"overdue", "today | overdue"}},
+		{"due_string", "to", []string{}},
+	}
+	for _, tt := range tests {
+		result, err := completer.Complete(context.Background(), tt.name, tt.value)
+		if err != nil {
+			t.Fatalf("Complete(%q, %q) error: %v", tt.name, tt.value, err)
+		}
+		if !slices.Equal(result.Completion.Values, tt.want) {
+			t.Errorf("Complete(%q, %q) = %v, want %v", tt.name, tt.value, result.Completion.Values, tt.want)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("made %d API calls, want 2 (projects and labels, cached)", calls)
+	}
+}
+
+func TestCompletionResult_Limit(t *testing.T) {
+	values := make([]string, 150)
+	for i := range values {
+		values[i] = fmt.Sprint(i)
+	}
+	result := completionResult(values)
+	if len(result.Completion.Values) != maxCompletionValues || result.Completion.Total != 150 || !result.Completion.HasMore {
+		t.Errorf("result = %d values, total %d, hasMore %v", len(result.Completion.Values), result.Completion.Total, result.Completion.HasMore)
+	}
+}
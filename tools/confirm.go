@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// pendingConfirmation is a previewed bulk operation awaiting confirmation.
+type pendingConfirmation struct {
+	scope     string
+	ids       []string
+	expiresAt time.Time
+}
+
+// ConfirmationStore issues short-lived, single-use tokens for filter-based bulk
+// operations. A token is bound to the tool and arguments it was issued for and
+// carries the task IDs shown in the preview, so the mutation affects exactly
+// the tasks the caller saw even if the filter would now match others.
+// cleanup_workspace uses the same tokens for the section and project IDs in
+// its report.
+type ConfirmationStore struct {
+	mu     sync.Mutex
+	tokens map[string]pendingConfirmation
+	ttl    time.Duration
+}
+
+// NewConfirmationStore creates a confirmation store whose tokens expire after ttl.
+func NewConfirmationStore(ttl time.Duration) *ConfirmationStore {
+	return &ConfirmationStore{tokens: make(map[string]pendingConfirmation), ttl: ttl}
+}
+
+// confirmationScope identifies a tool invocation so a token cannot be reused with different arguments.
+func confirmationScope(tool string, parts ...string) string {
+	return tool + "\x00" + strings.Join(parts, "\x00")
+}
+
+// issue records the previewed IDs and returns a new token and its expiry time.
+func (cs *ConfirmationStore) issue(scope string, ids []string) (string, time.Time) {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	now := time.Now()
+	for t, pending := range cs.tokens {
+		if now.After(pending.expiresAt) {
+			delete(cs.tokens, t)
+		}
+	}
+
+	expiresAt := now.Add(cs.ttl)
+	cs.tokens[token] = pendingConfirmation{scope: scope, ids: ids, expiresAt: expiresAt}
+	return token, expiresAt
+}
+
+// redeem consumes a token and returns the IDs it was issued for.
+func (cs *ConfirmationStore) redeem(token, scope string) ([]string, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	pending, ok := cs.tokens[token]
+	if !ok || time.Now().After(pending.expiresAt) {
+		delete(cs.tokens, token)
+		return nil, errors.New("confirmation_token is invalid or expired; call again without a token to get a new preview")
+	}
+	if pending.scope != scope {
+		return nil, errors.New("confirmation_token was issued for a different operation; call again without a token to get a new preview")
+	}
+	delete(cs.tokens, token)
+	return pending.ids, nil
+}
+
+// confirmationPreviewResult issues a token for the matched tasks and builds
+// the response returned instead of performing the bulk operation.
+func confirmationPreviewResult(store *ConfirmationStore, tool, scope string, tasks []models.Task) (*mcp.CallToolResult, error) {
+	taskIDs := make([]string, 0, len(tasks))
+	preview := make([]map[string]interface{}, 0, len(tasks))
+	for _, task := range tasks {
+		if task.ID == "" {
+			continue
+		}
+		taskIDs = append(taskIDs, task.ID)
+		entry := map[string]interface{}{
+			"id":         task.ID,
+			"content":    task.Content,
+			"project_id": task.ProjectID,
+		}
+		if task.Due != nil {
+			entry["due"] = task.Due.Date
+		}
+		preview = append(preview, entry)
+	}
+
+	token, expiresAt := store.issue(scope, taskIDs)
+
+	response := map[string]interface{}{
+		"requires_confirmation": true,
+		"confirmation_token":    token,
+		"expires_at":            expiresAt.UTC().Format(time.RFC3339),
+		"task_count":            len(taskIDs),
+		"tasks":                 preview,
+		"message":               fmt.Sprintf("No changes made. Review the %d matched tasks, then call %s again with the same arguments plus confirmation_token to proceed.", len(taskIDs), tool),
+	}
+
+	return respond.JSON(response), nil
+}
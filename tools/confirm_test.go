@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestConfirmationStore(t *testing.T) {
+	store := NewConfirmationStore(time.Minute)
+	scope := confirmationScope("bulk_delete_tasks", "today")
+
+	token, _ := store.issue(scope, []string{"1", "2"})
+	if _, err := store.redeem(token, confirmationScope("bulk_delete_tasks", "tomorrow")); err == nil ||
+		!strings.Contains(err.Error(), "different operation") {
+		t.Errorf("redeem with other scope: err = %v", err)
+	}
+
+	token, _ = store.issue(scope, []string{"1", "2"})
+	ids, err := store.redeem(token, scope)
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("redeem = %v, %v", ids, err)
+	}
+	if _, err := store.redeem(token, scope); err == nil {
+		t.Error("token should be single-use")
+	}
+
+	expired := NewConfirmationStore(-time.Minute)
+	token, _ = expired.issue(scope, []string{"1"})
+	if _, err := expired.redeem(token, scope); err == nil || !strings.Contains(err.Error(), "invalid or expired") {
+		t.Errorf("expired token: err = %v", err)
+	}
+}
+
+func TestBulkDeleteTasksHandler_Confirmation(t *testing.T) {
+	filterCalls := 0
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if !strings.HasPrefix(path, "/tasks?filter=") {
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			}
+			filterCalls++
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1", "content": "Old draft", "project_id": "p1"},
+				{"id": "2", "content": "Stale note", "project_id": "p1", "due": map[string]interface{}{"date": "2026-01-01"}},
+			})
+		},
+	}
+	var sent []string
+	client.DeleteFn = func(_ context.Context, path string) error {
+		sent = append(sent, path)
+		return nil
+	}
+	syncClient := &MockSyncAPI{}
+	handler := BulkDeleteTasksHandler(client, syncClient, NewConfirmationStore(time.Minute))
+
+	result, err := handler(context.Background(), makeReq(map[string]interface{}{"filter": "@old"}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+	var preview struct {
+		RequiresConfirmation bool   `json:"requires_confirmation"`
+		ConfirmationToken    string `json:"confirmation_token"`
+		TaskCount            int    `json:"task_count"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &preview); err != nil {
+		t.Fatalf("failed to parse preview: %v", err)
+	}
+	if !preview.RequiresConfirmation || preview.ConfirmationToken == "" || preview.TaskCount != 2 {
+		t.Fatalf("preview = %+v", preview)
+	}
+	if sent != nil {
+		t.Fatal("tasks were deleted before confirmation")
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"filter": "@other", "confirmation_token": preview.ConfirmationToken}))
+	if !result.IsError || !strings.Contains(resultText(result), "different operation") {
+		t.Fatalf("token reused with another filter: %s", resultText(result))
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"filter": "@old"}))
+	if err := json.Unmarshal([]byte(resultText(result)), &preview); err != nil {
+		t.Fatalf("failed to parse preview: %v", err)
+	}
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"filter": "@old", "confirmation_token": preview.ConfirmationToken}))
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+	if strings.Join(sent, ",") != "/tasks/1,/tasks/2" {
+		t.Fatalf("deleted = %v, want the 2 previewed tasks", sent)
+	}
+	if filterCalls != 2 {
+		t.Errorf("filter fetched %d times, want 2 (confirmed call must reuse previewed IDs)", filterCalls)
+	}
+}
+
+func TestBulkDeleteTasksHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantErr   bool
+		errSubstr string
+	}{
+		{name: "explicit task_ids", args: map[string]interface{}{"task_ids": []interface{}{"1", "2"}}},
+		{name: "nothing selected", args: map[string]interface{}{}, wantErr: true, errSubstr: "either task_ids or filter"},
+		{name: "invalid ID", args: map[string]interface{}{"task_ids": []interface{}{"../1"}}, wantErr: true, errSubstr: "contains invalid characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncClient := &MockSyncAPI{
+				BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+					status := make(map[string]interface{})
+					for _, c := range commands {
+						status[c.UUID] = "ok"
+					}
+					return &todoist.SyncResponse{SyncStatus: status}, nil
+				},
+			}
+			handler := BulkDeleteTasksHandler(&MockAPI{}, syncClient, NewConfirmationStore(time.Minute))
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// contextBundleFilter selects the tasks of the Todoist Today view.
+const contextBundleFilter = "today | overdue"
+
+// GetContextBundleHandler creates a handler that returns the reference data
+// an agent needs at the start of a session in one call: projects, labels,
+// sections, and the tasks due today or overdue. Projects, labels, and
+// sections come from cache while fresh; tasks are always fetched.
+func GetContextBundleHandler(cache *ReferenceCache) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectID, _ := args["project_id"].(string)
+		if projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+		refresh, _ := args["refresh"].(bool)
+
+		response := respond.Envelope{}
+		cached := make([]string, 0, 3)
+		lists := make(map[string][]map[string]interface{}, 3)
+		for _, kind := range []string{"projects", "labels", "sections"} {
+			items, fromCache, err := cache.list(ctx, "/"+kind, refresh)
+			if err != nil {
+				if items == nil {
+					return respond.Errorf("failed to list %s: %v", kind, err), nil
+				}
+				response.Warn("%s could not be refreshed and may be out of date: %v", kind, err)
+			}
+			if fromCache {
+				cached = append(cached, kind)
+			}
+			lists[kind] = items
+		}
+
+		// Cached entries are shared, so they are copied before changing them.
+		projects := make([]map[string]interface{}, 0, len(lists["projects"]))
+		for _, project := range lists["projects"] {
+			project = maps.Clone(project)
+			normalizeProject(project)
+			projects = append(projects, project)
+		}
+		sections := make([]map[string]interface{}, 0, len(lists["sections"]))
+		for _, section := range lists["sections"] {
+			if projectID == "" || fmt.Sprint(section["project_id"]) == projectID {
+				sections = append(sections, section)
+			}
+		}
+
+		today, err := resolveFilterTasks(ctx, cache.client, contextBundleFilter)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		for i, task := range today {
+			today[i].Set("is_timed", task.IsTimed())
+		}
+
+		response.Set("projects", projects).
+			Set("labels", lists["labels"]).
+			Set("sections", sections).
+			Set("today", today).
+			Set("counts", map[string]int{
+				"projects": len(projects),
+				"labels":   len(lists["labels"]),
+				"sections": len(sections),
+				"today":    len(today),
+			}).
+			Set("cached", cached).
+			Set("cache_ttl_seconds", int(cache.TTL().Seconds()))
+		if projectID != "" {
+			response.Set("sections_project_id", projectID)
+		}
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func bundleClient(calls map[string]int, fail *bool) *MockAPI {
+	return &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		endpoint, query, _ := strings.Cut(path, "?")
+		calls[endpoint]++
+		if *fail && endpoint != "/tasks" {
+			return nil, errors.New("service unavailable")
+		}
+		switch endpoint {
+		case "/projects":
+			return json.Marshal([]map[string]interface{}{{"id": "1", "name": "Inbox"}, {"id": "2", "name": "Work"}})
+		case "/labels":
+			return json.Marshal([]map[string]interface{}{{"id": "10", "name": "waiting"}})
+		case "/sections":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "20", "project_id": "2", "name": "Doing"},
+				{"id": "21", "project_id": "1", "name": "Someday"},
+			})
+		case "/tasks":
+			values, _ := url.ParseQuery(query)
+			if values.Get("filter") != contextBundleFilter {
+				return nil, fmt.Errorf("unexpected filter %q", values.Get("filter"))
+			}
+			return json.Marshal([]map[string]interface{}{{"id": "100", "content": "Ship it", "due": map[string]interface{}{"date": "2026-10-16"}}})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}}
+}
+
+type bundleResponse struct {
+	Projects []map[string]interface{} `json:"projects"`
+	Labels   []map[string]interface{} `json:"labels"`
+	Sections []map[string]interface{} `json:"sections"`
+	Today    []map[string]interface{} `json:"today"`
+	Counts   map[string]int           `json:"counts"`
+	Cached   []string                 `json:"cached"`
+	TTL      int                      `json:"cache_ttl_seconds"`
+	Warnings []string                 `json:"warnings"`
+}
+
+func callBundle(t *testing.T, cache *ReferenceCache, args map[string]interface{}) bundleResponse {
+	t.Helper()
+	result, err := GetContextBundleHandler(cache)(context.Background(), makeReq(args))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	var resp bundleResponse
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	return resp
+}
+
+func TestGetContextBundleHandler(t *testing.T) {
+	calls := make(map[string]int)
+	fail := false
+	cache := NewReferenceCache(bundleClient(calls, &fail), time.Minute)
+
+	resp := callBundle(t, cache, nil)
+	if resp.Counts["projects"] != 2 || resp.Counts["labels"] != 1 || resp.Counts["sections"] != 2 || resp.Counts["today"] != 1 {
+		t.Errorf("counts = %v", resp.Counts)
+	}
+	if resp.Projects[0]["description"] != "" {
+		t.Errorf("projects should be normalized, got %v", resp.Projects[0])
+	}
+	if resp.Today[0]["is_timed"] != false {
+		t.Errorf("today = %v, want is_timed set", resp.Today)
+	}
+	if len(resp.Cached) != 0 || resp.TTL != 60 {
+		t.Errorf("first call cached = %v, ttl = %d", resp.Cached, resp.TTL)
+	}
+
+	// The second call serves reference data from the cache, scoped sections
+	// included, and only fetches today's tasks.
+	resp = callBundle(t, cache, map[string]interface{}{"project_id": "2"})
+	if !slices.Equal(resp.Cached, []string{"projects", "labels", "sections"}) {
+		t.Errorf("second call cached = %v", resp.Cached)
+	}
+	if len(resp.Sections) != 1 || resp.Sections[0]["name"] != "Doing" {
+		t.Errorf("scoped sections = %v", resp.Sections)
+	}
+	if calls["/projects"] != 1 || calls["/labels"] != 1 || calls["/sections"] != 1 || calls["/tasks"] != 2 {
+		t.Errorf("calls = %v", calls)
+	}
+
+	resp = callBundle(t, cache, map[string]interface{}{"refresh": true})
+	if len(resp.Cached) != 0 || calls["/projects"] != 2 {
+		t.Errorf("refresh should refetch, cached = %v, calls = %v", resp.Cached, calls)
+	}
+}
+
+func TestGetContextBundleHandler_StaleOnError(t *testing.T) {
+	calls := make(map[string]int)
+	fail := true
+	cache := NewReferenceCache(bundleClient(calls, &fail), time.Minute)
+
+	result, _ := GetContextBundleHandler(cache)(context.Background(), makeReq(nil))
+	if !result.IsError || !strings.Contains(resultText(result), "failed to list projects") {
+		t.Errorf("expected an error without cached data, got %s", resultText(result))
+	}
+
+	fail = false
+	callBundle(t, cache, nil)
+	fail = true
+	resp := callBundle(t, cache, map[string]interface{}{"refresh": true})
+	if resp.Counts["projects"] != 2 || len(resp.Warnings) != 3 {
+		t.Errorf("expected stale data with warnings, got counts %v, warnings %v", resp.Counts, resp.Warnings)
+	}
+}
+
+func TestGetContextBundleHandler_InvalidProjectID(t *testing.T) {
+	calls := make(map[string]int)
+	fail := false
+	cache := NewReferenceCache(bundleClient(calls, &fail), time.Minute)
+	result, _ := GetContextBundleHandler(cache)(context.Background(), makeReq(map[string]interface{}{"project_id": "../x"}))
+	if !result.IsError {
+		t.Errorf("expected invalid project_id to be rejected, got %s", resultText(result))
+	}
+	if len(calls) != 0 {
+		t.Errorf("made API calls for an invalid request: %v", calls)
+	}
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// costOperationForms maps estimate_operation_cost's operation names to whether
+// the change has a REST form and a Sync form, mirroring the bulk tools.
+var costOperationForms = map[string]struct{ rest, sync bool }{
+	"complete": {rest: true, sync: true},
+	"delete":   {rest: true, sync: true},
+	"update":   {rest: true, sync: true},
+	"move":     {rest: false, sync: true},
+	"create":   {rest: false, sync: true},
+}
+
+// estimateCostRequested reports whether a bulk tool was called with
+// estimate_cost, asking for the request cost instead of running the change.
+func estimateCostRequested(args map[string]interface{}) bool {
+	estimate, _ := args["estimate_cost"].(bool)
+	return estimate
+}
+
+// bulkStrategyArg returns ctx carrying a bulk tool's strategy argument, so
+// that the change goes through the REST or Sync API as asked instead of the
+// executor choosing by batch size. An absent strategy leaves the choice to
+// the executor.
+func bulkStrategyArg(ctx context.Context, args map[string]interface{}) (context.Context, error) {
+	strategy, _ := args["strategy"].(string)
+	if strategy == "" {
+		return ctx, nil
+	}
+	if !slices.Contains(todoist.Strategies, strategy) {
+		return ctx, fmt.Errorf("strategy must be one of %s", strings.Join(todoist.Strategies, ", "))
+	}
+	return todoist.WithStrategy(ctx, strategy), nil
+}
+
+// overBudgetMessage explains an estimate that does not fit the remaining budget.
+func overBudgetMessage(estimate todoist.CostEstimate) string {
+	return fmt.Sprintf("Not enough rate limit budget: %s. Split the job or wait for the 15-minute window to free capacity", estimate.Message)
+}
+
+// bulkCostResult reports what executing ops would cost without sending them.
+func bulkCostResult(ctx context.Context, client todoist.API, syncClient todoist.SyncAPI, ops []todoist.BulkOperation) (*mcp.CallToolResult, error) {
+	estimate := todoist.NewBulkExecutor(client, syncClient).Estimate(ctx, ops)
+
+	response := map[string]interface{}{
+		"estimate_only": true,
+		"total_tasks":   len(ops),
+		"cost":          estimate,
+	}
+	if estimate.WithinBudget {
+		response["message"] = fmt.Sprintf("Would use %d REST and %d Sync request(s); nothing was changed", estimate.RESTRequests, estimate.SyncRequests)
+	} else {
+		response["message"] = overBudgetMessage(estimate)
+	}
+
+	return respond.JSON(response), nil
+}
+
+// EstimateOperationCostHandler creates a handler that predicts how many REST
+// and Sync requests a bulk change would consume against the remaining budget.
+func EstimateOperationCostHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		ctx, err := bulkStrategyArg(ctx, args)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		operation, _ := args["operation"].(string)
+		forms, ok := costOperationForms[operation]
+		if !ok {
+			return respond.Error("operation must be one of complete, delete, update, move, create"), nil
+		}
+
+		count := 0
+		if c, ok := args["count"].(float64); ok {
+			if c < 1 || c != float64(int(c)) {
+				return respond.Error("count must be a positive whole number"), nil
+			}
+			count = int(c)
+		} else if ids := taskIDsArg(args); len(ids) > 0 {
+			count = len(ids)
+		} else if filter, _ := args["filter"].(string); filter != "" {
+			tasks, err := resolveFilterTasks(ctx, client, filter)
+			if err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			count = len(tasks)
+		} else {
+			return respond.Error("one of count, task_ids, or filter is required"), nil
+		}
+
+		// Placeholder operations carry the same REST/Sync forms as the real
+		// change, which is all the executor's strategy choice depends on.
+		ops := make([]todoist.BulkOperation, count)
+		for i := range ops {
+			if forms.sync {
+				ops[i].Command = todoist.Command{Type: operation}
+			}
+			if forms.rest {
+				ops[i].Method = http.MethodPost
+				ops[i].Path = fmt.Sprintf("/tasks/%d", i)
+			}
+		}
+		estimate := todoist.NewBulkExecutor(client, syncClient).Estimate(ctx, ops)
+
+		response := map[string]interface{}{
+			"operation": operation,
+			"cost":      estimate,
+		}
+		if !estimate.WithinBudget {
+			response["message"] = overBudgetMessage(estimate)
+		}
+
+		return respond.JSON(response), nil
+	}
+}
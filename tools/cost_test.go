@@ -0,0 +1,185 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestEstimateOperationCostHandler(t *testing.T) {
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if !strings.HasPrefix(path, "/tasks?filter=") {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return json.Marshal([]map[string]interface{}{{"id": "1"}, {"id": "2"}})
+	}}
+
+	tests := []struct {
+		name          string
+		args          map[string]interface{}
+		syncRemaining int
+		want          todoist.CostEstimate
+		wantErr       string
+	}{
+		{
+			name: "small completion over REST",
+			args: map[string]interface{}{"operation": "complete", "count": float64(4)},
+			want: todoist.CostEstimate{Operations: 4, Strategy: todoist.StrategyREST, RESTRequests: 4, RESTRemaining: 450, SyncRemaining: 450, WithinBudget: true},
+		},
+		{
+			name: "large update in Sync chunks",
+			args: map[string]interface{}{"operation": "update", "count": float64(230)},
+			want: todoist.CostEstimate{Operations: 230, Strategy: todoist.StrategySync, SyncRequests: 3, RESTRemaining: 450, SyncRemaining: 450, WithinBudget: true},
+		},
+		{
+			name: "moves are Sync-only",
+			args: map[string]interface{}{"operation": "move", "task_ids": []interface{}{"1", "2"}},
+			want: todoist.CostEstimate{Operations: 2, Strategy: todoist.StrategySync, SyncRequests: 1, RESTRemaining: 450, SyncRemaining: 450, WithinBudget: true},
+		},
+		{
+			name: "filter is resolved to a count",
+			args: map[string]interface{}{"operation": "delete", "filter": "overdue"},
+			want: todoist.CostEstimate{Operations: 2, Strategy: todoist.StrategyREST, RESTRequests: 2, RESTRemaining: 450, SyncRemaining: 450, WithinBudget: true},
+		},
+		{
+			name:          "over budget",
+			args:          map[string]interface{}{"operation": "move", "count": float64(150)},
+			syncRemaining: 1,
+			want: todoist.CostEstimate{Operations: 150, SyncRequests: 2, RESTRemaining: 450, SyncRemaining: 1,
+				Message: "insufficient rate limit capacity: need 2 requests, have 1 remaining in 15min window"},
+		},
+		{
+			name: "forced Sync for a small batch",
+			args: map[string]interface{}{"operation": "complete", "count": float64(4), "strategy": "sync"},
+			want: todoist.CostEstimate{Operations: 4, Strategy: todoist.StrategySync, SyncRequests: 1, RESTRemaining: 450, SyncRemaining: 450, WithinBudget: true},
+		},
+		{
+			name: "moves cannot be forced over REST",
+			args: map[string]interface{}{"operation": "move", "count": float64(2), "strategy": "rest"},
+			want: todoist.CostEstimate{Operations: 2, SyncRequests: 1, RESTRemaining: 450, SyncRemaining: 450,
+				Message: "strategy rest is not available: some of these changes can only be made through the Sync API"},
+		},
+		{
+			name:    "unknown strategy",
+			args:    map[string]interface{}{"operation": "complete", "count": float64(1), "strategy": "fast"},
+			wantErr: "strategy must be one of auto, rest, sync",
+		},
+		{
+			name:    "unknown operation",
+			args:    map[string]interface{}{"operation": "archive", "count": float64(1)},
+			wantErr: "operation must be one of",
+		},
+		{
+			name:    "nothing to count",
+			args:    map[string]interface{}{"operation": "complete"},
+			wantErr: "one of count, task_ids, or filter is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncClient := &MockSyncAPI{}
+			if tt.syncRemaining > 0 {
+				syncClient.GetRemainingRequestsFn = func() int { return tt.syncRemaining }
+			}
+			result, err := EstimateOperationCostHandler(client, syncClient)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				Cost todoist.CostEstimate `json:"cost"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if resp.Cost != tt.want {
+				t.Errorf("cost = %+v, want %+v", resp.Cost, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkCompleteTasksHandler_EstimateCost(t *testing.T) {
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			return json.Marshal([]map[string]interface{}{{"id": "1"}, {"id": "2"}, {"id": "3"}})
+		},
+		PostFn: func(_ context.Context, path string, _ interface{}) ([]byte, error) {
+			return nil, fmt.Errorf("estimate_cost must not send %s", path)
+		},
+	}
+	syncClient := &MockSyncAPI{}
+	confirmations := NewConfirmationStore(time.Minute)
+
+	result, err := BulkCompleteTasksHandler(client, syncClient, confirmations)(context.Background(), makeReq(map[string]interface{}{
+		"filter":        "overdue",
+		"estimate_cost": true,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	var resp struct {
+		EstimateOnly bool                 `json:"estimate_only"`
+		TotalTasks   int                  `json:"total_tasks"`
+		Cost         todoist.CostEstimate `json:"cost"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !resp.EstimateOnly || resp.TotalTasks != 3 || resp.Cost.RESTRequests != 3 {
+		t.Errorf("response = %+v, want an estimate of 3 REST requests", resp)
+	}
+	if len(confirmations.tokens) != 0 {
+		t.Error("estimate_cost must not issue a confirmation token")
+	}
+}
+
+func TestBulkCompleteTasksHandler_Strategy(t *testing.T) {
+	client := &MockAPI{PostFn: func(_ context.Context, path string, _ interface{}) ([]byte, error) {
+		return nil, fmt.Errorf("strategy sync must not send %s", path)
+	}}
+	var batches int
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		batches++
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+	handler := BulkCompleteTasksHandler(client, syncClient, NewConfirmationStore(time.Minute))
+
+	result, err := handler(context.Background(), makeReq(map[string]interface{}{
+		"task_ids": []interface{}{"1", "2"},
+		"strategy": "sync",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	if batches != 1 {
+		t.Errorf("Sync batches = %d, want 1", batches)
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{
+		"task_ids": []interface{}{"1"},
+		"strategy": "batch",
+	}))
+	if !result.IsError || !strings.Contains(resultText(result), "strategy must be one of") {
+		t.Errorf("expected a strategy error, got %s", resultText(result))
+	}
+}
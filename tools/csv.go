@@ -0,0 +1,370 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// csvTemplateColumns is the column layout of Todoist's CSV template format as
+// produced by the web app's "Export as template" feature.
+var csvTemplateColumns = []string{
+	"TYPE", "CONTENT", "DESCRIPTION", "PRIORITY", "INDENT", "AUTHOR",
+	"RESPONSIBLE", "DATE", "DATE_LANG", "TIMEZONE", "DURATION", "DURATION_UNIT",
+}
+
+// csvPriority converts between API priorities (4 = urgent) and CSV template
+// priorities (1 = urgent), which use the numbering shown in the Todoist UI.
+// The mapping is its own inverse.
+func csvPriority(p int) int {
+	if p < 1 || p > 4 {
+		return 4
+	}
+	return 5 - p
+}
+
+// splitCSVContent separates @label tokens from task content, matching how
+// Todoist reads labels from the CONTENT column on import.
+func splitCSVContent(content string) (string, []string) {
+	var words, labels []string
+	for _, word := range strings.Fields(content) {
+		if len(word) > 1 && strings.HasPrefix(word, "@") {
+			labels = append(labels, word[1:])
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " "), labels
+}
+
+// ExportProjectHandler creates a handler for exporting a project in Todoist's CSV template format.
+func ExportProjectHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectID, ok := args["project_id"].(string)
+		if !ok || projectID == "" {
+			return respond.Error("project_id is required"), nil
+		}
+		if err := ValidateID(projectID, "project_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		projectBody, err := client.Get(ctx, fmt.Sprintf("/projects/%s", projectID))
+		if err != nil {
+			return respond.Errorf("failed to get project: %v", err), nil
+		}
+		var project models.Project
+		if err := json.Unmarshal(projectBody, &project); err != nil {
+			return respond.Errorf("failed to parse project: %v", err), nil
+		}
+
+		params := url.Values{}
+		params.Set("project_id", projectID)
+
+		sectionsBody, err := client.Get(ctx, "/sections?"+params.Encode())
+		if err != nil {
+			return respond.Errorf("failed to fetch sections: %v", err), nil
+		}
+		var sections []models.Section
+		if err := json.Unmarshal(sectionsBody, &sections); err != nil {
+			return respond.Errorf("failed to parse sections: %v", err), nil
+		}
+
+		tasksBody, err := client.Get(ctx, "/tasks?"+params.Encode())
+		if err != nil {
+			return respond.Errorf("failed to fetch tasks: %v", err), nil
+		}
+		var tasks []models.Task
+		if err := json.Unmarshal(tasksBody, &tasks); err != nil {
+			return respond.Errorf("failed to parse tasks: %v", err), nil
+		}
+
+		sort.SliceStable(sections, func(i, j int) bool { return sections[i].Order < sections[j].Order })
+		sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Order < tasks[j].Order })
+
+		children := make(map[string][]models.Task)
+		for _, task := range tasks {
+			parentID := task.ParentID
+			if parentID != "" && !taskInList(tasks, parentID) {
+				parentID = ""
+			}
+			if parentID != "" {
+				children[parentID] = append(children[parentID], task)
+			}
+		}
+
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		_ = w.Write(csvTemplateColumns)
+
+		taskCount := 0
+		var writeTask func(task models.Task, indent int)
+		writeTask = func(task models.Task, indent int) {
+			content := task.Content
+			for _, l := range task.Labels {
+				content += " @" + l
+			}
+
+			var date, dateLang, timezone string
+			if due := task.Due; due != nil {
+				date = due.String
+				if date == "" {
+					date = due.Date
+				}
+				dateLang, timezone = due.Lang, due.Timezone
+			}
+			var duration, durationUnit string
+			if d := task.Duration; d != nil {
+				duration, durationUnit = strconv.Itoa(d.Amount), d.Unit
+			}
+
+			_ = w.Write([]string{
+				"task", content, task.Description, strconv.Itoa(csvPriority(task.Priority)), strconv.Itoa(indent),
+				"", "", date, dateLang, timezone, duration, durationUnit,
+			})
+			taskCount++
+			for _, child := range children[task.ID] {
+				writeTask(child, indent+1)
+			}
+		}
+
+		writeTopLevel := func(sectionID string) {
+			for _, task := range tasks {
+				if task.SectionID == sectionID && (task.ParentID == "" || !taskInList(tasks, task.ParentID)) {
+					writeTask(task, 1)
+				}
+			}
+		}
+
+		writeTopLevel("")
+		for _, section := range sections {
+			_ = w.Write([]string{"section", section.Name, "", "", "", "", "", "", "", "", "", ""})
+			writeTopLevel(section.ID)
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return respond.Errorf("failed to write CSV: %v", err), nil
+		}
+
+		response := map[string]interface{}{
+			"project_id":    projectID,
+			"project_name":  project.Name,
+			"section_count": len(sections),
+			"task_count":    taskCount,
+			"csv":           buf.String(),
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// ImportTasksHandler creates a handler for importing sections, tasks, and notes from a Todoist CSV template.
+func ImportTasksHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectID, ok := args["project_id"].(string)
+		if !ok || projectID == "" {
+			return respond.Error("project_id is required"), nil
+		}
+		if err := ValidateID(projectID, "project_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		data, ok := args["csv"].(string)
+		if !ok || strings.TrimSpace(data) == "" {
+			return respond.Error("csv is required"), nil
+		}
+
+		r := csv.NewReader(strings.NewReader(data))
+		r.FieldsPerRecord = -1
+		header, err := r.Read()
+		if err != nil {
+			return respond.Errorf("failed to read CSV header: %v", err), nil
+		}
+		columns := make(map[string]int, len(header))
+		for i, name := range header {
+			columns[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+		}
+		if _, ok := columns["TYPE"]; !ok {
+			return respond.Error("CSV must have a TYPE column (Todoist template format)"), nil
+		}
+		if _, ok := columns["CONTENT"]; !ok {
+			return respond.Error("CSV must have a CONTENT column (Todoist template format)"), nil
+		}
+		field := func(record []string, name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		var commands []todoist.Command
+		sectionRef := ""
+		// parents[i] is the temp ID of the most recent task at indent i+1.
+		var parents []string
+		lastTask := ""
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return respond.Errorf("failed to parse CSV: %v", err), nil
+			}
+			line, _ := r.FieldPos(0)
+
+			rowType := strings.ToLower(field(record, "TYPE"))
+			content := field(record, "CONTENT")
+			switch rowType {
+			case "":
+				continue
+			case "meta":
+				// Project-level settings such as view_style are not applied.
+				continue
+			case "section":
+				if content == "" {
+					return respond.Errorf("line %d: section CONTENT is empty", line), nil
+				}
+				sectionRef = todoist.GenerateTempID()
+				parents = nil
+				lastTask = ""
+				commands = append(commands, todoist.Command{
+					Type:   "section_add",
+					UUID:   todoist.GenerateUUID(),
+					TempID: sectionRef,
+					Args:   map[string]interface{}{"name": content, "project_id": projectID},
+				})
+			case "task":
+				text, labels := splitCSVContent(content)
+				if text == "" {
+					return respond.Errorf("line %d: task CONTENT is empty", line), nil
+				}
+				indent := 1
+				if v := field(record, "INDENT"); v != "" {
+					if indent, err = strconv.Atoi(v); err != nil || indent < 1 {
+						return respond.Errorf("line %d: INDENT must be a positive integer", line), nil
+					}
+				}
+				if indent > len(parents)+1 {
+					return respond.Errorf("line %d: INDENT %d has no parent task at indent %d", line, indent, indent-1), nil
+				}
+
+				tempID := todoist.GenerateTempID()
+				cmdArgs := map[string]interface{}{"content": text, "project_id": projectID}
+				if sectionRef != "" {
+					cmdArgs["section_id"] = sectionRef
+				}
+				if indent > 1 {
+					cmdArgs["parent_id"] = parents[indent-2]
+				}
+				if len(labels) > 0 {
+					cmdArgs["labels"] = labels
+				}
+				if description := field(record, "DESCRIPTION"); description != "" {
+					cmdArgs["description"] = description
+				}
+				if v := field(record, "PRIORITY"); v != "" {
+					p, err := strconv.Atoi(v)
+					if err != nil || p < 1 || p > 4 {
+						return respond.Errorf("line %d: PRIORITY must be between 1 and 4", line), nil
+					}
+					cmdArgs["priority"] = csvPriority(p)
+				}
+				if date := field(record, "DATE"); date != "" {
+					due := map[string]interface{}{"string": date}
+					if lang := field(record, "DATE_LANG"); lang != "" {
+						due["lang"] = lang
+					}
+					if tz := field(record, "TIMEZONE"); tz != "" {
+						due["timezone"] = tz
+					}
+					cmdArgs["due"] = due
+				}
+				if v := field(record, "DURATION"); v != "" {
+					amount, err := strconv.Atoi(v)
+					if err != nil || amount < 1 {
+						return respond.Errorf("line %d: DURATION must be a positive integer", line), nil
+					}
+					unit := field(record, "DURATION_UNIT")
+					if unit == "" {
+						unit = "minute"
+					}
+					cmdArgs["duration"] = map[string]interface{}{"amount": amount, "unit": unit}
+				}
+
+				parents = append(parents[:indent-1], tempID)
+				lastTask = tempID
+				commands = append(commands, todoist.Command{
+					Type:   "item_add",
+					UUID:   todoist.GenerateUUID(),
+					TempID: tempID,
+					Args:   cmdArgs,
+				})
+			case "note":
+				if lastTask == "" {
+					return respond.Errorf("line %d: note must follow a task", line), nil
+				}
+				if content == "" {
+					continue
+				}
+				commands = append(commands, todoist.Command{
+					Type:   "note_add",
+					UUID:   todoist.GenerateUUID(),
+					TempID: todoist.GenerateTempID(),
+					Args:   map[string]interface{}{"item_id": lastTask, "content": content},
+				})
+			default:
+				return respond.Errorf("line %d: unknown TYPE %q (expected task, section, note, or meta)", line, rowType), nil
+			}
+		}
+
+		if len(commands) == 0 {
+			return respond.Error("CSV contains no tasks, sections, or notes to import"), nil
+		}
+		if len(commands) > maxPlanCommands {
+			return respond.Errorf("CSV needs %d commands, more than the %d allowed in one batch; split the file", len(commands), maxPlanCommands), nil
+		}
+
+		syncResp, err := syncClient.BatchCommands(ctx, commands)
+		if err != nil {
+			return respond.Errorf("failed to import tasks: %v", err), nil
+		}
+
+		counts := map[string]int{"section_add": 0, "item_add": 0, "note_add": 0}
+		failed := make([]map[string]interface{}, 0)
+		for _, cmd := range commands {
+			if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+				counts[cmd.Type]++
+			} else {
+				failed = append(failed, map[string]interface{}{
+					"type":  cmd.Type,
+					"name":  firstNonNil(cmd.Args["content"], cmd.Args["name"]),
+					"error": fmt.Sprintf("%v", syncResp.SyncStatus[cmd.UUID]),
+				})
+			}
+		}
+
+		response := map[string]interface{}{
+			"project_id":       projectID,
+			"sections_created": counts["section_add"],
+			"tasks_created":    counts["item_add"],
+			"notes_created":    counts["note_add"],
+			"failed":           failed,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestExportProjectHandler(t *testing.T) {
+	mockGet := func(_ context.Context, path string) ([]byte, error) {
+		switch {
+		case path == "/projects/p1":
+			return json.Marshal(map[string]interface{}{"id": "p1", "name": "Launch"})
+		case strings.HasPrefix(path, "/sections?"):
+			return json.Marshal([]map[string]interface{}{{"id": "s1", "name": "Release", "order": 1}})
+		case strings.HasPrefix(path, "/tasks?"):
+			return json.Marshal([]map[string]interface{}{
+				{"id": "t1", "content": "Plan", "priority": 4, "labels": []string{"work"}, "order": 1,
+					"due": map[string]interface{}{"date": "2026-03-02", "string": "every monday", "lang": "en"}},
+				{"id": "t2", "content": "Draft", "parent_id": "t1", "priority": 1, "order": 2},
+				{"id": "t3", "content": "Ship", "section_id": "s1", "priority": 1, "order": 3,
+					"duration": map[string]interface{}{"amount": 30, "unit": "minute"}},
+			})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}
+
+	result, err := ExportProjectHandler(&MockAPI{GetFn: mockGet})(context.Background(), makeReq(map[string]interface{}{"project_id": "p1"}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+	var resp struct {
+		TaskCount int    `json:"task_count"`
+		CSV       string `json:"csv"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(resp.CSV)).ReadAll()
+	if err != nil {
+		t.Fatalf("exported CSV does not parse: %v", err)
+	}
+
+	want := [][]string{
+		csvTemplateColumns,
+		{"task", "Plan @work", "", "1", "1", "", "", "every monday", "en", "", "", ""},
+		{"task", "Draft", "", "4", "2", "", "", "", "", "", "", ""},
+		{"section", "Release", "", "", "", "", "", "", "", "", "", ""},
+		{"task", "Ship", "", "4", "1", "", "", "", "", "", "30", "minute"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d rows, want %d:\n%s", len(records), len(want), resp.CSV)
+	}
+	for i := range want {
+		if strings.Join(records[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("row %d = %v, want %v", i, records[i], want[i])
+		}
+	}
+	if resp.TaskCount != 3 {
+		t.Errorf("task_count = %d, want 3", resp.TaskCount)
+	}
+}
+
+func TestImportTasksHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		csv       string
+		wantErr   bool
+		errSubstr string
+		wantTypes []string
+	}{
+		{
+			name: "template with sections, subtasks, and notes",
+			csv: "TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n" +
+				"meta,view_style=board,,,,,,,,\n" +
+				"task,Plan @work,Outline,1,1,,,tomorrow,en,\n" +
+				"\n" +
+				"task,Draft,,4,2,,,,,\n" +
+				"note,Remember the budget,,,,,,,,\n" +
+				"section,Release,,,,,,,,\n" +
+				"task,Ship,,4,1,,,,,\n",
+			wantTypes: []string{"item_add", "item_add", "note_add", "section_add", "item_add"},
+		},
+		{
+			name:      "missing TYPE column",
+			csv:       "CONTENT\nBuy milk\n",
+			wantErr:   true,
+			errSubstr: "TYPE column",
+		},
+		{
+			name:      "indent without parent",
+			csv:       "TYPE,CONTENT,INDENT\ntask,Orphan,2\n",
+			wantErr:   true,
+			errSubstr: "line 2: INDENT 2 has no parent",
+		},
+		{
+			name:      "unknown type",
+			csv:       "TYPE,CONTENT\nlist,Groceries\n",
+			wantErr:   true,
+			errSubstr: "unknown TYPE",
+		},
+		{
+			name:      "empty",
+			csv:       "TYPE,CONTENT\n",
+			wantErr:   true,
+			errSubstr: "no tasks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []todoist.Command
+			syncClient := &MockSyncAPI{
+				BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+					sent = commands
+					status := make(map[string]interface{})
+					for _, cmd := range commands {
+						status[cmd.UUID] = "ok"
+					}
+					return &todoist.SyncResponse{SyncStatus: status}, nil
+				},
+			}
+
+			result, err := ImportTasksHandler(syncClient)(context.Background(), makeReq(map[string]interface{}{"project_id": "p1", "csv": tt.csv}))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if len(sent) != len(tt.wantTypes) {
+				t.Fatalf("sent %d commands, want %d", len(sent), len(tt.wantTypes))
+			}
+			for i, want := range tt.wantTypes {
+				if sent[i].Type != want {
+					t.Errorf("commands[%d] = %s, want %s", i, sent[i].Type, want)
+				}
+			}
+
+			plan, draft, note, section, ship := sent[0], sent[1], sent[2], sent[3], sent[4]
+			if plan.Args["content"] != "Plan" || plan.Args["priority"] != 4 {
+				t.Errorf("plan args = %v, want content Plan and priority 4", plan.Args)
+			}
+			if labels := plan.Args["labels"].([]string); len(labels) != 1 || labels[0] != "work" {
+				t.Errorf("plan labels = %v, want [work]", labels)
+			}
+			if draft.Args["parent_id"] != plan.TempID || draft.Args["priority"] != 1 {
+				t.Errorf("draft args = %v, want parent %s and priority 1", draft.Args, plan.TempID)
+			}
+			if note.Args["item_id"] != draft.TempID {
+				t.Errorf("note item_id = %v, want %s", note.Args["item_id"], draft.TempID)
+			}
+			if ship.Args["section_id"] != section.TempID {
+				t.Errorf("ship section_id = %v, want %s", ship.Args["section_id"], section.TempID)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// deferSystem is the reference footer entry holding a task's defer date, e.g.
+// "ref:defer=2026-11-02". Until then the task is not available to work on.
+const deferSystem = "defer"
+
+// taskDeferDate returns a task's defer date as YYYY-MM-DD, or "" when it has
+// none or the footer entry is not a date.
+func taskDeferDate(task models.Task) string {
+	_, refs := parseReferences(task.Description)
+	date := refs[deferSystem]
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return ""
+	}
+	return date
+}
+
+// SetDeferDateHandler creates a handler for setting or clearing a task's
+// defer date.
+func SetDeferDateHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		taskID, ok := args["task_id"].(string)
+		if !ok || taskID == "" {
+			return respond.Error("task_id is required"), nil
+		}
+		if err := ValidateID(taskID, "task_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		date, _ := args["date"].(string)
+		date = strings.TrimSpace(date)
+		if date != "" {
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				return respond.Error("date must be a YYYY-MM-DD date, or empty to clear the defer date"), nil
+			}
+		}
+
+		path := fmt.Sprintf("/tasks/%s", taskID)
+		respBody, err := client.Get(ctx, path)
+		if err != nil {
+			return respond.Errorf("failed to get task: %v", err), nil
+		}
+		var task models.Task
+		if err := json.Unmarshal(respBody, &task); err != nil {
+			return respond.Errorf("failed to parse task: %v", err), nil
+		}
+
+		description := task.Description
+		body, refs := parseReferences(description)
+		if date == "" {
+			delete(refs, deferSystem)
+		} else {
+			refs[deferSystem] = date
+		}
+		if updated := formatReferences(body, refs); updated != description {
+			if _, err := client.Post(ctx, path, map[string]interface{}{"description": updated}); err != nil {
+				return respond.Errorf("failed to update task: %v", err), nil
+			}
+		}
+
+		response := respond.Envelope{"task_id": taskID}
+		if date == "" {
+			response["defer_until"] = nil
+			response["message"] = "Cleared defer date"
+		} else {
+			response["defer_until"] = date
+			response["message"] = fmt.Sprintf("Deferred until %s", date)
+			if due := task.Due.Day(); due != "" && due < date {
+				response.Warn("task is due %s, before its defer date", due)
+			}
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+)
+
+func TestTaskDeferDate(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"", ""},
+		{"Notes\n\nref:defer=2026-11-02\nref:jira=PROJ-1", "2026-11-02"},
+		{"ref:defer=next week", ""},
+		{"ref:defer=2026-11-02\n\nNot a footer", ""},
+	}
+	for _, tt := range tests {
+		if got := taskDeferDate(models.Task{Description: tt.description}); got != tt.want {
+			t.Errorf("taskDeferDate(%q) = %q, want %q", tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestSetDeferDateHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		wantErr     string
+		wantDesc    string
+		wantWarning bool
+	}{
+		{
+			name:     "set",
+			args:     map[string]interface{}{"task_id": "t1", "date": "2026-11-02"},
+			wantDesc: "Notes\n\nref:defer=2026-11-02\nref:jira=PROJ-1",
+		},
+		{
+			name:        "after due date",
+			args:        map[string]interface{}{"task_id": "t1", "date": "2026-11-20"},
+			wantDesc:    "Notes\n\nref:defer=2026-11-20\nref:jira=PROJ-1",
+			wantWarning: true,
+		},
+		{
+			name:     "clear",
+			args:     map[string]interface{}{"task_id": "t2", "date": ""},
+			wantDesc: "Notes",
+		},
+		{name: "invalid date", args: map[string]interface{}{"task_id": "t1", "date": "tomorrow"}, wantErr: "YYYY-MM-DD"},
+		{name: "missing task", args: map[string]interface{}{"date": "2026-11-02"}, wantErr: "task_id is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotDesc string
+			client := &MockAPI{
+				GetFn: func(_ context.Context, path string) ([]byte, error) {
+					if path == "/tasks/t2" {
+						return json.Marshal(map[string]interface{}{"id": "t2", "description": "Notes\n\nref:defer=2026-11-02"})
+					}
+					return json.Marshal(map[string]interface{}{"id": "t1", "description": "Notes\n\nref:jira=PROJ-1", "due": map[string]interface{}{"date": "2026-11-15"}})
+				},
+				PostFn: func(_ context.Context, _ string, body interface{}) ([]byte, error) {
+					gotDesc = body.(map[string]interface{})["description"].(string)
+					return []byte(`{}`), nil
+				},
+			}
+
+			result, _ := SetDeferDateHandler(client)(context.Background(), makeReq(tt.args))
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Errorf("result = %q, want error containing %q", text, tt.wantErr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if gotDesc != tt.wantDesc {
+				t.Errorf("description = %q, want %q", gotDesc, tt.wantDesc)
+			}
+			if strings.Contains(text, "before its defer date") != tt.wantWarning {
+				t.Errorf("result = %s, want warning %v", text, tt.wantWarning)
+			}
+		})
+	}
+}
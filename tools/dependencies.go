@@ -0,0 +1,275 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// blockedBySystem is the reference footer entry listing a task's blockers,
+// e.g. "ref:blocked-by=8123,8456". Links are stored only on the blocked task;
+// the tasks a task blocks are found by scanning the others.
+const blockedBySystem = "blocked-by"
+
+// taskBlockerIDs returns the IDs in a task's blocked-by footer entry.
+func taskBlockerIDs(task models.Task) []string {
+	_, refs := parseReferences(task.Description)
+	ids := make([]string, 0)
+	for _, id := range strings.Split(refs[blockedBySystem], ",") {
+		if id = strings.TrimSpace(id); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// blockedByGraph maps each active task with links to the IDs blocking it.
+func blockedByGraph(tasks []models.Task) map[string][]string {
+	graph := make(map[string][]string)
+	for _, task := range tasks {
+		if ids := taskBlockerIDs(task); len(ids) > 0 {
+			graph[task.ID] = ids
+		}
+	}
+	return graph
+}
+
+// blocksTransitively reports whether blocker is among the blockers of
+// taskID, directly or through other blockers.
+func blocksTransitively(graph map[string][]string, taskID, blocker string) bool {
+	seen := map[string]bool{taskID: true}
+	queue := []string{taskID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range graph[id] {
+			if next == blocker {
+				return true
+			}
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// LinkTasksHandler creates a handler for adding or removing a dependency
+// between two tasks.
+func LinkTasksHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		taskID, ok := args["task_id"].(string)
+		if !ok || taskID == "" {
+			return respond.Error("task_id is required"), nil
+		}
+		if err := ValidateID(taskID, "task_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		blockedBy, _ := args["blocked_by"].(string)
+		blocks, _ := args["blocks"].(string)
+		if (blockedBy == "") == (blocks == "") {
+			return respond.Error("exactly one of blocked_by or blocks is required"), nil
+		}
+		// The link is always stored on the blocked task.
+		blockedID, blockerID := taskID, blockedBy
+		if blocks != "" {
+			if err := ValidateID(blocks, "blocks"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			blockedID, blockerID = blocks, taskID
+		} else if err := ValidateID(blockedBy, "blocked_by"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		if blockedID == blockerID {
+			return respond.Error("a task cannot block itself"), nil
+		}
+		remove, _ := args["remove"].(bool)
+
+		path := fmt.Sprintf("/tasks/%s", blockedID)
+		respBody, err := client.Get(ctx, path)
+		if err != nil {
+			return respond.Errorf("failed to get task: %v", err), nil
+		}
+		var task models.Task
+		if err := json.Unmarshal(respBody, &task); err != nil {
+			return respond.Errorf("failed to parse task: %v", err), nil
+		}
+		ids := taskBlockerIDs(task)
+
+		if remove {
+			ids = slices.DeleteFunc(ids, func(id string) bool { return id == blockerID })
+		} else if !slices.Contains(ids, blockerID) {
+			tasks, err := fetchTasks(ctx, client, url.Values{})
+			if err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			if !slices.ContainsFunc(tasks, func(t models.Task) bool { return t.ID == blockerID }) {
+				return respond.Errorf("task %s is not an active task", blockerID), nil
+			}
+			if blocksTransitively(blockedByGraph(tasks), blockerID, blockedID) {
+				return respond.Errorf("task %s already depends on task %s; linking them would create a cycle", blockerID, blockedID), nil
+			}
+			ids = append(ids, blockerID)
+		}
+
+		description := task.Description
+		body, refs := parseReferences(description)
+		if len(ids) == 0 {
+			delete(refs, blockedBySystem)
+		} else {
+			refs[blockedBySystem] = strings.Join(ids, ",")
+		}
+		updated := formatReferences(body, refs)
+
+		response := map[string]interface{}{
+			"task_id":    blockedID,
+			"blocked_by": ids,
+			"changed":    updated != description,
+		}
+		if updated != description {
+			if _, err := client.Post(ctx, path, map[string]interface{}{"description": updated}); err != nil {
+				return respond.Errorf("failed to update task: %v", err), nil
+			}
+		}
+		if remove {
+			response["message"] = fmt.Sprintf("Task %s no longer blocks task %s", blockerID, blockedID)
+		} else {
+			response["message"] = fmt.Sprintf("Task %s now blocks task %s", blockerID, blockedID)
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// blockerSummary describes a linked task; tasks that are no longer active
+// count as done.
+func blockerSummary(id string, byID map[string]models.Task) map[string]interface{} {
+	task, active := byID[id]
+	if !active {
+		return map[string]interface{}{"id": id, "done": true}
+	}
+	return map[string]interface{}{"id": id, "content": task.Content, "project_id": task.ProjectID, "done": false}
+}
+
+// GetBlockersHandler creates a handler for resolving task dependencies.
+func GetBlockersHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		taskID, _ := args["task_id"].(string)
+		if taskID != "" {
+			if err := ValidateID(taskID, "task_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+		projectID, _ := args["project_id"].(string)
+		if projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+
+		// Blockers may live in any project, so the whole graph is loaded.
+		tasks, err := fetchTasks(ctx, client, url.Values{})
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		byID := make(map[string]models.Task, len(tasks))
+		for _, task := range tasks {
+			byID[task.ID] = task
+		}
+		graph := blockedByGraph(tasks)
+
+		if taskID != "" {
+			task, ok := byID[taskID]
+			if !ok {
+				return respond.Errorf("task %s is not an active task", taskID), nil
+			}
+			blockers := make([]map[string]interface{}, 0, len(graph[taskID]))
+			for _, id := range graph[taskID] {
+				blockers = append(blockers, blockerSummary(id, byID))
+			}
+			// Open blockers of open blockers hold the task up as well.
+			chain := make([]map[string]interface{}, 0)
+			seen := map[string]bool{taskID: true}
+			queue := slices.Clone(graph[taskID])
+			for len(queue) > 0 {
+				id := queue[0]
+				queue = queue[1:]
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				if _, active := byID[id]; active {
+					chain = append(chain, blockerSummary(id, byID))
+					queue = append(queue, graph[id]...)
+				}
+			}
+			blocking := make([]map[string]interface{}, 0)
+			for _, t := range tasks {
+				if id := t.ID; slices.Contains(graph[id], taskID) {
+					blocking = append(blocking, blockerSummary(id, byID))
+				}
+			}
+
+			response := map[string]interface{}{
+				"task_id":           taskID,
+				"content":           task.Content,
+				"blocked":           len(chain) > 0,
+				"blockers":          blockers,
+				"open_dependencies": chain,
+				"blocks":            blocking,
+			}
+			return respond.JSON(response), nil
+		}
+
+		linked := make([]map[string]interface{}, 0)
+		blockedCount := 0
+		for _, task := range tasks {
+			id := task.ID
+			if len(graph[id]) == 0 || (projectID != "" && task.ProjectID != projectID) {
+				continue
+			}
+			blockers := make([]map[string]interface{}, 0, len(graph[id]))
+			blocked := false
+			for _, blockerID := range graph[id] {
+				summary := blockerSummary(blockerID, byID)
+				blocked = blocked || summary["done"] == false
+				blockers = append(blockers, summary)
+			}
+			if blocked {
+				blockedCount++
+			}
+			linked = append(linked, map[string]interface{}{
+				"id":         id,
+				"content":    task.Content,
+				"project_id": task.ProjectID,
+				"blocked":    blocked,
+				"blockers":   blockers,
+			})
+		}
+		// Blocked tasks first, so the ones held up stand out.
+		slices.SortStableFunc(linked, func(a, b map[string]interface{}) int {
+			if a["blocked"] == b["blocked"] {
+				return 0
+			}
+			if a["blocked"] == true {
+				return -1
+			}
+			return 1
+		})
+
+		return respond.JSON(respond.List("tasks", linked).Set("blocked_count", blockedCount)), nil
+	}
+}
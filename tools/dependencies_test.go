@@ -0,0 +1,172 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// dependencyMock serves the given active tasks and records description
+// updates by task path.
+func dependencyMock(tasks []map[string]interface{}, updates map[string]string) *MockAPI {
+	return &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if path == "/tasks" {
+				return json.Marshal(tasks)
+			}
+			for _, task := range tasks {
+				if path == "/tasks/"+task["id"].(string) {
+					return json.Marshal(task)
+				}
+			}
+			return nil, errors.New("task not found")
+		},
+		PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+			updates[path] = body.(map[string]interface{})["description"].(string)
+			return []byte(`{}`), nil
+		},
+	}
+}
+
+func TestLinkTasksHandler(t *testing.T) {
+	tasks := []map[string]interface{}{
+		{"id": "t1", "content": "Design", "project_id": "p1", "description": "Notes\n\nref:jira=PROJ-1"},
+		{"id": "t2", "content": "Build", "project_id": "p2", "description": "ref:blocked-by=t1"},
+		{"id": "t3", "content": "Ship", "project_id": "p2", "description": ""},
+	}
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantErr   string
+		wantPath  string
+		wantDesc  string
+		unchanged bool
+	}{
+		{
+			name:     "blocked_by keeps other references",
+			args:     map[string]interface{}{"task_id": "t1", "blocked_by": "t3"},
+			wantPath: "/tasks/t1",
+			wantDesc: "Notes\n\nref:blocked-by=t3\nref:jira=PROJ-1",
+		},
+		{
+			name:     "blocks is stored on the other task",
+			args:     map[string]interface{}{"task_id": "t2", "blocks": "t3"},
+			wantPath: "/tasks/t3",
+			wantDesc: "ref:blocked-by=t2",
+		},
+		{
+			name:     "remove",
+			args:     map[string]interface{}{"task_id": "t2", "blocked_by": "t1", "remove": true},
+			wantPath: "/tasks/t2",
+			wantDesc: "",
+		},
+		{
+			name:      "existing link",
+			args:      map[string]interface{}{"task_id": "t1", "blocks": "t2"},
+			unchanged: true,
+		},
+		{name: "cycle", args: map[string]interface{}{"task_id": "t1", "blocked_by": "t2"}, wantErr: "cycle"},
+		{name: "self", args: map[string]interface{}{"task_id": "t1", "blocks": "t1"}, wantErr: "cannot block itself"},
+		{name: "inactive blocker", args: map[string]interface{}{"task_id": "t3", "blocked_by": "t9"}, wantErr: "not an active task"},
+		{name: "both directions", args: map[string]interface{}{"task_id": "t1", "blocks": "t2", "blocked_by": "t3"}, wantErr: "exactly one"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates := make(map[string]string)
+			result, err := LinkTasksHandler(dependencyMock(tasks, updates))(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Errorf("result = %q, want error containing %q", text, tt.wantErr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if tt.unchanged {
+				if len(updates) != 0 || !strings.Contains(text, `"changed": false`) {
+					t.Errorf("updates = %v, result = %s", updates, text)
+				}
+				return
+			}
+			if desc, ok := updates[tt.wantPath]; !ok || desc != tt.wantDesc || len(updates) != 1 {
+				t.Errorf("updates = %q, want %s = %q", updates, tt.wantPath, tt.wantDesc)
+			}
+		})
+	}
+}
+
+func TestGetBlockersHandler(t *testing.T) {
+	// t3 waits on t2, which waits on t1 and the completed t0; t4 waits only
+	// on t0.
+	tasks := []map[string]interface{}{
+		{"id": "t1", "content": "Design", "project_id": "p1"},
+		{"id": "t2", "content": "Build", "project_id": "p2", "description": "ref:blocked-by=t1,t0"},
+		{"id": "t3", "content": "Ship", "project_id": "p2", "description": "ref:blocked-by=t2"},
+		{"id": "t4", "content": "Announce", "project_id": "p1", "description": "ref:blocked-by=t0"},
+	}
+	handler := GetBlockersHandler(dependencyMock(tasks, map[string]string{}))
+
+	t.Run("single task", func(t *testing.T) {
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{"task_id": "t3"}))
+		var resp struct {
+			Blocked  bool                     `json:"blocked"`
+			Blockers []map[string]interface{} `json:"blockers"`
+			Open     []map[string]interface{} `json:"open_dependencies"`
+			Blocks   []map[string]interface{} `json:"blocks"`
+		}
+		if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+			t.Fatalf("parse: %v (%s)", err, resultText(result))
+		}
+		if !resp.Blocked || len(resp.Blockers) != 1 || resp.Blockers[0]["id"] != "t2" {
+			t.Errorf("resp = %+v", resp)
+		}
+		if len(resp.Open) != 2 || resp.Open[0]["id"] != "t2" || resp.Open[1]["id"] != "t1" || len(resp.Blocks) != 0 {
+			t.Errorf("open = %v, blocks = %v", resp.Open, resp.Blocks)
+		}
+	})
+
+	t.Run("blocks", func(t *testing.T) {
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{"task_id": "t1"}))
+		text := resultText(result)
+		if !strings.Contains(text, `"blocked": false`) || !strings.Contains(text, `"id": "t2"`) {
+			t.Errorf("result = %s", text)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{}))
+		var resp struct {
+			Count        int                      `json:"count"`
+			BlockedCount int                      `json:"blocked_count"`
+			Tasks        []map[string]interface{} `json:"tasks"`
+		}
+		if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		if resp.Count != 3 || resp.BlockedCount != 2 || resp.Tasks[0]["id"] != "t2" || resp.Tasks[2]["id"] != "t4" || resp.Tasks[2]["blocked"] != false {
+			t.Errorf("resp = %+v", resp)
+		}
+	})
+
+	t.Run("project filter", func(t *testing.T) {
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{"project_id": "p1"}))
+		if text := resultText(result); !strings.Contains(text, `"count": 1`) || !strings.Contains(text, `"blocked_count": 0`) {
+			t.Errorf("result = %s", text)
+		}
+	})
+
+	t.Run("inactive task", func(t *testing.T) {
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{"task_id": "t0"}))
+		if !result.IsError || !strings.Contains(resultText(result), "not an active task") {
+			t.Errorf("result = %s", resultText(result))
+		}
+	})
+}
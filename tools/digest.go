@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+)
+
+// DailyDigestURI is the MCP resource URI that renders today's summary.
+const DailyDigestURI = "todoist://digest/today"
+
+// maxDigestNotifications bounds the unread notifications listed in the digest.
+const maxDigestNotifications = 10
+
+// fetchUnreadNotifications returns the user's unread live notifications,
+// newest first.
+func fetchUnreadNotifications(ctx context.Context, syncClient todoist.SyncAPI) ([]map[string]interface{}, error) {
+	params := url.Values{}
+	params.Set("sync_token", "*")
+	params.Set("resource_types", `["live_notifications"]`)
+	respBody, err := syncClient.Get(ctx, "/sync?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		LiveNotifications []map[string]interface{} `json:"live_notifications"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse notifications: %w", err)
+	}
+	unread := make([]map[string]interface{}, 0)
+	for _, n := range resp.LiveNotifications {
+		if isUnread, _ := n["is_unread"].(bool); isUnread {
+			unread = append(unread, n)
+		}
+	}
+	sort.SliceStable(unread, func(i, j int) bool {
+		return fmt.Sprint(unread[i]["created_at"]) > fmt.Sprint(unread[j]["created_at"])
+	})
+	return unread, nil
+}
+
+// notificationPhrases describes common live notification types.
+var notificationPhrases = map[string]string{
+	"item_assign":               "Task assigned to you",
+	"item_complete":             "Task completed",
+	"item_uncomplete":           "Task reopened",
+	"note_added":                "New comment",
+	"share_invitation_sent":     "Project shared with you",
+	"share_invitation_accepted": "Invitation accepted",
+	"user_left_project":         "Collaborator left project",
+	"user_removed_from_project": "Removed from project",
+}
+
+// describeNotification renders a live notification as one line, e.g.
+// "Task assigned to you: Review PR".
+func describeNotification(n map[string]interface{}) string {
+	kind := fmt.Sprint(n["notification_type"])
+	phrase, ok := notificationPhrases[kind]
+	if !ok {
+		phrase = strings.ReplaceAll(kind, "_", " ")
+	}
+	for _, key := range []string{"item_content", "note_content", "project_name"} {
+		if detail, _ := n[key].(string); detail != "" {
+			return fmt.Sprintf("%s: %s", phrase, detail)
+		}
+	}
+	return phrase
+}
+
+// renderDigestTask renders a task as a Markdown list item.
+func renderDigestTask(b *strings.Builder, task models.Task, showDate bool) {
+	fmt.Fprintf(b, "- %s", task.Content)
+	if task.Priority > 1 {
+		// The API's priority 4 is p1 in the Todoist apps.
+		fmt.Fprintf(b, " (p%d)", 5-task.Priority)
+	}
+	if showDate {
+		fmt.Fprintf(b, " (due %s)", task.Due.Day())
+	}
+	fmt.Fprintf(b, " [id %s]\n", task.ID)
+}
+
+// DailyDigestResourceHandler creates a handler that renders today's summary
+// as Markdown when the resource is read: tasks due today, overdue tasks,
+// today's completions, and unread notifications.
+func DailyDigestResourceHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		loc, err := userLocation(ctx, syncClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve user time zone: %w", err)
+		}
+		now := time.Now().In(loc)
+		today := now.Format("2006-01-02")
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+		tasks, err := resolveFilterTasks(ctx, client, "today | overdue")
+		if err != nil {
+			return nil, err
+		}
+		var dueToday, overdue []models.Task
+		for _, task := range tasks {
+			if task.Due.Day() < today {
+				overdue = append(overdue, task)
+			} else {
+				dueToday = append(dueToday, task)
+			}
+		}
+		sort.SliceStable(dueToday, func(i, j int) bool { return dueToday[i].Priority > dueToday[j].Priority })
+		sort.SliceStable(overdue, func(i, j int) bool { return overdue[i].Due.Day() < overdue[j].Due.Day() })
+
+		completed, _, err := fetchCompletedTasks(ctx, syncClient, midnight, now, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch completed tasks: %w", err)
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "# Todoist digest for %s\n\n", formatLongDate(now))
+		fmt.Fprintf(&b, "%d due today, %d overdue, %d completed today.\n", len(dueToday), len(overdue), len(completed))
+
+		fmt.Fprintf(&b, "\n## Due today\n\n")
+		if len(dueToday) == 0 {
+			b.WriteString("Nothing due today.\n")
+		}
+		for _, task := range dueToday {
+			renderDigestTask(&b, task, false)
+		}
+		if len(overdue) > 0 {
+			fmt.Fprintf(&b, "\n## Overdue\n\n")
+			for _, task := range overdue {
+				renderDigestTask(&b, task, true)
+			}
+		}
+		if len(completed) > 0 {
+			fmt.Fprintf(&b, "\n## Completed today\n\n")
+			for _, item := range completed {
+				fmt.Fprintf(&b, "- %s\n", item.Content)
+			}
+		}
+
+		// Notifications are a nice-to-have; the digest still renders when
+		// the Sync endpoint is unavailable.
+		fmt.Fprintf(&b, "\n## Notifications\n\n")
+		if notifications, err := fetchUnreadNotifications(ctx, syncClient); err != nil {
+			fmt.Fprintf(&b, "Unavailable: %v\n", err)
+		} else if len(notifications) == 0 {
+			b.WriteString("No unread notifications.\n")
+		} else {
+			for i, n := range notifications {
+				if i == maxDigestNotifications {
+					fmt.Fprintf(&b, "- ... and %d more\n", len(notifications)-i)
+					break
+				}
+				fmt.Fprintf(&b, "- %s\n", describeNotification(n))
+			}
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      DailyDigestURI,
+				MIMEType: "text/markdown",
+				Text:     b.String(),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDailyDigestResourceHandler(t *testing.T) {
+	now := time.Now().UTC()
+	today := now.Format("2006-01-02")
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if !strings.Contains(path, "filter=today") {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return json.Marshal([]map[string]interface{}{
+			{"id": "1", "content": "Pay rent", "priority": float64(4), "due": map[string]interface{}{"date": today}},
+			{"id": "2", "content": "Call plumber", "priority": float64(1), "due": map[string]interface{}{"date": now.AddDate(0, 0, -3).Format("2006-01-02")}},
+		})
+	}}
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		switch {
+		case path == "/user":
+			return []byte(`{"tz_info": {"timezone": "UTC"}}`), nil
+		case strings.HasPrefix(path, "/tasks/completed/"):
+			return []byte(`{"items": [{"content": "Water plants", "completed_at": "` + now.Format(time.RFC3339) + `"}]}`), nil
+		case strings.HasPrefix(path, "/sync?"):
+			return json.Marshal(map[string]interface{}{"live_notifications": []map[string]interface{}{
+				{"notification_type": "item_assign", "item_content": "Review PR", "is_unread": true},
+				{"notification_type": "note_added", "is_unread": false},
+			}})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}}
+
+	contents, err := DailyDigestResourceHandler(client, syncClient)(context.Background(), mcp.ReadResourceRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := contents[0].(mcp.TextResourceContents).Text
+	for _, want := range []string{
+		"1 due today, 1 overdue, 1 completed today.",
+		"- Pay rent (p1) [id 1]",
+		"## Overdue\n\n- Call plumber (due ",
+		"## Completed today\n\n- Water plants",
+		"- Task assigned to you: Review PR",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("digest missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "New comment") {
+		t.Errorf("digest lists a read notification:\n%s", text)
+	}
+}
@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// userLocation returns the time zone configured in the user's Todoist
+// settings. Floating due times ("at 3pm" without a zone) are wall-clock times
+// in this zone.
+func userLocation(ctx context.Context, syncClient todoist.SyncAPI) (*time.Location, error) {
+	respBody, err := syncClient.Get(ctx, "/user")
+	if err != nil {
+		return nil, err
+	}
+	var user struct {
+		TZInfo struct {
+			Timezone string `json:"timezone"`
+		} `json:"tz_info"`
+	}
+	if err := json.Unmarshal(respBody, &user); err != nil {
+		return nil, fmt.Errorf("failed to parse user: %w", err)
+	}
+	if user.TZInfo.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(user.TZInfo.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown user time zone %q: %w", user.TZInfo.Timezone, err)
+	}
+	return loc, nil
+}
+
+// taskDueTime returns the instant a timed task is due. Fixed-zone datetimes
+// carry an offset; floating ones are read in loc. All-day tasks return false.
+func taskDueTime(task models.Task, loc *time.Location) (time.Time, bool) {
+	if task.Due == nil {
+		return time.Time{}, false
+	}
+	datetime := task.Due.Datetime
+	if datetime == "" && len(task.Due.Date) > 10 {
+		// Sync API objects put the time in due.date instead.
+		datetime = task.Due.Date
+	}
+	if datetime == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, datetime); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04:05", datetime, loc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// GetDueSoonHandler creates a handler listing timed tasks that start within the
+// next hours, plus tasks already running within their duration.
+func GetDueSoonHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		hours := 2.0
+		if h, ok := args["hours"].(float64); ok {
+			if h <= 0 || h > 168 {
+				return respond.Error("hours must be between 0 and 168"), nil
+			}
+			hours = h
+		}
+
+		loc, err := userLocation(ctx, syncClient)
+		if err != nil {
+			return respond.Errorf("failed to resolve user time zone: %v", err), nil
+		}
+		now := time.Now().In(loc)
+		end := now.Add(time.Duration(hours * float64(time.Hour)))
+
+		params := url.Values{}
+		params.Set("filter", fmt.Sprintf("due before: %s", end.AddDate(0, 0, 1).Format("2006-01-02")))
+		tasks, err := fetchTasks(ctx, client, params)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		type dueSoon struct {
+			start time.Time
+			entry map[string]interface{}
+		}
+		var found []dueSoon
+		for _, task := range tasks {
+			start, ok := taskDueTime(task, loc)
+			if !ok || start.After(end) {
+				continue
+			}
+			minutes, hasDuration := taskDurationMinutes(task, 24*60)
+			finish := start
+			if hasDuration {
+				finish = start.Add(time.Duration(minutes) * time.Minute)
+			}
+
+			status := "upcoming"
+			if start.Before(now) {
+				if !finish.After(now) {
+					continue
+				}
+				status = "in_progress"
+			}
+
+			startsIn := int(start.Sub(now).Round(time.Minute) / time.Minute)
+			entry := map[string]interface{}{
+				"id":                task.ID,
+				"content":           task.Content,
+				"priority":          task.Priority,
+				"project_id":        task.ProjectID,
+				"starts_at":         start.In(loc).Format(time.RFC3339),
+				"starts_in_minutes": startsIn,
+				"status":            status,
+			}
+			if status == "upcoming" {
+				entry["message"] = fmt.Sprintf("starts in %s", formatMinutes(startsIn))
+			} else {
+				entry["message"] = fmt.Sprintf("started %s ago, ends in %s", formatMinutes(-startsIn), formatMinutes(int(finish.Sub(now).Round(time.Minute)/time.Minute)))
+			}
+			if hasDuration {
+				entry["duration_minutes"] = minutes
+				entry["ends_at"] = finish.In(loc).Format(time.RFC3339)
+			}
+			found = append(found, dueSoon{start: start, entry: entry})
+		}
+
+		sort.SliceStable(found, func(i, j int) bool { return found[i].start.Before(found[j].start) })
+		results := make([]map[string]interface{}, len(found))
+		for i, f := range found {
+			results[i] = f.entry
+		}
+
+		response := map[string]interface{}{
+			"now":          now.Format(time.RFC3339),
+			"timezone":     loc.String(),
+			"window_hours": hours,
+			"count":        len(results),
+			"tasks":        results,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+)
+
+func TestTaskDueTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	tests := []struct {
+		name   string
+		due    *models.Due
+		want   string
+		wantOK bool
+	}{
+		{name: "fixed zone", due: &models.Due{Date: "2026-03-02", Datetime: "2026-03-02T14:00:00Z"}, want: "2026-03-02T14:00:00Z", wantOK: true},
+		{name: "floating", due: &models.Due{Date: "2026-03-02", Datetime: "2026-03-02T15:00:00"}, want: "2026-03-02T14:00:00Z", wantOK: true},
+		{name: "sync style", due: &models.Due{Date: "2026-03-02T15:00:00"}, want: "2026-03-02T14:00:00Z", wantOK: true},
+		{name: "all day", due: &models.Due{Date: "2026-03-02"}},
+		{name: "undated"},
+	}
+	for _, tt := range tests {
+		got, ok := taskDueTime(models.Task{Due: tt.due}, berlin)
+		if ok != tt.wantOK || (ok && got.UTC().Format(time.RFC3339) != tt.want) {
+			t.Errorf("%s: taskDueTime = %v, %v, want %s, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestGetDueSoonHandler(t *testing.T) {
+	now := time.Now().UTC()
+	at := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+	tasks := []map[string]interface{}{
+		{"id": "later", "content": "Later today", "due": map[string]interface{}{"datetime": at(5 * time.Hour)}},
+		{"id": "soon", "content": "Standup", "due": map[string]interface{}{"datetime": at(20 * time.Minute)}},
+		{"id": "running", "content": "Workshop", "due": map[string]interface{}{"datetime": at(-30 * time.Minute)},
+			"duration": map[string]interface{}{"amount": float64(60), "unit": "minute"}},
+		{"id": "missed", "content": "Missed call", "due": map[string]interface{}{"datetime": at(-30 * time.Minute)}},
+		{"id": "allday", "content": "All day", "due": map[string]interface{}{"date": now.Format("2006-01-02")}},
+	}
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if !strings.HasPrefix(path, "/tasks?filter=due+before") {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return json.Marshal(tasks)
+	}}
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		return []byte(`{"id": "u1", "tz_info": {"timezone": "UTC"}}`), nil
+	}}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantIDs []string
+		wantErr string
+	}{
+		{name: "default window", args: nil, wantIDs: []string{"running", "soon"}},
+		{name: "wider window", args: map[string]interface{}{"hours": float64(6)}, wantIDs: []string{"running", "soon", "later"}},
+		{name: "invalid hours", args: map[string]interface{}{"hours": float64(0)}, wantErr: "hours must be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetDueSoonHandler(client, syncClient)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			var resp struct {
+				Tasks []map[string]interface{} `json:"tasks"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			ids := make([]string, len(resp.Tasks))
+			for i, task := range resp.Tasks {
+				ids[i] = task["id"].(string)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			if resp.Tasks[0]["status"] != "in_progress" || resp.Tasks[1]["message"] != "starts in 20m" {
+				t.Errorf("tasks = %v", resp.Tasks)
+			}
+		})
+	}
+}
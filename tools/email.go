@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// emailLabel is the label applied to tasks captured from email.
+const emailLabel = "email"
+
+// maxEmailBodyLength keeps the description under Todoist's 16384 character
+// limit once the header lines and reference footer are added.
+const maxEmailBodyLength = 15000
+
+// emailDateLayouts are the accepted formats for received_date, including the
+// RFC 5322 Date header form that mail clients expose.
+var emailDateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02",
+}
+
+// parseEmailDate parses a received date in any of emailDateLayouts.
+func parseEmailDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	// Date headers often end with a comment such as "(UTC)".
+	if i := strings.Index(s, " ("); i > 0 {
+		s = s[:i]
+	}
+	for _, layout := range emailDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("received_date must be RFC 3339, an email Date header, or YYYY-MM-DD")
+}
+
+// emailMessageLink returns a message: URL that opens the email in mail
+// clients supporting the scheme (Apple Mail, Outlook for Mac, Thunderbird add-ons).
+func emailMessageLink(messageID string) string {
+	id := strings.Trim(strings.TrimSpace(messageID), "<>")
+	return "message://" + url.PathEscape("<"+id+">")
+}
+
+// CaptureEmailAsTaskHandler creates a handler that turns an email into a task.
+// Tasks go to project_id when given, otherwise to triageProjectID, otherwise Inbox.
+func CaptureEmailAsTaskHandler(client todoist.API, triageProjectID string) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		subject, _ := args["subject"].(string)
+		subject = strings.Join(strings.Fields(subject), " ")
+		if subject == "" {
+			return respond.Error("subject is required"), nil
+		}
+		sender, _ := args["sender"].(string)
+		sender = strings.TrimSpace(sender)
+		if sender == "" {
+			return respond.Error("sender is required"), nil
+		}
+		emailBody, _ := args["body"].(string)
+		emailBody = strings.TrimSpace(emailBody)
+		messageID, _ := args["message_id"].(string)
+		messageID = strings.TrimSpace(messageID)
+		if strings.ContainsAny(messageID, "\r\n") {
+			return respond.Error("message_id must be a single line"), nil
+		}
+
+		var received time.Time
+		if s, ok := args["received_date"].(string); ok && s != "" {
+			t, err := parseEmailDate(s)
+			if err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			received = t
+		}
+
+		projectID, _ := args["project_id"].(string)
+		if projectID == "" {
+			projectID = triageProjectID
+		}
+		if projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+
+		var header []string
+		header = append(header, "From: "+sender)
+		if !received.IsZero() {
+			header = append(header, "Received: "+received.Format("2006-01-02 15:04 -0700"))
+		}
+		if messageID != "" {
+			header = append(header, fmt.Sprintf("[Open in mail](%s)", emailMessageLink(messageID)))
+		}
+		description := strings.Join(header, "\n")
+
+		truncated := false
+		if len([]rune(emailBody)) > maxEmailBodyLength {
+			emailBody = string([]rune(emailBody)[:maxEmailBodyLength]) + "…"
+			truncated = true
+		}
+		if emailBody != "" {
+			description += "\n\n" + emailBody
+		}
+		if messageID != "" {
+			description = formatReferences(description, map[string]string{emailLabel: messageID})
+		}
+
+		body := map[string]interface{}{
+			"content":     subject,
+			"description": description,
+			"labels":      []string{emailLabel},
+		}
+		if projectID != "" {
+			body["project_id"] = projectID
+		}
+		if dueString, ok := args["due_string"].(string); ok && dueString != "" {
+			body["due_string"] = dueString
+		}
+
+		respBody, err := client.Post(ctx, "/tasks", body)
+		if err != nil {
+			return respond.Errorf("failed to create task: %v", err), nil
+		}
+
+		var task models.Task
+		if err := json.Unmarshal(respBody, &task); err != nil {
+			return respond.Errorf("failed to parse response: %v", err), nil
+		}
+		if truncated {
+			task.Set("body_truncated", true)
+		}
+
+		return respond.JSON(task), nil
+	}
+}
@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseEmailDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "2026-02-01T09:30:00Z", want: "2026-02-01T09:30:00Z"},
+		{in: "Sun, 1 Feb 2026 09:30:00 +0100", want: "2026-02-01T09:30:00+01:00"},
+		{in: "Sun, 01 Feb 2026 09:30:00 +0000 (UTC)", want: "2026-02-01T09:30:00Z"},
+		{in: "2026-02-01", want: "2026-02-01T00:00:00Z"},
+		{in: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseEmailDate(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseEmailDate(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got.Format("2006-01-02T15:04:05Z07:00") != tt.want {
+			t.Errorf("parseEmailDate(%q) = %v, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCaptureEmailAsTaskHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		triage      string
+		args        map[string]interface{}
+		wantErr     bool
+		errSubstr   string
+		wantProject string
+		wantDesc    []string
+	}{
+		{
+			name:   "full email into triage project",
+			triage: "triage1",
+			args: map[string]interface{}{
+				"subject":       "  Invoice   #42 ",
+				"sender":        "Billing <billing@example.com>",
+				"body":          "Please pay by Friday.",
+				"received_date": "2026-02-01T09:30:00Z",
+				"message_id":    "<abc123@mail.example.com>",
+			},
+			wantProject: "triage1",
+			wantDesc: []string{
+				"From: Billing <billing@example.com>",
+				"Received: 2026-02-01 09:30 +0000",
+				"[Open in mail](message://%3Cabc123@mail.example.com%3E)",
+				"Please pay by Friday.",
+				"ref:email=<abc123@mail.example.com>",
+			},
+		},
+		{
+			name:        "explicit project overrides triage",
+			triage:      "triage1",
+			args:        map[string]interface{}{"subject": "Hi", "sender": "a@example.com", "project_id": "p2"},
+			wantProject: "p2",
+			wantDesc:    []string{"From: a@example.com"},
+		},
+		{
+			name:     "inbox when no triage project",
+			args:     map[string]interface{}{"subject": "Hi", "sender": "a@example.com"},
+			wantDesc: []string{"From: a@example.com"},
+		},
+		{
+			name:      "missing subject",
+			args:      map[string]interface{}{"sender": "a@example.com"},
+			wantErr:   true,
+			errSubstr: "subject is required",
+		},
+		{
+			name:      "bad date",
+			args:      map[string]interface{}{"subject": "Hi", "sender": "a@example.com", "received_date": "last week"},
+			wantErr:   true,
+			errSubstr: "received_date",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent map[string]interface{}
+			client := &MockAPI{
+				PostFn: func(_ context.Context, _ string, body interface{}) ([]byte, error) {
+					sent = body.(map[string]interface{})
+					return json.Marshal(map[string]interface{}{"id": "t1"})
+				},
+			}
+
+			result, err := CaptureEmailAsTaskHandler(client, tt.triage)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+
+			if project, _ := sent["project_id"].(string); project != tt.wantProject {
+				t.Errorf("project_id = %q, want %q", project, tt.wantProject)
+			}
+			if labels := sent["labels"].([]string); len(labels) != 1 || labels[0] != emailLabel {
+				t.Errorf("labels = %v, want [email]", labels)
+			}
+			if content := sent["content"]; content != strings.Join(strings.Fields(tt.args["subject"].(string)), " ") {
+				t.Errorf("content = %v", content)
+			}
+			desc := sent["description"].(string)
+			for _, want := range tt.wantDesc {
+				if !strings.Contains(desc, want) {
+					t.Errorf("description %q missing %q", desc, want)
+				}
+			}
+			if _, refs := parseReferences(desc); tt.args["message_id"] != nil && refs["email"] != tt.args["message_id"] {
+				t.Errorf("email reference = %q", refs["email"])
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// estimateLabelPattern matches estimate labels such as 15min, 45m, or 1h.
+var estimateLabelPattern = regexp.MustCompile(`^(\d+)(min|m|h)$`)
+
+// parseEstimate converts an estimate such as "15min", "45m", or "1h" to
+// minutes. The second return value is false for anything else.
+func parseEstimate(s string) (int, bool) {
+	m := estimateLabelPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	if m[2] == "h" {
+		n *= 60
+	}
+	return n, true
+}
+
+// estimateLabel formats minutes as an estimate label: whole hours as "1h",
+// anything else as "45min".
+func estimateLabel(minutes int) string {
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dmin", minutes)
+}
+
+// taskEstimate returns a task's estimate in minutes and where it came from
+// ("duration" or "label"). The duration field wins over labels.
+func taskEstimate(task models.Task) (int, string, bool) {
+	if minutes, ok := taskDurationMinutes(task, 24*60); ok {
+		return minutes, "duration", true
+	}
+	for _, name := range task.Labels {
+		if minutes, ok := parseEstimate(name); ok {
+			return minutes, "label", true
+		}
+	}
+	return 0, "", false
+}
+
+// EstimateTasksHandler creates a handler that reports or applies time estimates across tasks.
+func EstimateTasksHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		estimate, _ := args["estimate"].(string)
+		minutes := 0
+		if estimate != "" {
+			m, ok := parseEstimate(estimate)
+			if !ok {
+				return respond.Error("estimate must look like 15min, 45m, or 1h"), nil
+			}
+			minutes = m
+		}
+		target := "duration"
+		if t, ok := args["target"].(string); ok && t != "" {
+			switch t {
+			case "duration", "label", "both":
+				target = t
+			default:
+				return respond.Error("target must be 'duration', 'label', or 'both'"), nil
+			}
+		}
+
+		filter, _ := args["filter"].(string)
+		projectID, _ := args["project_id"].(string)
+		ids := taskIDsArg(args)
+		if estimate != "" && filter == "" && projectID == "" && len(ids) == 0 {
+			return respond.Error("applying an estimate requires task_ids, filter, or project_id"), nil
+		}
+
+		params := url.Values{}
+		switch {
+		case len(ids) > 0:
+			params.Set("ids", strings.Join(ids, ","))
+		case filter != "":
+			params.Set("filter", filter)
+		case projectID != "":
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			params.Set("project_id", projectID)
+		}
+		tasks, err := fetchTasks(ctx, client, params)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		if estimate != "" {
+			return applyEstimate(ctx, client, syncClient, tasks, minutes, target)
+		}
+
+		buckets := make(map[string]int)
+		bySource := map[string]int{"duration": 0, "label": 0}
+		unestimated := make([]map[string]interface{}, 0)
+		totalMinutes := 0
+		for _, task := range tasks {
+			m, source, ok := taskEstimate(task)
+			if !ok {
+				unestimated = append(unestimated, map[string]interface{}{
+					"id":      task.ID,
+					"content": task.Content,
+				})
+				continue
+			}
+			buckets[estimateLabel(m)]++
+			bySource[source]++
+			totalMinutes += m
+		}
+
+		estimated := len(tasks) - len(unestimated)
+		coverage := 0.0
+		if len(tasks) > 0 {
+			coverage = float64(estimated) / float64(len(tasks)) * 100
+		}
+
+		response := map[string]interface{}{
+			"total_tasks":       len(tasks),
+			"estimated":         estimated,
+			"unestimated":       len(unestimated),
+			"coverage_percent":  coverage,
+			"total_minutes":     totalMinutes,
+			"by_estimate":       buckets,
+			"by_source":         bySource,
+			"unestimated_tasks": unestimated,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// applyEstimate sets a minutes estimate on tasks as a duration, an estimate
+// label, or both. Any other estimate label on a task is replaced.
+func applyEstimate(ctx context.Context, client todoist.API, syncClient todoist.SyncAPI, tasks []models.Task, minutes int, target string) (*mcp.CallToolResult, error) {
+	if len(tasks) == 0 {
+		return respond.Error("no tasks matched the selection"), nil
+	}
+	if len(tasks) > maxPlanCommands {
+		return respond.Errorf("selection matches %d tasks, more than the %d allowed per call", len(tasks), maxPlanCommands), nil
+	}
+
+	label := estimateLabel(minutes)
+	ops := make([]todoist.BulkOperation, 0, len(tasks))
+	for _, task := range tasks {
+		taskID := task.ID
+		cmdArgs := map[string]interface{}{"id": taskID}
+		body := map[string]interface{}{}
+
+		if target == "duration" || target == "both" {
+			cmdArgs["duration"] = map[string]interface{}{"amount": minutes, "unit": "minute"}
+			body["duration"] = minutes
+			body["duration_unit"] = "minute"
+		}
+		if target == "label" || target == "both" {
+			labels := []string{label}
+			for _, name := range task.Labels {
+				if _, isEstimate := parseEstimate(name); !isEstimate {
+					labels = append(labels, name)
+				}
+			}
+			sort.Strings(labels[1:])
+			cmdArgs["labels"] = labels
+			body["labels"] = labels
+		}
+
+		ops = append(ops, todoist.BulkOperation{
+			ID: taskID,
+			Command: todoist.Command{
+				Type: "item_update",
+				UUID: todoist.GenerateUUID(),
+				Args: cmdArgs,
+			},
+			Method: http.MethodPost,
+			Path:   fmt.Sprintf("/tasks/%s", taskID),
+			Body:   body,
+		})
+	}
+
+	result, err := todoist.NewBulkExecutor(client, syncClient).Execute(ctx, ops)
+	if err != nil {
+		return respond.Errorf("failed to apply estimates: %v", err), nil
+	}
+	failedTasks := result.FailedIDs()
+
+	response := map[string]interface{}{
+		"estimate_minutes": minutes,
+		"target":           target,
+		"total_tasks":      len(ops),
+		"updated":          len(result.Succeeded),
+		"failed":           len(failedTasks),
+		"failed_task_ids":  failedTasks,
+		"used_batching":    result.Strategy == todoist.StrategySync,
+	}
+	if target != "duration" {
+		response["label"] = label
+	}
+
+	return respond.JSON(response), nil
+}
@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseEstimate(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   int
+		wantOK bool
+	}{
+		{in: "15min", want: 15, wantOK: true},
+		{in: "45m", want: 45, wantOK: true},
+		{in: "2h", want: 120, wantOK: true},
+		{in: " 1H ", want: 60, wantOK: true},
+		{in: "0min"},
+		{in: "work"},
+		{in: "1.5h"},
+	}
+	for _, tt := range tests {
+		got, ok := parseEstimate(tt.in)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseEstimate(%q) = %d, %v, want %d, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+	if estimateLabel(90) != "90min" || estimateLabel(120) != "2h" {
+		t.Errorf("estimateLabel = %q, %q", estimateLabel(90), estimateLabel(120))
+	}
+}
+
+func TestEstimateTasksHandler(t *testing.T) {
+	tasks := []map[string]interface{}{
+		{"id": "1", "content": "Duration", "duration": map[string]interface{}{"amount": float64(30), "unit": "minute"}},
+		{"id": "2", "content": "Label", "labels": []interface{}{"work", "1h"}},
+		{"id": "3", "content": "None", "labels": []interface{}{"work"}},
+	}
+
+	t.Run("report", func(t *testing.T) {
+		client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if path != "/tasks?project_id=p1" {
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			}
+			return json.Marshal(tasks)
+		}}
+		result, err := EstimateTasksHandler(client, &MockSyncAPI{})(context.Background(), makeReq(map[string]interface{}{"project_id": "p1"}))
+		if err != nil || result.IsError {
+			t.Fatalf("unexpected error: %v %s", err, resultText(result))
+		}
+		var resp struct {
+			Estimated   int            `json:"estimated"`
+			Unestimated int            `json:"unestimated"`
+			Total       int            `json:"total_minutes"`
+			ByEstimate  map[string]int `json:"by_estimate"`
+			BySource    map[string]int `json:"by_source"`
+		}
+		if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		if resp.Estimated != 2 || resp.Unestimated != 1 || resp.Total != 90 {
+			t.Errorf("estimated = %d, unestimated = %d, total = %d", resp.Estimated, resp.Unestimated, resp.Total)
+		}
+		if resp.ByEstimate["30min"] != 1 || resp.ByEstimate["1h"] != 1 || resp.BySource["label"] != 1 {
+			t.Errorf("by_estimate = %v, by_source = %v", resp.ByEstimate, resp.BySource)
+		}
+	})
+
+	t.Run("apply label replaces old estimate", func(t *testing.T) {
+		bodies := make(map[string]map[string]interface{})
+		client := &MockAPI{
+			GetFn: func(_ context.Context, _ string) ([]byte, error) {
+				return json.Marshal(tasks[1:])
+			},
+			PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+				bodies[path] = body.(map[string]interface{})
+				return []byte(`{}`), nil
+			},
+		}
+		result, err := EstimateTasksHandler(client, &MockSyncAPI{})(context.Background(), makeReq(map[string]interface{}{
+			"task_ids": []interface{}{"2", "3"}, "estimate": "15m", "target": "both",
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("unexpected error: %v %s", err, resultText(result))
+		}
+		for _, path := range []string{"/tasks/2", "/tasks/3"} {
+			body := bodies[path]
+			if labels := fmt.Sprint(body["labels"]); labels != "[15min work]" {
+				t.Errorf("%s labels = %s, want [15min work]", path, labels)
+			}
+			if body["duration"] != 15 || body["duration_unit"] != "minute" {
+				t.Errorf("%s duration = %v %v", path, body["duration"], body["duration_unit"])
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		client := &MockAPI{GetFn: func(_ context.Context, _ string) ([]byte, error) { return []byte(`[]`), nil }}
+		for want, args := range map[string]map[string]interface{}{
+			"estimate must look like": {"estimate": "soon", "filter": "today"},
+			"requires task_ids":       {"estimate": "1h"},
+			"target must be":          {"target": "both_ways"},
+			"no tasks matched":        {"estimate": "1h", "filter": "today"},
+		} {
+			result, _ := EstimateTasksHandler(client, &MockSyncAPI{})(context.Background(), makeReq(args))
+			if !result.IsError || !strings.Contains(resultText(result), want) {
+				t.Errorf("args %v: got %q, want error containing %q", args, resultText(result), want)
+			}
+		}
+	})
+}
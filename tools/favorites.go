@@ -0,0 +1,245 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// favoriteCommandTypes maps each favoritable entity type to its Sync API update command.
+var favoriteCommandTypes = map[string]string{
+	"project": "project_update",
+	"label":   "label_update",
+	"filter":  "filter_update",
+}
+
+// fetchSyncResources performs a full sync for the given resource types and
+// returns the raw JSON of each requested resource, keyed by type.
+func fetchSyncResources(ctx context.Context, syncClient todoist.SyncAPI, resourceTypes ...string) (map[string]json.RawMessage, error) {
+	typesJSON, err := json.Marshal(resourceTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource types: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("sync_token", "*")
+	params.Set("resource_types", string(typesJSON))
+
+	respBody, err := syncClient.Get(ctx, "/sync?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse sync response: %w", err)
+	}
+	return resp, nil
+}
+
+// decodeSyncObjects unmarshals a sync resource list, dropping deleted objects.
+func decodeSyncObjects(raw json.RawMessage) ([]map[string]interface{}, error) {
+	if len(raw) == 0 {
+		return []map[string]interface{}{}, nil
+	}
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(raw, &objects); err != nil {
+		return nil, err
+	}
+	live := make([]map[string]interface{}, 0, len(objects))
+	for _, obj := range objects {
+		if deleted, _ := obj["is_deleted"].(bool); deleted {
+			continue
+		}
+		live = append(live, obj)
+	}
+	return live, nil
+}
+
+// decodeSyncTasks unmarshals a sync items list, dropping deleted tasks.
+func decodeSyncTasks(raw json.RawMessage) ([]models.Task, error) {
+	if len(raw) == 0 {
+		return []models.Task{}, nil
+	}
+	var tasks []models.Task
+	if err := json.Unmarshal(raw, &tasks); err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(tasks, func(task models.Task) bool { return task.IsDeleted }), nil
+}
+
+// decodeSyncProjects unmarshals a sync projects list, dropping deleted
+// projects.
+func decodeSyncProjects(raw json.RawMessage) ([]models.Project, error) {
+	if len(raw) == 0 {
+		return []models.Project{}, nil
+	}
+	var projects []models.Project
+	if err := json.Unmarshal(raw, &projects); err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(projects, func(project models.Project) bool { return project.IsDeleted }), nil
+}
+
+// fetchFilters reads the user's saved filters. Filters are not exposed by the
+// REST API, so they are read through a full sync request.
+func fetchFilters(ctx context.Context, syncClient todoist.SyncAPI) ([]map[string]interface{}, error) {
+	resources, err := fetchSyncResources(ctx, syncClient, "filters")
+	if err != nil {
+		return nil, err
+	}
+	filters, err := decodeSyncObjects(resources["filters"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse filters: %w", err)
+	}
+	return filters, nil
+}
+
+// favoriteEntry trims a project, label, or filter down to the fields shown in the sidebar.
+func favoriteEntry(entity map[string]interface{}) map[string]interface{} {
+	entry := map[string]interface{}{
+		"id":   entity["id"],
+		"name": entity["name"],
+	}
+	if color, ok := entity["color"]; ok {
+		entry["color"] = color
+	}
+	if query, ok := entity["query"]; ok {
+		entry["query"] = query
+	}
+	return entry
+}
+
+// ListFavoritesHandler creates a handler for listing favorite projects, labels, and filters.
+func ListFavoritesHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectsBody, err := client.Get(ctx, "/projects")
+		if err != nil {
+			return respond.Errorf("failed to fetch projects: %v", err), nil
+		}
+		var projects []map[string]interface{}
+		if err := json.Unmarshal(projectsBody, &projects); err != nil {
+			return respond.Errorf("failed to parse projects: %v", err), nil
+		}
+
+		labelsBody, err := client.Get(ctx, "/labels")
+		if err != nil {
+			return respond.Errorf("failed to fetch labels: %v", err), nil
+		}
+		var labels []map[string]interface{}
+		if err := json.Unmarshal(labelsBody, &labels); err != nil {
+			return respond.Errorf("failed to parse labels: %v", err), nil
+		}
+
+		filters, err := fetchFilters(ctx, syncClient)
+		if err != nil {
+			return respond.Errorf("failed to fetch filters: %v", err), nil
+		}
+
+		favorites := map[string][]map[string]interface{}{
+			"projects": {},
+			"labels":   {},
+			"filters":  {},
+		}
+		for kind, entities := range map[string][]map[string]interface{}{
+			"projects": projects,
+			"labels":   labels,
+			"filters":  filters,
+		} {
+			for _, e := range entities {
+				if fav, _ := e["is_favorite"].(bool); fav {
+					favorites[kind] = append(favorites[kind], favoriteEntry(e))
+				}
+			}
+		}
+
+		response := map[string]interface{}{
+			"projects": favorites["projects"],
+			"labels":   favorites["labels"],
+			"filters":  favorites["filters"],
+			"count":    len(favorites["projects"]) + len(favorites["labels"]) + len(favorites["filters"]),
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// SetFavoriteHandler creates a handler for marking or unmarking projects, labels, and filters as favorites.
+func SetFavoriteHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		itemsParam, ok := args["items"].([]interface{})
+		if !ok || len(itemsParam) == 0 {
+			return respond.Error("items array is required and must not be empty"), nil
+		}
+		if len(itemsParam) > 100 {
+			return respond.Error("maximum 100 items per batch"), nil
+		}
+
+		isFavorite := true
+		if fav, ok := args["is_favorite"].(bool); ok {
+			isFavorite = fav
+		}
+
+		commands := make([]todoist.Command, 0, len(itemsParam))
+		for i, item := range itemsParam {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				return respond.Errorf("item at index %d is not a valid object", i), nil
+			}
+			entityType, _ := itemMap["type"].(string)
+			cmdType, ok := favoriteCommandTypes[entityType]
+			if !ok {
+				return respond.Errorf("item at index %d has invalid type %q (use project, label, or filter)", i, entityType), nil
+			}
+			id, _ := itemMap["id"].(string)
+			if id == "" {
+				return respond.Errorf("item at index %d missing required 'id' field", i), nil
+			}
+			if err := ValidateID(id, "id"); err != nil {
+				return respond.Errorf("item at index %d: %v", i, err), nil
+			}
+			commands = append(commands, todoist.Command{
+				Type: cmdType,
+				UUID: todoist.GenerateUUID(),
+				Args: map[string]interface{}{"id": id, "is_favorite": isFavorite},
+			})
+		}
+
+		syncResp, err := syncClient.BatchCommands(ctx, commands)
+		if err != nil {
+			return respond.Errorf("failed to update favorites: %v", err), nil
+		}
+
+		succeeded := 0
+		failed := make([]map[string]interface{}, 0)
+		for i, cmd := range commands {
+			if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+				succeeded++
+			} else {
+				entityType, _ := itemsParam[i].(map[string]interface{})["type"].(string)
+				failed = append(failed, map[string]interface{}{
+					"type":  entityType,
+					"id":    cmd.Args["id"],
+					"error": fmt.Sprintf("%v", syncResp.SyncStatus[cmd.UUID]),
+				})
+			}
+		}
+
+		response := map[string]interface{}{
+			"is_favorite": isFavorite,
+			"succeeded":   succeeded,
+			"failed":      failed,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
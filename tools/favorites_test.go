@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestListFavoritesHandler(t *testing.T) {
+	mockGet := func(_ context.Context, path string) ([]byte, error) {
+		switch path {
+		case "/projects":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "p1", "name": "Work", "color": "blue", "is_favorite": true},
+				{"id": "p2", "name": "Home", "color": "green"},
+			})
+		case "/labels":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "l1", "name": "urgent", "color": "red", "is_favorite": true},
+			})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}
+
+	tests := []struct {
+		name      string
+		syncGet   func(ctx context.Context, path string) ([]byte, error)
+		wantErr   bool
+		errSubstr string
+		wantCount int
+	}{
+		{
+			name: "collects favorites across entity types",
+			syncGet: func(_ context.Context, path string) ([]byte, error) {
+				if !strings.HasPrefix(path, "/sync?") || !strings.Contains(path, "filters") {
+					return nil, fmt.Errorf("unexpected path: %s", path)
+				}
+				return json.Marshal(map[string]interface{}{
+					"filters": []map[string]interface{}{
+						{"id": "f1", "name": "Today P1", "query": "today & p1", "is_favorite": true},
+						{"id": "f2", "name": "Gone", "query": "#Old", "is_favorite": true, "is_deleted": true},
+						{"id": "f3", "name": "Waiting", "query": "@waiting"},
+					},
+				})
+			},
+			wantCount: 3,
+		},
+		{
+			name: "filter fetch error",
+			syncGet: func(_ context.Context, _ string) ([]byte, error) {
+				return nil, fmt.Errorf("forbidden")
+			},
+			wantErr:   true,
+			errSubstr: "failed to fetch filters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockAPI{GetFn: mockGet}
+			syncClient := &MockSyncAPI{GetFn: tt.syncGet}
+			handler := ListFavoritesHandler(client, syncClient)
+			result, err := handler(context.Background(), makeReq(nil))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if int(resp["count"].(float64)) != tt.wantCount {
+				t.Errorf("count = %v, want %d", resp["count"], tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestSetFavoriteHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantErr   bool
+		errSubstr string
+		wantTypes []string
+	}{
+		{
+			name: "mixed entity types",
+			args: map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"type": "project", "id": "p1"},
+				map[string]interface{}{"type": "label", "id": "l1"},
+				map[string]interface{}{"type": "filter", "id": "f1"},
+			}},
+			wantTypes: []string{"project_update", "label_update", "filter_update"},
+		},
+		{
+			name:      "missing items",
+			args:      map[string]interface{}{},
+			wantErr:   true,
+			errSubstr: "items array is required",
+		},
+		{
+			name: "invalid type",
+			args: map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"type": "section", "id": "s1"},
+			}},
+			wantErr:   true,
+			errSubstr: "invalid type",
+		},
+		{
+			name: "missing id",
+			args: map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"type": "project"},
+			}},
+			wantErr:   true,
+			errSubstr: "missing required 'id' field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []todoist.Command
+			syncClient := &MockSyncAPI{
+				BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+					sent = commands
+					status := make(map[string]interface{})
+					for _, c := range commands {
+						status[c.UUID] = "ok"
+					}
+					return &todoist.SyncResponse{SyncStatus: status}, nil
+				},
+			}
+			handler := SetFavoriteHandler(syncClient)
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if len(sent) != len(tt.wantTypes) {
+				t.Fatalf("sent %d commands, want %d", len(sent), len(tt.wantTypes))
+			}
+			for i, want := range tt.wantTypes {
+				if sent[i].Type != want {
+					t.Errorf("command[%d] = %s, want %s", i, sent[i].Type, want)
+				}
+				if sent[i].Args["is_favorite"] != true {
+					t.Errorf("command[%d] is_favorite = %v, want true", i, sent[i].Args["is_favorite"])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// focusSession is a set of tasks picked by start_focus_session.
+type focusSession struct {
+	id        string
+	filter    string
+	label     string
+	tasks     []models.Task
+	startedAt time.Time
+}
+
+// FocusStore holds focus sessions between start_focus_session and
+// end_focus_session. Sessions that are never ended expire after the store's TTL.
+type FocusStore struct {
+	mu       sync.Mutex
+	sessions map[string]focusSession
+	ttl      time.Duration
+}
+
+// NewFocusStore creates a focus session store whose sessions expire after ttl.
+func NewFocusStore(ttl time.Duration) *FocusStore {
+	return &FocusStore{sessions: make(map[string]focusSession), ttl: ttl}
+}
+
+// start stores a new session and returns its ID.
+func (fs *FocusStore) start(session focusSession) string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	for id, s := range fs.sessions {
+		if time.Since(s.startedAt) > fs.ttl {
+			delete(fs.sessions, id)
+		}
+	}
+
+	session.id = todoist.GenerateUUID()
+	fs.sessions[session.id] = session
+	return session.id
+}
+
+// end removes and returns the session with the given ID, or the most recently
+// started session when id is empty.
+func (fs *FocusStore) end(id string) (focusSession, bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if id == "" {
+		for sid, s := range fs.sessions {
+			if id == "" || s.startedAt.After(fs.sessions[id].startedAt) {
+				id = sid
+			}
+		}
+	}
+	session, ok := fs.sessions[id]
+	if !ok {
+		return focusSession{}, false
+	}
+	delete(fs.sessions, id)
+	if time.Since(session.startedAt) > fs.ttl {
+		return focusSession{}, false
+	}
+	return session, true
+}
+
+// focusScore ranks a task for a focus session. Each priority level is worth 10
+// points; a deadline within a week adds up to 30 and a due date of today or
+// earlier adds 5, so an imminent deadline can lift a task above one with
+// higher priority.
+func focusScore(task models.Task, today string) int {
+	score := task.Priority * 10
+
+	if deadline := task.Deadline.Day(); deadline != "" {
+		switch days := daysBetween(today, deadline); {
+		case days < 0:
+			score += 30
+		case days <= 1:
+			score += 25
+		case days <= 3:
+			score += 15
+		case days <= 7:
+			score += 8
+		}
+	}
+	if due := task.Due.Day(); due != "" && due <= today {
+		score += 5
+	}
+	return score
+}
+
+// focusLabelOps builds label updates for tasks, adding or removing label.
+func focusLabelOps(tasks []models.Task, label string, add bool) []todoist.BulkOperation {
+	ops := make([]todoist.BulkOperation, 0, len(tasks))
+	for _, task := range tasks {
+		taskID := task.ID
+		labels := make([]string, 0)
+		for _, name := range task.Labels {
+			if !strings.EqualFold(name, label) {
+				labels = append(labels, name)
+			}
+		}
+		if add {
+			labels = append(labels, label)
+		}
+		ops = append(ops, todoist.BulkOperation{
+			ID: taskID,
+			Command: todoist.Command{
+				Type: "item_update",
+				UUID: todoist.GenerateUUID(),
+				Args: map[string]interface{}{"id": taskID, "labels": labels},
+			},
+			Method: http.MethodPost,
+			Path:   fmt.Sprintf("/tasks/%s", taskID),
+			Body:   map[string]interface{}{"labels": labels},
+		})
+	}
+	return ops
+}
+
+// focusSummary trims a task down to the fields shown in focus session results.
+func focusSummary(task models.Task) map[string]interface{} {
+	summary := map[string]interface{}{
+		"id":       task.ID,
+		"content":  task.Content,
+		"priority": task.Priority,
+	}
+	if due := task.Due.Day(); due != "" {
+		summary["due"] = due
+	}
+	if deadline := task.Deadline.Day(); deadline != "" {
+		summary["deadline"] = deadline
+	}
+	return summary
+}
+
+// StartFocusSessionHandler creates a handler that picks the most urgent tasks
+// for a filter and remembers them as a focus session.
+func StartFocusSessionHandler(client todoist.API, syncClient todoist.SyncAPI, store *FocusStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		filter := "today | overdue"
+		if f, ok := args["filter"].(string); ok && strings.TrimSpace(f) != "" {
+			filter = strings.TrimSpace(f)
+		}
+		count := 3
+		if c, ok := args["count"].(float64); ok {
+			if c < 1 || c > 10 {
+				return respond.Error("count must be between 1 and 10"), nil
+			}
+			count = int(c)
+		}
+		addLabel, _ := args["add_label"].(bool)
+		label := "focus"
+		if l, ok := args["label"].(string); ok && strings.TrimSpace(l) != "" {
+			label = strings.TrimPrefix(strings.TrimSpace(l), "@")
+		}
+
+		tasks, err := resolveFilterTasks(ctx, client, filter)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		if len(tasks) == 0 {
+			return respond.Errorf("no tasks match filter %q", filter), nil
+		}
+
+		today := time.Now().Format("2006-01-02")
+		sort.SliceStable(tasks, func(i, j int) bool {
+			if a, b := focusScore(tasks[i], today), focusScore(tasks[j], today); a != b {
+				return a > b
+			}
+			dueA, dueB := tasks[i].Due.Day(), tasks[j].Due.Day()
+			return dueA != "" && (dueB == "" || dueA < dueB)
+		})
+		picked := tasks[:min(count, len(tasks))]
+
+		session := focusSession{filter: filter, tasks: picked, startedAt: time.Now()}
+		response := map[string]interface{}{
+			"filter":  filter,
+			"matched": len(tasks),
+		}
+		if addLabel {
+			result, err := todoist.NewBulkExecutor(client, syncClient).Execute(ctx, focusLabelOps(picked, label, true))
+			if err != nil {
+				return respond.Errorf("failed to label focus tasks: %v", err), nil
+			}
+			session.label = label
+			response["label"] = label
+			response["labeled"] = len(result.Succeeded)
+			response["label_failed"] = result.Failed
+		}
+
+		summaries := make([]map[string]interface{}, len(picked))
+		for i, task := range picked {
+			summaries[i] = focusSummary(task)
+			summaries[i]["score"] = focusScore(task, today)
+		}
+		response["session_id"] = store.start(session)
+		response["started_at"] = session.startedAt.UTC().Format(time.RFC3339)
+		response["tasks"] = summaries
+
+		return respond.JSON(response), nil
+	}
+}
+
+// EndFocusSessionHandler creates a handler that closes a focus session and
+// reports which of its tasks were completed while it ran.
+func EndFocusSessionHandler(client todoist.API, syncClient todoist.SyncAPI, store *FocusStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		sessionID, _ := args["session_id"].(string)
+		session, ok := store.end(sessionID)
+		if !ok {
+			if sessionID == "" {
+				return respond.Error("no active focus session; start one with start_focus_session"), nil
+			}
+			return respond.Errorf("focus session %s not found or expired", sessionID), nil
+		}
+		endedAt := time.Now()
+
+		items, truncated, err := fetchCompletedTasks(ctx, syncClient, session.startedAt, endedAt, nil)
+		if err != nil {
+			return respond.Errorf("failed to fetch completed tasks: %v", err), nil
+		}
+		completedAt := make(map[string]string)
+		var others []map[string]interface{}
+		var sessionIDs []string
+		for _, task := range session.tasks {
+			sessionIDs = append(sessionIDs, task.ID)
+		}
+		for _, item := range items {
+			at := item.CompletedAt
+			id := completedTaskID(item)
+			if slices.Contains(sessionIDs, id) {
+				completedAt[id] = at
+				continue
+			}
+			others = append(others, map[string]interface{}{
+				"id":           id,
+				"content":      item.Content,
+				"completed_at": at,
+			})
+		}
+
+		completed := make([]map[string]interface{}, 0)
+		remaining := make([]map[string]interface{}, 0)
+		for _, task := range session.tasks {
+			summary := focusSummary(task)
+			if at, ok := completedAt[task.ID]; ok {
+				summary["completed_at"] = at
+				completed = append(completed, summary)
+			} else {
+				remaining = append(remaining, summary)
+			}
+		}
+		if others == nil {
+			others = make([]map[string]interface{}, 0)
+		}
+
+		response := respond.Envelope{
+			"session_id":       session.id,
+			"filter":           session.filter,
+			"started_at":       session.startedAt.UTC().Format(time.RFC3339),
+			"ended_at":         endedAt.UTC().Format(time.RFC3339),
+			"duration_minutes": int(endedAt.Sub(session.startedAt).Round(time.Minute) / time.Minute),
+			"completed":        completed,
+			"not_completed":    remaining,
+			"also_completed":   others,
+			"completion_rate":  float64(len(completed)) / float64(len(session.tasks)),
+		}
+		warnCompletedTruncated(response, truncated)
+
+		// Take the focus label off tasks that are still open; completed ones
+		// keep it in the archive, which is harmless.
+		if session.label != "" {
+			labeled, err := resolveFilterTasks(ctx, client, "@"+session.label)
+			if err != nil {
+				return respond.Errorf("failed to find labeled tasks: %v", err), nil
+			}
+			var open []models.Task
+			for _, task := range labeled {
+				if slices.Contains(sessionIDs, task.ID) {
+					open = append(open, task)
+				}
+			}
+			result, err := todoist.NewBulkExecutor(client, syncClient).Execute(ctx, focusLabelOps(open, session.label, false))
+			if err != nil {
+				return respond.Errorf("failed to remove focus label: %v", err), nil
+			}
+			response["unlabeled"] = len(result.Succeeded)
+			response["unlabel_failed"] = result.Failed
+		}
+
+		return respond.JSON(response), nil
+	}
+}
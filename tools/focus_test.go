@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+)
+
+func TestFocusScore(t *testing.T) {
+	today := "2026-03-10"
+	tests := []struct {
+		name string
+		task models.Task
+		want int
+	}{
+		{"p1 without dates", models.Task{Priority: 4}, 40},
+		{"p3 with deadline tomorrow", models.Task{Priority: 2, Deadline: &models.Deadline{Date: "2026-03-11"}}, 45},
+		{"p4 overdue", models.Task{Priority: 1, Due: &models.Due{Date: "2026-03-09"}}, 15},
+		{"deadline passed", models.Task{Priority: 1, Deadline: &models.Deadline{Date: "2026-03-01"}}, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := focusScore(tt.task, today); got != tt.want {
+				t.Errorf("focusScore() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFocusSession(t *testing.T) {
+	var filters []string
+	var posted []string
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if !strings.HasPrefix(path, "/tasks?filter=") {
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			}
+			filters = append(filters, path)
+			if strings.Contains(path, "focus") {
+				return json.Marshal([]map[string]interface{}{
+					{"id": "t2", "content": "Write report", "labels": []string{"work", "focus"}},
+				})
+			}
+			return json.Marshal([]map[string]interface{}{
+				{"id": "t1", "content": "Water plants", "priority": float64(1)},
+				{"id": "t2", "content": "Write report", "priority": float64(3), "labels": []string{"work"}},
+				{"id": "t3", "content": "Pay invoice", "priority": float64(2), "deadline": map[string]interface{}{"date": time.Now().Format("2006-01-02")}},
+			})
+		},
+		PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+			labels := body.(map[string]interface{})["labels"].([]string)
+			posted = append(posted, fmt.Sprintf("%s %s", path, strings.Join(labels, ",")))
+			return []byte(`{}`), nil
+		},
+	}
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		return json.Marshal(map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"id": "t3", "content": "Pay invoice", "completed_at": time.Now().UTC().Format(time.RFC3339)},
+				{"id": "t9", "content": "Reply to Sam", "completed_at": time.Now().UTC().Format(time.RFC3339)},
+			},
+			"next_cursor": nil,
+		})
+	}}
+	store := NewFocusStore(time.Hour)
+
+	result, err := StartFocusSessionHandler(client, syncClient, store)(context.Background(), makeReq(map[string]interface{}{
+		"filter":    "#Work",
+		"count":     float64(2),
+		"add_label": true,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("start_focus_session failed: %v %s", err, resultText(result))
+	}
+	var started struct {
+		SessionID string                   `json:"session_id"`
+		Tasks     []map[string]interface{} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &started); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(started.Tasks) != 2 || started.Tasks[0]["id"] != "t3" || started.Tasks[1]["id"] != "t2" {
+		t.Fatalf("picked tasks = %v, want t3 then t2", started.Tasks)
+	}
+	if strings.Join(posted, "; ") != "/tasks/t3 focus; /tasks/t2 work,focus" {
+		t.Errorf("label updates = %v", posted)
+	}
+
+	posted = nil
+	result, err = EndFocusSessionHandler(client, syncClient, store)(context.Background(), makeReq(map[string]interface{}{}))
+	if err != nil || result.IsError {
+		t.Fatalf("end_focus_session failed: %v %s", err, resultText(result))
+	}
+	var ended struct {
+		SessionID     string                   `json:"session_id"`
+		Completed     []map[string]interface{} `json:"completed"`
+		NotCompleted  []map[string]interface{} `json:"not_completed"`
+		AlsoCompleted []map[string]interface{} `json:"also_completed"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &ended); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if ended.SessionID != started.SessionID {
+		t.Errorf("ended session %q, want %q", ended.SessionID, started.SessionID)
+	}
+	if len(ended.Completed) != 1 || ended.Completed[0]["id"] != "t3" {
+		t.Errorf("completed = %v, want t3", ended.Completed)
+	}
+	if len(ended.NotCompleted) != 1 || ended.NotCompleted[0]["id"] != "t2" {
+		t.Errorf("not_completed = %v, want t2", ended.NotCompleted)
+	}
+	if len(ended.AlsoCompleted) != 1 || ended.AlsoCompleted[0]["id"] != "t9" {
+		t.Errorf("also_completed = %v, want t9", ended.AlsoCompleted)
+	}
+	if strings.Join(posted, "; ") != "/tasks/t2 work" {
+		t.Errorf("label removals = %v", posted)
+	}
+
+	result, _ = EndFocusSessionHandler(client, syncClient, store)(context.Background(), makeReq(map[string]interface{}{}))
+	if !result.IsError || !strings.Contains(resultText(result), "no active focus session") {
+		t.Errorf("ending twice should fail, got %s", resultText(result))
+	}
+}
@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// syncResourceTypes are the resource types the Sync API accepts in
+// resource_types, besides "all".
+var syncResourceTypes = []string{
+	"calendar_accounts", "calendars", "collaborators", "completed_info", "filters",
+	"folders", "items", "labels", "live_notifications", "locations",
+	"notification_settings", "notes", "project_notes", "projects", "reminders",
+	"reminders_location", "sections", "stats", "user", "user_plan_limits",
+	"user_settings", "view_options", "workspace_users", "workspaces",
+}
+
+// userCredentialFields are fields of the Sync user object that carry the
+// account's API token. full_sync removes them so that the credential never
+// reaches the model.
+var userCredentialFields = []string{"token", "websocket_url"}
+
+// redactUser returns the Sync user object without its credential fields.
+func redactUser(raw json.RawMessage) (json.RawMessage, error) {
+	var user map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &user); err != nil {
+		return nil, err
+	}
+	for _, field := range userCredentialFields {
+		delete(user, field)
+	}
+	return json.Marshal(user)
+}
+
+// syncResourceTypesArg reads the resource_types argument, defaulting to all.
+// A type prefixed with "-" excludes it, e.g. ["all", "-notes"].
+func syncResourceTypesArg(args map[string]interface{}) ([]string, error) {
+	raw, _ := args["resource_types"].([]interface{})
+	types := make([]string, 0, len(raw))
+	for _, v := range raw {
+		name, ok := v.(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("resource_types must be an array of strings")
+		}
+		if name != "all" && !slices.Contains(syncResourceTypes, strings.TrimPrefix(name, "-")) {
+			return nil, fmt.Errorf("unknown resource type %q (available: all, %s)", name, strings.Join(syncResourceTypes, ", "))
+		}
+		if !slices.Contains(types, name) {
+			types = append(types, name)
+		}
+	}
+	if len(types) == 0 {
+		types = append(types, "all")
+	}
+	return types, nil
+}
+
+// FullSyncHandler creates a handler that returns the Sync API payload for the
+// requested resource types in one request: a full sync, or with a sync_token
+// from an earlier call, only what changed since. One request replaces the
+// many REST listings needed to read the same data.
+func FullSyncHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		types, err := syncResourceTypesArg(args)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		syncToken, _ := args["sync_token"].(string)
+		if syncToken == "" {
+			syncToken = "*"
+		}
+		if strings.ContainsAny(syncToken, " \t\r\n") {
+			return respond.Error("sync_token must be a token returned by an earlier full_sync"), nil
+		}
+		typesJSON, err := json.Marshal(types)
+		if err != nil {
+			return respond.Errorf("failed to encode resource types: %v", err), nil
+		}
+
+		params := url.Values{}
+		params.Set("sync_token", syncToken)
+		params.Set("resource_types", string(typesJSON))
+		respBody, err := syncClient.Get(ctx, "/sync?"+params.Encode())
+		if err != nil {
+			return respond.Errorf("failed to sync: %v", err), nil
+		}
+
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(respBody, &payload); err != nil {
+			return respond.Errorf("failed to parse sync response: %v", err), nil
+		}
+
+		// Counting the objects of each list resource lets a caller see the
+		// size of the payload without walking it.
+		counts := make(map[string]int)
+		response := respond.Envelope{}
+		for name, raw := range payload {
+			if name == "user" && string(raw) != "null" {
+				if raw, err = redactUser(raw); err != nil {
+					return respond.Errorf("failed to parse sync user: %v", err), nil
+				}
+			}
+			var items []json.RawMessage
+			if json.Unmarshal(raw, &items) == nil {
+				counts[name] = len(items)
+			}
+			response.Set(name, raw)
+		}
+		response.Set("resource_types", types).Set("counts", counts)
+		if len(counts) == 0 && syncToken == "*" {
+			response.Warn("the sync response contained no resources")
+		}
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestFullSyncHandler(t *testing.T) {
+	var gotPath string
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		gotPath = path
+		return []byte(`{"sync_token":"abc","full_sync":true,"projects":[{"id":"1"},{"id":"2"}],"items":[{"id":"10"}],"user":{"id":"u","token":"secret","websocket_url":"wss://ws.todoist.com/ws?token=secret"}}`), nil
+	}}
+
+	result, err := FullSyncHandler(syncClient)(context.Background(), makeReq(map[string]interface{}{
+		"resource_types": []interface{}{"projects", "items", "user", "projects"},
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+
+	path, query, _ := strings.Cut(gotPath, "?")
+	values, _ := url.ParseQuery(query)
+	if path != "/sync" || values.Get("sync_token") != "*" || values.Get("resource_types") != `["projects","items","user"]` {
+		t.Errorf("request = %s", gotPath)
+	}
+
+	var resp struct {
+		SyncToken string                   `json:"sync_token"`
+		Projects  []map[string]interface{} `json:"projects"`
+		User      map[string]interface{}   `json:"user"`
+		Counts    map[string]int           `json:"counts"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.SyncToken != "abc" || len(resp.Projects) != 2 || resp.User["id"] != "u" {
+		t.Errorf("payload not passed through: %s", resultText(result))
+	}
+	if strings.Contains(resultText(result), "secret") {
+		t.Errorf("user credentials not redacted: %s", resultText(result))
+	}
+	if resp.Counts["projects"] != 2 || resp.Counts["items"] != 1 || len(resp.Counts) != 2 {
+		t.Errorf("counts = %v", resp.Counts)
+	}
+}
+
+func TestFullSyncHandler_Incremental(t *testing.T) {
+	var gotPath string
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		gotPath = path
+		return []byte(`{"sync_token":"def","full_sync":false,"items":[]}`), nil
+	}}
+
+	result, _ := FullSyncHandler(syncClient)(context.Background(), makeReq(map[string]interface{}{"sync_token": "abc"}))
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(result))
+	}
+	values, _ := url.ParseQuery(strings.SplitN(gotPath, "?", 2)[1])
+	if values.Get("sync_token") != "abc" || values.Get("resource_types") != `["all"]` {
+		t.Errorf("request = %s", gotPath)
+	}
+	if strings.Contains(resultText(result), "warnings") {
+		t.Errorf("an empty incremental sync should not warn: %s", resultText(result))
+	}
+}
+
+func TestFullSyncHandler_Validation(t *testing.T) {
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		t.Fatalf("unexpected request: %s", path)
+		return nil, nil
+	}}
+	tests := []map[string]interface{}{
+		{"resource_types": []interface{}{"tasks"}},
+		{"resource_types": []interface{}{"-bogus"}},
+		{"resource_types": []interface{}{42}},
+		{"sync_token": "a b"},
+	}
+	for _, args := range tests {
+		result, _ := FullSyncHandler(syncClient)(context.Background(), makeReq(args))
+		if !result.IsError {
+			t.Errorf("args %v: expected an error, got %s", args, resultText(result))
+		}
+	}
+}
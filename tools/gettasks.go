@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+const (
+	// maxGetTasks caps the IDs of one get_tasks call.
+	maxGetTasks = 500
+	// getTasksChunk is how many IDs go into one request, keeping URLs short.
+	getTasksChunk = 50
+	// getTasksParallel is how many chunks are fetched at once.
+	getTasksParallel = 4
+)
+
+// fetchTasksByIDs fetches active tasks by ID, chunking the ids parameter and
+// fetching chunks concurrently. IDs without an active task are left out.
+func fetchTasksByIDs(ctx context.Context, client todoist.API, ids []string) (map[string]models.Task, error) {
+	chunks := slices.Collect(slices.Chunk(ids, getTasksChunk))
+	results := make([][]models.Task, len(chunks))
+	errs := make([]error, len(chunks))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, getTasksParallel)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			params := url.Values{}
+			params.Set("ids", strings.Join(chunk, ","))
+			results[i], errs[i] = fetchTasks(ctx, client, params)
+		}()
+	}
+	wg.Wait()
+
+	found := make(map[string]models.Task, len(ids))
+	for i := range chunks {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		for _, task := range results[i] {
+			found[task.ID] = task
+		}
+	}
+	return found, nil
+}
+
+// GetTasksHandler creates a handler that fetches many tasks by ID in as few
+// requests as possible, returning them keyed by ID.
+func GetTasksHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		var ids []string
+		for _, id := range taskIDsArg(args) {
+			if err := ValidateID(id, "task_ids"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return respond.Error("task_ids is required"), nil
+		}
+		if len(ids) > maxGetTasks {
+			return respond.Errorf("task_ids has %d IDs; at most %d can be fetched at once", len(ids), maxGetTasks), nil
+		}
+
+		found, err := fetchTasksByIDs(ctx, client, ids)
+		if err != nil {
+			return respond.Errorf("failed to get tasks: %v", err), nil
+		}
+
+		tasks := make(map[string]interface{}, len(ids))
+		notFound := make([]string, 0)
+		for _, id := range ids {
+			task, ok := found[id]
+			if !ok {
+				tasks[id] = map[string]interface{}{"id": id, "not_found": true}
+				notFound = append(notFound, id)
+				continue
+			}
+			task.Set("is_timed", task.IsTimed())
+			tasks[id] = task
+		}
+		response := respond.List("tasks", tasks).
+			Set("found", len(ids)-len(notFound)).
+			Set("not_found", notFound)
+		if len(notFound) > 0 {
+			response.Warn("%d of %d tasks were not found; they may be completed, deleted, or in a project you cannot access", len(notFound), len(ids))
+		}
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGetTasksHandler(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	mock := &MockAPI{
+		GetFn: func(ctx context.Context, path string) ([]byte, error) {
+			mu.Lock()
+			requests = append(requests, path)
+			mu.Unlock()
+			u, _ := url.Parse(path)
+			var tasks []map[string]interface{}
+			for _, id := range strings.Split(u.Query().Get("ids"), ",") {
+				if id != "404" {
+					tasks = append(tasks, map[string]interface{}{"id": id, "content": "Task " + id})
+				}
+			}
+			return json.Marshal(tasks)
+		},
+	}
+
+	ids := []interface{}{"404"}
+	for i := 1; i <= 120; i++ {
+		ids = append(ids, fmt.Sprint(i))
+	}
+	ids = append(ids, "1")
+	result, err := GetTasksHandler(mock)(context.Background(), makeReq(map[string]interface{}{"task_ids": ids}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	if len(requests) != 3 {
+		t.Errorf("expected 3 chunked requests for 121 IDs, got %d", len(requests))
+	}
+
+	var resp struct {
+		Count    int                               `json:"count"`
+		Found    int                               `json:"found"`
+		NotFound []string                          `json:"not_found"`
+		Tasks    map[string]map[string]interface{} `json:"tasks"`
+		Warnings []string                          `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Count != 121 || resp.Found != 120 {
+		t.Errorf("count = %d, found = %d, want 121 and 120", resp.Count, resp.Found)
+	}
+	if len(resp.NotFound) != 1 || resp.NotFound[0] != "404" || resp.Tasks["404"]["not_found"] != true {
+		t.Errorf("expected 404 marked not found, got %v %v", resp.NotFound, resp.Tasks["404"])
+	}
+	if resp.Tasks["57"]["content"] != "Task 57" || resp.Tasks["57"]["is_timed"] != false {
+		t.Errorf("unexpected task 57: %v", resp.Tasks["57"])
+	}
+	if len(resp.Warnings) != 1 {
+		t.Errorf("expected a not-found warning, got %v", resp.Warnings)
+	}
+}
+
+func TestGetTasksHandler_Validation(t *testing.T) {
+	tooMany := make([]interface{}, maxGetTasks+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprint(i + 1)
+	}
+	for _, args := range []map[string]interface{}{
+		{},
+		{"task_ids": []interface{}{}},
+		{"task_ids": []interface{}{"1", "bad/id"}},
+		{"task_ids": tooMany},
+	} {
+		result, _ := GetTasksHandler(&MockAPI{})(context.Background(), makeReq(args))
+		if !result.IsError {
+			t.Errorf("args %v: expected error", args["task_ids"])
+		}
+	}
+}
+
+func TestGetTasksHandler_APIError(t *testing.T) {
+	mock := &MockAPI{GetFn: func(ctx context.Context, path string) ([]byte, error) {
+		return nil, fmt.Errorf("boom")
+	}}
+	result, _ := GetTasksHandler(mock)(context.Background(), makeReq(map[string]interface{}{"task_ids": []interface{}{"1"}}))
+	if !result.IsError || !strings.Contains(resultText(result), "failed to get tasks") {
+		t.Errorf("expected API error, got %s", resultText(result))
+	}
+}
@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// githubIssueLabel is the label applied to tasks linked to GitHub issues.
+const githubIssueLabel = "github"
+
+// githubIssueRefPattern matches issue references such as "owner/repo#123".
+var githubIssueRefPattern = regexp.MustCompile(`^([A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)/([A-Za-z0-9._-]+)#([0-9]+)$`)
+
+// githubIssueURL parses an issue reference and returns its canonical form and URL.
+func githubIssueURL(ref string) (string, string, error) {
+	m := githubIssueRefPattern.FindStringSubmatch(strings.TrimSpace(ref))
+	if m == nil {
+		return "", "", fmt.Errorf("issue must be in owner/repo#number format, e.g. golang/go#12345")
+	}
+	canonical := fmt.Sprintf("%s/%s#%s", m[1], m[2], m[3])
+	return canonical, fmt.Sprintf("https://github.com/%s/%s/issues/%s", m[1], m[2], m[3]), nil
+}
+
+// githubIssueContent formats task content for a linked issue.
+func githubIssueContent(ref, issueURL, title string) string {
+	return fmt.Sprintf("[%s](%s) %s", ref, issueURL, title)
+}
+
+// LinkGitHubIssueHandler creates a handler that creates or updates a task mirroring a GitHub issue.
+func LinkGitHubIssueHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		issue, _ := args["issue"].(string)
+		ref, issueURL, err := githubIssueURL(issue)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		title, _ := args["title"].(string)
+		title = strings.TrimSpace(title)
+		state, _ := args["state"].(string)
+		if state != "" && state != "open" && state != "closed" {
+			return respond.Error("state must be 'open' or 'closed'"), nil
+		}
+		projectID, _ := args["project_id"].(string)
+		if projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+
+		// Linked tasks carry the github label and the issue URL in their
+		// description, which is what identifies them on later calls.
+		params := url.Values{}
+		params.Set("filter", "@"+githubIssueLabel)
+		respBody, err := client.Get(ctx, "/tasks?"+params.Encode())
+		if err != nil {
+			return respond.Errorf("failed to search linked tasks: %v", err), nil
+		}
+		var tasks []models.Task
+		if err := json.Unmarshal(respBody, &tasks); err != nil {
+			return respond.Errorf("failed to parse tasks: %v", err), nil
+		}
+		var task *models.Task
+		for i := range tasks {
+			if strings.Contains(tasks[i].Description, issueURL) {
+				task = &tasks[i]
+				break
+			}
+		}
+
+		var actions []string
+		var taskID string
+		if task == nil {
+			if state == "closed" {
+				return respond.Errorf("no open task is linked to %s; nothing to complete", ref), nil
+			}
+			if title == "" {
+				return respond.Error("title is required when no task is linked to the issue yet"), nil
+			}
+
+			body := map[string]interface{}{
+				"content":     githubIssueContent(ref, issueURL, title),
+				"description": fmt.Sprintf("GitHub issue: %s", issueURL),
+				"labels":      []string{githubIssueLabel},
+			}
+			if projectID != "" {
+				body["project_id"] = projectID
+			}
+			respBody, err := client.Post(ctx, "/tasks", body)
+			if err != nil {
+				return respond.Errorf("failed to create task: %v", err), nil
+			}
+			var created models.Task
+			if err := json.Unmarshal(respBody, &created); err != nil {
+				return respond.Errorf("failed to parse task: %v", err), nil
+			}
+			taskID = created.ID
+			actions = append(actions, "created")
+
+			comment := map[string]interface{}{
+				"task_id": taskID,
+				"content": fmt.Sprintf("Linked to GitHub issue %s\n%s", ref, issueURL),
+			}
+			if _, err := client.Post(ctx, "/comments", comment); err != nil {
+				return respond.Errorf("task %s created but failed to add link comment: %v", taskID, err), nil
+			}
+		} else {
+			taskID = task.ID
+			if title != "" {
+				content := githubIssueContent(ref, issueURL, title)
+				if task.Content != content {
+					path := fmt.Sprintf("/tasks/%s", taskID)
+					if _, err := client.Post(ctx, path, map[string]interface{}{"content": content}); err != nil {
+						return respond.Errorf("failed to update task title: %v", err), nil
+					}
+					actions = append(actions, "title_updated")
+				}
+			}
+		}
+
+		if state == "closed" {
+			path := fmt.Sprintf("/tasks/%s/close", taskID)
+			if _, err := client.Post(ctx, path, nil); err != nil {
+				return respond.Errorf("failed to complete task: %v", err), nil
+			}
+			actions = append(actions, "completed")
+		}
+		if len(actions) == 0 {
+			actions = append(actions, "unchanged")
+		}
+
+		response := map[string]interface{}{
+			"task_id": taskID,
+			"issue":   ref,
+			"url":     issueURL,
+			"actions": actions,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGitHubIssueURL(t *testing.T) {
+	tests := []struct {
+		ref     string
+		wantURL string
+		wantErr bool
+	}{
+		{ref: "golang/go#12345", wantURL: "https://github.com/golang/go/issues/12345"},
+		{ref: " acme/web.app#7 ", wantURL: "https://github.com/acme/web.app/issues/7"},
+		{ref: "golang/go", wantErr: true},
+		{ref: "go#1", wantErr: true},
+		{ref: "https://github.com/golang/go/issues/1", wantErr: true},
+	}
+	for _, tt := range tests {
+		_, got, err := githubIssueURL(tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("githubIssueURL(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			continue
+		}
+		if got != tt.wantURL {
+			t.Errorf("githubIssueURL(%q) = %q, want %q", tt.ref, got, tt.wantURL)
+		}
+	}
+}
+
+func TestLinkGitHubIssueHandler(t *testing.T) {
+	linked := []map[string]interface{}{
+		{
+			"id":          "t1",
+			"content":     "[acme/api#42](https://github.com/acme/api/issues/42) Old title",
+			"description": "GitHub issue: https://github.com/acme/api/issues/42",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		wantErr     bool
+		errSubstr   string
+		wantActions string
+		wantPosts   []string
+	}{
+		{
+			name:        "create new link",
+			args:        map[string]interface{}{"issue": "acme/api#7", "title": "Fix login", "project_id": "p1"},
+			wantActions: "created",
+			wantPosts:   []string{"/tasks", "/comments"},
+		},
+		{
+			name:        "sync title",
+			args:        map[string]interface{}{"issue": "acme/api#42", "title": "New title"},
+			wantActions: "title_updated",
+			wantPosts:   []string{"/tasks/t1"},
+		},
+		{
+			name:        "issue closed",
+			args:        map[string]interface{}{"issue": "acme/api#42", "state": "closed"},
+			wantActions: "completed",
+			wantPosts:   []string{"/tasks/t1/close"},
+		},
+		{
+			name:        "already in sync",
+			args:        map[string]interface{}{"issue": "acme/api#42", "title": "Old title"},
+			wantActions: "unchanged",
+		},
+		{
+			name:      "new link without title",
+			args:      map[string]interface{}{"issue": "acme/api#8"},
+			wantErr:   true,
+			errSubstr: "title is required",
+		},
+		{
+			name:      "invalid reference",
+			args:      map[string]interface{}{"issue": "acme#8", "title": "x"},
+			wantErr:   true,
+			errSubstr: "owner/repo#number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posts []string
+			client := &MockAPI{
+				GetFn: func(_ context.Context, path string) ([]byte, error) {
+					if !strings.HasPrefix(path, "/tasks?filter=") {
+						return nil, fmt.Errorf("unexpected path: %s", path)
+					}
+					return json.Marshal(linked)
+				},
+				PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+					posts = append(posts, path)
+					if path == "/tasks" {
+						b := body.(map[string]interface{})
+						if labels := b["labels"].([]string); labels[0] != githubIssueLabel {
+							t.Errorf("labels = %v", labels)
+						}
+						return json.Marshal(map[string]interface{}{"id": "t9"})
+					}
+					return []byte(`{}`), nil
+				},
+			}
+
+			result, err := LinkGitHubIssueHandler(client)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				Actions []string `json:"actions"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if strings.Join(resp.Actions, ",") != tt.wantActions {
+				t.Errorf("actions = %v, want %s", resp.Actions, tt.wantActions)
+			}
+			if strings.Join(posts, ",") != strings.Join(tt.wantPosts, ",") {
+				t.Errorf("posts = %v, want %v", posts, tt.wantPosts)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// ToolGroups registers tools in named groups that can be enabled and disabled
+// while the server runs. The server notifies connected clients through
+// notifications/tools/list_changed whenever the tool list changes.
+type ToolGroups struct {
+	mu      sync.Mutex
+	srv     *server.MCPServer
+	profile *Profile
+	scopes  []string
+	// excluded holds groups whose tools the profile or scopes left out entirely.
+	excluded map[string]bool
+	order    []string
+	tools    map[string][]server.ServerTool
+	disabled map[string]bool
+}
+
+// ToolGroupStatus describes one group for list and update responses.
+type ToolGroupStatus struct {
+	Name    string   `json:"name"`
+	Enabled bool     `json:"enabled"`
+	Tools   []string `json:"tools"`
+}
+
+// NewToolGroups creates a registry that adds tools to srv.
+func NewToolGroups(srv *server.MCPServer) *ToolGroups {
+	return &ToolGroups{
+		srv:      srv,
+		excluded: make(map[string]bool),
+		tools:    make(map[string][]server.ServerTool),
+		disabled: make(map[string]bool),
+	}
+}
+
+// SetProfile restricts later registrations to the tools of p and applies its
+// descriptions. It must be called before any tool is added.
+func (g *ToolGroups) SetProfile(p Profile) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.profile = &p
+}
+
+// SetScopes restricts later registrations to tools a token limited to the
+// given OAuth scopes can use. It must be called before any tool is added.
+func (g *ToolGroups) SetScopes(scopes []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.scopes = scopes
+}
+
+// allow applies the token scopes and the profile to a tool about to be
+// registered.
+func (g *ToolGroups) allow(group string, tool *mcp.Tool) bool {
+	if !scopeAllows(g.scopes, *tool) {
+		return false
+	}
+	if g.profile == nil {
+		return true
+	}
+	if !g.profile.includes(group, tool.Name) {
+		return false
+	}
+	if desc, ok := g.profile.Descriptions[tool.Name]; ok {
+		tool.Description = desc
+	}
+	return true
+}
+
+// Add registers a tool in group. It is exposed immediately unless the group
+// has been disabled, and skipped entirely if the profile excludes it.
+func (g *ToolGroups) Add(group string, tool mcp.Tool, handler server.ToolHandlerFunc) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.allow(group, &tool) {
+		if _, ok := g.tools[group]; !ok {
+			g.excluded[group] = true
+		}
+		return
+	}
+	delete(g.excluded, group)
+	if _, ok := g.tools[group]; !ok {
+		g.order = append(g.order, group)
+	}
+	st := server.ServerTool{Tool: tool, Handler: handler}
+	g.tools[group] = append(g.tools[group], st)
+	if !g.disabled[group] {
+		g.srv.AddTools(st)
+	}
+}
+
+// AddPinned registers an administrative tool outside any group, so it cannot
+// be disabled. Only profiles that include admin tools expose it.
+func (g *ToolGroups) AddPinned(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.allow("", &tool) {
+		g.srv.AddTool(tool, handler)
+	}
+}
+
+// SetEnabled enables or disables every tool in the named groups. Unknown
+// group names are rejected before anything changes.
+func (g *ToolGroups) SetEnabled(groups []string, enabled bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, name := range groups {
+		if _, ok := g.tools[name]; !ok {
+			return fmt.Errorf("unknown tool group %q (available: %s)", name, strings.Join(g.order, ", "))
+		}
+	}
+
+	var add []server.ServerTool
+	var remove []string
+	for _, name := range groups {
+		if g.disabled[name] == !enabled {
+			continue
+		}
+		g.disabled[name] = !enabled
+		for _, st := range g.tools[name] {
+			if enabled {
+				add = append(add, st)
+			} else {
+				remove = append(remove, st.Tool.Name)
+			}
+		}
+	}
+	// Each call sends one list_changed notification.
+	if len(add) > 0 {
+		g.srv.AddTools(add...)
+	}
+	if len(remove) > 0 {
+		g.srv.DeleteTools(remove...)
+	}
+	return nil
+}
+
+// Apply makes exactly the named groups disabled and all others enabled.
+// Groups the profile already excludes are ignored.
+func (g *ToolGroups) Apply(disabled []string) error {
+	g.mu.Lock()
+	disabled = slices.DeleteFunc(slices.Clone(disabled), func(name string) bool { return g.excluded[name] })
+	var enable []string
+	for _, name := range g.order {
+		if g.disabled[name] && !slices.Contains(disabled, name) {
+			enable = append(enable, name)
+		}
+	}
+	g.mu.Unlock()
+
+	if err := g.SetEnabled(disabled, false); err != nil {
+		return err
+	}
+	return g.SetEnabled(enable, true)
+}
+
+// Status lists the groups in registration order.
+func (g *ToolGroups) Status() []ToolGroupStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	status := make([]ToolGroupStatus, 0, len(g.order))
+	for _, name := range g.order {
+		names := make([]string, len(g.tools[name]))
+		for i, st := range g.tools[name] {
+			names[i] = st.Tool.Name
+		}
+		sort.Strings(names)
+		status = append(status, ToolGroupStatus{Name: name, Enabled: !g.disabled[name], Tools: names})
+	}
+	return status
+}
+
+// groupNamesArg reads an array of group names, trimmed and lowercased.
+func groupNamesArg(args map[string]interface{}, key string) []string {
+	raw, _ := args[key].([]interface{})
+	names := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+			names = append(names, strings.ToLower(strings.TrimSpace(s)))
+		}
+	}
+	return names
+}
+
+// ConfigureToolGroupsHandler creates a handler that lists the tool groups and
+// enables or disables them at runtime.
+func ConfigureToolGroupsHandler(groups *ToolGroups) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		enable := groupNamesArg(args, "enable")
+		disable := groupNamesArg(args, "disable")
+		for _, name := range enable {
+			if slices.Contains(disable, name) {
+				return respond.Errorf("group %q is in both enable and disable", name), nil
+			}
+		}
+
+		// Validate both lists before changing anything.
+		known := make(map[string]bool)
+		for _, st := range groups.Status() {
+			known[st.Name] = true
+		}
+		for _, name := range append(slices.Clone(enable), disable...) {
+			if !known[name] {
+				return respond.Errorf("unknown tool group %q", name), nil
+			}
+		}
+
+		if err := groups.SetEnabled(enable, true); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		if err := groups.SetEnabled(disable, false); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		response := map[string]interface{}{
+			"groups": groups.Status(),
+		}
+		if len(enable)+len(disable) > 0 {
+			response["message"] = "Tool groups updated; connected clients were notified that the tool list changed"
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func noopHandler(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText("ok"), nil
+}
+
+func newTestGroups() (*server.MCPServer, *ToolGroups) {
+	srv := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	groups := NewToolGroups(srv)
+	groups.Add("tasks", mcp.NewTool("create_task"), noopHandler)
+	groups.Add("tasks", mcp.NewTool("delete_task"), noopHandler)
+	groups.Add("reports", mcp.NewTool("get_monthly_report"), noopHandler)
+	return srv, groups
+}
+
+func TestToolGroups_SetEnabled(t *testing.T) {
+	srv, groups := newTestGroups()
+	if len(srv.ListTools()) != 3 {
+		t.Fatalf("got %d tools, want 3", len(srv.ListTools()))
+	}
+
+	if err := groups.SetEnabled([]string{"tasks"}, false); err != nil {
+		t.Fatalf("SetEnabled() error: %v", err)
+	}
+	if srv.GetTool("create_task") != nil || srv.GetTool("get_monthly_report") == nil {
+		t.Errorf("tools after disabling tasks = %v", srv.ListTools())
+	}
+
+	// Tools added to a disabled group stay hidden until it is enabled.
+	groups.Add("tasks", mcp.NewTool("update_task"), noopHandler)
+	if srv.GetTool("update_task") != nil {
+		t.Error("update_task should not be registered while tasks is disabled")
+	}
+
+	if err := groups.SetEnabled([]string{"tasks"}, true); err != nil {
+		t.Fatalf("SetEnabled() error: %v", err)
+	}
+	if len(srv.ListTools()) != 4 {
+		t.Errorf("got %d tools after re-enabling, want 4", len(srv.ListTools()))
+	}
+
+	if err := groups.SetEnabled([]string{"nope"}, false); err == nil || !strings.Contains(err.Error(), "unknown tool group") {
+		t.Errorf("error = %v, want unknown group error", err)
+	}
+}
+
+func TestToolGroups_Apply(t *testing.T) {
+	srv, groups := newTestGroups()
+	if err := groups.Apply([]string{"reports"}); err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if srv.GetTool("get_monthly_report") != nil {
+		t.Error("reports should be disabled")
+	}
+	if err := groups.Apply([]string{"tasks"}); err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if srv.GetTool("get_monthly_report") == nil || srv.GetTool("create_task") != nil {
+		t.Errorf("tools after second Apply = %v", srv.ListTools())
+	}
+}
+
+func TestConfigureToolGroupsHandler(t *testing.T) {
+	srv, groups := newTestGroups()
+	handler := ConfigureToolGroupsHandler(groups)
+
+	result, err := handler(context.Background(), makeReq(map[string]interface{}{
+		"disable": []interface{}{"Reports"},
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	var resp struct {
+		Groups []ToolGroupStatus `json:"groups"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(resp.Groups) != 2 || !resp.Groups[0].Enabled || resp.Groups[1].Enabled {
+		t.Errorf("groups = %+v, want tasks enabled and reports disabled", resp.Groups)
+	}
+	if srv.GetTool("get_monthly_report") != nil {
+		t.Error("get_monthly_report should be unregistered")
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"enable": []interface{}{"tasks"}, "disable": []interface{}{"tasks"}},
+		{"enable": []interface{}{"reports"}, "disable": []interface{}{"missing"}},
+	} {
+		result, _ := handler(context.Background(), makeReq(args))
+		if !result.IsError {
+			t.Errorf("args %v: expected tool error, got %s", args, resultText(result))
+		}
+	}
+	if srv.GetTool("get_monthly_report") != nil {
+		t.Error("a rejected call must not change any group")
+	}
+}
@@ -0,0 +1,185 @@
+package tools
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// daysBetween returns the number of calendar days from a to b, both
+// YYYY-MM-DD dates.
+func daysBetween(a, b string) int {
+	ta, _ := time.Parse("2006-01-02", a)
+	tb, _ := time.Parse("2006-01-02", b)
+	return int(tb.Sub(ta).Hours() / 24)
+}
+
+// habitStreaks computes the current and longest streaks from completion dates
+// sorted newest first. Consecutive completions may be at most gap days apart;
+// the current streak survives until today is more than gap days past the
+// latest completion.
+func habitStreaks(dates []string, today string, gap int) (current, longest int) {
+	ref := today
+	for _, d := range dates {
+		if daysBetween(d, ref) > gap {
+			break
+		}
+		current++
+		ref = d
+	}
+
+	run := 0
+	for i, d := range dates {
+		if i > 0 && daysBetween(d, dates[i-1]) <= gap {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+	return current, longest
+}
+
+// GetHabitSummaryHandler creates a handler that summarizes streaks and misses
+// for recurring tasks used as habits.
+func GetHabitSummaryHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		label := "habit"
+		if l, ok := args["label"].(string); ok && strings.TrimSpace(l) != "" {
+			label = strings.TrimPrefix(strings.TrimSpace(l), "@")
+		}
+		filter := "@" + label
+		if f, ok := args["filter"].(string); ok && strings.TrimSpace(f) != "" {
+			filter = strings.TrimSpace(f)
+		}
+		days := 30
+		if d, ok := args["days"].(float64); ok {
+			if d < 1 || d > 90 {
+				return respond.Error("days must be between 1 and 90"), nil
+			}
+			days = int(d)
+		}
+
+		loc, err := userLocation(ctx, syncClient)
+		if err != nil {
+			return respond.Errorf("failed to resolve user time zone: %v", err), nil
+		}
+		now := time.Now().In(loc)
+		today := now.Format("2006-01-02")
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		weekStart := midnight.AddDate(0, 0, -((int(now.Weekday()) + 6) % 7))
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+		windowStart := now.AddDate(0, 0, -days)
+
+		tasks, err := resolveFilterTasks(ctx, client, filter)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		// Streaks are counted over the full 90 days of completion history the
+		// API serves, independent of the misses window.
+		history, truncated, err := fetchCompletedTasks(ctx, syncClient, now.Add(-maxCompletedRange), now, nil)
+		if err != nil {
+			return respond.Errorf("failed to fetch completed tasks: %v", err), nil
+		}
+		completions := make(map[string][]time.Time)
+		for _, item := range history {
+			t, err := time.Parse(time.RFC3339Nano, item.CompletedAt)
+			if err != nil {
+				continue
+			}
+			id := completedTaskID(item)
+			completions[id] = append(completions[id], t.In(loc))
+		}
+
+		habits := make([]map[string]interface{}, 0)
+		nonRecurring := 0
+		for _, task := range tasks {
+			if !taskIsRecurring(task) {
+				nonRecurring++
+				continue
+			}
+
+			id := task.ID
+			dueString := task.Due.String
+			times := completions[id]
+			sort.Slice(times, func(i, j int) bool { return times[i].After(times[j]) })
+
+			var dates []string
+			week, month, inWindow := 0, 0, 0
+			for _, t := range times {
+				if d := t.Format("2006-01-02"); len(dates) == 0 || dates[len(dates)-1] != d {
+					dates = append(dates, d)
+				}
+				if !t.Before(weekStart) {
+					week++
+				}
+				if !t.Before(monthStart) {
+					month++
+				}
+				if !t.Before(windowStart) {
+					inWindow++
+				}
+			}
+
+			habit := map[string]interface{}{
+				"task_id":              id,
+				"content":              task.Content,
+				"recurrence":           dueString,
+				"next_due":             task.Due.Day(),
+				"done_today":           len(dates) > 0 && dates[0] == today,
+				"completed_this_week":  week,
+				"completed_this_month": month,
+				"completed":            inWindow,
+				"last_completed":       nil,
+				"current_streak":       nil,
+				"longest_streak":       nil,
+				"expected":             nil,
+				"misses":               nil,
+			}
+			if len(times) > 0 {
+				habit["last_completed"] = times[0].Format(time.RFC3339)
+			}
+
+			if gap, ok := recurrenceMaxGap(dueString); ok {
+				current, longest := habitStreaks(dates, today, gap)
+				habit["current_streak"] = current
+				habit["longest_streak"] = longest
+				// One more missed day ends a streak that is already at its gap.
+				habit["at_risk"] = current > 0 && daysBetween(dates[0], today) == gap
+			}
+			if interval, ok := recurrenceInterval(dueString); ok {
+				expected := expectedOccurrences(task, windowStart, now, interval)
+				habit["expected"] = expected
+				habit["misses"] = max(expected-inWindow, 0)
+			}
+			habits = append(habits, habit)
+		}
+
+		sort.SliceStable(habits, func(i, j int) bool {
+			a, _ := habits[i]["content"].(string)
+			b, _ := habits[j]["content"].(string)
+			return strings.ToLower(a) < strings.ToLower(b)
+		})
+
+		response := respond.Envelope{
+			"filter":        filter,
+			"timezone":      loc.String(),
+			"today":         today,
+			"days":          days,
+			"count":         len(habits),
+			"non_recurring": nonRecurring,
+			"habits":        habits,
+		}
+		warnCompletedTruncated(response, truncated)
+
+		return respond.JSON(response), nil
+	}
+}
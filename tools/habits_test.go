@@ -0,0 +1,185 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHabitStreaks(t *testing.T) {
+	tests := []struct {
+		name        string
+		dates       []string
+		gap         int
+		wantCurrent int
+		wantLongest int
+	}{
+		{
+			name:        "daily streak ending yesterday is still alive",
+			dates:       []string{"2026-03-09", "2026-03-08", "2026-03-07", "2026-03-05", "2026-03-04", "2026-03-03", "2026-03-02"},
+			gap:         1,
+			wantCurrent: 3,
+			wantLongest: 4,
+		},
+		{
+			name:        "missed yesterday breaks a daily streak",
+			dates:       []string{"2026-03-08", "2026-03-07"},
+			gap:         1,
+			wantCurrent: 0,
+			wantLongest: 2,
+		},
+		{
+			name:        "weekday habit spans the weekend",
+			dates:       []string{"2026-03-09", "2026-03-06", "2026-03-05"},
+			gap:         3,
+			wantCurrent: 3,
+			wantLongest: 3,
+		},
+		{
+			name: "no completions",
+			gap:  7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := habitStreaks(tt.dates, "2026-03-10", tt.gap)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("habitStreaks() = %d, %d; want %d, %d", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
+
+func TestRecurrenceMaxGap(t *testing.T) {
+	tests := []struct {
+		due  string
+		want int
+	}{
+		{"every day", 1},
+		{"every weekday", 3},
+		{"every mon, thu", 4},
+		{"every 2 weeks", 14},
+	}
+	for _, tt := range tests {
+		if got, ok := recurrenceMaxGap(tt.due); !ok || got != tt.want {
+			t.Errorf("recurrenceMaxGap(%q) = %d, %v; want %d", tt.due, got, ok, tt.want)
+		}
+	}
+}
+
+func TestGetHabitSummaryHandler(t *testing.T) {
+	recurring := func(s string) map[string]interface{} {
+		return map[string]interface{}{"string": s, "date": "2099-01-01", "is_recurring": true}
+	}
+	var filter string
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		u, _ := url.Parse(path)
+		filter = u.Query().Get("filter")
+		return json.Marshal([]map[string]interface{}{
+			{"id": "t1", "content": "Meditate", "due": recurring("every day")},
+			{"id": "t2", "content": "Call mom", "due": recurring("every week")},
+			{"id": "t3", "content": "Read", "due": recurring("every day")},
+			{"id": "t4", "content": "Buy running shoes", "due": map[string]interface{}{"date": "2099-01-01", "is_recurring": false}},
+		})
+	}}
+
+	now := time.Now().UTC()
+	daysAgo := func(n int) string { return now.AddDate(0, 0, -n).Format(time.RFC3339) }
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if path == "/user" {
+			return []byte(`{"tz_info": {"timezone": "UTC"}}`), nil
+		}
+		if !strings.HasPrefix(path, "/tasks/completed/by_completion_date") {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return json.Marshal(map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"id": "c1", "task_id": "t1", "completed_at": daysAgo(1)},
+				{"id": "c2", "task_id": "t1", "completed_at": daysAgo(2)},
+				{"id": "c3", "task_id": "t1", "completed_at": daysAgo(5)},
+				{"id": "c4", "task_id": "t2", "completed_at": daysAgo(20)},
+				{"id": "c5", "task_id": "t3", "completed_at": daysAgo(0)},
+			},
+			"next_cursor": nil,
+		})
+	}}
+
+	tests := []struct {
+		name       string
+		args       map[string]interface{}
+		wantFilter string
+		wantStreak map[string]float64
+		wantErr    string
+	}{
+		{
+			name:       "default habit label",
+			args:       map[string]interface{}{},
+			wantFilter: "@habit",
+			wantStreak: map[string]float64{"t1": 2, "t2": 0, "t3": 1},
+		},
+		{
+			name:       "custom label",
+			args:       map[string]interface{}{"label": "@routine"},
+			wantFilter: "@routine",
+			wantStreak: map[string]float64{"t1": 2, "t2": 0, "t3": 1},
+		},
+		{
+			name:    "days out of range",
+			args:    map[string]interface{}{"days": float64(120)},
+			wantErr: "days must be between 1 and 90",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetHabitSummaryHandler(client, syncClient)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if filter != tt.wantFilter {
+				t.Errorf("filter = %q, want %q", filter, tt.wantFilter)
+			}
+
+			var resp struct {
+				NonRecurring int                      `json:"non_recurring"`
+				Habits       []map[string]interface{} `json:"habits"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if resp.NonRecurring != 1 || len(resp.Habits) != 3 {
+				t.Fatalf("got %d habits and %d non-recurring, want 3 and 1", len(resp.Habits), resp.NonRecurring)
+			}
+			for _, h := range resp.Habits {
+				id := h["task_id"].(string)
+				if h["current_streak"] != tt.wantStreak[id] {
+					t.Errorf("%s current_streak = %v, want %v", id, h["current_streak"], tt.wantStreak[id])
+				}
+				if id == "t3" && (h["done_today"] != true || h["completed_this_week"].(float64) < 1) {
+					t.Errorf("t3 should be done today and counted this week: %v", h)
+				}
+				if id == "t1" && h["at_risk"] != true {
+					t.Errorf("t1 streak ending yesterday should be at risk: %v", h)
+				}
+				if id == "t2" && h["misses"].(float64) < 3 {
+					t.Errorf("t2 misses = %v, want at least 3", h["misses"])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// outputLanguage selects the language of human-readable messages in tool
+// results. Field names and machine-readable values are never translated.
+var outputLanguage = "en"
+
+// OutputLanguages lists the supported OUTPUT_LANG values.
+var OutputLanguages = []string{"de", "en", "es", "fr"}
+
+// SetOutputLanguage sets the language of result messages. Region suffixes
+// are ignored, so "de-AT" selects German.
+func SetOutputLanguage(lang string) error {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == "" {
+		lang = "en"
+	}
+	if !slices.Contains(OutputLanguages, lang) {
+		return fmt.Errorf("unsupported output language %q (supported: %s)", lang, strings.Join(OutputLanguages, ", "))
+	}
+	outputLanguage = lang
+	return nil
+}
+
+// messageCatalog holds the translated result messages, keyed by message ID
+// and then language. Arguments are filled in with fmt verbs.
+var messageCatalog = map[string]map[string]string{
+	"task_completed": {
+		"en": "Task completed successfully",
+		"de": "Aufgabe erfolgreich erledigt",
+		"es": "Tarea completada correctamente",
+		"fr": "Tâche terminée avec succès",
+	},
+	"task_reopened": {
+		"en": "Task reopened successfully",
+		"de": "Aufgabe erfolgreich wieder geöffnet",
+		"es": "Tarea reabierta correctamente",
+		"fr": "Tâche rouverte avec succès",
+	},
+	"task_deleted": {
+		"en": "Task deleted successfully",
+		"de": "Aufgabe erfolgreich gelöscht",
+		"es": "Tarea eliminada correctamente",
+		"fr": "Tâche supprimée avec succès",
+	},
+	"task_snoozed": {
+		"en": "Task snoozed until %s",
+		"de": "Aufgabe zurückgestellt bis %s",
+		"es": "Tarea pospuesta hasta el %s",
+		"fr": "Tâche reportée au %s",
+	},
+	"project_deleted": {
+		"en": "Project deleted successfully",
+		"de": "Projekt erfolgreich gelöscht",
+		"es": "Proyecto eliminado correctamente",
+		"fr": "Projet supprimé avec succès",
+	},
+	"section_deleted": {
+		"en": "Section deleted successfully",
+		"de": "Abschnitt erfolgreich gelöscht",
+		"es": "Sección eliminada correctamente",
+		"fr": "Section supprimée avec succès",
+	},
+	"label_deleted": {
+		"en": "Label deleted successfully",
+		"de": "Label erfolgreich gelöscht",
+		"es": "Etiqueta eliminada correctamente",
+		"fr": "Étiquette supprimée avec succès",
+	},
+	"comment_deleted": {
+		"en": "Comment deleted successfully",
+		"de": "Kommentar erfolgreich gelöscht",
+		"es": "Comentario eliminado correctamente",
+		"fr": "Commentaire supprimé avec succès",
+	},
+	"bulk_completed": {
+		"en": "Successfully completed %d tasks",
+		"de": "%d Aufgaben erfolgreich erledigt",
+		"es": "%d tareas completadas correctamente",
+		"fr": "%d tâches terminées avec succès",
+	},
+	"bulk_completed_partial": {
+		"en": "Completed %d of %d tasks (%d failed)",
+		"de": "%d von %d Aufgaben erledigt (%d fehlgeschlagen)",
+		"es": "Completadas %d de %d tareas (%d fallidas)",
+		"fr": "%d tâches sur %d terminées (%d en échec)",
+	},
+	"bulk_created": {
+		"en": "Successfully created %d tasks in a single batch",
+		"de": "%d Aufgaben erfolgreich in einem Durchgang erstellt",
+		"es": "%d tareas creadas correctamente en un solo lote",
+		"fr": "%d tâches créées avec succès en un seul lot",
+	},
+	"bulk_created_partial": {
+		"en": "Created %d of %d tasks (%d failed)",
+		"de": "%d von %d Aufgaben erstellt (%d fehlgeschlagen)",
+		"es": "Creadas %d de %d tareas (%d fallidas)",
+		"fr": "%d tâches sur %d créées (%d en échec)",
+	},
+	"bulk_moved": {
+		"en": "Successfully moved %d tasks to '%s'",
+		"de": "%d Aufgaben erfolgreich nach '%s' verschoben",
+		"es": "%d tareas movidas correctamente a '%s'",
+		"fr": "%d tâches déplacées avec succès vers '%s'",
+	},
+	"bulk_moved_partial": {
+		"en": "Moved %d of %d tasks to '%s' (%d failed)",
+		"de": "%d von %d Aufgaben nach '%s' verschoben (%d fehlgeschlagen)",
+		"es": "Movidas %d de %d tareas a '%s' (%d fallidas)",
+		"fr": "%d tâches sur %d déplacées vers '%s' (%d en échec)",
+	},
+	"bulk_deleted": {
+		"en": "Successfully deleted %d tasks",
+		"de": "%d Aufgaben erfolgreich gelöscht",
+		"es": "%d tareas eliminadas correctamente",
+		"fr": "%d tâches supprimées avec succès",
+	},
+	"bulk_deleted_partial": {
+		"en": "Deleted %d of %d tasks (%d failed)",
+		"de": "%d von %d Aufgaben gelöscht (%d fehlgeschlagen)",
+		"es": "Eliminadas %d de %d tareas (%d fallidas)",
+		"fr": "%d tâches sur %d supprimées (%d en échec)",
+	},
+	"bulk_rescheduled": {
+		"en": "Successfully rescheduled %d tasks",
+		"de": "%d Aufgaben erfolgreich neu geplant",
+		"es": "%d tareas reprogramadas correctamente",
+		"fr": "%d tâches replanifiées avec succès",
+	},
+	"bulk_rescheduled_partial": {
+		"en": "Rescheduled %d of %d tasks (%d failed)",
+		"de": "%d von %d Aufgaben neu geplant (%d fehlgeschlagen)",
+		"es": "Reprogramadas %d de %d tareas (%d fallidas)",
+		"fr": "%d tâches sur %d replanifiées (%d en échec)",
+	},
+}
+
+// localize returns message id in the output language, falling back to English.
+func localize(id string, args ...interface{}) string {
+	translations := messageCatalog[id]
+	format, ok := translations[outputLanguage]
+	if !ok {
+		format = translations["en"]
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Weekday (Sunday first) and month names per output language.
+var (
+	weekdayNames = map[string][7]string{
+		"en": {"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		"de": {"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		"es": {"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		"fr": {"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	}
+	monthNames = map[string][12]string{
+		"en": {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		"de": {"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		"es": {"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		"fr": {"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	}
+)
+
+// formatLongDate writes t as a full date in the output language, e.g.
+// "Monday, January 5, 2026" or "Montag, 5. Januar 2026".
+func formatLongDate(t time.Time) string {
+	weekday := weekdayNames[outputLanguage][t.Weekday()]
+	month := monthNames[outputLanguage][t.Month()-1]
+	switch outputLanguage {
+	case "de":
+		return fmt.Sprintf("%s, %d. %s %d", weekday, t.Day(), month, t.Year())
+	case "es":
+		return fmt.Sprintf("%s, %d de %s de %d", weekday, t.Day(), month, t.Year())
+	case "fr":
+		return fmt.Sprintf("%s %d %s %d", weekday, t.Day(), month, t.Year())
+	default:
+		return fmt.Sprintf("%s, %s %d, %d", weekday, month, t.Day(), t.Year())
+	}
+}
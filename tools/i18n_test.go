@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useOutputLanguage switches the output language for one test.
+func useOutputLanguage(t *testing.T, lang string) {
+	t.Helper()
+	previous := outputLanguage
+	if err := SetOutputLanguage(lang); err != nil {
+		t.Fatalf("SetOutputLanguage(%q) error: %v", lang, err)
+	}
+	t.Cleanup(func() { outputLanguage = previous })
+}
+
+func TestSetOutputLanguage(t *testing.T) {
+	t.Cleanup(func() { outputLanguage = "en" })
+
+	for lang, want := range map[string]string{"de-AT": "de", " FR ": "fr", "es_MX": "es", "": "en"} {
+		if err := SetOutputLanguage(lang); err != nil {
+			t.Fatalf("SetOutputLanguage(%q) error: %v", lang, err)
+		}
+		if outputLanguage != want {
+			t.Errorf("SetOutputLanguage(%q) selected %q, want %q", lang, outputLanguage, want)
+		}
+	}
+	if err := SetOutputLanguage("ja"); err == nil || !strings.Contains(err.Error(), "supported: de, en, es, fr") {
+		t.Errorf("error = %v, want unsupported language error", err)
+	}
+}
+
+func TestMessageCatalogComplete(t *testing.T) {
+	for id, translations := range messageCatalog {
+		for _, lang := range OutputLanguages {
+			if translations[lang] == "" {
+				t.Errorf("message %q has no %s translation", id, lang)
+			}
+			if strings.Count(translations[lang], "%") != strings.Count(translations["en"], "%") {
+				t.Errorf("message %q (%s) has different arguments than English", id, lang)
+			}
+		}
+	}
+}
+
+func TestFormatLongDate(t *testing.T) {
+	date := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+	tests := map[string]string{
+		"en": "Monday, January 5, 2026",
+		"de": "Montag, 5. Januar 2026",
+		"es": "lunes, 5 de enero de 2026",
+		"fr": "lundi 5 janvier 2026",
+	}
+	for lang, want := range tests {
+		t.Run(lang, func(t *testing.T) {
+			useOutputLanguage(t, lang)
+			if got := formatLongDate(date); got != want {
+				t.Errorf("formatLongDate() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCompleteTaskHandler_Localized(t *testing.T) {
+	useOutputLanguage(t, "de")
+	client := &MockAPI{PostFn: func(context.Context, string, interface{}) ([]byte, error) { return nil, nil }}
+
+	result, err := CompleteTaskHandler(client, &MockSyncAPI{})(context.Background(), makeReq(map[string]interface{}{"task_id": "1"}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	var resp struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Message != "Aufgabe erfolgreich erledigt" {
+		t.Errorf("message = %q, want the German translation", resp.Message)
+	}
+}
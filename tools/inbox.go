@@ -0,0 +1,351 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// defaultInboxPage and maxInboxPage bound the tasks returned per
+// process_inbox page.
+const (
+	defaultInboxPage = 10
+	maxInboxPage     = 50
+)
+
+// staleInboxDays is the age past which an Inbox task is suggested for
+// deletion.
+const staleInboxDays = 30
+
+// mentions reports whether text contains name as a whole word or phrase,
+// ignoring case.
+func mentions(text, name string) bool {
+	if len(name) < 3 {
+		return false
+	}
+	matched, _ := regexp.MatchString(`(?i)(^|[^\pL\pN])`+regexp.QuoteMeta(name)+`([^\pL\pN]|$)`, text)
+	return matched
+}
+
+// minInboxConfidence is the classifier confidence needed to suggest moving an
+// Inbox task to a project its content does not name.
+const minInboxConfidence = 0.5
+
+// inboxSuggestions proposes what to do with an Inbox task: move it to a
+// project its content names or the classifier model picks, add labels its
+// content names, schedule it, or delete it when it has sat in the Inbox for
+// long. model may be nil.
+func inboxSuggestions(task models.Task, projects []models.Project, labels []models.Label, model *projectModel, now time.Time) []map[string]interface{} {
+	suggestions := make([]map[string]interface{}, 0)
+	content := task.Content
+
+	for _, project := range projects {
+		if mentions(content, project.Name) {
+			suggestions = append(suggestions, map[string]interface{}{
+				"action":     "move",
+				"project_id": project.ID,
+				"reason":     fmt.Sprintf("mentions project %q", project.Name),
+			})
+			break
+		}
+	}
+	if len(suggestions) == 0 && model != nil {
+		if best := model.suggest(content, 1); len(best) > 0 && best[0].Confidence >= minInboxConfidence {
+			suggestions = append(suggestions, map[string]interface{}{
+				"action":     "move",
+				"project_id": best[0].ProjectID,
+				"confidence": best[0].Confidence,
+				"reason":     fmt.Sprintf("resembles tasks in %q (%s)", best[0].ProjectName, strings.Join(best[0].Keywords, ", ")),
+			})
+		}
+	}
+
+	var named []string
+	for _, label := range labels {
+		if mentions(content, label.Name) && !taskHasLabel(task, label.Name) {
+			named = append(named, label.Name)
+		}
+	}
+	if len(named) > 0 {
+		suggestions = append(suggestions, map[string]interface{}{
+			"action": "label",
+			"labels": named,
+			"reason": "mentions " + strings.Join(named, ", "),
+		})
+	}
+
+	if task.Due.Day() == "" {
+		suggestions = append(suggestions, map[string]interface{}{"action": "schedule", "reason": "no due date"})
+	}
+	if added, ok := taskAddedAt(task); ok {
+		if age := int(now.Sub(added).Hours() / 24); age > staleInboxDays {
+			suggestions = append(suggestions, map[string]interface{}{
+				"action": "delete",
+				"reason": fmt.Sprintf("in the Inbox for %d days", age),
+			})
+		}
+	}
+	return suggestions
+}
+
+// ProcessInboxHandler creates a handler for working through the Inbox: it
+// lists Inbox tasks a page at a time with suggested actions, and applies a
+// page's decisions in one Sync batch. classifier may be nil.
+func ProcessInboxHandler(client todoist.API, syncClient todoist.SyncAPI, classifier *ProjectClassifier) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectsBody, err := client.Get(ctx, "/projects")
+		if err != nil {
+			return respond.Errorf("failed to fetch projects: %v", err), nil
+		}
+		var projects []models.Project
+		if err := json.Unmarshal(projectsBody, &projects); err != nil {
+			return respond.Errorf("failed to parse projects: %v", err), nil
+		}
+		inboxID := ""
+		targets := make([]models.Project, 0, len(projects))
+		for _, project := range projects {
+			if project.IsInboxProject {
+				inboxID = project.ID
+				continue
+			}
+			if !project.IsArchived {
+				targets = append(targets, project)
+			}
+		}
+		if inboxID == "" {
+			return respond.Error("could not find the Inbox project"), nil
+		}
+
+		params := url.Values{}
+		params.Set("project_id", inboxID)
+		tasks, err := fetchTasks(ctx, client, params)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		slices.SortStableFunc(tasks, func(a, b models.Task) int { return strings.Compare(taskAgeKey(a), taskAgeKey(b)) })
+
+		if decisions, ok := args["decisions"].([]interface{}); ok && len(decisions) > 0 {
+			return applyInboxDecisions(ctx, syncClient, inboxID, tasks, decisions), nil
+		}
+
+		pageSize := defaultInboxPage
+		if n, ok := args["page_size"].(float64); ok {
+			if n < 1 || n > maxInboxPage {
+				return respond.Errorf("page_size must be between 1 and %d", maxInboxPage), nil
+			}
+			pageSize = int(n)
+		}
+		cursor, _ := args["cursor"].(string)
+		start := ageCursorStart(tasks, cursor)
+		end := min(start+pageSize, len(tasks))
+
+		labelsBody, err := client.Get(ctx, "/labels")
+		if err != nil {
+			return respond.Errorf("failed to fetch labels: %v", err), nil
+		}
+		var labels []models.Label
+		if err := json.Unmarshal(labelsBody, &labels); err != nil {
+			return respond.Errorf("failed to parse labels: %v", err), nil
+		}
+
+		var model *projectModel
+		var modelErr error
+		if classifier != nil {
+			model, modelErr = classifier.load(ctx)
+		}
+
+		now := time.Now()
+		page := make([]map[string]interface{}, 0, end-start)
+		for _, task := range tasks[start:end] {
+			entry := map[string]interface{}{
+				"id":          task.ID,
+				"content":     task.Content,
+				"description": task.Description,
+				"labels":      task.Labels,
+				"priority":    task.Priority,
+				"suggestions": inboxSuggestions(task, targets, labels, model, now),
+			}
+			if due := task.Due.Day(); due != "" {
+				entry["due"] = due
+			}
+			if added, ok := taskAddedAt(task); ok {
+				entry["added_at"] = added.UTC().Format(time.RFC3339)
+			}
+			page = append(page, entry)
+		}
+
+		response := respond.List("tasks", page).
+			Set("inbox_project_id", inboxID).
+			Set("total", len(tasks)).
+			Set("remaining", len(tasks)-end)
+		if end < len(tasks) {
+			response.Paginate(taskAgeKey(tasks[end-1]))
+		}
+		if modelErr != nil {
+			response.Warn("project suggestions from similar tasks are unavailable: %v", modelErr)
+		}
+		return respond.JSON(response), nil
+	}
+}
+
+// applyInboxDecisions sends the commands for a page of Inbox decisions in one
+// Sync batch. Decisions may only name tasks in the Inbox.
+func applyInboxDecisions(ctx context.Context, syncClient todoist.SyncAPI, inboxID string, tasks []models.Task, decisions []interface{}) *mcp.CallToolResult {
+	if len(decisions) > maxPlanCommands {
+		return respond.Errorf("decisions exceeds %d entries", maxPlanCommands)
+	}
+	inInbox := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		inInbox[task.ID] = true
+	}
+
+	var commands []todoist.Command
+	commandTask := make(map[string]string)
+	add := func(taskID, cmdType string, cmdArgs map[string]interface{}) {
+		cmd := todoist.Command{Type: cmdType, UUID: todoist.GenerateUUID(), Args: cmdArgs}
+		commands = append(commands, cmd)
+		commandTask[cmd.UUID] = taskID
+	}
+	// leaves records the tasks a decision takes out of the Inbox.
+	leaves := make(map[string]bool)
+	decided := make(map[string]bool)
+	for i, raw := range decisions {
+		decision, ok := raw.(map[string]interface{})
+		if !ok {
+			return respond.Errorf("decisions[%d] must be an object", i)
+		}
+		taskID, _ := decision["task_id"].(string)
+		if err := ValidateID(taskID, fmt.Sprintf("decisions[%d].task_id", i)); err != nil {
+			return respond.ErrorFrom(err)
+		}
+		if !inInbox[taskID] {
+			return respond.Errorf("decisions[%d]: task %s is not in the Inbox", i, taskID)
+		}
+		if decided[taskID] {
+			return respond.Errorf("decisions[%d]: task %s has more than one decision", i, taskID)
+		}
+		decided[taskID] = true
+
+		remove, _ := decision["delete"].(bool)
+		complete, _ := decision["complete"].(bool)
+		projectID, _ := decision["project_id"].(string)
+		dueString, _ := decision["due_string"].(string)
+		dueDate, _ := decision["due_date"].(string)
+		rawLabels, hasLabels := decision["labels"].([]interface{})
+		edits := projectID != "" || dueString != "" || dueDate != "" || hasLabels
+
+		switch {
+		case remove && (complete || edits):
+			return respond.Errorf("decisions[%d]: delete cannot be combined with other changes", i)
+		case complete && edits:
+			return respond.Errorf("decisions[%d]: complete cannot be combined with other changes", i)
+		case remove:
+			add(taskID, "item_delete", map[string]interface{}{"id": taskID})
+			leaves[taskID] = true
+			continue
+		case complete:
+			add(taskID, "item_close", map[string]interface{}{"id": taskID})
+			leaves[taskID] = true
+			continue
+		case !edits:
+			return respond.Errorf("decisions[%d] needs project_id, due_string, due_date, labels, delete, or complete", i)
+		}
+
+		if projectID != "" && projectID != inboxID {
+			if err := ValidateID(projectID, fmt.Sprintf("decisions[%d].project_id", i)); err != nil {
+				return respond.ErrorFrom(err)
+			}
+			add(taskID, "item_move", map[string]interface{}{"id": taskID, "project_id": projectID})
+			leaves[taskID] = true
+		}
+		update := map[string]interface{}{"id": taskID}
+		switch {
+		case dueString != "":
+			update["due"] = map[string]interface{}{"string": dueString}
+		case dueDate != "":
+			if _, err := time.Parse("2006-01-02", dueDate); err != nil {
+				return respond.Errorf("decisions[%d].due_date must be a YYYY-MM-DD date", i)
+			}
+			update["due"] = map[string]interface{}{"date": dueDate}
+		}
+		if hasLabels {
+			labels := make([]string, 0, len(rawLabels))
+			for _, l := range rawLabels {
+				if s, ok := l.(string); ok && strings.TrimPrefix(s, "@") != "" {
+					labels = append(labels, strings.TrimPrefix(s, "@"))
+				}
+			}
+			update["labels"] = labels
+		}
+		if len(update) > 1 {
+			add(taskID, "item_update", update)
+		}
+	}
+	if len(commands) > maxPlanCommands {
+		return respond.Errorf("decisions need %d commands, more than the batch limit of %d", len(commands), maxPlanCommands)
+	}
+
+	failed := make([]todoist.BulkFailure, 0)
+	failedIDs := make(map[string]bool)
+	if len(commands) > 0 {
+		syncResp, err := syncClient.BatchCommands(ctx, commands)
+		if err != nil {
+			return respond.Errorf("failed to apply decisions: %v", err)
+		}
+		for _, cmd := range commands {
+			if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+				continue
+			}
+			taskID := commandTask[cmd.UUID]
+			if !failedIDs[taskID] {
+				failedIDs[taskID] = true
+				failed = append(failed, todoist.BulkFailure{ID: taskID, Error: fmt.Sprintf("%s: %v", cmd.Type, syncResp.SyncStatus[cmd.UUID])})
+			}
+		}
+	}
+
+	counts := map[string]int{"moved": 0, "updated": 0, "completed": 0, "deleted": 0}
+	for _, cmd := range commands {
+		if failedIDs[commandTask[cmd.UUID]] {
+			continue
+		}
+		switch cmd.Type {
+		case "item_move":
+			counts["moved"]++
+		case "item_update":
+			counts["updated"]++
+		case "item_close":
+			counts["completed"]++
+		case "item_delete":
+			counts["deleted"]++
+		}
+	}
+	remaining := len(tasks)
+	for taskID := range leaves {
+		if !failedIDs[taskID] {
+			remaining--
+		}
+	}
+
+	return respond.JSON(map[string]interface{}{
+		"processed":       len(decided) - len(failed),
+		"moved":           counts["moved"],
+		"updated":         counts["updated"],
+		"completed":       counts["completed"],
+		"deleted":         counts["deleted"],
+		"failed":          failed,
+		"inbox_remaining": remaining,
+	})
+}
@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+// inboxMock serves Inbox project p-in with three tasks, oldest first: i1, i2,
+// i3, and the projects Work and Home.
+func inboxMock() *MockAPI {
+	old := time.Now().AddDate(0, 0, -45).UTC().Format(time.RFC3339)
+	return &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		switch {
+		case path == "/projects":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "p-in", "name": "Inbox", "is_inbox_project": true},
+				{"id": "p-work", "name": "Work"},
+				{"id": "p-home", "name": "Home", "is_archived": true},
+			})
+		case path == "/labels":
+			return json.Marshal([]map[string]interface{}{{"name": "calls"}, {"name": "errands"}})
+		case strings.Contains(path, "project_id=p-in"):
+			return json.Marshal([]map[string]interface{}{
+				{"id": "i3", "content": "Home repairs", "added_at": "2026-10-01T10:00:00Z", "due": map[string]interface{}{"date": "2026-10-20"}},
+				{"id": "i1", "content": "Old invoice idea", "added_at": old},
+				{"id": "i2", "content": "Work calls with vendor", "added_at": "2026-09-30T10:00:00Z"},
+			})
+		}
+		return []byte(`[]`), nil
+	}}
+}
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		text, name string
+		want       bool
+	}{
+		{"Work calls with vendor", "work", true},
+		{"Homework", "work", false},
+		{"Read a book", "a", false},
+		{"Plan Q4 (roadmap)", "Q4 (roadmap)", true},
+	}
+	for _, tt := range tests {
+		if got := mentions(tt.text, tt.name); got != tt.want {
+			t.Errorf("mentions(%q, %q) = %v, want %v", tt.text, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProcessInboxHandler_Pages(t *testing.T) {
+	handler := ProcessInboxHandler(inboxMock(), &MockSyncAPI{}, nil)
+
+	var first struct {
+		Count      int                      `json:"count"`
+		Total      int                      `json:"total"`
+		Remaining  int                      `json:"remaining"`
+		NextCursor string                   `json:"next_cursor"`
+		Tasks      []map[string]interface{} `json:"tasks"`
+	}
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"page_size": float64(2)}))
+	if err := json.Unmarshal([]byte(resultText(result)), &first); err != nil {
+		t.Fatalf("parse: %v (%s)", err, resultText(result))
+	}
+	if first.Count != 2 || first.Total != 3 || first.Remaining != 1 || first.NextCursor == "" {
+		t.Fatalf("first page = %+v", first)
+	}
+	if first.Tasks[0]["id"] != "i1" || first.Tasks[1]["id"] != "i2" {
+		t.Fatalf("first page tasks = %v", first.Tasks)
+	}
+	old, _ := json.Marshal(first.Tasks[0]["suggestions"])
+	if !strings.Contains(string(old), `"action":"schedule"`) || !strings.Contains(string(old), `"action":"delete"`) {
+		t.Errorf("i1 suggestions = %s", old)
+	}
+	work, _ := json.Marshal(first.Tasks[1]["suggestions"])
+	if !strings.Contains(string(work), `"project_id":"p-work"`) || !strings.Contains(string(work), `"labels":["calls"]`) {
+		t.Errorf("i2 suggestions = %s", work)
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"page_size": float64(2), "cursor": first.NextCursor}))
+	text := resultText(result)
+	if !strings.Contains(text, `"id": "i3"`) || !strings.Contains(text, `"remaining": 0`) || strings.Contains(text, "p-home") || strings.Contains(text, `"schedule"`) {
+		t.Errorf("second page = %s", text)
+	}
+}
+
+func TestProcessInboxHandler_Decisions(t *testing.T) {
+	var sent []todoist.Command
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		sent = commands
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+			if cmd.Type == "item_close" {
+				status[cmd.UUID] = map[string]interface{}{"error": "forbidden"}
+			}
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+	handler := ProcessInboxHandler(inboxMock(), syncClient, nil)
+
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"decisions": []interface{}{
+		map[string]interface{}{"task_id": "i1", "delete": true},
+		map[string]interface{}{"task_id": "i2", "project_id": "p-work", "due_string": "friday", "labels": []interface{}{"@calls"}},
+		map[string]interface{}{"task_id": "i3", "complete": true},
+	}}))
+	var resp struct {
+		Processed      int                   `json:"processed"`
+		Moved          int                   `json:"moved"`
+		Updated        int                   `json:"updated"`
+		Completed      int                   `json:"completed"`
+		Deleted        int                   `json:"deleted"`
+		Failed         []todoist.BulkFailure `json:"failed"`
+		InboxRemaining int                   `json:"inbox_remaining"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("parse: %v (%s)", err, resultText(result))
+	}
+	if resp.Processed != 2 || resp.Moved != 1 || resp.Updated != 1 || resp.Deleted != 1 || resp.Completed != 0 ||
+		len(resp.Failed) != 1 || resp.Failed[0].ID != "i3" || resp.InboxRemaining != 1 {
+		t.Errorf("resp = %+v", resp)
+	}
+	if len(sent) != 4 || sent[0].Type != "item_delete" || sent[1].Type != "item_move" || sent[2].Type != "item_update" || sent[3].Type != "item_close" {
+		t.Fatalf("sent = %+v", sent)
+	}
+	due, _ := sent[2].Args["due"].(map[string]interface{})
+	labels, _ := sent[2].Args["labels"].([]string)
+	if due["string"] != "friday" || len(labels) != 1 || labels[0] != "calls" {
+		t.Errorf("update args = %v", sent[2].Args)
+	}
+
+	tests := []struct {
+		decision map[string]interface{}
+		want     string
+	}{
+		{map[string]interface{}{"task_id": "other", "delete": true}, "not in the Inbox"},
+		{map[string]interface{}{"task_id": "i1", "delete": true, "project_id": "p-work"}, "delete cannot be combined"},
+		{map[string]interface{}{"task_id": "i1", "complete": true, "due_string": "today"}, "complete cannot be combined"},
+		{map[string]interface{}{"task_id": "i1"}, "needs project_id"},
+		{map[string]interface{}{"task_id": "i1", "due_date": "next week"}, "YYYY-MM-DD"},
+	}
+	for _, tt := range tests {
+		sent = nil
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{"decisions": []interface{}{tt.decision}}))
+		if !result.IsError || !strings.Contains(resultText(result), tt.want) || sent != nil {
+			t.Errorf("%v: result = %s, want %q", tt.decision, resultText(result), tt.want)
+		}
+	}
+}
+
+func TestProcessInboxHandler_ClassifierSuggestion(t *testing.T) {
+	calls := 0
+	handler := ProcessInboxHandler(inboxMock(), &MockSyncAPI{}, NewProjectClassifier(classifierSync(&calls), time.Hour))
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{}))
+	var resp struct {
+		Tasks []struct {
+			ID          string                   `json:"id"`
+			Suggestions []map[string]interface{} `json:"suggestions"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("parse: %v (%s)", err, resultText(result))
+	}
+	// i1 only resembles Work tasks; i2 names Work, which takes precedence.
+	move := resp.Tasks[0].Suggestions[0]
+	if resp.Tasks[0].ID != "i1" || move["action"] != "move" || move["project_id"] != "p-work" || move["confidence"] == nil {
+		t.Errorf("i1 suggestions = %v", resp.Tasks[0].Suggestions)
+	}
+	if move := resp.Tasks[1].Suggestions[0]; move["confidence"] != nil || !strings.Contains(fmt.Sprint(move["reason"]), "mentions project") {
+		t.Errorf("i2 suggestions = %v", resp.Tasks[1].Suggestions)
+	}
+}
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// instructionHint is a workflow recommendation that only makes sense when
+// all of its tools are registered.
+type instructionHint struct {
+	tools []string
+	text  string
+}
+
+// instructionHints are listed in the order an agent typically needs them.
+var instructionHints = []instructionHint{
+	{[]string{"list_projects", "create_task"}, "Call list_projects (and list_sections / list_labels) before create_task or update_task so project_id, section_id, and label names are real; never guess IDs."},
+	{[]string{"search_tasks"}, "Find tasks with search_tasks and a Todoist filter (e.g. \"today | overdue\", \"#Work & p1\") instead of listing everything and filtering client-side."},
+	{[]string{"quick_add_task"}, "For a single task described in natural language, quick_add_task parses dates, #projects, @labels, and priorities in one request."},
+	{[]string{"batch_create_tasks"}, "Create several tasks with one batch_create_tasks call rather than repeated create_task calls; batch tools use the Sync API and save rate limit budget."},
+	{[]string{"bulk_complete_tasks", "bulk_delete_tasks"}, "Complete or delete many tasks with bulk_complete_tasks / bulk_delete_tasks. A filter selection returns a preview and confirmation_token first; show the preview to the user before confirming."},
+	{[]string{"plan_bulk_operation", "execute_plan"}, "For multi-step or large changes, build a plan with plan_bulk_operation, review it with the user, then run it with execute_plan."},
+	{[]string{"estimate_operation_cost"}, "Before very large jobs, estimate_operation_cost (or estimate_cost: true on bulk tools) reports whether the 450 requests / 15 minutes budget suffices."},
+	{[]string{"cleanup_workspace", "normalize_colors"}, "Maintenance tools change many items at once: run cleanup_workspace with action \"report\" and normalize_colors with dry_run first."},
+	{[]string{"list_recent_operations"}, "list_recent_operations shows what this session changed, which helps when the user asks to undo or review."},
+}
+
+// defaultInstructionsTemplate renders the MCP server instructions unless
+// INSTRUCTIONS_FILE supplies another template.
+const defaultInstructionsTemplate = `This server manages the user's Todoist tasks, projects, sections, labels, and comments ({{.ToolCount}} tools).
+
+Recommended workflow:
+{{range .Hints}}- {{.}}
+{{end}}
+Dates accept natural language ("tomorrow at 5pm", "every monday") via due_string. Priorities run from 1 (normal) to 4 (urgent).`
+
+// InstructionsData is the data available to an instructions template.
+type InstructionsData struct {
+	// Tools holds the registered tool names, sorted.
+	Tools     []string
+	ToolCount int
+	// Hints are the workflow recommendations whose tools are all registered.
+	Hints []string
+}
+
+// BuildInstructions renders the MCP server instructions for the registered
+// tools. An empty tmpl selects the built-in template.
+func BuildInstructions(tmpl string, toolNames []string) (string, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = defaultInstructionsTemplate
+	}
+	t, err := template.New("instructions").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid instructions template: %w", err)
+	}
+
+	names := slices.Clone(toolNames)
+	slices.Sort(names)
+	data := InstructionsData{Tools: names, ToolCount: len(names)}
+	for _, hint := range instructionHints {
+		registered := true
+		for _, name := range hint.tools {
+			if _, found := slices.BinarySearch(names, name); !found {
+				registered = false
+				break
+			}
+		}
+		if registered {
+			data.Hints = append(data.Hints, hint.text)
+		}
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("rendering instructions template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildInstructions(t *testing.T) {
+	t.Run("default template includes hints for registered tools only", func(t *testing.T) {
+		got, err := BuildInstructions("", []string{"search_tasks", "create_task", "list_projects"})
+		if err != nil {
+			t.Fatalf("BuildInstructions() error: %v", err)
+		}
+		if !strings.Contains(got, "(3 tools)") {
+			t.Errorf("instructions should mention the tool count:\n%s", got)
+		}
+		if !strings.Contains(got, "Call list_projects") || !strings.Contains(got, "search_tasks and a Todoist filter") {
+			t.Errorf("instructions missing hints for registered tools:\n%s", got)
+		}
+		if strings.Contains(got, "batch_create_tasks") || strings.Contains(got, "execute_plan") {
+			t.Errorf("instructions mention unregistered tools:\n%s", got)
+		}
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		got, err := BuildInstructions("Tools: {{range .Tools}}{{.}} {{end}}\n", []string{"b_tool", "a_tool"})
+		if err != nil {
+			t.Fatalf("BuildInstructions() error: %v", err)
+		}
+		if got != "Tools: a_tool b_tool" {
+			t.Errorf("got %q", got)
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		if _, err := BuildInstructions("{{.Missing", nil); err == nil || !strings.Contains(err.Error(), "invalid instructions template") {
+			t.Errorf("error = %v, want invalid template error", err)
+		}
+		if _, err := BuildInstructions("{{.Nope}}", nil); err == nil || !strings.Contains(err.Error(), "rendering") {
+			t.Errorf("error = %v, want rendering error", err)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// RecentOperationsURI is the MCP resource URI that exposes the session journal.
+const RecentOperationsURI = "todoist://operations/recent"
+
+// JournalEntry records a single mutating tool call made during this session.
+type JournalEntry struct {
+	ID        int                 `json:"id"`
+	Tool      string              `json:"tool"`
+	Timestamp time.Time           `json:"timestamp"`
+	Status    string              `json:"status"`
+	Entities  map[string][]string `json:"entities,omitempty"`
+}
+
+// Journal is a bounded, in-memory log of the mutations performed by this
+// server process, newest entries evicting the oldest once capacity is reached.
+type Journal struct {
+	mu       sync.Mutex
+	entries  []JournalEntry
+	capacity int
+	nextID   int
+}
+
+// NewJournal creates a journal that retains at most capacity entries.
+func NewJournal(capacity int) *Journal {
+	return &Journal{capacity: capacity, nextID: 1}
+}
+
+// Record appends an entry for a completed tool call. Affected entities are
+// taken from *_id / *_ids arguments and from the "id" field of the result.
+func (j *Journal) Record(tool string, args map[string]interface{}, result *mcp.CallToolResult) {
+	status := "ok"
+	if result == nil || result.IsError {
+		status = "error"
+	}
+
+	entities := make(map[string][]string)
+	for key, value := range args {
+		if !strings.HasSuffix(key, "_id") && !strings.HasSuffix(key, "_ids") && key != "ids" {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			if v != "" {
+				entities[key] = append(entities[key], v)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok && s != "" {
+					entities[key] = append(entities[key], s)
+				}
+			}
+		}
+	}
+	if status == "ok" {
+		var body map[string]interface{}
+		if json.Unmarshal([]byte(resultTextContent(result)), &body) == nil {
+			if id, ok := body["id"].(string); ok && id != "" && !slices.Contains(entities["id"], id) {
+				entities["id"] = append(entities["id"], id)
+			}
+		}
+	}
+	if len(entities) == 0 {
+		entities = nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = append(j.entries, JournalEntry{
+		ID:        j.nextID,
+		Tool:      tool,
+		Timestamp: time.Now().UTC(),
+		Status:    status,
+		Entities:  entities,
+	})
+	j.nextID++
+	if len(j.entries) > j.capacity {
+		j.entries = j.entries[len(j.entries)-j.capacity:]
+	}
+}
+
+// Recent returns up to limit entries, newest first. A limit of zero or less returns all entries.
+func (j *Journal) Recent(limit int) []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	n := len(j.entries)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	recent := make([]JournalEntry, 0, n)
+	for i := len(j.entries) - 1; i >= 0 && len(recent) < n; i-- {
+		recent = append(recent, j.entries[i])
+	}
+	return recent
+}
+
+// resultTextContent returns the text of the first text content block of a result.
+func resultTextContent(result *mcp.CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+	for _, c := range result.Content {
+		if tc, ok := c.(mcp.TextContent); ok {
+			return tc.Text
+		}
+	}
+	return ""
+}
+
+// ListRecentOperationsHandler creates a handler for listing the mutations recorded in this session.
+func ListRecentOperationsHandler(journal *Journal) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		limit := 20
+		if l, ok := args["limit"].(float64); ok {
+			if l < 1 || l > 200 {
+				return respond.Error("limit must be between 1 and 200"), nil
+			}
+			limit = int(l)
+		}
+
+		operations := journal.Recent(limit)
+		response := respond.List("operations", operations)
+
+		return respond.JSON(response), nil
+	}
+}
+
+// RecentOperationsResourceHandler creates a handler that serves the full journal as an MCP resource.
+func RecentOperationsResourceHandler(journal *Journal) func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(_ context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return respond.Resource(RecentOperationsURI, journal.Recent(0))
+	}
+}
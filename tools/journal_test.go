@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestJournal_Record(t *testing.T) {
+	journal := NewJournal(2)
+
+	journal.Record("create_task", map[string]interface{}{"content": "x", "project_id": "p1"},
+		mcp.NewToolResultText(`{"id": "t1", "content": "x"}`))
+	journal.Record("bulk_complete_tasks", map[string]interface{}{"task_ids": []interface{}{"a", "b"}},
+		mcp.NewToolResultText(`{"success_count": 2}`))
+	journal.Record("delete_task", map[string]interface{}{"task_id": "t9"},
+		mcp.NewToolResultError("failed to delete task: not found"))
+
+	recent := journal.Recent(0)
+	if len(recent) != 2 {
+		t.Fatalf("got %d entries, want 2 (capacity)", len(recent))
+	}
+	if recent[0].Tool != "delete_task" || recent[0].Status != "error" || recent[0].ID != 3 {
+		t.Errorf("newest entry = %+v", recent[0])
+	}
+	if got := recent[1].Entities["task_ids"]; len(got) != 2 {
+		t.Errorf("task_ids entities = %v", got)
+	}
+
+	journal = NewJournal(10)
+	journal.Record("create_task", map[string]interface{}{"project_id": "p1"},
+		mcp.NewToolResultText(`{"id": "t1"}`))
+	entry := journal.Recent(1)[0]
+	if entry.Entities["id"][0] != "t1" || entry.Entities["project_id"][0] != "p1" {
+		t.Errorf("entities = %v, want id t1 and project_id p1", entry.Entities)
+	}
+}
+
+func TestListRecentOperationsHandler(t *testing.T) {
+	journal := NewJournal(10)
+	for i := 0; i < 3; i++ {
+		journal.Record("update_task", map[string]interface{}{"task_id": "t1"}, mcp.NewToolResultText(`{}`))
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantErr   bool
+		errSubstr string
+		wantCount int
+	}{
+		{name: "default limit", args: map[string]interface{}{}, wantCount: 3},
+		{name: "limited", args: map[string]interface{}{"limit": float64(2)}, wantCount: 2},
+		{name: "invalid limit", args: map[string]interface{}{"limit": float64(0)}, wantErr: true, errSubstr: "limit must be between"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := ListRecentOperationsHandler(journal)
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			var resp struct {
+				Count      int            `json:"count"`
+				Operations []JournalEntry `json:"operations"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if resp.Count != tt.wantCount || len(resp.Operations) != tt.wantCount {
+				t.Errorf("count = %d, want %d", resp.Count, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestRecentOperationsResourceHandler(t *testing.T) {
+	journal := NewJournal(10)
+	journal.Record("create_label", map[string]interface{}{"name": "x"}, mcp.NewToolResultText(`{"id": "l1"}`))
+
+	contents, err := RecentOperationsResourceHandler(journal)(context.Background(), mcp.ReadResourceRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(contents) != 1 {
+		t.Fatalf("got %d contents, want 1", len(contents))
+	}
+	text, ok := contents[0].(mcp.TextResourceContents)
+	if !ok || text.URI != RecentOperationsURI {
+		t.Fatalf("unexpected contents: %+v", contents[0])
+	}
+	var entries []JournalEntry
+	if err := json.Unmarshal([]byte(text.Text), &entries); err != nil {
+		t.Fatalf("failed to parse resource: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Tool != "create_label" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
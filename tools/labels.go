@@ -7,6 +7,8 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
 )
 
 // ListLabelsHandler creates a handler for listing all personal labels.
@@ -14,25 +16,17 @@ func ListLabelsHandler(client todoist.API) func(context.Context, mcp.CallToolReq
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		respBody, err := client.Get(ctx, "/labels")
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to list labels: %v", err)), nil
+			return respond.Errorf("failed to list labels: %v", err), nil
 		}
 
-		var labels []map[string]interface{}
+		var labels []models.Label
 		if err := json.Unmarshal(respBody, &labels); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse labels: %v", err)), nil
+			return respond.Errorf("failed to parse labels: %v", err), nil
 		}
 
-		response := map[string]interface{}{
-			"count":  len(labels),
-			"labels": labels,
-		}
+		response := respond.List("labels", labels)
 
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
 
@@ -43,7 +37,7 @@ func CreateLabelHandler(client todoist.API) func(context.Context, mcp.CallToolRe
 
 		name, ok := args["name"].(string)
 		if !ok || name == "" {
-			return mcp.NewToolResultError("name is required"), nil
+			return respond.Error("name is required"), nil
 		}
 
 		body := map[string]interface{}{
@@ -62,20 +56,15 @@ func CreateLabelHandler(client todoist.API) func(context.Context, mcp.CallToolRe
 
 		respBody, err := client.Post(ctx, "/labels", body)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to create label: %v", err)), nil
+			return respond.Errorf("failed to create label: %v", err), nil
 		}
 
-		var label map[string]interface{}
+		var label models.Label
 		if err := json.Unmarshal(respBody, &label); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse response: %v", err)), nil
-		}
-
-		jsonData, err := json.MarshalIndent(label, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			return respond.Errorf("failed to parse response: %v", err), nil
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(label), nil
 	}
 }
 
@@ -86,10 +75,10 @@ func UpdateLabelHandler(client todoist.API) func(context.Context, mcp.CallToolRe
 
 		labelID, ok := args["label_id"].(string)
 		if !ok || labelID == "" {
-			return mcp.NewToolResultError("label_id is required"), nil
+			return respond.Error("label_id is required"), nil
 		}
 		if err := ValidateID(labelID, "label_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
 		body := map[string]interface{}{}
@@ -108,26 +97,21 @@ func UpdateLabelHandler(client todoist.API) func(context.Context, mcp.CallToolRe
 		}
 
 		if len(body) == 0 {
-			return mcp.NewToolResultError("at least one field to update must be provided"), nil
+			return respond.Error("at least one field to update must be provided"), nil
 		}
 
 		path := fmt.Sprintf("/labels/%s", labelID)
 		respBody, err := client.Post(ctx, path, body)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to update label: %v", err)), nil
+			return respond.Errorf("failed to update label: %v", err), nil
 		}
 
-		var label map[string]interface{}
+		var label models.Label
 		if err := json.Unmarshal(respBody, &label); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse response: %v", err)), nil
-		}
-
-		jsonData, err := json.MarshalIndent(label, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			return respond.Errorf("failed to parse response: %v", err), nil
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(label), nil
 	}
 }
 
@@ -138,29 +122,24 @@ func DeleteLabelHandler(client todoist.API) func(context.Context, mcp.CallToolRe
 
 		labelID, ok := args["label_id"].(string)
 		if !ok || labelID == "" {
-			return mcp.NewToolResultError("label_id is required"), nil
+			return respond.Error("label_id is required"), nil
 		}
 		if err := ValidateID(labelID, "label_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
 		path := fmt.Sprintf("/labels/%s", labelID)
 		err := client.Delete(ctx, path)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to delete label: %v", err)), nil
+			return respond.Errorf("failed to delete label: %v", err), nil
 		}
 
 		response := map[string]interface{}{
 			"success":  true,
 			"label_id": labelID,
-			"message":  "Label deleted successfully",
-		}
-
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			"message":  localize("label_deleted"),
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
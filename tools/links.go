@@ -0,0 +1,196 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// maxResultLinks bounds the entities linked from one tool result, so that
+// bulk operations do not bury the text content.
+const maxResultLinks = 20
+
+// EntityKinds lists the entity kinds readable through the
+// todoist://<kind>/{id} resource templates, keyed by singular name.
+var EntityKinds = map[string]string{
+	"task":    "tasks",
+	"project": "projects",
+	"section": "sections",
+	"comment": "comments",
+	"label":   "labels",
+}
+
+// EntityURI returns the MCP resource URI for an entity, e.g.
+// "todoist://tasks/123".
+func EntityURI(kind, id string) string {
+	return fmt.Sprintf("todoist://%s/%s", EntityKinds[kind], id)
+}
+
+// entityWebURL returns the Todoist web app URL for tasks and projects, which
+// are the only entities with their own pages.
+func entityWebURL(kind, id string) string {
+	switch kind {
+	case "task", "project":
+		return fmt.Sprintf("https://app.todoist.com/app/%s/%s", kind, id)
+	}
+	return ""
+}
+
+// argumentKind maps an argument such as "parent_id" or "task_ids" to the
+// entity kind it refers to. Plain "id" and "ids" take the tool's kind.
+func argumentKind(key, toolKind string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(key, "_ids"), "_id")
+	switch base {
+	case "id", "ids":
+		return toolKind
+	case "parent":
+		return "task"
+	}
+	if _, ok := EntityKinds[base]; ok {
+		return base
+	}
+	return ""
+}
+
+// toolKind infers the entity kind a tool acts on from its name, e.g. "task"
+// for add_task and complete_tasks.
+func toolKind(tool string) string {
+	noun := strings.TrimSuffix(tool[strings.LastIndex(tool, "_")+1:], "s")
+	if _, ok := EntityKinds[noun]; ok {
+		return noun
+	}
+	return ""
+}
+
+// entityRef is an entity to link from a tool result.
+type entityRef struct {
+	kind, id, name, url string
+}
+
+// deletesEntities reports whether tool deletes what it names, e.g.
+// delete_task or bulk_delete_tasks, leaving nothing to link to.
+func deletesEntities(tool string) bool {
+	return slices.Contains(strings.Split(tool, "_"), "delete")
+}
+
+// AddEntityLinks appends a resource link for every entity the tool call
+// created or touched: the *_id / *_ids arguments, the "id" of the result, and
+// the "succeeded" IDs of bulk results. Tasks and projects also get a link to
+// their Todoist web page, which hosts can open. Clients read the todoist://
+// URIs to fetch the current state. Results of delete tools get no links.
+func AddEntityLinks(tool string, args map[string]interface{}, result *mcp.CallToolResult) {
+	if result == nil || result.IsError || deletesEntities(tool) {
+		return
+	}
+	kind := toolKind(tool)
+	var refs []entityRef
+	add := func(ref entityRef) {
+		if ref.kind == "" || ref.id == "" || ValidateID(ref.id, "id") != nil {
+			return
+		}
+		for i, existing := range refs {
+			if existing.kind == ref.kind && existing.id == ref.id {
+				// The result knows more than the arguments; keep its details.
+				if ref.name != "" || ref.url != "" {
+					refs[i] = ref
+				}
+				return
+			}
+		}
+		refs = append(refs, ref)
+	}
+
+	var body map[string]interface{}
+	if json.Unmarshal([]byte(resultTextContent(result)), &body) == nil {
+		if id, ok := body["id"].(string); ok {
+			name, _ := body["content"].(string)
+			if name == "" {
+				name, _ = body["name"].(string)
+			}
+			webURL, _ := body["url"].(string)
+			add(entityRef{kind: kind, id: id, name: name, url: webURL})
+		}
+		if succeeded, ok := body["succeeded"].([]interface{}); ok {
+			for _, item := range succeeded {
+				if id, ok := item.(string); ok {
+					add(entityRef{kind: kind, id: id})
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if !strings.HasSuffix(key, "_id") && !strings.HasSuffix(key, "_ids") && key != "id" && key != "ids" {
+			continue
+		}
+		argKind := argumentKind(key, kind)
+		switch v := args[key].(type) {
+		case string:
+			add(entityRef{kind: argKind, id: v})
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					add(entityRef{kind: argKind, id: s})
+				}
+			}
+		}
+	}
+
+	for i, ref := range refs {
+		if i == maxResultLinks {
+			break
+		}
+		name := ref.name
+		if name == "" {
+			name = fmt.Sprintf("%s %s", ref.kind, ref.id)
+		}
+		webURL := ref.url
+		if webURL == "" {
+			webURL = entityWebURL(ref.kind, ref.id)
+		}
+		result.Content = append(result.Content, mcp.NewResourceLink(EntityURI(ref.kind, ref.id), name, fmt.Sprintf("Current %s as JSON", ref.kind), "application/json"))
+		if webURL != "" {
+			result.Content = append(result.Content, mcp.NewResourceLink(webURL, name, fmt.Sprintf("Open the %s in Todoist", ref.kind), "text/html"))
+		}
+	}
+}
+
+// EntityResourceHandler creates a handler for the todoist://<kind>/{id}
+// resource template, returning the entity as the REST API reports it.
+func EntityResourceHandler(client todoist.API, kind string) func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		var id string
+		switch v := req.Params.Arguments["id"].(type) {
+		case string:
+			id = v
+		case []string:
+			if len(v) > 0 {
+				id = v[0]
+			}
+		}
+		if err := ValidateID(id, "id"); err != nil {
+			return nil, err
+		}
+
+		respBody, err := client.Get(ctx, fmt.Sprintf("/%s/%s", EntityKinds[kind], id))
+		if err != nil {
+			return nil, err
+		}
+		var entity interface{}
+		if err := json.Unmarshal(respBody, &entity); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", kind, err)
+		}
+		return respond.Resource(req.Params.URI, entity)
+	}
+}
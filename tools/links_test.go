@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func resultLinks(result *mcp.CallToolResult) []mcp.ResourceLink {
+	var links []mcp.ResourceLink
+	for _, c := range result.Content {
+		if link, ok := c.(mcp.ResourceLink); ok {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+func TestAddEntityLinks(t *testing.T) {
+	t.Run("created task", func(t *testing.T) {
+		result := mcp.NewToolResultText(`{"id": "123", "content": "Buy milk", "url": "https://app.todoist.com/app/task/buy-milk-123"}`)
+		AddEntityLinks("add_task", map[string]interface{}{"content": "Buy milk", "project_id": "9"}, result)
+
+		links := resultLinks(result)
+		if len(links) != 4 {
+			t.Fatalf("expected 4 links, got %+v", links)
+		}
+		if links[0].URI != "todoist://tasks/123" || links[0].Name != "Buy milk" || links[0].MIMEType != "application/json" {
+			t.Errorf("unexpected task link: %+v", links[0])
+		}
+		if links[1].URI != "https://app.todoist.com/app/task/buy-milk-123" || links[1].Name != "Buy milk" || links[1].MIMEType != "text/html" {
+			t.Errorf("unexpected task web link: %+v", links[1])
+		}
+		if links[2].URI != "todoist://projects/9" || links[3].URI != "https://app.todoist.com/app/project/9" {
+			t.Errorf("unexpected project links: %+v", links[2:])
+		}
+	})
+
+	t.Run("bulk result", func(t *testing.T) {
+		result := mcp.NewToolResultText(`{"succeeded": ["1", "2"], "failed": []}`)
+		AddEntityLinks("complete_tasks", map[string]interface{}{"task_ids": []interface{}{"1", "2"}}, result)
+
+		links := resultLinks(result)
+		if len(links) != 4 || links[0].URI != "todoist://tasks/1" || links[2].URI != "todoist://tasks/2" {
+			t.Errorf("unexpected links: %+v", links)
+		}
+	})
+
+	t.Run("caps links", func(t *testing.T) {
+		ids := make([]interface{}, 30)
+		for i := range ids {
+			ids[i] = fmt.Sprint(i + 1)
+		}
+		result := mcp.NewToolResultText(`{}`)
+		AddEntityLinks("complete_tasks", map[string]interface{}{"task_ids": ids}, result)
+		if links := resultLinks(result); len(links) != 2*maxResultLinks {
+			t.Errorf("expected %d links, got %d", 2*maxResultLinks, len(links))
+		}
+	})
+
+	t.Run("deleted entities", func(t *testing.T) {
+		for _, tool := range []string{"delete_task", "bulk_delete_tasks", "delete_reminder"} {
+			result := mcp.NewToolResultText(`{"success": true}`)
+			AddEntityLinks(tool, map[string]interface{}{"task_id": "1", "task_ids": []interface{}{"2"}}, result)
+			if links := resultLinks(result); len(links) != 0 {
+				t.Errorf("%s: expected no links, got %+v", tool, links)
+			}
+		}
+	})
+
+	t.Run("error result", func(t *testing.T) {
+		result := mcp.NewToolResultError("task_id is required")
+		AddEntityLinks("close_task", map[string]interface{}{"task_id": "1"}, result)
+		if links := resultLinks(result); len(links) != 0 {
+			t.Errorf("expected no links on error, got %+v", links)
+		}
+	})
+}
+
+func TestEntityResourceHandler(t *testing.T) {
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if path != "/tasks/123" {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return []byte(`{"id": "123", "content": "Buy milk"}`), nil
+	}}
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "todoist://tasks/123"
+	req.Params.Arguments = map[string]any{"id": []string{"123"}}
+	contents, err := EntityResourceHandler(client, "task")(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := contents[0].(mcp.TextResourceContents)
+	if text.URI != "todoist://tasks/123" || text.Text != "{\n  \"content\": \"Buy milk\",\n  \"id\": \"123\"\n}" {
+		t.Errorf("unexpected contents: %+v", text)
+	}
+
+	req.Params.Arguments = map[string]any{"id": []string{".."}}
+	if _, err := EntityResourceHandler(client, "task")(context.Background(), req); err == nil {
+		t.Error("expected error for invalid id")
+	}
+}
@@ -0,0 +1,491 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// taskLastModified returns the most recent of the task's updated_at and
+// creation timestamps.
+func taskLastModified(task models.Task) (time.Time, bool) {
+	last, ok := taskAddedAt(task)
+	if task.UpdatedAt != "" {
+		if t, err := time.Parse(time.RFC3339Nano, task.UpdatedAt); err == nil && t.After(last) {
+			return t, true
+		}
+	}
+	return last, ok
+}
+
+// FindStaleTasksHandler creates a handler for finding undated tasks that have not changed in a while.
+func FindStaleTasksHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		days := 30
+		if d, ok := args["days"].(float64); ok {
+			if d < 1 {
+				return respond.Error("days must be at least 1"), nil
+			}
+			days = int(d)
+		}
+
+		labelName := "stale"
+		if l, ok := args["label_name"].(string); ok && l != "" {
+			labelName = l
+		}
+		applyLabel, _ := args["apply_label"].(bool)
+
+		params := url.Values{}
+		params.Set("filter", "no date")
+		if projectID, ok := args["project_id"].(string); ok && projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			params.Set("project_id", projectID)
+		}
+
+		respBody, err := client.Get(ctx, "/tasks?"+params.Encode())
+		if err != nil {
+			return respond.Errorf("failed to fetch tasks: %v", err), nil
+		}
+		var tasks []models.Task
+		if err := json.Unmarshal(respBody, &tasks); err != nil {
+			return respond.Errorf("failed to parse tasks: %v", err), nil
+		}
+
+		projectMap := make(map[string]string)
+		if projectsBody, err := client.Get(ctx, "/projects"); err == nil {
+			var projects []models.Project
+			if json.Unmarshal(projectsBody, &projects) == nil {
+				for _, proj := range projects {
+					projectMap[proj.ID] = proj.Name
+				}
+			}
+		}
+
+		cutoff := time.Now().AddDate(0, 0, -days)
+		byProject := make(map[string][]map[string]interface{})
+		var stale []models.Task
+
+		for _, task := range tasks {
+			if task.Due != nil {
+				continue
+			}
+			lastModified, ok := taskLastModified(task)
+			if !ok || !lastModified.Before(cutoff) {
+				continue
+			}
+			stale = append(stale, task)
+
+			projectName := "Unknown"
+			if name := projectMap[task.ProjectID]; name != "" {
+				projectName = name
+			}
+			byProject[projectName] = append(byProject[projectName], map[string]interface{}{
+				"id":            task.ID,
+				"content":       task.Content,
+				"last_modified": lastModified.UTC().Format(time.RFC3339),
+				"idle_days":     int(time.Since(lastModified).Hours() / 24),
+			})
+		}
+
+		for _, entries := range byProject {
+			sort.Slice(entries, func(i, j int) bool {
+				return entries[i]["idle_days"].(int) > entries[j]["idle_days"].(int)
+			})
+		}
+
+		response := map[string]interface{}{
+			"days":        days,
+			"stale_count": len(stale),
+			"by_project":  byProject,
+		}
+
+		if applyLabel && len(stale) > 0 {
+			commands := make([]todoist.Command, 0, len(stale))
+			for _, task := range stale {
+				if taskHasLabel(task, labelName) {
+					continue
+				}
+				commands = append(commands, todoist.Command{
+					Type: "item_update",
+					UUID: todoist.GenerateUUID(),
+					Args: map[string]interface{}{
+						"id":     task.ID,
+						"labels": append(slices.Clone(task.Labels), labelName),
+					},
+				})
+			}
+
+			labeled := 0
+			if len(commands) > 0 {
+				// A large backlog can exceed the Sync API's per-request limit.
+				syncResp, err := todoist.SendCommands(ctx, syncClient, commands)
+				if err != nil {
+					return respond.Errorf("failed to label stale tasks: %v", err), nil
+				}
+				for _, cmd := range commands {
+					if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+						labeled++
+					}
+				}
+			}
+			response["labeled"] = labeled
+			response["label_name"] = labelName
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// CleanupWorkspaceHandler creates a handler for finding and optionally removing empty sections and projects.
+// A report issues a confirmation token for its candidates; archive and delete
+// require that token and act only on the candidates it was issued for that
+// are still empty.
+func CleanupWorkspaceHandler(client todoist.API, syncClient todoist.SyncAPI, confirmations *ConfirmationStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		action := "report"
+		if a, ok := args["action"].(string); ok && a != "" {
+			action = a
+		}
+		if action != "report" && action != "archive" && action != "delete" {
+			return respond.Error("action must be one of: report, archive, delete"), nil
+		}
+
+		scope := confirmationScope("cleanup_workspace")
+		var previewed map[string]bool
+		if action != "report" {
+			token, _ := args["confirmation_token"].(string)
+			if token == "" {
+				return respond.Errorf("action '%s' requires the confirmation_token from a 'report' call; run with action 'report' first to review the candidates", action), nil
+			}
+			ids, err := confirmations.redeem(token, scope)
+			if err != nil {
+				return respond.Error("confirmation_token is invalid, expired, or was issued by another tool; run action 'report' again for a new token"), nil
+			}
+			previewed = make(map[string]bool, len(ids))
+			for _, id := range ids {
+				previewed[id] = true
+			}
+		}
+
+		projectsBody, err := client.Get(ctx, "/projects")
+		if err != nil {
+			return respond.Errorf("failed to fetch projects: %v", err), nil
+		}
+		var projects []models.Project
+		if err := json.Unmarshal(projectsBody, &projects); err != nil {
+			return respond.Errorf("failed to parse projects: %v", err), nil
+		}
+
+		sectionsBody, err := client.Get(ctx, "/sections")
+		if err != nil {
+			return respond.Errorf("failed to fetch sections: %v", err), nil
+		}
+		var sections []models.Section
+		if err := json.Unmarshal(sectionsBody, &sections); err != nil {
+			return respond.Errorf("failed to parse sections: %v", err), nil
+		}
+
+		tasksBody, err := client.Get(ctx, "/tasks")
+		if err != nil {
+			return respond.Errorf("failed to fetch tasks: %v", err), nil
+		}
+		var tasks []models.Task
+		if err := json.Unmarshal(tasksBody, &tasks); err != nil {
+			return respond.Errorf("failed to parse tasks: %v", err), nil
+		}
+
+		tasksPerProject := make(map[string]int)
+		tasksPerSection := make(map[string]int)
+		for _, task := range tasks {
+			tasksPerProject[task.ProjectID]++
+			if task.SectionID != "" {
+				tasksPerSection[task.SectionID]++
+			}
+		}
+
+		hasChildren := make(map[string]bool)
+		for _, proj := range projects {
+			if proj.ParentID != "" {
+				hasChildren[proj.ParentID] = true
+			}
+		}
+
+		emptySections := make([]map[string]interface{}, 0)
+		for _, section := range sections {
+			if previewed != nil && !previewed[section.ID] {
+				continue
+			}
+			if tasksPerSection[section.ID] == 0 {
+				emptySections = append(emptySections, map[string]interface{}{
+					"id":         section.ID,
+					"name":       section.Name,
+					"project_id": section.ProjectID,
+				})
+			}
+		}
+
+		emptyProjects := make([]map[string]interface{}, 0)
+		for _, proj := range projects {
+			if proj.IsInboxProject || proj.IsTeamInbox || proj.IsFavorite {
+				continue
+			}
+			if hasChildren[proj.ID] || tasksPerProject[proj.ID] > 0 {
+				continue
+			}
+			if previewed != nil && !previewed[proj.ID] {
+				continue
+			}
+			emptyProjects = append(emptyProjects, map[string]interface{}{
+				"id":   proj.ID,
+				"name": proj.Name,
+			})
+		}
+
+		response := map[string]interface{}{
+			"action":         action,
+			"empty_sections": emptySections,
+			"empty_projects": emptyProjects,
+		}
+
+		if action == "report" && len(emptySections)+len(emptyProjects) > 0 {
+			ids := make([]string, 0, len(emptySections)+len(emptyProjects))
+			for _, section := range emptySections {
+				ids = append(ids, section["id"].(string))
+			}
+			for _, p := range emptyProjects {
+				ids = append(ids, p["id"].(string))
+			}
+			token, expiresAt := confirmations.issue(scope, ids)
+			response["confirmation_token"] = token
+			response["expires_at"] = expiresAt.UTC().Format(time.RFC3339)
+		}
+
+		if action != "report" && len(emptySections)+len(emptyProjects) > 0 {
+			emptyProjectIDs := make(map[string]bool, len(emptyProjects))
+			for _, p := range emptyProjects {
+				emptyProjectIDs[p["id"].(string)] = true
+			}
+
+			commands := make([]todoist.Command, 0, len(emptySections)+len(emptyProjects))
+			for _, section := range emptySections {
+				// Sections inside a project being removed go with the project.
+				if pid, _ := section["project_id"].(string); emptyProjectIDs[pid] {
+					continue
+				}
+				commands = append(commands, todoist.Command{
+					Type: "section_" + action,
+					UUID: todoist.GenerateUUID(),
+					Args: map[string]interface{}{"id": section["id"]},
+				})
+			}
+			for _, p := range emptyProjects {
+				commands = append(commands, todoist.Command{
+					Type: "project_" + action,
+					UUID: todoist.GenerateUUID(),
+					Args: map[string]interface{}{"id": p["id"]},
+				})
+			}
+
+			// Sections are sent before projects, so chunks keep that order.
+			syncResp, err := todoist.SendCommands(ctx, syncClient, commands)
+			if err != nil {
+				return respond.Errorf("failed to %s empty items: %v", action, err), nil
+			}
+
+			succeeded := 0
+			failed := make([]interface{}, 0)
+			for _, cmd := range commands {
+				if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+					succeeded++
+				} else {
+					failed = append(failed, cmd.Args["id"])
+				}
+			}
+			response["processed"] = succeeded
+			response["failed_ids"] = failed
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// todoistColors lists the color names accepted by the Todoist API for projects and labels.
+var todoistColors = []string{
+	"berry_red", "red", "orange", "yellow", "olive_green", "lime_green", "green", "mint_green", "teal", "sky_blue",
+	"light_blue", "blue", "grape", "violet", "lavender", "magenta", "salmon", "charcoal", "grey", "taupe",
+}
+
+// colorRule maps entities whose name contains Match to Color.
+type colorRule struct {
+	Match              string
+	Entity             string
+	Color              string
+	IncludeSubProjects bool
+}
+
+// NormalizeColorsHandler creates a handler for listing and bulk-applying project and label colors.
+func NormalizeColorsHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		var rules []colorRule
+		if rulesParam, ok := args["rules"].([]interface{}); ok {
+			for i, r := range rulesParam {
+				ruleMap, ok := r.(map[string]interface{})
+				if !ok {
+					return respond.Errorf("rule at index %d is not a valid object", i), nil
+				}
+				rule := colorRule{Entity: "all"}
+				rule.Match, _ = ruleMap["match"].(string)
+				rule.Color, _ = ruleMap["color"].(string)
+				if e, ok := ruleMap["entity"].(string); ok && e != "" {
+					rule.Entity = e
+				}
+				rule.IncludeSubProjects, _ = ruleMap["include_sub_projects"].(bool)
+				if rule.Match == "" {
+					return respond.Errorf("rule at index %d missing required 'match' field", i), nil
+				}
+				if !slices.Contains(todoistColors, rule.Color) {
+					return respond.Errorf("rule at index %d has invalid color %q", i, rule.Color), nil
+				}
+				if rule.Entity != "all" && rule.Entity != "project" && rule.Entity != "label" {
+					return respond.Errorf("rule at index %d has invalid entity %q (use project, label, or all)", i, rule.Entity), nil
+				}
+				rules = append(rules, rule)
+			}
+		}
+		dryRun, _ := args["dry_run"].(bool)
+
+		projectsBody, err := client.Get(ctx, "/projects")
+		if err != nil {
+			return respond.Errorf("failed to fetch projects: %v", err), nil
+		}
+		var projects []models.Project
+		if err := json.Unmarshal(projectsBody, &projects); err != nil {
+			return respond.Errorf("failed to parse projects: %v", err), nil
+		}
+
+		labelsBody, err := client.Get(ctx, "/labels")
+		if err != nil {
+			return respond.Errorf("failed to fetch labels: %v", err), nil
+		}
+		var labels []models.Label
+		if err := json.Unmarshal(labelsBody, &labels); err != nil {
+			return respond.Errorf("failed to parse labels: %v", err), nil
+		}
+
+		currentColors := map[string]map[string][]string{
+			"projects": {},
+			"labels":   {},
+		}
+		projectByID := make(map[string]models.Project, len(projects))
+		for _, proj := range projects {
+			projectByID[proj.ID] = proj
+			currentColors["projects"][proj.Color] = append(currentColors["projects"][proj.Color], proj.Name)
+		}
+		for _, label := range labels {
+			currentColors["labels"][label.Color] = append(currentColors["labels"][label.Color], label.Name)
+		}
+
+		response := map[string]interface{}{
+			"current_colors": currentColors,
+		}
+
+		if len(rules) == 0 {
+			return respond.JSON(response), nil
+		}
+
+		// projectMatches reports whether the project, or an ancestor when
+		// include_sub_projects is set, has a name containing the rule's match.
+		projectMatches := func(proj models.Project, rule colorRule) bool {
+			for depth := 0; depth < maxParentDepth; depth++ {
+				if strings.Contains(strings.ToLower(proj.Name), strings.ToLower(rule.Match)) {
+					return true
+				}
+				parent, ok := projectByID[proj.ParentID]
+				if !rule.IncludeSubProjects || !ok {
+					return false
+				}
+				proj = parent
+			}
+			return false
+		}
+
+		changes := make([]map[string]interface{}, 0)
+		commands := make([]todoist.Command, 0)
+
+		for _, proj := range projects {
+			for _, rule := range rules {
+				if rule.Entity == "label" || !projectMatches(proj, rule) {
+					continue
+				}
+				if proj.Color != rule.Color {
+					changes = append(changes, map[string]interface{}{
+						"type": "project", "id": proj.ID, "name": proj.Name,
+						"from": proj.Color, "to": rule.Color,
+					})
+					commands = append(commands, todoist.Command{
+						Type: "project_update",
+						UUID: todoist.GenerateUUID(),
+						Args: map[string]interface{}{"id": proj.ID, "color": rule.Color},
+					})
+				}
+				break
+			}
+		}
+		for _, label := range labels {
+			for _, rule := range rules {
+				if rule.Entity == "project" || !strings.Contains(strings.ToLower(label.Name), strings.ToLower(rule.Match)) {
+					continue
+				}
+				if label.Color != rule.Color {
+					changes = append(changes, map[string]interface{}{
+						"type": "label", "id": label.ID, "name": label.Name,
+						"from": label.Color, "to": rule.Color,
+					})
+					commands = append(commands, todoist.Command{
+						Type: "label_update",
+						UUID: todoist.GenerateUUID(),
+						Args: map[string]interface{}{"id": label.ID, "color": rule.Color},
+					})
+				}
+				break
+			}
+		}
+
+		response["changes"] = changes
+		response["dry_run"] = dryRun
+
+		if !dryRun && len(commands) > 0 {
+			syncResp, err := todoist.SendCommands(ctx, syncClient, commands)
+			if err != nil {
+				return respond.Errorf("failed to apply colors: %v", err), nil
+			}
+			applied := 0
+			for _, cmd := range commands {
+				if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+					applied++
+				}
+			}
+			response["applied"] = applied
+			response["failed"] = len(commands) - applied
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,541 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestFindStaleTasksHandler(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -60).UTC().Format(time.RFC3339)
+	recent := time.Now().AddDate(0, 0, -2).UTC().Format(time.RFC3339)
+
+	mockGet := func(_ context.Context, path string) ([]byte, error) {
+		switch {
+		case strings.HasPrefix(path, "/tasks?"):
+			if !strings.Contains(path, "filter=no+date") {
+				return nil, fmt.Errorf("expected no date filter, got: %s", path)
+			}
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1", "content": "Old idea", "project_id": "p1", "created_at": old},
+				{"id": "2", "content": "Fresh", "project_id": "p1", "created_at": recent},
+				{"id": "3", "content": "Old but touched", "project_id": "p1", "created_at": old, "updated_at": recent},
+				{"id": "4", "content": "Already stale", "project_id": "p2", "created_at": old, "labels": []string{"stale"}},
+			})
+		case path == "/projects":
+			return json.Marshal([]map[string]interface{}{{"id": "p1", "name": "Ideas"}, {"id": "p2", "name": "Home"}})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		mockGet      func(ctx context.Context, path string) ([]byte, error)
+		wantErr      bool
+		errSubstr    string
+		wantStale    int
+		wantCommands int
+	}{
+		{
+			name:      "report only",
+			args:      map[string]interface{}{},
+			mockGet:   mockGet,
+			wantStale: 2,
+		},
+		{
+			name:         "apply label skips already labeled",
+			args:         map[string]interface{}{"apply_label": true},
+			mockGet:      mockGet,
+			wantStale:    2,
+			wantCommands: 1,
+		},
+		{
+			name:      "invalid days",
+			args:      map[string]interface{}{"days": float64(0)},
+			wantErr:   true,
+			errSubstr: "days must be at least 1",
+		},
+		{
+			name: "API error",
+			args: map[string]interface{}{},
+			mockGet: func(_ context.Context, _ string) ([]byte, error) {
+				return nil, fmt.Errorf("boom")
+			},
+			wantErr:   true,
+			errSubstr: "failed to fetch tasks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []todoist.Command
+			client := &MockAPI{GetFn: tt.mockGet}
+			syncClient := &MockSyncAPI{
+				BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+					sent = commands
+					status := make(map[string]interface{})
+					for _, c := range commands {
+						status[c.UUID] = "ok"
+					}
+					return &todoist.SyncResponse{SyncStatus: status}, nil
+				},
+			}
+			handler := FindStaleTasksHandler(client, syncClient)
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if int(resp["stale_count"].(float64)) != tt.wantStale {
+				t.Errorf("stale_count = %v, want %d", resp["stale_count"], tt.wantStale)
+			}
+			if len(sent) != tt.wantCommands {
+				t.Fatalf("sent %d commands, want %d", len(sent), tt.wantCommands)
+			}
+			if tt.wantCommands > 0 {
+				labels := sent[0].Args["labels"].([]string)
+				if labels[len(labels)-1] != "stale" {
+					t.Errorf("labels = %v, want stale appended", labels)
+				}
+			}
+		})
+	}
+}
+
+func TestFindStaleTasksHandler_ManyTasks(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -60).UTC().Format(time.RFC3339)
+	tasks := make([]map[string]interface{}, 150)
+	for i := range tasks {
+		tasks[i] = map[string]interface{}{"id": fmt.Sprint(i), "content": "Old idea", "project_id": "p1", "created_at": old}
+	}
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if path == "/projects" {
+			return json.Marshal([]map[string]interface{}{{"id": "p1", "name": "Ideas"}})
+		}
+		return json.Marshal(tasks)
+	}}
+	var batches []int
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		batches = append(batches, len(commands))
+		status := make(map[string]interface{})
+		for _, c := range commands {
+			status[c.UUID] = "ok"
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+
+	result, err := FindStaleTasksHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{"apply_label": true}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	if fmt.Sprint(batches) != "[100 50]" {
+		t.Errorf("batch sizes = %v, want [100 50]", batches)
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp["labeled"] != float64(150) {
+		t.Errorf("labeled = %v, want 150", resp["labeled"])
+	}
+}
+
+func TestCleanupWorkspaceHandler(t *testing.T) {
+	mockGet := func(_ context.Context, path string) ([]byte, error) {
+		switch path {
+		case "/projects":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "inbox", "name": "Inbox", "is_inbox_project": true},
+				{"id": "fav", "name": "Pinned", "is_favorite": true},
+				{"id": "parent", "name": "Parent"},
+				{"id": "child", "name": "Child", "parent_id": "parent"},
+				{"id": "empty", "name": "Empty"},
+				{"id": "busy", "name": "Busy"},
+			})
+		case "/sections":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "s1", "name": "Todo", "project_id": "busy"},
+				{"id": "s2", "name": "Later", "project_id": "busy"},
+				{"id": "s3", "name": "Old", "project_id": "empty"},
+			})
+		case "/tasks":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1", "project_id": "busy", "section_id": "s1"},
+				{"id": "2", "project_id": "child"},
+			})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		reportFirst  bool
+		wantErr      bool
+		errSubstr    string
+		wantSections int
+		wantProjects int
+		wantCommands []string
+	}{
+		{
+			name:         "report",
+			args:         map[string]interface{}{},
+			wantSections: 2,
+			wantProjects: 1,
+		},
+		{
+			name:      "archive without token",
+			args:      map[string]interface{}{"action": "archive"},
+			wantErr:   true,
+			errSubstr: "requires the confirmation_token",
+		},
+		{
+			name:      "archive with unknown token",
+			args:      map[string]interface{}{"action": "archive", "confirmation_token": "nope"},
+			wantErr:   true,
+			errSubstr: "confirmation_token is invalid",
+		},
+		{
+			name:      "invalid action",
+			args:      map[string]interface{}{"action": "purge"},
+			wantErr:   true,
+			errSubstr: "action must be one of",
+		},
+		{
+			name:         "archive with report token",
+			args:         map[string]interface{}{"action": "archive"},
+			reportFirst:  true,
+			wantSections: 2,
+			wantProjects: 1,
+			wantCommands: []string{"section_archive", "project_archive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []todoist.Command
+			client := &MockAPI{GetFn: mockGet}
+			syncClient := &MockSyncAPI{
+				BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+					sent = commands
+					status := make(map[string]interface{})
+					for _, c := range commands {
+						status[c.UUID] = "ok"
+					}
+					return &todoist.SyncResponse{SyncStatus: status}, nil
+				},
+			}
+			handler := CleanupWorkspaceHandler(client, syncClient, NewConfirmationStore(time.Minute))
+			if tt.reportFirst {
+				tt.args["confirmation_token"] = cleanupReportToken(t, handler)
+			}
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				EmptySections []map[string]interface{} `json:"empty_sections"`
+				EmptyProjects []map[string]interface{} `json:"empty_projects"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if len(resp.EmptySections) != tt.wantSections || len(resp.EmptyProjects) != tt.wantProjects {
+				t.Errorf("empty sections = %d, projects = %d, want %d and %d",
+					len(resp.EmptySections), len(resp.EmptyProjects), tt.wantSections, tt.wantProjects)
+			}
+			if len(sent) != len(tt.wantCommands) {
+				t.Fatalf("sent %d commands, want %d", len(sent), len(tt.wantCommands))
+			}
+			for i, want := range tt.wantCommands {
+				if sent[i].Type != want {
+					t.Errorf("command[%d] = %s, want %s", i, sent[i].Type, want)
+				}
+			}
+		})
+	}
+}
+
+// cleanupReportToken runs a cleanup_workspace report and returns its confirmation token.
+func cleanupReportToken(t *testing.T, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) string {
+	t.Helper()
+	result, err := handler(context.Background(), makeReq(map[string]interface{}{}))
+	if err != nil || result.IsError {
+		t.Fatalf("report failed: %v %s", err, resultText(result))
+	}
+	var resp struct {
+		ConfirmationToken string `json:"confirmation_token"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil || resp.ConfirmationToken == "" {
+		t.Fatalf("report returned no confirmation_token: %s", resultText(result))
+	}
+	return resp.ConfirmationToken
+}
+
+func TestCleanupWorkspaceHandler_Token(t *testing.T) {
+	var taskCount int
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		switch path {
+		case "/projects":
+			return json.Marshal([]map[string]interface{}{{"id": "a", "name": "Old"}, {"id": "b", "name": "Older"}})
+		case "/sections":
+			return json.Marshal([]map[string]interface{}{})
+		}
+		// A task added to project b after the report.
+		tasks := make([]map[string]interface{}, 0)
+		if taskCount > 0 {
+			tasks = append(tasks, map[string]interface{}{"id": "1", "project_id": "b"})
+		}
+		return json.Marshal(tasks)
+	}}
+	var sent []todoist.Command
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		sent = append(sent, commands...)
+		status := make(map[string]interface{})
+		for _, c := range commands {
+			status[c.UUID] = "ok"
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+	handler := CleanupWorkspaceHandler(client, syncClient, NewConfirmationStore(time.Minute))
+
+	token := cleanupReportToken(t, handler)
+	taskCount = 1
+	args := map[string]interface{}{"action": "delete", "confirmation_token": token}
+	result, err := handler(context.Background(), makeReq(args))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	if len(sent) != 1 || sent[0].Type != "project_delete" || sent[0].Args["id"] != "a" {
+		t.Errorf("sent = %+v, want only project_delete of a", sent)
+	}
+
+	result, _ = handler(context.Background(), makeReq(args))
+	if !result.IsError || !strings.Contains(resultText(result), "invalid") {
+		t.Errorf("reused token accepted: %s", resultText(result))
+	}
+}
+
+func TestCleanupWorkspaceHandler_ManySections(t *testing.T) {
+	sections := make([]map[string]interface{}, 120)
+	for i := range sections {
+		sections[i] = map[string]interface{}{"id": fmt.Sprint("s", i), "name": "Old", "project_id": "busy"}
+	}
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		switch path {
+		case "/projects":
+			return json.Marshal([]map[string]interface{}{{"id": "busy", "name": "Busy"}})
+		case "/sections":
+			return json.Marshal(sections)
+		}
+		return json.Marshal([]map[string]interface{}{{"id": "1", "project_id": "busy"}})
+	}}
+	var batches []int
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		batches = append(batches, len(commands))
+		status := make(map[string]interface{})
+		for _, c := range commands {
+			status[c.UUID] = "ok"
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+
+	handler := CleanupWorkspaceHandler(client, syncClient, NewConfirmationStore(time.Minute))
+	token := cleanupReportToken(t, handler)
+	result, err := handler(context.Background(), makeReq(map[string]interface{}{"action": "archive", "confirmation_token": token}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	if fmt.Sprint(batches) != "[100 20]" {
+		t.Errorf("batch sizes = %v, want [100 20]", batches)
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp["processed"] != float64(120) {
+		t.Errorf("processed = %v, want 120", resp["processed"])
+	}
+}
+
+func TestNormalizeColorsHandler(t *testing.T) {
+	mockGet := func(_ context.Context, path string) ([]byte, error) {
+		switch path {
+		case "/projects":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "p1", "name": "Work", "color": "red"},
+				{"id": "p2", "name": "Clients", "color": "green", "parent_id": "p1"},
+				{"id": "p3", "name": "Home", "color": "blue"},
+			})
+		case "/labels":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "l1", "name": "work-urgent", "color": "grey"},
+				{"id": "l2", "name": "errand", "color": "grey"},
+			})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		wantErr     bool
+		errSubstr   string
+		wantChanges int
+		wantSent    int
+	}{
+		{
+			name: "list only",
+			args: map[string]interface{}{},
+		},
+		{
+			name: "apply to sub-projects and labels",
+			args: map[string]interface{}{"rules": []interface{}{
+				map[string]interface{}{"match": "work", "color": "blue", "include_sub_projects": true},
+			}},
+			wantChanges: 3,
+			wantSent:    3,
+		},
+		{
+			name: "dry run",
+			args: map[string]interface{}{"dry_run": true, "rules": []interface{}{
+				map[string]interface{}{"match": "work", "entity": "project", "color": "blue"},
+			}},
+			wantChanges: 1,
+		},
+		{
+			name: "invalid color",
+			args: map[string]interface{}{"rules": []interface{}{
+				map[string]interface{}{"match": "work", "color": "pink"},
+			}},
+			wantErr:   true,
+			errSubstr: "invalid color",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []todoist.Command
+			client := &MockAPI{GetFn: mockGet}
+			syncClient := &MockSyncAPI{
+				BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+					sent = commands
+					status := make(map[string]interface{})
+					for _, c := range commands {
+						status[c.UUID] = "ok"
+					}
+					return &todoist.SyncResponse{SyncStatus: status}, nil
+				},
+			}
+			handler := NormalizeColorsHandler(client, syncClient)
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				CurrentColors map[string]map[string][]string `json:"current_colors"`
+				Changes       []map[string]interface{}       `json:"changes"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if len(resp.CurrentColors["labels"]["grey"]) != 2 {
+				t.Errorf("current_colors.labels.grey = %v", resp.CurrentColors["labels"]["grey"])
+			}
+			if len(resp.Changes) != tt.wantChanges {
+				t.Errorf("changes = %d, want %d", len(resp.Changes), tt.wantChanges)
+			}
+			if len(sent) != tt.wantSent {
+				t.Errorf("sent %d commands, want %d", len(sent), tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestNormalizeColorsHandler_ManyLabels(t *testing.T) {
+	labels := make([]map[string]interface{}, 130)
+	for i := range labels {
+		labels[i] = map[string]interface{}{"id": fmt.Sprint("l", i), "name": fmt.Sprint("work-", i), "color": "grey"}
+	}
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if path == "/labels" {
+			return json.Marshal(labels)
+		}
+		return json.Marshal([]map[string]interface{}{})
+	}}
+	var batches []int
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		batches = append(batches, len(commands))
+		status := make(map[string]interface{})
+		for _, c := range commands {
+			status[c.UUID] = "ok"
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+
+	result, err := NormalizeColorsHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{"rules": []interface{}{
+		map[string]interface{}{"match": "work", "color": "blue"},
+	}}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	if fmt.Sprint(batches) != "[100 30]" {
+		t.Errorf("batch sizes = %v, want [100 30]", batches)
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp["applied"] != float64(130) || resp["failed"] != float64(0) {
+		t.Errorf("applied = %v, failed = %v, want 130 and 0", resp["applied"], resp["failed"])
+	}
+}
@@ -55,6 +55,7 @@ func (m *MockAPI) GetRemainingRequests() int {
 // MockSyncAPI implements todoist.SyncAPI for testing.
 type MockSyncAPI struct {
 	BatchCommandsFn        func(ctx context.Context, commands []todoist.Command) (*todoist.SyncResponse, error)
+	GetFn                  func(ctx context.Context, path string) ([]byte, error)
 	GetRemainingRequestsFn func() int
 }
 
@@ -65,6 +66,13 @@ func (m *MockSyncAPI) BatchCommands(ctx context.Context, commands []todoist.Comm
 	return nil, fmt.Errorf("BatchCommands not configured")
 }
 
+func (m *MockSyncAPI) Get(ctx context.Context, path string) ([]byte, error) {
+	if m.GetFn != nil {
+		return m.GetFn(ctx, path)
+	}
+	return nil, fmt.Errorf("Get not configured")
+}
+
 func (m *MockSyncAPI) GetRemainingRequests() int {
 	if m.GetRemainingRequestsFn != nil {
 		return m.GetRemainingRequestsFn()
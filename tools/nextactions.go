@@ -0,0 +1,214 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// energyLevels ranks the energy a task needs, from its low-energy or
+// high-energy label; unlabeled tasks need medium energy.
+var energyLevels = map[string]int{"low": 0, "medium": 1, "high": 2}
+
+// defaultNextActions and maxNextActions bound the suggestions returned.
+const (
+	defaultNextActions = 5
+	maxNextActions     = 20
+)
+
+// taskEnergy returns the energy level a task needs.
+func taskEnergy(task models.Task) string {
+	switch {
+	case taskHasLabel(task, "low-energy"):
+		return "low"
+	case taskHasLabel(task, "high-energy"):
+		return "high"
+	}
+	return "medium"
+}
+
+// nextActionScore rates how well a task fits now. Higher is better; reasons
+// explain the score.
+func nextActionScore(task models.Task, today time.Time, minutes int, energy string) (int, []string) {
+	score := 0
+	reasons := make([]string, 0)
+
+	if p := task.Priority; p > 1 {
+		score += (p - 1) * 10
+		reasons = append(reasons, fmt.Sprintf("priority p%d", 5-p))
+	}
+
+	// The nearer of the due date and the deadline sets the urgency.
+	days, dated := 0, false
+	for _, day := range []string{task.Due.Day(), task.Deadline.Day()} {
+		date, err := time.ParseInLocation("2006-01-02", day, today.Location())
+		if err != nil {
+			continue
+		}
+		if d := int(date.Sub(today).Hours() / 24); !dated || d < days {
+			days, dated = d, true
+		}
+	}
+	switch {
+	case !dated:
+	case days < 0:
+		score += 40
+		reasons = append(reasons, "overdue")
+	case days == 0:
+		score += 30
+		reasons = append(reasons, "due today")
+	case days <= 3:
+		score += 20
+		reasons = append(reasons, fmt.Sprintf("due in %d days", days))
+	case days <= 7:
+		score += 10
+		reasons = append(reasons, "due this week")
+	}
+
+	// Tasks that use most of the available time make the best use of it.
+	if estimate, _, ok := taskEstimate(task); ok {
+		score += 10 * estimate / minutes
+		reasons = append(reasons, fmt.Sprintf("fits in %d of %d minutes", estimate, minutes))
+	}
+
+	if needed := taskEnergy(task); needed == energy && needed != "medium" {
+		score += 10
+		reasons = append(reasons, needed+" energy")
+	}
+	return score, reasons
+}
+
+// SuggestNextActionsHandler creates a handler that ranks the tasks fitting the
+// user's available time, energy, and context.
+func SuggestNextActionsHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		m, ok := args["minutes"].(float64)
+		if !ok || m < 1 {
+			return respond.Error("minutes must be at least 1"), nil
+		}
+		minutes := int(m)
+		energy := "medium"
+		if e, ok := args["energy"].(string); ok && e != "" {
+			if _, known := energyLevels[e]; !known {
+				return respond.Error("energy must be one of: low, medium, high"), nil
+			}
+			energy = e
+		}
+		var contexts []string
+		if raw, ok := args["contexts"].([]interface{}); ok {
+			for _, c := range raw {
+				if s, ok := c.(string); ok && strings.TrimPrefix(s, "@") != "" {
+					contexts = append(contexts, strings.TrimPrefix(s, "@"))
+				}
+			}
+		}
+		limit := defaultNextActions
+		if l, ok := args["limit"].(float64); ok {
+			if l < 1 || l > maxNextActions {
+				return respond.Errorf("limit must be between 1 and %d", maxNextActions), nil
+			}
+			limit = int(l)
+		}
+
+		loc, err := userLocation(ctx, syncClient)
+		if err != nil {
+			return respond.Errorf("failed to resolve user time zone: %v", err), nil
+		}
+		now := time.Now().In(loc)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+		tasks, err := fetchTasks(ctx, client, url.Values{})
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		active := make(map[string]bool, len(tasks))
+		for _, task := range tasks {
+			active[task.ID] = true
+		}
+		graph := blockedByGraph(tasks)
+
+		type candidate struct {
+			entry map[string]interface{}
+			score int
+			mins  int
+		}
+		var candidates []candidate
+		skipped := map[string]int{"too_long": 0, "too_demanding": 0, "deferred": 0, "blocked": 0}
+		for _, task := range tasks {
+			if len(contexts) > 0 && !slices.ContainsFunc(contexts, func(c string) bool { return taskHasLabel(task, c) }) {
+				continue
+			}
+			if taskDeferDate(task) > today.Format("2006-01-02") {
+				skipped["deferred"]++
+				continue
+			}
+			if slices.ContainsFunc(graph[task.ID], func(id string) bool { return active[id] }) {
+				skipped["blocked"]++
+				continue
+			}
+			if energyLevels[taskEnergy(task)] > energyLevels[energy] {
+				skipped["too_demanding"]++
+				continue
+			}
+			estimate, _, estimated := taskEstimate(task)
+			if estimated && estimate > minutes {
+				skipped["too_long"]++
+				continue
+			}
+
+			score, reasons := nextActionScore(task, today, minutes, energy)
+			entry := map[string]interface{}{
+				"id":         task.ID,
+				"content":    task.Content,
+				"project_id": task.ProjectID,
+				"score":      score,
+				"reasons":    reasons,
+			}
+			if estimated {
+				entry["estimated_minutes"] = estimate
+			} else {
+				entry["estimated_minutes"] = nil
+			}
+			if due := task.Due.Day(); due != "" {
+				entry["due"] = due
+			}
+			candidates = append(candidates, candidate{entry: entry, score: score, mins: estimate})
+		}
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+		suggestions := make([]map[string]interface{}, 0, limit)
+		// The plan takes the best-scoring estimated tasks that fit together.
+		plan := make([]interface{}, 0)
+		planned := 0
+		for i, c := range candidates {
+			if i < limit {
+				suggestions = append(suggestions, c.entry)
+			}
+			if c.mins > 0 && planned+c.mins <= minutes {
+				plan = append(plan, c.entry["id"])
+				planned += c.mins
+			}
+		}
+
+		response := respond.List("suggestions", suggestions).
+			Set("minutes", minutes).
+			Set("energy", energy).
+			Set("plan", map[string]interface{}{"task_ids": plan, "estimated_minutes": planned}).
+			Set("skipped", skipped)
+		if len(contexts) > 0 {
+			response.Set("contexts", contexts)
+		}
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+)
+
+func TestNextActionScore(t *testing.T) {
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		task models.Task
+		want int
+	}{
+		{"plain", models.Task{}, 0},
+		{"p1 overdue", models.Task{Priority: 4, Due: &models.Due{Date: "2026-10-15"}}, 70},
+		{"deadline nearer than due", models.Task{Due: &models.Due{Date: "2026-10-30"}, Deadline: &models.Deadline{Date: "2026-10-18"}}, 20},
+		{"fills half the time", models.Task{Labels: []string{"30min"}}, 5},
+		{"matches low energy", models.Task{Labels: []string{"low-energy"}}, 10},
+	}
+	for _, tt := range tests {
+		if got, reasons := nextActionScore(tt.task, today, 60, "low"); got != tt.want {
+			t.Errorf("%s: score = %d (%v), want %d", tt.name, got, reasons, tt.want)
+		}
+	}
+}
+
+func TestSuggestNextActionsHandler(t *testing.T) {
+	today := time.Now().UTC().Format("2006-01-02")
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	client := &MockAPI{GetFn: func(_ context.Context, _ string) ([]byte, error) {
+		return json.Marshal([]map[string]interface{}{
+			{"id": "call", "content": "Call mom", "labels": []interface{}{"home", "15min"}, "priority": float64(3)},
+			{"id": "taxes", "content": "Do taxes", "labels": []interface{}{"home", "2h"}, "priority": float64(4)},
+			{"id": "dishes", "content": "Dishes", "labels": []interface{}{"home", "30min", "low-energy"}, "due": map[string]interface{}{"date": today}},
+			{"id": "refactor", "content": "Refactor", "labels": []interface{}{"home", "high-energy", "30min"}},
+			{"id": "later", "content": "Paint fence", "labels": []interface{}{"home"}, "description": "ref:defer=" + tomorrow},
+			{"id": "waiting", "content": "Assemble shelf", "labels": []interface{}{"home"}, "description": "ref:blocked-by=call"},
+			{"id": "milk", "content": "Buy milk", "labels": []interface{}{"errands", "10min"}},
+			{"id": "read", "content": "Read article", "labels": []interface{}{"home"}},
+		})
+	}}
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, _ string) ([]byte, error) {
+		return []byte(`{"tz_info":{"timezone":"UTC"}}`), nil
+	}}
+	handler := SuggestNextActionsHandler(client, syncClient)
+
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{
+		"minutes": float64(45), "energy": "medium", "contexts": []interface{}{"@home"},
+	}))
+	var resp struct {
+		Suggestions []map[string]interface{} `json:"suggestions"`
+		Plan        struct {
+			TaskIDs []string `json:"task_ids"`
+			Minutes int      `json:"estimated_minutes"`
+		} `json:"plan"`
+		Skipped map[string]int `json:"skipped"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("parse: %v (%s)", err, resultText(result))
+	}
+	var ids []string
+	for _, s := range resp.Suggestions {
+		ids = append(ids, s["id"].(string))
+	}
+	if strings.Join(ids, ",") != "dishes,call,read" {
+		t.Errorf("suggestions = %v", ids)
+	}
+	if strings.Join(resp.Plan.TaskIDs, ",") != "dishes,call" || resp.Plan.Minutes != 45 {
+		t.Errorf("plan = %+v", resp.Plan)
+	}
+	want := map[string]int{"too_long": 1, "too_demanding": 1, "deferred": 1, "blocked": 1}
+	for k, v := range want {
+		if resp.Skipped[k] != v {
+			t.Errorf("skipped = %v, want %v", resp.Skipped, want)
+			break
+		}
+	}
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"minutes": float64(30), "energy": "sleepy"},
+		{"minutes": float64(30), "limit": float64(21)},
+	} {
+		if result, _ := handler(context.Background(), makeReq(args)); !result.IsError {
+			t.Errorf("%v: expected error, got %s", args, resultText(result))
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+// nestedUpdateFields maps update fields that Todoist reports inside an
+// object, e.g. due_date as due.date, to that object and key. due_string is
+// left out: a phrase such as "tomorrow" resolves to a new date each day, so an
+// unchanged phrase is no sign of an unchanged date.
+var nestedUpdateFields = map[string][2]string{
+	"due_date":      {"due", "date"},
+	"due_datetime":  {"due", "datetime"},
+	"duration":      {"duration", "amount"},
+	"duration_unit": {"duration", "unit"},
+	"deadline_date": {"deadline", "date"},
+}
+
+// fetchEntity reads a single task or project for comparison with an update.
+func fetchEntity(ctx context.Context, client todoist.API, path string) (map[string]interface{}, error) {
+	respBody, err := client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var entity map[string]interface{}
+	if err := json.Unmarshal(respBody, &entity); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", strings.TrimPrefix(path, "/"), err)
+	}
+	return entity, nil
+}
+
+// updateIsNoop reports whether every field of an update body already has the
+// requested value on current, so that sending it would change nothing. Fields
+// it cannot compare count as changes.
+func updateIsNoop(current, body map[string]interface{}) bool {
+	for field, want := range body {
+		have, ok := current[field]
+		if nested, isNested := nestedUpdateFields[field]; isNested {
+			parent, _ := current[nested[0]].(map[string]interface{})
+			have, ok = parent[nested[1]]
+			// due_date makes a timed task all-day, so it changes the task
+			// even when the date matches.
+			if datetime, _ := parent["datetime"].(string); field == "due_date" && datetime != "" {
+				return false
+			}
+		}
+		if !ok || !sameUpdateValue(field, have, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameUpdateValue compares a current field value with a requested one. Labels
+// compare as sets, as Todoist treats them, and due phrases never match.
+func sameUpdateValue(field string, have, want interface{}) bool {
+	switch field {
+	case "labels":
+		haveList, _ := have.([]interface{})
+		wantList, _ := want.([]string)
+		if len(haveList) != len(wantList) {
+			return false
+		}
+		for _, label := range haveList {
+			name := fmt.Sprint(label)
+			if !slices.ContainsFunc(wantList, func(w string) bool { return strings.EqualFold(w, name) }) {
+				return false
+			}
+		}
+		return true
+	case "due_string":
+		return false
+	case "due_datetime":
+		haveTime, err1 := time.Parse(time.RFC3339, fmt.Sprint(have))
+		wantTime, err2 := time.Parse(time.RFC3339, fmt.Sprint(want))
+		if err1 == nil && err2 == nil {
+			return haveTime.Equal(wantTime)
+		}
+	}
+	// Numbers decode as float64 and are sent as int; both print the same.
+	return have != nil && fmt.Sprint(have) == fmt.Sprint(want)
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestUpdateIsNoop(t *testing.T) {
+	var current map[string]interface{}
+	_ = json.Unmarshal([]byte(`{
+		"id": "1", "content": "Pay rent", "priority": 4, "labels": ["Home", "bills"],
+		"due": {"date": "2026-10-16", "string": "every month", "datetime": "2026-10-16T09:00:00Z"},
+		"duration": null
+	}`), &current)
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+		want bool
+	}{
+		{"same content and priority", map[string]interface{}{"content": "Pay rent", "priority": 4}, true},
+		{"labels as a set", map[string]interface{}{"labels": []string{"bills", "home"}}, true},
+		{"due phrase is always sent", map[string]interface{}{"due_string": "every month"}, false},
+		{"relative due phrase", map[string]interface{}{"content": "Pay rent", "due_string": "tomorrow"}, false},
+		{"same instant", map[string]interface{}{"due_datetime": "2026-10-16T11:00:00+02:00"}, true},
+		{"due date drops the time of day", map[string]interface{}{"due_date": "2026-10-16"}, false},
+		{"different content", map[string]interface{}{"content": "Pay rent now"}, false},
+		{"extra label", map[string]interface{}{"labels": []string{"home", "bills", "urgent"}}, false},
+		{"unset nested field", map[string]interface{}{"duration": 15}, false},
+		{"unknown field", map[string]interface{}{"assignee_id": "7"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := updateIsNoop(current, tt.body); got != tt.want {
+				t.Errorf("updateIsNoop(%v) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+	var allDay map[string]interface{}
+	_ = json.Unmarshal([]byte(`{"id": "2", "due": {"date": "2026-10-16", "string": "Oct 16", "datetime": null}}`), &allDay)
+	if !updateIsNoop(allDay, map[string]interface{}{"due_date": "2026-10-16"}) {
+		t.Error("same due_date on an all-day task should be a no-op")
+	}
+}
+
+func TestUpdateHandlers_SkipIfUnchanged(t *testing.T) {
+	posts := 0
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if path == "/projects/9" {
+				return []byte(`{"id": "9", "name": "Work", "color": "blue"}`), nil
+			}
+			return []byte(`{"id": "1", "content": "Pay rent", "priority": 4, "labels": ["bills"]}`), nil
+		},
+		PostFn: func(_ context.Context, _ string, _ interface{}) ([]byte, error) {
+			posts++
+			return []byte(`{"id": "1", "content": "Pay rent", "priority": 3, "labels": ["bills"]}`), nil
+		},
+	}
+	changed := func(t *testing.T, text string) interface{} {
+		t.Helper()
+		var body map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return body["changed"]
+	}
+
+	result, _ := UpdateTaskHandler(client)(context.Background(), makeReq(map[string]interface{}{
+		"task_id": "1", "priority": float64(4), "labels": []interface{}{"bills"}, "merge_strategy": "union", "skip_if_unchanged": true,
+	}))
+	if result.IsError || changed(t, resultText(result)) != false || posts != 0 {
+		t.Errorf("no-op task update: posts = %d, result = %s", posts, resultText(result))
+	}
+
+	result, _ = UpdateTaskHandler(client)(context.Background(), makeReq(map[string]interface{}{
+		"task_id": "1", "priority": float64(3), "skip_if_unchanged": true,
+	}))
+	if result.IsError || changed(t, resultText(result)) != true || posts != 1 {
+		t.Errorf("task update: posts = %d, result = %s", posts, resultText(result))
+	}
+
+	result, _ = UpdateProjectHandler(client)(context.Background(), makeReq(map[string]interface{}{
+		"project_id": "9", "name": "Work", "skip_if_unchanged": true,
+	}))
+	if result.IsError || changed(t, resultText(result)) != false || posts != 1 {
+		t.Errorf("no-op project update: posts = %d, result = %s", posts, resultText(result))
+	}
+}
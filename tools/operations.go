@@ -0,0 +1,249 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+const (
+	// maxRunningOperations caps the background operations running at once,
+	// since they share one rate limit budget.
+	maxRunningOperations = 4
+	// maxOperationDuration stops a background operation that runs longer,
+	// such as one waiting out rate limit windows indefinitely.
+	maxOperationDuration = 2 * time.Hour
+)
+
+// Operation states reported by get_operation_status.
+const (
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+	OperationCancelled = "cancelled"
+)
+
+// operation is a tool call running in the background.
+type operation struct {
+	ID         string          `json:"operation_id"`
+	Tool       string          `json:"tool"`
+	State      string          `json:"state"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+	Done       int             `json:"done"`
+	Total      int             `json:"total"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+
+	cancel context.CancelFunc
+}
+
+// OperationStore runs bulk tool calls in the background and keeps their
+// progress and results, so that MCP clients with short tool timeouts can
+// start a large job, poll it with get_operation_status, and stop it with
+// cancel_operation. Finished operations are kept for the retention period.
+type OperationStore struct {
+	mu         sync.Mutex
+	operations map[string]*operation
+	retention  time.Duration
+}
+
+// NewOperationStore creates a store keeping finished operations for retention.
+func NewOperationStore(retention time.Duration) *OperationStore {
+	return &OperationStore{operations: make(map[string]*operation), retention: retention}
+}
+
+// Background wraps the handler of a bulk tool so that a call with
+// background: true returns an operation ID at once and runs the handler under
+// its own context, detached from the call's deadline. Calls without it run
+// the handler as before.
+func (s *OperationStore) Background(tool string, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if background, _ := req.GetArguments()["background"].(bool); !background {
+			return handler(ctx, req)
+		}
+
+		runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), maxOperationDuration)
+		op, err := s.start(tool, cancel)
+		if err != nil {
+			cancel()
+			return respond.ErrorFrom(err), nil
+		}
+		runCtx = todoist.WithProgress(runCtx, func(done, total int) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			op.Done, op.Total = done, total
+		})
+
+		go func() {
+			defer cancel()
+			result, err := handler(runCtx, req)
+			s.finish(op, result, err, runCtx.Err())
+		}()
+
+		return respond.JSON(map[string]interface{}{
+			"operation_id": op.ID,
+			"tool":         tool,
+			"state":        OperationRunning,
+			"message":      fmt.Sprintf("%s is running in the background; poll get_operation_status with this operation_id, or stop it with cancel_operation", tool),
+		}), nil
+	}
+}
+
+// start registers a new running operation, refusing when too many run.
+func (s *OperationStore) start(tool string, cancel context.CancelFunc) (*operation, error) {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(time.Now())
+	running := 0
+	for _, op := range s.operations {
+		if op.State == OperationRunning {
+			running++
+		}
+	}
+	if running >= maxRunningOperations {
+		return nil, fmt.Errorf("%d background operations are already running; wait for one to finish or cancel one with cancel_operation", running)
+	}
+
+	op := &operation{ID: hex.EncodeToString(b), Tool: tool, State: OperationRunning, StartedAt: time.Now(), cancel: cancel}
+	s.operations[op.ID] = op
+	return op, nil
+}
+
+// finish records the outcome of a background handler. runErr is the error of
+// the operation's context once the handler returned, set when it was
+// cancelled or timed out.
+func (s *OperationStore) finish(op *operation, result *mcp.CallToolResult, err error, runErr error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	op.FinishedAt = &now
+	text := resultTextContent(result)
+	switch {
+	case err != nil:
+		op.State, op.Error = OperationFailed, err.Error()
+	case errors.Is(runErr, context.Canceled):
+		op.State = OperationCancelled
+	case errors.Is(runErr, context.DeadlineExceeded):
+		op.State, op.Error = OperationFailed, fmt.Sprintf("stopped after %s", maxOperationDuration)
+	case result != nil && result.IsError:
+		op.State = OperationFailed
+	default:
+		op.State = OperationSucceeded
+	}
+	switch {
+	case result == nil:
+	case result.IsError && op.Error == "":
+		op.Error = text
+	case !result.IsError && json.Valid([]byte(text)):
+		op.Result = json.RawMessage(text)
+	}
+	slog.Info("background operation finished", "operation_id", op.ID, "tool", op.Tool, "state", op.State)
+}
+
+// pruneLocked drops operations that finished more than the retention period
+// before now. The caller holds s.mu.
+func (s *OperationStore) pruneLocked(now time.Time) {
+	for id, op := range s.operations {
+		if op.FinishedAt != nil && now.Sub(*op.FinishedAt) > s.retention {
+			delete(s.operations, id)
+		}
+	}
+}
+
+// snapshot returns a copy of the operation with id, safe to encode.
+func (s *OperationStore) snapshot(id string) (operation, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(time.Now())
+	op, ok := s.operations[id]
+	if !ok {
+		return operation{}, false
+	}
+	return *op, true
+}
+
+// list returns copies of every kept operation, newest first.
+func (s *OperationStore) list() []operation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(time.Now())
+	ops := make([]operation, 0, len(s.operations))
+	for _, op := range s.operations {
+		copied := *op
+		copied.Result = nil
+		ops = append(ops, copied)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].StartedAt.After(ops[j].StartedAt) })
+	return ops
+}
+
+// GetOperationStatusHandler creates a handler reporting the state, progress,
+// and result of a background operation, or listing all of them without an
+// operation_id.
+func GetOperationStatusHandler(store *OperationStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, _ := req.GetArguments()["operation_id"].(string)
+		if id == "" {
+			return respond.JSON(respond.List("operations", store.list())), nil
+		}
+		op, ok := store.snapshot(id)
+		if !ok {
+			return respond.Errorf("operation %s not found; finished operations are kept for %s", id, store.retention), nil
+		}
+		return respond.JSON(op), nil
+	}
+}
+
+// CancelOperationHandler creates a handler stopping a running background
+// operation. Changes already sent stay applied; the rest are reported as
+// failed in the operation's result.
+func CancelOperationHandler(store *OperationStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, _ := req.GetArguments()["operation_id"].(string)
+		if id == "" {
+			return respond.Error("operation_id is required"), nil
+		}
+
+		store.mu.Lock()
+		op, ok := store.operations[id]
+		var state string
+		if ok {
+			state = op.State
+			if state == OperationRunning {
+				op.cancel()
+			}
+		}
+		store.mu.Unlock()
+
+		switch {
+		case !ok:
+			return respond.Errorf("operation %s not found", id), nil
+		case state != OperationRunning:
+			return respond.Errorf("operation %s already %s", id, state), nil
+		}
+		return respond.JSON(map[string]interface{}{
+			"operation_id": id,
+			"cancelling":   true,
+			"message":      "Cancellation requested; changes already sent stay applied. Poll get_operation_status for the final result",
+		}), nil
+	}
+}
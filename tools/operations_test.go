@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// waitForState polls the store until the operation leaves running.
+func waitForState(t *testing.T, store *OperationStore, id string) operation {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if op, ok := store.snapshot(id); ok && op.State != OperationRunning {
+			return op
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("operation %s still running", id)
+	return operation{}
+}
+
+// startOperation calls handler in the background and returns the operation ID.
+func startOperation(t *testing.T, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]interface{}) string {
+	t.Helper()
+	args["background"] = true
+	result, err := handler(context.Background(), makeReq(args))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	var resp struct {
+		OperationID string `json:"operation_id"`
+		State       string `json:"state"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.OperationID == "" || resp.State != OperationRunning {
+		t.Fatalf("response = %s", resultText(result))
+	}
+	return resp.OperationID
+}
+
+func TestOperationStore_Background(t *testing.T) {
+	store := NewOperationStore(time.Hour)
+	client := &MockAPI{}
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(ctx context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < time.Hour {
+			t.Errorf("background work should not inherit the call's deadline")
+		}
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+	handler := store.Background("bulk_complete_tasks", BulkCompleteTasksHandler(client, syncClient, NewConfirmationStore(time.Minute)))
+
+	ids := make([]interface{}, 150)
+	for i := range ids {
+		ids[i] = string(rune('a'+i%26)) + strings.Repeat("x", i/26)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	result, _ := handler(ctx, makeReq(map[string]interface{}{"task_ids": ids, "background": true}))
+	cancel()
+	var started struct {
+		OperationID string `json:"operation_id"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &started); err != nil || started.OperationID == "" {
+		t.Fatalf("response = %s", resultText(result))
+	}
+
+	op := waitForState(t, store, started.OperationID)
+	if op.State != OperationSucceeded || op.Done != 150 || op.Total != 150 || op.FinishedAt == nil {
+		t.Fatalf("operation = %+v", op)
+	}
+	var final struct {
+		Completed int `json:"completed"`
+	}
+	if err := json.Unmarshal(op.Result, &final); err != nil || final.Completed != 150 {
+		t.Errorf("result = %s", op.Result)
+	}
+
+	status, _ := GetOperationStatusHandler(store)(context.Background(), makeReq(map[string]interface{}{"operation_id": started.OperationID}))
+	if !strings.Contains(resultText(status), `"state": "succeeded"`) || !strings.Contains(resultText(status), `"completed": 150`) {
+		t.Errorf("status = %s", resultText(status))
+	}
+	list, _ := GetOperationStatusHandler(store)(context.Background(), makeReq(map[string]interface{}{}))
+	if !strings.Contains(resultText(list), `"count": 1`) || strings.Contains(resultText(list), `"result"`) {
+		t.Errorf("list = %s", resultText(list))
+	}
+}
+
+func TestOperationStore_Foreground(t *testing.T) {
+	store := NewOperationStore(time.Hour)
+	handler := store.Background("noop", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return respond.JSON(map[string]interface{}{"ok": true}), nil
+	})
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{}))
+	if !strings.Contains(resultText(result), `"ok": true`) || len(store.list()) != 0 {
+		t.Errorf("calls without background should run directly: %s", resultText(result))
+	}
+}
+
+func TestOperationStore_FailedAndCancelled(t *testing.T) {
+	store := NewOperationStore(time.Hour)
+
+	failing := store.Background("failing", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return respond.Error("task_ids or filter is required"), nil
+	})
+	op := waitForState(t, store, startOperation(t, failing, map[string]interface{}{}))
+	if op.State != OperationFailed || op.Error != "task_ids or filter is required" || op.Result != nil {
+		t.Errorf("operation = %+v", op)
+	}
+
+	release := make(chan struct{})
+	blocking := store.Background("blocking", func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(release)
+		<-ctx.Done()
+		return respond.JSON(map[string]interface{}{"moved": 3}), nil
+	})
+	id := startOperation(t, blocking, map[string]interface{}{})
+	<-release
+
+	result, _ := CancelOperationHandler(store)(context.Background(), makeReq(map[string]interface{}{"operation_id": id}))
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(result))
+	}
+	op = waitForState(t, store, id)
+	if op.State != OperationCancelled || string(op.Result) == "" {
+		t.Errorf("operation = %+v, want cancelled with its partial result", op)
+	}
+
+	result, _ = CancelOperationHandler(store)(context.Background(), makeReq(map[string]interface{}{"operation_id": id}))
+	if !result.IsError || !strings.Contains(resultText(result), "already cancelled") {
+		t.Errorf("second cancel = %s", resultText(result))
+	}
+	result, _ = CancelOperationHandler(store)(context.Background(), makeReq(map[string]interface{}{"operation_id": "missing"}))
+	if !result.IsError {
+		t.Error("expected an error for an unknown operation")
+	}
+}
+
+func TestOperationStore_Limit(t *testing.T) {
+	store := NewOperationStore(time.Hour)
+	done := make(chan struct{})
+	defer close(done)
+	handler := store.Background("blocking", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-done
+		return respond.JSON(map[string]interface{}{}), nil
+	})
+	for range maxRunningOperations {
+		startOperation(t, handler, map[string]interface{}{})
+	}
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"background": true}))
+	if !result.IsError || !strings.Contains(resultText(result), "already running") {
+		t.Errorf("expected the limit error, got %s", resultText(result))
+	}
+}
+
+func TestOperationStore_Retention(t *testing.T) {
+	store := NewOperationStore(time.Minute)
+	finished := time.Now().Add(-2 * time.Minute)
+	store.operations["old"] = &operation{ID: "old", State: OperationSucceeded, FinishedAt: &finished}
+	if _, ok := store.snapshot("old"); ok {
+		t.Error("operations finished before the retention period should be dropped")
+	}
+}
@@ -0,0 +1,304 @@
+package tools
+
+import (
+	"context"
+	"net/url"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// orderNode is a project task in the dependency graph built by
+// SuggestTaskOrderHandler.
+type orderNode struct {
+	task     models.Task
+	index    int      // position in the project's task list, the last tie-breaker
+	blockers []string // open blockers inside the project
+	waiting  []string // open blockers in other projects
+	due      string
+	deadline string // the earliest due date of the task and everything waiting on it
+	priority int
+	minutes  int
+}
+
+// orderBefore reports whether a should be worked on before b when both are
+// ready: earlier deadlines first, undated last, then higher priority.
+func orderBefore(a, b *orderNode) bool {
+	if a.deadline != b.deadline {
+		return b.deadline == "" || (a.deadline != "" && a.deadline < b.deadline)
+	}
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return a.index < b.index
+}
+
+// dependencyCycles returns the cycles among the given task IDs as strongly
+// connected components, each in project order.
+func dependencyCycles(nodes map[string]*orderNode, ids []string) [][]string {
+	remaining := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		remaining[id] = true
+	}
+
+	// Tarjan's algorithm, restricted to the tasks left unordered.
+	index, low := make(map[string]int), make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+	var visit func(id string)
+	visit = func(id string) {
+		index[id], low[id] = len(index), len(index)
+		stack = append(stack, id)
+		onStack[id] = true
+		for _, next := range nodes[id].blockers {
+			if !remaining[next] {
+				continue
+			}
+			if _, seen := index[next]; !seen {
+				visit(next)
+				low[id] = min(low[id], low[next])
+			} else if onStack[next] {
+				low[id] = min(low[id], index[next])
+			}
+		}
+		if low[id] != index[id] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == id {
+				break
+			}
+		}
+		if len(component) > 1 || slices.Contains(nodes[id].blockers, id) {
+			slices.SortFunc(component, func(a, b string) int { return nodes[a].index - nodes[b].index })
+			cycles = append(cycles, component)
+		}
+	}
+	for _, id := range ids {
+		if _, seen := index[id]; !seen {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// SuggestTaskOrderHandler creates a handler that orders a project's tasks by
+// their dependencies and due dates.
+func SuggestTaskOrderHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectID, ok := args["project_id"].(string)
+		if !ok || projectID == "" {
+			return respond.Error("project_id is required"), nil
+		}
+		if err := ValidateID(projectID, "project_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		// Blockers may live in other projects, so all active tasks are loaded.
+		tasks, err := fetchTasks(ctx, client, url.Values{})
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		byID := make(map[string]models.Task, len(tasks))
+		for _, task := range tasks {
+			byID[task.ID] = task
+		}
+		graph := blockedByGraph(tasks)
+
+		nodes := make(map[string]*orderNode)
+		var ids []string
+		for _, task := range tasks {
+			if task.ProjectID != projectID {
+				continue
+			}
+			id := task.ID
+			node := &orderNode{task: task, index: len(ids), due: task.Due.Day(), priority: task.Priority}
+			node.deadline = node.due
+			node.minutes, _, _ = taskEstimate(task)
+			nodes[id] = node
+			ids = append(ids, id)
+		}
+		dependents := make(map[string][]string)
+		for _, id := range ids {
+			for _, blockerID := range graph[id] {
+				_, open := byID[blockerID]
+				switch {
+				case !open:
+					// Completed or deleted blockers no longer constrain the order.
+				case nodes[blockerID] != nil:
+					nodes[id].blockers = append(nodes[id].blockers, blockerID)
+					dependents[blockerID] = append(dependents[blockerID], id)
+				default:
+					nodes[id].waiting = append(nodes[id].waiting, blockerID)
+				}
+			}
+		}
+
+		// A plain topological pass first, to propagate deadlines from tasks to
+		// their blockers: a blocker is due no later than what waits on it.
+		pending := make(map[string]int, len(ids))
+		var topo []string
+		for _, id := range ids {
+			pending[id] = len(nodes[id].blockers)
+			if pending[id] == 0 {
+				topo = append(topo, id)
+			}
+		}
+		for i := 0; i < len(topo); i++ {
+			for _, next := range dependents[topo[i]] {
+				if pending[next]--; pending[next] == 0 {
+					topo = append(topo, next)
+				}
+			}
+		}
+		for i := len(topo) - 1; i >= 0; i-- {
+			node := nodes[topo[i]]
+			for _, next := range dependents[topo[i]] {
+				if d := nodes[next].deadline; d != "" && (node.deadline == "" || d < node.deadline) {
+					node.deadline = d
+				}
+			}
+		}
+
+		// The order itself: among the tasks whose blockers are done, always
+		// take the most pressing next.
+		for _, id := range ids {
+			pending[id] = len(nodes[id].blockers)
+		}
+		var ready []string
+		for _, id := range ids {
+			if pending[id] == 0 {
+				ready = append(ready, id)
+			}
+		}
+		order := make([]map[string]interface{}, 0, len(topo))
+		ordered := make(map[string]bool, len(topo))
+		for len(ready) > 0 {
+			best := 0
+			for i := range ready {
+				if orderBefore(nodes[ready[i]], nodes[ready[best]]) {
+					best = i
+				}
+			}
+			id := ready[best]
+			ready = slices.Delete(ready, best, best+1)
+			ordered[id] = true
+
+			node := nodes[id]
+			entry := map[string]interface{}{
+				"position": len(order) + 1,
+				"id":       id,
+				"content":  node.task.Content,
+			}
+			if node.due != "" {
+				entry["due"] = node.due
+			}
+			if node.deadline != node.due {
+				entry["needed_by"] = node.deadline
+			}
+			if len(node.blockers) > 0 {
+				entry["after"] = node.blockers
+			}
+			if len(node.waiting) > 0 {
+				entry["waiting_on"] = node.waiting
+			}
+			order = append(order, entry)
+
+			for _, next := range dependents[id] {
+				if pending[next]--; pending[next] == 0 {
+					ready = append(ready, next)
+				}
+			}
+		}
+
+		// A blocker due after a task that waits on it, directly or not, cannot
+		// keep its date. Blockers in other projects are checked too.
+		neededBy := make(map[string]string)
+		for _, id := range ids {
+			node := nodes[id]
+			if node.due != "" && node.deadline < node.due {
+				neededBy[id] = node.deadline
+			}
+			for _, blockerID := range node.waiting {
+				due := byID[blockerID].Due.Day()
+				if d, ok := neededBy[blockerID]; node.deadline != "" && due > node.deadline && (!ok || node.deadline < d) {
+					neededBy[blockerID] = node.deadline
+				}
+			}
+		}
+		conflicts := make([]map[string]interface{}, 0, len(neededBy))
+		for _, task := range tasks {
+			if d, ok := neededBy[task.ID]; ok {
+				conflicts = append(conflicts, map[string]interface{}{
+					"task_id":    task.ID,
+					"content":    task.Content,
+					"project_id": task.ProjectID,
+					"due":        task.Due.Day(),
+					"needed_by":  d,
+				})
+			}
+		}
+
+		var unordered []string
+		for _, id := range ids {
+			if !ordered[id] {
+				unordered = append(unordered, id)
+			}
+		}
+		cycles := dependencyCycles(nodes, unordered)
+		if cycles == nil {
+			cycles = make([][]string, 0)
+		}
+
+		// The critical path is the dependency chain with the most estimated
+		// minutes, ties going to the chain with more tasks.
+		type pathEnd struct {
+			minutes, tasks int
+			prev           string
+		}
+		paths := make(map[string]pathEnd, len(topo))
+		var last string
+		for _, id := range topo {
+			end := pathEnd{minutes: nodes[id].minutes, tasks: 1}
+			for _, blockerID := range nodes[id].blockers {
+				p := paths[blockerID]
+				if p.minutes+nodes[id].minutes > end.minutes || (p.minutes+nodes[id].minutes == end.minutes && p.tasks+1 > end.tasks) {
+					end = pathEnd{minutes: p.minutes + nodes[id].minutes, tasks: p.tasks + 1, prev: blockerID}
+				}
+			}
+			paths[id] = end
+			if last == "" || end.minutes > paths[last].minutes || (end.minutes == paths[last].minutes && end.tasks > paths[last].tasks) {
+				last = id
+			}
+		}
+		criticalPath := make([]string, 0)
+		for id := last; id != ""; id = paths[id].prev {
+			criticalPath = append([]string{id}, criticalPath...)
+		}
+
+		response := respond.List("order", order).
+			Set("project_id", projectID).
+			Set("critical_path", map[string]interface{}{
+				"task_ids":          criticalPath,
+				"estimated_minutes": paths[last].minutes,
+			}).
+			Set("conflicts", conflicts).
+			Set("cycles", cycles)
+		if len(cycles) > 0 {
+			response.Warn("%d task(s) were left out of the order because of dependency cycles; remove a link with link_tasks to break them", len(unordered))
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSuggestTaskOrderHandler(t *testing.T) {
+	type entry struct {
+		ID        string   `json:"id"`
+		Due       string   `json:"due"`
+		NeededBy  string   `json:"needed_by"`
+		After     []string `json:"after"`
+		WaitingOn []string `json:"waiting_on"`
+	}
+	type response struct {
+		Count        int     `json:"count"`
+		Order        []entry `json:"order"`
+		CriticalPath struct {
+			TaskIDs []string `json:"task_ids"`
+			Minutes int      `json:"estimated_minutes"`
+		} `json:"critical_path"`
+		Conflicts []map[string]interface{} `json:"conflicts"`
+		Cycles    [][]string               `json:"cycles"`
+		Warnings  []string                 `json:"warnings"`
+	}
+	run := func(t *testing.T, tasks []map[string]interface{}) response {
+		t.Helper()
+		result, _ := SuggestTaskOrderHandler(dependencyMock(tasks, map[string]string{}))(context.Background(), makeReq(map[string]interface{}{"project_id": "p1"}))
+		if result.IsError {
+			t.Fatalf("unexpected tool error: %s", resultText(result))
+		}
+		var resp response
+		if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		return resp
+	}
+	ids := func(order []entry) string {
+		var s []string
+		for _, e := range order {
+			s = append(s, e.ID)
+		}
+		return strings.Join(s, ",")
+	}
+
+	t.Run("dependencies and deadlines", func(t *testing.T) {
+		// Launch (due the 10th) waits on Build, which waits on Design and on
+		// Legal in another project. Docs is due the 12th; Cleanup is undated.
+		tasks := []map[string]interface{}{
+			{"id": "cleanup", "project_id": "p1", "priority": float64(4)},
+			{"id": "launch", "project_id": "p1", "due": map[string]interface{}{"date": "2026-11-10"}, "description": "ref:blocked-by=build"},
+			{"id": "docs", "project_id": "p1", "due": map[string]interface{}{"date": "2026-11-12"}},
+			{"id": "build", "project_id": "p1", "labels": []interface{}{"2h"}, "description": "ref:blocked-by=design,legal,done"},
+			{"id": "design", "project_id": "p1", "labels": []interface{}{"1h"}, "due": map[string]interface{}{"date": "2026-11-11"}},
+			{"id": "legal", "project_id": "p2", "due": map[string]interface{}{"date": "2026-11-20"}},
+		}
+		resp := run(t, tasks)
+		if got := ids(resp.Order); got != "design,build,launch,docs,cleanup" {
+			t.Errorf("order = %s", got)
+		}
+		if build := resp.Order[1]; build.NeededBy != "2026-11-10" || strings.Join(build.After, ",") != "design" || strings.Join(build.WaitingOn, ",") != "legal" {
+			t.Errorf("build = %+v", build)
+		}
+		if strings.Join(resp.CriticalPath.TaskIDs, ",") != "design,build,launch" || resp.CriticalPath.Minutes != 180 {
+			t.Errorf("critical path = %+v", resp.CriticalPath)
+		}
+		// Design (11th) and Legal (20th) are due after Launch needs them.
+		if len(resp.Conflicts) != 2 || resp.Conflicts[0]["task_id"] != "design" || resp.Conflicts[1]["task_id"] != "legal" || resp.Conflicts[1]["needed_by"] != "2026-11-10" {
+			t.Errorf("conflicts = %v", resp.Conflicts)
+		}
+		if len(resp.Cycles) != 0 || len(resp.Warnings) != 0 {
+			t.Errorf("cycles = %v, warnings = %v", resp.Cycles, resp.Warnings)
+		}
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		tasks := []map[string]interface{}{
+			{"id": "a", "project_id": "p1", "due": map[string]interface{}{"date": "2026-11-20"}},
+			{"id": "b", "project_id": "p1", "due": map[string]interface{}{"date": "2026-11-10"}, "description": "ref:blocked-by=a,x"},
+			{"id": "x", "project_id": "p2", "due": map[string]interface{}{"date": "2026-11-15"}},
+		}
+		resp := run(t, tasks)
+		if len(resp.Conflicts) != 2 || resp.Conflicts[0]["task_id"] != "a" || resp.Conflicts[1]["task_id"] != "x" || resp.Conflicts[1]["needed_by"] != "2026-11-10" {
+			t.Errorf("conflicts = %v", resp.Conflicts)
+		}
+	})
+
+	t.Run("cycles", func(t *testing.T) {
+		tasks := []map[string]interface{}{
+			{"id": "a", "project_id": "p1", "description": "ref:blocked-by=c"},
+			{"id": "b", "project_id": "p1", "description": "ref:blocked-by=a"},
+			{"id": "c", "project_id": "p1", "description": "ref:blocked-by=b"},
+			{"id": "d", "project_id": "p1", "description": "ref:blocked-by=c"},
+			{"id": "e", "project_id": "p1"},
+		}
+		resp := run(t, tasks)
+		if ids(resp.Order) != "e" || resp.Count != 1 {
+			t.Errorf("order = %s", ids(resp.Order))
+		}
+		if len(resp.Cycles) != 1 || strings.Join(resp.Cycles[0], ",") != "a,b,c" {
+			t.Errorf("cycles = %v", resp.Cycles)
+		}
+		if len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "4 task(s)") {
+			t.Errorf("warnings = %v", resp.Warnings)
+		}
+	})
+
+	t.Run("missing project", func(t *testing.T) {
+		result, _ := SuggestTaskOrderHandler(&MockAPI{})(context.Background(), makeReq(map[string]interface{}{}))
+		if !result.IsError || !strings.Contains(resultText(result), "project_id is required") {
+			t.Errorf("result = %s", resultText(result))
+		}
+	})
+}
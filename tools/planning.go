@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// taskDurationMinutes returns the task's duration in minutes. Day-based
+// durations consume a full day of capacity. The second return value is false
+// when the task has no duration estimate.
+func taskDurationMinutes(task models.Task, capacityMinutes int) (int, bool) {
+	if task.Duration == nil || task.Duration.Amount <= 0 {
+		return 0, false
+	}
+	if task.Duration.Unit == "day" {
+		return task.Duration.Amount * capacityMinutes, true
+	}
+	return task.Duration.Amount, true
+}
+
+// WorkloadEstimateHandler creates a handler for estimating scheduled workload from task durations.
+func WorkloadEstimateHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		period := "today"
+		if p, ok := args["period"].(string); ok && p != "" {
+			period = p
+		}
+
+		var filter string
+		switch period {
+		case "today":
+			filter = "today"
+		case "week":
+			filter = "7 days"
+		default:
+			return respond.Error("period must be 'today' or 'week'"), nil
+		}
+
+		capacityHours := 6.0
+		if c, ok := args["capacity_hours"].(float64); ok {
+			if c <= 0 || c > 24 {
+				return respond.Error("capacity_hours must be between 0 and 24"), nil
+			}
+			capacityHours = c
+		}
+		capacityMinutes := int(capacityHours * 60)
+
+		params := url.Values{}
+		params.Set("filter", filter)
+		respBody, err := client.Get(ctx, "/tasks?"+params.Encode())
+		if err != nil {
+			return respond.Errorf("failed to fetch tasks: %v", err), nil
+		}
+
+		var tasks []models.Task
+		if err := json.Unmarshal(respBody, &tasks); err != nil {
+			return respond.Errorf("failed to parse tasks: %v", err), nil
+		}
+
+		minutesByDay := make(map[string]int)
+		tasksByDay := make(map[string]int)
+		unestimated := make([]map[string]interface{}, 0)
+		totalMinutes := 0
+
+		for _, task := range tasks {
+			day := task.Due.Day()
+			tasksByDay[day]++
+
+			minutes, ok := taskDurationMinutes(task, capacityMinutes)
+			if !ok {
+				unestimated = append(unestimated, map[string]interface{}{
+					"id":      task.ID,
+					"content": task.Content,
+					"due":     day,
+				})
+				continue
+			}
+			minutesByDay[day] += minutes
+			totalMinutes += minutes
+		}
+
+		days := make([]string, 0, len(tasksByDay))
+		for day := range tasksByDay {
+			days = append(days, day)
+		}
+		sort.Strings(days)
+
+		dayReports := make([]map[string]interface{}, 0, len(days))
+		overCapacity := make([]string, 0)
+		for _, day := range days {
+			minutes := minutesByDay[day]
+			over := minutes > capacityMinutes
+			if over {
+				overCapacity = append(overCapacity, day)
+			}
+			dayReports = append(dayReports, map[string]interface{}{
+				"date":              day,
+				"task_count":        tasksByDay[day],
+				"estimated_minutes": minutes,
+				"remaining_minutes": capacityMinutes - minutes,
+				"over_capacity":     over,
+			})
+		}
+
+		response := map[string]interface{}{
+			"period":                  period,
+			"capacity_hours":          capacityHours,
+			"total_tasks":             len(tasks),
+			"total_estimated_minutes": totalMinutes,
+			"days":                    dayReports,
+			"over_capacity_days":      overCapacity,
+			"unestimated_count":       len(unestimated),
+			"unestimated_tasks":       unestimated,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWorkloadEstimateHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            map[string]interface{}
+		mockGet         func(ctx context.Context, path string) ([]byte, error)
+		wantErr         bool
+		errSubstr       string
+		wantMinutes     int
+		wantUnestimated int
+		wantOver        int
+	}{
+		{
+			name: "today within capacity",
+			args: map[string]interface{}{},
+			mockGet: func(_ context.Context, path string) ([]byte, error) {
+				if !strings.Contains(path, "filter=today") {
+					return nil, fmt.Errorf("unexpected path: %s", path)
+				}
+				return json.Marshal([]map[string]interface{}{
+					{"id": "1", "content": "Write", "due": map[string]interface{}{"date": "2026-02-02"}, "duration": map[string]interface{}{"amount": 90, "unit": "minute"}},
+					{"id": "2", "content": "Call", "due": map[string]interface{}{"date": "2026-02-02"}},
+				})
+			},
+			wantMinutes:     90,
+			wantUnestimated: 1,
+		},
+		{
+			name: "week over capacity",
+			args: map[string]interface{}{"period": "week", "capacity_hours": float64(2)},
+			mockGet: func(_ context.Context, path string) ([]byte, error) {
+				if !strings.Contains(path, "filter=7+days") {
+					return nil, fmt.Errorf("unexpected path: %s", path)
+				}
+				return json.Marshal([]map[string]interface{}{
+					{"id": "1", "due": map[string]interface{}{"date": "2026-02-02T09:00:00"}, "duration": map[string]interface{}{"amount": 100, "unit": "minute"}},
+					{"id": "2", "due": map[string]interface{}{"date": "2026-02-02"}, "duration": map[string]interface{}{"amount": 30, "unit": "minute"}},
+					{"id": "3", "due": map[string]interface{}{"date": "2026-02-03"}, "duration": map[string]interface{}{"amount": 1, "unit": "day"}},
+				})
+			},
+			wantMinutes: 250,
+			wantOver:    1,
+		},
+		{
+			name:      "invalid period",
+			args:      map[string]interface{}{"period": "month"},
+			wantErr:   true,
+			errSubstr: "period must be",
+		},
+		{
+			name:      "invalid capacity",
+			args:      map[string]interface{}{"capacity_hours": float64(30)},
+			wantErr:   true,
+			errSubstr: "capacity_hours",
+		},
+		{
+			name: "API error",
+			args: map[string]interface{}{},
+			mockGet: func(_ context.Context, _ string) ([]byte, error) {
+				return nil, fmt.Errorf("timeout")
+			},
+			wantErr:   true,
+			errSubstr: "failed to fetch tasks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockAPI{GetFn: tt.mockGet}
+			handler := WorkloadEstimateHandler(client)
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if int(resp["total_estimated_minutes"].(float64)) != tt.wantMinutes {
+				t.Errorf("total_estimated_minutes = %v, want %d", resp["total_estimated_minutes"], tt.wantMinutes)
+			}
+			if int(resp["unestimated_count"].(float64)) != tt.wantUnestimated {
+				t.Errorf("unestimated_count = %v, want %d", resp["unestimated_count"], tt.wantUnestimated)
+			}
+			if got := len(resp["over_capacity_days"].([]interface{})); got != tt.wantOver {
+				t.Errorf("over_capacity_days = %d, want %d", got, tt.wantOver)
+			}
+		})
+	}
+}
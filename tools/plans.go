@@ -0,0 +1,278 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// maxPlanCommands is the Sync API's limit on commands per request.
+const maxPlanCommands = todoist.MaxSyncCommands
+
+// bulkPlan is a resolved set of Sync commands awaiting execution.
+type bulkPlan struct {
+	commands  []todoist.Command
+	expiresAt time.Time
+}
+
+// PlanStore holds bulk operation plans between plan_bulk_operation and
+// execute_plan. Plans expire after the store's TTL and can be executed once.
+type PlanStore struct {
+	mu    sync.Mutex
+	plans map[string]bulkPlan
+	ttl   time.Duration
+}
+
+// NewPlanStore creates a plan store whose plans expire after ttl.
+func NewPlanStore(ttl time.Duration) *PlanStore {
+	return &PlanStore{plans: make(map[string]bulkPlan), ttl: ttl}
+}
+
+// save stores commands under a new plan ID and returns the ID and expiry time.
+func (ps *PlanStore) save(commands []todoist.Command) (string, time.Time) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	now := time.Now()
+	for id, plan := range ps.plans {
+		if now.After(plan.expiresAt) {
+			delete(ps.plans, id)
+		}
+	}
+
+	id := todoist.GenerateUUID()
+	expiresAt := now.Add(ps.ttl)
+	ps.plans[id] = bulkPlan{commands: commands, expiresAt: expiresAt}
+	return id, expiresAt
+}
+
+// take removes and returns the plan with the given ID if it exists and has not expired.
+func (ps *PlanStore) take(id string) ([]todoist.Command, bool) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	plan, ok := ps.plans[id]
+	if !ok {
+		return nil, false
+	}
+	delete(ps.plans, id)
+	if time.Now().After(plan.expiresAt) {
+		return nil, false
+	}
+	return plan.commands, true
+}
+
+// PlanBulkOperationHandler creates a handler that resolves a bulk change into Sync commands without applying it.
+func PlanBulkOperationHandler(client todoist.API, store *PlanStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		moveToProject, _ := args["move_to_project_id"].(string)
+		moveToSection, _ := args["move_to_section_id"].(string)
+		dueString, _ := args["due_string"].(string)
+		clearDue, _ := args["clear_due"].(bool)
+		complete, _ := args["complete"].(bool)
+		deleteTasks, _ := args["delete"].(bool)
+		priority, hasPriority := args["priority"].(float64)
+
+		for field, id := range map[string]string{"move_to_project_id": moveToProject, "move_to_section_id": moveToSection} {
+			if id != "" {
+				if err := ValidateID(id, field); err != nil {
+					return respond.ErrorFrom(err), nil
+				}
+			}
+		}
+		if moveToProject != "" && moveToSection != "" {
+			return respond.Error("move_to_project_id and move_to_section_id are mutually exclusive"), nil
+		}
+		if clearDue && dueString != "" {
+			return respond.Error("clear_due and due_string are mutually exclusive"), nil
+		}
+		if hasPriority && (priority < 1 || priority > 4) {
+			return respond.Error("priority must be between 1 and 4"), nil
+		}
+
+		var addLabels, removeLabels []string
+		if labels, ok := args["add_labels"].([]interface{}); ok {
+			for _, l := range labels {
+				if s, ok := l.(string); ok && s != "" {
+					addLabels = append(addLabels, s)
+				}
+			}
+		}
+		if labels, ok := args["remove_labels"].([]interface{}); ok {
+			for _, l := range labels {
+				if s, ok := l.(string); ok && s != "" {
+					removeLabels = append(removeLabels, s)
+				}
+			}
+		}
+
+		hasUpdate := dueString != "" || clearDue || hasPriority || len(addLabels) > 0 || len(removeLabels) > 0
+		hasMove := moveToProject != "" || moveToSection != ""
+		if deleteTasks && (hasUpdate || hasMove || complete) {
+			return respond.Error("delete cannot be combined with other actions"), nil
+		}
+		if !deleteTasks && !complete && !hasUpdate && !hasMove {
+			return respond.Error("at least one action must be provided (move_to_project_id, move_to_section_id, due_string, clear_due, priority, add_labels, remove_labels, complete, or delete)"), nil
+		}
+
+		var tasks []models.Task
+		var err error
+		if filter, ok := args["filter"].(string); ok && filter != "" {
+			tasks, err = resolveFilterTasks(ctx, client, filter)
+		} else if ids := taskIDsArg(args); len(ids) > 0 {
+			params := url.Values{}
+			params.Set("ids", strings.Join(ids, ","))
+			tasks, err = fetchTasks(ctx, client, params)
+		} else {
+			return respond.Error("either filter or task_ids must be provided"), nil
+		}
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		if len(tasks) == 0 {
+			return respond.Error("no tasks matched; nothing to plan"), nil
+		}
+
+		commands := make([]todoist.Command, 0, len(tasks))
+		preview := make([]map[string]interface{}, 0, len(tasks))
+		for _, task := range tasks {
+			taskID := task.ID
+			preview = append(preview, map[string]interface{}{
+				"id":         taskID,
+				"content":    task.Content,
+				"project_id": task.ProjectID,
+			})
+
+			if deleteTasks {
+				commands = append(commands, todoist.Command{
+					Type: "item_delete", UUID: todoist.GenerateUUID(),
+					Args: map[string]interface{}{"id": taskID},
+				})
+				continue
+			}
+			if hasMove {
+				moveArgs := map[string]interface{}{"id": taskID}
+				if moveToProject != "" {
+					moveArgs["project_id"] = moveToProject
+				} else {
+					moveArgs["section_id"] = moveToSection
+				}
+				commands = append(commands, todoist.Command{
+					Type: "item_move", UUID: todoist.GenerateUUID(), Args: moveArgs,
+				})
+			}
+			if hasUpdate {
+				updateArgs := map[string]interface{}{"id": taskID}
+				if clearDue {
+					updateArgs["due"] = nil
+				}
+				if dueString != "" {
+					updateArgs["due"] = map[string]interface{}{"string": dueString}
+				}
+				if hasPriority {
+					updateArgs["priority"] = int(priority)
+				}
+				if len(addLabels) > 0 || len(removeLabels) > 0 {
+					var labels []string
+					for _, l := range task.Labels {
+						if !slices.Contains(removeLabels, l) {
+							labels = append(labels, l)
+						}
+					}
+					for _, l := range addLabels {
+						if !slices.Contains(labels, l) {
+							labels = append(labels, l)
+						}
+					}
+					if labels == nil {
+						labels = []string{}
+					}
+					updateArgs["labels"] = labels
+				}
+				commands = append(commands, todoist.Command{
+					Type: "item_update", UUID: todoist.GenerateUUID(), Args: updateArgs,
+				})
+			}
+			if complete {
+				commands = append(commands, todoist.Command{
+					Type: "item_close", UUID: todoist.GenerateUUID(),
+					Args: map[string]interface{}{"id": taskID},
+				})
+			}
+		}
+
+		if len(commands) > maxPlanCommands {
+			return respond.Errorf("plan would need %d commands (max %d); narrow the filter or split the operation", len(commands), maxPlanCommands), nil
+		}
+
+		planID, expiresAt := store.save(commands)
+
+		response := map[string]interface{}{
+			"plan_id":       planID,
+			"expires_at":    expiresAt.UTC().Format(time.RFC3339),
+			"task_count":    len(tasks),
+			"tasks":         preview,
+			"command_count": len(commands),
+			"commands":      commands,
+			"message":       "Review the commands, then call execute_plan with this plan_id to apply them.",
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// ExecutePlanHandler creates a handler that applies a plan produced by plan_bulk_operation.
+func ExecutePlanHandler(syncClient todoist.SyncAPI, store *PlanStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		planID, ok := args["plan_id"].(string)
+		if !ok || planID == "" {
+			return respond.Error("plan_id is required"), nil
+		}
+
+		commands, ok := store.take(planID)
+		if !ok {
+			return respond.Error("plan not found or expired; call plan_bulk_operation again"), nil
+		}
+
+		syncResp, err := syncClient.BatchCommands(ctx, commands)
+		if err != nil {
+			return respond.Errorf("failed to execute plan: %v", err), nil
+		}
+
+		succeeded := 0
+		failed := make([]map[string]interface{}, 0)
+		for _, cmd := range commands {
+			if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+				succeeded++
+			} else {
+				failed = append(failed, map[string]interface{}{
+					"type":  cmd.Type,
+					"id":    cmd.Args["id"],
+					"error": fmt.Sprintf("%v", syncResp.SyncStatus[cmd.UUID]),
+				})
+			}
+		}
+
+		response := map[string]interface{}{
+			"plan_id":   planID,
+			"succeeded": succeeded,
+			"failed":    failed,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestPlanBulkOperationHandler(t *testing.T) {
+	mockGet := func(_ context.Context, path string) ([]byte, error) {
+		if !strings.HasPrefix(path, "/tasks?filter=") {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return json.Marshal([]map[string]interface{}{
+			{"id": "1", "content": "Learn piano", "project_id": "inbox", "labels": []string{"someday", "music"}},
+			{"id": "2", "content": "Visit Japan", "project_id": "inbox", "labels": []string{"someday"}},
+		})
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantErr      bool
+		errSubstr    string
+		wantCommands []string
+	}{
+		{
+			name: "move and clear due dates",
+			args: map[string]interface{}{
+				"filter":             "#Inbox & @someday",
+				"move_to_project_id": "p9",
+				"clear_due":          true,
+				"remove_labels":      []interface{}{"someday"},
+			},
+			wantCommands: []string{"item_move", "item_update", "item_move", "item_update"},
+		},
+		{
+			name:         "delete",
+			args:         map[string]interface{}{"filter": "@someday", "delete": true},
+			wantCommands: []string{"item_delete", "item_delete"},
+		},
+		{
+			name:      "no action",
+			args:      map[string]interface{}{"filter": "@someday"},
+			wantErr:   true,
+			errSubstr: "at least one action",
+		},
+		{
+			name:      "delete combined with move",
+			args:      map[string]interface{}{"filter": "@someday", "delete": true, "move_to_project_id": "p9"},
+			wantErr:   true,
+			errSubstr: "delete cannot be combined",
+		},
+		{
+			name:      "no selection",
+			args:      map[string]interface{}{"complete": true},
+			wantErr:   true,
+			errSubstr: "either filter or task_ids",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := PlanBulkOperationHandler(&MockAPI{GetFn: mockGet}, NewPlanStore(time.Minute))
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				PlanID   string            `json:"plan_id"`
+				Commands []todoist.Command `json:"commands"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if resp.PlanID == "" {
+				t.Error("plan_id is empty")
+			}
+			if len(resp.Commands) != len(tt.wantCommands) {
+				t.Fatalf("got %d commands, want %d", len(resp.Commands), len(tt.wantCommands))
+			}
+			for i, want := range tt.wantCommands {
+				if resp.Commands[i].Type != want {
+					t.Errorf("commands[%d] = %s, want %s", i, resp.Commands[i].Type, want)
+				}
+			}
+			if tt.wantCommands[1] == "item_update" {
+				update := resp.Commands[1].Args
+				if v, ok := update["due"]; !ok || v != nil {
+					t.Errorf("due = %v, want explicit null", v)
+				}
+				if labels := update["labels"].([]interface{}); len(labels) != 1 || labels[0] != "music" {
+					t.Errorf("labels = %v, want [music]", labels)
+				}
+			}
+		})
+	}
+}
+
+func TestExecutePlanHandler(t *testing.T) {
+	store := NewPlanStore(time.Minute)
+	planID, _ := store.save([]todoist.Command{
+		{Type: "item_close", UUID: "u1", Args: map[string]interface{}{"id": "1"}},
+		{Type: "item_close", UUID: "u2", Args: map[string]interface{}{"id": "2"}},
+	})
+	expired := NewPlanStore(-time.Minute)
+	expiredID, _ := expired.save([]todoist.Command{{Type: "item_close", UUID: "u3"}})
+
+	syncClient := &MockSyncAPI{
+		BatchCommandsFn: func(_ context.Context, _ []todoist.Command) (*todoist.SyncResponse, error) {
+			return &todoist.SyncResponse{SyncStatus: map[string]interface{}{
+				"u1": "ok",
+				"u2": map[string]interface{}{"error": "Item not found"},
+			}}, nil
+		},
+	}
+
+	result, err := ExecutePlanHandler(syncClient, store)(context.Background(), makeReq(map[string]interface{}{"plan_id": planID}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+	var resp struct {
+		Succeeded int                      `json:"succeeded"`
+		Failed    []map[string]interface{} `json:"failed"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Succeeded != 1 || len(resp.Failed) != 1 {
+		t.Errorf("succeeded = %d, failed = %v", resp.Succeeded, resp.Failed)
+	}
+
+	result, _ = ExecutePlanHandler(syncClient, store)(context.Background(), makeReq(map[string]interface{}{"plan_id": planID}))
+	if !result.IsError || !strings.Contains(resultText(result), "not found or expired") {
+		t.Errorf("re-executing a plan should fail, got %s", resultText(result))
+	}
+
+	result, _ = ExecutePlanHandler(syncClient, expired)(context.Background(), makeReq(map[string]interface{}{"plan_id": expiredID}))
+	if !result.IsError || !strings.Contains(resultText(result), "not found or expired") {
+		t.Errorf("expired plan should fail, got %s", resultText(result))
+	}
+}
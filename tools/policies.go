@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+// CreationPolicy is the set of rules tasks created in one project must meet.
+type CreationPolicy struct {
+	// RequireDue requires a due date.
+	RequireDue bool `json:"require_due"`
+	// Labels, when set, requires at least one of these labels.
+	Labels []string `json:"labels"`
+	// OnViolation is "reject" (the default) to refuse a task breaking the
+	// rules, or "fix" to add what is missing.
+	OnViolation string `json:"on_violation"`
+	// FixDue is the due string a fix adds; empty means "today".
+	FixDue string `json:"fix_due"`
+	// FixLabel is the label a fix adds; empty means the first of Labels.
+	FixLabel string `json:"fix_label"`
+}
+
+// CreationPolicies maps project IDs to the policy for tasks created there.
+type CreationPolicies map[string]CreationPolicy
+
+// ParseCreationPolicies parses and validates a CREATION_POLICIES_FILE: a JSON
+// object keyed by project ID. Empty input means no policies.
+func ParseCreationPolicies(data string) (CreationPolicies, error) {
+	policies := CreationPolicies{}
+	if strings.TrimSpace(data) == "" {
+		return policies, nil
+	}
+	if err := json.Unmarshal([]byte(data), &policies); err != nil {
+		return nil, fmt.Errorf("invalid creation policies: %w", err)
+	}
+	for projectID, policy := range policies {
+		if err := ValidateID(projectID, "creation policy project ID"); err != nil {
+			return nil, err
+		}
+		switch policy.OnViolation {
+		case "", "reject", "fix":
+		default:
+			return nil, fmt.Errorf("creation policy for project %s: on_violation must be reject or fix", projectID)
+		}
+		labels := make([]string, 0, len(policy.Labels))
+		for _, l := range policy.Labels {
+			if l = strings.TrimPrefix(strings.TrimSpace(l), "@"); l != "" {
+				labels = append(labels, l)
+			}
+		}
+		policy.Labels = labels
+		policy.FixLabel = strings.TrimPrefix(strings.TrimSpace(policy.FixLabel), "@")
+		if policy.FixLabel != "" && !containsFold(policy.Labels, policy.FixLabel) {
+			return nil, fmt.Errorf("creation policy for project %s: fix_label %q is not one of its labels", projectID, policy.FixLabel)
+		}
+		policies[projectID] = policy
+	}
+	return policies, nil
+}
+
+// containsFold reports whether list holds s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// projectOf returns the project a task creation body puts the task in: its
+// project_id, or that of its section or parent. Parents created earlier in
+// the same batch are looked up in pending, by temp ID. Tasks with none of
+// these go to the Inbox and return "".
+func (p CreationPolicies) projectOf(ctx context.Context, client todoist.API, body map[string]interface{}, pending map[string]string) (string, error) {
+	if projectID, _ := body["project_id"].(string); projectID != "" {
+		return projectID, nil
+	}
+	lookup := func(path string) (string, error) {
+		respBody, err := client.Get(ctx, path)
+		if err != nil {
+			return "", err
+		}
+		var entity map[string]interface{}
+		if err := json.Unmarshal(respBody, &entity); err != nil {
+			return "", err
+		}
+		projectID, _ := entity["project_id"].(string)
+		return projectID, nil
+	}
+	if sectionID, _ := body["section_id"].(string); sectionID != "" {
+		projectID, err := lookup(fmt.Sprintf("/sections/%s", sectionID))
+		if err != nil {
+			return "", fmt.Errorf("failed to look up the project of section %s: %w", sectionID, err)
+		}
+		return projectID, nil
+	}
+	if parentID, _ := body["parent_id"].(string); parentID != "" {
+		if projectID, ok := pending[parentID]; ok {
+			return projectID, nil
+		}
+		projectID, err := lookup(fmt.Sprintf("/tasks/%s", parentID))
+		if err != nil {
+			return "", fmt.Errorf("failed to look up the project of parent task %s: %w", parentID, err)
+		}
+		return projectID, nil
+	}
+	return "", nil
+}
+
+// enforce checks a task creation body against the policy of the project it
+// is created in, which it also returns. A policy set to fix adds what is
+// missing to body and returns the fields it set; otherwise a violation is
+// returned as an error.
+func (p CreationPolicies) enforce(ctx context.Context, client todoist.API, body map[string]interface{}, pending map[string]string) (string, map[string]interface{}, error) {
+	if len(p) == 0 {
+		return "", nil, nil
+	}
+	projectID, err := p.projectOf(ctx, client, body, pending)
+	if err != nil {
+		return "", nil, err
+	}
+	policy, ok := p[projectID]
+	if !ok {
+		return projectID, nil, nil
+	}
+
+	var missing []string
+	fixes := make(map[string]interface{})
+	if policy.RequireDue && body["due_string"] == nil && body["due_date"] == nil && body["due_datetime"] == nil {
+		missing = append(missing, "a due date")
+		due := policy.FixDue
+		if due == "" {
+			due = "today"
+		}
+		fixes["due_string"] = due
+	}
+	if len(policy.Labels) > 0 {
+		labels, _ := body["labels"].([]string)
+		if !hasAnyLabel(labels, policy.Labels) {
+			missing = append(missing, "one of the labels "+strings.Join(policy.Labels, ", "))
+			label := policy.FixLabel
+			if label == "" {
+				label = policy.Labels[0]
+			}
+			fixes["labels"] = append(append([]string{}, labels...), label)
+		}
+	}
+	if len(missing) == 0 {
+		return projectID, nil, nil
+	}
+	if policy.OnViolation != "fix" {
+		return "", nil, fmt.Errorf("project %s requires %s on new tasks; add what is missing and retry", projectID, strings.Join(missing, " and "))
+	}
+	for key, value := range fixes {
+		body[key] = value
+	}
+	return projectID, fixes, nil
+}
+
+// hasAnyLabel reports whether labels holds any of wanted, ignoring case.
+func hasAnyLabel(labels, wanted []string) bool {
+	for _, l := range labels {
+		if containsFold(wanted, l) {
+			return true
+		}
+	}
+	return false
+}
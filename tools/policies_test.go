@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestParseCreationPolicies(t *testing.T) {
+	policies, err := ParseCreationPolicies(`{"p1": {"require_due": true, "labels": ["@Bug", " feature "], "on_violation": "fix", "fix_label": "feature"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := policies["p1"]; !p.RequireDue || len(p.Labels) != 2 || p.Labels[0] != "Bug" || p.Labels[1] != "feature" || p.FixLabel != "feature" {
+		t.Errorf("policy = %+v", p)
+	}
+	if policies, err := ParseCreationPolicies("  "); err != nil || len(policies) != 0 {
+		t.Errorf("empty: %v, %v", policies, err)
+	}
+
+	tests := []struct {
+		data, want string
+	}{
+		{`[]`, "invalid creation policies"},
+		{`{"p1": {"on_violation": "warn"}}`, "on_violation must be reject or fix"},
+		{`{"p1": {"labels": ["bug"], "fix_label": "chore"}}`, `fix_label "chore" is not one of its labels`},
+		{`{"../p1": {"require_due": true}}`, "creation policy project ID"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseCreationPolicies(tt.data); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestCreationPolicies_Enforce(t *testing.T) {
+	policies := CreationPolicies{
+		"strict":  {RequireDue: true, Labels: []string{"bug", "feature"}},
+		"lenient": {RequireDue: true, Labels: []string{"bug", "feature"}, OnViolation: "fix", FixDue: "tomorrow", FixLabel: "feature"},
+	}
+	var gets []string
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		gets = append(gets, path)
+		switch path {
+		case "/sections/s1":
+			return []byte(`{"id":"s1","project_id":"strict"}`), nil
+		case "/tasks/t1":
+			return []byte(`{"id":"t1","project_id":"lenient"}`), nil
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}}
+
+	_, _, err := policies.enforce(context.Background(), client, map[string]interface{}{"section_id": "s1", "labels": []string{"Bug"}}, nil)
+	if err == nil || err.Error() != "project strict requires a due date on new tasks; add what is missing and retry" {
+		t.Errorf("strict error = %v", err)
+	}
+
+	body := map[string]interface{}{"parent_id": "t1", "labels": []string{"urgent"}}
+	projectID, fixes, err := policies.enforce(context.Background(), client, body, nil)
+	if err != nil || projectID != "lenient" || len(fixes) != 2 || body["due_string"] != "tomorrow" {
+		t.Fatalf("lenient: project %q, fixes %v, err %v", projectID, fixes, err)
+	}
+	if labels, _ := body["labels"].([]string); len(labels) != 2 || labels[0] != "urgent" || labels[1] != "feature" {
+		t.Errorf("labels = %v", body["labels"])
+	}
+
+	// Parents from the same batch resolve without a request; other projects
+	// and the Inbox have no policy.
+	gets = nil
+	for _, body := range []map[string]interface{}{
+		{"parent_id": "$tmp", "due_string": "today", "labels": []string{"bug"}},
+		{"project_id": "other"},
+		{},
+	} {
+		if _, fixes, err := policies.enforce(context.Background(), client, body, map[string]string{"$tmp": "strict"}); err != nil || fixes != nil {
+			t.Errorf("%v: fixes %v, err %v", body, fixes, err)
+		}
+	}
+	if len(gets) != 0 {
+		t.Errorf("requests = %v", gets)
+	}
+}
+
+func TestCreateTaskHandler_Policies(t *testing.T) {
+	policies := CreationPolicies{"p-team": {Labels: []string{"agent"}}}
+	posted := false
+	client := &MockAPI{PostFn: func(_ context.Context, _ string, body interface{}) ([]byte, error) {
+		posted = true
+		return json.Marshal(map[string]interface{}{"id": "1"})
+	}}
+	result, _ := CreateTaskHandler(client, TaskDefaults{}, policies)(context.Background(), makeReq(map[string]interface{}{"content": "Ship it", "project_id": "p-team"}))
+	if text := resultText(result); !result.IsError || !strings.Contains(text, "task not created: project p-team requires one of the labels agent") || posted {
+		t.Errorf("result = %s, posted = %v", text, posted)
+	}
+
+	// Defaults are applied first and can satisfy the policy.
+	result, _ = CreateTaskHandler(client, TaskDefaults{Labels: []string{"agent"}}, policies)(context.Background(), makeReq(map[string]interface{}{"content": "Ship it", "project_id": "p-team"}))
+	if text := resultText(result); result.IsError || strings.Contains(text, "policy_fixes") || !posted {
+		t.Errorf("with default label: result = %s", text)
+	}
+}
+
+func TestBatchCreateTasksHandler_Policies(t *testing.T) {
+	policies := CreationPolicies{"p-team": {RequireDue: true, OnViolation: "fix"}}
+	var sent []todoist.Command
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		sent = commands
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+	handler := BatchCreateTasksHandler(&MockAPI{}, syncClient, policies)
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"tasks": []interface{}{
+		map[string]interface{}{"content": "Parent", "project_id": "p-team", "due_string": "friday"},
+		map[string]interface{}{"content": "Child", "parent_temp_id": "0"},
+		map[string]interface{}{"content": "Elsewhere", "project_id": "p-me"},
+	}}))
+	if result.IsError || len(sent) != 3 {
+		t.Fatalf("result = %s", resultText(result))
+	}
+	if sent[0].Args["due_string"] != "friday" || sent[1].Args["due_string"] != "today" || sent[2].Args["due_string"] != nil {
+		t.Errorf("sent = %+v", sent)
+	}
+	var resp struct {
+		CreatedTasks []map[string]interface{} `json:"created_tasks"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.CreatedTasks[0]["policy_fixes"] != nil || resp.CreatedTasks[1]["policy_fixes"] == nil {
+		t.Errorf("created_tasks = %v", resp.CreatedTasks)
+	}
+}
@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// productivityStats is the part of the API v1 productivity stats that
+// get_productivity_stats and get_goal_progress read.
+type productivityStats struct {
+	CompletedCount  int     `json:"completed_count"`
+	Karma           float64 `json:"karma"`
+	KarmaTrend      string  `json:"karma_trend"`
+	KarmaLastUpdate float64 `json:"karma_last_update"`
+	KarmaGraphData  []struct {
+		Date     string  `json:"date"`
+		KarmaAvg float64 `json:"karma_avg"`
+	} `json:"karma_graph_data"`
+	DaysItems []struct {
+		Date           string               `json:"date"`
+		TotalCompleted int                  `json:"total_completed"`
+		Items          []projectCompletions `json:"items"`
+	} `json:"days_items"`
+	WeekItems []struct {
+		From           string               `json:"from"`
+		To             string               `json:"to"`
+		TotalCompleted int                  `json:"total_completed"`
+		Items          []projectCompletions `json:"items"`
+	} `json:"week_items"`
+	Goals struct {
+		DailyGoal           int        `json:"daily_goal"`
+		WeeklyGoal          int        `json:"weekly_goal"`
+		IgnoreDays          []int      `json:"ignore_days"`
+		VacationMode        int        `json:"vacation_mode"`
+		KarmaDisabled       int        `json:"karma_disabled"`
+		CurrentDailyStreak  goalStreak `json:"current_daily_streak"`
+		LastDailyStreak     goalStreak `json:"last_daily_streak"`
+		MaxDailyStreak      goalStreak `json:"max_daily_streak"`
+		CurrentWeeklyStreak goalStreak `json:"current_weekly_streak"`
+		LastWeeklyStreak    goalStreak `json:"last_weekly_streak"`
+		MaxWeeklyStreak     goalStreak `json:"max_weekly_streak"`
+	} `json:"goals"`
+}
+
+// projectCompletions is a per-project completion count in productivity stats.
+type projectCompletions struct {
+	ID        string `json:"id"`
+	Completed int    `json:"completed"`
+}
+
+// goalStreak is a run of days or weeks meeting the goal.
+type goalStreak struct {
+	Count int    `json:"count"`
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// GetProductivityStatsHandler creates a handler reporting karma, daily and
+// weekly completion counts, goals, and streaks from the productivity stats
+// endpoint. Unlike get_task_stats, it covers completed work.
+func GetProductivityStatsHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		includeHistory, _ := args["include_karma_history"].(bool)
+
+		respBody, err := syncClient.Get(ctx, "/tasks/completed/stats")
+		if err != nil {
+			return respond.Errorf("failed to fetch productivity stats: %v", err), nil
+		}
+		var stats productivityStats
+		if err := json.Unmarshal(respBody, &stats); err != nil {
+			return respond.Errorf("failed to parse productivity stats: %v", err), nil
+		}
+
+		response := respond.Envelope{}
+
+		// Project names are a convenience; counts keyed by ID still answer
+		// the question when they cannot be fetched.
+		names, err := fetchProjectNames(ctx, client)
+		if err != nil {
+			response.Warn("project names unavailable: %v", err)
+		}
+		byProject := func(items []projectCompletions) []map[string]interface{} {
+			projects := make([]map[string]interface{}, 0, len(items))
+			for _, item := range items {
+				entry := map[string]interface{}{"project_id": item.ID, "completed": item.Completed}
+				if name, ok := names[item.ID]; ok {
+					entry["project_name"] = name
+				}
+				projects = append(projects, entry)
+			}
+			return projects
+		}
+
+		days := make([]map[string]interface{}, 0, len(stats.DaysItems))
+		for _, day := range stats.DaysItems {
+			days = append(days, map[string]interface{}{
+				"date":       day.Date,
+				"completed":  day.TotalCompleted,
+				"by_project": byProject(day.Items),
+			})
+		}
+		weeks := make([]map[string]interface{}, 0, len(stats.WeekItems))
+		for _, week := range stats.WeekItems {
+			weeks = append(weeks, map[string]interface{}{
+				"from":       week.From,
+				"to":         week.To,
+				"completed":  week.TotalCompleted,
+				"by_project": byProject(week.Items),
+			})
+		}
+
+		karma := map[string]interface{}{
+			"score":    stats.Karma,
+			"trend":    stats.KarmaTrend,
+			"disabled": stats.Goals.KarmaDisabled == 1,
+		}
+		if stats.KarmaLastUpdate != 0 {
+			karma["last_change"] = stats.KarmaLastUpdate
+		}
+		if includeHistory {
+			history := make([]map[string]interface{}, 0, len(stats.KarmaGraphData))
+			for _, point := range stats.KarmaGraphData {
+				history = append(history, map[string]interface{}{"date": point.Date, "karma": point.KarmaAvg})
+			}
+			karma["history"] = history
+		}
+
+		ignoreDays := stats.Goals.IgnoreDays
+		if ignoreDays == nil {
+			ignoreDays = []int{}
+		}
+		response["karma"] = karma
+		response["completed_count"] = stats.CompletedCount
+		response["goals"] = map[string]interface{}{
+			"daily":         stats.Goals.DailyGoal,
+			"weekly":        stats.Goals.WeeklyGoal,
+			"ignore_days":   ignoreDays,
+			"vacation_mode": stats.Goals.VacationMode == 1,
+		}
+		response["streaks"] = map[string]interface{}{
+			"daily": map[string]interface{}{
+				"current": stats.Goals.CurrentDailyStreak,
+				"last":    stats.Goals.LastDailyStreak,
+				"max":     stats.Goals.MaxDailyStreak,
+			},
+			"weekly": map[string]interface{}{
+				"current": stats.Goals.CurrentWeeklyStreak,
+				"last":    stats.Goals.LastWeeklyStreak,
+				"max":     stats.Goals.MaxWeeklyStreak,
+			},
+		}
+		response["days"] = days
+		response["weeks"] = weeks
+
+		return respond.JSON(response), nil
+	}
+}
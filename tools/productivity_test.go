@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const productivityStatsFixture = `{
+	"completed_count": 4211, "karma": 12840, "karma_trend": "up", "karma_last_update": 5,
+	"karma_graph_data": [{"date": "2026-01-12", "karma_avg": 12800}, {"date": "2026-01-13", "karma_avg": 12840}],
+	"days_items": [{"date": "2026-01-13", "total_completed": 3, "items": [{"id": "p1", "completed": 2}, {"id": "p9", "completed": 1}]}],
+	"week_items": [{"from": "2026-01-12", "to": "2026-01-18", "total_completed": 14, "items": [{"id": "p1", "completed": 14}]}],
+	"goals": {"daily_goal": 5, "weekly_goal": 25, "ignore_days": [6, 7], "vacation_mode": 0, "karma_disabled": 0,
+		"current_daily_streak": {"count": 6, "start": "2026-01-08", "end": "2026-01-13"},
+		"last_daily_streak": {"count": 6, "start": "2026-01-08", "end": "2026-01-13"},
+		"max_daily_streak": {"count": 21, "start": "2025-10-01", "end": "2025-10-21"},
+		"current_weekly_streak": {"count": 2}, "last_weekly_streak": {"count": 2}, "max_weekly_streak": {"count": 9}},
+	"project_colors": {"p1": "red"}
+}`
+
+func TestGetProductivityStatsHandler(t *testing.T) {
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if path != "/tasks/completed/stats" {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return []byte(productivityStatsFixture), nil
+	}}
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		return []byte(`[{"id": "p1", "name": "Work"}]`), nil
+	}}
+
+	result, err := GetProductivityStatsHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+
+	var resp struct {
+		Karma          map[string]interface{} `json:"karma"`
+		CompletedCount int                    `json:"completed_count"`
+		Goals          struct {
+			Daily      int   `json:"daily"`
+			IgnoreDays []int `json:"ignore_days"`
+		} `json:"goals"`
+		Streaks map[string]map[string]goalStreak `json:"streaks"`
+		Days    []struct {
+			Date      string                   `json:"date"`
+			Completed int                      `json:"completed"`
+			ByProject []map[string]interface{} `json:"by_project"`
+		} `json:"days"`
+		Weeks    []map[string]interface{} `json:"weeks"`
+		Warnings []string                 `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Karma["score"] != float64(12840) || resp.Karma["trend"] != "up" || resp.Karma["history"] != nil {
+		t.Errorf("karma = %v", resp.Karma)
+	}
+	if resp.CompletedCount != 4211 || resp.Goals.Daily != 5 || len(resp.Goals.IgnoreDays) != 2 {
+		t.Errorf("completed_count = %d, goals = %+v", resp.CompletedCount, resp.Goals)
+	}
+	if got := resp.Streaks["daily"]["max"]; got.Count != 21 || got.Start != "2025-10-01" {
+		t.Errorf("max daily streak = %+v", got)
+	}
+	if len(resp.Days) != 1 || resp.Days[0].Completed != 3 || len(resp.Days[0].ByProject) != 2 {
+		t.Fatalf("days = %+v", resp.Days)
+	}
+	if resp.Days[0].ByProject[0]["project_name"] != "Work" || resp.Days[0].ByProject[1]["project_name"] != nil {
+		t.Errorf("by_project = %v, want Work named and p9 left as an ID", resp.Days[0].ByProject)
+	}
+	if len(resp.Weeks) != 1 || resp.Weeks[0]["completed"] != float64(14) || len(resp.Warnings) != 0 {
+		t.Errorf("weeks = %v, warnings = %v", resp.Weeks, resp.Warnings)
+	}
+}
+
+func TestGetProductivityStatsHandler_KarmaHistory(t *testing.T) {
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		return []byte(productivityStatsFixture), nil
+	}}
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		return nil, fmt.Errorf("rate limited")
+	}}
+
+	result, _ := GetProductivityStatsHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{"include_karma_history": true}))
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(result))
+	}
+	text := resultText(result)
+	if !strings.Contains(text, `"history"`) || !strings.Contains(text, `"karma": 12800`) {
+		t.Errorf("expected karma history: %s", text)
+	}
+	if !strings.Contains(text, "project names unavailable") {
+		t.Errorf("expected a warning for missing project names: %s", text)
+	}
+}
+
+func TestGetProductivityStatsHandler_Error(t *testing.T) {
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		return nil, fmt.Errorf("boom")
+	}}
+	result, _ := GetProductivityStatsHandler(&MockAPI{}, syncClient)(context.Background(), makeReq(map[string]interface{}{}))
+	if !result.IsError || !strings.Contains(resultText(result), "failed to fetch productivity stats") {
+		t.Errorf("expected an error, got %s", resultText(result))
+	}
+}
@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Profile selects a curated subset of tools for clients, typically smaller
+// models, that cope poorly with the full catalog.
+type Profile struct {
+	Name string
+	// Groups whose tools are all included.
+	Groups []string
+	// Tools included individually, whatever their group.
+	Tools []string
+	// Admin includes ungrouped administrative tools such as configure_tool_groups.
+	Admin bool
+	// RawSync includes the raw Sync API tools, which bypass the checks of the
+	// dedicated tools. Only a profile selected by name may set it.
+	RawSync bool
+	// Descriptions replace the default description of included tools.
+	Descriptions map[string]string
+}
+
+// basicDescriptions are shorter descriptions for the basic profile, which is
+// aimed at models with small context windows.
+var basicDescriptions = map[string]string{
+	"search_tasks":  "Find active tasks. Pass a Todoist filter such as \"today | overdue\" or \"#Work & p1\", or a project_id. Returns tasks with id, content, due, priority, and labels.",
+	"create_task":   "Create a task. Call list_projects first for project_id. priority: 1 normal to 4 urgent. due_string accepts natural language like \"tomorrow 5pm\".",
+	"update_task":   "Change a task's content, description, due date, priority, or labels. Only the fields you pass are changed.",
+	"list_projects": "List projects with their id and name. Use the id as project_id in other tools.",
+}
+
+// rawSyncTools are the ungrouped tools exposed only by RawSync profiles.
+var rawSyncTools = []string{"execute_commands", "full_sync"}
+
+// Profiles are the predefined tool profiles, selected with TOOL_PROFILE.
+var Profiles = map[string]Profile{
+	"basic": {
+		Name: "basic",
+		Tools: []string{
+			"search_tasks", "get_task", "create_task", "quick_add_task", "update_task",
+			"complete_task", "delete_task", "get_due_soon",
+			"list_projects", "list_sections", "list_labels", "get_context_bundle",
+			"get_comments", "add_comment",
+		},
+		Descriptions: basicDescriptions,
+	},
+	"reporting": {
+		Name:   "reporting",
+		Groups: []string{"reports"},
+		Tools: []string{
+			"search_tasks", "search_all", "get_task", "get_tasks", "get_task_stats", "get_task_history",
+			"search_completed", "get_completed_tasks", "get_due_soon", "list_projects", "get_project", "get_project_stats",
+			"list_sections", "check_wip_limits", "list_labels", "get_comments", "get_time_log",
+			"list_favorites", "list_recent_operations", "get_server_info", "get_context_bundle",
+		},
+	},
+	"power": {
+		Name: "power",
+		Groups: []string{
+			"tasks", "projects", "sections", "labels", "comments", "reports",
+			"planning", "favorites", "session", "templates", "integrations",
+		},
+	},
+	"full": {
+		Name:  "full",
+		Admin: true,
+		Groups: []string{
+			"tasks", "projects", "sections", "labels", "comments", "maintenance", "reports",
+			"planning", "favorites", "session", "templates", "integrations",
+		},
+	},
+	"admin": {
+		Name:    "admin",
+		Admin:   true,
+		RawSync: true,
+		Groups: []string{
+			"tasks", "projects", "sections", "labels", "comments", "maintenance", "reports",
+			"planning", "favorites", "session", "templates", "integrations",
+		},
+	},
+}
+
+// LookupProfile returns the named profile. An empty name selects full, every
+// tool except the raw Sync tools, which must be asked for with admin.
+func LookupProfile(name string) (Profile, error) {
+	if name == "" {
+		name = "full"
+	}
+	profile, ok := Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(Profiles))
+		for n := range Profiles {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+		return Profile{}, fmt.Errorf("unknown tool profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return profile, nil
+}
+
+// includes reports whether the profile exposes tool from group. An empty
+// group marks an ungrouped administrative tool.
+func (p Profile) includes(group, tool string) bool {
+	if group == "" {
+		if slices.Contains(rawSyncTools, tool) {
+			return p.RawSync
+		}
+		return p.Admin
+	}
+	return slices.Contains(p.Groups, group) || slices.Contains(p.Tools, tool)
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestLookupProfile(t *testing.T) {
+	p, err := LookupProfile("")
+	if err != nil || p.Name != "full" || !p.Admin || p.RawSync {
+		t.Errorf("LookupProfile(\"\") = %+v, %v, want full without raw Sync tools", p, err)
+	}
+	if _, err := LookupProfile("tiny"); err == nil || !strings.Contains(err.Error(), "available: admin, basic, full, power, reporting") {
+		t.Errorf("error = %v, want unknown profile error listing profiles", err)
+	}
+}
+
+func TestToolGroups_Profile(t *testing.T) {
+	srv := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	groups := NewToolGroups(srv)
+	basic, _ := LookupProfile("basic")
+	groups.SetProfile(basic)
+
+	groups.Add("tasks", mcp.NewTool("create_task", mcp.WithDescription("long description")), noopHandler)
+	groups.Add("tasks", mcp.NewTool("bulk_delete_tasks"), noopHandler)
+	groups.Add("maintenance", mcp.NewTool("cleanup_workspace"), noopHandler)
+	groups.AddPinned(mcp.NewTool("configure_tool_groups"), noopHandler)
+
+	if len(srv.ListTools()) != 1 {
+		t.Fatalf("tools = %v, want only create_task", srv.ListTools())
+	}
+	if got := srv.GetTool("create_task").Tool.Description; got != basicDescriptions["create_task"] {
+		t.Errorf("create_task description = %q, want the basic profile's", got)
+	}
+	if status := groups.Status(); len(status) != 1 || status[0].Name != "tasks" {
+		t.Errorf("Status() = %+v, want only tasks", status)
+	}
+	// Disabling a group the profile left out is not an error.
+	if err := groups.Apply([]string{"maintenance"}); err != nil {
+		t.Errorf("Apply() error: %v", err)
+	}
+}
+
+func TestToolGroups_RawSyncNeedsAdmin(t *testing.T) {
+	for _, tt := range []struct {
+		profile string
+		want    int
+	}{
+		{profile: "", want: 1},
+		{profile: "admin", want: 3},
+	} {
+		srv := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+		groups := NewToolGroups(srv)
+		p, _ := LookupProfile(tt.profile)
+		groups.SetProfile(p)
+
+		groups.AddPinned(mcp.NewTool("configure_tool_groups"), noopHandler)
+		groups.AddPinned(mcp.NewTool("execute_commands"), noopHandler)
+		groups.AddPinned(mcp.NewTool("full_sync"), noopHandler)
+
+		if got := len(srv.ListTools()); got != tt.want {
+			t.Errorf("profile %q: %d tools, want %d", tt.profile, got, tt.want)
+		}
+	}
+}
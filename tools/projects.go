@@ -4,9 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
 )
 
 // ListProjectsHandler creates a handler for listing all projects.
@@ -14,25 +20,17 @@ func ListProjectsHandler(client todoist.API) func(context.Context, mcp.CallToolR
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		respBody, err := client.Get(ctx, "/projects")
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to list projects: %v", err)), nil
+			return respond.Errorf("failed to list projects: %v", err), nil
 		}
 
-		var projects []map[string]interface{}
+		var projects []models.Project
 		if err := json.Unmarshal(respBody, &projects); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse projects: %v", err)), nil
+			return respond.Errorf("failed to parse projects: %v", err), nil
 		}
 
-		response := map[string]interface{}{
-			"count":    len(projects),
-			"projects": projects,
-		}
-
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
-		}
+		response := respond.List("projects", projects)
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
 
@@ -43,7 +41,7 @@ func CreateProjectHandler(client todoist.API) func(context.Context, mcp.CallTool
 
 		name, ok := args["name"].(string)
 		if !ok || name == "" {
-			return mcp.NewToolResultError("name is required"), nil
+			return respond.Error("name is required"), nil
 		}
 
 		body := map[string]interface{}{
@@ -53,6 +51,9 @@ func CreateProjectHandler(client todoist.API) func(context.Context, mcp.CallTool
 		if parentID, ok := args["parent_id"].(string); ok && parentID != "" {
 			body["parent_id"] = parentID
 		}
+		if description, ok := args["description"].(string); ok && description != "" {
+			body["description"] = description
+		}
 		if color, ok := args["color"].(string); ok && color != "" {
 			body["color"] = color
 		}
@@ -65,20 +66,15 @@ func CreateProjectHandler(client todoist.API) func(context.Context, mcp.CallTool
 
 		respBody, err := client.Post(ctx, "/projects", body)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to create project: %v", err)), nil
+			return respond.Errorf("failed to create project: %v", err), nil
 		}
 
-		var project map[string]interface{}
+		var project models.Project
 		if err := json.Unmarshal(respBody, &project); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse response: %v", err)), nil
+			return respond.Errorf("failed to parse response: %v", err), nil
 		}
 
-		jsonData, err := json.MarshalIndent(project, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(project), nil
 	}
 }
 
@@ -89,29 +85,24 @@ func GetProjectHandler(client todoist.API) func(context.Context, mcp.CallToolReq
 
 		projectID, ok := args["project_id"].(string)
 		if !ok || projectID == "" {
-			return mcp.NewToolResultError("project_id is required"), nil
+			return respond.Error("project_id is required"), nil
 		}
 		if err := ValidateID(projectID, "project_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
 		path := fmt.Sprintf("/projects/%s", projectID)
 		respBody, err := client.Get(ctx, path)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %v", err)), nil
+			return respond.Errorf("failed to get project: %v", err), nil
 		}
 
-		var project map[string]interface{}
+		var project models.Project
 		if err := json.Unmarshal(respBody, &project); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse project: %v", err)), nil
+			return respond.Errorf("failed to parse project: %v", err), nil
 		}
 
-		jsonData, err := json.MarshalIndent(project, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(project), nil
 	}
 }
 
@@ -122,10 +113,10 @@ func UpdateProjectHandler(client todoist.API) func(context.Context, mcp.CallTool
 
 		projectID, ok := args["project_id"].(string)
 		if !ok || projectID == "" {
-			return mcp.NewToolResultError("project_id is required"), nil
+			return respond.Error("project_id is required"), nil
 		}
 		if err := ValidateID(projectID, "project_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
 		body := map[string]interface{}{}
@@ -133,6 +124,10 @@ func UpdateProjectHandler(client todoist.API) func(context.Context, mcp.CallTool
 		if name, ok := args["name"].(string); ok && name != "" {
 			body["name"] = name
 		}
+		// An empty description is sent as-is so it can be cleared.
+		if description, ok := args["description"].(string); ok {
+			body["description"] = description
+		}
 		if color, ok := args["color"].(string); ok && color != "" {
 			body["color"] = color
 		}
@@ -144,59 +139,361 @@ func UpdateProjectHandler(client todoist.API) func(context.Context, mcp.CallTool
 		}
 
 		if len(body) == 0 {
-			return mcp.NewToolResultError("at least one field to update must be provided"), nil
+			return respond.Error("at least one field to update must be provided"), nil
 		}
 
 		path := fmt.Sprintf("/projects/%s", projectID)
+		skipIfUnchanged, _ := args["skip_if_unchanged"].(bool)
+		if skipIfUnchanged {
+			current, err := fetchEntity(ctx, client, path)
+			if err != nil {
+				return respond.Errorf("failed to get current project: %v", err), nil
+			}
+			normalizeProject(current)
+			if updateIsNoop(current, body) {
+				current["changed"] = false
+				return respond.JSON(current), nil
+			}
+		}
+
 		respBody, err := client.Post(ctx, path, body)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to update project: %v", err)), nil
+			return respond.Errorf("failed to update project: %v", err), nil
 		}
 
-		var project map[string]interface{}
+		var project models.Project
 		if err := json.Unmarshal(respBody, &project); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse response: %v", err)), nil
+			return respond.Errorf("failed to parse response: %v", err), nil
 		}
-
-		jsonData, err := json.MarshalIndent(project, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		if skipIfUnchanged {
+			project.Set("changed", true)
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(project), nil
+	}
+}
+
+// normalizeProject makes the description field always present, since older
+// API responses omit it for projects that have never had one. Unified-API fields
+// such as workspace_id and folder_id are passed through untouched where present.
+func normalizeProject(project map[string]interface{}) {
+	if _, ok := project["description"].(string); !ok {
+		project["description"] = ""
 	}
 }
 
-// DeleteProjectHandler creates a handler for deleting a project.
-func DeleteProjectHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// DeleteProjectHandler creates a handler for deleting a project. When a
+// backup is requested, or on by default in backups, the project is archived
+// first and is not deleted if the archive cannot be written.
+func DeleteProjectHandler(client todoist.API, backups *BackupStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 
 		projectID, ok := args["project_id"].(string)
 		if !ok || projectID == "" {
-			return mcp.NewToolResultError("project_id is required"), nil
+			return respond.Error("project_id is required"), nil
 		}
 		if err := ValidateID(projectID, "project_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
+		}
+
+		backup, ok := args["backup"].(bool)
+		if !ok {
+			backup = backups.byDefault
+		}
+		var backupFile string
+		var backedUp *ProjectBackup
+		if backup {
+			var err error
+			if backedUp, err = buildProjectBackup(ctx, client, projectID); err != nil {
+				return respond.Errorf("project not deleted: backup failed: %v", err), nil
+			}
+			if backupFile, err = backups.Write(backedUp); err != nil {
+				return respond.Errorf("project not deleted: backup failed: %v", err), nil
+			}
 		}
 
 		path := fmt.Sprintf("/projects/%s", projectID)
 		err := client.Delete(ctx, path)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to delete project: %v", err)), nil
+			return respond.Errorf("failed to delete project: %v", err), nil
 		}
 
 		response := map[string]interface{}{
 			"success":    true,
 			"project_id": projectID,
-			"message":    "Project deleted successfully",
+			"message":    localize("project_deleted"),
+		}
+		if backedUp != nil {
+			response["backup"] = map[string]interface{}{
+				"file":     backupFile,
+				"projects": len(backedUp.Projects),
+				"sections": len(backedUp.Sections),
+				"tasks":    len(backedUp.Tasks),
+			}
 		}
 
-		jsonData, err := json.MarshalIndent(response, "", "  ")
+		return respond.JSON(response), nil
+	}
+}
+
+// descendantProjectIDs returns projectID followed by the IDs of all projects
+// nested beneath it, at any depth.
+func descendantProjectIDs(ctx context.Context, client todoist.API, projectID string) ([]string, error) {
+	respBody, err := client.Get(ctx, "/projects")
+	if err != nil {
+		return nil, err
+	}
+	var projects []models.Project
+	if err := json.Unmarshal(respBody, &projects); err != nil {
+		return nil, fmt.Errorf("failed to parse projects: %w", err)
+	}
+	return descendantsIn(projects, projectID), nil
+}
+
+// descendantsIn returns projectID followed by the IDs of all projects nested
+// beneath it in projects, at any depth.
+func descendantsIn(projects []models.Project, projectID string) []string {
+	children := make(map[string][]string)
+	for _, proj := range projects {
+		if proj.ParentID != "" {
+			children[proj.ParentID] = append(children[proj.ParentID], proj.ID)
+		}
+	}
+
+	ids := []string{projectID}
+	seen := map[string]bool{projectID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// GetProjectStatsHandler creates a handler for aggregate statistics about a single project.
+func GetProjectStatsHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectID, ok := args["project_id"].(string)
+		if !ok || projectID == "" {
+			return respond.Error("project_id is required"), nil
+		}
+		if err := ValidateID(projectID, "project_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		projectBody, err := client.Get(ctx, fmt.Sprintf("/projects/%s", projectID))
+		if err != nil {
+			return respond.Errorf("failed to get project: %v", err), nil
+		}
+		var project models.Project
+		if err := json.Unmarshal(projectBody, &project); err != nil {
+			return respond.Errorf("failed to parse project: %v", err), nil
+		}
+
+		includeSubprojects, _ := args["include_subprojects"].(bool)
+		projectIDs := []string{projectID}
+		tasksPath, sectionsPath := "/tasks", "/sections"
+		if includeSubprojects {
+			projectIDs, err = descendantProjectIDs(ctx, client, projectID)
+			if err != nil {
+				return respond.Errorf("failed to resolve sub-projects: %v", err), nil
+			}
+		} else {
+			params := url.Values{}
+			params.Set("project_id", projectID)
+			tasksPath += "?" + params.Encode()
+			sectionsPath += "?" + params.Encode()
+		}
+		tasksBody, err := client.Get(ctx, tasksPath)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			return respond.Errorf("failed to fetch tasks: %v", err), nil
+		}
+		var allTasks []models.Task
+		if err := json.Unmarshal(tasksBody, &allTasks); err != nil {
+			return respond.Errorf("failed to parse tasks: %v", err), nil
+		}
+		tasks := allTasks
+		if includeSubprojects {
+			tasks = make([]models.Task, 0, len(allTasks))
+			for _, task := range allTasks {
+				if slices.Contains(projectIDs, task.ProjectID) {
+					tasks = append(tasks, task)
+				}
+			}
+		}
+
+		sectionNames := make(map[string]string)
+		if sectionsBody, err := client.Get(ctx, sectionsPath); err == nil {
+			var sections []models.Section
+			if json.Unmarshal(sectionsBody, &sections) == nil {
+				for _, section := range sections {
+					if includeSubprojects && !slices.Contains(projectIDs, section.ProjectID) {
+						continue
+					}
+					sectionNames[section.ID] = section.Name
+				}
+			}
+		}
+
+		collaboratorNames := make(map[string]string)
+		if collabBody, err := client.Get(ctx, fmt.Sprintf("/projects/%s/collaborators", projectID)); err == nil {
+			var collaborators []map[string]interface{}
+			if json.Unmarshal(collabBody, &collaborators) == nil {
+				for _, c := range collaborators {
+					if id, ok := c["id"].(string); ok {
+						name, _ := c["name"].(string)
+						collaboratorNames[id] = name
+					}
+				}
+			}
+		}
+
+		byPriority := map[string]int{"p1": 0, "p2": 0, "p3": 0, "p4": 0}
+		bySection := make(map[string]int)
+		byAssignee := make(map[string]int)
+		overdue := 0
+		today := time.Now().Format("2006-01-02")
+
+		for _, name := range sectionNames {
+			bySection[name] = 0
+		}
+
+		for _, task := range tasks {
+			switch task.Priority {
+			case 4:
+				byPriority["p1"]++
+			case 3:
+				byPriority["p2"]++
+			case 2:
+				byPriority["p3"]++
+			case 1:
+				byPriority["p4"]++
+			}
+
+			sectionName := "(no section)"
+			if task.SectionID != "" {
+				sectionName = sectionNames[task.SectionID]
+				if sectionName == "" {
+					sectionName = task.SectionID
+				}
+			}
+			bySection[sectionName]++
+
+			assignee := "(unassigned)"
+			if task.AssigneeID != "" {
+				assignee = collaboratorNames[task.AssigneeID]
+				if assignee == "" {
+					assignee = task.AssigneeID
+				}
+			}
+			byAssignee[assignee]++
+
+			if dueDate := task.Due.Day(); dueDate != "" && dueDate < today {
+				overdue++
+			}
+		}
+
+		var lastActivity interface{}
+		activityParams := url.Values{}
+		activityParams.Set("parent_project_id", projectID)
+		activityParams.Set("limit", "1")
+		if activityBody, err := syncClient.Get(ctx, "/activities?"+activityParams.Encode()); err == nil {
+			var activity struct {
+				Results []map[string]interface{} `json:"results"`
+			}
+			if json.Unmarshal(activityBody, &activity) == nil && len(activity.Results) > 0 {
+				lastActivity = activity.Results[0]["event_date"]
+			}
+		}
+
+		response := map[string]interface{}{
+			"project_id":       projectID,
+			"project_name":     project.Name,
+			"active_tasks":     len(tasks),
+			"overdue":          overdue,
+			"by_priority":      byPriority,
+			"by_section":       bySection,
+			"by_assignee":      byAssignee,
+			"last_activity_at": lastActivity,
+		}
+		if includeSubprojects {
+			response["project_ids"] = projectIDs
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// projectViewStyles lists the view styles accepted by the Todoist API.
+var projectViewStyles = []string{"list", "board", "calendar"}
+
+// BulkSetViewStyleHandler creates a handler for switching the view style of many projects at once.
+func BulkSetViewStyleHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		viewStyle, ok := args["view_style"].(string)
+		if !ok || viewStyle == "" {
+			return respond.Error("view_style is required"), nil
+		}
+		if !slices.Contains(projectViewStyles, viewStyle) {
+			return respond.Errorf("view_style must be one of: %s", strings.Join(projectViewStyles, ", ")), nil
+		}
+
+		projectIDsParam, ok := args["project_ids"].([]interface{})
+		if !ok || len(projectIDsParam) == 0 {
+			return respond.Error("project_ids array is required and must not be empty"), nil
+		}
+		if len(projectIDsParam) > 100 {
+			return respond.Error("maximum 100 projects per batch"), nil
+		}
+
+		commands := make([]todoist.Command, 0, len(projectIDsParam))
+		for i, id := range projectIDsParam {
+			projectID, ok := id.(string)
+			if !ok || projectID == "" {
+				return respond.Errorf("project_ids[%d] must be a non-empty string", i), nil
+			}
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			commands = append(commands, todoist.Command{
+				Type: "project_update",
+				UUID: todoist.GenerateUUID(),
+				Args: map[string]interface{}{
+					"id":         projectID,
+					"view_style": viewStyle,
+				},
+			})
+		}
+
+		syncResp, err := syncClient.BatchCommands(ctx, commands)
+		if err != nil {
+			return respond.Errorf("failed to update view styles: %v", err), nil
+		}
+
+		succeeded := 0
+		failed := make([]interface{}, 0)
+		for _, cmd := range commands {
+			if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+				succeeded++
+			} else {
+				failed = append(failed, cmd.Args["id"])
+			}
+		}
+
+		response := map[string]interface{}{
+			"view_style": viewStyle,
+			"succeeded":  succeeded,
+			"failed_ids": failed,
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
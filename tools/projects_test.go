@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
 )
 
 func TestListProjectsHandler(t *testing.T) {
@@ -102,6 +104,17 @@ func TestCreateProjectHandler(t *testing.T) {
 				return json.Marshal(map[string]interface{}{"id": "1", "name": "New Project"})
 			},
 		},
+		{
+			name: "with description",
+			args: map[string]interface{}{"name": "New Project", "description": "Quarterly goals"},
+			mockPost: func(_ context.Context, _ string, body interface{}) ([]byte, error) {
+				b := body.(map[string]interface{})
+				if b["description"] != "Quarterly goals" {
+					return nil, fmt.Errorf("missing description")
+				}
+				return json.Marshal(map[string]interface{}{"id": "1", "name": "New Project", "description": "Quarterly goals"})
+			},
+		},
 		{
 			name:      "missing name",
 			args:      map[string]interface{}{},
@@ -197,6 +210,13 @@ func TestGetProjectHandler(t *testing.T) {
 			if result.IsError {
 				t.Fatalf("unexpected tool error: %s", text)
 			}
+			var project map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &project); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if _, ok := project["description"].(string); !ok {
+				t.Errorf("description missing from response: %v", project)
+			}
 		})
 	}
 }
@@ -219,6 +239,17 @@ func TestUpdateProjectHandler(t *testing.T) {
 				return json.Marshal(map[string]interface{}{"id": "123", "name": "Renamed"})
 			},
 		},
+		{
+			name: "clear description",
+			args: map[string]interface{}{"project_id": "123", "description": ""},
+			mockPost: func(_ context.Context, _ string, body interface{}) ([]byte, error) {
+				b := body.(map[string]interface{})
+				if v, ok := b["description"]; !ok || v != "" {
+					return nil, fmt.Errorf("expected empty description, got %v", b)
+				}
+				return json.Marshal(map[string]interface{}{"id": "123", "name": "Renamed"})
+			},
+		},
 		{
 			name:      "missing project_id",
 			args:      map[string]interface{}{"name": "x"},
@@ -302,7 +333,7 @@ func TestDeleteProjectHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			client := &MockAPI{DeleteFn: tt.mockDel}
-			handler := DeleteProjectHandler(client)
+			handler := DeleteProjectHandler(client, NewBackupStore(t.TempDir(), false))
 			result, err := handler(context.Background(), makeReq(tt.args))
 			if err != nil {
 				t.Fatalf("unexpected Go error: %v", err)
@@ -326,3 +357,258 @@ func TestDeleteProjectHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGetProjectStatsHandler(t *testing.T) {
+	okGet := func(_ context.Context, path string) ([]byte, error) {
+		switch path {
+		case "/projects/p1":
+			return json.Marshal(map[string]interface{}{"id": "p1", "name": "Work"})
+		case "/tasks?project_id=p1":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1", "priority": 4, "section_id": "s1", "assignee_id": "u1", "due": map[string]interface{}{"date": "2020-01-01"}},
+				{"id": "2", "priority": 1, "section_id": "s1"},
+				{"id": "3", "priority": 3, "assignee_id": "u9"},
+			})
+		case "/sections?project_id=p1":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "s1", "name": "Doing"},
+				{"id": "s2", "name": "Done"},
+			})
+		case "/projects/p1/collaborators":
+			return json.Marshal([]map[string]interface{}{{"id": "u1", "name": "Ada"}})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		mockGet   func(ctx context.Context, path string) ([]byte, error)
+		wantErr   bool
+		errSubstr string
+	}{
+		{
+			name:    "happy path",
+			args:    map[string]interface{}{"project_id": "p1"},
+			mockGet: okGet,
+		},
+		{
+			name:      "missing project_id",
+			args:      map[string]interface{}{},
+			wantErr:   true,
+			errSubstr: "project_id is required",
+		},
+		{
+			name: "project not found",
+			args: map[string]interface{}{"project_id": "p1"},
+			mockGet: func(_ context.Context, _ string) ([]byte, error) {
+				return nil, fmt.Errorf("resource not found")
+			},
+			wantErr:   true,
+			errSubstr: "failed to get project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockAPI{GetFn: tt.mockGet}
+			syncClient := &MockSyncAPI{
+				GetFn: func(_ context.Context, path string) ([]byte, error) {
+					return json.Marshal(map[string]interface{}{
+						"results": []map[string]interface{}{{"event_date": "2026-02-01T12:00:00Z"}},
+					})
+				},
+			}
+			handler := GetProjectStatsHandler(client, syncClient)
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				ActiveTasks    int            `json:"active_tasks"`
+				Overdue        int            `json:"overdue"`
+				ByPriority     map[string]int `json:"by_priority"`
+				BySection      map[string]int `json:"by_section"`
+				ByAssignee     map[string]int `json:"by_assignee"`
+				LastActivityAt string         `json:"last_activity_at"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if resp.ActiveTasks != 3 || resp.Overdue != 1 {
+				t.Errorf("active_tasks = %d, overdue = %d, want 3 and 1", resp.ActiveTasks, resp.Overdue)
+			}
+			if resp.ByPriority["p1"] != 1 || resp.ByPriority["p2"] != 1 || resp.ByPriority["p4"] != 1 {
+				t.Errorf("by_priority = %v", resp.ByPriority)
+			}
+			if resp.BySection["Doing"] != 2 || resp.BySection["Done"] != 0 || resp.BySection["(no section)"] != 1 {
+				t.Errorf("by_section = %v", resp.BySection)
+			}
+			if resp.ByAssignee["Ada"] != 1 || resp.ByAssignee["u9"] != 1 || resp.ByAssignee["(unassigned)"] != 1 {
+				t.Errorf("by_assignee = %v", resp.ByAssignee)
+			}
+			if resp.LastActivityAt != "2026-02-01T12:00:00Z" {
+				t.Errorf("last_activity_at = %q", resp.LastActivityAt)
+			}
+		})
+	}
+}
+
+func TestGetProjectStatsHandler_IncludeSubprojects(t *testing.T) {
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			switch path {
+			case "/projects/p1":
+				return json.Marshal(map[string]interface{}{"id": "p1", "name": "Work"})
+			case "/projects":
+				return json.Marshal([]map[string]interface{}{
+					{"id": "p1", "name": "Work"},
+					{"id": "p2", "name": "Clients", "parent_id": "p1"},
+					{"id": "p3", "name": "Acme", "parent_id": "p2"},
+					{"id": "p4", "name": "Home"},
+				})
+			case "/tasks":
+				return json.Marshal([]map[string]interface{}{
+					{"id": "1", "project_id": "p1", "priority": 1},
+					{"id": "2", "project_id": "p3", "priority": 4, "section_id": "s3"},
+					{"id": "3", "project_id": "p4", "priority": 1},
+				})
+			case "/sections":
+				return json.Marshal([]map[string]interface{}{
+					{"id": "s3", "name": "Backlog", "project_id": "p3"},
+					{"id": "s4", "name": "Chores", "project_id": "p4"},
+				})
+			case "/projects/p1/collaborators":
+				return json.Marshal([]map[string]interface{}{})
+			}
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		},
+	}
+	syncClient := &MockSyncAPI{
+		GetFn: func(_ context.Context, _ string) ([]byte, error) {
+			return json.Marshal(map[string]interface{}{"results": []map[string]interface{}{}})
+		},
+	}
+
+	handler := GetProjectStatsHandler(client, syncClient)
+	result, err := handler(context.Background(), makeReq(map[string]interface{}{"project_id": "p1", "include_subprojects": true}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	text := resultText(result)
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", text)
+	}
+	var resp struct {
+		ActiveTasks int            `json:"active_tasks"`
+		BySection   map[string]int `json:"by_section"`
+		ProjectIDs  []string       `json:"project_ids"`
+	}
+	if err := json.Unmarshal([]byte(text), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.ActiveTasks != 2 {
+		t.Errorf("active_tasks = %d, want 2", resp.ActiveTasks)
+	}
+	if _, ok := resp.BySection["Chores"]; ok {
+		t.Errorf("by_section includes section from unrelated project: %v", resp.BySection)
+	}
+	if len(resp.ProjectIDs) != 3 {
+		t.Errorf("project_ids = %v, want p1, p2, p3", resp.ProjectIDs)
+	}
+}
+
+func TestBulkSetViewStyleHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantErr   bool
+		errSubstr string
+		wantSent  int
+	}{
+		{
+			name:     "calendar view for several projects",
+			args:     map[string]interface{}{"view_style": "calendar", "project_ids": []interface{}{"p1", "p2", "p3"}},
+			wantSent: 3,
+		},
+		{
+			name:      "missing view_style",
+			args:      map[string]interface{}{"project_ids": []interface{}{"p1"}},
+			wantErr:   true,
+			errSubstr: "view_style is required",
+		},
+		{
+			name:      "invalid view_style",
+			args:      map[string]interface{}{"view_style": "timeline", "project_ids": []interface{}{"p1"}},
+			wantErr:   true,
+			errSubstr: "view_style must be one of",
+		},
+		{
+			name:      "missing project_ids",
+			args:      map[string]interface{}{"view_style": "board"},
+			wantErr:   true,
+			errSubstr: "project_ids array is required",
+		},
+		{
+			name:      "invalid project ID",
+			args:      map[string]interface{}{"view_style": "board", "project_ids": []interface{}{"../x"}},
+			wantErr:   true,
+			errSubstr: "contains invalid characters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []todoist.Command
+			syncClient := &MockSyncAPI{
+				BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+					sent = commands
+					status := make(map[string]interface{})
+					for _, c := range commands {
+						status[c.UUID] = "ok"
+					}
+					return &todoist.SyncResponse{SyncStatus: status}, nil
+				},
+			}
+			handler := BulkSetViewStyleHandler(syncClient)
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if len(sent) != tt.wantSent {
+				t.Fatalf("sent %d commands, want %d", len(sent), tt.wantSent)
+			}
+			for _, cmd := range sent {
+				if cmd.Type != "project_update" || cmd.Args["view_style"] != tt.args["view_style"] {
+					t.Errorf("unexpected command: %+v", cmd)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package tools
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// daysPerMonth is the average month length used to turn monthly recurrences
+// into an expected number of occurrences.
+const daysPerMonth = 30.44
+
+var (
+	everyNPattern      = regexp.MustCompile(`^every!? (\d+) (day|week|month|year)s?\b`)
+	everyUnitPattern   = regexp.MustCompile(`^every!? (other )?(day|morning|afternoon|evening|night|weekday|workday|week|month|year)\b`)
+	everyDaysPattern   = regexp.MustCompile(`^every!? ((?:(?:mon|tue|wed|thu|fri|sat|sun)[a-z]*(?:,\s*|\s+and\s+|\s+)?)+)`)
+	weekdayNamePattern = regexp.MustCompile(`(mon|tue|wed|thu|fri|sat|sun)[a-z]*`)
+)
+
+// recurrenceInterval estimates the average number of days between occurrences
+// of a recurring due string such as "every day", "every 2 weeks", or
+// "every mon, wed". The second return value is false for patterns it does not
+// understand, e.g. "every 3rd friday".
+func recurrenceInterval(dueString string) (float64, bool) {
+	s := strings.ToLower(strings.TrimSpace(dueString))
+	switch {
+	case strings.HasPrefix(s, "daily"):
+		return 1, true
+	case strings.HasPrefix(s, "weekly"):
+		return 7, true
+	case strings.HasPrefix(s, "monthly"):
+		return daysPerMonth, true
+	case strings.HasPrefix(s, "yearly"), strings.HasPrefix(s, "annually"):
+		return 365, true
+	}
+
+	unitDays := map[string]float64{"day": 1, "week": 7, "month": daysPerMonth, "year": 365}
+	if m := everyNPattern.FindStringSubmatch(s); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n < 1 {
+			return 0, false
+		}
+		return float64(n) * unitDays[m[2]], true
+	}
+	if m := everyUnitPattern.FindStringSubmatch(s); m != nil {
+		var days float64
+		switch m[2] {
+		case "weekday", "workday":
+			days = 7.0 / 5
+		case "day", "morning", "afternoon", "evening", "night":
+			days = 1
+		default:
+			days = unitDays[m[2]]
+		}
+		if m[1] != "" {
+			days *= 2
+		}
+		return days, true
+	}
+	if m := everyDaysPattern.FindStringSubmatch(s); m != nil {
+		seen := make(map[string]bool)
+		for _, day := range weekdayNamePattern.FindAllStringSubmatch(m[1], -1) {
+			seen[day[1]] = true
+		}
+		return 7 / float64(len(seen)), true
+	}
+	return 0, false
+}
+
+// recurrenceMaxGap returns the longest number of days a recurring due string
+// allows between two consecutive occurrences, e.g. 3 for "every weekday"
+// (Friday to Monday) and 4 for "every mon, thu".
+func recurrenceMaxGap(dueString string) (int, bool) {
+	s := strings.ToLower(strings.TrimSpace(dueString))
+	if m := everyUnitPattern.FindStringSubmatch(s); m != nil && m[1] == "" && (m[2] == "weekday" || m[2] == "workday") {
+		return 3, true
+	}
+	if m := everyDaysPattern.FindStringSubmatch(s); m != nil {
+		seen := make(map[string]bool)
+		for _, day := range weekdayNamePattern.FindAllStringSubmatch(m[1], -1) {
+			seen[day[1]] = true
+		}
+		var days []int
+		for i, name := range []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"} {
+			if seen[name] {
+				days = append(days, i)
+			}
+		}
+		gap := 0
+		for i, day := range days {
+			next := days[(i+1)%len(days)]
+			if next <= day {
+				next += 7
+			}
+			gap = max(gap, next-day)
+		}
+		return gap, true
+	}
+	interval, ok := recurrenceInterval(s)
+	return int(math.Ceil(interval)), ok
+}
+
+// expectedOccurrences estimates how many times a recurring task came due
+// between since and until. Occurrences before the task existed cannot have
+// been missed, so the window starts no earlier than its creation.
+func expectedOccurrences(task models.Task, since, until time.Time, interval float64) int {
+	start := since
+	if added, ok := taskAddedAt(task); ok && added.After(start) {
+		start = added
+	}
+	if !start.Before(until) {
+		return 0
+	}
+	return int(math.Floor(until.Sub(start).Hours() / 24 / interval))
+}
+
+// RecurringStatsHandler creates a handler that compares how often each
+// recurring task was completed in a window against how often it came due.
+func RecurringStatsHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		now := time.Now().UTC()
+		until, untilDate := now, now.Format("2006-01-02")
+		if v, ok := args["until"].(string); ok && v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return respond.Error("until must be in YYYY-MM-DD format"), nil
+			}
+			until, untilDate = t.AddDate(0, 0, 1), v
+			if until.After(now) {
+				until = now
+			}
+		}
+		since := until.AddDate(0, 0, -30)
+		if v, ok := args["since"].(string); ok && v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return respond.Error("since must be in YYYY-MM-DD format"), nil
+			}
+			since = t
+		}
+		if !since.Before(until) {
+			return respond.Error("since must be before until"), nil
+		}
+		if until.Sub(since) > maxCompletedRange {
+			return respond.Error("date range must not exceed 90 days"), nil
+		}
+
+		minRate := 0.5
+		if v, ok := args["min_rate"].(float64); ok {
+			if v < 0 || v > 1 {
+				return respond.Error("min_rate must be between 0 and 1"), nil
+			}
+			minRate = v
+		}
+
+		projectID, _ := args["project_id"].(string)
+		extra := url.Values{}
+		if projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			extra.Set("project_id", projectID)
+		}
+		filter := "recurring"
+		if f, ok := args["filter"].(string); ok && strings.TrimSpace(f) != "" {
+			filter = strings.TrimSpace(f)
+		}
+
+		tasks, err := resolveFilterTasks(ctx, client, filter)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		completed, truncated, err := fetchCompletedTasks(ctx, syncClient, since, until, extra)
+		if err != nil {
+			return respond.Errorf("failed to fetch completed tasks: %v", err), nil
+		}
+		completions := make(map[string]int)
+		lastCompleted := make(map[string]string)
+		for _, item := range completed {
+			id := completedTaskID(item)
+			completions[id]++
+			if item.CompletedAt > lastCompleted[id] {
+				lastCompleted[id] = item.CompletedAt
+			}
+		}
+
+		today := now.Format("2006-01-02")
+		stats := make([]map[string]interface{}, 0)
+		broken := 0
+		for _, task := range tasks {
+			if !taskIsRecurring(task) {
+				continue
+			}
+			if projectID != "" && task.ProjectID != projectID {
+				continue
+			}
+
+			id := task.ID
+			dueString := task.Due.String
+			entry := map[string]interface{}{
+				"task_id":        id,
+				"content":        task.Content,
+				"project_id":     task.ProjectID,
+				"recurrence":     dueString,
+				"next_due":       task.Due.Day(),
+				"completed":      completions[id],
+				"last_completed": nil,
+			}
+			if at, ok := lastCompleted[id]; ok {
+				entry["last_completed"] = at
+			}
+			// A recurring task still due in the past was neither completed nor
+			// rescheduled for its latest occurrence.
+			overdue := entry["next_due"].(string) != "" && entry["next_due"].(string) < today
+			entry["overdue"] = overdue
+
+			interval, known := recurrenceInterval(dueString)
+			if !known {
+				entry["expected"] = nil
+				entry["status"] = "unknown"
+				stats = append(stats, entry)
+				continue
+			}
+
+			expected := expectedOccurrences(task, since, until, interval)
+			skipped := expected - completions[id]
+			if skipped < 0 {
+				skipped = 0
+			}
+			rate := 1.0
+			if expected > 0 {
+				rate = math.Min(float64(completions[id])/float64(expected), 1)
+			}
+			entry["expected"] = expected
+			entry["skipped"] = skipped
+			entry["completion_rate"] = math.Round(rate*100) / 100
+
+			status := "on_track"
+			if expected > 0 && rate < minRate {
+				status = "broken"
+				broken++
+			} else if skipped > 0 || overdue {
+				status = "slipping"
+			}
+			entry["status"] = status
+			stats = append(stats, entry)
+		}
+
+		// Worst habits first; tasks with an unknown recurrence go last.
+		sort.SliceStable(stats, func(i, j int) bool {
+			a, aok := stats[i]["completion_rate"].(float64)
+			b, bok := stats[j]["completion_rate"].(float64)
+			if aok != bok {
+				return aok
+			}
+			return a < b
+		})
+
+		response := respond.Envelope{
+			"since":    since.Format("2006-01-02"),
+			"until":    untilDate,
+			"min_rate": minRate,
+			"count":    len(stats),
+			"broken":   broken,
+			"tasks":    stats,
+		}
+		warnCompletedTruncated(response, truncated)
+
+		return respond.JSON(response), nil
+	}
+}
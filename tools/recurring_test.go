@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRecurrenceInterval(t *testing.T) {
+	tests := []struct {
+		due    string
+		want   float64
+		wantOK bool
+	}{
+		{"every day", 1, true},
+		{"Every morning", 1, true},
+		{"daily at 9am", 1, true},
+		{"every! 3 days", 3, true},
+		{"every other week", 14, true},
+		{"every 2 weeks", 14, true},
+		{"every weekday", 1.4, true},
+		{"every mon, wed and fri", 7.0 / 3, true},
+		{"every month", daysPerMonth, true},
+		{"every 3rd friday", 0, false},
+		{"tomorrow", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.due, func(t *testing.T) {
+			got, ok := recurrenceInterval(tt.due)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("recurrenceInterval(%q) = %v, %v; want %v, %v", tt.due, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRecurringStatsHandler(t *testing.T) {
+	recurring := func(s, date string) map[string]interface{} {
+		return map[string]interface{}{"string": s, "date": date, "is_recurring": true}
+	}
+	var filter string
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		u, _ := url.Parse(path)
+		filter = u.Query().Get("filter")
+		return json.Marshal([]map[string]interface{}{
+			{"id": "t1", "content": "Stretch", "project_id": "p1", "due": recurring("every day", "2099-01-01"), "added_at": "2025-06-01T00:00:00Z"},
+			{"id": "t2", "content": "Weekly review", "project_id": "p1", "due": recurring("every week", "2026-01-05"), "added_at": "2025-06-01T00:00:00Z"},
+			{"id": "t3", "content": "Team lunch", "project_id": "p1", "due": recurring("every 3rd friday", "2099-01-01")},
+			{"id": "t4", "content": "One-off", "project_id": "p1", "due": map[string]interface{}{"date": "2026-01-10", "is_recurring": false}},
+			{"id": "t5", "content": "Gym", "project_id": "p2", "due": recurring("every mon, thu", "2099-01-01"), "added_at": "2026-01-15T00:00:00Z"},
+		})
+	}}
+
+	var completed []map[string]interface{}
+	for i := 0; i < 27; i++ {
+		completed = append(completed, map[string]interface{}{"id": fmt.Sprintf("c%d", i), "task_id": "t1", "completed_at": fmt.Sprintf("2026-01-%02dT07:00:00Z", i+1)})
+	}
+	completed = append(completed, map[string]interface{}{"id": "c-w", "task_id": "t2", "completed_at": "2026-01-04T18:00:00Z"})
+	for _, day := range []string{"15", "19", "22", "26"} {
+		completed = append(completed, map[string]interface{}{"id": "c-g" + day, "task_id": "t5", "completed_at": "2026-01-" + day + "T17:00:00Z"})
+	}
+	var lastQuery url.Values
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		u, _ := url.Parse(path)
+		lastQuery = u.Query()
+		return json.Marshal(map[string]interface{}{"items": completed, "next_cursor": nil})
+	}}
+
+	tests := []struct {
+		name       string
+		args       map[string]interface{}
+		wantIDs    []string
+		wantStatus map[string]string
+		wantFilter string
+		wantErr    string
+	}{
+		{
+			name:       "worst first with unknown recurrences last",
+			args:       map[string]interface{}{"since": "2026-01-01", "until": "2026-01-28"},
+			wantIDs:    []string{"t2", "t1", "t5", "t3"},
+			wantStatus: map[string]string{"t1": "slipping", "t2": "broken", "t3": "unknown", "t5": "on_track"},
+			wantFilter: "recurring",
+		},
+		{
+			name:       "project and custom filter",
+			args:       map[string]interface{}{"since": "2026-01-01", "until": "2026-01-28", "project_id": "p2", "filter": "@habit"},
+			wantIDs:    []string{"t5"},
+			wantStatus: map[string]string{"t5": "on_track"},
+			wantFilter: "@habit",
+		},
+		{
+			name:    "range too wide",
+			args:    map[string]interface{}{"since": "2025-01-01", "until": "2026-01-28"},
+			wantErr: "must not exceed 90 days",
+		},
+		{
+			name:    "bad min_rate",
+			args:    map[string]interface{}{"min_rate": float64(2)},
+			wantErr: "min_rate must be between 0 and 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RecurringStatsHandler(client, syncClient)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if filter != tt.wantFilter {
+				t.Errorf("filter = %q, want %q", filter, tt.wantFilter)
+			}
+			if p, ok := tt.args["project_id"]; ok && lastQuery.Get("project_id") != p {
+				t.Errorf("completed query project_id = %q, want %q", lastQuery.Get("project_id"), p)
+			}
+
+			var resp struct {
+				Tasks []map[string]interface{} `json:"tasks"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			var ids []string
+			for _, task := range resp.Tasks {
+				id := task["task_id"].(string)
+				ids = append(ids, id)
+				if task["status"] != tt.wantStatus[id] {
+					t.Errorf("%s status = %v, want %s", id, task["status"], tt.wantStatus[id])
+				}
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("task order = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
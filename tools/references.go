@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// referencePrefix starts each external reference line in a task description
+// footer, e.g. "ref:jira=PROJ-123". Lines are kept at the end of the
+// description so edits to the task's content or body leave them intact.
+const referencePrefix = "ref:"
+
+// referenceSystemPattern restricts system names so footer lines parse unambiguously.
+var referenceSystemPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,31}$`)
+
+// parseReferences splits a description into its body and reference footer.
+func parseReferences(description string) (string, map[string]string) {
+	refs := make(map[string]string)
+	lines := strings.Split(description, "\n")
+	end := len(lines)
+	for end > 0 {
+		line := strings.TrimSpace(lines[end-1])
+		if line == "" {
+			end--
+			continue
+		}
+		system, value, ok := strings.Cut(strings.TrimPrefix(line, referencePrefix), "=")
+		if !strings.HasPrefix(line, referencePrefix) || !ok || !referenceSystemPattern.MatchString(system) {
+			break
+		}
+		if _, seen := refs[system]; !seen {
+			refs[system] = value
+		}
+		end--
+	}
+	return strings.TrimRight(strings.Join(lines[:end], "\n"), "\n "), refs
+}
+
+// formatReferences appends a reference footer to a description body.
+func formatReferences(body string, refs map[string]string) string {
+	systems := make([]string, 0, len(refs))
+	for system := range refs {
+		systems = append(systems, system)
+	}
+	sort.Strings(systems)
+
+	var b strings.Builder
+	b.WriteString(body)
+	for i, system := range systems {
+		if i == 0 && body != "" {
+			b.WriteString("\n\n")
+		} else if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(referencePrefix + system + "=" + refs[system])
+	}
+	return b.String()
+}
+
+// SetTaskReferenceHandler creates a handler for attaching an external system ID to a task.
+func SetTaskReferenceHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		taskID, ok := args["task_id"].(string)
+		if !ok || taskID == "" {
+			return respond.Error("task_id is required"), nil
+		}
+		if err := ValidateID(taskID, "task_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		system, _ := args["system"].(string)
+		system = strings.ToLower(strings.TrimSpace(system))
+		if !referenceSystemPattern.MatchString(system) {
+			return respond.Error("system must be 1-32 lowercase letters, digits, '.', '_' or '-' (e.g. 'jira', 'email', 'salesforce')"), nil
+		}
+		reference, _ := args["reference"].(string)
+		reference = strings.TrimSpace(reference)
+		if strings.ContainsAny(reference, "\r\n") {
+			return respond.Error("reference must be a single line"), nil
+		}
+
+		path := fmt.Sprintf("/tasks/%s", taskID)
+		respBody, err := client.Get(ctx, path)
+		if err != nil {
+			return respond.Errorf("failed to get task: %v", err), nil
+		}
+		var task models.Task
+		if err := json.Unmarshal(respBody, &task); err != nil {
+			return respond.Errorf("failed to parse task: %v", err), nil
+		}
+
+		body, refs := parseReferences(task.Description)
+		if reference == "" {
+			delete(refs, system)
+		} else {
+			refs[system] = reference
+		}
+
+		if _, err := client.Post(ctx, path, map[string]interface{}{"description": formatReferences(body, refs)}); err != nil {
+			return respond.Errorf("failed to update task: %v", err), nil
+		}
+
+		response := map[string]interface{}{
+			"task_id":    taskID,
+			"references": refs,
+		}
+		if reference == "" {
+			response["message"] = fmt.Sprintf("Removed %s reference", system)
+		} else {
+			response["message"] = fmt.Sprintf("Set %s reference to %s", system, reference)
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// FindTaskByReferenceHandler creates a handler for looking up active tasks by external system ID.
+func FindTaskByReferenceHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		system, _ := args["system"].(string)
+		system = strings.ToLower(strings.TrimSpace(system))
+		if !referenceSystemPattern.MatchString(system) {
+			return respond.Error("system must be 1-32 lowercase letters, digits, '.', '_' or '-'"), nil
+		}
+		reference, _ := args["reference"].(string)
+		reference = strings.TrimSpace(reference)
+		if reference == "" {
+			return respond.Error("reference is required"), nil
+		}
+
+		path := "/tasks"
+		if projectID, ok := args["project_id"].(string); ok && projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			params := url.Values{}
+			params.Set("project_id", projectID)
+			path += "?" + params.Encode()
+		}
+
+		respBody, err := client.Get(ctx, path)
+		if err != nil {
+			return respond.Errorf("failed to fetch tasks: %v", err), nil
+		}
+		var tasks []models.Task
+		if err := json.Unmarshal(respBody, &tasks); err != nil {
+			return respond.Errorf("failed to parse tasks: %v", err), nil
+		}
+
+		matches := make([]models.Task, 0)
+		for _, task := range tasks {
+			if _, refs := parseReferences(task.Description); refs[system] == reference {
+				matches = append(matches, task)
+			}
+		}
+
+		response := map[string]interface{}{
+			"system":    system,
+			"reference": reference,
+			"count":     len(matches),
+			"tasks":     matches,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseReferences(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		wantBody    string
+		wantRefs    map[string]string
+	}{
+		{name: "empty", description: "", wantBody: "", wantRefs: map[string]string{}},
+		{name: "body only", description: "Call back\nref: none", wantBody: "Call back\nref: none", wantRefs: map[string]string{}},
+		{
+			name:        "body and footer",
+			description: "Customer asked for a quote.\n\nref:crm=0065g00000XyZ\nref:jira=SALES-12\n",
+			wantBody:    "Customer asked for a quote.",
+			wantRefs:    map[string]string{"crm": "0065g00000XyZ", "jira": "SALES-12"},
+		},
+		{
+			name:        "footer only",
+			description: "ref:email=<abc@mail.example.com>",
+			wantBody:    "",
+			wantRefs:    map[string]string{"email": "<abc@mail.example.com>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, refs := parseReferences(tt.description)
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+			if len(refs) != len(tt.wantRefs) {
+				t.Fatalf("refs = %v, want %v", refs, tt.wantRefs)
+			}
+			for k, v := range tt.wantRefs {
+				if refs[k] != v {
+					t.Errorf("refs[%s] = %q, want %q", k, refs[k], v)
+				}
+			}
+
+			// Formatting and reparsing must round-trip.
+			body2, refs2 := parseReferences(formatReferences(body, refs))
+			if body2 != body || len(refs2) != len(refs) {
+				t.Errorf("round trip = %q %v", body2, refs2)
+			}
+		})
+	}
+}
+
+func TestSetTaskReferenceHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantErr   bool
+		errSubstr string
+		wantDesc  string
+	}{
+		{
+			name:     "add reference",
+			args:     map[string]interface{}{"task_id": "t1", "system": "Jira", "reference": "PROJ-9"},
+			wantDesc: "Notes\n\nref:crm=42\nref:jira=PROJ-9",
+		},
+		{
+			name:     "remove reference",
+			args:     map[string]interface{}{"task_id": "t1", "system": "crm", "reference": ""},
+			wantDesc: "Notes",
+		},
+		{
+			name:      "invalid system",
+			args:      map[string]interface{}{"task_id": "t1", "system": "my system", "reference": "x"},
+			wantErr:   true,
+			errSubstr: "system must be",
+		},
+		{
+			name:      "multiline reference",
+			args:      map[string]interface{}{"task_id": "t1", "system": "jira", "reference": "A\nB"},
+			wantErr:   true,
+			errSubstr: "single line",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotDesc string
+			client := &MockAPI{
+				GetFn: func(_ context.Context, _ string) ([]byte, error) {
+					return json.Marshal(map[string]interface{}{"id": "t1", "description": "Notes\n\nref:crm=42"})
+				},
+				PostFn: func(_ context.Context, _ string, body interface{}) ([]byte, error) {
+					gotDesc = body.(map[string]interface{})["description"].(string)
+					return []byte(`{}`), nil
+				},
+			}
+
+			result, err := SetTaskReferenceHandler(client)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if gotDesc != tt.wantDesc {
+				t.Errorf("description = %q, want %q", gotDesc, tt.wantDesc)
+			}
+		})
+	}
+}
+
+func TestFindTaskByReferenceHandler(t *testing.T) {
+	client := &MockAPI{
+		GetFn: func(_ context.Context, _ string) ([]byte, error) {
+			return json.Marshal([]map[string]interface{}{
+				{"id": "t1", "description": "ref:jira=PROJ-1"},
+				{"id": "t2", "description": "Mentions ref:jira=PROJ-2 inline\nbut has no footer"},
+				{"id": "t3", "description": "Body\n\nref:jira=PROJ-2"},
+			})
+		},
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantErr   bool
+		errSubstr string
+		wantIDs   string
+	}{
+		{name: "match", args: map[string]interface{}{"system": "jira", "reference": "PROJ-2"}, wantIDs: "t3"},
+		{name: "no match", args: map[string]interface{}{"system": "crm", "reference": "PROJ-2"}, wantIDs: ""},
+		{name: "missing reference", args: map[string]interface{}{"system": "jira"}, wantErr: true, errSubstr: "reference is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FindTaskByReferenceHandler(client)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			var resp struct {
+				Tasks []map[string]interface{} `json:"tasks"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			var ids []string
+			for _, task := range resp.Tasks {
+				ids = append(ids, task["id"].(string))
+			}
+			if strings.Join(ids, ",") != tt.wantIDs {
+				t.Errorf("ids = %v, want %s", ids, tt.wantIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// Reminder types accepted by add_reminder, as the Sync API names them.
+const (
+	reminderRelative = "relative"
+	reminderAbsolute = "absolute"
+	reminderLocation = "location"
+)
+
+// floatingDateTime is the Sync API's date-time without a time zone, read in
+// the user's time zone.
+const floatingDateTime = "2006-01-02T15:04:05"
+
+// reminderAddCommand builds the reminder_add command for the add_reminder
+// arguments:
+//   - relative: minute_offset before the task's due time
+//   - absolute: due_datetime (RFC 3339, or floating in the user's time zone) or due_string
+//   - location: name, loc_lat, loc_long, and optionally loc_trigger and radius
+func reminderAddCommand(args map[string]interface{}) (todoist.Command, error) {
+	taskID, _ := args["task_id"].(string)
+	if err := ValidateID(taskID, "task_id"); err != nil {
+		return todoist.Command{}, err
+	}
+	reminderType, _ := args["type"].(string)
+	cmdArgs := map[string]interface{}{"item_id": taskID, "type": reminderType}
+
+	switch reminderType {
+	case reminderRelative:
+		offset, ok := args["minute_offset"].(float64)
+		if !ok || offset < 0 || offset != float64(int(offset)) {
+			return todoist.Command{}, fmt.Errorf("relative reminders need minute_offset, a whole number of minutes before the due time (0 for at the due time)")
+		}
+		cmdArgs["minute_offset"] = int(offset)
+
+	case reminderAbsolute:
+		due, err := reminderDue(args)
+		if err != nil {
+			return todoist.Command{}, err
+		}
+		cmdArgs["due"] = due
+
+	case reminderLocation:
+		name, _ := args["name"].(string)
+		lat, latOK := args["loc_lat"].(float64)
+		long, longOK := args["loc_long"].(float64)
+		if name == "" || !latOK || !longOK {
+			return todoist.Command{}, fmt.Errorf("location reminders need name, loc_lat, and loc_long")
+		}
+		if lat < -90 || lat > 90 || long < -180 || long > 180 {
+			return todoist.Command{}, fmt.Errorf("loc_lat must be between -90 and 90 and loc_long between -180 and 180")
+		}
+		trigger, _ := args["loc_trigger"].(string)
+		if trigger == "" {
+			trigger = "on_enter"
+		}
+		if trigger != "on_enter" && trigger != "on_leave" {
+			return todoist.Command{}, fmt.Errorf("loc_trigger must be on_enter or on_leave")
+		}
+		cmdArgs["name"] = name
+		// The Sync API takes coordinates as strings.
+		cmdArgs["loc_lat"] = strconv.FormatFloat(lat, 'f', -1, 64)
+		cmdArgs["loc_long"] = strconv.FormatFloat(long, 'f', -1, 64)
+		cmdArgs["loc_trigger"] = trigger
+		if radius, ok := args["radius"].(float64); ok {
+			if radius < 1 || radius != float64(int(radius)) {
+				return todoist.Command{}, fmt.Errorf("radius must be a positive whole number of meters")
+			}
+			cmdArgs["radius"] = int(radius)
+		}
+
+	default:
+		return todoist.Command{}, fmt.Errorf("type must be one of relative, absolute, location")
+	}
+
+	return todoist.Command{
+		Type:   "reminder_add",
+		UUID:   todoist.GenerateUUID(),
+		TempID: todoist.GenerateTempID(),
+		Args:   cmdArgs,
+	}, nil
+}
+
+// reminderDue reads the time of an absolute reminder as a Sync API due
+// object. RFC 3339 times are sent in UTC; floating ones are kept as is.
+func reminderDue(args map[string]interface{}) (map[string]interface{}, error) {
+	dueDatetime, _ := args["due_datetime"].(string)
+	dueString, _ := args["due_string"].(string)
+	switch {
+	case dueDatetime != "" && dueString != "":
+		return nil, fmt.Errorf("absolute reminders take due_datetime or due_string, not both")
+	case dueString != "":
+		return map[string]interface{}{"string": dueString}, nil
+	case dueDatetime == "":
+		return nil, fmt.Errorf("absolute reminders need due_datetime or due_string")
+	}
+	if t, err := time.Parse(time.RFC3339, dueDatetime); err == nil {
+		return map[string]interface{}{"date": t.UTC().Format(floatingDateTime + "Z")}, nil
+	}
+	if _, err := time.Parse(floatingDateTime, dueDatetime); err == nil {
+		return map[string]interface{}{"date": dueDatetime}, nil
+	}
+	return nil, fmt.Errorf("due_datetime must be an RFC 3339 time or YYYY-MM-DDTHH:MM:SS in your time zone")
+}
+
+// reminderDeleteCommand builds the reminder_delete command for a reminder.
+func reminderDeleteCommand(reminderID string) todoist.Command {
+	return todoist.Command{
+		Type: "reminder_delete",
+		UUID: todoist.GenerateUUID(),
+		Args: map[string]interface{}{"id": reminderID},
+	}
+}
+
+// ListRemindersHandler creates a handler for listing relative, absolute, and
+// location reminders, read through the Sync API since the REST API does not
+// expose them.
+func ListRemindersHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		taskID, _ := args["task_id"].(string)
+		if taskID != "" {
+			if err := ValidateID(taskID, "task_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+		reminderType, _ := args["type"].(string)
+		if reminderType != "" && reminderType != reminderRelative && reminderType != reminderAbsolute && reminderType != reminderLocation {
+			return respond.Error("type must be one of relative, absolute, location"), nil
+		}
+
+		// Location reminders are a resource of their own.
+		resources, err := fetchSyncResources(ctx, syncClient, "reminders", "reminders_location")
+		if err != nil {
+			return respond.Errorf("failed to fetch reminders: %v", err), nil
+		}
+		var all []map[string]interface{}
+		for _, resource := range []string{"reminders", "reminders_location"} {
+			objects, err := decodeSyncObjects(resources[resource])
+			if err != nil {
+				return respond.Errorf("failed to parse %s: %v", resource, err), nil
+			}
+			all = append(all, objects...)
+		}
+
+		reminders := make([]map[string]interface{}, 0, len(all))
+		for _, reminder := range all {
+			if taskID != "" && fmt.Sprint(reminder["item_id"]) != taskID {
+				continue
+			}
+			if reminderType != "" && reminder["type"] != reminderType {
+				continue
+			}
+			reminders = append(reminders, reminder)
+		}
+		sort.Slice(reminders, func(i, j int) bool {
+			taskI, taskJ := fmt.Sprint(reminders[i]["item_id"]), fmt.Sprint(reminders[j]["item_id"])
+			if taskI != taskJ {
+				return taskI < taskJ
+			}
+			return fmt.Sprint(reminders[i]["id"]) < fmt.Sprint(reminders[j]["id"])
+		})
+
+		return respond.JSON(respond.List("reminders", reminders)), nil
+	}
+}
+
+// AddReminderHandler creates a handler for adding a relative, absolute, or
+// location reminder to a task.
+func AddReminderHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cmd, err := reminderAddCommand(req.GetArguments())
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		syncResp, err := syncClient.BatchCommands(ctx, []todoist.Command{cmd})
+		if err != nil {
+			return respond.Errorf("failed to add reminder: %v", err), nil
+		}
+		if status, ok := syncResp.SyncStatus[cmd.UUID].(string); !ok || status != "ok" {
+			return respond.Errorf("failed to add reminder: %v", syncResp.SyncStatus[cmd.UUID]), nil
+		}
+
+		reminder := map[string]interface{}{"id": syncResp.TempIDMapping[cmd.TempID]}
+		for key, value := range cmd.Args {
+			reminder[key] = value
+		}
+		response := map[string]interface{}{
+			"success":  true,
+			"reminder": reminder,
+			"message":  fmt.Sprintf("Reminder (%s) added to task %s", cmd.Args["type"], cmd.Args["item_id"]),
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// DeleteReminderHandler creates a handler for deleting a reminder.
+func DeleteReminderHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		reminderID, _ := req.GetArguments()["reminder_id"].(string)
+		if err := ValidateID(reminderID, "reminder_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		cmd := reminderDeleteCommand(reminderID)
+		syncResp, err := syncClient.BatchCommands(ctx, []todoist.Command{cmd})
+		if err != nil {
+			return respond.Errorf("failed to delete reminder: %v", err), nil
+		}
+		if status, ok := syncResp.SyncStatus[cmd.UUID].(string); !ok || status != "ok" {
+			return respond.Errorf("failed to delete reminder: %v", syncResp.SyncStatus[cmd.UUID]), nil
+		}
+
+		response := map[string]interface{}{
+			"success":     true,
+			"reminder_id": reminderID,
+			"message":     "Reminder deleted",
+		}
+
+		return respond.JSON(response), nil
+	}
+}
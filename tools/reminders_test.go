@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestReminderAddCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantArgs  map[string]interface{}
+		errSubstr string
+	}{
+		{
+			name:     "relative",
+			args:     map[string]interface{}{"task_id": "t1", "type": "relative", "minute_offset": float64(30)},
+			wantArgs: map[string]interface{}{"item_id": "t1", "type": "relative", "minute_offset": 30},
+		},
+		{
+			name:     "absolute RFC 3339 is sent in UTC",
+			args:     map[string]interface{}{"task_id": "t1", "type": "absolute", "due_datetime": "2026-03-02T09:00:00+01:00"},
+			wantArgs: map[string]interface{}{"item_id": "t1", "type": "absolute", "due": map[string]interface{}{"date": "2026-03-02T08:00:00Z"}},
+		},
+		{
+			name:     "absolute floating",
+			args:     map[string]interface{}{"task_id": "t1", "type": "absolute", "due_datetime": "2026-03-02T09:00:00"},
+			wantArgs: map[string]interface{}{"item_id": "t1", "type": "absolute", "due": map[string]interface{}{"date": "2026-03-02T09:00:00"}},
+		},
+		{
+			name:     "absolute natural language",
+			args:     map[string]interface{}{"task_id": "t1", "type": "absolute", "due_string": "tomorrow at 9am"},
+			wantArgs: map[string]interface{}{"item_id": "t1", "type": "absolute", "due": map[string]interface{}{"string": "tomorrow at 9am"}},
+		},
+		{
+			name: "location",
+			args: map[string]interface{}{"task_id": "t1", "type": "location", "name": "Office",
+				"loc_lat": 52.52, "loc_long": 13.405, "loc_trigger": "on_leave", "radius": float64(150)},
+			wantArgs: map[string]interface{}{"item_id": "t1", "type": "location", "name": "Office",
+				"loc_lat": "52.52", "loc_long": "13.405", "loc_trigger": "on_leave", "radius": 150},
+		},
+		{
+			name: "location defaults to on_enter",
+			args: map[string]interface{}{"task_id": "t1", "type": "location", "name": "Home", "loc_lat": float64(1), "loc_long": float64(2)},
+			wantArgs: map[string]interface{}{"item_id": "t1", "type": "location", "name": "Home",
+				"loc_lat": "1", "loc_long": "2", "loc_trigger": "on_enter"},
+		},
+		{
+			name:      "missing task_id",
+			args:      map[string]interface{}{"type": "relative", "minute_offset": float64(0)},
+			errSubstr: "task_id",
+		},
+		{
+			name:      "unknown type",
+			args:      map[string]interface{}{"task_id": "t1", "type": "weekly"},
+			errSubstr: "type must be one of",
+		},
+		{
+			name:      "relative without offset",
+			args:      map[string]interface{}{"task_id": "t1", "type": "relative"},
+			errSubstr: "minute_offset",
+		},
+		{
+			name:      "absolute with both times",
+			args:      map[string]interface{}{"task_id": "t1", "type": "absolute", "due_datetime": "2026-03-02T09:00:00", "due_string": "tomorrow"},
+			errSubstr: "not both",
+		},
+		{
+			name:      "absolute with a date only",
+			args:      map[string]interface{}{"task_id": "t1", "type": "absolute", "due_datetime": "2026-03-02"},
+			errSubstr: "due_datetime must be",
+		},
+		{
+			name:      "location without coordinates",
+			args:      map[string]interface{}{"task_id": "t1", "type": "location", "name": "Office"},
+			errSubstr: "loc_lat, and loc_long",
+		},
+		{
+			name:      "location with bad trigger",
+			args:      map[string]interface{}{"task_id": "t1", "type": "location", "name": "Office", "loc_lat": float64(1), "loc_long": float64(2), "loc_trigger": "nearby"},
+			errSubstr: "loc_trigger",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := reminderAddCommand(tt.args)
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("err = %v, want substring %q", err, tt.errSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cmd.Type != "reminder_add" || cmd.UUID == "" || cmd.TempID == "" {
+				t.Errorf("command = %+v, want reminder_add with a UUID and temp_id", cmd)
+			}
+			if got, want := fmt.Sprint(cmd.Args), fmt.Sprint(tt.wantArgs); got != want {
+				t.Errorf("args = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestListRemindersHandler(t *testing.T) {
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if !strings.HasPrefix(path, "/sync?") || !strings.Contains(path, "reminders_location") {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return json.Marshal(map[string]interface{}{
+			"reminders": []map[string]interface{}{
+				{"id": "r1", "item_id": "t2", "type": "relative", "minute_offset": 30},
+				{"id": "r3", "item_id": "t1", "type": "absolute", "due": map[string]interface{}{"date": "2026-03-02T08:00:00Z"}},
+				{"id": "r4", "item_id": "t1", "type": "relative", "minute_offset": 0, "is_deleted": true},
+			},
+			"reminders_location": []map[string]interface{}{
+				{"id": "r2", "item_id": "t1", "type": "location", "name": "Office", "loc_lat": "52.52", "loc_long": "13.405", "loc_trigger": "on_enter"},
+			},
+		})
+	}}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantIDs string
+	}{
+		{name: "all, by task", args: nil, wantIDs: "r2,r3,r1"},
+		{name: "one task", args: map[string]interface{}{"task_id": "t1"}, wantIDs: "r2,r3"},
+		{name: "one type", args: map[string]interface{}{"type": "relative"}, wantIDs: "r1"},
+		{name: "location", args: map[string]interface{}{"type": "location"}, wantIDs: "r2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ListRemindersHandler(syncClient)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", resultText(result))
+			}
+			var resp struct {
+				Count     int `json:"count"`
+				Reminders []struct {
+					ID string `json:"id"`
+				} `json:"reminders"`
+			}
+			if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			ids := make([]string, 0, len(resp.Reminders))
+			for _, r := range resp.Reminders {
+				ids = append(ids, r.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.wantIDs {
+				t.Errorf("reminders = %s, want %s", got, tt.wantIDs)
+			}
+			if resp.Count != len(ids) {
+				t.Errorf("count = %d, want %d", resp.Count, len(ids))
+			}
+		})
+	}
+
+	t.Run("invalid type", func(t *testing.T) {
+		result, _ := ListRemindersHandler(syncClient)(context.Background(), makeReq(map[string]interface{}{"type": "weekly"}))
+		if !result.IsError {
+			t.Fatal("expected tool error")
+		}
+	})
+}
+
+func TestAddReminderHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    interface{}
+		wantErr   bool
+		errSubstr string
+	}{
+		{name: "added", status: "ok"},
+		{
+			name:      "rejected",
+			status:    map[string]interface{}{"error": "Premium only feature"},
+			wantErr:   true,
+			errSubstr: "Premium only feature",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+				if len(commands) != 1 || commands[0].Type != "reminder_add" {
+					t.Fatalf("commands = %+v, want one reminder_add", commands)
+				}
+				return &todoist.SyncResponse{
+					SyncStatus:    map[string]interface{}{commands[0].UUID: tt.status},
+					TempIDMapping: map[string]string{commands[0].TempID: "r9"},
+				}, nil
+			}}
+			args := map[string]interface{}{"task_id": "t1", "type": "relative", "minute_offset": float64(15)}
+			result, err := AddReminderHandler(syncClient)(context.Background(), makeReq(args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError || !strings.Contains(text, tt.errSubstr) {
+					t.Fatalf("result = %q, want error containing %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				Reminder map[string]interface{} `json:"reminder"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if resp.Reminder["id"] != "r9" || resp.Reminder["item_id"] != "t1" || resp.Reminder["minute_offset"] != float64(15) {
+				t.Errorf("reminder = %v, want id r9 for t1 at 15 minutes", resp.Reminder)
+			}
+		})
+	}
+
+	t.Run("invalid args send nothing", func(t *testing.T) {
+		syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, _ []todoist.Command) (*todoist.SyncResponse, error) {
+			t.Fatal("nothing should be sent")
+			return nil, nil
+		}}
+		result, _ := AddReminderHandler(syncClient)(context.Background(), makeReq(map[string]interface{}{"task_id": "t1", "type": "absolute"}))
+		if !result.IsError {
+			t.Fatal("expected tool error")
+		}
+	})
+}
+
+func TestDeleteReminderHandler(t *testing.T) {
+	var sent []todoist.Command
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		sent = commands
+		return &todoist.SyncResponse{SyncStatus: map[string]interface{}{commands[0].UUID: "ok"}}, nil
+	}}
+
+	result, err := DeleteReminderHandler(syncClient)(context.Background(), makeReq(map[string]interface{}{"reminder_id": "r1"}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+	if len(sent) != 1 || sent[0].Type != "reminder_delete" || sent[0].Args["id"] != "r1" {
+		t.Errorf("commands = %+v, want reminder_delete of r1", sent)
+	}
+
+	result, _ = DeleteReminderHandler(syncClient)(context.Background(), makeReq(map[string]interface{}{}))
+	if !result.IsError {
+		t.Error("expected tool error without reminder_id")
+	}
+}
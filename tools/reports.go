@@ -0,0 +1,362 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// maxCompletedPages caps pagination through the completed tasks endpoint so a
+// single report cannot exhaust the rate limit window.
+const maxCompletedPages = 20
+
+// maxCompletedRange is the widest completion date range the API v1 completed
+// tasks endpoint accepts in a single query.
+const maxCompletedRange = 90 * 24 * time.Hour
+
+// fetchCompletedTasks pages through the API v1 completed tasks endpoint for the
+// given completion date range. Extra query parameters such as project_id or
+// filter_query are passed through unchanged. truncated reports that more
+// completions remained after maxCompletedPages pages.
+func fetchCompletedTasks(ctx context.Context, syncClient todoist.SyncAPI, since, until time.Time, extra url.Values) (items []models.Task, truncated bool, err error) {
+	cursor := ""
+
+	for page := 0; ; page++ {
+		if page == maxCompletedPages {
+			return items, true, nil
+		}
+		params := url.Values{}
+		for key, values := range extra {
+			params[key] = values
+		}
+		params.Set("since", since.UTC().Format(time.RFC3339))
+		params.Set("until", until.UTC().Format(time.RFC3339))
+		params.Set("limit", "200")
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		respBody, err := syncClient.Get(ctx, "/tasks/completed/by_completion_date?"+params.Encode())
+		if err != nil {
+			return nil, false, err
+		}
+
+		var resp struct {
+			Items      []models.Task `json:"items"`
+			NextCursor *string       `json:"next_cursor"`
+		}
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return nil, false, fmt.Errorf("failed to parse completed tasks: %w", err)
+		}
+
+		items = append(items, resp.Items...)
+
+		if resp.NextCursor == nil || *resp.NextCursor == "" {
+			return items, false, nil
+		}
+		cursor = *resp.NextCursor
+	}
+}
+
+// warnCompletedTruncated warns that the completed tasks behind response stop
+// at the pagination cap, so totals computed from them are lower bounds.
+func warnCompletedTruncated(response respond.Envelope, truncated bool) {
+	if truncated {
+		response.Warn("completed tasks were truncated at %d; totals may be incomplete", maxCompletedPages*200)
+	}
+}
+
+// MonthlyReportHandler creates a handler for summarizing a month's completed tasks.
+func MonthlyReportHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		month, _ := args["month"].(string)
+		if month != "" {
+			if _, err := time.Parse("2006-01", month); err != nil {
+				return respond.Error("month must be in YYYY-MM format"), nil
+			}
+		}
+
+		extra := url.Values{}
+		if p, ok := args["project_id"].(string); ok && p != "" {
+			if err := ValidateID(p, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			extra.Set("project_id", p)
+		}
+
+		// The month runs from midnight to midnight on the user's calendar.
+		loc, err := userLocation(ctx, syncClient)
+		if err != nil {
+			return respond.Errorf("failed to resolve user time zone: %v", err), nil
+		}
+		if month == "" {
+			month = time.Now().In(loc).Format("2006-01")
+		}
+		start, err := time.ParseInLocation("2006-01", month, loc)
+		if err != nil {
+			return respond.Error("month must be in YYYY-MM format"), nil
+		}
+		end := start.AddDate(0, 1, 0)
+
+		items, truncated, err := fetchCompletedTasks(ctx, syncClient, start, end, extra)
+		if err != nil {
+			return respond.Errorf("failed to fetch completed tasks: %v", err), nil
+		}
+
+		projectMap := make(map[string]string)
+		if projectsBody, err := client.Get(ctx, "/projects"); err == nil {
+			var projects []models.Project
+			if json.Unmarshal(projectsBody, &projects) == nil {
+				for _, proj := range projects {
+					projectMap[proj.ID] = proj.Name
+				}
+			}
+		}
+
+		byProject := make(map[string]int)
+		byLabel := make(map[string]int)
+		notable := make([]map[string]interface{}, 0)
+
+		for _, item := range items {
+			if item.ProjectID != "" {
+				name := projectMap[item.ProjectID]
+				if name == "" {
+					name = "Unknown"
+				}
+				byProject[name]++
+			}
+
+			for _, label := range item.Labels {
+				byLabel[label]++
+			}
+
+			if item.Priority == 4 {
+				notable = append(notable, map[string]interface{}{
+					"id":           item.ID,
+					"content":      item.Content,
+					"project_id":   item.ProjectID,
+					"completed_at": item.CompletedAt,
+				})
+			}
+		}
+
+		sort.Slice(notable, func(i, j int) bool {
+			return notable[i]["completed_at"].(string) < notable[j]["completed_at"].(string)
+		})
+
+		response := respond.Envelope{
+			"month":           month,
+			"total_completed": len(items),
+			"by_project":      byProject,
+			"by_label":        byLabel,
+			"notable_p1":      notable,
+		}
+		warnCompletedTruncated(response, truncated)
+
+		return respond.JSON(response), nil
+	}
+}
+
+// GoalProgressHandler creates a handler for comparing today's completions against karma goals.
+func GoalProgressHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		respBody, err := syncClient.Get(ctx, "/tasks/completed/stats")
+		if err != nil {
+			return respond.Errorf("failed to fetch productivity stats: %v", err), nil
+		}
+
+		var stats productivityStats
+		if err := json.Unmarshal(respBody, &stats); err != nil {
+			return respond.Errorf("failed to parse productivity stats: %v", err), nil
+		}
+
+		// days_items is keyed by the user's calendar day, not the server's.
+		loc, err := userLocation(ctx, syncClient)
+		if err != nil {
+			return respond.Errorf("failed to resolve user time zone: %v", err), nil
+		}
+		today := time.Now().In(loc).Format("2006-01-02")
+		completedToday := 0
+		for _, day := range stats.DaysItems {
+			if day.Date == today {
+				completedToday = day.TotalCompleted
+				break
+			}
+		}
+
+		completedThisWeek := 0
+		if len(stats.WeekItems) > 0 {
+			completedThisWeek = stats.WeekItems[0].TotalCompleted
+		}
+
+		remainingToday := stats.Goals.DailyGoal - completedToday
+		if remainingToday < 0 {
+			remainingToday = 0
+		}
+		remainingThisWeek := stats.Goals.WeeklyGoal - completedThisWeek
+		if remainingThisWeek < 0 {
+			remainingThisWeek = 0
+		}
+
+		dailyGoalMet := stats.Goals.DailyGoal > 0 && remainingToday == 0
+		onVacation := stats.Goals.VacationMode == 1
+
+		response := map[string]interface{}{
+			"karma":                 stats.Karma,
+			"karma_trend":           stats.KarmaTrend,
+			"daily_goal":            stats.Goals.DailyGoal,
+			"completed_today":       completedToday,
+			"remaining_today":       remainingToday,
+			"daily_goal_met":        dailyGoalMet,
+			"weekly_goal":           stats.Goals.WeeklyGoal,
+			"completed_this_week":   completedThisWeek,
+			"remaining_this_week":   remainingThisWeek,
+			"current_daily_streak":  stats.Goals.CurrentDailyStreak.Count,
+			"max_daily_streak":      stats.Goals.MaxDailyStreak.Count,
+			"current_weekly_streak": stats.Goals.CurrentWeeklyStreak.Count,
+			"vacation_mode":         onVacation,
+			"streak_at_risk":        !dailyGoalMet && !onVacation && stats.Goals.DailyGoal > 0,
+		}
+
+		switch {
+		case stats.Goals.DailyGoal == 0:
+			response["message"] = "No daily goal is set"
+		case dailyGoalMet:
+			response["message"] = fmt.Sprintf("Daily goal of %d met (%d completed today)", stats.Goals.DailyGoal, completedToday)
+		default:
+			response["message"] = fmt.Sprintf("Complete %d more task(s) today to reach the daily goal of %d", remainingToday, stats.Goals.DailyGoal)
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// ProjectBurndownHandler creates a handler for daily remaining vs completed counts in a project.
+func ProjectBurndownHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectID, ok := args["project_id"].(string)
+		if !ok || projectID == "" {
+			return respond.Error("project_id is required"), nil
+		}
+		if err := ValidateID(projectID, "project_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		until := today
+		if v, ok := args["until"].(string); ok && v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return respond.Error("until must be in YYYY-MM-DD format"), nil
+			}
+			until = t
+		}
+		since := until.AddDate(0, 0, -13)
+		if v, ok := args["since"].(string); ok && v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return respond.Error("since must be in YYYY-MM-DD format"), nil
+			}
+			since = t
+		}
+		if until.Before(since) {
+			return respond.Error("since must not be after until"), nil
+		}
+		if until.Sub(since) >= maxCompletedRange {
+			return respond.Error("date range must not exceed 90 days"), nil
+		}
+		rangeEnd := until.AddDate(0, 0, 1)
+
+		params := url.Values{}
+		params.Set("project_id", projectID)
+		tasksBody, err := client.Get(ctx, "/tasks?"+params.Encode())
+		if err != nil {
+			return respond.Errorf("failed to fetch tasks: %v", err), nil
+		}
+		var active []models.Task
+		if err := json.Unmarshal(tasksBody, &active); err != nil {
+			return respond.Errorf("failed to parse tasks: %v", err), nil
+		}
+
+		// Tasks completed after the range still count as remaining on days within
+		// it, so fetch completions up to today where the API range limit allows.
+		completedUntil := today.AddDate(0, 0, 1)
+		if completedUntil.Sub(since) > maxCompletedRange {
+			completedUntil = since.Add(maxCompletedRange)
+		}
+		if completedUntil.Before(rangeEnd) {
+			completedUntil = rangeEnd
+		}
+		completed, truncated, err := fetchCompletedTasks(ctx, syncClient, since, completedUntil, params)
+		if err != nil {
+			return respond.Errorf("failed to fetch completed tasks: %v", err), nil
+		}
+
+		type span struct {
+			added     time.Time
+			completed time.Time
+		}
+		spans := make([]span, 0, len(active)+len(completed))
+		for _, task := range active {
+			added, _ := taskAddedAt(task)
+			spans = append(spans, span{added: added})
+		}
+		for _, task := range completed {
+			added, _ := taskAddedAt(task)
+			s := span{added: added}
+			if t, err := time.Parse(time.RFC3339Nano, task.CompletedAt); err == nil {
+				s.completed = t
+			}
+			spans = append(spans, s)
+		}
+
+		days := make([]map[string]interface{}, 0)
+		cumulative := 0
+		for day := since; day.Before(rangeEnd); day = day.AddDate(0, 0, 1) {
+			dayEnd := day.AddDate(0, 0, 1)
+			remaining := 0
+			completedToday := 0
+			for _, s := range spans {
+				if !s.added.IsZero() && !s.added.Before(dayEnd) {
+					continue
+				}
+				switch {
+				case s.completed.IsZero() || !s.completed.Before(dayEnd):
+					remaining++
+				case !s.completed.Before(day):
+					completedToday++
+				}
+			}
+			cumulative += completedToday
+			days = append(days, map[string]interface{}{
+				"date":                 day.Format("2006-01-02"),
+				"remaining":            remaining,
+				"completed":            completedToday,
+				"cumulative_completed": cumulative,
+			})
+		}
+
+		response := respond.Envelope{
+			"project_id":      projectID,
+			"since":           since.Format("2006-01-02"),
+			"until":           until.Format("2006-01-02"),
+			"total_completed": cumulative,
+			"days":            days,
+		}
+		warnCompletedTruncated(response, truncated)
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,395 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonthlyReportHandler(t *testing.T) {
+	projectsGet := func(_ context.Context, path string) ([]byte, error) {
+		if path != "/projects" {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return json.Marshal([]map[string]interface{}{
+			{"id": "p1", "name": "Work"},
+			{"id": "p2", "name": "Home"},
+		})
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		syncGet   func(ctx context.Context, path string) ([]byte, error)
+		wantErr   bool
+		errSubstr string
+		wantTotal int
+		wantP1    int
+	}{
+		{
+			name: "happy path with pagination",
+			args: map[string]interface{}{"month": "2026-01"},
+			syncGet: func(_ context.Context, path string) ([]byte, error) {
+				if path == "/user" {
+					return []byte(`{"tz_info": {"timezone": "America/New_York"}}`), nil
+				}
+				// January in New York starts and ends at 05:00 UTC.
+				if !strings.Contains(path, "since=2026-01-01T05%3A00%3A00Z") || !strings.Contains(path, "until=2026-02-01T05%3A00%3A00Z") {
+					return nil, fmt.Errorf("unexpected range in path: %s", path)
+				}
+				if !strings.Contains(path, "cursor=next") {
+					return json.Marshal(map[string]interface{}{
+						"items": []map[string]interface{}{
+							{"id": "1", "content": "Ship", "project_id": "p1", "priority": 4, "labels": []string{"release"}},
+							{"id": "2", "content": "Review", "project_id": "p1", "priority": 1},
+						},
+						"next_cursor": "next",
+					})
+				}
+				return json.Marshal(map[string]interface{}{
+					"items": []map[string]interface{}{
+						{"id": "3", "content": "Laundry", "project_id": "p2", "priority": 2, "labels": []string{"chores"}},
+					},
+					"next_cursor": nil,
+				})
+			},
+			wantTotal: 3,
+			wantP1:    1,
+		},
+		{
+			name:      "invalid month",
+			args:      map[string]interface{}{"month": "January"},
+			wantErr:   true,
+			errSubstr: "YYYY-MM",
+		},
+		{
+			name:      "invalid project_id",
+			args:      map[string]interface{}{"project_id": "../x"},
+			wantErr:   true,
+			errSubstr: "contains invalid characters",
+		},
+		{
+			name: "API error",
+			args: map[string]interface{}{"month": "2026-01"},
+			syncGet: func(_ context.Context, path string) ([]byte, error) {
+				if path == "/user" {
+					return []byte(`{"tz_info": {"timezone": "UTC"}}`), nil
+				}
+				return nil, fmt.Errorf("server error")
+			},
+			wantErr:   true,
+			errSubstr: "failed to fetch completed tasks",
+		},
+		{
+			name: "user time zone error",
+			args: map[string]interface{}{"month": "2026-01"},
+			syncGet: func(_ context.Context, _ string) ([]byte, error) {
+				return nil, fmt.Errorf("server error")
+			},
+			wantErr:   true,
+			errSubstr: "failed to resolve user time zone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockAPI{GetFn: projectsGet}
+			syncClient := &MockSyncAPI{GetFn: tt.syncGet}
+			handler := MonthlyReportHandler(client, syncClient)
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if int(resp["total_completed"].(float64)) != tt.wantTotal {
+				t.Errorf("total_completed = %v, want %d", resp["total_completed"], tt.wantTotal)
+			}
+			if got := len(resp["notable_p1"].([]interface{})); got != tt.wantP1 {
+				t.Errorf("notable_p1 count = %d, want %d", got, tt.wantP1)
+			}
+			byProject := resp["by_project"].(map[string]interface{})
+			if byProject["Work"].(float64) != 2 {
+				t.Errorf("by_project[Work] = %v, want 2", byProject["Work"])
+			}
+		})
+	}
+}
+
+func TestMonthlyReportHandler_Truncated(t *testing.T) {
+	pages := 0
+	syncClient := &MockSyncAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if path == "/user" {
+				return []byte(`{"tz_info": {"timezone": "UTC"}}`), nil
+			}
+			pages++
+			return json.Marshal(map[string]interface{}{
+				"items":       []map[string]interface{}{{"id": fmt.Sprint(pages), "content": "Task"}},
+				"next_cursor": "more",
+			})
+		},
+	}
+	client := &MockAPI{GetFn: func(_ context.Context, _ string) ([]byte, error) { return []byte("[]"), nil }}
+
+	result, err := MonthlyReportHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{"month": "2026-01"}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+	if pages != maxCompletedPages {
+		t.Errorf("fetched %d pages, want %d", pages, maxCompletedPages)
+	}
+	var resp struct {
+		TotalCompleted int      `json:"total_completed"`
+		Warnings       []string `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.TotalCompleted != maxCompletedPages {
+		t.Errorf("total_completed = %d, want %d", resp.TotalCompleted, maxCompletedPages)
+	}
+	if len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "truncated") {
+		t.Errorf("warnings = %v, want a truncation warning", resp.Warnings)
+	}
+}
+
+func TestGoalProgressHandler(t *testing.T) {
+	// These zones are 25 hours apart, so at least one of them is on a
+	// different date than the host; "today" must follow the user's zone.
+	var loc *time.Location
+	for _, name := range []string{"Pacific/Kiritimati", "Pacific/Pago_Pago"} {
+		l, err := time.LoadLocation(name)
+		if err != nil {
+			t.Skipf("time zone data unavailable: %v", err)
+		}
+		if time.Now().In(l).Format("2006-01-02") != time.Now().Format("2006-01-02") {
+			loc = l
+		}
+	}
+	today := time.Now().In(loc).Format("2006-01-02")
+	user, _ := json.Marshal(map[string]interface{}{"tz_info": map[string]interface{}{"timezone": loc.String()}})
+
+	tests := []struct {
+		name          string
+		syncGet       func(ctx context.Context, path string) ([]byte, error)
+		wantErr       bool
+		errSubstr     string
+		wantRemaining int
+		wantMet       bool
+		wantAtRisk    bool
+	}{
+		{
+			name: "goal not yet met",
+			syncGet: func(_ context.Context, path string) ([]byte, error) {
+				if path == "/user" {
+					return user, nil
+				}
+				if path != "/tasks/completed/stats" {
+					return nil, fmt.Errorf("unexpected path: %s", path)
+				}
+				return json.Marshal(map[string]interface{}{
+					"karma":      1234.0,
+					"days_items": []map[string]interface{}{{"date": today, "total_completed": 2}},
+					"goals": map[string]interface{}{
+						"daily_goal":           5,
+						"current_daily_streak": map[string]interface{}{"count": 4},
+					},
+				})
+			},
+			wantRemaining: 3,
+			wantAtRisk:    true,
+		},
+		{
+			name: "goal met",
+			syncGet: func(_ context.Context, path string) ([]byte, error) {
+				if path == "/user" {
+					return user, nil
+				}
+				return json.Marshal(map[string]interface{}{
+					"days_items": []map[string]interface{}{{"date": today, "total_completed": 7}},
+					"goals":      map[string]interface{}{"daily_goal": 5},
+				})
+			},
+			wantRemaining: 0,
+			wantMet:       true,
+		},
+		{
+			name: "vacation mode is not at risk",
+			syncGet: func(_ context.Context, _ string) ([]byte, error) {
+				return json.Marshal(map[string]interface{}{
+					"goals": map[string]interface{}{"daily_goal": 5, "vacation_mode": 1},
+				})
+			},
+			wantRemaining: 5,
+		},
+		{
+			name: "API error",
+			syncGet: func(_ context.Context, _ string) ([]byte, error) {
+				return nil, fmt.Errorf("unauthorized")
+			},
+			wantErr:   true,
+			errSubstr: "failed to fetch productivity stats",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncClient := &MockSyncAPI{GetFn: tt.syncGet}
+			handler := GoalProgressHandler(syncClient)
+			result, err := handler(context.Background(), makeReq(nil))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if int(resp["remaining_today"].(float64)) != tt.wantRemaining {
+				t.Errorf("remaining_today = %v, want %d", resp["remaining_today"], tt.wantRemaining)
+			}
+			if resp["daily_goal_met"] != tt.wantMet {
+				t.Errorf("daily_goal_met = %v, want %v", resp["daily_goal_met"], tt.wantMet)
+			}
+			if resp["streak_at_risk"] != tt.wantAtRisk {
+				t.Errorf("streak_at_risk = %v, want %v", resp["streak_at_risk"], tt.wantAtRisk)
+			}
+		})
+	}
+}
+
+func TestProjectBurndownHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          map[string]interface{}
+		wantErr       bool
+		errSubstr     string
+		wantRemaining []int
+		wantCompleted []int
+	}{
+		{
+			name:          "three day range",
+			args:          map[string]interface{}{"project_id": "p1", "since": "2026-01-01", "until": "2026-01-03"},
+			wantRemaining: []int{3, 3, 2},
+			wantCompleted: []int{0, 1, 1},
+		},
+		{
+			name:      "missing project_id",
+			args:      map[string]interface{}{},
+			wantErr:   true,
+			errSubstr: "project_id is required",
+		},
+		{
+			name:      "inverted range",
+			args:      map[string]interface{}{"project_id": "p1", "since": "2026-01-05", "until": "2026-01-01"},
+			wantErr:   true,
+			errSubstr: "since must not be after until",
+		},
+		{
+			name:      "range too wide",
+			args:      map[string]interface{}{"project_id": "p1", "since": "2025-01-01", "until": "2026-01-01"},
+			wantErr:   true,
+			errSubstr: "must not exceed 90 days",
+		},
+	}
+
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if path != "/tasks?project_id=p1" {
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			}
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1", "created_at": "2025-12-20T10:00:00Z"},
+				{"id": "2", "created_at": "2026-01-02T10:00:00Z"},
+			})
+		},
+	}
+	syncClient := &MockSyncAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if !strings.Contains(path, "project_id=p1") {
+				return nil, fmt.Errorf("expected project_id in path, got: %s", path)
+			}
+			return json.Marshal(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"id": "3", "added_at": "2025-12-01T10:00:00Z", "completed_at": "2026-01-02T08:00:00Z"},
+					{"id": "4", "added_at": "2025-12-01T10:00:00Z", "completed_at": "2026-01-03T08:00:00Z"},
+				},
+			})
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := ProjectBurndownHandler(client, syncClient)
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				Days []struct {
+					Remaining int `json:"remaining"`
+					Completed int `json:"completed"`
+				} `json:"days"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if len(resp.Days) != len(tt.wantRemaining) {
+				t.Fatalf("got %d days, want %d", len(resp.Days), len(tt.wantRemaining))
+			}
+			for i := range resp.Days {
+				if resp.Days[i].Remaining != tt.wantRemaining[i] || resp.Days[i].Completed != tt.wantCompleted[i] {
+					t.Errorf("day %d = remaining %d completed %d, want %d and %d", i,
+						resp.Days[i].Remaining, resp.Days[i].Completed, tt.wantRemaining[i], tt.wantCompleted[i])
+				}
+			}
+		})
+	}
+}
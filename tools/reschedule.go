@@ -0,0 +1,268 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// rescheduleCandidate is a task on an overloaded day that could be pushed.
+type rescheduleCandidate struct {
+	task     models.Task
+	priority int
+	minutes  int
+	deadline string
+}
+
+// SuggestRescheduleHandler creates a handler that proposes which lower-priority
+// tasks to push off an over-capacity day, and to which days.
+func SuggestRescheduleHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		day := time.Now()
+		if v, ok := args["date"].(string); ok && v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return respond.Error("date must be a YYYY-MM-DD date"), nil
+			}
+			day = t
+		}
+		date := day.Format("2006-01-02")
+
+		capacityHours := 6.0
+		if c, ok := args["capacity_hours"].(float64); ok {
+			if c <= 0 || c > 24 {
+				return respond.Error("capacity_hours must be between 0 and 24"), nil
+			}
+			capacityHours = c
+		}
+		capacityMinutes := int(capacityHours * 60)
+
+		horizonDays := 7
+		if h, ok := args["horizon_days"].(float64); ok {
+			if h < 1 || h > 30 {
+				return respond.Error("horizon_days must be between 1 and 30"), nil
+			}
+			horizonDays = int(h)
+		}
+
+		params := url.Values{}
+		params.Set("filter", fmt.Sprintf("due after: %s & due before: %s",
+			day.AddDate(0, 0, -1).Format("2006-01-02"),
+			day.AddDate(0, 0, horizonDays+1).Format("2006-01-02")))
+		tasks, err := fetchTasks(ctx, client, params)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		load := make(map[string]int)
+		var candidates []rescheduleCandidate
+		for _, task := range tasks {
+			due := task.Due.Day()
+			minutes, ok := taskDurationMinutes(task, capacityMinutes)
+			if due == "" || !ok {
+				continue
+			}
+			load[due] += minutes
+			if due != date {
+				continue
+			}
+
+			if task.Due.IsRecurring || task.Due.Datetime != "" {
+				continue
+			}
+			if task.Priority >= 4 {
+				continue
+			}
+			candidates = append(candidates, rescheduleCandidate{
+				task:     task,
+				priority: task.Priority,
+				minutes:  minutes,
+				deadline: task.Deadline.Day(),
+			})
+		}
+
+		scheduled := load[date]
+		response := map[string]interface{}{
+			"date":              date,
+			"capacity_minutes":  capacityMinutes,
+			"scheduled_minutes": scheduled,
+			"overloaded":        scheduled > capacityMinutes,
+		}
+
+		moves := make([]map[string]interface{}, 0)
+		unplaced := make([]map[string]interface{}, 0)
+		if scheduled > capacityMinutes {
+			// Push the lowest priority first; within a priority, tasks without a
+			// deadline (or with the latest one) go before tightly bounded tasks.
+			sort.SliceStable(candidates, func(i, j int) bool {
+				a, b := candidates[i], candidates[j]
+				if a.priority != b.priority {
+					return a.priority < b.priority
+				}
+				if (a.deadline == "") != (b.deadline == "") {
+					return a.deadline == ""
+				}
+				return a.deadline > b.deadline
+			})
+
+			for _, c := range candidates {
+				if scheduled <= capacityMinutes {
+					break
+				}
+				target := ""
+				for offset := 1; offset <= horizonDays; offset++ {
+					next := day.AddDate(0, 0, offset).Format("2006-01-02")
+					if c.deadline != "" && next > c.deadline {
+						break
+					}
+					if load[next]+c.minutes <= capacityMinutes {
+						target = next
+						break
+					}
+				}
+
+				entry := map[string]interface{}{
+					"task_id":  c.task.ID,
+					"content":  c.task.Content,
+					"priority": c.priority,
+					"minutes":  c.minutes,
+				}
+				if c.deadline != "" {
+					entry["deadline"] = c.deadline
+				}
+				if target == "" {
+					unplaced = append(unplaced, entry)
+					continue
+				}
+				entry["from"] = date
+				entry["due_date"] = target
+				load[target] += c.minutes
+				scheduled -= c.minutes
+				moves = append(moves, entry)
+			}
+		}
+
+		applyMoves := make([]map[string]interface{}, len(moves))
+		for i, m := range moves {
+			applyMoves[i] = map[string]interface{}{"task_id": m["task_id"], "due_date": m["due_date"]}
+		}
+
+		response["moves"] = moves
+		response["unplaced"] = unplaced
+		response["minutes_after_moves"] = scheduled
+		response["still_overloaded"] = scheduled > capacityMinutes
+		if len(moves) > 0 {
+			response["apply"] = map[string]interface{}{
+				"tool":      "bulk_reschedule",
+				"arguments": map[string]interface{}{"moves": applyMoves},
+			}
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// BulkRescheduleHandler creates a handler that sets new due dates on several tasks at once.
+func BulkRescheduleHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		ctx, err := bulkStrategyArg(ctx, args)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		rawMoves, _ := args["moves"].([]interface{})
+		if len(rawMoves) == 0 {
+			return respond.Error("moves must contain at least one entry"), nil
+		}
+		if len(rawMoves) > maxPlanCommands {
+			return respond.Errorf("moves exceeds %d entries", maxPlanCommands), nil
+		}
+
+		atomic := atomicRequested(args)
+		ops := make([]todoist.BulkOperation, len(rawMoves))
+		for i, raw := range rawMoves {
+			move, ok := raw.(map[string]interface{})
+			if !ok {
+				return respond.Errorf("moves[%d] must be an object", i), nil
+			}
+			taskID, _ := move["task_id"].(string)
+			if err := ValidateID(taskID, fmt.Sprintf("moves[%d].task_id", i)); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			dueDate, _ := move["due_date"].(string)
+			if _, err := time.Parse("2006-01-02", dueDate); err != nil {
+				return respond.Errorf("moves[%d].due_date must be a YYYY-MM-DD date", i), nil
+			}
+
+			ops[i] = todoist.BulkOperation{
+				ID: taskID,
+				Command: todoist.Command{
+					Type: "item_update",
+					UUID: todoist.GenerateUUID(),
+					Args: map[string]interface{}{
+						"id":  taskID,
+						"due": map[string]interface{}{"date": dueDate},
+					},
+				},
+				Method: http.MethodPost,
+				Path:   fmt.Sprintf("/tasks/%s", taskID),
+				Body:   map[string]interface{}{"due_date": dueDate},
+			}
+		}
+
+		if estimateCostRequested(args) {
+			return bulkCostResult(ctx, client, syncClient, ops)
+		}
+
+		// Atomic runs need each task's due date to set it back.
+		if atomic {
+			ids := make([]string, len(ops))
+			for i, op := range ops {
+				ids[i] = op.ID
+			}
+			tasks, err := fetchTasksByIDs(ctx, client, ids)
+			if err != nil {
+				return respond.Errorf("failed to fetch tasks to reschedule: %v", err), nil
+			}
+			for i, op := range ops {
+				if task, ok := tasks[op.ID]; ok {
+					ops[i].Revert = restoreDueOperation(op.ID, task.Due)
+				}
+			}
+		}
+
+		result, err := todoist.NewBulkExecutor(client, syncClient).Execute(ctx, ops)
+		if err != nil {
+			return respond.Errorf("failed to reschedule tasks: %v", err), nil
+		}
+		if atomic && len(result.Failed) > 0 {
+			return atomicFailure(ctx, client, syncClient, ops, result, "reschedule"), nil
+		}
+		failedTasks := result.FailedIDs()
+
+		response := map[string]interface{}{
+			"total_tasks":     len(ops),
+			"rescheduled":     len(result.Succeeded),
+			"failed":          len(failedTasks),
+			"failed_task_ids": failedTasks,
+			"used_batching":   result.Strategy == todoist.StrategySync,
+		}
+		if len(failedTasks) == 0 {
+			response["message"] = localize("bulk_rescheduled", len(result.Succeeded))
+		} else {
+			response["message"] = localize("bulk_rescheduled_partial", len(result.Succeeded), len(ops), len(failedTasks))
+		}
+
+		return respond.JSON(response), nil
+	}
+}
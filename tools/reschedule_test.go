@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestSuggestRescheduleHandler(t *testing.T) {
+	task := func(id string, priority int, due string, minutes int, extra map[string]interface{}) map[string]interface{} {
+		tk := map[string]interface{}{
+			"id":       id,
+			"content":  "Task " + id,
+			"priority": float64(priority),
+			"due":      map[string]interface{}{"date": due},
+			"duration": map[string]interface{}{"amount": float64(minutes), "unit": "minute"},
+		}
+		for k, v := range extra {
+			tk[k] = v
+		}
+		return tk
+	}
+	tasks := []map[string]interface{}{
+		task("urgent", 4, "2026-03-02", 180, nil),
+		task("low", 1, "2026-03-02", 120, nil),
+		task("mid", 2, "2026-03-02", 90, nil),
+		task("recurring", 1, "2026-03-02", 60, map[string]interface{}{
+			"due": map[string]interface{}{"date": "2026-03-02", "is_recurring": true},
+		}),
+		task("bounded", 1, "2026-03-02", 60, map[string]interface{}{
+			"deadline": map[string]interface{}{"date": "2026-03-02"},
+		}),
+		task("busy", 3, "2026-03-03", 300, nil),
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantMoves    map[string]string
+		wantUnplaced []string
+		wantErr      string
+	}{
+		{
+			name: "pushes lowest priority first",
+			args: map[string]interface{}{"date": "2026-03-02", "capacity_hours": float64(6)},
+			// 510 scheduled minutes against 360: "low" (120) alone is not enough;
+			// "bounded" cannot move past its deadline, so "mid" goes next. Both
+			// skip 2026-03-03, which is already nearly full.
+			wantMoves:    map[string]string{"low": "2026-03-04", "mid": "2026-03-04"},
+			wantUnplaced: []string{"bounded"},
+		},
+		{
+			name:      "not overloaded",
+			args:      map[string]interface{}{"date": "2026-03-02", "capacity_hours": float64(12)},
+			wantMoves: map[string]string{},
+		},
+		{
+			name:    "invalid date",
+			args:    map[string]interface{}{"date": "monday"},
+			wantErr: "date must be",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFilter string
+			client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+				gotFilter = path
+				return json.Marshal(tasks)
+			}}
+			result, err := SuggestRescheduleHandler(client)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if !strings.Contains(gotFilter, "2026-03-01") || !strings.Contains(gotFilter, "2026-03-10") {
+				t.Errorf("filter path %q does not span the horizon", gotFilter)
+			}
+
+			var resp struct {
+				Moves    []map[string]interface{} `json:"moves"`
+				Unplaced []map[string]interface{} `json:"unplaced"`
+				Apply    struct {
+					Tool      string `json:"tool"`
+					Arguments struct {
+						Moves []map[string]interface{} `json:"moves"`
+					} `json:"arguments"`
+				} `json:"apply"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			got := make(map[string]string)
+			for _, m := range resp.Moves {
+				got[m["task_id"].(string)] = m["due_date"].(string)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantMoves) {
+				t.Errorf("moves = %v, want %v", got, tt.wantMoves)
+			}
+			if len(resp.Unplaced) != len(tt.wantUnplaced) {
+				t.Errorf("unplaced = %v, want %v", resp.Unplaced, tt.wantUnplaced)
+			}
+			if len(tt.wantMoves) > 0 && (resp.Apply.Tool != "bulk_reschedule" || len(resp.Apply.Arguments.Moves) != len(tt.wantMoves)) {
+				t.Errorf("apply = %+v", resp.Apply)
+			}
+		})
+	}
+}
+
+func TestBulkRescheduleHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		moves     []interface{}
+		wantPaths []string
+		wantErr   string
+	}{
+		{
+			name: "REST for a few tasks",
+			moves: []interface{}{
+				map[string]interface{}{"task_id": "1", "due_date": "2026-03-04"},
+				map[string]interface{}{"task_id": "2", "due_date": "2026-03-05"},
+			},
+			wantPaths: []string{"/tasks/1", "/tasks/2"},
+		},
+		{
+			name:    "empty moves",
+			moves:   []interface{}{},
+			wantErr: "at least one entry",
+		},
+		{
+			name:    "bad date",
+			moves:   []interface{}{map[string]interface{}{"task_id": "1", "due_date": "tomorrow"}},
+			wantErr: "moves[0].due_date",
+		},
+		{
+			name:    "missing task id",
+			moves:   []interface{}{map[string]interface{}{"due_date": "2026-03-04"}},
+			wantErr: "moves[0].task_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			client := &MockAPI{PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+				paths = append(paths, path)
+				if _, ok := body.(map[string]interface{})["due_date"]; !ok {
+					return nil, fmt.Errorf("missing due_date in %v", body)
+				}
+				return []byte(`{}`), nil
+			}}
+			syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, _ []todoist.Command) (*todoist.SyncResponse, error) {
+				return nil, fmt.Errorf("unexpected Sync call")
+			}}
+
+			result, err := BulkRescheduleHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{"moves": tt.moves}))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if strings.Join(paths, ",") != strings.Join(tt.wantPaths, ",") {
+				t.Errorf("paths = %v, want %v", paths, tt.wantPaths)
+			}
+			if !strings.Contains(text, `"rescheduled": 2`) {
+				t.Errorf("response = %s", text)
+			}
+		})
+	}
+}
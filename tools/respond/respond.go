@@ -0,0 +1,166 @@
+// Package respond builds tool results: JSON responses in a consistent
+// envelope, error results, and the response verbosity applied to both.
+package respond
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Envelope is a JSON object response. List responses carry a "count" and
+// their items under a key named after the item kind, e.g. "tasks"; the
+// optional "next_cursor", "warnings", and "rate_limit_remaining" fields are
+// set through its methods so that every tool spells them the same way.
+type Envelope map[string]interface{}
+
+// List creates the envelope for a list of items, stored under key.
+func List(key string, items interface{}) Envelope {
+	count := 0
+	if v := reflect.ValueOf(items); v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+		count = v.Len()
+	}
+	return Envelope{"count": count, key: items}
+}
+
+// Set adds a field to the envelope.
+func (e Envelope) Set(key string, value interface{}) Envelope {
+	e[key] = value
+	return e
+}
+
+// Paginate records the cursor for the next page. An empty cursor means the
+// list is complete and leaves the envelope unchanged.
+func (e Envelope) Paginate(nextCursor string) Envelope {
+	if nextCursor != "" {
+		e["next_cursor"] = nextCursor
+	}
+	return e
+}
+
+// Warn appends a non-fatal warning to the envelope's "warnings" array.
+func (e Envelope) Warn(format string, args ...interface{}) Envelope {
+	warnings, _ := e["warnings"].([]string)
+	e["warnings"] = append(warnings, fmt.Sprintf(format, args...))
+	return e
+}
+
+// RateLimit reports the REST requests remaining in the current window.
+func (e Envelope) RateLimit(remaining int) Envelope {
+	e["rate_limit_remaining"] = remaining
+	return e
+}
+
+// AddRateLimit sets "rate_limit_remaining" on a successful JSON object
+// result. A result that is a single entity, an object with a string "id", is
+// left as the API reports it and carries the count in its _meta instead.
+// Errors and non-JSON text are unchanged.
+func AddRateLimit(result *mcp.CallToolResult, remaining int) {
+	rewriteJSON(result, func(value interface{}) interface{} {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if _, isEntity := object["id"].(string); isEntity {
+			if result.Meta == nil {
+				result.Meta = mcp.NewMetaFromMap(map[string]any{})
+			} else if result.Meta.AdditionalFields == nil {
+				result.Meta.AdditionalFields = map[string]any{}
+			}
+			result.Meta.AdditionalFields["rate_limit_remaining"] = remaining
+			return nil
+		}
+		return Envelope(object).RateLimit(remaining)
+	})
+}
+
+// JSON renders v as an indented JSON text result, or an error result if it
+// cannot be encoded.
+func JSON(v interface{}) *mcp.CallToolResult {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return Errorf("failed to format response: %v", err)
+	}
+	return mcp.NewToolResultText(string(data))
+}
+
+// Resource renders v as the contents of the JSON resource at uri, in the
+// same format as JSON.
+func Resource(uri string, v interface{}) ([]mcp.ResourceContents, error) {
+	result := JSON(v)
+	if result.IsError {
+		return nil, errors.New(result.Content[0].(mcp.TextContent).Text)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     result.Content[0].(mcp.TextContent).Text,
+		},
+	}, nil
+}
+
+// Error creates an error result with message.
+func Error(message string) *mcp.CallToolResult {
+	return mcp.NewToolResultError(message)
+}
+
+// ErrorFrom creates an error result with the message of err, carrying retry
+// metadata when err is worth retrying after a wait.
+func ErrorFrom(err error) *mcp.CallToolResult {
+	return withRetryHint(mcp.NewToolResultError(err.Error()), err)
+}
+
+// Errorf creates an error result with a formatted message. When one of args
+// is an error worth retrying after a wait, such as a rate limit or a transient
+// server error, the result carries retry metadata too.
+func Errorf(format string, args ...interface{}) *mcp.CallToolResult {
+	result := mcp.NewToolResultError(fmt.Sprintf(format, args...))
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			withRetryHint(result, err)
+		}
+	}
+	return result
+}
+
+// withRetryHint marks result as retryable when err is.
+func withRetryHint(result *mcp.CallToolResult, err error) *mcp.CallToolResult {
+	var hint retryHinter
+	if result.StructuredContent == nil && errors.As(err, &hint) {
+		Retryable(result, hint.RetryAfter())
+	}
+	return result
+}
+
+// retryHinter is implemented by errors that may succeed when retried later.
+type retryHinter interface {
+	RetryAfter() time.Duration
+}
+
+// Retryable marks an error result as worth retrying after wait: its
+// structured content gets "retryable" and "retry_after_seconds", so agent
+// frameworks can back off instead of calling again at once, and the message
+// says the same for clients that only read text.
+func Retryable(result *mcp.CallToolResult, wait time.Duration) *mcp.CallToolResult {
+	seconds := max(int(math.Ceil(wait.Seconds())), 1)
+	message := ""
+	if len(result.Content) > 0 {
+		if tc, ok := result.Content[0].(mcp.TextContent); ok {
+			message = tc.Text
+			tc.Text = fmt.Sprintf("%s (retryable: try again in %ds)", tc.Text, seconds)
+			result.Content[0] = tc
+		}
+	}
+	result.StructuredContent = map[string]interface{}{
+		"error":               message,
+		"retryable":           true,
+		"retry_after_seconds": seconds,
+	}
+	return result
+}
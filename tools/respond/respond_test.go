@@ -0,0 +1,145 @@
+package respond
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// resultText returns the text of the first content item of a result.
+func resultText(r *mcp.CallToolResult) string {
+	if r != nil && len(r.Content) > 0 {
+		if tc, ok := r.Content[0].(mcp.TextContent); ok {
+			return tc.Text
+		}
+	}
+	return ""
+}
+
+func TestJSON(t *testing.T) {
+	result := JSON(map[string]interface{}{"id": "1"})
+	if result.IsError || resultText(result) != "{\n  \"id\": \"1\"\n}" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	result = JSON(map[string]interface{}{"bad": make(chan int)})
+	if !result.IsError {
+		t.Fatal("expected error result for unencodable value")
+	}
+	if text := resultText(result); text[:len("failed to format response")] != "failed to format response" {
+		t.Errorf("unexpected error text: %q", text)
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	result := Errorf("failed to fetch %s: %v", "tasks", "timeout")
+	if !result.IsError || resultText(result) != "failed to fetch tasks: timeout" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+type waitError struct{ wait time.Duration }
+
+func (e waitError) Error() string             { return "rate limit exceeded" }
+func (e waitError) RetryAfter() time.Duration { return e.wait }
+
+func TestErrorf_RetryHint(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", waitError{wait: 1500 * time.Millisecond})
+	result := Errorf("failed to fetch tasks: %v", err)
+	if text := resultText(result); text != "failed to fetch tasks: wrapped: rate limit exceeded (retryable: try again in 2s)" {
+		t.Errorf("unexpected text %q", text)
+	}
+	hint, ok := result.StructuredContent.(map[string]interface{})
+	if !ok || hint["retryable"] != true || hint["retry_after_seconds"] != 2 || hint["error"] != "failed to fetch tasks: wrapped: rate limit exceeded" {
+		t.Errorf("unexpected structured content %+v", result.StructuredContent)
+	}
+
+	if result := Errorf("failed: %v", errors.New("not found")); result.StructuredContent != nil {
+		t.Errorf("expected no retry hint, got %+v", result.StructuredContent)
+	}
+}
+
+func TestErrorFrom(t *testing.T) {
+	result := ErrorFrom(waitError{})
+	if !result.IsError || resultText(result) != "rate limit exceeded (retryable: try again in 1s)" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result := ErrorFrom(errors.New("task_id is required")); resultText(result) != "task_id is required" || result.StructuredContent != nil {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	tasks := []map[string]interface{}{{"id": "1"}, {"id": "2"}}
+	env := List("tasks", tasks).
+		Paginate("").
+		Paginate("abc").
+		Warn("label %q not found and was skipped", "foo").
+		Warn("filter matched 0 tasks").
+		RateLimit(440)
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got struct {
+		Count              int                      `json:"count"`
+		Tasks              []map[string]interface{} `json:"tasks"`
+		NextCursor         string                   `json:"next_cursor"`
+		Warnings           []string                 `json:"warnings"`
+		RateLimitRemaining int                      `json:"rate_limit_remaining"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got.Count != 2 || len(got.Tasks) != 2 || got.NextCursor != "abc" || got.RateLimitRemaining != 440 {
+		t.Errorf("unexpected envelope: %s", data)
+	}
+	if len(got.Warnings) != 2 || got.Warnings[0] != `label "foo" not found and was skipped` {
+		t.Errorf("unexpected warnings: %v", got.Warnings)
+	}
+
+	if _, ok := List("tasks", nil)["next_cursor"]; ok {
+		t.Error("empty envelope has a cursor")
+	}
+}
+
+func TestAddRateLimit(t *testing.T) {
+	result := JSON(List("tasks", []map[string]interface{}{{"id": "1"}}))
+	AddRateLimit(result, 440)
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(resultText(result)), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got["rate_limit_remaining"] != float64(440) || got["count"] != float64(1) {
+		t.Errorf("unexpected envelope: %v", got)
+	}
+
+	for _, text := range []string{`{"id": "1", "content": "Buy milk"}`, `[1, 2]`, "id,content\n1,Buy milk"} {
+		result := mcp.NewToolResultText(text)
+		AddRateLimit(result, 440)
+		if resultText(result) != text {
+			t.Errorf("AddRateLimit changed %q to %q", text, resultText(result))
+		}
+	}
+
+	entity := mcp.NewToolResultText(`{"id": "1", "content": "Buy milk"}`)
+	AddRateLimit(entity, 440)
+	data, err := json.Marshal(entity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"_meta":{"rate_limit_remaining":440}`) {
+		t.Errorf("entity result without _meta rate limit: %s", data)
+	}
+	failed := Error("boom")
+	AddRateLimit(failed, 440)
+	if resultText(failed) != "boom" {
+		t.Errorf("AddRateLimit changed an error result: %q", resultText(failed))
+	}
+}
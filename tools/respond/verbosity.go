@@ -0,0 +1,178 @@
+package respond
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Response verbosity levels, selected with RESPONSE_VERBOSITY.
+const (
+	// VerbosityFull returns entities exactly as the API reports them.
+	VerbosityFull = "full"
+	// VerbosityNormal reduces entities to their IDs and key fields.
+	VerbosityNormal = "normal"
+	// VerbosityMinimal reduces entities to their IDs.
+	VerbosityMinimal = "minimal"
+)
+
+// verbosity controls how much of each entity tool results include.
+var verbosity = VerbosityFull
+
+// Verbosities lists the supported RESPONSE_VERBOSITY values.
+var Verbosities = []string{VerbosityMinimal, VerbosityNormal, VerbosityFull}
+
+// SetVerbosity sets how much of each entity tool results include.
+// An empty level selects full.
+func SetVerbosity(level string) error {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if level == "" {
+		level = VerbosityFull
+	}
+	if !slices.Contains(Verbosities, level) {
+		return fmt.Errorf("unsupported response verbosity %q (supported: %s)", level, strings.Join(Verbosities, ", "))
+	}
+	verbosity = level
+	return nil
+}
+
+// entityKeyFields are the API fields kept at normal verbosity. Together they
+// identify an entity and place it, without descriptions, timestamps, or
+// creator metadata.
+var entityKeyFields = []string{
+	"id", "content", "name", "project_id", "section_id", "parent_id",
+	"due", "deadline", "priority", "labels", "checked", "is_completed",
+}
+
+// entityAPIFields are the fields Todoist reports on tasks, projects, sections,
+// labels, comments, collaborators, and reminders, in the REST and Sync APIs.
+// Verbosity trims only these: fields a tool computed and added to an entity,
+// such as breadcrumb, is_timed, or not_found, are always kept.
+var entityAPIFields = setOf(
+	// Tasks.
+	"id", "content", "description", "project_id", "section_id", "parent_id", "labels",
+	"priority", "due", "deadline", "duration", "order", "url", "comment_count", "note_count",
+	"is_completed", "checked", "is_deleted", "is_collapsed", "collapsed", "child_order", "day_order",
+	"created_at", "added_at", "updated_at", "completed_at", "creator_id", "user_id",
+	"assignee_id", "assigner_id", "added_by_uid", "assigned_by_uid", "responsible_uid",
+	"v2_id", "v2_parent_id", "v2_project_id", "v2_section_id", "sync_id",
+	// Projects, sections, and labels.
+	"name", "color", "is_shared", "is_favorite", "is_inbox_project", "is_team_inbox", "inbox_project",
+	"team_inbox", "view_style", "workspace_id", "folder_id", "is_archived", "archived_at",
+	"can_assign_tasks", "default_order", "role", "access", "section_order", "item_order",
+	// Comments.
+	"task_id", "item_id", "posted_at", "posted_uid", "attachment", "file_attachment",
+	"uids_to_notify", "reactions",
+	// Collaborators.
+	"email", "full_name", "timezone", "image_id",
+	// Reminders.
+	"type", "notify_uid", "minute_offset", "loc_lat", "loc_long", "loc_trigger", "radius",
+)
+
+// setOf returns a set of the given names.
+func setOf(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// shapeEntities applies the response verbosity to every entity in a decoded
+// JSON value. An entity is any object with a string "id". Its API fields are
+// trimmed to the level; other fields, which the tool added, are kept and
+// shaped in turn, as are the summary fields around entities.
+func shapeEntities(value interface{}, level string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, isEntity := v["id"].(string); isEntity {
+			shaped := make(map[string]interface{})
+			for key, field := range v {
+				switch {
+				case !entityAPIFields[key]:
+					shaped[key] = shapeEntities(field, level)
+					continue
+				case level == VerbosityMinimal && key != "id":
+					continue
+				case !slices.Contains(entityKeyFields, key):
+					continue
+				}
+				if due, isObject := field.(map[string]interface{}); isObject {
+					// Keep when a task is due and the phrase that set it,
+					// not the recurrence details.
+					kept := map[string]interface{}{"date": due["date"]}
+					for _, k := range []string{"datetime", "string"} {
+						if v, ok := due[k]; ok && v != nil {
+							kept[k] = v
+						}
+					}
+					field = kept
+				}
+				shaped[key] = field
+			}
+			return shaped
+		}
+		for key, field := range v {
+			v[key] = shapeEntities(field, level)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = shapeEntities(item, level)
+		}
+		return v
+	}
+	return value
+}
+
+// ApplyVerbosity reduces the entities in a successful JSON tool
+// result to the configured verbosity. Results at full verbosity, errors, and
+// non-JSON text such as CSV exports are left unchanged.
+func ApplyVerbosity(result *mcp.CallToolResult) {
+	if verbosity == VerbosityFull {
+		return
+	}
+	rewriteJSON(result, func(value interface{}) interface{} {
+		return shapeEntities(value, verbosity)
+	})
+}
+
+// rewriteJSON replaces the JSON text of a successful tool result with
+// rewrite applied to its decoded value. Errors, non-JSON text, and results
+// for which rewrite returns nil are left unchanged.
+func rewriteJSON(result *mcp.CallToolResult, rewrite func(value interface{}) interface{}) {
+	if result == nil || result.IsError {
+		return
+	}
+	for i, c := range result.Content {
+		tc, ok := c.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(tc.Text))
+		// Keep large numeric IDs and durations exact.
+		decoder.UseNumber()
+		var value interface{}
+		if decoder.Decode(&value) != nil || decoder.More() {
+			return
+		}
+		value = rewrite(value)
+		if value == nil {
+			return
+		}
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if encoder.Encode(value) != nil {
+			return
+		}
+		tc.Text = strings.TrimSuffix(buf.String(), "\n")
+		result.Content[i] = tc
+		return
+	}
+}
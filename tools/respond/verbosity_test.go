@@ -0,0 +1,148 @@
+package respond
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// useVerbosity switches the response verbosity for one test.
+func useVerbosity(t *testing.T, level string) {
+	t.Helper()
+	previous := verbosity
+	if err := SetVerbosity(level); err != nil {
+		t.Fatalf("SetVerbosity(%q) error: %v", level, err)
+	}
+	t.Cleanup(func() { verbosity = previous })
+}
+
+func TestSetVerbosity(t *testing.T) {
+	t.Cleanup(func() { verbosity = VerbosityFull })
+	for _, level := range []string{"minimal", " Normal ", "full", ""} {
+		if err := SetVerbosity(level); err != nil {
+			t.Errorf("SetVerbosity(%q) error: %v", level, err)
+		}
+	}
+	if verbosity != VerbosityFull {
+		t.Errorf("empty level selected %q, want full", verbosity)
+	}
+	if err := SetVerbosity("verbose"); err == nil {
+		t.Error("expected error for unsupported level")
+	}
+}
+
+func TestApplyVerbosity(t *testing.T) {
+	const body = `{
+  "succeeded": 1,
+  "tasks": [
+    {
+      "id": "123",
+      "content": "Buy milk",
+      "description": "2% from the corner shop",
+      "project_id": "9",
+      "priority": 4,
+      "due": {"date": "2026-10-16", "string": "every day", "is_recurring": true},
+      "duration": {"amount": 15, "unit": "minute"},
+      "creator_id": "77"
+    }
+  ]
+}`
+
+	tests := []struct {
+		level string
+		want  map[string]interface{}
+	}{
+		{VerbosityFull, map[string]interface{}{
+			"id": "123", "content": "Buy milk", "description": "2% from the corner shop", "project_id": "9", "priority": float64(4),
+			"due":      map[string]interface{}{"date": "2026-10-16", "string": "every day", "is_recurring": true},
+			"duration": map[string]interface{}{"amount": float64(15), "unit": "minute"}, "creator_id": "77",
+		}},
+		{VerbosityNormal, map[string]interface{}{
+			"id": "123", "content": "Buy milk", "project_id": "9", "priority": float64(4),
+			"due": map[string]interface{}{"date": "2026-10-16", "string": "every day"},
+		}},
+		{VerbosityMinimal, map[string]interface{}{"id": "123"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			useVerbosity(t, tt.level)
+			result := mcp.NewToolResultText(body)
+			ApplyVerbosity(result)
+
+			var got struct {
+				Succeeded int                      `json:"succeeded"`
+				Tasks     []map[string]interface{} `json:"tasks"`
+			}
+			if err := json.Unmarshal([]byte(resultText(result)), &got); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if got.Succeeded != 1 {
+				t.Errorf("summary field lost: %+v", got)
+			}
+			gotJSON, _ := json.Marshal(got.Tasks[0])
+			wantJSON, _ := json.Marshal(tt.want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("task = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+
+	t.Run("due time kept", func(t *testing.T) {
+		useVerbosity(t, VerbosityNormal)
+		result := mcp.NewToolResultText(`{"id": "1", "content": "Call Bob", "due": {"date": "2026-10-16", "datetime": "2026-10-16T15:00:00", "timezone": null, "is_recurring": false, "lang": "en"}}`)
+		ApplyVerbosity(result)
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(resultText(result)), &got); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		gotJSON, _ := json.Marshal(got["due"])
+		if want := `{"date":"2026-10-16","datetime":"2026-10-16T15:00:00"}`; string(gotJSON) != want {
+			t.Errorf("due = %s, want %s", gotJSON, want)
+		}
+	})
+
+	t.Run("warnings kept", func(t *testing.T) {
+		useVerbosity(t, VerbosityMinimal)
+		result := mcp.NewToolResultText(`{"id": "1", "content": "Call Bob", "warnings": ["due_string could not be parsed"]}`)
+		ApplyVerbosity(result)
+		if got := resultText(result); got != "{\n  \"id\": \"1\",\n  \"warnings\": [\n    \"due_string could not be parsed\"\n  ]\n}" {
+			t.Errorf("unexpected result: %s", got)
+		}
+	})
+
+	t.Run("fields the tool added kept", func(t *testing.T) {
+		useVerbosity(t, VerbosityMinimal)
+		result := mcp.NewToolResultText(`{"tasks": {
+  "1": {"id": "1", "content": "Call Bob", "creator_id": "7", "is_timed": false, "breadcrumb": "Work > Calls",
+    "parent_chain": [{"id": "0", "content": "Calls", "url": "https://todoist.com"}]},
+  "2": {"id": "2", "not_found": true}
+}}`)
+		ApplyVerbosity(result)
+		var got struct {
+			Tasks map[string]map[string]interface{} `json:"tasks"`
+		}
+		if err := json.Unmarshal([]byte(resultText(result)), &got); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		gotJSON, _ := json.Marshal(got.Tasks)
+		want := `{"1":{"breadcrumb":"Work \u003e Calls","id":"1","is_timed":false,"parent_chain":[{"id":"0"}]},"2":{"id":"2","not_found":true}}`
+		if string(gotJSON) != want {
+			t.Errorf("tasks = %s, want %s", gotJSON, want)
+		}
+	})
+
+	t.Run("non-JSON and errors unchanged", func(t *testing.T) {
+		useVerbosity(t, VerbosityMinimal)
+		for _, result := range []*mcp.CallToolResult{
+			mcp.NewToolResultText("TYPE,CONTENT\ntask,Buy milk"),
+			mcp.NewToolResultError(`{"id": "1", "content": "x"}`),
+		} {
+			before := resultText(result)
+			ApplyVerbosity(result)
+			if after := resultText(result); after != before {
+				t.Errorf("result changed: %q -> %q", before, after)
+			}
+		}
+	})
+}
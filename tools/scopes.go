@@ -0,0 +1,190 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// Todoist OAuth scopes. Personal API tokens carry all of them.
+const (
+	ScopeTaskAdd       = "task:add"
+	ScopeDataRead      = "data:read"
+	ScopeDataReadWrite = "data:read_write"
+	ScopeDataDelete    = "data:delete"
+	ScopeProjectDelete = "project:delete"
+)
+
+// KnownScopes lists the scopes accepted in TODOIST_TOKEN_SCOPES.
+var KnownScopes = []string{ScopeTaskAdd, ScopeDataRead, ScopeDataReadWrite, ScopeDataDelete, ScopeProjectDelete}
+
+// taskAddTools only create tasks, so the task:add scope is enough for them.
+var taskAddTools = []string{"create_task", "quick_add_task", "batch_create_tasks"}
+
+// ValidateScopes rejects unknown scope names.
+func ValidateScopes(scopes []string) error {
+	for _, s := range scopes {
+		if !slices.Contains(KnownScopes, s) {
+			return fmt.Errorf("unknown token scope %q (known: %s)", s, strings.Join(KnownScopes, ", "))
+		}
+	}
+	return nil
+}
+
+// scopeAllows reports whether a token limited to scopes can use tool. An
+// empty scope list means an unrestricted personal token.
+func scopeAllows(scopes []string, tool mcp.Tool) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	if tool.Annotations.OpenWorldHint != nil && !*tool.Annotations.OpenWorldHint {
+		// Local tools never call Todoist.
+		return true
+	}
+	has := func(s string) bool { return slices.Contains(scopes, s) }
+	readOnly := tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+	if readOnly {
+		return has(ScopeDataRead) || has(ScopeDataReadWrite)
+	}
+	if slices.Contains(taskAddTools, tool.Name) && has(ScopeTaskAdd) {
+		return true
+	}
+	if !has(ScopeDataReadWrite) {
+		return false
+	}
+	if tool.Name == "delete_project" {
+		return has(ScopeProjectDelete)
+	}
+	if strings.HasPrefix(tool.Name, "delete_") || strings.HasPrefix(tool.Name, "bulk_delete_") || tool.Name == "cleanup_workspace" {
+		return has(ScopeDataDelete)
+	}
+	return true
+}
+
+// forbiddenThreshold is how many consecutive 403 responses mark a tool as
+// refused. One 403 can be a single shared project the user cannot edit; a
+// repeat suggests the token itself lacks permission.
+const forbiddenThreshold = 2
+
+// forbiddenMarker is the text of the client's 403 error.
+const forbiddenMarker = "access forbidden:"
+
+// refusedNote is prepended to the description of refused tools.
+const refusedNote = "[Currently unavailable: Todoist refused this tool with 403 Forbidden; the API token probably lacks the required scope.] "
+
+type refusal struct {
+	count int
+	until time.Time
+}
+
+// ScopeGuard watches tool results for 403 Forbidden errors. After repeated
+// refusals a tool is annotated as unavailable, so clients are told through
+// notifications/tools/list_changed, and further calls fail fast without
+// using the API until the block expires.
+type ScopeGuard struct {
+	mu       sync.Mutex
+	srv      *server.MCPServer
+	ttl      time.Duration
+	refusals map[string]*refusal
+}
+
+// NewScopeGuard creates a guard that blocks refused tools for ttl.
+func NewScopeGuard(srv *server.MCPServer, ttl time.Duration) *ScopeGuard {
+	return &ScopeGuard{srv: srv, ttl: ttl, refusals: make(map[string]*refusal)}
+}
+
+// Middleware short-circuits blocked tools and records 403 results.
+func (g *ScopeGuard) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			name := req.Params.Name
+			if until, blocked := g.blockedUntil(name); blocked {
+				return respond.Errorf(
+					"%s is unavailable: Todoist refused it with 403 Forbidden on repeated calls, so the API token likely lacks permission (e.g. a read-only OAuth token). Not retrying until %s; use another tool or ask the user to grant access",
+					name, until.Format(time.RFC3339)), nil
+			}
+
+			result, err := next(ctx, req)
+			if err == nil && result != nil {
+				g.observe(name, result.IsError && strings.Contains(resultTextContent(result), forbiddenMarker))
+			}
+			return result, err
+		}
+	}
+}
+
+func (g *ScopeGuard) blockedUntil(name string) (time.Time, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	r, ok := g.refusals[name]
+	if !ok || r.until.IsZero() || !time.Now().Before(r.until) {
+		return time.Time{}, false
+	}
+	return r.until, true
+}
+
+// observe counts consecutive 403 results for a tool, annotating it once the
+// threshold is reached and restoring it after a success.
+func (g *ScopeGuard) observe(name string, forbidden bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	r := g.refusals[name]
+	if !forbidden {
+		if r != nil {
+			delete(g.refusals, name)
+			if !r.until.IsZero() {
+				g.annotate(name, false)
+			}
+		}
+		return
+	}
+	if r == nil {
+		r = &refusal{}
+		g.refusals[name] = r
+	}
+	r.count++
+	if r.count >= forbiddenThreshold {
+		if r.until.IsZero() {
+			g.annotate(name, true)
+		}
+		r.until = time.Now().Add(g.ttl)
+	}
+}
+
+// annotate re-registers a tool with or without the refused note, which makes
+// the server send notifications/tools/list_changed.
+func (g *ScopeGuard) annotate(name string, refused bool) {
+	st := g.srv.GetTool(name)
+	if st == nil {
+		return
+	}
+	tool := st.Tool
+	tool.Description = strings.TrimPrefix(tool.Description, refusedNote)
+	if refused {
+		tool.Description = refusedNote + tool.Description
+	}
+	g.srv.AddTools(server.ServerTool{Tool: tool, Handler: st.Handler})
+}
+
+// Refused lists the tools currently blocked after repeated 403 responses.
+func (g *ScopeGuard) Refused() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	names := make([]string, 0)
+	for name, r := range g.refusals {
+		if !r.until.IsZero() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
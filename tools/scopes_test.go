@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestScopeAllows(t *testing.T) {
+	readTool := mcp.NewTool("get_tasks", mcp.WithReadOnlyHintAnnotation(true))
+	localTool := mcp.NewTool("get_server_info", mcp.WithOpenWorldHintAnnotation(false))
+	tests := []struct {
+		scopes []string
+		tool   mcp.Tool
+		want   bool
+	}{
+		{nil, mcp.NewTool("delete_project"), true},
+		{[]string{ScopeDataRead}, readTool, true},
+		{[]string{ScopeTaskAdd}, readTool, false},
+		{[]string{ScopeTaskAdd}, localTool, true},
+		{[]string{ScopeTaskAdd}, mcp.NewTool("create_task"), true},
+		{[]string{ScopeDataRead}, mcp.NewTool("update_task"), false},
+		{[]string{ScopeDataReadWrite}, mcp.NewTool("update_task"), true},
+		{[]string{ScopeDataReadWrite}, mcp.NewTool("delete_task"), false},
+		{[]string{ScopeDataReadWrite, ScopeDataDelete}, mcp.NewTool("delete_task"), true},
+		{[]string{ScopeDataReadWrite, ScopeDataDelete}, mcp.NewTool("delete_project"), false},
+		{[]string{ScopeDataReadWrite, ScopeProjectDelete}, mcp.NewTool("delete_project"), true},
+	}
+	for _, tt := range tests {
+		if got := scopeAllows(tt.scopes, tt.tool); got != tt.want {
+			t.Errorf("scopeAllows(%v, %s) = %v, want %v", tt.scopes, tt.tool.Name, got, tt.want)
+		}
+	}
+
+	if err := ValidateScopes([]string{"data:write"}); err == nil {
+		t.Error("expected error for unknown scope")
+	}
+}
+
+func TestToolGroups_SetScopes(t *testing.T) {
+	srv := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	groups := NewToolGroups(srv)
+	groups.SetScopes([]string{ScopeDataRead})
+	groups.Add("tasks", mcp.NewTool("get_tasks", mcp.WithReadOnlyHintAnnotation(true)), noopHandler)
+	groups.Add("tasks", mcp.NewTool("create_task"), noopHandler)
+
+	if srv.GetTool("get_tasks") == nil || srv.GetTool("create_task") != nil {
+		t.Errorf("tools = %v, want only get_tasks", srv.ListTools())
+	}
+}
+
+func TestScopeGuard(t *testing.T) {
+	srv := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	srv.AddTool(mcp.NewTool("share_project", mcp.WithDescription("Share a project")), noopHandler)
+	guard := NewScopeGuard(srv, time.Hour)
+
+	calls := 0
+	forbidden := true
+	handler := guard.Middleware()(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		if forbidden {
+			return mcp.NewToolResultError("failed to share project: access forbidden: you don't have permission to access this resource"), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+	req := makeReq(nil)
+	req.Params.Name = "share_project"
+
+	for range forbiddenThreshold {
+		if _, err := handler(context.Background(), req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := guard.Refused(); len(got) != 1 || got[0] != "share_project" {
+		t.Fatalf("Refused() = %v, want [share_project]", got)
+	}
+	if desc := srv.GetTool("share_project").Tool.Description; !strings.HasPrefix(desc, refusedNote) {
+		t.Errorf("description = %q, want refused note", desc)
+	}
+
+	result, _ := handler(context.Background(), req)
+	if !result.IsError || !strings.Contains(resultText(result), "unavailable") || calls != forbiddenThreshold {
+		t.Errorf("blocked call = %q after %d calls", resultText(result), calls)
+	}
+
+	// Once the block expires a successful call restores the tool.
+	guard.refusals["share_project"].until = time.Now().Add(-time.Second)
+	forbidden = false
+	if result, _ := handler(context.Background(), req); result.IsError {
+		t.Errorf("call after expiry = %q", resultText(result))
+	}
+	if len(guard.Refused()) != 0 || srv.GetTool("share_project").Tool.Description != "Share a project" {
+		t.Errorf("Refused() = %v, description = %q", guard.Refused(), srv.GetTool("share_project").Tool.Description)
+	}
+}
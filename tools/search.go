@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// searchEntityTypes lists the entity types search_all can look through, in result order.
+var searchEntityTypes = []string{"task", "project", "section", "label", "comment"}
+
+// textMatch returns the first of the given fields whose value contains the
+// lowercased query, or "" when none does.
+func textMatch(query string, fields ...textField) string {
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field.value), query) {
+			return field.name
+		}
+	}
+	return ""
+}
+
+// textField is a named text field of an entity, searched by textMatch.
+type textField struct {
+	name, value string
+}
+
+// SearchAllHandler creates a handler for searching tasks, projects, sections, labels, and comments at once.
+func SearchAllHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		query, ok := args["query"].(string)
+		query = strings.ToLower(strings.TrimSpace(query))
+		if !ok || query == "" {
+			return respond.Error("query is required"), nil
+		}
+
+		types := searchEntityTypes
+		if typesParam, ok := args["types"].([]interface{}); ok && len(typesParam) > 0 {
+			types = make([]string, 0, len(typesParam))
+			for _, t := range typesParam {
+				typeStr, _ := t.(string)
+				if !slices.Contains(searchEntityTypes, typeStr) {
+					return respond.Errorf("invalid type %q (use %s)", typeStr, strings.Join(searchEntityTypes, ", ")), nil
+				}
+				types = append(types, typeStr)
+			}
+		}
+
+		limit := 10
+		if l, ok := args["limit"].(float64); ok {
+			if l < 1 || l > 50 {
+				return respond.Error("limit must be between 1 and 50"), nil
+			}
+			limit = int(l)
+		}
+
+		projectNames := make(map[string]string)
+		var projects []models.Project
+		if projectsBody, err := client.Get(ctx, "/projects"); err == nil {
+			if json.Unmarshal(projectsBody, &projects) == nil {
+				for _, proj := range projects {
+					projectNames[proj.ID] = proj.Name
+				}
+			}
+		} else if slices.Contains(types, "project") {
+			return respond.Errorf("failed to fetch projects: %v", err), nil
+		}
+
+		matches := make([]map[string]interface{}, 0)
+		counts := make(map[string]int)
+		add := func(entityType string, match map[string]interface{}) {
+			if counts[entityType] >= limit {
+				return
+			}
+			counts[entityType]++
+			match["type"] = entityType
+			if projectID, ok := match["project_id"].(string); ok && projectNames[projectID] != "" {
+				match["project_name"] = projectNames[projectID]
+			}
+			matches = append(matches, match)
+		}
+
+		for _, entityType := range types {
+			switch entityType {
+			case "task":
+				respBody, err := client.Get(ctx, "/tasks")
+				if err != nil {
+					return respond.Errorf("failed to fetch tasks: %v", err), nil
+				}
+				var tasks []models.Task
+				if err := json.Unmarshal(respBody, &tasks); err != nil {
+					return respond.Errorf("failed to parse tasks: %v", err), nil
+				}
+				for _, task := range tasks {
+					if field := textMatch(query, textField{"content", task.Content}, textField{"description", task.Description}); field != "" {
+						add("task", map[string]interface{}{
+							"id": task.ID, "content": task.Content, "project_id": task.ProjectID, "matched_field": field,
+						})
+					}
+				}
+
+			case "project":
+				for _, proj := range projects {
+					if field := textMatch(query, textField{"name", proj.Name}, textField{"description", proj.Description}); field != "" {
+						add("project", map[string]interface{}{
+							"id": proj.ID, "name": proj.Name, "matched_field": field,
+						})
+					}
+				}
+
+			case "section":
+				respBody, err := client.Get(ctx, "/sections")
+				if err != nil {
+					return respond.Errorf("failed to fetch sections: %v", err), nil
+				}
+				var sections []models.Section
+				if err := json.Unmarshal(respBody, &sections); err != nil {
+					return respond.Errorf("failed to parse sections: %v", err), nil
+				}
+				for _, section := range sections {
+					if field := textMatch(query, textField{"name", section.Name}); field != "" {
+						add("section", map[string]interface{}{
+							"id": section.ID, "name": section.Name, "project_id": section.ProjectID, "matched_field": field,
+						})
+					}
+				}
+
+			case "label":
+				respBody, err := client.Get(ctx, "/labels")
+				if err != nil {
+					return respond.Errorf("failed to fetch labels: %v", err), nil
+				}
+				var labels []models.Label
+				if err := json.Unmarshal(respBody, &labels); err != nil {
+					return respond.Errorf("failed to parse labels: %v", err), nil
+				}
+				for _, label := range labels {
+					if field := textMatch(query, textField{"name", label.Name}); field != "" {
+						add("label", map[string]interface{}{
+							"id": label.ID, "name": label.Name, "matched_field": field,
+						})
+					}
+				}
+
+			case "comment":
+				resources, err := fetchSyncResources(ctx, syncClient, "notes", "project_notes")
+				if err != nil {
+					return respond.Errorf("failed to fetch comments: %v", err), nil
+				}
+				for _, resource := range []string{"notes", "project_notes"} {
+					notes, err := decodeSyncObjects(resources[resource])
+					if err != nil {
+						return respond.Errorf("failed to parse comments: %v", err), nil
+					}
+					for _, note := range notes {
+						content, _ := note["content"].(string)
+						if field := textMatch(query, textField{"content", content}); field != "" {
+							match := map[string]interface{}{
+								"id": note["id"], "content": note["content"], "matched_field": field,
+							}
+							if taskID, ok := note["item_id"]; ok && taskID != nil {
+								match["task_id"] = taskID
+							}
+							if projectID, ok := note["project_id"]; ok && projectID != nil {
+								match["project_id"] = projectID
+							}
+							add("comment", match)
+						}
+					}
+				}
+			}
+		}
+
+		response := map[string]interface{}{
+			"query":   query,
+			"count":   len(matches),
+			"counts":  counts,
+			"matches": matches,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
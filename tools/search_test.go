@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSearchAllHandler(t *testing.T) {
+	mockGet := func(_ context.Context, path string) ([]byte, error) {
+		switch path {
+		case "/projects":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "p1", "name": "Taxes 2026"},
+				{"id": "p2", "name": "Home"},
+			})
+		case "/tasks":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1", "content": "Gather receipts", "description": "for the TAX return", "project_id": "p1"},
+				{"id": "2", "content": "Mow lawn", "project_id": "p2"},
+			})
+		case "/sections":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "s1", "name": "Tax forms", "project_id": "p1"},
+			})
+		case "/labels":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "l1", "name": "errand"},
+			})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}
+	syncGet := func(_ context.Context, path string) ([]byte, error) {
+		if !strings.HasPrefix(path, "/sync?") {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return json.Marshal(map[string]interface{}{
+			"notes": []map[string]interface{}{
+				{"id": "n1", "item_id": "2", "content": "Ask about tax deduction"},
+				{"id": "n2", "item_id": "2", "content": "tax", "is_deleted": true},
+			},
+			"project_notes": []map[string]interface{}{
+				{"id": "n3", "project_id": "p2", "content": "Nothing relevant"},
+			},
+		})
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantErr   bool
+		errSubstr string
+		wantTypes []string
+	}{
+		{
+			name:      "matches across entity types",
+			args:      map[string]interface{}{"query": "tax"},
+			wantTypes: []string{"task", "project", "section", "comment"},
+		},
+		{
+			name:      "restricted to types",
+			args:      map[string]interface{}{"query": "tax", "types": []interface{}{"project", "label"}},
+			wantTypes: []string{"project"},
+		},
+		{
+			name:      "limit per type",
+			args:      map[string]interface{}{"query": "a", "types": []interface{}{"task"}, "limit": float64(1)},
+			wantTypes: []string{"task"},
+		},
+		{
+			name:      "missing query",
+			args:      map[string]interface{}{"query": "  "},
+			wantErr:   true,
+			errSubstr: "query is required",
+		},
+		{
+			name:      "invalid type",
+			args:      map[string]interface{}{"query": "tax", "types": []interface{}{"filter"}},
+			wantErr:   true,
+			errSubstr: "invalid type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := SearchAllHandler(&MockAPI{GetFn: mockGet}, &MockSyncAPI{GetFn: syncGet})
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				Matches []map[string]interface{} `json:"matches"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if len(resp.Matches) != len(tt.wantTypes) {
+				t.Fatalf("got %d matches, want %d: %v", len(resp.Matches), len(tt.wantTypes), resp.Matches)
+			}
+			for i, want := range tt.wantTypes {
+				if resp.Matches[i]["type"] != want {
+					t.Errorf("matches[%d].type = %v, want %s", i, resp.Matches[i]["type"], want)
+				}
+			}
+		})
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
 )
 
 // ListSectionsHandler creates a handler for listing sections.
@@ -18,7 +21,7 @@ func ListSectionsHandler(client todoist.API) func(context.Context, mcp.CallToolR
 		params := url.Values{}
 		if projectID, ok := args["project_id"].(string); ok && projectID != "" {
 			if err := ValidateID(projectID, "project_id"); err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return respond.ErrorFrom(err), nil
 			}
 			params.Set("project_id", projectID)
 		}
@@ -30,25 +33,17 @@ func ListSectionsHandler(client todoist.API) func(context.Context, mcp.CallToolR
 
 		respBody, err := client.Get(ctx, path)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to list sections: %v", err)), nil
+			return respond.Errorf("failed to list sections: %v", err), nil
 		}
 
-		var sections []map[string]interface{}
+		var sections []models.Section
 		if err := json.Unmarshal(respBody, &sections); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse sections: %v", err)), nil
+			return respond.Errorf("failed to parse sections: %v", err), nil
 		}
 
-		response := map[string]interface{}{
-			"count":    len(sections),
-			"sections": sections,
-		}
-
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
-		}
+		response := respond.List("sections", sections)
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
 
@@ -59,12 +54,12 @@ func CreateSectionHandler(client todoist.API) func(context.Context, mcp.CallTool
 
 		name, ok := args["name"].(string)
 		if !ok || name == "" {
-			return mcp.NewToolResultError("name is required"), nil
+			return respond.Error("name is required"), nil
 		}
 
 		projectID, ok := args["project_id"].(string)
 		if !ok || projectID == "" {
-			return mcp.NewToolResultError("project_id is required"), nil
+			return respond.Error("project_id is required"), nil
 		}
 
 		body := map[string]interface{}{
@@ -78,20 +73,15 @@ func CreateSectionHandler(client todoist.API) func(context.Context, mcp.CallTool
 
 		respBody, err := client.Post(ctx, "/sections", body)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to create section: %v", err)), nil
+			return respond.Errorf("failed to create section: %v", err), nil
 		}
 
-		var section map[string]interface{}
+		var section models.Section
 		if err := json.Unmarshal(respBody, &section); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse response: %v", err)), nil
+			return respond.Errorf("failed to parse response: %v", err), nil
 		}
 
-		jsonData, err := json.MarshalIndent(section, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(section), nil
 	}
 }
 
@@ -102,15 +92,15 @@ func UpdateSectionHandler(client todoist.API) func(context.Context, mcp.CallTool
 
 		sectionID, ok := args["section_id"].(string)
 		if !ok || sectionID == "" {
-			return mcp.NewToolResultError("section_id is required"), nil
+			return respond.Error("section_id is required"), nil
 		}
 		if err := ValidateID(sectionID, "section_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
 		name, ok := args["name"].(string)
 		if !ok || name == "" {
-			return mcp.NewToolResultError("name is required"), nil
+			return respond.Error("name is required"), nil
 		}
 
 		body := map[string]interface{}{
@@ -120,20 +110,15 @@ func UpdateSectionHandler(client todoist.API) func(context.Context, mcp.CallTool
 		path := fmt.Sprintf("/sections/%s", sectionID)
 		respBody, err := client.Post(ctx, path, body)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to update section: %v", err)), nil
+			return respond.Errorf("failed to update section: %v", err), nil
 		}
 
-		var section map[string]interface{}
+		var section models.Section
 		if err := json.Unmarshal(respBody, &section); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse response: %v", err)), nil
-		}
-
-		jsonData, err := json.MarshalIndent(section, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			return respond.Errorf("failed to parse response: %v", err), nil
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(section), nil
 	}
 }
 
@@ -144,29 +129,137 @@ func DeleteSectionHandler(client todoist.API) func(context.Context, mcp.CallTool
 
 		sectionID, ok := args["section_id"].(string)
 		if !ok || sectionID == "" {
-			return mcp.NewToolResultError("section_id is required"), nil
+			return respond.Error("section_id is required"), nil
 		}
 		if err := ValidateID(sectionID, "section_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
 		path := fmt.Sprintf("/sections/%s", sectionID)
 		err := client.Delete(ctx, path)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to delete section: %v", err)), nil
+			return respond.Errorf("failed to delete section: %v", err), nil
 		}
 
 		response := map[string]interface{}{
 			"success":    true,
 			"section_id": sectionID,
-			"message":    "Section deleted successfully",
+			"message":    localize("section_deleted"),
 		}
 
-		jsonData, err := json.MarshalIndent(response, "", "  ")
+		return respond.JSON(response), nil
+	}
+}
+
+// BatchCreateSectionsHandler creates a handler that adds several sections to a
+// project, in the given order, with one Sync API request.
+func BatchCreateSectionsHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectID, _ := args["project_id"].(string)
+		if err := ValidateID(projectID, "project_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		rawNames, _ := args["names"].([]interface{})
+		if len(rawNames) == 0 {
+			return respond.Error("names must contain at least one section name"), nil
+		}
+		if len(rawNames) > maxPlanCommands {
+			return respond.Errorf("names exceeds %d entries", maxPlanCommands), nil
+		}
+		names := make([]string, 0, len(rawNames))
+		for i, raw := range rawNames {
+			name, _ := raw.(string)
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return respond.Errorf("names[%d] must be a non-empty string", i), nil
+			}
+			names = append(names, name)
+		}
+		skipExisting := true
+		if v, ok := args["skip_existing"].(bool); ok {
+			skipExisting = v
+		}
+
+		// New sections go after the existing ones so a board keeps its columns.
+		params := url.Values{}
+		params.Set("project_id", projectID)
+		respBody, err := client.Get(ctx, "/sections?"+params.Encode())
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			return respond.Errorf("failed to list sections: %v", err), nil
+		}
+		var existing []models.Section
+		if err := json.Unmarshal(respBody, &existing); err != nil {
+			return respond.Errorf("failed to parse sections: %v", err), nil
+		}
+		existingNames := make(map[string]string, len(existing))
+		nextOrder := 1
+		for _, section := range existing {
+			existingNames[strings.ToLower(section.Name)] = section.ID
+			if section.Order >= nextOrder {
+				nextOrder = section.Order + 1
+			}
+		}
+
+		skipped := make([]map[string]interface{}, 0)
+		var commands []todoist.Command
+		seen := make(map[string]bool)
+		for _, name := range names {
+			key := strings.ToLower(name)
+			if seen[key] {
+				skipped = append(skipped, map[string]interface{}{"name": name, "reason": "duplicate name in request"})
+				continue
+			}
+			if id, ok := existingNames[key]; ok && skipExisting {
+				skipped = append(skipped, map[string]interface{}{"name": name, "id": id, "reason": "already exists"})
+				continue
+			}
+			seen[key] = true
+			commands = append(commands, todoist.Command{
+				Type:   "section_add",
+				UUID:   todoist.GenerateUUID(),
+				TempID: todoist.GenerateTempID(),
+				Args: map[string]interface{}{
+					"name":          name,
+					"project_id":    projectID,
+					"section_order": nextOrder,
+				},
+			})
+			nextOrder++
+		}
+
+		created := make([]map[string]interface{}, 0, len(commands))
+		failed := make([]map[string]interface{}, 0)
+		if len(commands) > 0 {
+			syncResp, err := syncClient.BatchCommands(ctx, commands)
+			if err != nil {
+				return respond.Errorf("failed to create sections: %v", err), nil
+			}
+			for _, cmd := range commands {
+				if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+					created = append(created, map[string]interface{}{
+						"id":    syncResp.TempIDMapping[cmd.TempID],
+						"name":  cmd.Args["name"],
+						"order": cmd.Args["section_order"],
+					})
+				} else {
+					failed = append(failed, map[string]interface{}{
+						"name":  cmd.Args["name"],
+						"error": fmt.Sprintf("%v", syncResp.SyncStatus[cmd.UUID]),
+					})
+				}
+			}
+		}
+
+		response := map[string]interface{}{
+			"project_id": projectID,
+			"created":    created,
+			"skipped":    skipped,
+			"failed":     failed,
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
 )
 
 func TestListSectionsHandler(t *testing.T) {
@@ -275,3 +277,90 @@ func TestDeleteSectionHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestBatchCreateSectionsHandler(t *testing.T) {
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if path != "/sections?project_id=p1" {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return []byte(`[{"id": "s1", "name": "Backlog", "order": 1}, {"id": "s2", "name": "Doing", "order": 4}]`), nil
+	}}
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		wantCreated []string
+		wantSkipped int
+		wantErr     string
+	}{
+		{
+			name:        "skips existing and duplicates",
+			args:        map[string]interface{}{"project_id": "p1", "names": []interface{}{"backlog", "Review", " Done ", "review"}},
+			wantCreated: []string{"Review", "Done"},
+			wantSkipped: 2,
+		},
+		{
+			name:        "skip_existing false",
+			args:        map[string]interface{}{"project_id": "p1", "names": []interface{}{"Backlog"}, "skip_existing": false},
+			wantCreated: []string{"Backlog"},
+		},
+		{
+			name:    "empty name",
+			args:    map[string]interface{}{"project_id": "p1", "names": []interface{}{"Todo", " "}},
+			wantErr: "names[1]",
+		},
+		{
+			name:    "missing project",
+			args:    map[string]interface{}{"names": []interface{}{"Todo"}},
+			wantErr: "project_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []todoist.Command
+			syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+				sent = commands
+				resp := &todoist.SyncResponse{SyncStatus: map[string]interface{}{}, TempIDMapping: map[string]string{}}
+				for i, cmd := range commands {
+					resp.SyncStatus[cmd.UUID] = "ok"
+					resp.TempIDMapping[cmd.TempID] = fmt.Sprintf("new%d", i)
+				}
+				return resp, nil
+			}}
+
+			result, err := BatchCreateSectionsHandler(client, syncClient)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if len(sent) != len(tt.wantCreated) {
+				t.Fatalf("sent %d commands, want %d", len(sent), len(tt.wantCreated))
+			}
+			for i, cmd := range sent {
+				if cmd.Type != "section_add" || cmd.Args["name"] != tt.wantCreated[i] || cmd.Args["section_order"] != 5+i {
+					t.Errorf("command %d = %+v", i, cmd)
+				}
+			}
+			var resp struct {
+				Created []map[string]interface{} `json:"created"`
+				Skipped []map[string]interface{} `json:"skipped"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if len(resp.Created) != len(tt.wantCreated) || resp.Created[0]["id"] != "new0" || len(resp.Skipped) != tt.wantSkipped {
+				t.Errorf("response = %s", text)
+			}
+		})
+	}
+}
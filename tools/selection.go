@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// maxTaskPages bounds how many cursor pages fetchTasks follows.
+const maxTaskPages = 50
+
+// fetchTasks GETs /tasks with params. The REST v2 endpoint returns a plain
+// array; paginated responses ({"results": [...], "next_cursor": "..."}) are
+// followed until the cursor runs out.
+func fetchTasks(ctx context.Context, client todoist.API, params url.Values) ([]models.Task, error) {
+	var tasks []models.Task
+	query := url.Values{}
+	for k, v := range params {
+		query[k] = v
+	}
+
+	for page := 0; page < maxTaskPages; page++ {
+		path := "/tasks"
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+		respBody, err := client.Get(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+		}
+
+		if trimmed := strings.TrimSpace(string(respBody)); !strings.HasPrefix(trimmed, "{") {
+			var list []models.Task
+			if err := json.Unmarshal(respBody, &list); err != nil {
+				return nil, fmt.Errorf("failed to parse tasks: %w", err)
+			}
+			return append(tasks, list...), nil
+		}
+
+		var paged struct {
+			Results    []models.Task `json:"results"`
+			NextCursor string        `json:"next_cursor"`
+		}
+		if err := json.Unmarshal(respBody, &paged); err != nil {
+			return nil, fmt.Errorf("failed to parse tasks: %w", err)
+		}
+		tasks = append(tasks, paged.Results...)
+		if paged.NextCursor == "" {
+			return tasks, nil
+		}
+		query.Set("cursor", paged.NextCursor)
+	}
+	return tasks, nil
+}
+
+// resolveFilterTasks returns the active tasks matching a Todoist filter.
+func resolveFilterTasks(ctx context.Context, client todoist.API, filter string) ([]models.Task, error) {
+	params := url.Values{}
+	params.Set("filter", filter)
+	return fetchTasks(ctx, client, params)
+}
+
+// taskIDsArg reads a task_ids array argument.
+func taskIDsArg(args map[string]interface{}) []string {
+	raw, _ := args["task_ids"].([]interface{})
+	ids := make([]string, 0, len(raw))
+	for _, id := range raw {
+		if s, ok := id.(string); ok && s != "" {
+			ids = append(ids, s)
+		}
+	}
+	return ids
+}
+
+// selectBulkTaskIDs resolves the task selection of a confirmation-gated bulk
+// tool. task_ids take precedence. A filter without a confirmation_token yields
+// a preview result that the handler must return as-is; with a token, the IDs
+// captured at preview time are used. scopeParts are the arguments, besides the
+// filter, that the token is bound to. A non-nil result is always returned
+// directly, and is either the preview or a tool error. With estimate_cost the
+// filter is resolved directly, and tokens are neither issued nor redeemed, so
+// the estimate does not use up a pending confirmation.
+func selectBulkTaskIDs(ctx context.Context, client todoist.API, confirmations *ConfirmationStore, args map[string]interface{}, tool string, scopeParts ...string) ([]string, *mcp.CallToolResult) {
+	if ids := taskIDsArg(args); len(ids) > 0 {
+		return ids, nil
+	}
+
+	filter, _ := args["filter"].(string)
+	if filter == "" {
+		return nil, respond.Error("either task_ids or filter must be provided and match at least one task")
+	}
+
+	if estimateCostRequested(args) {
+		tasks, err := resolveFilterTasks(ctx, client, filter)
+		if err != nil {
+			return nil, respond.ErrorFrom(err)
+		}
+		ids := make([]string, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+		return ids, nil
+	}
+
+	scope := confirmationScope(tool, append([]string{filter}, scopeParts...)...)
+	if token, _ := args["confirmation_token"].(string); token != "" {
+		ids, err := confirmations.redeem(token, scope)
+		if err != nil {
+			return nil, respond.ErrorFrom(err)
+		}
+		if len(ids) == 0 {
+			return nil, respond.Error("either task_ids or filter must be provided and match at least one task")
+		}
+		return ids, nil
+	}
+
+	tasks, err := resolveFilterTasks(ctx, client, filter)
+	if err != nil {
+		return nil, respond.ErrorFrom(err)
+	}
+	if len(tasks) == 0 {
+		return nil, respond.Error("either task_ids or filter must be provided and match at least one task")
+	}
+	preview, _ := confirmationPreviewResult(confirmations, tool, scope, tasks)
+	return nil, preview
+}
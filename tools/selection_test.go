@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchTasks(t *testing.T) {
+	t.Run("plain array", func(t *testing.T) {
+		client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if path != "/tasks?filter=today" {
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			}
+			return []byte(`[{"id": "1"}, {"id": "2"}]`), nil
+		}}
+		tasks, err := resolveFilterTasks(context.Background(), client, "today")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tasks) != 2 {
+			t.Errorf("got %d tasks, want 2", len(tasks))
+		}
+	})
+
+	t.Run("cursor pagination", func(t *testing.T) {
+		var paths []string
+		client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+			paths = append(paths, path)
+			u, _ := url.Parse(path)
+			switch u.Query().Get("cursor") {
+			case "":
+				return []byte(`{"results": [{"id": "1"}, {"id": "2"}], "next_cursor": "c2"}`), nil
+			case "c2":
+				return []byte(`{"results": [{"id": "3"}], "next_cursor": null}`), nil
+			}
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}}
+		params := url.Values{}
+		params.Set("filter", "@work")
+		tasks, err := fetchTasks(context.Background(), client, params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tasks) != 3 || len(paths) != 2 {
+			t.Errorf("got %d tasks over %d requests, want 3 over 2", len(tasks), len(paths))
+		}
+		if !strings.Contains(paths[1], "filter=%40work") {
+			t.Errorf("second request %q lost the filter", paths[1])
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		client := &MockAPI{GetFn: func(_ context.Context, _ string) ([]byte, error) {
+			return nil, fmt.Errorf("boom")
+		}}
+		if _, err := resolveFilterTasks(context.Background(), client, "today"); err == nil || !strings.Contains(err.Error(), "failed to fetch tasks") {
+			t.Errorf("error = %v, want failed to fetch tasks", err)
+		}
+	})
+}
+
+func TestSelectBulkTaskIDs(t *testing.T) {
+	client := &MockAPI{GetFn: func(_ context.Context, _ string) ([]byte, error) {
+		return json.Marshal([]map[string]interface{}{{"id": "7", "content": "Matched"}})
+	}}
+	empty := &MockAPI{GetFn: func(_ context.Context, _ string) ([]byte, error) {
+		return []byte(`[]`), nil
+	}}
+	store := NewConfirmationStore(time.Minute)
+
+	ids, result := selectBulkTaskIDs(context.Background(), client, store, map[string]interface{}{
+		"task_ids": []interface{}{"1", "2"}, "filter": "ignored",
+	}, "bulk_complete_tasks")
+	if result != nil || strings.Join(ids, ",") != "1,2" {
+		t.Errorf("task_ids selection = %v, %v", ids, result)
+	}
+
+	_, result = selectBulkTaskIDs(context.Background(), client, store, map[string]interface{}{"filter": "today"}, "move_tasks", "p1")
+	if result == nil || result.IsError {
+		t.Fatalf("expected preview, got %v", result)
+	}
+	var preview struct {
+		Token string `json:"confirmation_token"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &preview); err != nil {
+		t.Fatalf("failed to parse preview: %v", err)
+	}
+
+	_, result = selectBulkTaskIDs(context.Background(), client, store, map[string]interface{}{"filter": "today", "confirmation_token": preview.Token}, "move_tasks", "p2")
+	if result == nil || !strings.Contains(resultText(result), "different operation") {
+		t.Errorf("token with different scope = %v", resultText(result))
+	}
+
+	_, result = selectBulkTaskIDs(context.Background(), client, store, map[string]interface{}{"filter": "today"}, "move_tasks", "p1")
+	_ = json.Unmarshal([]byte(resultText(result)), &preview)
+	ids, result = selectBulkTaskIDs(context.Background(), client, store, map[string]interface{}{"filter": "today", "confirmation_token": preview.Token}, "move_tasks", "p1")
+	if result != nil || strings.Join(ids, ",") != "7" {
+		t.Errorf("confirmed selection = %v, %v", ids, result)
+	}
+
+	_, result = selectBulkTaskIDs(context.Background(), empty, store, map[string]interface{}{"filter": "today"}, "move_tasks", "p1")
+	if result == nil || !result.IsError || !strings.Contains(resultText(result), "match at least one task") {
+		t.Errorf("empty filter result = %v", result)
+	}
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// selfTestStep is the outcome of one run_self_test operation.
+type selfTestStep struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// RunSelfTestHandler creates a handler that exercises the main write paths
+// end to end on a throwaway Inbox task: create, update, comment, complete,
+// and delete. Each step runs only if the task exists; the task is deleted
+// even when an earlier step fails.
+func RunSelfTestHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		label, _ := args["label"].(string)
+		if label == "" {
+			label = "mcp-self-test"
+		}
+
+		steps := make([]selfTestStep, 0, 5)
+		run := func(name string, fn func() error) bool {
+			start := time.Now()
+			err := fn()
+			step := selfTestStep{Name: name, Status: "ok", DurationMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				step.Status = "failed"
+				step.Error = err.Error()
+			}
+			steps = append(steps, step)
+			return err == nil
+		}
+		skip := func(names ...string) {
+			for _, name := range names {
+				steps = append(steps, selfTestStep{Name: name, Status: "skipped"})
+			}
+		}
+
+		content := fmt.Sprintf("mcp-todoist self-test %s", time.Now().UTC().Format(time.RFC3339))
+		var taskID string
+		created := run("create_task", func() error {
+			respBody, err := client.Post(ctx, "/tasks", map[string]interface{}{
+				"content":     content,
+				"description": "Created by run_self_test and deleted when the test finishes.",
+				"labels":      []string{label},
+			})
+			if err != nil {
+				return err
+			}
+			var task struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(respBody, &task); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			if task.ID == "" {
+				return fmt.Errorf("response has no task id")
+			}
+			taskID = task.ID
+			return nil
+		})
+
+		if created {
+			run("update_task", func() error {
+				_, err := client.Post(ctx, fmt.Sprintf("/tasks/%s", taskID), map[string]interface{}{"content": content + " (updated)"})
+				return err
+			})
+			run("add_comment", func() error {
+				_, err := client.Post(ctx, "/comments", map[string]interface{}{"task_id": taskID, "content": "run_self_test comment"})
+				return err
+			})
+			run("complete_task", func() error {
+				_, err := client.Post(ctx, fmt.Sprintf("/tasks/%s/close", taskID), nil)
+				return err
+			})
+			run("delete_task", func() error {
+				return client.Delete(ctx, fmt.Sprintf("/tasks/%s", taskID))
+			})
+		} else {
+			skip("update_task", "add_comment", "complete_task", "delete_task")
+		}
+
+		passed := 0
+		for _, step := range steps {
+			if step.Status == "ok" {
+				passed++
+			}
+		}
+		response := map[string]interface{}{
+			"passed": passed == len(steps),
+			"steps":  steps,
+			"label":  label,
+		}
+		if taskID != "" {
+			response["task_id"] = taskID
+		}
+		switch {
+		case passed == len(steps):
+			response["message"] = "All self-test operations succeeded"
+		case steps[len(steps)-1].Name == "delete_task" && steps[len(steps)-1].Status == "failed":
+			response["message"] = fmt.Sprintf("%d of %d operations succeeded; the test task %s could not be deleted, remove it from the Inbox by hand", passed, len(steps), taskID)
+		default:
+			response["message"] = fmt.Sprintf("%d of %d operations succeeded", passed, len(steps))
+		}
+
+		return respond.JSON(response), nil
+	}
+}
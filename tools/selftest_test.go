@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRunSelfTestHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		failPath   string
+		failDelete bool
+		wantPassed bool
+		wantStatus map[string]string
+		wantMsg    string
+	}{
+		{
+			name:       "all steps succeed",
+			wantPassed: true,
+			wantStatus: map[string]string{"create_task": "ok", "update_task": "ok", "add_comment": "ok", "complete_task": "ok", "delete_task": "ok"},
+			wantMsg:    "All self-test operations succeeded",
+		},
+		{
+			name:       "create fails and skips the rest",
+			failPath:   "/tasks",
+			wantStatus: map[string]string{"create_task": "failed", "update_task": "skipped", "delete_task": "skipped"},
+			wantMsg:    "0 of 5 operations succeeded",
+		},
+		{
+			name:       "comment fails but task is still deleted",
+			failPath:   "/comments",
+			wantStatus: map[string]string{"add_comment": "failed", "complete_task": "ok", "delete_task": "ok"},
+			wantMsg:    "4 of 5 operations succeeded",
+		},
+		{
+			name:       "delete fails",
+			failDelete: true,
+			wantStatus: map[string]string{"delete_task": "failed"},
+			wantMsg:    "could not be deleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var createBody map[string]interface{}
+			var deleted []string
+			client := &MockAPI{
+				PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+					if path == tt.failPath {
+						return nil, fmt.Errorf("API error (status 500)")
+					}
+					if path == "/tasks" {
+						createBody = body.(map[string]interface{})
+						return json.Marshal(map[string]interface{}{"id": "99"})
+					}
+					return []byte(`{}`), nil
+				},
+				DeleteFn: func(_ context.Context, path string) error {
+					if tt.failDelete {
+						return fmt.Errorf("API error (status 500)")
+					}
+					deleted = append(deleted, path)
+					return nil
+				},
+			}
+
+			result, err := RunSelfTestHandler(client)(context.Background(), makeReq(nil))
+			if err != nil || result.IsError {
+				t.Fatalf("unexpected error: %v %s", err, resultText(result))
+			}
+			var resp struct {
+				Passed  bool           `json:"passed"`
+				Steps   []selfTestStep `json:"steps"`
+				Message string         `json:"message"`
+			}
+			if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+
+			if resp.Passed != tt.wantPassed {
+				t.Errorf("passed = %v, want %v", resp.Passed, tt.wantPassed)
+			}
+			if len(resp.Steps) != 5 {
+				t.Fatalf("got %d steps, want 5", len(resp.Steps))
+			}
+			for _, step := range resp.Steps {
+				if want, ok := tt.wantStatus[step.Name]; ok && step.Status != want {
+					t.Errorf("step %s status = %q, want %q", step.Name, step.Status, want)
+				}
+			}
+			if !strings.Contains(resp.Message, tt.wantMsg) {
+				t.Errorf("message = %q, want it to contain %q", resp.Message, tt.wantMsg)
+			}
+			if tt.failPath != "/tasks" {
+				if labels, _ := createBody["labels"].([]string); len(labels) != 1 || labels[0] != "mcp-self-test" {
+					t.Errorf("create labels = %v, want [mcp-self-test]", createBody["labels"])
+				}
+				if _, ok := createBody["project_id"]; ok {
+					t.Error("self-test task should go to the Inbox")
+				}
+			}
+			if !tt.failDelete && tt.failPath != "/tasks" && (len(deleted) != 1 || deleted[0] != "/tasks/99") {
+				t.Errorf("deleted = %v, want [/tasks/99]", deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// ServerInfo is the deployment configuration reported by get_server_info.
+type ServerInfo struct {
+	Version string
+	// Profile is the TOOL_PROFILE in effect.
+	Profile string
+	// Transports lists how the server is reachable, e.g. "stdio".
+	Transports         []string
+	RateLimitWindow    time.Duration
+	RateLimitMax       int
+	MaxInFlight        int
+	RateLimitStateFile string
+	// TokenScopes are the declared OAuth scopes; empty for a personal token.
+	TokenScopes []string
+	// RefusedTools lists tools blocked after repeated 403 responses.
+	RefusedTools func() []string
+	// ReferenceCacheTTL is how long projects, labels, and sections are
+	// cached; zero when nothing is cached.
+	ReferenceCacheTTL time.Duration
+}
+
+// GetServerInfoHandler creates a handler that reports the server version,
+// registered tools, transports, cache status, rate limit configuration, and
+// the Todoist API variants in use. listTools returns the registered tool names.
+func GetServerInfoHandler(info ServerInfo, client todoist.API, listTools func() []string) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		names := listTools()
+		slices.Sort(names)
+
+		rateLimit := map[string]interface{}{
+			"window":        info.RateLimitWindow.String(),
+			"max_requests":  info.RateLimitMax,
+			"remaining":     client.GetRemainingRequests(),
+			"max_in_flight": info.MaxInFlight,
+			"persisted":     info.RateLimitStateFile != "",
+		}
+		if info.RateLimitStateFile != "" {
+			rateLimit["state_file"] = info.RateLimitStateFile
+		}
+
+		token := map[string]interface{}{"scopes": "all (personal token)"}
+		if len(info.TokenScopes) > 0 {
+			token["scopes"] = info.TokenScopes
+		}
+		if info.RefusedTools != nil {
+			token["refused_tools"] = info.RefusedTools()
+		}
+
+		// Other responses are not cached: their tool calls read live data.
+		cache := map[string]interface{}{"enabled": info.ReferenceCacheTTL > 0}
+		if info.ReferenceCacheTTL > 0 {
+			cache["ttl"] = info.ReferenceCacheTTL.String()
+			cache["entries"] = []string{"projects", "labels", "sections"}
+		}
+
+		response := map[string]interface{}{
+			"token":      token,
+			"version":    info.Version,
+			"profile":    info.Profile,
+			"transports": info.Transports,
+			"tools": map[string]interface{}{
+				"count": len(names),
+				"names": names,
+			},
+			"cache":      cache,
+			"rate_limit": rateLimit,
+			"apis":       todoist.APIEndpoints(),
+		}
+
+		return respond.JSON(response), nil
+	}
+}
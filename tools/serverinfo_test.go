@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestGetServerInfoHandler(t *testing.T) {
+	info := ServerInfo{
+		Version:         "1.2.3",
+		Transports:      []string{"stdio"},
+		RateLimitWindow: 15 * time.Minute,
+		RateLimitMax:    450,
+		MaxInFlight:     4,
+	}
+	client := &MockAPI{GetRemainingRequestsFn: func() int { return 120 }}
+	listTools := func() []string { return []string{"search_tasks", "get_server_info", "create_task"} }
+
+	result, err := GetServerInfoHandler(info, client, listTools)(context.Background(), makeReq(nil))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+
+	var resp struct {
+		Version    string   `json:"version"`
+		Transports []string `json:"transports"`
+		Tools      struct {
+			Count int      `json:"count"`
+			Names []string `json:"names"`
+		} `json:"tools"`
+		Cache struct {
+			Enabled bool `json:"enabled"`
+		} `json:"cache"`
+		RateLimit struct {
+			Window      string `json:"window"`
+			MaxRequests int    `json:"max_requests"`
+			Remaining   int    `json:"remaining"`
+			MaxInFlight int    `json:"max_in_flight"`
+			Persisted   bool   `json:"persisted"`
+		} `json:"rate_limit"`
+		APIs []struct {
+			Name    string `json:"name"`
+			BaseURL string `json:"base_url"`
+		} `json:"apis"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if resp.Version != "1.2.3" || len(resp.Transports) != 1 || resp.Transports[0] != "stdio" {
+		t.Errorf("version/transports = %q %v", resp.Version, resp.Transports)
+	}
+	if resp.Tools.Count != 3 || resp.Tools.Names[0] != "create_task" || resp.Tools.Names[2] != "search_tasks" {
+		t.Errorf("tools = %+v, want 3 sorted names", resp.Tools)
+	}
+	if resp.Cache.Enabled {
+		t.Error("cache should be reported as disabled")
+	}
+	if resp.RateLimit.Window != "15m0s" || resp.RateLimit.MaxRequests != 450 || resp.RateLimit.Remaining != 120 ||
+		resp.RateLimit.MaxInFlight != 4 || resp.RateLimit.Persisted {
+		t.Errorf("rate_limit = %+v", resp.RateLimit)
+	}
+	if len(resp.APIs) == 0 || resp.APIs[0].BaseURL == "" {
+		t.Errorf("apis = %+v, want the Todoist API variants", resp.APIs)
+	}
+}
+
+func TestGetServerInfoHandler_ReferenceCache(t *testing.T) {
+	info := ServerInfo{ReferenceCacheTTL: time.Minute}
+	client := &MockAPI{GetRemainingRequestsFn: func() int { return 450 }}
+	result, err := GetServerInfoHandler(info, client, func() []string { return nil })(context.Background(), makeReq(nil))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	var resp struct {
+		Cache struct {
+			Enabled bool   `json:"enabled"`
+			TTL     string `json:"ttl"`
+		} `json:"cache"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !resp.Cache.Enabled || resp.Cache.TTL != "1m0s" {
+		t.Errorf("cache = %+v, want enabled with a 1m0s ttl", resp.Cache)
+	}
+}
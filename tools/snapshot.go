@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+)
+
+// maxSnapshotOverdue bounds the overdue tasks listed in a weekly snapshot.
+const maxSnapshotOverdue = 20
+
+// WeeklySnapshot posts a comment with last week's statistics on a project
+// once per week, so the history accumulates inside Todoist itself.
+type WeeklySnapshot struct {
+	client     todoist.API
+	syncClient todoist.SyncAPI
+	projectID  string
+
+	mu     sync.Mutex
+	posted string // the last week known to have a snapshot, e.g. "2026-W41"
+}
+
+// NewWeeklySnapshot creates the snapshot job for the project with projectID.
+func NewWeeklySnapshot(client todoist.API, syncClient todoist.SyncAPI, projectID string) *WeeklySnapshot {
+	return &WeeklySnapshot{client: client, syncClient: syncClient, projectID: projectID}
+}
+
+// Automation checks hourly whether last week's snapshot is due.
+func (w *WeeklySnapshot) Automation() Automation {
+	return Automation{Name: "weekly_snapshot", Every: time.Hour, Run: w.Run}
+}
+
+// snapshotWeek returns the ISO week before the one containing now, e.g.
+// "2026-W41", with its Monday and the following Monday.
+func snapshotWeek(now time.Time) (string, time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Weekdays count from Sunday; weeks here start on Monday.
+	thisMonday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	start := thisMonday.AddDate(0, 0, -7)
+	year, week := start.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week), start, thisMonday
+}
+
+// snapshotHeading starts every snapshot comment, in bold, and identifies its
+// week.
+func snapshotHeading(week string) string {
+	return "Weekly snapshot " + week
+}
+
+// Run posts the snapshot for last week unless the project already has one.
+// Existing comments are checked, so restarts never post a week twice.
+func (w *WeeklySnapshot) Run(ctx context.Context) error {
+	loc, err := userLocation(ctx, w.syncClient)
+	if err != nil {
+		return fmt.Errorf("failed to resolve user time zone: %w", err)
+	}
+	week, start, end := snapshotWeek(time.Now().In(loc))
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.posted == week {
+		return nil
+	}
+
+	params := url.Values{}
+	params.Set("project_id", w.projectID)
+	respBody, err := w.client.Get(ctx, "/comments?"+params.Encode())
+	if err != nil {
+		return fmt.Errorf("failed to get comments: %w", err)
+	}
+	var comments []models.Comment
+	if err := json.Unmarshal(respBody, &comments); err != nil {
+		return fmt.Errorf("failed to parse comments: %w", err)
+	}
+	for _, comment := range comments {
+		if strings.HasPrefix(strings.TrimLeft(comment.Content, "*"), snapshotHeading(week)) {
+			w.posted = week
+			return nil
+		}
+	}
+
+	completed, _, err := fetchCompletedTasks(ctx, w.syncClient, start, end, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch completed tasks: %w", err)
+	}
+	overdue, err := resolveFilterTasks(ctx, w.client, "overdue")
+	if err != nil {
+		return err
+	}
+	projectNames, err := fetchProjectNames(ctx, w.client)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]interface{}{
+		"project_id": w.projectID,
+		"content":    renderWeeklySnapshot(week, start, end, completed, overdue, projectNames),
+	}
+	if _, err := w.client.Post(ctx, "/comments", body); err != nil {
+		return fmt.Errorf("failed to post snapshot: %w", err)
+	}
+	w.posted = week
+	return nil
+}
+
+// fetchProjectNames maps project IDs to names.
+func fetchProjectNames(ctx context.Context, client todoist.API) (map[string]string, error) {
+	respBody, err := client.Get(ctx, "/projects")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch projects: %w", err)
+	}
+	var projects []models.Project
+	if err := json.Unmarshal(respBody, &projects); err != nil {
+		return nil, fmt.Errorf("failed to parse projects: %w", err)
+	}
+	names := make(map[string]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+	return names, nil
+}
+
+// renderWeeklySnapshot renders the snapshot comment as Markdown: completions
+// per project for the week from start to end, and the tasks overdue now.
+func renderWeeklySnapshot(week string, start, end time.Time, completed, overdue []models.Task, projectNames map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s** (%s – %s)\n\n", snapshotHeading(week), start.Format("Jan 2"), end.AddDate(0, 0, -1).Format("Jan 2"))
+
+	fmt.Fprintf(&b, "Completed: %d\n", len(completed))
+	perProject := make(map[string]int)
+	for _, item := range completed {
+		name, ok := projectNames[item.ProjectID]
+		if !ok {
+			name = "Other"
+		}
+		perProject[name]++
+	}
+	names := make([]string, 0, len(perProject))
+	for name := range perProject {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if perProject[names[i]] != perProject[names[j]] {
+			return perProject[names[i]] > perProject[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		fmt.Fprintf(&b, "- %s: %d\n", name, perProject[name])
+	}
+
+	sort.SliceStable(overdue, func(i, j int) bool { return overdue[i].Due.Day() < overdue[j].Due.Day() })
+	fmt.Fprintf(&b, "\nOverdue now: %d\n", len(overdue))
+	for i, task := range overdue {
+		if i == maxSnapshotOverdue {
+			fmt.Fprintf(&b, "- ... and %d more\n", len(overdue)-i)
+			break
+		}
+		fmt.Fprintf(&b, "- %s (due %s)\n", task.Content, task.Due.Day())
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotWeek(t *testing.T) {
+	for _, now := range []time.Time{
+		time.Date(2026, 10, 12, 0, 30, 0, 0, time.UTC), // Monday
+		time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC), // Sunday
+	} {
+		week, start, end := snapshotWeek(now)
+		if week != "2026-W41" || start.Format("2006-01-02") != "2026-10-05" || end.Format("2006-01-02") != "2026-10-12" {
+			t.Errorf("snapshotWeek(%s) = %s, %s, %s", now.Weekday(), week, start, end)
+		}
+	}
+}
+
+func TestWeeklySnapshot_Run(t *testing.T) {
+	var posted []string
+	comments := `[]`
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			switch {
+			case strings.HasPrefix(path, "/comments?"):
+				return []byte(comments), nil
+			case strings.Contains(path, "filter=overdue"):
+				return []byte(`[{"id": "1", "content": "Pay rent", "due": {"date": "2026-01-01"}}]`), nil
+			case path == "/projects":
+				return []byte(`[{"id": "9", "name": "Work"}]`), nil
+			}
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		},
+		PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+			if path != "/comments" {
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			}
+			posted = append(posted, body.(map[string]interface{})["content"].(string))
+			return []byte(`{"id": "c1"}`), nil
+		},
+	}
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		switch {
+		case path == "/user":
+			return []byte(`{"tz_info": {"timezone": "UTC"}}`), nil
+		case strings.HasPrefix(path, "/tasks/completed/"):
+			return []byte(`{"items": [{"content": "a", "project_id": "9"}, {"content": "b", "project_id": "9"}, {"content": "c", "project_id": "0"}]}`), nil
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}}
+
+	job := NewWeeklySnapshot(client, syncClient, "9")
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(posted) != 1 {
+		t.Fatalf("posted %d snapshots, want 1", len(posted))
+	}
+	for _, want := range []string{"**Weekly snapshot ", "Completed: 3\n- Work: 2\n- Other: 1\n", "Overdue now: 1\n- Pay rent (due 2026-01-01)"} {
+		if !strings.Contains(posted[0], want) {
+			t.Errorf("snapshot missing %q:\n%s", want, posted[0])
+		}
+	}
+
+	// A restarted server finds the week's comment and does not post again.
+	comments = fmt.Sprintf(`[{"content": %q}]`, posted[0])
+	if err := NewWeeklySnapshot(client, syncClient, "9").Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(posted) != 1 {
+		t.Errorf("posted %d snapshots, want 1", len(posted))
+	}
+}
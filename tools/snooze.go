@@ -0,0 +1,247 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// snoozeLabel marks tasks snoozed by snooze_task.
+const snoozeLabel = "snoozed"
+
+// noDueDate is recorded as the original date of a task that had no due date.
+const noDueDate = "none"
+
+// snoozePattern matches the comment left by snooze_task, e.g.
+// "💤 Snoozed from 2026-03-02 until 2026-03-05 — waiting on vendor".
+var snoozePattern = regexp.MustCompile(`^💤 Snoozed from (\S+) until (\S+)(?: — (.+))?$`)
+
+// formatSnooze builds the comment recording a snooze.
+func formatSnooze(original, until, reason string) string {
+	content := fmt.Sprintf("💤 Snoozed from %s until %s", original, until)
+	if reason != "" {
+		content += " — " + reason
+	}
+	return content
+}
+
+// SnoozeTaskHandler creates a handler that moves a task's due date forward and
+// records the original date in a comment.
+func SnoozeTaskHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		taskID, _ := args["task_id"].(string)
+		if err := ValidateID(taskID, "task_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		untilArg, _ := args["until"].(string)
+		days, hasDays := args["days"].(float64)
+		if (untilArg == "") == !hasDays {
+			return respond.Error("exactly one of until or days is required"), nil
+		}
+		var until time.Time
+		if untilArg != "" {
+			t, err := time.Parse("2006-01-02", untilArg)
+			if err != nil {
+				return respond.Error("until must be a YYYY-MM-DD date"), nil
+			}
+			until = t
+		} else {
+			if days < 1 || days > 365 {
+				return respond.Error("days must be between 1 and 365"), nil
+			}
+			until = time.Now().AddDate(0, 0, int(days))
+		}
+		reason, _ := args["reason"].(string)
+		reason = strings.Join(strings.Fields(reason), " ")
+
+		respBody, err := client.Get(ctx, fmt.Sprintf("/tasks/%s", taskID))
+		if err != nil {
+			return respond.Errorf("failed to get task: %v", err), nil
+		}
+		var task models.Task
+		if err := json.Unmarshal(respBody, &task); err != nil {
+			return respond.Errorf("failed to parse task: %v", err), nil
+		}
+
+		// Keep the time of day for timed tasks; REST reports it separately in
+		// due.datetime, while the Sync API takes it as part of due.date.
+		original, timeOfDay := noDueDate, ""
+		if due := task.Due; due != nil {
+			if due.IsRecurring {
+				return respond.Error("recurring tasks cannot be snoozed; changing the date would break the recurrence"), nil
+			}
+			if len(due.Datetime) > 10 {
+				original, timeOfDay = due.Datetime, due.Datetime[10:]
+			} else if due.Date != "" {
+				original = due.Date
+			}
+		}
+		newDue := until.Format("2006-01-02") + timeOfDay
+		if original != noDueDate && newDue <= original {
+			return respond.Errorf("snooze date must be after the current due date %s", original[:10]), nil
+		}
+
+		labels := []string{snoozeLabel}
+		for _, name := range task.Labels {
+			if name != snoozeLabel {
+				labels = append(labels, name)
+			}
+		}
+
+		content := formatSnooze(original, newDue, reason)
+		commands := []todoist.Command{
+			{
+				Type: "item_update",
+				UUID: todoist.GenerateUUID(),
+				Args: map[string]interface{}{
+					"id":     taskID,
+					"due":    map[string]interface{}{"date": newDue},
+					"labels": labels,
+				},
+			},
+			{
+				Type:   "note_add",
+				UUID:   todoist.GenerateUUID(),
+				TempID: todoist.GenerateTempID(),
+				Args: map[string]interface{}{
+					"item_id": taskID,
+					"content": content,
+				},
+			},
+		}
+
+		syncResp, err := syncClient.BatchCommands(ctx, commands)
+		if err != nil {
+			return respond.Errorf("failed to snooze task: %v", err), nil
+		}
+		for _, cmd := range commands {
+			if status, ok := syncResp.SyncStatus[cmd.UUID].(string); !ok || status != "ok" {
+				return respond.Errorf("failed to snooze task: %s: %v", cmd.Type, syncResp.SyncStatus[cmd.UUID]), nil
+			}
+		}
+
+		response := map[string]interface{}{
+			"task_id":       taskID,
+			"original_due":  original,
+			"snoozed_until": newDue,
+			"comment":       content,
+			"message":       localize("task_snoozed", formatLongDate(until)),
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// ListSnoozedHandler creates a handler that lists snoozed tasks and optionally
+// restores their original due dates.
+func ListSnoozedHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		restore, _ := args["restore"].(bool)
+		only := taskIDsArg(args)
+
+		resources, err := fetchSyncResources(ctx, syncClient, "items", "notes")
+		if err != nil {
+			return respond.Errorf("failed to fetch tasks: %v", err), nil
+		}
+		items, err := decodeSyncTasks(resources["items"])
+		if err != nil {
+			return respond.Errorf("failed to parse tasks: %v", err), nil
+		}
+		notes, err := decodeSyncObjects(resources["notes"])
+		if err != nil {
+			return respond.Errorf("failed to parse comments: %v", err), nil
+		}
+
+		// The latest snooze comment on a task holds its original date.
+		sort.SliceStable(notes, func(i, j int) bool {
+			a, _ := notes[i]["posted_at"].(string)
+			b, _ := notes[j]["posted_at"].(string)
+			return a < b
+		})
+		latest := make(map[string][]string)
+		for _, note := range notes {
+			content, _ := note["content"].(string)
+			if m := snoozePattern.FindStringSubmatch(strings.TrimSpace(content)); m != nil {
+				latest[fmt.Sprint(note["item_id"])] = m[1:]
+			}
+		}
+
+		snoozed := make([]map[string]interface{}, 0)
+		var ops []todoist.BulkOperation
+		for _, item := range items {
+			id := item.ID
+			if !taskHasLabel(item, snoozeLabel) || (len(only) > 0 && !slices.Contains(only, id)) {
+				continue
+			}
+			entry := map[string]interface{}{
+				"task_id":     id,
+				"content":     item.Content,
+				"current_due": nil,
+			}
+			if item.Due != nil {
+				entry["current_due"] = item.Due.Date
+			}
+			record, ok := latest[id]
+			if !ok {
+				entry["original_due"] = nil
+				snoozed = append(snoozed, entry)
+				continue
+			}
+			entry["original_due"] = record[0]
+			entry["snoozed_until"] = record[1]
+			if record[2] != "" {
+				entry["reason"] = record[2]
+			}
+			snoozed = append(snoozed, entry)
+
+			if restore {
+				var due interface{}
+				if record[0] != noDueDate {
+					due = map[string]interface{}{"date": record[0]}
+				}
+				labels := make([]string, 0)
+				for _, name := range item.Labels {
+					if name != snoozeLabel {
+						labels = append(labels, name)
+					}
+				}
+				ops = append(ops, todoist.BulkOperation{
+					ID: id,
+					Command: todoist.Command{
+						Type: "item_update",
+						UUID: todoist.GenerateUUID(),
+						Args: map[string]interface{}{"id": id, "due": due, "labels": labels},
+					},
+				})
+			}
+		}
+
+		response := respond.List("tasks", snoozed)
+
+		if restore {
+			result, err := todoist.NewBulkExecutor(client, syncClient).Execute(ctx, ops)
+			if err != nil {
+				return respond.Errorf("failed to restore tasks: %v", err), nil
+			}
+			response["restored"] = len(result.Succeeded)
+			response["restored_task_ids"] = result.Succeeded
+			response["failed"] = result.Failed
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestSnoozeTaskHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		task        string
+		args        map[string]interface{}
+		wantDue     string
+		wantComment string
+		wantErr     string
+	}{
+		{
+			name:        "dated task",
+			task:        `{"id": "t1", "due": {"date": "2026-03-02"}, "labels": ["work"]}`,
+			args:        map[string]interface{}{"task_id": "t1", "until": "2026-03-05", "reason": "waiting on vendor"},
+			wantDue:     "2026-03-05",
+			wantComment: "💤 Snoozed from 2026-03-02 until 2026-03-05 — waiting on vendor",
+		},
+		{
+			name:        "timed task keeps time of day",
+			task:        `{"id": "t1", "due": {"date": "2026-03-02", "datetime": "2026-03-02T09:00:00Z"}}`,
+			args:        map[string]interface{}{"task_id": "t1", "until": "2026-03-04"},
+			wantDue:     "2026-03-04T09:00:00Z",
+			wantComment: "💤 Snoozed from 2026-03-02T09:00:00Z until 2026-03-04T09:00:00Z",
+		},
+		{
+			name:        "undated task",
+			task:        `{"id": "t1"}`,
+			args:        map[string]interface{}{"task_id": "t1", "until": "2026-03-04"},
+			wantDue:     "2026-03-04",
+			wantComment: "💤 Snoozed from none until 2026-03-04",
+		},
+		{
+			name:    "recurring task",
+			task:    `{"id": "t1", "due": {"date": "2026-03-02", "is_recurring": true}}`,
+			args:    map[string]interface{}{"task_id": "t1", "until": "2026-03-04"},
+			wantErr: "recurring tasks cannot be snoozed",
+		},
+		{
+			name:    "date not in the future of due",
+			task:    `{"id": "t1", "due": {"date": "2026-03-02"}}`,
+			args:    map[string]interface{}{"task_id": "t1", "until": "2026-03-01"},
+			wantErr: "must be after",
+		},
+		{
+			name:    "both until and days",
+			args:    map[string]interface{}{"task_id": "t1", "until": "2026-03-04", "days": float64(2)},
+			wantErr: "exactly one of until or days",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+				if path != "/tasks/t1" {
+					return nil, fmt.Errorf("unexpected path: %s", path)
+				}
+				return []byte(tt.task), nil
+			}}
+			var sent []todoist.Command
+			syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+				sent = commands
+				status := make(map[string]interface{})
+				for _, cmd := range commands {
+					status[cmd.UUID] = "ok"
+				}
+				return &todoist.SyncResponse{SyncStatus: status}, nil
+			}}
+
+			result, err := SnoozeTaskHandler(client, syncClient)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if len(sent) != 2 {
+				t.Fatalf("sent %d commands, want 2", len(sent))
+			}
+			update := sent[0].Args
+			if due := update["due"].(map[string]interface{})["date"]; due != tt.wantDue {
+				t.Errorf("due = %v, want %s", due, tt.wantDue)
+			}
+			if labels := update["labels"].([]string); labels[0] != snoozeLabel {
+				t.Errorf("labels = %v, want snoozed first", labels)
+			}
+			if content := sent[1].Args["content"]; content != tt.wantComment {
+				t.Errorf("comment = %q, want %q", content, tt.wantComment)
+			}
+		})
+	}
+}
+
+func TestListSnoozedHandler(t *testing.T) {
+	var batches [][]todoist.Command
+	syncClient := &MockSyncAPI{
+		GetFn: func(_ context.Context, _ string) ([]byte, error) {
+			return json.Marshal(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"id": "t1", "content": "Call vendor", "labels": []string{"snoozed", "work"}, "due": map[string]interface{}{"date": "2026-03-09"}},
+					{"id": "t2", "content": "Read book", "labels": []string{"snoozed"}, "due": map[string]interface{}{"date": "2026-03-06"}},
+					{"id": "t3", "content": "Not snoozed", "labels": []string{}},
+				},
+				"notes": []map[string]interface{}{
+					{"item_id": "t1", "posted_at": "2026-03-05T10:00:00Z", "content": "💤 Snoozed from 2026-03-05 until 2026-03-09"},
+					{"item_id": "t1", "posted_at": "2026-03-01T10:00:00Z", "content": "💤 Snoozed from 2026-03-02 until 2026-03-05 — waiting"},
+					{"item_id": "t2", "posted_at": "2026-03-01T10:00:00Z", "content": "💤 Snoozed from none until 2026-03-06"},
+				},
+			})
+		},
+		BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+			batches = append(batches, commands)
+			status := make(map[string]interface{})
+			for _, cmd := range commands {
+				status[cmd.UUID] = "ok"
+			}
+			return &todoist.SyncResponse{SyncStatus: status}, nil
+		},
+	}
+
+	result, err := ListSnoozedHandler(&MockAPI{}, syncClient)(context.Background(), makeReq(nil))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	var resp struct {
+		Count int                      `json:"count"`
+		Tasks []map[string]interface{} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Count != 2 || resp.Tasks[0]["original_due"] != "2026-03-05" {
+		t.Errorf("tasks = %v", resp.Tasks)
+	}
+	if len(batches) != 0 {
+		t.Errorf("listing sent %d batches", len(batches))
+	}
+
+	result, _ = ListSnoozedHandler(&MockAPI{}, syncClient)(context.Background(), makeReq(map[string]interface{}{"restore": true}))
+	if result.IsError || !strings.Contains(resultText(result), `"restored": 2`) {
+		t.Fatalf("restore response = %s", resultText(result))
+	}
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("batches = %v", batches)
+	}
+	first := batches[0][0].Args
+	if due := first["due"].(map[string]interface{})["date"]; due != "2026-03-05" || fmt.Sprint(first["labels"]) != "[work]" {
+		t.Errorf("restore t1 = %v", first)
+	}
+	if second := batches[0][1].Args; second["due"] != nil {
+		t.Errorf("restore t2 due = %v, want cleared", second["due"])
+	}
+}
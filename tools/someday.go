@@ -0,0 +1,265 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// defaultSomedayLabel marks Someday/Maybe tasks when neither a project nor a
+// label is configured.
+const defaultSomedayLabel = "someday"
+
+// defaultSomedayBatch and maxSomedayBatch bound the tasks listed per review
+// batch.
+const (
+	defaultSomedayBatch = 10
+	maxSomedayBatch     = 50
+)
+
+// SomedayOptions locates the Someday/Maybe list: the tasks in a project, the
+// tasks with a label, or both.
+type SomedayOptions struct {
+	ProjectID string
+	Label     string
+}
+
+// fetchSomedayTasks returns the tasks in the Someday project or with the
+// Someday label, oldest first.
+func fetchSomedayTasks(ctx context.Context, client todoist.API, opts SomedayOptions) ([]models.Task, error) {
+	var tasks []models.Task
+	seen := make(map[string]bool)
+	for _, source := range [][2]string{{"project_id", opts.ProjectID}, {"label", opts.Label}} {
+		if source[1] == "" {
+			continue
+		}
+		params := url.Values{}
+		params.Set(source[0], source[1])
+		found, err := fetchTasks(ctx, client, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range found {
+			if !seen[task.ID] {
+				seen[task.ID] = true
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	slices.SortStableFunc(tasks, func(a, b models.Task) int { return strings.Compare(taskAgeKey(a), taskAgeKey(b)) })
+	return tasks, nil
+}
+
+// taskAgeKey orders tasks by age and doubles as a review cursor, so that tasks
+// moved or deleted in between do not shift the next batch.
+func taskAgeKey(task models.Task) string {
+	added, _ := taskAddedAt(task)
+	return added.UTC().Format("2006-01-02T15:04:05.000000Z") + "/" + task.ID
+}
+
+// ageCursorStart returns the index of the first task, in taskAgeKey order,
+// after cursor; an empty cursor starts at the beginning.
+func ageCursorStart(tasks []models.Task, cursor string) int {
+	if cursor == "" {
+		return 0
+	}
+	start, _ := slices.BinarySearchFunc(tasks, cursor, func(task models.Task, c string) int {
+		if key := taskAgeKey(task); key <= c {
+			return -1
+		}
+		return 1
+	})
+	return start
+}
+
+// ReviewSomedayHandler creates a handler for reviewing the Someday/Maybe list
+// in batches and applying keep, activate, and delete decisions.
+func ReviewSomedayHandler(client todoist.API, syncClient todoist.SyncAPI, defaults SomedayOptions) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		opts := defaults
+		if projectID, ok := args["project_id"].(string); ok && projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			opts = SomedayOptions{ProjectID: projectID}
+		}
+		if label, ok := args["label"].(string); ok && label != "" {
+			opts.Label = strings.TrimPrefix(label, "@")
+		}
+		if opts.ProjectID == "" && opts.Label == "" {
+			opts.Label = defaultSomedayLabel
+		}
+
+		tasks, err := fetchSomedayTasks(ctx, client, opts)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		if decisions, ok := args["decisions"].([]interface{}); ok && len(decisions) > 0 {
+			return applySomedayDecisions(ctx, syncClient, opts, tasks, decisions), nil
+		}
+
+		batchSize := defaultSomedayBatch
+		if n, ok := args["batch_size"].(float64); ok {
+			if n < 1 || n > maxSomedayBatch {
+				return respond.Errorf("batch_size must be between 1 and %d", maxSomedayBatch), nil
+			}
+			batchSize = int(n)
+		}
+		cursor, _ := args["cursor"].(string)
+		start := ageCursorStart(tasks, cursor)
+		end := min(start+batchSize, len(tasks))
+
+		now := time.Now()
+		batch := make([]map[string]interface{}, 0, end-start)
+		for _, task := range tasks[start:end] {
+			entry := map[string]interface{}{
+				"id":          task.ID,
+				"content":     task.Content,
+				"description": task.Description,
+				"project_id":  task.ProjectID,
+				"labels":      task.Labels,
+			}
+			if added, ok := taskAddedAt(task); ok {
+				entry["added_at"] = added.UTC().Format(time.RFC3339)
+				entry["age_days"] = int(now.Sub(added).Hours() / 24)
+			}
+			batch = append(batch, entry)
+		}
+
+		response := respond.List("tasks", batch).
+			Set("total", len(tasks)).
+			Set("remaining", len(tasks)-end)
+		if end < len(tasks) {
+			response.Paginate(taskAgeKey(tasks[end-1]))
+		}
+		return respond.JSON(response), nil
+	}
+}
+
+// applySomedayDecisions sends the commands for a review's decisions in one
+// Sync batch. Decisions may only name tasks on the Someday list.
+func applySomedayDecisions(ctx context.Context, syncClient todoist.SyncAPI, opts SomedayOptions, tasks []models.Task, decisions []interface{}) *mcp.CallToolResult {
+	if len(decisions) > maxPlanCommands {
+		return respond.Errorf("decisions exceeds %d entries", maxPlanCommands)
+	}
+	byID := make(map[string]models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	var commands []todoist.Command
+	commandTask := make(map[string]string)
+	kept, activated, deleted := 0, 0, 0
+	decided := make(map[string]bool)
+	for i, raw := range decisions {
+		decision, ok := raw.(map[string]interface{})
+		if !ok {
+			return respond.Errorf("decisions[%d] must be an object", i)
+		}
+		taskID, _ := decision["task_id"].(string)
+		if err := ValidateID(taskID, fmt.Sprintf("decisions[%d].task_id", i)); err != nil {
+			return respond.ErrorFrom(err)
+		}
+		task, ok := byID[taskID]
+		if !ok {
+			return respond.Errorf("decisions[%d]: task %s is not on the Someday list", i, taskID)
+		}
+		if decided[taskID] {
+			return respond.Errorf("decisions[%d]: task %s has more than one decision", i, taskID)
+		}
+		decided[taskID] = true
+
+		switch action, _ := decision["action"].(string); action {
+		case "keep":
+			kept++
+		case "delete":
+			cmd := todoist.Command{Type: "item_delete", UUID: todoist.GenerateUUID(), Args: map[string]interface{}{"id": taskID}}
+			commands = append(commands, cmd)
+			commandTask[cmd.UUID] = taskID
+			deleted++
+		case "activate":
+			projectID, _ := decision["project_id"].(string)
+			if err := ValidateID(projectID, fmt.Sprintf("decisions[%d].project_id", i)); err != nil {
+				return respond.ErrorFrom(err)
+			}
+			if projectID == opts.ProjectID {
+				return respond.Errorf("decisions[%d]: activate must move the task out of the Someday project", i)
+			}
+			due := map[string]interface{}{}
+			if s, _ := decision["due_string"].(string); s != "" {
+				due["string"] = s
+			} else if d, _ := decision["due_date"].(string); d != "" {
+				if _, err := time.Parse("2006-01-02", d); err != nil {
+					return respond.Errorf("decisions[%d].due_date must be a YYYY-MM-DD date", i)
+				}
+				due["date"] = d
+			} else {
+				return respond.Errorf("decisions[%d]: activate needs due_string or due_date", i)
+			}
+
+			if task.ProjectID != projectID {
+				cmd := todoist.Command{Type: "item_move", UUID: todoist.GenerateUUID(), Args: map[string]interface{}{"id": taskID, "project_id": projectID}}
+				commands = append(commands, cmd)
+				commandTask[cmd.UUID] = taskID
+			}
+			update := map[string]interface{}{"id": taskID, "due": due}
+			if opts.Label != "" && taskHasLabel(task, opts.Label) {
+				remaining := make([]string, 0, len(task.Labels))
+				for _, l := range task.Labels {
+					if !strings.EqualFold(l, opts.Label) {
+						remaining = append(remaining, l)
+					}
+				}
+				update["labels"] = remaining
+			}
+			cmd := todoist.Command{Type: "item_update", UUID: todoist.GenerateUUID(), Args: update}
+			commands = append(commands, cmd)
+			commandTask[cmd.UUID] = taskID
+			activated++
+		default:
+			return respond.Errorf("decisions[%d].action must be one of: keep, activate, delete", i)
+		}
+	}
+
+	failed := make([]todoist.BulkFailure, 0)
+	if len(commands) > 0 {
+		syncResp, err := syncClient.BatchCommands(ctx, commands)
+		if err != nil {
+			return respond.Errorf("failed to apply decisions: %v", err)
+		}
+		failedIDs := make(map[string]bool)
+		for _, cmd := range commands {
+			if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+				continue
+			}
+			taskID := commandTask[cmd.UUID]
+			if !failedIDs[taskID] {
+				failedIDs[taskID] = true
+				failed = append(failed, todoist.BulkFailure{ID: taskID, Error: fmt.Sprintf("%s: %v", cmd.Type, syncResp.SyncStatus[cmd.UUID])})
+				if cmd.Type == "item_delete" {
+					deleted--
+				} else {
+					activated--
+				}
+			}
+		}
+	}
+
+	return respond.JSON(map[string]interface{}{
+		"kept":      kept,
+		"activated": activated,
+		"deleted":   deleted,
+		"failed":    failed,
+	})
+}
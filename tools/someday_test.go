@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+// somedayMock serves the Someday project p-some and the @someday label, with
+// task s2 in both.
+func somedayMock(paths *[]string) *MockAPI {
+	return &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		*paths = append(*paths, path)
+		switch {
+		case strings.Contains(path, "project_id=p-some"):
+			return json.Marshal([]map[string]interface{}{
+				{"id": "s3", "content": "Learn Rust", "project_id": "p-some", "added_at": "2025-06-01T10:00:00Z"},
+				{"id": "s2", "content": "Kayak trip", "project_id": "p-some", "added_at": "2024-05-01T10:00:00Z", "labels": []interface{}{"someday"}},
+			})
+		case strings.Contains(path, "label=someday"):
+			return json.Marshal([]map[string]interface{}{
+				{"id": "s2", "content": "Kayak trip", "project_id": "p-some", "added_at": "2024-05-01T10:00:00Z", "labels": []interface{}{"someday"}},
+				{"id": "s1", "content": "Write a novel", "project_id": "inbox", "added_at": "2023-01-01T10:00:00Z", "labels": []interface{}{"someday", "writing"}},
+			})
+		}
+		return []byte(`[]`), nil
+	}}
+}
+
+func TestReviewSomedayHandler_Batches(t *testing.T) {
+	var paths []string
+	handler := ReviewSomedayHandler(somedayMock(&paths), &MockSyncAPI{}, SomedayOptions{ProjectID: "p-some", Label: "someday"})
+
+	var first struct {
+		Count      int                      `json:"count"`
+		Total      int                      `json:"total"`
+		Remaining  int                      `json:"remaining"`
+		NextCursor string                   `json:"next_cursor"`
+		Tasks      []map[string]interface{} `json:"tasks"`
+	}
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"batch_size": float64(2)}))
+	if err := json.Unmarshal([]byte(resultText(result)), &first); err != nil {
+		t.Fatalf("parse: %v (%s)", err, resultText(result))
+	}
+	if first.Count != 2 || first.Total != 3 || first.Remaining != 1 || first.NextCursor == "" {
+		t.Fatalf("first batch = %+v", first)
+	}
+	if first.Tasks[0]["id"] != "s1" || first.Tasks[1]["id"] != "s2" || first.Tasks[0]["age_days"] == nil {
+		t.Errorf("first batch tasks = %v", first.Tasks)
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"batch_size": float64(2), "cursor": first.NextCursor}))
+	text := resultText(result)
+	if !strings.Contains(text, `"id": "s3"`) || !strings.Contains(text, `"remaining": 0`) || strings.Contains(text, "next_cursor") {
+		t.Errorf("second batch = %s", text)
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"batch_size": float64(51)}))
+	if !result.IsError {
+		t.Errorf("batch_size 51: %s", resultText(result))
+	}
+}
+
+func TestReviewSomedayHandler_DefaultLabel(t *testing.T) {
+	var paths []string
+	handler := ReviewSomedayHandler(somedayMock(&paths), &MockSyncAPI{}, SomedayOptions{})
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{}))
+	if text := resultText(result); !strings.Contains(text, `"total": 2`) || len(paths) != 1 || !strings.Contains(paths[0], "label=someday") {
+		t.Errorf("result = %s, paths = %v", text, paths)
+	}
+}
+
+func TestReviewSomedayHandler_Decisions(t *testing.T) {
+	var paths []string
+	var sent []todoist.Command
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		sent = commands
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+			if cmd.Type == "item_delete" {
+				status[cmd.UUID] = map[string]interface{}{"error": "forbidden"}
+			}
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+	handler := ReviewSomedayHandler(somedayMock(&paths), syncClient, SomedayOptions{ProjectID: "p-some", Label: "someday"})
+
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"decisions": []interface{}{
+		map[string]interface{}{"task_id": "s1", "action": "activate", "project_id": "p-work", "due_string": "next monday"},
+		map[string]interface{}{"task_id": "s2", "action": "keep"},
+		map[string]interface{}{"task_id": "s3", "action": "delete"},
+	}}))
+	var resp struct {
+		Kept      int                   `json:"kept"`
+		Activated int                   `json:"activated"`
+		Deleted   int                   `json:"deleted"`
+		Failed    []todoist.BulkFailure `json:"failed"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("parse: %v (%s)", err, resultText(result))
+	}
+	if resp.Kept != 1 || resp.Activated != 1 || resp.Deleted != 0 || len(resp.Failed) != 1 || resp.Failed[0].ID != "s3" {
+		t.Errorf("resp = %+v", resp)
+	}
+	if len(sent) != 3 || sent[0].Type != "item_move" || sent[0].Args["project_id"] != "p-work" || sent[1].Type != "item_update" {
+		t.Fatalf("sent = %+v", sent)
+	}
+	due, _ := sent[1].Args["due"].(map[string]interface{})
+	labels, _ := sent[1].Args["labels"].([]string)
+	if due["string"] != "next monday" || len(labels) != 1 || labels[0] != "writing" {
+		t.Errorf("update args = %v", sent[1].Args)
+	}
+
+	tests := []struct {
+		decision map[string]interface{}
+		want     string
+	}{
+		{map[string]interface{}{"task_id": "other", "action": "delete"}, "not on the Someday list"},
+		{map[string]interface{}{"task_id": "s1", "action": "archive"}, "must be one of"},
+		{map[string]interface{}{"task_id": "s1", "action": "activate", "project_id": "p-work"}, "due_string or due_date"},
+		{map[string]interface{}{"task_id": "s1", "action": "activate", "project_id": "p-some", "due_date": "2026-11-02"}, "out of the Someday project"},
+		{map[string]interface{}{"task_id": "s1", "action": "activate", "due_date": "2026-11-02"}, "project_id is required"},
+	}
+	for _, tt := range tests {
+		sent = nil
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{"decisions": []interface{}{tt.decision}}))
+		if !result.IsError || !strings.Contains(resultText(result), tt.want) || sent != nil {
+			t.Errorf("%v: result = %s, want %q", tt.decision, resultText(result), tt.want)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// defaultSprintLabel marks the tasks that move into the next sprint.
+const defaultSprintLabel = "next-sprint"
+
+// sprintNamePattern matches the projects managed as sprints, e.g.
+// "Sprint 2026-42" for the sprint starting in ISO week 42 of 2026.
+var sprintNamePattern = regexp.MustCompile(`^Sprint (\d{4})-(\d{2})$`)
+
+// SprintOptions configures how sprints are started.
+type SprintOptions struct {
+	// Template names the project template a new sprint is created from;
+	// empty creates an empty project.
+	Template string
+	// Label marks the tasks moved into a new sprint; the label is removed
+	// from them once moved. Empty means "next-sprint".
+	Label string
+	// Weeks is the sprint length used by the automation; zero disables it.
+	Weeks int
+}
+
+// sprintName returns the sprint project name for the ISO week containing t.
+func sprintName(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("Sprint %d-%02d", year, week)
+}
+
+// isoWeekMonday returns the Monday starting an ISO week in loc.
+func isoWeekMonday(year, week int, loc *time.Location) time.Time {
+	// January 4th is always in week 1.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	return jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(week-1)*7)
+}
+
+// sprintProject is an active project whose name matches sprintNamePattern.
+type sprintProject struct {
+	id, name string
+	start    time.Time
+}
+
+// listSprintProjects returns the active sprint projects, oldest first.
+func listSprintProjects(ctx context.Context, client todoist.API, loc *time.Location) ([]sprintProject, error) {
+	respBody, err := client.Get(ctx, "/projects")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch projects: %w", err)
+	}
+	var projects []models.Project
+	if err := json.Unmarshal(respBody, &projects); err != nil {
+		return nil, fmt.Errorf("failed to parse projects: %w", err)
+	}
+	var sprints []sprintProject
+	for _, project := range projects {
+		name := project.Name
+		m := sprintNamePattern.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		sprints = append(sprints, sprintProject{id: project.ID, name: name, start: isoWeekMonday(year, week, loc)})
+	}
+	slices.SortFunc(sprints, func(a, b sprintProject) int { return a.start.Compare(b.start) })
+	return sprints, nil
+}
+
+// startSprint creates this week's sprint project, moves the labeled tasks and
+// the open tasks of earlier sprints into it, and archives the earlier
+// sprints. It does nothing when this week's sprint already exists.
+func startSprint(ctx context.Context, client todoist.API, syncClient todoist.SyncAPI, templates *TemplateStore, now time.Time, opts SprintOptions) (map[string]interface{}, error) {
+	label := opts.Label
+	if label == "" {
+		label = defaultSprintLabel
+	}
+	name := sprintName(now)
+
+	sprints, err := listSprintProjects(ctx, client, now.Location())
+	if err != nil {
+		return nil, err
+	}
+	for _, sprint := range sprints {
+		if sprint.name == name {
+			return map[string]interface{}{"project_id": sprint.id, "project_name": name, "created": false}, nil
+		}
+	}
+
+	response := map[string]interface{}{"project_name": name, "created": true}
+	var projectID string
+	if opts.Template != "" {
+		tpl, err := templates.Load(opts.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load template %q: %w", opts.Template, err)
+		}
+		commands, projectRef, err := templateCommands(tpl, "", name, now)
+		if err != nil {
+			return nil, err
+		}
+		created, err := runTemplateCommands(ctx, syncClient, tpl, commands, projectRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sprint project: %w", err)
+		}
+		projectID = fmt.Sprint(created["project_id"])
+		response["template"] = opts.Template
+		response["tasks_created"] = created["tasks_created"]
+	} else {
+		respBody, err := client.Post(ctx, "/projects", map[string]interface{}{"name": name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sprint project: %w", err)
+		}
+		var project models.Project
+		if err := json.Unmarshal(respBody, &project); err != nil {
+			return nil, fmt.Errorf("failed to parse project: %w", err)
+		}
+		projectID = project.ID
+	}
+	if err := ValidateID(projectID, "project_id"); err != nil {
+		return nil, fmt.Errorf("sprint project was not created: %w", err)
+	}
+	response["project_id"] = projectID
+
+	labeled, err := resolveFilterTasks(ctx, client, "@"+label)
+	if err != nil {
+		return nil, err
+	}
+	var moves, relabels []todoist.BulkOperation
+	seen := make(map[string]bool)
+	addMove := func(task models.Task) {
+		id := task.ID
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		moves = append(moves, todoist.BulkOperation{
+			ID:      id,
+			Command: todoist.Command{Type: "item_move", UUID: todoist.GenerateUUID(), Args: map[string]interface{}{"id": id, "project_id": projectID}},
+		})
+	}
+	for _, task := range labeled {
+		addMove(task)
+		kept := make([]string, 0, len(task.Labels))
+		for _, l := range task.Labels {
+			if !strings.EqualFold(l, label) {
+				kept = append(kept, l)
+			}
+		}
+		id := task.ID
+		relabels = append(relabels, todoist.BulkOperation{
+			ID:      id,
+			Command: todoist.Command{Type: "item_update", UUID: todoist.GenerateUUID(), Args: map[string]interface{}{"id": id, "labels": kept}},
+			Method:  "POST",
+			Path:    fmt.Sprintf("/tasks/%s", id),
+			Body:    map[string]interface{}{"labels": kept},
+		})
+	}
+	// Open tasks of earlier sprints carry over, so archiving never hides them.
+	carried := make(map[string][]string, len(sprints))
+	for _, sprint := range sprints {
+		params := url.Values{}
+		params.Set("project_id", sprint.id)
+		open, err := fetchTasks(ctx, client, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tasks of %s: %w", sprint.name, err)
+		}
+		for _, task := range open {
+			// Subtasks move with their parents.
+			if task.ParentID == "" {
+				addMove(task)
+				carried[sprint.id] = append(carried[sprint.id], task.ID)
+			}
+		}
+	}
+
+	executor := todoist.NewBulkExecutor(client, syncClient)
+	moved, failed := 0, make([]todoist.BulkFailure, 0)
+	if len(moves) > 0 {
+		result, err := executor.Execute(ctx, moves)
+		if err != nil {
+			return nil, fmt.Errorf("failed to move tasks: %w", err)
+		}
+		moved = len(result.Succeeded)
+		failed = append(failed, result.Failed...)
+	}
+	if len(relabels) > 0 {
+		result, err := executor.Execute(ctx, relabels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove @%s: %w", label, err)
+		}
+		failed = append(failed, result.Failed...)
+	}
+	// A sprint is archived only once all of its open tasks have moved out.
+	var archives []todoist.BulkOperation
+	for _, sprint := range sprints {
+		if !slices.ContainsFunc(carried[sprint.id], func(id string) bool {
+			return slices.ContainsFunc(failed, func(f todoist.BulkFailure) bool { return f.ID == id })
+		}) {
+			archives = append(archives, todoist.BulkOperation{
+				ID:      sprint.id,
+				Command: todoist.Command{Type: "project_archive", UUID: todoist.GenerateUUID(), Args: map[string]interface{}{"id": sprint.id}},
+			})
+		}
+	}
+	archived := make([]string, 0, len(archives))
+	if len(archives) > 0 {
+		result, err := executor.Execute(ctx, archives)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive previous sprints: %w", err)
+		}
+		for _, sprint := range sprints {
+			if slices.Contains(result.Succeeded, sprint.id) {
+				archived = append(archived, sprint.name)
+			}
+		}
+		failed = append(failed, result.Failed...)
+	}
+
+	response["tasks_moved"] = moved
+	response["archived"] = archived
+	response["failed"] = failed
+	return response, nil
+}
+
+// sprintDue reports whether a new sprint should start at now: when there is
+// no sprint yet, or the latest one began weeks or more weeks ago.
+func sprintDue(sprints []sprintProject, now time.Time, weeks int) bool {
+	if len(sprints) == 0 {
+		return true
+	}
+	latest := sprints[len(sprints)-1]
+	return !now.Before(latest.start.AddDate(0, 0, 7*weeks))
+}
+
+// SprintAutomation starts a new sprint every opts.Weeks weeks, checking
+// hourly.
+func SprintAutomation(client todoist.API, syncClient todoist.SyncAPI, templates *TemplateStore, opts SprintOptions) Automation {
+	return Automation{
+		Name:  "sprint_rollover",
+		Every: time.Hour,
+		Run: func(ctx context.Context) error {
+			loc, err := userLocation(ctx, syncClient)
+			if err != nil {
+				return fmt.Errorf("failed to resolve user time zone: %w", err)
+			}
+			now := time.Now().In(loc)
+			sprints, err := listSprintProjects(ctx, client, loc)
+			if err != nil {
+				return err
+			}
+			if !sprintDue(sprints, now, opts.Weeks) {
+				return nil
+			}
+			_, err = startSprint(ctx, client, syncClient, templates, now, opts)
+			return err
+		},
+	}
+}
+
+// StartSprintHandler creates a handler for starting this week's sprint on
+// demand.
+func StartSprintHandler(client todoist.API, syncClient todoist.SyncAPI, templates *TemplateStore, defaults SprintOptions) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		opts := defaults
+		if template, ok := args["template"].(string); ok && template != "" {
+			opts.Template = template
+		}
+		if label, ok := args["label"].(string); ok && label != "" {
+			opts.Label = strings.TrimPrefix(label, "@")
+		}
+
+		loc, err := userLocation(ctx, syncClient)
+		if err != nil {
+			return respond.Errorf("failed to resolve user time zone: %v", err), nil
+		}
+		response, err := startSprint(ctx, client, syncClient, templates, time.Now().In(loc), opts)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		return respond.JSON(response), nil
+	}
+}
@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestSprintName(t *testing.T) {
+	tests := []struct {
+		date time.Time
+		want string
+	}{
+		{time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), "Sprint 2026-42"},
+		{time.Date(2027, 1, 1, 9, 0, 0, 0, time.UTC), "Sprint 2026-53"},
+		{time.Date(2025, 12, 29, 9, 0, 0, 0, time.UTC), "Sprint 2026-01"},
+	}
+	for _, tt := range tests {
+		if got := sprintName(tt.date); got != tt.want {
+			t.Errorf("sprintName(%s) = %q, want %q", tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestIsoWeekMonday(t *testing.T) {
+	tests := []struct {
+		year, week int
+		want       string
+	}{
+		{2026, 42, "2026-10-12"},
+		{2026, 1, "2025-12-29"},
+		{2026, 53, "2026-12-28"},
+	}
+	for _, tt := range tests {
+		if got := isoWeekMonday(tt.year, tt.week, time.UTC).Format("2006-01-02"); got != tt.want {
+			t.Errorf("isoWeekMonday(%d, %d) = %s, want %s", tt.year, tt.week, got, tt.want)
+		}
+	}
+}
+
+func TestSprintDue(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	sprint := func(week int) []sprintProject {
+		return []sprintProject{{id: "p1", start: isoWeekMonday(2026, week, time.UTC)}}
+	}
+	tests := []struct {
+		name    string
+		sprints []sprintProject
+		weeks   int
+		want    bool
+	}{
+		{"no sprint", nil, 2, true},
+		{"current week", sprint(42), 1, false},
+		{"last week, one-week sprints", sprint(41), 1, true},
+		{"last week, two-week sprints", sprint(41), 2, false},
+		{"two weeks ago, two-week sprints", sprint(40), 2, true},
+	}
+	for _, tt := range tests {
+		if got := sprintDue(tt.sprints, now, tt.weeks); got != tt.want {
+			t.Errorf("%s: sprintDue = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// sprintMock serves projects, the tasks of project p-old and the tasks
+// labeled @next-sprint, and records project creations and task updates.
+func sprintMock(projects string, posts *[]string) *MockAPI {
+	return &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			switch {
+			case path == "/projects":
+				return []byte(projects), nil
+			case strings.Contains(path, "filter=%40next-sprint"):
+				return []byte(`[{"id":"t1","content":"Plan","labels":["next-sprint","work"]}]`), nil
+			case strings.Contains(path, "project_id=p-old"):
+				return []byte(`[{"id":"t2","content":"Leftover"},{"id":"t3","content":"Sub","parent_id":"t2"}]`), nil
+			}
+			return []byte(`[]`), nil
+		},
+		PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+			data, _ := json.Marshal(body)
+			*posts = append(*posts, path+" "+string(data))
+			if path == "/projects" {
+				return []byte(`{"id":"p-new","name":"Sprint 2026-42"}`), nil
+			}
+			return []byte(`{}`), nil
+		},
+	}
+}
+
+func TestStartSprint(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	t.Run("creates sprint and rolls over", func(t *testing.T) {
+		var posts []string
+		var sent []todoist.Command
+		client := sprintMock(`[{"id":"p-old","name":"Sprint 2026-40"},{"id":"p2","name":"Work"}]`, &posts)
+		syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+			sent = append(sent, commands...)
+			status := make(map[string]interface{})
+			for _, cmd := range commands {
+				status[cmd.UUID] = "ok"
+			}
+			return &todoist.SyncResponse{SyncStatus: status}, nil
+		}}
+
+		response, err := startSprint(context.Background(), client, syncClient, nil, now, SprintOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response["project_id"] != "p-new" || response["created"] != true || response["tasks_moved"] != 2 {
+			t.Errorf("response = %+v", response)
+		}
+		if archived, _ := response["archived"].([]string); len(archived) != 1 || archived[0] != "Sprint 2026-40" {
+			t.Errorf("archived = %v", response["archived"])
+		}
+		if len(posts) != 2 || !strings.Contains(posts[0], `"name":"Sprint 2026-42"`) || posts[1] != `/tasks/t1 {"labels":["work"]}` {
+			t.Errorf("posts = %v", posts)
+		}
+		var moved, archives []string
+		for _, cmd := range sent {
+			switch cmd.Type {
+			case "item_move":
+				if cmd.Args["project_id"] != "p-new" {
+					t.Errorf("item_move args = %v", cmd.Args)
+				}
+				moved = append(moved, cmd.Args["id"].(string))
+			case "project_archive":
+				archives = append(archives, cmd.Args["id"].(string))
+			}
+		}
+		if strings.Join(moved, ",") != "t1,t2" || strings.Join(archives, ",") != "p-old" {
+			t.Errorf("moved = %v, archived = %v", moved, archives)
+		}
+	})
+
+	t.Run("keeps sprint with failed moves", func(t *testing.T) {
+		var posts []string
+		client := sprintMock(`[{"id":"p-old","name":"Sprint 2026-40"}]`, &posts)
+		archivedAny := false
+		syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+			status := make(map[string]interface{})
+			for _, cmd := range commands {
+				if cmd.Type == "project_archive" {
+					archivedAny = true
+				}
+				if cmd.Args["id"] == "t2" {
+					status[cmd.UUID] = map[string]interface{}{"error": "not allowed"}
+					continue
+				}
+				status[cmd.UUID] = "ok"
+			}
+			return &todoist.SyncResponse{SyncStatus: status}, nil
+		}}
+
+		response, err := startSprint(context.Background(), client, syncClient, nil, now, SprintOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if archivedAny || response["tasks_moved"] != 1 {
+			t.Errorf("archived = %v, response = %+v", archivedAny, response)
+		}
+		if failed, _ := response["failed"].([]todoist.BulkFailure); len(failed) != 1 || failed[0].ID != "t2" {
+			t.Errorf("failed = %+v", response["failed"])
+		}
+	})
+
+	t.Run("existing sprint", func(t *testing.T) {
+		var posts []string
+		client := sprintMock(`[{"id":"p-cur","name":"Sprint 2026-42"}]`, &posts)
+		response, err := startSprint(context.Background(), client, &MockSyncAPI{}, nil, now, SprintOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response["project_id"] != "p-cur" || response["created"] != false || len(posts) != 0 {
+			t.Errorf("response = %+v, posts = %v", response, posts)
+		}
+	})
+}
@@ -4,39 +4,246 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
 )
 
+// parseDateBound parses a YYYY-MM-DD date or RFC 3339 timestamp used as a search bound.
+func parseDateBound(value, paramName string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%s must be a YYYY-MM-DD date or RFC 3339 timestamp", paramName)
+}
+
+// taskAddedAt returns when the task was created, reading the REST v2 created_at
+// field or the Sync/API v1 added_at field.
+func taskAddedAt(task models.Task) (time.Time, bool) {
+	for _, v := range []string{task.AddedAt, task.CreatedAt} {
+		if v != "" {
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// taskAddedBy returns the ID of the user who created the task.
+func taskAddedBy(task models.Task) string {
+	if task.AddedByUID != "" {
+		return task.AddedByUID
+	}
+	return task.CreatorID
+}
+
+// groupTasksByDay groups tasks by due date in calendar order. Within a day,
+// all-day tasks come first and timed tasks follow in chronological order.
+// Undated tasks form a final group with a null date.
+func groupTasksByDay(tasks []models.Task) []map[string]interface{} {
+	byDay := make(map[string][]models.Task)
+	for _, task := range tasks {
+		day := task.Due.Day()
+		byDay[day] = append(byDay[day], task)
+	}
+
+	days := make([]string, 0, len(byDay))
+	for day := range byDay {
+		if day != "" {
+			days = append(days, day)
+		}
+	}
+	sort.Strings(days)
+	if _, ok := byDay[""]; ok {
+		days = append(days, "")
+	}
+
+	groups := make([]map[string]interface{}, 0, len(days))
+	for _, day := range days {
+		allDay := make([]models.Task, 0)
+		timed := make([]models.Task, 0)
+		for _, task := range byDay[day] {
+			if task.IsTimed() {
+				timed = append(timed, task)
+			} else {
+				allDay = append(allDay, task)
+			}
+		}
+		sort.SliceStable(timed, func(i, j int) bool {
+			a, _ := taskDueTime(timed[i], time.UTC)
+			b, _ := taskDueTime(timed[j], time.UTC)
+			return a.Before(b)
+		})
+
+		group := map[string]interface{}{
+			"date":    day,
+			"count":   len(allDay) + len(timed),
+			"all_day": allDay,
+			"timed":   timed,
+		}
+		if day == "" {
+			group["date"] = nil
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// taskHasLabel reports whether the task carries the named label (case-insensitive).
+func taskHasLabel(task models.Task, label string) bool {
+	return slices.ContainsFunc(task.Labels, func(l string) bool { return strings.EqualFold(l, label) })
+}
+
+// fetchTaskLabels returns the current labels of a task.
+func fetchTaskLabels(ctx context.Context, client todoist.API, taskID string) ([]string, error) {
+	respBody, err := client.Get(ctx, fmt.Sprintf("/tasks/%s", taskID))
+	if err != nil {
+		return nil, err
+	}
+	var task models.Task
+	if err := json.Unmarshal(respBody, &task); err != nil {
+		return nil, fmt.Errorf("failed to parse task: %w", err)
+	}
+	return task.Labels, nil
+}
+
+// mergeLabels combines a task's current labels with the requested ones:
+// union appends labels the task lacks and subtract removes the requested
+// labels. Names compare case-insensitively and keep their current spelling.
+func mergeLabels(current, requested []string, strategy string) []string {
+	contains := func(list []string, name string) bool {
+		return slices.ContainsFunc(list, func(l string) bool { return strings.EqualFold(l, name) })
+	}
+	merged := make([]string, 0, len(current)+len(requested))
+	switch strategy {
+	case "union":
+		merged = append(merged, current...)
+		for _, name := range requested {
+			if !contains(merged, name) {
+				merged = append(merged, name)
+			}
+		}
+	case "subtract":
+		for _, name := range current {
+			if !contains(requested, name) {
+				merged = append(merged, name)
+			}
+		}
+	default:
+		merged = append(merged, requested...)
+	}
+	return merged
+}
+
+// warnUnparsedDue warns when a due_string was sent but Todoist returned the
+// task without a due date, which is how it treats phrases it cannot parse.
+// "no date" clears the due date on purpose and is not reported.
+func warnUnparsedDue(task respond.Envelope, body map[string]interface{}) {
+	dueString, ok := body["due_string"].(string)
+	if ok && task["due"] == nil && !strings.EqualFold(dueString, "no date") && !strings.EqualFold(dueString, "no due date") {
+		task.Warn("due_string %q could not be parsed by Todoist; the task has no due date", dueString)
+	}
+}
+
+// currentUserID fetches the ID of the user that owns the API token.
+func currentUserID(ctx context.Context, syncClient todoist.SyncAPI) (string, error) {
+	respBody, err := syncClient.Get(ctx, "/user")
+	if err != nil {
+		return "", err
+	}
+	var user struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &user); err != nil {
+		return "", fmt.Errorf("failed to parse user: %w", err)
+	}
+	if user.ID == "" {
+		return "", fmt.Errorf("user response did not include an id")
+	}
+	return user.ID, nil
+}
+
 // SearchTasksHandler creates a handler for searching/listing tasks.
-func SearchTasksHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func SearchTasksHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 
+		var createdAfter, createdBefore time.Time
+		if v, ok := args["created_after"].(string); ok && v != "" {
+			t, err := parseDateBound(v, "created_after")
+			if err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			createdAfter = t
+		}
+		if v, ok := args["created_before"].(string); ok && v != "" {
+			t, err := parseDateBound(v, "created_before")
+			if err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			createdBefore = t
+		}
+		var deadlineFrom, deadlineTo string
+		for name, dst := range map[string]*string{"deadline_from": &deadlineFrom, "deadline_to": &deadlineTo} {
+			if v, ok := args[name].(string); ok && v != "" {
+				if _, err := time.Parse("2006-01-02", v); err != nil {
+					return respond.Errorf("%s must be a YYYY-MM-DD date", name), nil
+				}
+				*dst = v
+			}
+		}
+		addedByMe, _ := args["added_by_me"].(bool)
+		includeCompleted, _ := args["include_completed"].(bool)
+		includeSubprojects, _ := args["include_subprojects"].(bool)
+		hideDeferred, _ := args["hide_deferred"].(bool)
+
 		params := url.Values{}
+		completedParams := url.Values{}
+		var label string
+		var idStrs []string
+		var projectIDs []string
 
 		if filter, ok := args["filter"].(string); ok && filter != "" {
 			params.Set("filter", filter)
+			completedParams.Set("filter_query", filter)
 		}
 
 		if projectID, ok := args["project_id"].(string); ok && projectID != "" {
 			if err := ValidateID(projectID, "project_id"); err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return respond.ErrorFrom(err), nil
+			}
+			if includeSubprojects {
+				ids, err := descendantProjectIDs(ctx, client, projectID)
+				if err != nil {
+					return respond.Errorf("failed to resolve sub-projects: %v", err), nil
+				}
+				projectIDs = ids
+			} else {
+				params.Set("project_id", projectID)
+				completedParams.Set("project_id", projectID)
 			}
-			params.Set("project_id", projectID)
 		}
 
-		if label, ok := args["label"].(string); ok && label != "" {
+		if l, ok := args["label"].(string); ok && l != "" {
+			label = l
 			params.Set("label", label)
 		}
 
 		if ids, ok := args["ids"].([]interface{}); ok && len(ids) > 0 {
-			idStrs := make([]string, 0, len(ids))
+			idStrs = make([]string, 0, len(ids))
 			for _, id := range ids {
 				if idStr, ok := id.(string); ok {
 					idStrs = append(idStrs, idStr)
@@ -54,69 +261,303 @@ func SearchTasksHandler(client todoist.API) func(context.Context, mcp.CallToolRe
 
 		respBody, err := client.Get(ctx, path)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to search tasks: %v", err)), nil
+			return respond.Errorf("failed to search tasks: %v", err), nil
 		}
 
-		var tasks []map[string]interface{}
+		var tasks []models.Task
 		if err := json.Unmarshal(respBody, &tasks); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse tasks: %v", err)), nil
+			return respond.Errorf("failed to parse tasks: %v", err), nil
 		}
 
-		response := map[string]interface{}{
-			"count": len(tasks),
-			"tasks": tasks,
+		completedTruncated := false
+		completedIDs := make(map[string]bool)
+		if includeCompleted {
+			until := time.Now()
+			completed, truncated, err := fetchCompletedTasks(ctx, syncClient, until.Add(-maxCompletedRange), until, completedParams)
+			if err != nil {
+				return respond.Errorf("failed to search completed tasks: %v", err), nil
+			}
+			completedTruncated = truncated
+			for i := range tasks {
+				tasks[i].Set("completed", false)
+			}
+			for _, task := range completed {
+				if label != "" && !taskHasLabel(task, label) {
+					continue
+				}
+				if len(idStrs) > 0 && !slices.Contains(idStrs, task.ID) {
+					continue
+				}
+				task.Set("completed", true)
+				completedIDs[task.ID] = true
+				tasks = append(tasks, task)
+			}
 		}
 
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		var userID string
+		if addedByMe {
+			userID, err = currentUserID(ctx, syncClient)
+			if err != nil {
+				return respond.Errorf("failed to resolve current user: %v", err), nil
+			}
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		// Deferred tasks stay hidden until their defer date in the user's time zone.
+		var today string
+		if hideDeferred {
+			loc, err := userLocation(ctx, syncClient)
+			if err != nil {
+				return respond.Errorf("failed to resolve user time zone: %v", err), nil
+			}
+			today = time.Now().In(loc).Format("2006-01-02")
+		}
+		hiddenDeferred := 0
+
+		if !createdAfter.IsZero() || !createdBefore.IsZero() || userID != "" || len(projectIDs) > 0 || deadlineFrom != "" || deadlineTo != "" || today != "" {
+			filtered := make([]models.Task, 0, len(tasks))
+			for _, task := range tasks {
+				if len(projectIDs) > 0 && !slices.Contains(projectIDs, task.ProjectID) {
+					continue
+				}
+				if !createdAfter.IsZero() || !createdBefore.IsZero() {
+					addedAt, ok := taskAddedAt(task)
+					if !ok {
+						continue
+					}
+					if !createdAfter.IsZero() && addedAt.Before(createdAfter) {
+						continue
+					}
+					if !createdBefore.IsZero() && !addedAt.Before(createdBefore) {
+						continue
+					}
+				}
+				if userID != "" && taskAddedBy(task) != userID {
+					continue
+				}
+				if deadlineFrom != "" || deadlineTo != "" {
+					deadline := task.Deadline.Day()
+					if deadline == "" || (deadlineFrom != "" && deadline < deadlineFrom) || (deadlineTo != "" && deadline > deadlineTo) {
+						continue
+					}
+				}
+				if today != "" && taskDeferDate(task) > today {
+					hiddenDeferred++
+					continue
+				}
+				filtered = append(filtered, task)
+			}
+			tasks = filtered
+		}
+
+		completedCount := 0
+		for i, task := range tasks {
+			tasks[i].Set("is_timed", task.IsTimed())
+			if completedIDs[task.ID] {
+				completedCount++
+			}
+		}
+
+		response := respond.List("tasks", tasks)
+		if includeCompleted {
+			response["completed_count"] = completedCount
+			warnCompletedTruncated(response, completedTruncated)
+		}
+		if hideDeferred {
+			response["hidden_deferred"] = hiddenDeferred
+		}
+		if filter, _ := args["filter"].(string); filter != "" && len(tasks) == 0 {
+			response.Warn("filter %q matched 0 tasks", filter)
+		}
+		for _, id := range idStrs {
+			if !slices.ContainsFunc(tasks, func(task models.Task) bool { return task.ID == id }) {
+				response.Warn("task %s was not found and was skipped", id)
+			}
+		}
+		if groupByDay, _ := args["group_by_day"].(bool); groupByDay {
+			response["days"] = groupTasksByDay(tasks)
+		}
+
+		return respond.JSON(response), nil
 	}
 }
 
 // GetTaskHandler creates a handler for getting a single task.
-func GetTaskHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func GetTaskHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 
 		taskID, ok := args["task_id"].(string)
 		if !ok || taskID == "" {
-			return mcp.NewToolResultError("task_id is required"), nil
+			return respond.Error("task_id is required"), nil
 		}
 		if err := ValidateID(taskID, "task_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
 		path := fmt.Sprintf("/tasks/%s", taskID)
 		respBody, err := client.Get(ctx, path)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get task: %v", err)), nil
+			return respond.Errorf("failed to get task: %v", err), nil
 		}
 
-		var task map[string]interface{}
+		var task models.Task
 		if err := json.Unmarshal(respBody, &task); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse task: %v", err)), nil
+			return respond.Errorf("failed to parse task: %v", err), nil
+		}
+
+		task.Set("is_timed", task.IsTimed())
+
+		if includeContext, ok := args["include_context"].(bool); ok && includeContext {
+			task.Set("parent_chain", resolveParentChain(ctx, client, task))
+			task.Set("breadcrumb", resolveBreadcrumb(ctx, client, syncClient, task))
 		}
 
-		jsonData, err := json.MarshalIndent(task, "", "  ")
+		return respond.JSON(task), nil
+	}
+}
+
+// maxParentDepth bounds parent chain resolution so malformed data cannot loop forever.
+const maxParentDepth = 10
+
+// resolveParentChain walks parent_id links upward and returns the ancestors of
+// task ordered from the root down to the immediate parent.
+func resolveParentChain(ctx context.Context, client todoist.API, task models.Task) []map[string]interface{} {
+	chain := make([]map[string]interface{}, 0)
+	parentID := task.ParentID
+
+	for depth := 0; parentID != "" && depth < maxParentDepth; depth++ {
+		if ValidateID(parentID, "parent_id") != nil {
+			break
+		}
+		respBody, err := client.Get(ctx, fmt.Sprintf("/tasks/%s", parentID))
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			break
 		}
+		var parent models.Task
+		if json.Unmarshal(respBody, &parent) != nil {
+			break
+		}
+		chain = append([]map[string]interface{}{{
+			"id":      parent.ID,
+			"content": parent.Content,
+		}}, chain...)
+		parentID = parent.ParentID
+	}
+
+	return chain
+}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+// resolveBreadcrumb builds a human-readable location path for task in the form
+// "Workspace > Parent Project > Project > Section", where the workspace is
+// only named for projects in a team workspace. Lookups that fail are skipped.
+func resolveBreadcrumb(ctx context.Context, client todoist.API, syncClient todoist.SyncAPI, task models.Task) string {
+	var parts []string
+
+	if projectID := task.ProjectID; projectID != "" {
+		var workspaceID string
+		if respBody, err := client.Get(ctx, "/projects"); err == nil {
+			var projects []models.Project
+			if json.Unmarshal(respBody, &projects) == nil {
+				byID := make(map[string]models.Project, len(projects))
+				for _, proj := range projects {
+					byID[proj.ID] = proj
+				}
+				for depth := 0; projectID != "" && depth < maxParentDepth; depth++ {
+					proj, ok := byID[projectID]
+					if !ok {
+						break
+					}
+					if proj.Name != "" {
+						parts = append([]string{proj.Name}, parts...)
+					}
+					if proj.WorkspaceID != "" {
+						workspaceID = proj.WorkspaceID
+					}
+					projectID = proj.ParentID
+				}
+			}
+		}
+		if workspaceID != "" {
+			if name := workspaceName(ctx, syncClient, workspaceID); name != "" {
+				parts = append([]string{name}, parts...)
+			}
+		}
 	}
+
+	if sectionID := task.SectionID; sectionID != "" && ValidateID(sectionID, "section_id") == nil {
+		if respBody, err := client.Get(ctx, fmt.Sprintf("/sections/%s", sectionID)); err == nil {
+			var section models.Section
+			if json.Unmarshal(respBody, &section) == nil && section.Name != "" {
+				parts = append(parts, section.Name)
+			}
+		}
+	}
+
+	return strings.Join(parts, " > ")
+}
+
+// workspaceName returns the name of the workspace with id from the Sync API's
+// workspaces resource, or "" when it cannot be looked up.
+func workspaceName(ctx context.Context, syncClient todoist.SyncAPI, id string) string {
+	resources, err := fetchSyncResources(ctx, syncClient, "workspaces")
+	if err != nil {
+		return ""
+	}
+	workspaces, err := decodeSyncObjects(resources["workspaces"])
+	if err != nil {
+		return ""
+	}
+	for _, workspace := range workspaces {
+		if fmt.Sprint(workspace["id"]) == id {
+			name, _ := workspace["name"].(string)
+			return name
+		}
+	}
+	return ""
+}
+
+// TaskDefaults are applied to new tasks for the fields the caller leaves out.
+type TaskDefaults struct {
+	ProjectID string
+	Labels    []string
+	Priority  int
+	DueString string
 }
 
-// CreateTaskHandler creates a handler for creating a new task.
-func CreateTaskHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// apply fills in the defaults missing from a task creation body and returns
+// the fields it set. Subtasks and tasks placed in a section already have a
+// project, so the default project is not applied to them.
+func (d TaskDefaults) apply(body map[string]interface{}) map[string]interface{} {
+	applied := make(map[string]interface{})
+	set := func(key string, value interface{}) {
+		body[key] = value
+		applied[key] = value
+	}
+	if d.ProjectID != "" && body["project_id"] == nil && body["section_id"] == nil && body["parent_id"] == nil {
+		set("project_id", d.ProjectID)
+	}
+	if len(d.Labels) > 0 && body["labels"] == nil {
+		set("labels", d.Labels)
+	}
+	if d.Priority > 0 && body["priority"] == nil {
+		set("priority", d.Priority)
+	}
+	if d.DueString != "" && body["due_string"] == nil && body["due_date"] == nil && body["due_datetime"] == nil {
+		set("due_string", d.DueString)
+	}
+	return applied
+}
+
+// CreateTaskHandler creates a handler for creating a new task. defaults fill
+// in the fields the caller omits, then the policy of the task's project is
+// enforced.
+func CreateTaskHandler(client todoist.API, defaults TaskDefaults, policies CreationPolicies) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 
 		content, ok := args["content"].(string)
 		if !ok || content == "" {
-			return mcp.NewToolResultError("content is required"), nil
+			return respond.Error("content is required"), nil
 		}
 
 		body := map[string]interface{}{
@@ -152,7 +593,7 @@ func CreateTaskHandler(client todoist.API) func(context.Context, mcp.CallToolReq
 		if priority, ok := args["priority"].(float64); ok {
 			p := int(priority)
 			if p < 1 || p > 4 {
-				return mcp.NewToolResultError("priority must be between 1 (normal) and 4 (urgent)"), nil
+				return respond.Error("priority must be between 1 (normal) and 4 (urgent)"), nil
 			}
 			body["priority"] = p
 		}
@@ -177,23 +618,30 @@ func CreateTaskHandler(client todoist.API) func(context.Context, mcp.CallToolReq
 		if deadlineDate, ok := args["deadline_date"].(string); ok && deadlineDate != "" {
 			body["deadline_date"] = deadlineDate
 		}
+		applied := defaults.apply(body)
+		_, fixes, err := policies.enforce(ctx, client, body, nil)
+		if err != nil {
+			return respond.Errorf("task not created: %v", err), nil
+		}
 
 		respBody, err := client.Post(ctx, "/tasks", body)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to create task: %v", err)), nil
+			return respond.Errorf("failed to create task: %v", err), nil
 		}
 
-		var task map[string]interface{}
+		var task respond.Envelope
 		if err := json.Unmarshal(respBody, &task); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse response: %v", err)), nil
+			return respond.Errorf("failed to parse response: %v", err), nil
 		}
-
-		jsonData, err := json.MarshalIndent(task, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		warnUnparsedDue(task, body)
+		if len(applied) > 0 {
+			task["applied_defaults"] = applied
+		}
+		if len(fixes) > 0 {
+			task["policy_fixes"] = fixes
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(task), nil
 	}
 }
 
@@ -204,10 +652,10 @@ func UpdateTaskHandler(client todoist.API) func(context.Context, mcp.CallToolReq
 
 		taskID, ok := args["task_id"].(string)
 		if !ok || taskID == "" {
-			return mcp.NewToolResultError("task_id is required"), nil
+			return respond.Error("task_id is required"), nil
 		}
 		if err := ValidateID(taskID, "task_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
 		body := map[string]interface{}{}
@@ -218,6 +666,24 @@ func UpdateTaskHandler(client todoist.API) func(context.Context, mcp.CallToolReq
 		if description, ok := args["description"].(string); ok && description != "" {
 			body["description"] = description
 		}
+		mergeStrategy, _ := args["merge_strategy"].(string)
+		if mergeStrategy == "" {
+			mergeStrategy = "replace"
+		}
+		if mergeStrategy != "replace" && mergeStrategy != "union" && mergeStrategy != "subtract" {
+			return respond.Error("merge_strategy must be replace, union, or subtract"), nil
+		}
+		var warnings []string
+		// With skip_if_unchanged the task is read once, up front, for both
+		// the label merge and the no-op check.
+		skipIfUnchanged, _ := args["skip_if_unchanged"].(bool)
+		var current map[string]interface{}
+		if skipIfUnchanged {
+			var err error
+			if current, err = fetchEntity(ctx, client, fmt.Sprintf("/tasks/%s", taskID)); err != nil {
+				return respond.Errorf("failed to get current task: %v", err), nil
+			}
+		}
 		if labels, ok := args["labels"].([]interface{}); ok && len(labels) > 0 {
 			labelStrs := make([]string, 0, len(labels))
 			for _, l := range labels {
@@ -226,13 +692,37 @@ func UpdateTaskHandler(client todoist.API) func(context.Context, mcp.CallToolReq
 				}
 			}
 			if len(labelStrs) > 0 {
+				if mergeStrategy != "replace" {
+					// Merge against the labels as they are right now, so labels
+					// added since the caller last read the task are kept.
+					var currentLabels []string
+					if current != nil {
+						labels, _ := current["labels"].([]interface{})
+						for _, l := range labels {
+							currentLabels = append(currentLabels, fmt.Sprint(l))
+						}
+					} else {
+						var err error
+						if currentLabels, err = fetchTaskLabels(ctx, client, taskID); err != nil {
+							return respond.Errorf("failed to get current labels: %v", err), nil
+						}
+					}
+					if mergeStrategy == "subtract" {
+						for _, name := range labelStrs {
+							if !slices.ContainsFunc(currentLabels, func(l string) bool { return strings.EqualFold(l, name) }) {
+								warnings = append(warnings, fmt.Sprintf("label %q is not on the task and was skipped", name))
+							}
+						}
+					}
+					labelStrs = mergeLabels(currentLabels, labelStrs, mergeStrategy)
+				}
 				body["labels"] = labelStrs
 			}
 		}
 		if priority, ok := args["priority"].(float64); ok {
 			p := int(priority)
 			if p < 1 || p > 4 {
-				return mcp.NewToolResultError("priority must be between 1 (normal) and 4 (urgent)"), nil
+				return respond.Error("priority must be between 1 (normal) and 4 (urgent)"), nil
 			}
 			body["priority"] = p
 		}
@@ -259,94 +749,248 @@ func UpdateTaskHandler(client todoist.API) func(context.Context, mcp.CallToolReq
 		}
 
 		if len(body) == 0 {
-			return mcp.NewToolResultError("at least one field to update must be provided"), nil
+			return respond.Error("at least one field to update must be provided"), nil
+		}
+
+		if skipIfUnchanged && updateIsNoop(current, body) {
+			task := respond.Envelope(current).Set("changed", false)
+			for _, warning := range warnings {
+				task.Warn("%s", warning)
+			}
+			return respond.JSON(task), nil
 		}
 
 		path := fmt.Sprintf("/tasks/%s", taskID)
 		respBody, err := client.Post(ctx, path, body)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to update task: %v", err)), nil
+			return respond.Errorf("failed to update task: %v", err), nil
 		}
 
-		var task map[string]interface{}
+		var task respond.Envelope
 		if err := json.Unmarshal(respBody, &task); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse response: %v", err)), nil
+			return respond.Errorf("failed to parse response: %v", err), nil
 		}
-
-		jsonData, err := json.MarshalIndent(task, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		if skipIfUnchanged {
+			task["changed"] = true
+		}
+		for _, warning := range warnings {
+			task.Warn("%s", warning)
 		}
+		warnUnparsedDue(task, body)
+
+		return respond.JSON(task), nil
+	}
+}
+
+// completedAtArg reads the optional completed_at argument, which backdates a
+// completion, as a UTC timestamp for the Sync API.
+func completedAtArg(args map[string]interface{}) (string, error) {
+	v, _ := args["completed_at"].(string)
+	if v == "" {
+		return "", nil
+	}
+	t, err := parseDateBound(v, "completed_at")
+	if err != nil {
+		return "", err
+	}
+	if t.After(time.Now()) {
+		return "", fmt.Errorf("completed_at cannot be in the future")
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// itemCompleteCommand completes a task as of completedAt. Unlike item_close,
+// item_complete takes a completion date, but it ends a recurring task instead
+// of advancing it, so callers must leave recurring tasks out.
+func itemCompleteCommand(taskID, completedAt string) todoist.Command {
+	return todoist.Command{
+		Type: "item_complete",
+		UUID: todoist.GenerateUUID(),
+		Args: map[string]interface{}{"id": taskID, "date_completed": completedAt},
+	}
+}
+
+// taskIsRecurring reports whether a task has a recurring due date.
+func taskIsRecurring(task models.Task) bool {
+	return task.Due != nil && task.Due.IsRecurring
+}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+// openSubtasks returns the open descendants of a task, deepest first, so
+// that completing them in order never closes a parent before its children.
+func openSubtasks(ctx context.Context, client todoist.API, task models.Task) ([]models.Task, error) {
+	params := url.Values{}
+	params.Set("project_id", task.ProjectID)
+	tasks, err := fetchTasks(ctx, client, params)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[string][]models.Task)
+	for _, t := range tasks {
+		if t.ParentID != "" {
+			children[t.ParentID] = append(children[t.ParentID], t)
+		}
+	}
+	var subtasks []models.Task
+	var walk func(id string)
+	walk = func(id string) {
+		for _, child := range children[id] {
+			walk(child.ID)
+			subtasks = append(subtasks, child)
+		}
 	}
+	walk(task.ID)
+	return subtasks, nil
 }
 
 // CompleteTaskHandler creates a handler for completing a task.
-func CompleteTaskHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func CompleteTaskHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 
 		taskID, ok := args["task_id"].(string)
 		if !ok || taskID == "" {
-			return mcp.NewToolResultError("task_id is required"), nil
+			return respond.Error("task_id is required"), nil
 		}
 		if err := ValidateID(taskID, "task_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
+		}
+		completedAt, err := completedAtArg(args)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		policy := "ignore"
+		if p, ok := args["subtask_policy"].(string); ok && p != "" {
+			if p != "ignore" && p != "complete_all" && p != "require_complete" {
+				return respond.Error("subtask_policy must be one of: ignore, complete_all, require_complete"), nil
+			}
+			policy = p
 		}
 
-		path := fmt.Sprintf("/tasks/%s/close", taskID)
-		_, err := client.Post(ctx, path, nil)
+		if completedAt == "" && policy == "ignore" {
+			path := fmt.Sprintf("/tasks/%s/close", taskID)
+			if _, err := client.Post(ctx, path, nil); err != nil {
+				return respond.Errorf("failed to complete task: %v", err), nil
+			}
+			return respond.JSON(map[string]interface{}{
+				"success": true,
+				"task_id": taskID,
+				"message": localize("task_completed"),
+			}), nil
+		}
+
+		respBody, err := client.Get(ctx, fmt.Sprintf("/tasks/%s", taskID))
+		if err != nil {
+			return respond.Errorf("failed to get task: %v", err), nil
+		}
+		var task models.Task
+		if err := json.Unmarshal(respBody, &task); err != nil {
+			return respond.Errorf("failed to parse task: %v", err), nil
+		}
+
+		var subtasks []models.Task
+		if policy != "ignore" {
+			subtasks, err = openSubtasks(ctx, client, task)
+			if err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+		if policy == "require_complete" && len(subtasks) > 0 {
+			ids := make([]string, len(subtasks))
+			for i, subtask := range subtasks {
+				ids[i] = subtask.ID
+			}
+			return respond.Errorf("task not completed: %d subtask(s) are still open (%s); complete them first or use subtask_policy complete_all", len(subtasks), strings.Join(ids, ", ")), nil
+		}
+
+		// Subtasks first, then the task itself. A parent with many open
+		// subtasks can exceed one Sync request; the task stays last.
+		var commands []todoist.Command
+		for _, t := range append(subtasks, task) {
+			id := t.ID
+			if completedAt == "" {
+				commands = append(commands, todoist.Command{Type: "item_close", UUID: todoist.GenerateUUID(), Args: map[string]interface{}{"id": id}})
+				continue
+			}
+			if taskIsRecurring(t) {
+				if id == taskID {
+					return respond.Error("completed_at is not supported for recurring tasks; complete it without completed_at to advance it to the next occurrence"), nil
+				}
+				return respond.Errorf("completed_at is not supported for recurring tasks, and subtask %s is recurring", id), nil
+			}
+			commands = append(commands, itemCompleteCommand(id, completedAt))
+		}
+		syncResp, err := todoist.SendCommands(ctx, syncClient, commands)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to complete task: %v", err)), nil
+			return respond.Errorf("failed to complete task: %v", err), nil
+		}
+		failed := make([]todoist.BulkFailure, 0)
+		for _, cmd := range commands {
+			if status, ok := syncResp.SyncStatus[cmd.UUID].(string); !ok || status != "ok" {
+				failed = append(failed, todoist.BulkFailure{ID: fmt.Sprint(cmd.Args["id"]), Error: fmt.Sprintf("%v", syncResp.SyncStatus[cmd.UUID])})
+			}
+		}
+		last := commands[len(commands)-1]
+		if status, ok := syncResp.SyncStatus[last.UUID].(string); !ok || status != "ok" {
+			if completed := len(subtasks) - len(failed) + 1; completed > 0 {
+				return respond.Errorf("failed to complete task: %v (%d of its subtasks were completed)", syncResp.SyncStatus[last.UUID], completed), nil
+			}
+			return respond.Errorf("failed to complete task: %v", syncResp.SyncStatus[last.UUID]), nil
 		}
 
 		response := map[string]interface{}{
 			"success": true,
 			"task_id": taskID,
-			"message": "Task completed successfully",
+			"message": localize("task_completed"),
 		}
-
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		if completedAt != "" {
+			response["completed_at"] = completedAt
+		}
+		if policy == "complete_all" {
+			response["subtasks_completed"] = len(subtasks) - len(failed)
+			response["failed"] = failed
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
 
 // UncompleteTaskHandler creates a handler for reopening a task.
-func UncompleteTaskHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func UncompleteTaskHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 
-		taskID, ok := args["task_id"].(string)
-		if !ok || taskID == "" {
-			return mcp.NewToolResultError("task_id is required"), nil
+		taskID, _ := args["task_id"].(string)
+		completedItemID, _ := args["completed_item_id"].(string)
+		if taskID == "" && completedItemID == "" {
+			return respond.Error("task_id or completed_item_id is required"), nil
+		}
+		if taskID == "" {
+			if err := ValidateID(completedItemID, "completed_item_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			resolved, err := resolveCompletedItem(ctx, syncClient, completedItemID)
+			if err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			taskID = resolved
 		}
 		if err := ValidateID(taskID, "task_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
 		path := fmt.Sprintf("/tasks/%s/reopen", taskID)
 		_, err := client.Post(ctx, path, nil)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to reopen task: %v", err)), nil
+			return respond.Errorf("failed to reopen task: %v", err), nil
 		}
 
 		response := map[string]interface{}{
 			"success": true,
 			"task_id": taskID,
-			"message": "Task reopened successfully",
+			"message": localize("task_reopened"),
 		}
 
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
 
@@ -357,41 +1001,65 @@ func DeleteTaskHandler(client todoist.API) func(context.Context, mcp.CallToolReq
 
 		taskID, ok := args["task_id"].(string)
 		if !ok || taskID == "" {
-			return mcp.NewToolResultError("task_id is required"), nil
+			return respond.Error("task_id is required"), nil
 		}
 		if err := ValidateID(taskID, "task_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
+		force, _ := args["force"].(bool)
+
+		// Todoist deletes a task's subtasks along with it, so a parent is only
+		// deleted when the caller has seen the cascade and passed force.
 		path := fmt.Sprintf("/tasks/%s", taskID)
-		err := client.Delete(ctx, path)
+		respBody, err := client.Get(ctx, path)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to delete task: %v", err)), nil
+			return respond.Errorf("failed to get task: %v", err), nil
+		}
+		var task models.Task
+		if err := json.Unmarshal(respBody, &task); err != nil {
+			return respond.Errorf("failed to parse task: %v", err), nil
+		}
+		subtasks, err := openSubtasks(ctx, client, task)
+		if err != nil {
+			return respond.Errorf("failed to list subtasks: %v", err), nil
+		}
+		if len(subtasks) > 0 && !force {
+			ids := make([]string, 0, len(subtasks))
+			for _, sub := range subtasks {
+				ids = append(ids, sub.ID)
+			}
+			return respond.Errorf("task not deleted: deleting it would also delete %d subtask(s) (%s); pass force: true to delete them all", len(subtasks), strings.Join(ids, ", ")), nil
 		}
 
-		response := map[string]interface{}{
+		if err := client.Delete(ctx, path); err != nil {
+			return respond.Errorf("failed to delete task: %v", err), nil
+		}
+
+		response := respond.Envelope{
 			"success": true,
 			"task_id": taskID,
-			"message": "Task deleted successfully",
+			"message": localize("task_deleted"),
 		}
-
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		if len(subtasks) > 0 {
+			response["subtasks_deleted"] = len(subtasks)
+			response.Warn("%d subtask(s) were deleted with the task", len(subtasks))
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
 
-// QuickAddTaskHandler creates a handler for quick adding tasks with Todoist syntax.
-func QuickAddTaskHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// QuickAddTaskHandler creates a handler for quick adding tasks with Todoist
+// syntax. defaults fill in what the content does not specify, then the policy
+// of the task's project is enforced.
+func QuickAddTaskHandler(client todoist.API, defaults TaskDefaults, policies CreationPolicies) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 
 		content, ok := args["content"].(string)
 		if !ok || content == "" {
-			return mcp.NewToolResultError("content is required"), nil
+			return respond.Error("content is required"), nil
 		}
 
 		// Parse project (#ProjectName)
@@ -403,16 +1071,12 @@ func QuickAddTaskHandler(client todoist.API) func(context.Context, mcp.CallToolR
 
 			respBody, err := client.Get(ctx, "/projects")
 			if err == nil {
-				var projects []map[string]interface{}
+				var projects []models.Project
 				if err := json.Unmarshal(respBody, &projects); err == nil {
 					for _, proj := range projects {
-						if name, ok := proj["name"].(string); ok {
-							if strings.EqualFold(name, projectName) {
-								if id, ok := proj["id"].(string); ok {
-									projectID = id
-									break
-								}
-							}
+						if strings.EqualFold(proj.Name, projectName) {
+							projectID = proj.ID
+							break
 						}
 					}
 				}
@@ -489,56 +1153,66 @@ func QuickAddTaskHandler(client todoist.API) func(context.Context, mcp.CallToolR
 		if dueString != "" {
 			body["due_string"] = dueString
 		}
+		applied := defaults.apply(body)
+		_, fixes, err := policies.enforce(ctx, client, body, nil)
+		if err != nil {
+			return respond.Errorf("task not created: %v", err), nil
+		}
 
 		respBody, err := client.Post(ctx, "/tasks", body)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to create task: %v", err)), nil
+			return respond.Errorf("failed to create task: %v", err), nil
 		}
 
-		var task map[string]interface{}
+		var task models.Task
 		if err := json.Unmarshal(respBody, &task); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse response: %v", err)), nil
+			return respond.Errorf("failed to parse response: %v", err), nil
 		}
-
-		jsonData, err := json.MarshalIndent(task, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		if len(applied) > 0 {
+			task.Set("applied_defaults", applied)
+		}
+		if len(fixes) > 0 {
+			task.Set("policy_fixes", fixes)
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(task), nil
 	}
 }
 
 // GetTaskStatsHandler creates a handler for getting task statistics.
 func GetTaskStatsHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		deadlineDays := 7
+		if v, ok := req.GetArguments()["deadline_days"].(float64); ok {
+			if v < 0 || v > 365 {
+				return respond.Error("deadline_days must be between 0 and 365"), nil
+			}
+			deadlineDays = int(v)
+		}
+
 		tasksBody, err := client.Get(ctx, "/tasks")
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to fetch tasks: %v", err)), nil
+			return respond.Errorf("failed to fetch tasks: %v", err), nil
 		}
 
-		var tasks []map[string]interface{}
+		var tasks []models.Task
 		if err := json.Unmarshal(tasksBody, &tasks); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse tasks: %v", err)), nil
+			return respond.Errorf("failed to parse tasks: %v", err), nil
 		}
 
 		projectsBody, err := client.Get(ctx, "/projects")
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to fetch projects: %v", err)), nil
+			return respond.Errorf("failed to fetch projects: %v", err), nil
 		}
 
-		var projects []map[string]interface{}
+		var projects []models.Project
 		if err := json.Unmarshal(projectsBody, &projects); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to parse projects: %v", err)), nil
+			return respond.Errorf("failed to parse projects: %v", err), nil
 		}
 
 		projectMap := make(map[string]string)
 		for _, proj := range projects {
-			if id, ok := proj["id"].(string); ok {
-				if name, ok := proj["name"].(string); ok {
-					projectMap[id] = name
-				}
-			}
+			projectMap[proj.ID] = proj.Name
 		}
 
 		stats := map[string]interface{}{
@@ -551,191 +1225,210 @@ func GetTaskStatsHandler(client todoist.API) func(context.Context, mcp.CallToolR
 				"p3": 0,
 				"p4": 0,
 			},
-			"by_project": make(map[string]int),
+			"by_project":            make(map[string]int),
+			"deadlines_overdue":     0,
+			"deadlines_approaching": 0,
 		}
+		dueAfterDeadline := make([]map[string]interface{}, 0)
 
-		today := time.Now().Format("2006-01-02")
+		now := time.Now()
+		today := now.Format("2006-01-02")
+		deadlineHorizon := now.AddDate(0, 0, deadlineDays).Format("2006-01-02")
 
 		for _, task := range tasks {
-			if priority, ok := task["priority"].(float64); ok {
-				p := int(priority)
-				switch p {
-				case 4:
-					stats["by_priority"].(map[string]int)["p1"]++
-				case 3:
-					stats["by_priority"].(map[string]int)["p2"]++
-				case 2:
-					stats["by_priority"].(map[string]int)["p3"]++
-				case 1:
-					stats["by_priority"].(map[string]int)["p4"]++
-				}
+			switch task.Priority {
+			case 4:
+				stats["by_priority"].(map[string]int)["p1"]++
+			case 3:
+				stats["by_priority"].(map[string]int)["p2"]++
+			case 2:
+				stats["by_priority"].(map[string]int)["p3"]++
+			case 1:
+				stats["by_priority"].(map[string]int)["p4"]++
 			}
 
-			if projectID, ok := task["project_id"].(string); ok {
-				projectName := projectMap[projectID]
+			if task.ProjectID != "" {
+				projectName := projectMap[task.ProjectID]
 				if projectName == "" {
 					projectName = "Unknown"
 				}
 				stats["by_project"].(map[string]int)[projectName]++
 			}
 
-			if due, ok := task["due"].(map[string]interface{}); ok {
-				if dueDate, ok := due["date"].(string); ok {
-					if dueDate == today {
-						stats["today"] = stats["today"].(int) + 1
-					} else if dueDate < today {
-						stats["overdue"] = stats["overdue"].(int) + 1
-					}
+			if dueDate := task.Due.Day(); dueDate != "" {
+				if dueDate == today {
+					stats["today"] = stats["today"].(int) + 1
+				} else if dueDate < today {
+					stats["overdue"] = stats["overdue"].(int) + 1
 				}
 			}
-		}
 
-		jsonData, err := json.MarshalIndent(stats, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			if deadline := task.Deadline.Day(); deadline != "" {
+				if deadline < today {
+					stats["deadlines_overdue"] = stats["deadlines_overdue"].(int) + 1
+				} else if deadline <= deadlineHorizon {
+					stats["deadlines_approaching"] = stats["deadlines_approaching"].(int) + 1
+				}
+				if due := task.Due.Day(); due != "" && due > deadline {
+					dueAfterDeadline = append(dueAfterDeadline, map[string]interface{}{
+						"id":       task.ID,
+						"content":  task.Content,
+						"due":      due,
+						"deadline": deadline,
+					})
+				}
+			}
 		}
+		stats["due_after_deadline"] = dueAfterDeadline
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(stats), nil
 	}
 }
 
 // BulkCompleteTasksHandler creates a handler for completing multiple tasks.
-func BulkCompleteTasksHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func BulkCompleteTasksHandler(client todoist.API, syncClient todoist.SyncAPI, confirmations *ConfirmationStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 
-		var taskIDs []string
+		ctx, err := bulkStrategyArg(ctx, args)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
 
-		if filter, ok := args["filter"].(string); ok && filter != "" {
-			params := url.Values{}
-			params.Set("filter", filter)
-			path := "/tasks?" + params.Encode()
+		completedAt, err := completedAtArg(args)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		taskIDs, preview := selectBulkTaskIDs(ctx, client, confirmations, args, "bulk_complete_tasks")
+		if preview != nil {
+			return preview, nil
+		}
 
-			respBody, err := client.Get(ctx, path)
-			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to fetch tasks with filter: %v", err)), nil
-			}
+		atomic := atomicRequested(args)
 
-			var tasks []map[string]interface{}
-			if err := json.Unmarshal(respBody, &tasks); err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to parse tasks: %v", err)), nil
+		// Backdated completions cannot advance recurring tasks, so those are
+		// skipped and reported as failed. Atomic runs need the due dates of
+		// recurring tasks to move them back.
+		var skipped []string
+		recurringDue := make(map[string]*models.Due)
+		if (completedAt != "" || atomic) && len(taskIDs) > 0 {
+			params := url.Values{}
+			params.Set("ids", strings.Join(taskIDs, ","))
+			tasks, err := fetchTasks(ctx, client, params)
+			if err != nil {
+				return respond.ErrorFrom(err), nil
 			}
-
 			for _, task := range tasks {
-				if id, ok := task["id"].(string); ok {
-					taskIDs = append(taskIDs, id)
+				if !taskIsRecurring(task) {
+					continue
 				}
-			}
-		}
-
-		if taskIDsParam, ok := args["task_ids"].([]interface{}); ok && len(taskIDsParam) > 0 {
-			taskIDs = make([]string, 0, len(taskIDsParam))
-			for _, id := range taskIDsParam {
-				if idStr, ok := id.(string); ok {
-					taskIDs = append(taskIDs, idStr)
+				if completedAt != "" {
+					skipped = append(skipped, task.ID)
+				} else {
+					recurringDue[task.ID] = task.Due
 				}
 			}
 		}
-
-		if len(taskIDs) == 0 {
-			return mcp.NewToolResultError("either task_ids or filter must be provided and match at least one task"), nil
-		}
-
-		var successCount int
-		var failedTasks []string
-		var usedBatching bool
-
-		if len(taskIDs) > 5 {
-			usedBatching = true
-
-			commands := make([]todoist.Command, len(taskIDs))
-			for i, taskID := range taskIDs {
-				commands[i] = todoist.Command{
-					Type: "item_close",
-					UUID: todoist.GenerateUUID(),
-					Args: map[string]interface{}{
-						"id": taskID,
+		if atomic && len(skipped) > 0 {
+			return respond.Errorf("atomic complete stopped before changing anything: tasks %s are recurring, and completed_at cannot advance a recurring task", strings.Join(skipped, ", ")), nil
+		}
+
+		ops := make([]todoist.BulkOperation, 0, len(taskIDs))
+		for _, taskID := range taskIDs {
+			var op todoist.BulkOperation
+			switch {
+			case slices.Contains(skipped, taskID):
+				continue
+			case completedAt != "":
+				op = todoist.BulkOperation{ID: taskID, Command: itemCompleteCommand(taskID, completedAt)}
+			default:
+				op = todoist.BulkOperation{
+					ID: taskID,
+					Command: todoist.Command{
+						Type: "item_close",
+						UUID: todoist.GenerateUUID(),
+						Args: map[string]interface{}{
+							"id": taskID,
+						},
 					},
+					Method: http.MethodPost,
+					Path:   fmt.Sprintf("/tasks/%s/close", taskID),
 				}
 			}
-
-			syncResp, err := syncClient.BatchCommands(ctx, commands)
-			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to batch complete tasks: %v", err)), nil
-			}
-
-			for i, cmd := range commands {
-				status := syncResp.SyncStatus[cmd.UUID]
-				if statusStr, ok := status.(string); ok && statusStr == "ok" {
-					successCount++
-				} else {
-					failedTasks = append(failedTasks, taskIDs[i])
-				}
+			if due, ok := recurringDue[taskID]; ok {
+				op.Revert = restoreDueOperation(taskID, due)
+			} else {
+				op.Revert = reopenOperation(taskID)
 			}
-		} else {
-			usedBatching = false
+			ops = append(ops, op)
+		}
 
-			remaining := client.GetRemainingRequests()
-			if remaining < len(taskIDs) {
-				return mcp.NewToolResultError(fmt.Sprintf("insufficient rate limit capacity: need %d requests, have %d remaining in 15min window", len(taskIDs), remaining)), nil
-			}
+		if estimateCostRequested(args) {
+			return bulkCostResult(ctx, client, syncClient, ops)
+		}
 
-			for _, taskID := range taskIDs {
-				path := fmt.Sprintf("/tasks/%s/close", taskID)
-				_, err := client.Post(ctx, path, nil)
-				if err != nil {
-					failedTasks = append(failedTasks, taskID)
-					continue
-				}
-				successCount++
-			}
+		result, err := todoist.NewBulkExecutor(client, syncClient).Execute(ctx, ops)
+		if err != nil {
+			return respond.Errorf("failed to batch complete tasks: %v", err), nil
 		}
+		if atomic && len(result.Failed) > 0 {
+			return atomicFailure(ctx, client, syncClient, ops, result, "complete"), nil
+		}
+		successCount := len(result.Succeeded)
+		failedTasks := append(result.FailedIDs(), skipped...)
 
-		response := map[string]interface{}{
+		response := respond.Envelope{
 			"total_tasks":     len(taskIDs),
 			"completed":       successCount,
 			"failed":          len(failedTasks),
 			"failed_task_ids": failedTasks,
-			"used_batching":   usedBatching,
+			"used_batching":   result.Strategy == todoist.StrategySync,
+		}
+		if completedAt != "" {
+			response["completed_at"] = completedAt
+		}
+		for _, id := range skipped {
+			response.Warn("task %s is recurring and was skipped; completed_at cannot advance a recurring task", id)
 		}
 
 		if len(failedTasks) == 0 {
-			response["message"] = fmt.Sprintf("Successfully completed %d tasks", successCount)
+			response["message"] = localize("bulk_completed", successCount)
 		} else {
-			response["message"] = fmt.Sprintf("Completed %d of %d tasks (%d failed)", successCount, len(taskIDs), len(failedTasks))
+			response["message"] = localize("bulk_completed_partial", successCount, len(taskIDs), len(failedTasks))
 		}
 
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
 
-// BatchCreateTasksHandler creates a handler for creating multiple tasks in one batch.
-func BatchCreateTasksHandler(syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// BatchCreateTasksHandler creates a handler for creating multiple tasks in Sync
+// batches of up to todoist.MaxSyncCommands. Each task must meet the policy of
+// its project.
+func BatchCreateTasksHandler(client todoist.API, syncClient todoist.SyncAPI, policies CreationPolicies) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 
 		tasksParam, ok := args["tasks"].([]interface{})
 		if !ok || len(tasksParam) == 0 {
-			return mcp.NewToolResultError("tasks array is required and must contain at least one task"), nil
+			return respond.Error("tasks array is required and must contain at least one task"), nil
 		}
 
 		commands := make([]todoist.Command, 0, len(tasksParam))
 		tempIDs := make([]string, len(tasksParam))
+		// pending maps the temp IDs of earlier tasks to their projects, for
+		// subtasks of tasks created in the same batch.
+		pending := make(map[string]string)
+		fixes := make(map[int]map[string]interface{})
 
 		for i, taskParam := range tasksParam {
 			taskMap, ok := taskParam.(map[string]interface{})
 			if !ok {
-				return mcp.NewToolResultError(fmt.Sprintf("task at index %d is not a valid object", i)), nil
+				return respond.Errorf("task at index %d is not a valid object", i), nil
 			}
 
 			content, ok := taskMap["content"].(string)
 			if !ok || content == "" {
-				return mcp.NewToolResultError(fmt.Sprintf("task at index %d missing required 'content' field", i)), nil
+				return respond.Errorf("task at index %d missing required 'content' field", i), nil
 			}
 
 			tempID := todoist.GenerateTempID()
@@ -790,6 +1483,14 @@ func BatchCreateTasksHandler(syncClient todoist.SyncAPI) func(context.Context, m
 			} else if parentID, ok := taskMap["parent_id"].(string); ok && parentID != "" {
 				cmdArgs["parent_id"] = parentID
 			}
+			projectID, fixed, err := policies.enforce(ctx, client, cmdArgs, pending)
+			if err != nil {
+				return respond.Errorf("task at index %d: %v", i, err), nil
+			}
+			pending[tempID] = projectID
+			if len(fixed) > 0 {
+				fixes[i] = fixed
+			}
 
 			commands = append(commands, todoist.Command{
 				Type:   "item_add",
@@ -799,9 +1500,17 @@ func BatchCreateTasksHandler(syncClient todoist.SyncAPI) func(context.Context, m
 			})
 		}
 
-		syncResp, err := syncClient.BatchCommands(ctx, commands)
+		// Large batches span several Sync requests; SendCommands carries the
+		// parents' IDs across them and reports progress.
+		syncResp, err := todoist.SendCommands(ctx, syncClient, commands)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to batch create tasks: %v", err)), nil
+			return respond.Errorf("failed to batch create tasks: %v", err), nil
+		}
+
+		if atomicRequested(args) {
+			if failure := rollBackBatchCreate(ctx, client, syncClient, commands, syncResp); failure != nil {
+				return failure, nil
+			}
 		}
 
 		createdTasks := make([]map[string]interface{}, 0)
@@ -818,6 +1527,9 @@ func BatchCreateTasksHandler(syncClient todoist.SyncAPI) func(context.Context, m
 					taskInfo["id"] = realID
 				}
 				taskInfo["content"] = cmd.Args["content"]
+				if fixed, ok := fixes[i]; ok {
+					taskInfo["policy_fixes"] = fixed
+				}
 				createdTasks = append(createdTasks, taskInfo)
 			} else {
 				failedIndices = append(failedIndices, i)
@@ -834,159 +1546,273 @@ func BatchCreateTasksHandler(syncClient todoist.SyncAPI) func(context.Context, m
 		}
 
 		if len(failedIndices) == 0 {
-			response["message"] = fmt.Sprintf("Successfully created %d tasks in a single batch", len(createdTasks))
+			response["message"] = localize("bulk_created", len(createdTasks))
 		} else {
-			response["message"] = fmt.Sprintf("Created %d of %d tasks (%d failed)", len(createdTasks), len(commands), len(failedIndices))
-		}
-
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			response["message"] = localize("bulk_created_partial", len(createdTasks), len(commands), len(failedIndices))
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
 
 // MoveTasksHandler creates a handler for moving multiple tasks to a different project.
-func MoveTasksHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func MoveTasksHandler(client todoist.API, syncClient todoist.SyncAPI, confirmations *ConfirmationStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args := req.GetArguments()
 
+		ctx, err := bulkStrategyArg(ctx, args)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
 		toProjectID, ok := args["to_project_id"].(string)
 		if !ok || toProjectID == "" {
-			return mcp.NewToolResultError("to_project_id is required"), nil
+			return respond.Error("to_project_id is required"), nil
 		}
 		if err := ValidateID(toProjectID, "to_project_id"); err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return respond.ErrorFrom(err), nil
 		}
 
-		var taskIDs []string
+		taskIDs, preview := selectBulkTaskIDs(ctx, client, confirmations, args, "move_tasks", toProjectID)
+		if preview != nil {
+			return preview, nil
+		}
 
-		if filter, ok := args["filter"].(string); ok && filter != "" {
-			params := url.Values{}
-			params.Set("filter", filter)
-			path := "/tasks?" + params.Encode()
+		preserveSections, _ := args["preserve_sections"].(bool)
+		estimate := estimateCostRequested(args)
 
-			respBody, err := client.Get(ctx, path)
+		// Map each task's section to the destination section of the same
+		// name. Estimates skip this so they never create sections.
+		var sectionMap *sectionMapping
+		if preserveSections && !estimate {
+			var err error
+			sectionMap, err = mapSectionsForMove(ctx, client, taskIDs, toProjectID)
 			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to fetch tasks with filter: %v", err)), nil
-			}
-
-			var tasks []map[string]interface{}
-			if err := json.Unmarshal(respBody, &tasks); err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to parse tasks: %v", err)), nil
+				return respond.Errorf("failed to map sections: %v", err), nil
 			}
+		}
 
-			for _, task := range tasks {
-				if id, ok := task["id"].(string); ok {
-					taskIDs = append(taskIDs, id)
-				}
+		// Atomic runs need each task's place to move it back.
+		atomic := atomicRequested(args)
+		var origins map[string]models.Task
+		if atomic && !estimate {
+			origins, err = fetchTasksByIDs(ctx, client, taskIDs)
+			if err != nil {
+				return respond.Errorf("failed to fetch tasks to move: %v", err), nil
 			}
 		}
 
-		if taskIDsParam, ok := args["task_ids"].([]interface{}); ok && len(taskIDsParam) > 0 {
-			taskIDs = make([]string, 0, len(taskIDsParam))
-			for _, id := range taskIDsParam {
-				if idStr, ok := id.(string); ok {
-					taskIDs = append(taskIDs, idStr)
-				}
+		// The REST API cannot change a task's project, so moves always go
+		// through the Sync API's item_move.
+		ops := make([]todoist.BulkOperation, len(taskIDs))
+		for i, taskID := range taskIDs {
+			moveArgs := map[string]interface{}{
+				"id":         taskID,
+				"project_id": toProjectID,
+			}
+			if sectionID := sectionMap.target(taskID); sectionID != "" {
+				// item_move takes exactly one destination.
+				delete(moveArgs, "project_id")
+				moveArgs["section_id"] = sectionID
+			}
+			ops[i] = todoist.BulkOperation{
+				ID: taskID,
+				Command: todoist.Command{
+					Type: "item_move",
+					UUID: todoist.GenerateUUID(),
+					Args: moveArgs,
+				},
+			}
+			if task, ok := origins[taskID]; ok {
+				ops[i].Revert = moveBackOperation(task)
 			}
 		}
 
-		if len(taskIDs) == 0 {
-			return mcp.NewToolResultError("either task_ids or filter must be provided and match at least one task"), nil
+		if estimate {
+			return bulkCostResult(ctx, client, syncClient, ops)
 		}
 
 		projectPath := fmt.Sprintf("/projects/%s", toProjectID)
 		projectResp, err := client.Get(ctx, projectPath)
 		var toProjectName string
 		if err == nil {
-			var project map[string]interface{}
+			var project models.Project
 			if json.Unmarshal(projectResp, &project) == nil {
-				if name, ok := project["name"].(string); ok {
-					toProjectName = name
-				}
+				toProjectName = project.Name
 			}
 		}
 		if toProjectName == "" {
 			toProjectName = toProjectID
 		}
 
-		var successCount int
-		var failedTasks []string
-		var usedBatching bool
+		result, err := todoist.NewBulkExecutor(client, syncClient).Execute(ctx, ops)
+		if err != nil {
+			return respond.Errorf("failed to batch move tasks: %v", err), nil
+		}
+		if atomic && len(result.Failed) > 0 {
+			return atomicFailure(ctx, client, syncClient, ops, result, "move"), nil
+		}
+		successCount := len(result.Succeeded)
+		failedTasks := result.FailedIDs()
 
-		if len(taskIDs) > 5 {
-			usedBatching = true
+		response := map[string]interface{}{
+			"total_tasks":     len(taskIDs),
+			"moved":           successCount,
+			"failed":          len(failedTasks),
+			"failed_task_ids": failedTasks,
+			"to_project":      toProjectName,
+			"used_batching":   result.Strategy == todoist.StrategySync,
+		}
+		if sectionMap != nil {
+			response["sections_matched"] = sectionMap.matched
+			response["sections_created"] = sectionMap.created
+		}
 
-			commands := make([]todoist.Command, len(taskIDs))
-			for i, taskID := range taskIDs {
-				commands[i] = todoist.Command{
-					Type: "item_update",
-					UUID: todoist.GenerateUUID(),
-					Args: map[string]interface{}{
-						"id":         taskID,
-						"project_id": toProjectID,
-					},
-				}
-			}
+		if len(failedTasks) == 0 {
+			response["message"] = localize("bulk_moved", successCount, toProjectName)
+		} else {
+			response["message"] = localize("bulk_moved_partial", successCount, len(taskIDs), toProjectName, len(failedTasks))
+		}
 
-			syncResp, err := syncClient.BatchCommands(ctx, commands)
+		return respond.JSON(response), nil
+	}
+}
+
+// sectionMapping maps tasks being moved to same-named sections in the
+// destination project.
+type sectionMapping struct {
+	// byTask maps task IDs to destination section IDs.
+	byTask  map[string]string
+	matched []string
+	created []string
+}
+
+// target returns the destination section for a task, or "" for the project root.
+func (m *sectionMapping) target(taskID string) string {
+	if m == nil {
+		return ""
+	}
+	return m.byTask[taskID]
+}
+
+// mapSectionsForMove looks up the sections of the tasks being moved and finds
+// the destination section of the same name (case-insensitive), creating it
+// when the destination project has none. Tasks without a section map to the
+// project root.
+func mapSectionsForMove(ctx context.Context, client todoist.API, taskIDs []string, toProjectID string) (*sectionMapping, error) {
+	params := url.Values{}
+	params.Set("ids", strings.Join(taskIDs, ","))
+	tasks, err := fetchTasks(ctx, client, params)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := client.Get(ctx, "/sections")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sections: %w", err)
+	}
+	var sections []models.Section
+	if err := json.Unmarshal(respBody, &sections); err != nil {
+		return nil, fmt.Errorf("failed to parse sections: %w", err)
+	}
+	names := make(map[string]string)
+	destination := make(map[string]string)
+	for _, section := range sections {
+		names[section.ID] = section.Name
+		if section.ProjectID == toProjectID {
+			destination[strings.ToLower(section.Name)] = section.ID
+		}
+	}
+
+	m := &sectionMapping{byTask: make(map[string]string), matched: []string{}, created: []string{}}
+	for _, task := range tasks {
+		name := names[task.SectionID]
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		id, ok := destination[key]
+		if !ok {
+			created, err := client.Post(ctx, "/sections", map[string]interface{}{"name": name, "project_id": toProjectID})
 			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to batch move tasks: %v", err)), nil
+				return nil, fmt.Errorf("failed to create section %q: %w", name, err)
 			}
-
-			for i, cmd := range commands {
-				status := syncResp.SyncStatus[cmd.UUID]
-				if statusStr, ok := status.(string); ok && statusStr == "ok" {
-					successCount++
-				} else {
-					failedTasks = append(failedTasks, taskIDs[i])
-				}
+			var section models.Section
+			if err := json.Unmarshal(created, &section); err != nil {
+				return nil, fmt.Errorf("failed to parse created section: %w", err)
 			}
-		} else {
-			usedBatching = false
+			id = section.ID
+			destination[key] = id
+			m.created = append(m.created, name)
+		} else if !slices.Contains(m.matched, name) && !slices.Contains(m.created, name) {
+			m.matched = append(m.matched, name)
+		}
+		m.byTask[task.ID] = id
+	}
+	return m, nil
+}
 
-			remaining := client.GetRemainingRequests()
-			if remaining < len(taskIDs) {
-				return mcp.NewToolResultError(fmt.Sprintf("insufficient rate limit capacity: need %d requests, have %d remaining in 15min window", len(taskIDs), remaining)), nil
-			}
+// BulkDeleteTasksHandler creates a handler for deleting multiple tasks in one Sync API batch.
+func BulkDeleteTasksHandler(client todoist.API, syncClient todoist.SyncAPI, confirmations *ConfirmationStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
 
-			for _, taskID := range taskIDs {
-				path := fmt.Sprintf("/tasks/%s", taskID)
-				body := map[string]interface{}{
-					"project_id": toProjectID,
-				}
-				_, err := client.Post(ctx, path, body)
-				if err != nil {
-					failedTasks = append(failedTasks, taskID)
-					continue
-				}
-				successCount++
+		ctx, err := bulkStrategyArg(ctx, args)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		taskIDs, preview := selectBulkTaskIDs(ctx, client, confirmations, args, "bulk_delete_tasks")
+		if preview != nil {
+			return preview, nil
+		}
+		if len(taskIDs) > 100 {
+			return respond.Error("maximum 100 tasks per batch"), nil
+		}
+
+		ops := make([]todoist.BulkOperation, len(taskIDs))
+		for i, taskID := range taskIDs {
+			if err := ValidateID(taskID, "task_id"); err != nil {
+				return respond.ErrorFrom(err), nil
 			}
+			ops[i] = todoist.BulkOperation{
+				ID: taskID,
+				Command: todoist.Command{
+					Type: "item_delete",
+					UUID: todoist.GenerateUUID(),
+					Args: map[string]interface{}{
+						"id": taskID,
+					},
+				},
+				Method: http.MethodDelete,
+				Path:   fmt.Sprintf("/tasks/%s", taskID),
+			}
+		}
+
+		if estimateCostRequested(args) {
+			return bulkCostResult(ctx, client, syncClient, ops)
+		}
+
+		result, err := todoist.NewBulkExecutor(client, syncClient).Execute(ctx, ops)
+		if err != nil {
+			return respond.Errorf("failed to batch delete tasks: %v", err), nil
 		}
+		successCount := len(result.Succeeded)
+		failedTasks := result.FailedIDs()
 
 		response := map[string]interface{}{
 			"total_tasks":     len(taskIDs),
-			"moved":           successCount,
+			"deleted":         successCount,
 			"failed":          len(failedTasks),
 			"failed_task_ids": failedTasks,
-			"to_project":      toProjectName,
-			"used_batching":   usedBatching,
 		}
 
 		if len(failedTasks) == 0 {
-			response["message"] = fmt.Sprintf("Successfully moved %d tasks to '%s'", successCount, toProjectName)
+			response["message"] = localize("bulk_deleted", successCount)
 		} else {
-			response["message"] = fmt.Sprintf("Moved %d of %d tasks to '%s' (%d failed)", successCount, len(taskIDs), toProjectName, len(failedTasks))
-		}
-
-		jsonData, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			response["message"] = localize("bulk_deleted_partial", successCount, len(taskIDs), len(failedTasks))
 		}
 
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return respond.JSON(response), nil
 	}
 }
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/rgabriel/mcp-todoist/todoist"
 )
 
@@ -74,7 +77,7 @@ func TestSearchTasksHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			client := &MockAPI{GetFn: tt.mockGet}
-			handler := SearchTasksHandler(client)
+			handler := SearchTasksHandler(client, &MockSyncAPI{})
 			result, err := handler(context.Background(), makeReq(tt.args))
 			if err != nil {
 				t.Fatalf("unexpected Go error: %v", err)
@@ -103,6 +106,272 @@ func TestSearchTasksHandler(t *testing.T) {
 	}
 }
 
+func TestSearchTasksHandler_CreatedFilters(t *testing.T) {
+	client := &MockAPI{
+		GetFn: func(_ context.Context, _ string) ([]byte, error) {
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1", "created_at": "2026-01-01T10:00:00Z", "creator_id": "me", "deadline": map[string]interface{}{"date": "2026-03-01"}},
+				{"id": "2", "created_at": "2026-01-10T10:00:00.123456Z", "creator_id": "other", "deadline": map[string]interface{}{"date": "2026-03-15"}},
+				{"id": "3", "created_at": "2026-01-12T10:00:00Z", "creator_id": "me"},
+				{"id": "4"},
+			})
+		},
+	}
+	syncClient := &MockSyncAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if path != "/user" {
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			}
+			return json.Marshal(map[string]interface{}{"id": "me"})
+		},
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantIDs   []string
+		wantErr   bool
+		errSubstr string
+	}{
+		{
+			name:    "created_after",
+			args:    map[string]interface{}{"created_after": "2026-01-05"},
+			wantIDs: []string{"2", "3"},
+		},
+		{
+			name:    "created range",
+			args:    map[string]interface{}{"created_after": "2026-01-05", "created_before": "2026-01-11T00:00:00Z"},
+			wantIDs: []string{"2"},
+		},
+		{
+			name:    "added_by_me",
+			args:    map[string]interface{}{"added_by_me": true},
+			wantIDs: []string{"1", "3"},
+		},
+		{
+			name:    "deadline range",
+			args:    map[string]interface{}{"deadline_from": "2026-03-01", "deadline_to": "2026-03-10"},
+			wantIDs: []string{"1"},
+		},
+		{
+			name:    "deadline_from only",
+			args:    map[string]interface{}{"deadline_from": "2026-03-02"},
+			wantIDs: []string{"2"},
+		},
+		{
+			name:      "invalid deadline_to",
+			args:      map[string]interface{}{"deadline_to": "next month"},
+			wantErr:   true,
+			errSubstr: "deadline_to must be",
+		},
+		{
+			name:      "invalid created_after",
+			args:      map[string]interface{}{"created_after": "last week"},
+			wantErr:   true,
+			errSubstr: "created_after must be",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := SearchTasksHandler(client, syncClient)
+			result, err := handler(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError || !strings.Contains(text, tt.errSubstr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.errSubstr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				Tasks []map[string]interface{} `json:"tasks"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if len(resp.Tasks) != len(tt.wantIDs) {
+				t.Fatalf("got %d tasks, want %d", len(resp.Tasks), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if resp.Tasks[i]["id"] != id {
+					t.Errorf("tasks[%d].id = %v, want %s", i, resp.Tasks[i]["id"], id)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchTasksHandler_IncludeCompleted(t *testing.T) {
+	client := &MockAPI{
+		GetFn: func(_ context.Context, _ string) ([]byte, error) {
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1", "content": "Draft contract"},
+			})
+		},
+	}
+	syncClient := &MockSyncAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if !strings.HasPrefix(path, "/tasks/completed/by_completion_date?") {
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			}
+			if !strings.Contains(path, "filter_query=search%3A+contract") {
+				return nil, fmt.Errorf("expected filter_query in path, got: %s", path)
+			}
+			return json.Marshal(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"id": "9", "content": "Sign contract", "labels": []string{"legal"}},
+					{"id": "8", "content": "Send contract", "labels": []string{"mail"}},
+				},
+			})
+		},
+	}
+
+	handler := SearchTasksHandler(client, syncClient)
+	result, err := handler(context.Background(), makeReq(map[string]interface{}{
+		"filter":            "search: contract",
+		"include_completed": true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+
+	var resp struct {
+		Count          int                      `json:"count"`
+		CompletedCount int                      `json:"completed_count"`
+		Tasks          []map[string]interface{} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Count != 3 || resp.CompletedCount != 2 {
+		t.Fatalf("count = %d, completed_count = %d, want 3 and 2", resp.Count, resp.CompletedCount)
+	}
+	if resp.Tasks[0]["completed"] != false || resp.Tasks[1]["completed"] != true {
+		t.Errorf("completed markers = %v, %v", resp.Tasks[0]["completed"], resp.Tasks[1]["completed"])
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{
+		"filter":            "search: contract",
+		"label":             "legal",
+		"include_completed": true,
+	}))
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.CompletedCount != 1 {
+		t.Errorf("completed_count with label = %d, want 1", resp.CompletedCount)
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{
+		"filter":            "search: contract",
+		"created_after":     "2024-01-01",
+		"include_completed": true,
+	}))
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Count != 0 || resp.CompletedCount != 0 {
+		t.Errorf("count = %d, completed_count = %d after filtering, want 0 and 0", resp.Count, resp.CompletedCount)
+	}
+}
+
+func TestSearchTasksHandler_IncludeSubprojects(t *testing.T) {
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			switch path {
+			case "/projects":
+				return json.Marshal([]map[string]interface{}{
+					{"id": "p1", "name": "Work"},
+					{"id": "p2", "name": "Clients", "parent_id": "p1"},
+					{"id": "p3", "name": "Home"},
+				})
+			case "/tasks":
+				return json.Marshal([]map[string]interface{}{
+					{"id": "1", "project_id": "p1"},
+					{"id": "2", "project_id": "p2"},
+					{"id": "3", "project_id": "p3"},
+				})
+			}
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		},
+	}
+
+	handler := SearchTasksHandler(client, &MockSyncAPI{})
+	result, err := handler(context.Background(), makeReq(map[string]interface{}{
+		"project_id":          "p1",
+		"include_subprojects": true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+
+	var resp struct {
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Count != 2 {
+		t.Errorf("count = %d, want 2", resp.Count)
+	}
+}
+
+func TestSearchTasksHandler_GroupByDay(t *testing.T) {
+	client := &MockAPI{GetFn: func(_ context.Context, _ string) ([]byte, error) {
+		return json.Marshal([]map[string]interface{}{
+			{"id": "late", "due": map[string]interface{}{"date": "2026-03-02", "datetime": "2026-03-02T16:00:00Z"}},
+			{"id": "undated"},
+			{"id": "next", "due": map[string]interface{}{"date": "2026-03-03"}},
+			{"id": "early", "due": map[string]interface{}{"date": "2026-03-02", "datetime": "2026-03-02T09:00:00Z"}},
+			{"id": "allday", "due": map[string]interface{}{"date": "2026-03-02"}},
+		})
+	}}
+
+	result, err := SearchTasksHandler(client, &MockSyncAPI{})(context.Background(), makeReq(map[string]interface{}{"group_by_day": true}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+	var resp struct {
+		Tasks []map[string]interface{} `json:"tasks"`
+		Days  []struct {
+			Date   *string                  `json:"date"`
+			AllDay []map[string]interface{} `json:"all_day"`
+			Timed  []map[string]interface{} `json:"timed"`
+		} `json:"days"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	timed := make(map[string]bool)
+	for _, task := range resp.Tasks {
+		timed[task["id"].(string)] = task["is_timed"].(bool)
+	}
+	if !timed["late"] || !timed["early"] || timed["allday"] || timed["undated"] {
+		t.Errorf("is_timed = %v", timed)
+	}
+
+	if len(resp.Days) != 3 || *resp.Days[0].Date != "2026-03-02" || *resp.Days[1].Date != "2026-03-03" || resp.Days[2].Date != nil {
+		t.Fatalf("days = %+v", resp.Days)
+	}
+	first := resp.Days[0]
+	if len(first.AllDay) != 1 || len(first.Timed) != 2 || first.Timed[0]["id"] != "early" || first.Timed[1]["id"] != "late" {
+		t.Errorf("first day = %+v", first)
+	}
+}
+
 func TestGetTaskHandler(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -147,7 +416,7 @@ func TestGetTaskHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			client := &MockAPI{GetFn: tt.mockGet}
-			handler := GetTaskHandler(client)
+			handler := GetTaskHandler(client, &MockSyncAPI{})
 			result, err := handler(context.Background(), makeReq(tt.args))
 			if err != nil {
 				t.Fatalf("unexpected Go error: %v", err)
@@ -169,6 +438,98 @@ func TestGetTaskHandler(t *testing.T) {
 	}
 }
 
+func TestGetTaskHandler_IncludeContext(t *testing.T) {
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			switch path {
+			case "/tasks/3":
+				return json.Marshal(map[string]interface{}{"id": "3", "content": "Leaf", "parent_id": "2", "project_id": "p2", "section_id": "s1"})
+			case "/tasks/2":
+				return json.Marshal(map[string]interface{}{"id": "2", "content": "Middle", "parent_id": "1"})
+			case "/tasks/1":
+				return json.Marshal(map[string]interface{}{"id": "1", "content": "Root"})
+			case "/projects":
+				return json.Marshal([]map[string]interface{}{
+					{"id": "p1", "name": "Work"},
+					{"id": "p2", "name": "Clients", "parent_id": "p1"},
+				})
+			case "/sections/s1":
+				return json.Marshal(map[string]interface{}{"id": "s1", "name": "Backlog"})
+			}
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		},
+	}
+
+	handler := GetTaskHandler(client, &MockSyncAPI{})
+	result, err := handler(context.Background(), makeReq(map[string]interface{}{"task_id": "3", "include_context": true}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp["breadcrumb"] != "Work > Clients > Backlog" {
+		t.Errorf("breadcrumb = %v, want %q", resp["breadcrumb"], "Work > Clients > Backlog")
+	}
+	chain := resp["parent_chain"].([]interface{})
+	if len(chain) != 2 {
+		t.Fatalf("parent_chain length = %d, want 2", len(chain))
+	}
+	if chain[0].(map[string]interface{})["id"] != "1" {
+		t.Errorf("parent_chain[0] = %v, want root task 1", chain[0])
+	}
+}
+
+func TestGetTaskHandler_WorkspaceBreadcrumb(t *testing.T) {
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		switch path {
+		case "/tasks/3":
+			return json.Marshal(map[string]interface{}{"id": "3", "content": "Leaf", "project_id": "p2", "section_id": "s1"})
+		case "/projects":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "p1", "name": "Engineering", "workspace_id": "w1"},
+				{"id": "p2", "name": "Backend", "parent_id": "p1", "workspace_id": "w1"},
+			})
+		case "/sections/s1":
+			return json.Marshal(map[string]interface{}{"id": "s1", "name": "Backlog"})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}}
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if !strings.Contains(path, "workspaces") {
+			return nil, fmt.Errorf("unexpected sync path: %s", path)
+		}
+		return json.Marshal(map[string]interface{}{"workspaces": []map[string]interface{}{
+			{"id": "w0", "name": "Other"},
+			{"id": "w1", "name": "Acme"},
+		}})
+	}}
+
+	result, _ := GetTaskHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{"task_id": "3", "include_context": true}))
+	var resp map[string]interface{}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %s", resultText(result))
+	}
+	if want := "Acme > Engineering > Backend > Backlog"; resp["breadcrumb"] != want {
+		t.Errorf("breadcrumb = %v, want %q", resp["breadcrumb"], want)
+	}
+
+	// Without the workspace name, the rest of the path is still returned.
+	syncClient.GetFn = func(_ context.Context, _ string) ([]byte, error) { return nil, fmt.Errorf("boom") }
+	result, _ = GetTaskHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{"task_id": "3", "include_context": true}))
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %s", resultText(result))
+	}
+	if want := "Engineering > Backend > Backlog"; resp["breadcrumb"] != want {
+		t.Errorf("breadcrumb after failed lookup = %v, want %q", resp["breadcrumb"], want)
+	}
+}
+
 func TestCreateTaskHandler(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -240,7 +601,7 @@ func TestCreateTaskHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			client := &MockAPI{PostFn: tt.mockPost}
-			handler := CreateTaskHandler(client)
+			handler := CreateTaskHandler(client, TaskDefaults{}, nil)
 			result, err := handler(context.Background(), makeReq(tt.args))
 			if err != nil {
 				t.Fatalf("unexpected Go error: %v", err)
@@ -262,6 +623,41 @@ func TestCreateTaskHandler(t *testing.T) {
 	}
 }
 
+func TestCreateTaskHandler_Defaults(t *testing.T) {
+	defaults := TaskDefaults{ProjectID: "p-default", Labels: []string{"agent"}, Priority: 2, DueString: "today"}
+	var posted map[string]interface{}
+	client := &MockAPI{PostFn: func(_ context.Context, _ string, body interface{}) ([]byte, error) {
+		posted = body.(map[string]interface{})
+		return json.Marshal(map[string]interface{}{"id": "1", "content": posted["content"]})
+	}}
+	handler := CreateTaskHandler(client, defaults, nil)
+
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"content": "Buy milk"}))
+	if posted["project_id"] != "p-default" || posted["priority"] != 2 || posted["due_string"] != "today" {
+		t.Errorf("posted = %v", posted)
+	}
+	var resp struct {
+		AppliedDefaults map[string]interface{} `json:"applied_defaults"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("parse: %v (%s)", err, resultText(result))
+	}
+	if len(resp.AppliedDefaults) != 4 || resp.AppliedDefaults["project_id"] != "p-default" {
+		t.Errorf("applied_defaults = %v", resp.AppliedDefaults)
+	}
+
+	// Given fields win; a subtask keeps its parent's project.
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{
+		"content": "Buy milk", "parent_id": "9", "labels": []interface{}{"home"}, "priority": float64(4), "due_date": "2026-11-02",
+	}))
+	if _, ok := posted["project_id"]; ok || posted["priority"] != 4 || posted["due_string"] != nil {
+		t.Errorf("posted = %v", posted)
+	}
+	if text := resultText(result); strings.Contains(text, "applied_defaults") {
+		t.Errorf("result = %s", text)
+	}
+}
+
 func TestUpdateTaskHandler(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -331,6 +727,129 @@ func TestUpdateTaskHandler(t *testing.T) {
 	}
 }
 
+func TestUpdateTaskHandler_MergeStrategy(t *testing.T) {
+	tests := []struct {
+		strategy string
+		labels   []interface{}
+		want     []string
+	}{
+		{"", []interface{}{"urgent"}, []string{"urgent"}},
+		{"union", []interface{}{"Work", "urgent"}, []string{"work", "waiting", "urgent"}},
+		{"subtract", []interface{}{"WAITING"}, []string{"work"}},
+		{"subtract", []interface{}{"work", "waiting"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			gets := 0
+			var sent []string
+			client := &MockAPI{
+				GetFn: func(_ context.Context, path string) ([]byte, error) {
+					gets++
+					return json.Marshal(map[string]interface{}{"id": "123", "labels": []string{"work", "waiting"}})
+				},
+				PostFn: func(_ context.Context, _ string, body interface{}) ([]byte, error) {
+					sent = body.(map[string]interface{})["labels"].([]string)
+					return json.Marshal(map[string]interface{}{"id": "123", "labels": sent})
+				},
+			}
+			args := map[string]interface{}{"task_id": "123", "labels": tt.labels}
+			if tt.strategy != "" {
+				args["merge_strategy"] = tt.strategy
+			}
+			result, _ := UpdateTaskHandler(client)(context.Background(), makeReq(args))
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", resultText(result))
+			}
+			if !slices.Equal(sent, tt.want) {
+				t.Errorf("labels = %v, want %v", sent, tt.want)
+			}
+			if wantGets := map[bool]int{true: 0, false: 1}[tt.strategy == ""]; gets != wantGets {
+				t.Errorf("fetched the task %d times, want %d", gets, wantGets)
+			}
+		})
+	}
+
+	result, _ := UpdateTaskHandler(&MockAPI{})(context.Background(), makeReq(map[string]interface{}{
+		"task_id": "123", "labels": []interface{}{"x"}, "merge_strategy": "merge",
+	}))
+	if !result.IsError || !strings.Contains(resultText(result), "merge_strategy") {
+		t.Errorf("result = %q, want merge_strategy error", resultText(result))
+	}
+}
+
+func TestTaskHandlers_Warnings(t *testing.T) {
+	warningsOf := func(t *testing.T, result *mcp.CallToolResult) []string {
+		t.Helper()
+		if result.IsError {
+			t.Fatalf("unexpected tool error: %s", resultText(result))
+		}
+		var body struct {
+			Warnings []string `json:"warnings"`
+		}
+		if err := json.Unmarshal([]byte(resultText(result)), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return body.Warnings
+	}
+
+	t.Run("unparsed due_string", func(t *testing.T) {
+		client := &MockAPI{PostFn: func(_ context.Context, _ string, _ interface{}) ([]byte, error) {
+			return []byte(`{"id": "1", "content": "Call Bob", "due": null}`), nil
+		}}
+		result, _ := CreateTaskHandler(client, TaskDefaults{}, nil)(context.Background(), makeReq(map[string]interface{}{
+			"content": "Call Bob", "due_string": "someday soonish",
+		}))
+		warnings := warningsOf(t, result)
+		if len(warnings) != 1 || !strings.Contains(warnings[0], `"someday soonish" could not be parsed`) {
+			t.Errorf("warnings = %v", warnings)
+		}
+
+		result, _ = UpdateTaskHandler(client)(context.Background(), makeReq(map[string]interface{}{
+			"task_id": "1", "due_string": "no date",
+		}))
+		if warnings := warningsOf(t, result); len(warnings) != 0 {
+			t.Errorf("clearing the due date warned: %v", warnings)
+		}
+	})
+
+	t.Run("subtracted label not on task", func(t *testing.T) {
+		client := &MockAPI{
+			GetFn: func(_ context.Context, _ string) ([]byte, error) {
+				return []byte(`{"id": "1", "labels": ["work"]}`), nil
+			},
+			PostFn: func(_ context.Context, _ string, _ interface{}) ([]byte, error) {
+				return []byte(`{"id": "1", "labels": []}`), nil
+			},
+		}
+		result, _ := UpdateTaskHandler(client)(context.Background(), makeReq(map[string]interface{}{
+			"task_id": "1", "labels": []interface{}{"work", "foo"}, "merge_strategy": "subtract",
+		}))
+		warnings := warningsOf(t, result)
+		if len(warnings) != 1 || warnings[0] != `label "foo" is not on the task and was skipped` {
+			t.Errorf("warnings = %v", warnings)
+		}
+	})
+
+	t.Run("empty filter and missing ids", func(t *testing.T) {
+		client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if strings.Contains(path, "ids=") {
+				return []byte(`[{"id": "1"}]`), nil
+			}
+			return []byte(`[]`), nil
+		}}
+		result, _ := SearchTasksHandler(client, &MockSyncAPI{})(context.Background(), makeReq(map[string]interface{}{"filter": "p1 & today"}))
+		if warnings := warningsOf(t, result); len(warnings) != 1 || warnings[0] != `filter "p1 & today" matched 0 tasks` {
+			t.Errorf("warnings = %v", warnings)
+		}
+
+		result, _ = SearchTasksHandler(client, &MockSyncAPI{})(context.Background(), makeReq(map[string]interface{}{"ids": []interface{}{"1", "2"}}))
+		if warnings := warningsOf(t, result); len(warnings) != 1 || warnings[0] != "task 2 was not found and was skipped" {
+			t.Errorf("warnings = %v", warnings)
+		}
+	})
+}
+
 func TestCompleteTaskHandler(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -369,7 +888,7 @@ func TestCompleteTaskHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			client := &MockAPI{PostFn: tt.mockPost}
-			handler := CompleteTaskHandler(client)
+			handler := CompleteTaskHandler(client, &MockSyncAPI{})
 			result, err := handler(context.Background(), makeReq(tt.args))
 			if err != nil {
 				t.Fatalf("unexpected Go error: %v", err)
@@ -412,18 +931,41 @@ func TestUncompleteTaskHandler(t *testing.T) {
 				return nil, nil
 			},
 		},
+		{
+			name: "completed item ID",
+			args: map[string]interface{}{"completed_item_id": "900"},
+			mockPost: func(_ context.Context, path string, _ interface{}) ([]byte, error) {
+				if path != "/tasks/123/reopen" {
+					return nil, fmt.Errorf("unexpected path: %s", path)
+				}
+				return nil, nil
+			},
+		},
+		{
+			name:      "unknown completed item ID",
+			args:      map[string]interface{}{"completed_item_id": "901"},
+			wantErr:   true,
+			errSubstr: "completed item 901 not found",
+		},
 		{
 			name:      "missing task_id",
 			args:      map[string]interface{}{},
 			wantErr:   true,
-			errSubstr: "task_id is required",
+			errSubstr: "task_id or completed_item_id is required",
 		},
 	}
 
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if !strings.HasPrefix(path, "/tasks/completed/by_completion_date?") {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return []byte(`{"items": [{"id": "900", "task_id": "123", "content": "Done"}], "next_cursor": null}`), nil
+	}}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			client := &MockAPI{PostFn: tt.mockPost}
-			handler := UncompleteTaskHandler(client)
+			handler := UncompleteTaskHandler(client, syncClient)
 			result, err := handler(context.Background(), makeReq(tt.args))
 			if err != nil {
 				t.Fatalf("unexpected Go error: %v", err)
@@ -485,7 +1027,15 @@ func TestDeleteTaskHandler(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client := &MockAPI{DeleteFn: tt.mockDel}
+			client := &MockAPI{
+				GetFn: func(_ context.Context, path string) ([]byte, error) {
+					if path == "/tasks/123" {
+						return []byte(`{"id":"123","project_id":"p1"}`), nil
+					}
+					return []byte(`[]`), nil
+				},
+				DeleteFn: tt.mockDel,
+			}
 			handler := DeleteTaskHandler(client)
 			result, err := handler(context.Background(), makeReq(tt.args))
 			if err != nil {
@@ -511,6 +1061,46 @@ func TestDeleteTaskHandler(t *testing.T) {
 	}
 }
 
+func TestDeleteTaskHandler_Subtasks(t *testing.T) {
+	var deleted []string
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if path == "/tasks/1" {
+				return []byte(`{"id":"1","project_id":"p1"}`), nil
+			}
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1", "project_id": "p1"},
+				{"id": "2", "project_id": "p1", "parent_id": "1"},
+				{"id": "3", "project_id": "p1", "parent_id": "2"},
+				{"id": "4", "project_id": "p1"},
+			})
+		},
+		DeleteFn: func(_ context.Context, path string) error {
+			deleted = append(deleted, path)
+			return nil
+		},
+	}
+	handler := DeleteTaskHandler(client)
+
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"task_id": "1"}))
+	text := resultText(result)
+	if !result.IsError || !strings.Contains(text, "2 subtask(s) (3, 2)") || !strings.Contains(text, "force") {
+		t.Errorf("without force: %s", text)
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("deleted without force: %v", deleted)
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"task_id": "1", "force": true}))
+	text = resultText(result)
+	if result.IsError || !strings.Contains(text, `"subtasks_deleted": 2`) || !strings.Contains(text, "warnings") {
+		t.Errorf("with force: %s", text)
+	}
+	if len(deleted) != 1 || deleted[0] != "/tasks/1" {
+		t.Errorf("deleted = %v", deleted)
+	}
+}
+
 func TestQuickAddTaskHandler(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -577,7 +1167,7 @@ func TestQuickAddTaskHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			client := &MockAPI{GetFn: tt.mockGet, PostFn: tt.mockPost}
-			handler := QuickAddTaskHandler(client)
+			handler := QuickAddTaskHandler(client, TaskDefaults{}, nil)
 			result, err := handler(context.Background(), makeReq(tt.args))
 			if err != nil {
 				t.Fatalf("unexpected Go error: %v", err)
@@ -599,6 +1189,31 @@ func TestQuickAddTaskHandler(t *testing.T) {
 	}
 }
 
+func TestQuickAddTaskHandler_Defaults(t *testing.T) {
+	var posted map[string]interface{}
+	client := &MockAPI{PostFn: func(_ context.Context, _ string, body interface{}) ([]byte, error) {
+		posted = body.(map[string]interface{})
+		return json.Marshal(map[string]interface{}{"id": "1"})
+	}}
+	handler := QuickAddTaskHandler(client, TaskDefaults{Labels: []string{"agent"}, DueString: "today"}, nil)
+
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"content": "Call mom @family tomorrow"}))
+	if posted["due_string"] != "tomorrow" || posted["project_id"] != nil {
+		t.Errorf("posted = %v", posted)
+	}
+	if labels, _ := posted["labels"].([]string); len(labels) != 1 || labels[0] != "family" {
+		t.Errorf("labels = %v", posted["labels"])
+	}
+	if text := resultText(result); strings.Contains(text, "applied_defaults") {
+		t.Errorf("result = %s", text)
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"content": "Call mom"}))
+	if text := resultText(result); posted["due_string"] != "today" || !strings.Contains(text, `"applied_defaults"`) || !strings.Contains(text, `"agent"`) {
+		t.Errorf("posted = %v, result = %s", posted, text)
+	}
+}
+
 func TestGetTaskStatsHandler(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -679,6 +1294,55 @@ func TestGetTaskStatsHandler(t *testing.T) {
 	}
 }
 
+func TestGetTaskStatsHandler_Deadlines(t *testing.T) {
+	day := func(offset int) string { return time.Now().AddDate(0, 0, offset).Format("2006-01-02") }
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if path == "/tasks" {
+			return json.Marshal([]map[string]interface{}{
+				{"id": "1", "content": "Late", "deadline": map[string]interface{}{"date": day(-1)}},
+				{"id": "2", "content": "Soon", "deadline": map[string]interface{}{"date": day(3)}},
+				{"id": "3", "content": "Far", "deadline": map[string]interface{}{"date": day(30)}},
+				{"id": "4", "content": "Scheduled too late", "due": map[string]interface{}{"date": day(5) + "T10:00:00"}, "deadline": map[string]interface{}{"date": day(2)}},
+				{"id": "5", "content": "No deadline", "due": map[string]interface{}{"date": day(1)}},
+			})
+		}
+		return []byte(`[]`), nil
+	}}
+
+	tests := []struct {
+		args            map[string]interface{}
+		wantApproaching int
+	}{
+		{args: nil, wantApproaching: 2},
+		{args: map[string]interface{}{"deadline_days": float64(60)}, wantApproaching: 3},
+	}
+	for _, tt := range tests {
+		result, err := GetTaskStatsHandler(client)(context.Background(), makeReq(tt.args))
+		if err != nil || result.IsError {
+			t.Fatalf("unexpected error: %v %s", err, resultText(result))
+		}
+		var stats struct {
+			Overdue          int                      `json:"deadlines_overdue"`
+			Approaching      int                      `json:"deadlines_approaching"`
+			DueAfterDeadline []map[string]interface{} `json:"due_after_deadline"`
+		}
+		if err := json.Unmarshal([]byte(resultText(result)), &stats); err != nil {
+			t.Fatalf("failed to parse stats: %v", err)
+		}
+		if stats.Overdue != 1 || stats.Approaching != tt.wantApproaching {
+			t.Errorf("args %v: overdue = %d, approaching = %d, want 1, %d", tt.args, stats.Overdue, stats.Approaching, tt.wantApproaching)
+		}
+		if len(stats.DueAfterDeadline) != 1 || stats.DueAfterDeadline[0]["id"] != "4" {
+			t.Errorf("due_after_deadline = %v, want task 4", stats.DueAfterDeadline)
+		}
+	}
+
+	result, _ := GetTaskStatsHandler(client)(context.Background(), makeReq(map[string]interface{}{"deadline_days": float64(-1)}))
+	if !result.IsError {
+		t.Error("expected error for negative deadline_days")
+	}
+}
+
 func TestBulkCompleteTasksHandler(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -712,7 +1376,7 @@ func TestBulkCompleteTasksHandler(t *testing.T) {
 			},
 		},
 		{
-			name: "with filter",
+			name: "filter without token returns preview",
 			args: map[string]interface{}{"filter": "today"},
 			mockGet: func(_ context.Context, _ string) ([]byte, error) {
 				return json.Marshal([]map[string]interface{}{
@@ -721,9 +1385,15 @@ func TestBulkCompleteTasksHandler(t *testing.T) {
 				})
 			},
 			mockPost: func(_ context.Context, _ string, _ interface{}) ([]byte, error) {
-				return nil, nil
+				return nil, fmt.Errorf("should not complete before confirmation")
 			},
 		},
+		{
+			name:      "filter with unknown token",
+			args:      map[string]interface{}{"filter": "today", "confirmation_token": "deadbeef"},
+			wantErr:   true,
+			errSubstr: "confirmation_token is invalid or expired",
+		},
 		{
 			name:      "no task_ids or filter",
 			args:      map[string]interface{}{},
@@ -747,7 +1417,7 @@ func TestBulkCompleteTasksHandler(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			client := &MockAPI{GetFn: tt.mockGet, PostFn: tt.mockPost}
 			syncClient := &MockSyncAPI{BatchCommandsFn: tt.mockBatch}
-			handler := BulkCompleteTasksHandler(client, syncClient)
+			handler := BulkCompleteTasksHandler(client, syncClient, NewConfirmationStore(time.Minute))
 			result, err := handler(context.Background(), makeReq(tt.args))
 			if err != nil {
 				t.Fatalf("unexpected Go error: %v", err)
@@ -829,7 +1499,7 @@ func TestBatchCreateTasksHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			syncClient := &MockSyncAPI{BatchCommandsFn: tt.mockBatch}
-			handler := BatchCreateTasksHandler(syncClient)
+			handler := BatchCreateTasksHandler(&MockAPI{}, syncClient, nil)
 			result, err := handler(context.Background(), makeReq(tt.args))
 			if err != nil {
 				t.Fatalf("unexpected Go error: %v", err)
@@ -851,6 +1521,51 @@ func TestBatchCreateTasksHandler(t *testing.T) {
 	}
 }
 
+func TestBatchCreateTasksHandler_Chunked(t *testing.T) {
+	var batches [][]todoist.Command
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		batches = append(batches, commands)
+		status := make(map[string]interface{})
+		mapping := make(map[string]string)
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+			mapping[cmd.TempID] = "real-" + cmd.TempID
+		}
+		return &todoist.SyncResponse{SyncStatus: status, TempIDMapping: mapping}, nil
+	}}
+
+	// The last task is a subtask of the first, which the first request creates.
+	n := todoist.MaxSyncCommands + 1
+	tasks := make([]interface{}, n)
+	for i := range tasks {
+		tasks[i] = map[string]interface{}{"content": fmt.Sprintf("Task %d", i)}
+	}
+	tasks[n-1].(map[string]interface{})["parent_temp_id"] = "0"
+
+	var progress []int
+	ctx := todoist.WithProgress(context.Background(), func(done, _ int) { progress = append(progress, done) })
+	result, err := BatchCreateTasksHandler(&MockAPI{}, syncClient, nil)(ctx, makeReq(map[string]interface{}{"tasks": tasks}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, resultText(result))
+	}
+
+	if len(batches) != 2 || len(batches[0]) != todoist.MaxSyncCommands {
+		t.Fatalf("sent %d requests, want 2 with %d commands in the first", len(batches), todoist.MaxSyncCommands)
+	}
+	if got, want := batches[1][0].Args["parent_id"], "real-"+batches[0][0].TempID; got != want {
+		t.Errorf("parent_id = %v, want %s", got, want)
+	}
+	var resp struct {
+		Created int `json:"created"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Created != n || fmt.Sprint(progress) != fmt.Sprintf("[%d %d]", todoist.MaxSyncCommands, n) {
+		t.Errorf("created = %d, progress = %v", resp.Created, progress)
+	}
+}
+
 func TestMoveTasksHandler(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -862,7 +1577,7 @@ func TestMoveTasksHandler(t *testing.T) {
 		errSubstr string
 	}{
 		{
-			name: "REST path with few tasks",
+			name: "few tasks still use Sync item_move",
 			args: map[string]interface{}{
 				"task_ids":      []interface{}{"1", "2"},
 				"to_project_id": "proj1",
@@ -870,8 +1585,15 @@ func TestMoveTasksHandler(t *testing.T) {
 			mockGet: func(_ context.Context, path string) ([]byte, error) {
 				return json.Marshal(map[string]interface{}{"id": "proj1", "name": "Destination"})
 			},
-			mockPost: func(_ context.Context, _ string, _ interface{}) ([]byte, error) {
-				return json.Marshal(map[string]interface{}{"id": "1"})
+			mockBatch: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+				status := make(map[string]interface{})
+				for _, cmd := range commands {
+					if cmd.Type != "item_move" {
+						return nil, fmt.Errorf("unexpected command %s", cmd.Type)
+					}
+					status[cmd.UUID] = "ok"
+				}
+				return &todoist.SyncResponse{SyncStatus: status}, nil
 			},
 		},
 		{
@@ -898,7 +1620,7 @@ func TestMoveTasksHandler(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			client := &MockAPI{GetFn: tt.mockGet, PostFn: tt.mockPost}
 			syncClient := &MockSyncAPI{BatchCommandsFn: tt.mockBatch}
-			handler := MoveTasksHandler(client, syncClient)
+			handler := MoveTasksHandler(client, syncClient, NewConfirmationStore(time.Minute))
 			result, err := handler(context.Background(), makeReq(tt.args))
 			if err != nil {
 				t.Fatalf("unexpected Go error: %v", err)
@@ -919,3 +1641,262 @@ func TestMoveTasksHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestMoveTasksHandler_PreserveSections(t *testing.T) {
+	var createdSection interface{}
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			switch {
+			case strings.HasPrefix(path, "/tasks?"):
+				return json.Marshal([]map[string]interface{}{
+					{"id": "1", "section_id": "s-doing"},
+					{"id": "2", "section_id": "s-review"},
+					{"id": "3", "section_id": nil},
+				})
+			case path == "/sections":
+				return json.Marshal([]map[string]interface{}{
+					{"id": "s-doing", "name": "Doing", "project_id": "src"},
+					{"id": "s-review", "name": "Review", "project_id": "src"},
+					{"id": "d-doing", "name": "doing", "project_id": "dst"},
+				})
+			default:
+				return json.Marshal(map[string]interface{}{"id": "dst", "name": "Destination"})
+			}
+		},
+		PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+			if path != "/sections" {
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			}
+			createdSection = body
+			return json.Marshal(map[string]interface{}{"id": "d-review", "name": "Review"})
+		},
+	}
+	moves := make(map[string]map[string]interface{})
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			moves[cmd.Args["id"].(string)] = cmd.Args
+			status[cmd.UUID] = "ok"
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+
+	handler := MoveTasksHandler(client, syncClient, NewConfirmationStore(time.Minute))
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{
+		"task_ids":          []interface{}{"1", "2", "3"},
+		"to_project_id":     "dst",
+		"preserve_sections": true,
+	}))
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+
+	if moves["1"]["section_id"] != "d-doing" || moves["2"]["section_id"] != "d-review" || moves["3"]["project_id"] != "dst" {
+		t.Errorf("moves = %v", moves)
+	}
+	if _, ok := moves["1"]["project_id"]; ok {
+		t.Errorf("move with section_id also sets project_id: %v", moves["1"])
+	}
+	if body, _ := createdSection.(map[string]interface{}); body["name"] != "Review" || body["project_id"] != "dst" {
+		t.Errorf("created section = %v", createdSection)
+	}
+	var resp struct {
+		Matched []string `json:"sections_matched"`
+		Created []string `json:"sections_created"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(resp.Matched, []string{"Doing"}) || !slices.Equal(resp.Created, []string{"Review"}) {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestSearchTasksHandler_HideDeferred(t *testing.T) {
+	today := time.Now().UTC().Format("2006-01-02")
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	client := &MockAPI{GetFn: func(_ context.Context, _ string) ([]byte, error) {
+		return json.Marshal([]map[string]interface{}{
+			{"id": "plain"},
+			{"id": "available", "description": "ref:defer=" + today},
+			{"id": "deferred", "description": "Later\n\nref:defer=" + tomorrow},
+		})
+	}}
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, _ string) ([]byte, error) {
+		return []byte(`{"tz_info":{"timezone":"UTC"}}`), nil
+	}}
+
+	result, _ := SearchTasksHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{"hide_deferred": true}))
+	var resp struct {
+		Tasks          []map[string]interface{} `json:"tasks"`
+		HiddenDeferred int                      `json:"hidden_deferred"`
+	}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v (%s)", err, resultText(result))
+	}
+	if len(resp.Tasks) != 2 || resp.Tasks[1]["id"] != "available" || resp.HiddenDeferred != 1 {
+		t.Errorf("tasks = %v, hidden_deferred = %d", resp.Tasks, resp.HiddenDeferred)
+	}
+
+	result, _ = SearchTasksHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{}))
+	if text := resultText(result); !strings.Contains(text, `"count": 3`) || strings.Contains(text, "hidden_deferred") {
+		t.Errorf("without hide_deferred: %s", text)
+	}
+}
+
+func TestCompleteTaskHandlers_CompletedAt(t *testing.T) {
+	tasks := map[string]map[string]interface{}{
+		"1": {"id": "1", "content": "Report"},
+		"2": {"id": "2", "content": "Water plants", "due": map[string]interface{}{"date": "2026-10-16", "is_recurring": true}},
+	}
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if strings.HasPrefix(path, "/tasks?") {
+				return json.Marshal([]map[string]interface{}{tasks["1"], tasks["2"]})
+			}
+			return json.Marshal(tasks[strings.TrimPrefix(path, "/tasks/")])
+		},
+		PostFn: func(_ context.Context, path string, _ interface{}) ([]byte, error) {
+			return nil, fmt.Errorf("unexpected REST call to %s", path)
+		},
+	}
+	var sent []todoist.Command
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		sent = append(sent, commands...)
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+
+	t.Run("complete_task", func(t *testing.T) {
+		sent = nil
+		result, _ := CompleteTaskHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{"task_id": "1", "completed_at": "2026-10-15T18:30:00+02:00"}))
+		if result.IsError || len(sent) != 1 || sent[0].Type != "item_complete" || sent[0].Args["date_completed"] != "2026-10-15T16:30:00Z" {
+			t.Errorf("result = %s, sent = %+v", resultText(result), sent)
+		}
+
+		for args, want := range map[string]map[string]interface{}{
+			"recurring tasks":         {"task_id": "2", "completed_at": "2026-10-15"},
+			"cannot be in the future": {"task_id": "1", "completed_at": "2999-01-01"},
+			"RFC 3339":                {"task_id": "1", "completed_at": "yesterday"},
+		} {
+			result, _ := CompleteTaskHandler(client, syncClient)(context.Background(), makeReq(want))
+			if !result.IsError || !strings.Contains(resultText(result), args) {
+				t.Errorf("%v: result = %s, want %q", want, resultText(result), args)
+			}
+		}
+	})
+
+	t.Run("bulk_complete_tasks", func(t *testing.T) {
+		sent = nil
+		handler := BulkCompleteTasksHandler(client, syncClient, NewConfirmationStore(time.Minute))
+		result, _ := handler(context.Background(), makeReq(map[string]interface{}{"task_ids": []interface{}{"1", "2"}, "completed_at": "2026-10-15"}))
+		var resp struct {
+			Completed     int      `json:"completed"`
+			FailedTaskIDs []string `json:"failed_task_ids"`
+			CompletedAt   string   `json:"completed_at"`
+			Warnings      []string `json:"warnings"`
+		}
+		if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+			t.Fatalf("parse: %v (%s)", err, resultText(result))
+		}
+		if resp.Completed != 1 || len(resp.FailedTaskIDs) != 1 || resp.FailedTaskIDs[0] != "2" || resp.CompletedAt != "2026-10-15T00:00:00Z" || len(resp.Warnings) != 1 {
+			t.Errorf("resp = %+v", resp)
+		}
+		if len(sent) != 1 || sent[0].Type != "item_complete" || sent[0].Args["id"] != "1" {
+			t.Errorf("sent = %+v", sent)
+		}
+	})
+}
+
+func TestCompleteTaskHandler_SubtaskPolicy(t *testing.T) {
+	// p has the subtask a, which has its own subtask b; c is a sibling of p.
+	projectTasks := []map[string]interface{}{
+		{"id": "p", "project_id": "p1", "content": "Launch"},
+		{"id": "a", "project_id": "p1", "parent_id": "p"},
+		{"id": "b", "project_id": "p1", "parent_id": "a"},
+		{"id": "c", "project_id": "p1"},
+	}
+	client := &MockAPI{
+		GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if path == "/tasks/p" {
+				return json.Marshal(projectTasks[0])
+			}
+			if path == "/tasks?project_id=p1" {
+				return json.Marshal(projectTasks)
+			}
+			return nil, fmt.Errorf("unexpected path %s", path)
+		},
+		PostFn: func(_ context.Context, path string, _ interface{}) ([]byte, error) {
+			return nil, fmt.Errorf("unexpected REST call to %s", path)
+		},
+	}
+	var sent []todoist.Command
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		sent = commands
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+	handler := CompleteTaskHandler(client, syncClient)
+
+	result, _ := handler(context.Background(), makeReq(map[string]interface{}{"task_id": "p", "subtask_policy": "complete_all"}))
+	var order []string
+	for _, cmd := range sent {
+		if cmd.Type != "item_close" {
+			t.Errorf("command type = %s", cmd.Type)
+		}
+		order = append(order, cmd.Args["id"].(string))
+	}
+	if result.IsError || strings.Join(order, ",") != "b,a,p" || !strings.Contains(resultText(result), `"subtasks_completed": 2`) {
+		t.Errorf("result = %s, order = %v", resultText(result), order)
+	}
+
+	sent = nil
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"task_id": "p", "subtask_policy": "require_complete"}))
+	if !result.IsError || !strings.Contains(resultText(result), "2 subtask(s) are still open (b, a)") || sent != nil {
+		t.Errorf("require_complete: result = %s, sent = %v", resultText(result), sent)
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{"task_id": "p", "subtask_policy": "orphan"}))
+	if !result.IsError || !strings.Contains(resultText(result), "subtask_policy must be one of") {
+		t.Errorf("invalid policy: result = %s", resultText(result))
+	}
+}
+
+func TestCompleteTaskHandler_ManySubtasks(t *testing.T) {
+	projectTasks := []map[string]interface{}{{"id": "p", "project_id": "p1", "content": "Launch"}}
+	for i := 0; i < 120; i++ {
+		projectTasks = append(projectTasks, map[string]interface{}{"id": fmt.Sprint("s", i), "project_id": "p1", "parent_id": "p"})
+	}
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if path == "/tasks/p" {
+			return json.Marshal(projectTasks[0])
+		}
+		return json.Marshal(projectTasks)
+	}}
+	var batches [][]todoist.Command
+	syncClient := &MockSyncAPI{BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+		batches = append(batches, commands)
+		status := make(map[string]interface{})
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+		}
+		return &todoist.SyncResponse{SyncStatus: status}, nil
+	}}
+
+	result, _ := CompleteTaskHandler(client, syncClient)(context.Background(), makeReq(map[string]interface{}{"task_id": "p", "subtask_policy": "complete_all"}))
+	if result.IsError || !strings.Contains(resultText(result), `"subtasks_completed": 120`) {
+		t.Fatalf("result = %s", resultText(result))
+	}
+	if len(batches) != 2 || len(batches[0]) != 100 || len(batches[1]) != 21 {
+		t.Fatalf("sent %d batches, want 100 and 21 commands", len(batches))
+	}
+	if last := batches[1][20]; last.Args["id"] != "p" {
+		t.Errorf("last command completes %v, want the parent", last.Args["id"])
+	}
+}
@@ -0,0 +1,582 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// ProjectTemplate is a reusable project structure saved to the template library.
+type ProjectTemplate struct {
+	Name          string         `json:"name"`
+	Description   string         `json:"description,omitempty"`
+	SourceProject string         `json:"source_project,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Sections      []string       `json:"sections"`
+	Tasks         []TemplateTask `json:"tasks"`
+}
+
+// TemplateTask is a task in a template. Parent is the index of an earlier task
+// in the same template, and Day is a 1-based relative day ("day 1" is the day
+// the template is instantiated); zero means the task has no date.
+type TemplateTask struct {
+	Content     string   `json:"content"`
+	Description string   `json:"description,omitempty"`
+	Priority    int      `json:"priority,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Section     string   `json:"section,omitempty"`
+	Parent      *int     `json:"parent,omitempty"`
+	Day         int      `json:"day,omitempty"`
+}
+
+// TemplateStore persists project templates as JSON files in a directory.
+type TemplateStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewTemplateStore creates a template store backed by dir. The directory is
+// created on first save.
+func NewTemplateStore(dir string) *TemplateStore {
+	return &TemplateStore{dir: dir}
+}
+
+// errTemplateNotFound is returned when no template has the requested name.
+var errTemplateNotFound = errors.New("template not found")
+
+// templateFileName maps a template name to a safe file name.
+func templateFileName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+			b.WriteByte('-')
+		}
+	}
+	return strings.Trim(b.String(), "-") + ".json"
+}
+
+// Save writes a template, refusing to replace an existing one unless overwrite is set.
+func (ts *TemplateStore) Save(tpl *ProjectTemplate, overwrite bool) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if err := os.MkdirAll(ts.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create template directory: %w", err)
+	}
+	path := filepath.Join(ts.dir, templateFileName(tpl.Name))
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("template %q already exists (set overwrite to replace it)", tpl.Name)
+		}
+	}
+
+	data, err := json.MarshalIndent(tpl, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	return nil
+}
+
+// Load reads the template with the given name.
+func (ts *TemplateStore) Load(name string) (*ProjectTemplate, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(ts.dir, templateFileName(name)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	var tpl ProjectTemplate
+	if err := json.Unmarshal(data, &tpl); err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return &tpl, nil
+}
+
+// List returns all stored templates sorted by name.
+func (ts *TemplateStore) List() ([]*ProjectTemplate, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	entries, err := os.ReadDir(ts.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []*ProjectTemplate{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template directory: %w", err)
+	}
+
+	templates := make([]*ProjectTemplate, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(ts.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var tpl ProjectTemplate
+		if json.Unmarshal(data, &tpl) == nil && tpl.Name != "" {
+			templates = append(templates, &tpl)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// templateTaskDue converts a template day into a Sync API due object. With a
+// zero start the day is expressed as a relative due string for Todoist to
+// resolve; otherwise the date is computed from start (day 1 is start itself).
+func templateTaskDue(day int, start time.Time) map[string]interface{} {
+	switch {
+	case day <= 0:
+		return nil
+	case !start.IsZero():
+		return map[string]interface{}{"date": start.AddDate(0, 0, day-1).Format("2006-01-02")}
+	case day == 1:
+		return map[string]interface{}{"string": "today"}
+	default:
+		return map[string]interface{}{"string": fmt.Sprintf("in %d days", day-1)}
+	}
+}
+
+// parseStartDate parses the optional start_date argument.
+func parseStartDate(args map[string]interface{}) (time.Time, error) {
+	startDate, _ := args["start_date"].(string)
+	if startDate == "" {
+		return time.Time{}, nil
+	}
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("start_date must be in YYYY-MM-DD format")
+	}
+	return start, nil
+}
+
+// buildProjectTemplate reads a project's sections and tasks and converts them
+// into a template. Due dates become days relative to the earliest due date.
+func buildProjectTemplate(ctx context.Context, client todoist.API, projectID string) (*ProjectTemplate, error) {
+	projectBody, err := client.Get(ctx, fmt.Sprintf("/projects/%s", projectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	var project models.Project
+	if err := json.Unmarshal(projectBody, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse project: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("project_id", projectID)
+
+	sectionsBody, err := client.Get(ctx, "/sections?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sections: %w", err)
+	}
+	var sections []models.Section
+	if err := json.Unmarshal(sectionsBody, &sections); err != nil {
+		return nil, fmt.Errorf("failed to parse sections: %w", err)
+	}
+
+	tasksBody, err := client.Get(ctx, "/tasks?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+	var tasks []models.Task
+	if err := json.Unmarshal(tasksBody, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to parse tasks: %w", err)
+	}
+
+	sort.SliceStable(sections, func(i, j int) bool { return sections[i].Order < sections[j].Order })
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Order < tasks[j].Order })
+
+	tpl := &ProjectTemplate{
+		SourceProject: project.Name,
+		CreatedAt:     time.Now().UTC(),
+		Sections:      make([]string, 0, len(sections)),
+		Tasks:         make([]TemplateTask, 0, len(tasks)),
+	}
+	sectionNames := make(map[string]string)
+	for _, section := range sections {
+		sectionNames[section.ID] = section.Name
+		tpl.Sections = append(tpl.Sections, section.Name)
+	}
+
+	// Relative days are measured from the earliest due date in the project.
+	var earliest time.Time
+	dueDates := make(map[string]time.Time)
+	for _, task := range tasks {
+		if d, err := time.Parse("2006-01-02", task.Due.Day()); err == nil {
+			dueDates[task.ID] = d
+			if earliest.IsZero() || d.Before(earliest) {
+				earliest = d
+			}
+		}
+	}
+
+	// Emit parents before their children so Parent always points backwards.
+	index := make(map[string]int)
+	remaining := tasks
+	for len(remaining) > 0 {
+		var deferred []models.Task
+		for _, task := range remaining {
+			id, parentID := task.ID, task.ParentID
+			parentIdx, parentKnown := index[parentID]
+			if parentID != "" && !parentKnown && taskInList(tasks, parentID) {
+				deferred = append(deferred, task)
+				continue
+			}
+
+			tt := TemplateTask{
+				Content:     task.Content,
+				Description: task.Description,
+				Labels:      task.Labels,
+				Section:     sectionNames[task.SectionID],
+			}
+			if task.Priority > 1 {
+				tt.Priority = task.Priority
+			}
+			if parentKnown {
+				p := parentIdx
+				tt.Parent = &p
+			}
+			if d, ok := dueDates[id]; ok {
+				tt.Day = int(d.Sub(earliest).Hours()/24) + 1
+			}
+
+			index[id] = len(tpl.Tasks)
+			tpl.Tasks = append(tpl.Tasks, tt)
+		}
+		if len(deferred) == len(remaining) {
+			break
+		}
+		remaining = deferred
+	}
+
+	return tpl, nil
+}
+
+// taskInList reports whether a task with the given ID is in tasks.
+func taskInList(tasks []models.Task, id string) bool {
+	return slices.ContainsFunc(tasks, func(task models.Task) bool { return task.ID == id })
+}
+
+// templateCommands builds the Sync commands that create a template's sections
+// and tasks. When projectName is set a project_add command comes first and the
+// returned reference is its temp ID; otherwise it is projectID.
+func templateCommands(tpl *ProjectTemplate, projectID, projectName string, start time.Time) ([]todoist.Command, string, error) {
+	commandCount := len(tpl.Sections) + len(tpl.Tasks)
+	if projectName != "" {
+		commandCount++
+	}
+	if commandCount > maxPlanCommands {
+		return nil, "", fmt.Errorf("template needs %d commands, more than the %d allowed in one batch", commandCount, maxPlanCommands)
+	}
+
+	commands := make([]todoist.Command, 0, commandCount)
+	projectRef := projectID
+	if projectName != "" {
+		projectRef = todoist.GenerateTempID()
+		commands = append(commands, todoist.Command{
+			Type:   "project_add",
+			UUID:   todoist.GenerateUUID(),
+			TempID: projectRef,
+			Args:   map[string]interface{}{"name": projectName},
+		})
+	}
+
+	sectionRefs := make(map[string]string, len(tpl.Sections))
+	for i, sectionName := range tpl.Sections {
+		tempID := todoist.GenerateTempID()
+		sectionRefs[sectionName] = tempID
+		commands = append(commands, todoist.Command{
+			Type:   "section_add",
+			UUID:   todoist.GenerateUUID(),
+			TempID: tempID,
+			Args: map[string]interface{}{
+				"name":          sectionName,
+				"project_id":    projectRef,
+				"section_order": i + 1,
+			},
+		})
+	}
+
+	taskRefs := make([]string, len(tpl.Tasks))
+	for i, task := range tpl.Tasks {
+		taskRefs[i] = todoist.GenerateTempID()
+		cmdArgs := map[string]interface{}{
+			"content":    task.Content,
+			"project_id": projectRef,
+		}
+		if task.Description != "" {
+			cmdArgs["description"] = task.Description
+		}
+		if task.Priority > 0 {
+			cmdArgs["priority"] = task.Priority
+		}
+		if len(task.Labels) > 0 {
+			cmdArgs["labels"] = task.Labels
+		}
+		if ref, ok := sectionRefs[task.Section]; ok && task.Section != "" {
+			cmdArgs["section_id"] = ref
+		}
+		if task.Parent != nil && *task.Parent >= 0 && *task.Parent < i {
+			cmdArgs["parent_id"] = taskRefs[*task.Parent]
+		}
+		if due := templateTaskDue(task.Day, start); due != nil {
+			cmdArgs["due"] = due
+		}
+		commands = append(commands, todoist.Command{
+			Type:   "item_add",
+			UUID:   todoist.GenerateUUID(),
+			TempID: taskRefs[i],
+			Args:   cmdArgs,
+		})
+	}
+
+	return commands, projectRef, nil
+}
+
+// runTemplateCommands executes template commands and builds the common
+// response fields. projectRef is resolved through the temp ID mapping.
+func runTemplateCommands(ctx context.Context, syncClient todoist.SyncAPI, tpl *ProjectTemplate, commands []todoist.Command, projectRef string) (map[string]interface{}, error) {
+	syncResp, err := syncClient.BatchCommands(ctx, commands)
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := 0
+	failed := make([]map[string]interface{}, 0)
+	for _, cmd := range commands {
+		if status, ok := syncResp.SyncStatus[cmd.UUID].(string); ok && status == "ok" {
+			succeeded++
+		} else {
+			failed = append(failed, map[string]interface{}{
+				"type":  cmd.Type,
+				"name":  firstNonNil(cmd.Args["content"], cmd.Args["name"]),
+				"error": fmt.Sprintf("%v", syncResp.SyncStatus[cmd.UUID]),
+			})
+		}
+	}
+
+	projectID := projectRef
+	if realID, ok := syncResp.TempIDMapping[projectRef]; ok {
+		projectID = realID
+	}
+
+	return map[string]interface{}{
+		"project_id":       projectID,
+		"sections_created": len(tpl.Sections),
+		"tasks_created":    len(tpl.Tasks),
+		"succeeded":        succeeded,
+		"failed":           failed,
+	}, nil
+}
+
+// SaveTemplateHandler creates a handler for saving a project's structure as a named template.
+func SaveTemplateHandler(client todoist.API, store *TemplateStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectID, ok := args["project_id"].(string)
+		if !ok || projectID == "" {
+			return respond.Error("project_id is required"), nil
+		}
+		if err := ValidateID(projectID, "project_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		name, _ := args["name"].(string)
+		name = strings.TrimSpace(name)
+		if name == "" || templateFileName(name) == ".json" {
+			return respond.Error("name is required and must contain letters or digits"), nil
+		}
+		description, _ := args["description"].(string)
+		overwrite, _ := args["overwrite"].(bool)
+
+		tpl, err := buildProjectTemplate(ctx, client, projectID)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		tpl.Name = name
+		tpl.Description = description
+
+		if err := store.Save(tpl, overwrite); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		response := map[string]interface{}{
+			"success":        true,
+			"name":           tpl.Name,
+			"source_project": tpl.SourceProject,
+			"section_count":  len(tpl.Sections),
+			"task_count":     len(tpl.Tasks),
+			"message":        fmt.Sprintf("Saved template %q with %d sections and %d tasks", tpl.Name, len(tpl.Sections), len(tpl.Tasks)),
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// ListTemplatesHandler creates a handler for listing saved project templates.
+func ListTemplatesHandler(store *TemplateStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		templates, err := store.List()
+		if err != nil {
+			return respond.Errorf("failed to list templates: %v", err), nil
+		}
+
+		summaries := make([]map[string]interface{}, 0, len(templates))
+		for _, tpl := range templates {
+			summaries = append(summaries, map[string]interface{}{
+				"name":           tpl.Name,
+				"description":    tpl.Description,
+				"source_project": tpl.SourceProject,
+				"created_at":     tpl.CreatedAt,
+				"section_count":  len(tpl.Sections),
+				"task_count":     len(tpl.Tasks),
+			})
+		}
+
+		response := respond.List("templates", summaries)
+
+		return respond.JSON(response), nil
+	}
+}
+
+// InstantiateTemplateHandler creates a handler for creating a template's sections and tasks in a project.
+func InstantiateTemplateHandler(syncClient todoist.SyncAPI, store *TemplateStore) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		name, ok := args["name"].(string)
+		if !ok || name == "" {
+			return respond.Error("name is required"), nil
+		}
+		projectID, _ := args["project_id"].(string)
+		projectName, _ := args["project_name"].(string)
+		if (projectID == "") == (projectName == "") {
+			return respond.Error("provide exactly one of project_id (existing project) or project_name (new project)"), nil
+		}
+		if projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+		start, err := parseStartDate(args)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		tpl, err := store.Load(name)
+		if errors.Is(err, errTemplateNotFound) {
+			return respond.Errorf("template %q not found; use list_templates to see available templates", name), nil
+		}
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		commands, projectRef, err := templateCommands(tpl, projectID, projectName, start)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		response, err := runTemplateCommands(ctx, syncClient, tpl, commands, projectRef)
+		if err != nil {
+			return respond.Errorf("failed to instantiate template: %v", err), nil
+		}
+		response["template"] = tpl.Name
+		if !start.IsZero() {
+			response["start_date"] = start.Format("2006-01-02")
+		}
+		if failed := response["failed"].([]map[string]interface{}); len(failed) > 0 {
+			response["message"] = fmt.Sprintf("Instantiated template %q with %d of %d commands failing", tpl.Name, len(failed), len(commands))
+		} else {
+			response["message"] = fmt.Sprintf("Instantiated template %q: %d sections and %d tasks", tpl.Name, len(tpl.Sections), len(tpl.Tasks))
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// DuplicateProjectHandler creates a handler for copying a project's sections and active tasks into a new project.
+func DuplicateProjectHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectID, ok := args["project_id"].(string)
+		if !ok || projectID == "" {
+			return respond.Error("project_id is required"), nil
+		}
+		if err := ValidateID(projectID, "project_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+		start, err := parseStartDate(args)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		tpl, err := buildProjectTemplate(ctx, client, projectID)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		newName, _ := args["name"].(string)
+		if newName == "" {
+			newName = tpl.SourceProject + " (copy)"
+		}
+
+		commands, projectRef, err := templateCommands(tpl, "", newName, start)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		response, err := runTemplateCommands(ctx, syncClient, tpl, commands, projectRef)
+		if err != nil {
+			return respond.Errorf("failed to duplicate project: %v", err), nil
+		}
+		response["source_project_id"] = projectID
+		response["name"] = newName
+		if !start.IsZero() {
+			response["start_date"] = start.Format("2006-01-02")
+		}
+		if failed := response["failed"].([]map[string]interface{}); len(failed) > 0 {
+			response["message"] = fmt.Sprintf("Duplicated %q as %q with %d of %d commands failing", tpl.SourceProject, newName, len(failed), len(commands))
+		} else {
+			response["message"] = fmt.Sprintf("Duplicated %q as %q: %d sections and %d tasks", tpl.SourceProject, newName, len(tpl.Sections), len(tpl.Tasks))
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// firstNonNil returns the first argument that is not nil.
+func firstNonNil(values ...interface{}) interface{} {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-todoist/todoist"
+)
+
+func TestTemplateStore(t *testing.T) {
+	store := NewTemplateStore(t.TempDir())
+
+	templates, err := store.List()
+	if err != nil || len(templates) != 0 {
+		t.Fatalf("List() on empty store = %v, %v", templates, err)
+	}
+
+	tpl := &ProjectTemplate{Name: "Client Onboarding", Sections: []string{"Kickoff"}}
+	if err := store.Save(tpl, false); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if err := store.Save(tpl, false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Save() without overwrite = %v, want already exists error", err)
+	}
+	if err := store.Save(tpl, true); err != nil {
+		t.Errorf("Save() with overwrite error: %v", err)
+	}
+
+	loaded, err := store.Load("client onboarding")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if loaded.Name != "Client Onboarding" || len(loaded.Sections) != 1 {
+		t.Errorf("Load() = %+v", loaded)
+	}
+	if _, err := store.Load("missing"); err != errTemplateNotFound {
+		t.Errorf("Load(missing) error = %v, want errTemplateNotFound", err)
+	}
+}
+
+func TestTemplateTaskDue(t *testing.T) {
+	start := time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		day   int
+		start time.Time
+		want  string
+	}{
+		{day: 0, want: ""},
+		{day: 1, want: "today"},
+		{day: 8, want: "in 7 days"},
+		{day: 0, start: start, want: ""},
+		{day: 1, start: start, want: "2026-03-30"},
+		{day: 4, start: start, want: "2026-04-02"},
+	}
+	for _, tt := range tests {
+		due := templateTaskDue(tt.day, tt.start)
+		var got string
+		if due != nil {
+			got = fmt.Sprint(firstNonNil(due["date"], due["string"]))
+		}
+		if got != tt.want {
+			t.Errorf("templateTaskDue(%d, %v) = %q, want %q", tt.day, tt.start, got, tt.want)
+		}
+	}
+}
+
+func TestSaveTemplateHandler(t *testing.T) {
+	mockGet := func(_ context.Context, path string) ([]byte, error) {
+		switch {
+		case path == "/projects/p1":
+			return json.Marshal(map[string]interface{}{"id": "p1", "name": "Launch"})
+		case strings.HasPrefix(path, "/sections?"):
+			return json.Marshal([]map[string]interface{}{
+				{"id": "s2", "name": "Release", "order": 2},
+				{"id": "s1", "name": "Prep", "order": 1},
+			})
+		case strings.HasPrefix(path, "/tasks?"):
+			return json.Marshal([]map[string]interface{}{
+				{"id": "t2", "content": "Subtask", "parent_id": "t1", "order": 1},
+				{"id": "t1", "content": "Write plan", "section_id": "s1", "order": 2, "priority": 4,
+					"due": map[string]interface{}{"date": "2026-03-01"}},
+				{"id": "t3", "content": "Ship", "section_id": "s2", "order": 3,
+					"due": map[string]interface{}{"date": "2026-03-07T09:00:00"}},
+			})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantErr   bool
+		errSubstr string
+	}{
+		{name: "valid", args: map[string]interface{}{"project_id": "p1", "name": "Launch plan"}},
+		{name: "missing project", args: map[string]interface{}{"name": "x"}, wantErr: true, errSubstr: "project_id is required"},
+		{name: "missing name", args: map[string]interface{}{"project_id": "p1", "name": "  "}, wantErr: true, errSubstr: "name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewTemplateStore(t.TempDir())
+			result, err := SaveTemplateHandler(&MockAPI{GetFn: mockGet}, store)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+
+			tpl, err := store.Load("Launch plan")
+			if err != nil {
+				t.Fatalf("Load() error: %v", err)
+			}
+			if strings.Join(tpl.Sections, ",") != "Prep,Release" {
+				t.Errorf("sections = %v", tpl.Sections)
+			}
+			if len(tpl.Tasks) != 3 {
+				t.Fatalf("got %d tasks, want 3", len(tpl.Tasks))
+			}
+			if tpl.Tasks[0].Content != "Write plan" || tpl.Tasks[0].Day != 1 || tpl.Tasks[0].Section != "Prep" {
+				t.Errorf("tasks[0] = %+v", tpl.Tasks[0])
+			}
+			if tpl.Tasks[1].Content != "Ship" || tpl.Tasks[1].Day != 7 {
+				t.Errorf("tasks[1] = %+v", tpl.Tasks[1])
+			}
+			if sub := tpl.Tasks[2]; sub.Parent == nil || *sub.Parent != 0 || sub.Day != 0 {
+				t.Errorf("subtask = %+v, want parent 0 and no day", sub)
+			}
+		})
+	}
+}
+
+func TestInstantiateTemplateHandler(t *testing.T) {
+	store := NewTemplateStore(t.TempDir())
+	parent := 0
+	if err := store.Save(&ProjectTemplate{
+		Name:     "Launch",
+		Sections: []string{"Prep"},
+		Tasks: []TemplateTask{
+			{Content: "Write plan", Section: "Prep", Day: 1},
+			{Content: "Subtask", Parent: &parent, Day: 3},
+		},
+	}, false); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		args       map[string]interface{}
+		wantErr    bool
+		errSubstr  string
+		wantTypes  []string
+		wantSubDue string
+	}{
+		{
+			name:      "new project",
+			args:      map[string]interface{}{"name": "Launch", "project_name": "Q3 Launch"},
+			wantTypes: []string{"project_add", "section_add", "item_add", "item_add"},
+		},
+		{
+			name:      "existing project",
+			args:      map[string]interface{}{"name": "Launch", "project_id": "p1"},
+			wantTypes: []string{"section_add", "item_add", "item_add"},
+		},
+		{
+			name:       "anchored to start date",
+			args:       map[string]interface{}{"name": "Launch", "project_id": "p1", "start_date": "2026-03-30"},
+			wantTypes:  []string{"section_add", "item_add", "item_add"},
+			wantSubDue: "2026-04-01",
+		},
+		{
+			name:      "invalid start date",
+			args:      map[string]interface{}{"name": "Launch", "project_id": "p1", "start_date": "next monday"},
+			wantErr:   true,
+			errSubstr: "YYYY-MM-DD",
+		},
+		{
+			name:      "no target",
+			args:      map[string]interface{}{"name": "Launch"},
+			wantErr:   true,
+			errSubstr: "exactly one of project_id",
+		},
+		{
+			name:      "unknown template",
+			args:      map[string]interface{}{"name": "Nope", "project_id": "p1"},
+			wantErr:   true,
+			errSubstr: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []todoist.Command
+			syncClient := &MockSyncAPI{
+				BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+					sent = commands
+					status := make(map[string]interface{})
+					mapping := make(map[string]string)
+					for _, cmd := range commands {
+						status[cmd.UUID] = "ok"
+						if cmd.Type == "project_add" {
+							mapping[cmd.TempID] = "p-new"
+						}
+					}
+					return &todoist.SyncResponse{SyncStatus: status, TempIDMapping: mapping}, nil
+				},
+			}
+
+			result, err := InstantiateTemplateHandler(syncClient, store)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr {
+				if !result.IsError {
+					t.Fatal("expected tool error")
+				}
+				if !strings.Contains(text, tt.errSubstr) {
+					t.Errorf("error = %q, want substring %q", text, tt.errSubstr)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if len(sent) != len(tt.wantTypes) {
+				t.Fatalf("sent %d commands, want %d", len(sent), len(tt.wantTypes))
+			}
+			for i, want := range tt.wantTypes {
+				if sent[i].Type != want {
+					t.Errorf("commands[%d] = %s, want %s", i, sent[i].Type, want)
+				}
+			}
+
+			n := len(sent)
+			section, task, sub := sent[n-3], sent[n-2], sent[n-1]
+			if task.Args["section_id"] != section.TempID {
+				t.Errorf("task section_id = %v, want section temp id", task.Args["section_id"])
+			}
+			if sub.Args["parent_id"] != task.TempID {
+				t.Errorf("subtask parent_id = %v, want task temp id", sub.Args["parent_id"])
+			}
+			wantSubDue := tt.wantSubDue
+			if wantSubDue == "" {
+				wantSubDue = "in 2 days"
+			}
+			due := sub.Args["due"].(map[string]interface{})
+			if got := firstNonNil(due["date"], due["string"]); got != wantSubDue {
+				t.Errorf("subtask due = %v, want %s", got, wantSubDue)
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if _, isNew := tt.args["project_name"]; isNew && resp["project_id"] != "p-new" {
+				t.Errorf("project_id = %v, want p-new", resp["project_id"])
+			}
+		})
+	}
+}
+
+func TestDuplicateProjectHandler(t *testing.T) {
+	mockGet := func(_ context.Context, path string) ([]byte, error) {
+		switch {
+		case path == "/projects/p1":
+			return json.Marshal(map[string]interface{}{"id": "p1", "name": "Sprint"})
+		case strings.HasPrefix(path, "/sections?"):
+			return json.Marshal([]map[string]interface{}{})
+		case strings.HasPrefix(path, "/tasks?"):
+			return json.Marshal([]map[string]interface{}{
+				{"id": "t1", "content": "Plan", "due": map[string]interface{}{"date": "2026-01-05"}},
+				{"id": "t2", "content": "Review", "due": map[string]interface{}{"date": "2026-01-12"}},
+			})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}
+
+	var sent []todoist.Command
+	syncClient := &MockSyncAPI{
+		BatchCommandsFn: func(_ context.Context, commands []todoist.Command) (*todoist.SyncResponse, error) {
+			sent = commands
+			status := make(map[string]interface{})
+			for _, cmd := range commands {
+				status[cmd.UUID] = "ok"
+			}
+			return &todoist.SyncResponse{SyncStatus: status, TempIDMapping: map[string]string{commands[0].TempID: "p2"}}, nil
+		},
+	}
+
+	handler := DuplicateProjectHandler(&MockAPI{GetFn: mockGet}, syncClient)
+	result, err := handler(context.Background(), makeReq(map[string]interface{}{"project_id": "p1", "start_date": "2026-02-02"}))
+	if err != nil {
+		t.Fatalf("unexpected Go error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", resultText(result))
+	}
+	if len(sent) != 3 || sent[0].Type != "project_add" || sent[0].Args["name"] != "Sprint (copy)" {
+		t.Fatalf("unexpected commands: %+v", sent)
+	}
+	for i, want := range []string{"2026-02-02", "2026-02-09"} {
+		if got := sent[i+1].Args["due"].(map[string]interface{})["date"]; got != want {
+			t.Errorf("task %d due = %v, want %s", i, got, want)
+		}
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp["project_id"] != "p2" {
+		t.Errorf("project_id = %v, want p2", resp["project_id"])
+	}
+
+	result, _ = handler(context.Background(), makeReq(map[string]interface{}{}))
+	if !result.IsError || !strings.Contains(resultText(result), "project_id is required") {
+		t.Errorf("missing project_id should fail, got %s", resultText(result))
+	}
+}
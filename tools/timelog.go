@@ -0,0 +1,265 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// timeLogPattern matches a time-log comment such as "⏱ 45m on 2025-06-01" or
+// "⏱ 1h 30m on 2025-06-01 — code review".
+var timeLogPattern = regexp.MustCompile(`^⏱\s*(?:(\d+)h)?\s*(?:(\d+)m)?\s+on\s+(\d{4}-\d{2}-\d{2})(?:\s+[—–-]\s+(.+))?$`)
+
+// formatMinutes renders minutes as "45m", "2h", or "1h 30m".
+func formatMinutes(minutes int) string {
+	h, m := minutes/60, minutes%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh %dm", h, m)
+	}
+}
+
+// formatTimeLog builds the comment body recorded by log_time.
+func formatTimeLog(minutes int, date, note string) string {
+	content := fmt.Sprintf("⏱ %s on %s", formatMinutes(minutes), date)
+	if note != "" {
+		content += " — " + note
+	}
+	return content
+}
+
+// parseTimeLog reads a time-log comment. The second return value is false for
+// comments that are not time logs.
+func parseTimeLog(content string) (minutes int, date, note string, ok bool) {
+	m := timeLogPattern.FindStringSubmatch(strings.TrimSpace(content))
+	if m == nil {
+		return 0, "", "", false
+	}
+	h, _ := strconv.Atoi(m[1])
+	mins, _ := strconv.Atoi(m[2])
+	minutes = h*60 + mins
+	if minutes <= 0 {
+		return 0, "", "", false
+	}
+	return minutes, m[3], m[4], true
+}
+
+// LogTimeHandler creates a handler that records time spent on a task as a structured comment.
+func LogTimeHandler(client todoist.API) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		taskID, _ := args["task_id"].(string)
+		if err := ValidateID(taskID, "task_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		minutes, _ := args["minutes"].(float64)
+		if minutes < 1 || minutes > 1440 || minutes != float64(int(minutes)) {
+			return respond.Error("minutes must be a whole number between 1 and 1440"), nil
+		}
+
+		date := time.Now().Format("2006-01-02")
+		if d, ok := args["date"].(string); ok && d != "" {
+			if _, err := time.Parse("2006-01-02", d); err != nil {
+				return respond.Error("date must be a YYYY-MM-DD date"), nil
+			}
+			date = d
+		}
+		note, _ := args["note"].(string)
+		note = strings.Join(strings.Fields(note), " ")
+
+		content := formatTimeLog(int(minutes), date, note)
+		respBody, err := client.Post(ctx, "/comments", map[string]interface{}{
+			"task_id": taskID,
+			"content": content,
+		})
+		if err != nil {
+			return respond.Errorf("failed to log time: %v", err), nil
+		}
+
+		var comment map[string]interface{}
+		if err := json.Unmarshal(respBody, &comment); err != nil {
+			return respond.Errorf("failed to parse comment: %v", err), nil
+		}
+
+		response := map[string]interface{}{
+			"comment_id": comment["id"],
+			"task_id":    taskID,
+			"minutes":    int(minutes),
+			"date":       date,
+			"content":    content,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
+
+// GetTimeLogHandler creates a handler that parses and sums time-log comments.
+func GetTimeLogHandler(client todoist.API, syncClient todoist.SyncAPI) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		var from, to string
+		if v, ok := args["from"].(string); ok && v != "" {
+			if _, err := time.Parse("2006-01-02", v); err != nil {
+				return respond.Error("from must be a YYYY-MM-DD date"), nil
+			}
+			from = v
+		}
+		if v, ok := args["to"].(string); ok && v != "" {
+			if _, err := time.Parse("2006-01-02", v); err != nil {
+				return respond.Error("to must be a YYYY-MM-DD date"), nil
+			}
+			to = v
+		}
+		taskID, _ := args["task_id"].(string)
+		projectID, _ := args["project_id"].(string)
+		if projectID != "" {
+			if err := ValidateID(projectID, "project_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+		}
+
+		// comments pairs each candidate comment with the task it belongs to.
+		type taskComment struct {
+			note map[string]interface{}
+			task models.Task
+		}
+		var comments []taskComment
+
+		if taskID != "" {
+			if err := ValidateID(taskID, "task_id"); err != nil {
+				return respond.ErrorFrom(err), nil
+			}
+			params := url.Values{}
+			params.Set("task_id", taskID)
+			respBody, err := client.Get(ctx, "/comments?"+params.Encode())
+			if err != nil {
+				return respond.Errorf("failed to get comments: %v", err), nil
+			}
+			var notes []map[string]interface{}
+			if err := json.Unmarshal(respBody, &notes); err != nil {
+				return respond.Errorf("failed to parse comments: %v", err), nil
+			}
+			task := models.Task{ID: taskID}
+			for _, note := range notes {
+				comments = append(comments, taskComment{note: note, task: task})
+			}
+		} else {
+			// One full sync returns every comment on active tasks, instead of a
+			// comments request per task.
+			resources, err := fetchSyncResources(ctx, syncClient, "items", "notes")
+			if err != nil {
+				return respond.Errorf("failed to fetch comments: %v", err), nil
+			}
+			items, err := decodeSyncTasks(resources["items"])
+			if err != nil {
+				return respond.Errorf("failed to parse tasks: %v", err), nil
+			}
+			notes, err := decodeSyncObjects(resources["notes"])
+			if err != nil {
+				return respond.Errorf("failed to parse comments: %v", err), nil
+			}
+			tasks := make(map[string]models.Task, len(items))
+			for _, item := range items {
+				tasks[item.ID] = item
+			}
+			for _, note := range notes {
+				task, ok := tasks[fmt.Sprint(note["item_id"])]
+				if !ok {
+					continue
+				}
+				if projectID != "" && task.ProjectID != projectID {
+					continue
+				}
+				comments = append(comments, taskComment{note: note, task: task})
+			}
+		}
+
+		entries := make([]map[string]interface{}, 0)
+		byTask := make(map[string]int)
+		byProject := make(map[string]int)
+		byDate := make(map[string]int)
+		taskContent := make(map[string]interface{})
+		total := 0
+		for _, c := range comments {
+			content, _ := c.note["content"].(string)
+			minutes, date, note, ok := parseTimeLog(content)
+			if !ok {
+				continue
+			}
+			if (from != "" && date < from) || (to != "" && date > to) {
+				continue
+			}
+
+			id := c.task.ID
+			entry := map[string]interface{}{
+				"comment_id": c.note["id"],
+				"task_id":    id,
+				"date":       date,
+				"minutes":    minutes,
+			}
+			if note != "" {
+				entry["note"] = note
+			}
+			if c.task.Content != "" {
+				entry["task_content"] = c.task.Content
+				taskContent[id] = c.task.Content
+			}
+			if c.task.ProjectID != "" {
+				entry["project_id"] = c.task.ProjectID
+				byProject[c.task.ProjectID] += minutes
+			}
+			entries = append(entries, entry)
+			byTask[id] += minutes
+			byDate[date] += minutes
+			total += minutes
+		}
+
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i]["date"].(string) < entries[j]["date"].(string)
+		})
+
+		taskTotals := make([]map[string]interface{}, 0, len(byTask))
+		for id, minutes := range byTask {
+			t := map[string]interface{}{"task_id": id, "minutes": minutes}
+			if content, ok := taskContent[id]; ok {
+				t["content"] = content
+			}
+			taskTotals = append(taskTotals, t)
+		}
+		sort.Slice(taskTotals, func(i, j int) bool {
+			return taskTotals[i]["minutes"].(int) > taskTotals[j]["minutes"].(int)
+		})
+
+		response := map[string]interface{}{
+			"count":         len(entries),
+			"total_minutes": total,
+			"total":         formatMinutes(total),
+			"entries":       entries,
+			"by_task":       taskTotals,
+			"by_date":       byDate,
+		}
+		if taskID == "" {
+			response["by_project"] = byProject
+		}
+
+		return respond.JSON(response), nil
+	}
+}
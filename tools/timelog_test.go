@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseTimeLog(t *testing.T) {
+	tests := []struct {
+		in          string
+		wantMinutes int
+		wantDate    string
+		wantNote    string
+		wantOK      bool
+	}{
+		{in: "⏱ 45m on 2025-06-01", wantMinutes: 45, wantDate: "2025-06-01", wantOK: true},
+		{in: "⏱ 1h 30m on 2025-06-02 — code review", wantMinutes: 90, wantDate: "2025-06-02", wantNote: "code review", wantOK: true},
+		{in: "⏱2h on 2025-06-03 - pairing", wantMinutes: 120, wantDate: "2025-06-03", wantNote: "pairing", wantOK: true},
+		{in: "⏱ 0m on 2025-06-01"},
+		{in: "spent 45m on 2025-06-01"},
+		{in: "⏱ on 2025-06-01"},
+	}
+	for _, tt := range tests {
+		minutes, date, note, ok := parseTimeLog(tt.in)
+		if ok != tt.wantOK || minutes != tt.wantMinutes || date != tt.wantDate || note != tt.wantNote {
+			t.Errorf("parseTimeLog(%q) = %d, %q, %q, %v", tt.in, minutes, date, note, ok)
+		}
+	}
+
+	for _, minutes := range []int{5, 60, 135} {
+		content := formatTimeLog(minutes, "2025-06-01", "note")
+		if got, _, _, ok := parseTimeLog(content); !ok || got != minutes {
+			t.Errorf("round trip of %d minutes via %q = %d", minutes, content, got)
+		}
+	}
+}
+
+func TestLogTimeHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		wantContent string
+		wantErr     string
+	}{
+		{
+			name:        "with note",
+			args:        map[string]interface{}{"task_id": "t1", "minutes": float64(75), "date": "2025-06-01", "note": " design  review "},
+			wantContent: "⏱ 1h 15m on 2025-06-01 — design review",
+		},
+		{
+			name:    "fractional minutes",
+			args:    map[string]interface{}{"task_id": "t1", "minutes": float64(1.5)},
+			wantErr: "whole number",
+		},
+		{
+			name:    "bad date",
+			args:    map[string]interface{}{"task_id": "t1", "minutes": float64(10), "date": "June 1"},
+			wantErr: "date must be",
+		},
+		{
+			name:    "missing task",
+			args:    map[string]interface{}{"minutes": float64(10)},
+			wantErr: "task_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent map[string]interface{}
+			client := &MockAPI{PostFn: func(_ context.Context, path string, body interface{}) ([]byte, error) {
+				if path != "/comments" {
+					return nil, fmt.Errorf("unexpected path: %s", path)
+				}
+				sent = body.(map[string]interface{})
+				return []byte(`{"id": "c1"}`), nil
+			}}
+			result, err := LogTimeHandler(client)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			if sent["content"] != tt.wantContent || sent["task_id"] != "t1" {
+				t.Errorf("sent = %v, want content %q", sent, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestGetTimeLogHandler(t *testing.T) {
+	syncClient := &MockSyncAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		if !strings.HasPrefix(path, "/sync?") {
+			return nil, fmt.Errorf("unexpected path: %s", path)
+		}
+		return json.Marshal(map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"id": "t1", "content": "Write report", "project_id": "p1"},
+				{"id": "t2", "content": "Review PR", "project_id": "p2"},
+			},
+			"notes": []map[string]interface{}{
+				{"id": "c1", "item_id": "t1", "content": "⏱ 45m on 2025-06-01"},
+				{"id": "c2", "item_id": "t1", "content": "⏱ 1h on 2025-06-03 — second pass"},
+				{"id": "c3", "item_id": "t2", "content": "⏱ 30m on 2025-06-02"},
+				{"id": "c4", "item_id": "t2", "content": "Looks good to me"},
+				{"id": "c5", "item_id": "t2", "content": "⏱ 2h on 2025-06-02", "is_deleted": true},
+			},
+		})
+	}}
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		wantCount   int
+		wantMinutes int
+	}{
+		{name: "all", args: nil, wantCount: 3, wantMinutes: 135},
+		{name: "project", args: map[string]interface{}{"project_id": "p1"}, wantCount: 2, wantMinutes: 105},
+		{name: "date range", args: map[string]interface{}{"from": "2025-06-02", "to": "2025-06-02"}, wantCount: 1, wantMinutes: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetTimeLogHandler(&MockAPI{}, syncClient)(context.Background(), makeReq(tt.args))
+			if err != nil || result.IsError {
+				t.Fatalf("unexpected error: %v %s", err, resultText(result))
+			}
+			var resp struct {
+				Count     int            `json:"count"`
+				Total     int            `json:"total_minutes"`
+				ByProject map[string]int `json:"by_project"`
+			}
+			if err := json.Unmarshal([]byte(resultText(result)), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if resp.Count != tt.wantCount || resp.Total != tt.wantMinutes {
+				t.Errorf("count = %d, total = %d, want %d, %d", resp.Count, resp.Total, tt.wantCount, tt.wantMinutes)
+			}
+		})
+	}
+
+	t.Run("single task via REST", func(t *testing.T) {
+		client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+			if path != "/comments?task_id=t1" {
+				return nil, fmt.Errorf("unexpected path: %s", path)
+			}
+			return []byte(`[{"id": "c1", "content": "⏱ 20m on 2025-06-01"}, {"id": "c2", "content": "⏱ 25m on 2025-06-04"}]`), nil
+		}}
+		result, _ := GetTimeLogHandler(client, &MockSyncAPI{})(context.Background(), makeReq(map[string]interface{}{"task_id": "t1"}))
+		if result.IsError || !strings.Contains(resultText(result), `"total": "45m"`) {
+			t.Errorf("response = %s", resultText(result))
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-todoist/todoist"
+	"github.com/rgabriel/mcp-todoist/todoist/models"
+	"github.com/rgabriel/mcp-todoist/tools/respond"
+)
+
+// CheckWIPLimitsHandler creates a handler that reports board sections holding
+// more tasks than their work-in-progress limit. defaults maps lowercase
+// section names to limits and is overridden by the limits argument.
+func CheckWIPLimitsHandler(client todoist.API, defaults map[string]int) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		projectID, _ := args["project_id"].(string)
+		if err := ValidateID(projectID, "project_id"); err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		limits := make(map[string]int, len(defaults))
+		for name, limit := range defaults {
+			limits[name] = limit
+		}
+		if raw, ok := args["limits"].(map[string]interface{}); ok {
+			for key, v := range raw {
+				limit, ok := v.(float64)
+				if !ok || limit < 0 || limit != float64(int(limit)) {
+					return respond.Errorf("limits[%q] must be a non-negative whole number", key), nil
+				}
+				limits[strings.ToLower(key)] = int(limit)
+			}
+		}
+		defaultLimit := -1
+		if v, ok := args["default_limit"].(float64); ok {
+			if v < 0 {
+				return respond.Error("default_limit must not be negative"), nil
+			}
+			defaultLimit = int(v)
+		}
+		if len(limits) == 0 && defaultLimit < 0 {
+			return respond.Error("no WIP limits given: pass limits or default_limit, or set WIP_LIMITS"), nil
+		}
+
+		params := url.Values{}
+		params.Set("project_id", projectID)
+		respBody, err := client.Get(ctx, "/sections?"+params.Encode())
+		if err != nil {
+			return respond.Errorf("failed to list sections: %v", err), nil
+		}
+		var sections []models.Section
+		if err := json.Unmarshal(respBody, &sections); err != nil {
+			return respond.Errorf("failed to parse sections: %v", err), nil
+		}
+
+		tasks, err := fetchTasks(ctx, client, params)
+		if err != nil {
+			return respond.ErrorFrom(err), nil
+		}
+
+		// Only top-level tasks are cards on a board; subtasks do not count.
+		bySection := make(map[string][]models.Task)
+		for _, task := range tasks {
+			if task.ParentID != "" {
+				continue
+			}
+			if task.SectionID != "" {
+				bySection[task.SectionID] = append(bySection[task.SectionID], task)
+			}
+		}
+
+		sort.SliceStable(sections, func(i, j int) bool { return sections[i].Order < sections[j].Order })
+
+		reports := make([]map[string]interface{}, 0, len(sections))
+		violations := 0
+		for _, section := range sections {
+			id, name := section.ID, section.Name
+
+			limit, ok := limits[strings.ToLower(id)]
+			if !ok {
+				limit, ok = limits[strings.ToLower(name)]
+			}
+			if !ok {
+				if defaultLimit < 0 {
+					continue
+				}
+				limit = defaultLimit
+			}
+
+			sectionTasks := bySection[id]
+			sort.SliceStable(sectionTasks, func(i, j int) bool { return sectionTasks[i].Order < sectionTasks[j].Order })
+
+			report := map[string]interface{}{
+				"section_id": id,
+				"name":       name,
+				"count":      len(sectionTasks),
+				"limit":      limit,
+				"over_limit": len(sectionTasks) > limit,
+			}
+			if len(sectionTasks) > limit {
+				violations++
+				excess := make([]map[string]interface{}, 0, len(sectionTasks)-limit)
+				for _, task := range sectionTasks[limit:] {
+					excess = append(excess, map[string]interface{}{
+						"id":       task.ID,
+						"content":  task.Content,
+						"priority": task.Priority,
+					})
+				}
+				report["excess"] = len(sectionTasks) - limit
+				report["excess_tasks"] = excess
+			}
+			reports = append(reports, report)
+		}
+
+		response := map[string]interface{}{
+			"project_id":       projectID,
+			"sections_checked": len(reports),
+			"sections_over":    violations,
+			"within_limits":    violations == 0,
+			"sections":         reports,
+		}
+
+		return respond.JSON(response), nil
+	}
+}
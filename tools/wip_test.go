@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCheckWIPLimitsHandler(t *testing.T) {
+	client := &MockAPI{GetFn: func(_ context.Context, path string) ([]byte, error) {
+		switch path {
+		case "/sections?project_id=p1":
+			return []byte(`[
+				{"id": "s3", "name": "Done", "order": 3},
+				{"id": "s1", "name": "Backlog", "order": 1},
+				{"id": "s2", "name": "Doing", "order": 2}
+			]`), nil
+		case "/tasks?project_id=p1":
+			return json.Marshal([]map[string]interface{}{
+				{"id": "a", "section_id": "s2", "order": float64(3), "content": "Third"},
+				{"id": "b", "section_id": "s2", "order": float64(1), "content": "First"},
+				{"id": "c", "section_id": "s2", "order": float64(2), "content": "Second"},
+				{"id": "c1", "section_id": "s2", "parent_id": "c", "content": "Subtask"},
+				{"id": "d", "section_id": "s1", "content": "Idea"},
+				{"id": "e", "content": "No section"},
+			})
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}}
+
+	tests := []struct {
+		name        string
+		defaults    map[string]int
+		args        map[string]interface{}
+		wantChecked []string
+		wantExcess  []string
+		wantErr     string
+	}{
+		{
+			name:        "config default",
+			defaults:    map[string]int{"doing": 2},
+			args:        map[string]interface{}{"project_id": "p1"},
+			wantChecked: []string{"Doing"},
+			wantExcess:  []string{"a"},
+		},
+		{
+			name:        "parameter overrides config, by section id",
+			defaults:    map[string]int{"doing": 2},
+			args:        map[string]interface{}{"project_id": "p1", "limits": map[string]interface{}{"s2": float64(1)}},
+			wantChecked: []string{"Doing"},
+			wantExcess:  []string{"c", "a"},
+		},
+		{
+			name:        "default_limit covers unnamed sections",
+			args:        map[string]interface{}{"project_id": "p1", "limits": map[string]interface{}{"Doing": float64(3)}, "default_limit": float64(0)},
+			wantChecked: []string{"Backlog", "Doing", "Done"},
+			wantExcess:  []string{"d"},
+		},
+		{
+			name:    "no limits",
+			args:    map[string]interface{}{"project_id": "p1"},
+			wantErr: "no WIP limits given",
+		},
+		{
+			name:    "bad limit",
+			args:    map[string]interface{}{"project_id": "p1", "limits": map[string]interface{}{"Doing": "three"}},
+			wantErr: "non-negative whole number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CheckWIPLimitsHandler(client, tt.defaults)(context.Background(), makeReq(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected Go error: %v", err)
+			}
+			text := resultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Fatalf("expected tool error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected tool error: %s", text)
+			}
+			var resp struct {
+				Sections []struct {
+					Name        string                   `json:"name"`
+					ExcessTasks []map[string]interface{} `json:"excess_tasks"`
+				} `json:"sections"`
+			}
+			if err := json.Unmarshal([]byte(text), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			var checked, excess []string
+			for _, s := range resp.Sections {
+				checked = append(checked, s.Name)
+				for _, task := range s.ExcessTasks {
+					excess = append(excess, task["id"].(string))
+				}
+			}
+			if strings.Join(checked, ",") != strings.Join(tt.wantChecked, ",") {
+				t.Errorf("checked = %v, want %v", checked, tt.wantChecked)
+			}
+			if strings.Join(excess, ",") != strings.Join(tt.wantExcess, ",") {
+				t.Errorf("excess = %v, want %v", excess, tt.wantExcess)
+			}
+		})
+	}
+}